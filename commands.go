@@ -0,0 +1,762 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/shnupta/herd/internal/bundle"
+	"github.com/shnupta/herd/internal/cli"
+	"github.com/shnupta/herd/internal/config"
+	"github.com/shnupta/herd/internal/daemon"
+	"github.com/shnupta/herd/internal/demo"
+	"github.com/shnupta/herd/internal/git"
+	"github.com/shnupta/herd/internal/history"
+	"github.com/shnupta/herd/internal/hook"
+	"github.com/shnupta/herd/internal/names"
+	"github.com/shnupta/herd/internal/organize"
+	"github.com/shnupta/herd/internal/report"
+	"github.com/shnupta/herd/internal/session"
+	"github.com/shnupta/herd/internal/sidebar"
+	"github.com/shnupta/herd/internal/state"
+	"github.com/shnupta/herd/internal/statusline"
+	"github.com/shnupta/herd/internal/tmux"
+	"github.com/shnupta/herd/internal/tui"
+)
+
+// commands are herd's subcommands; plain herd launches the TUI.
+var commands = []*cli.Command{
+	{
+		Name:    "help",
+		Aliases: []string{"--help", "-h"},
+		Args:    "[command]",
+		Summary: "Show this help, or a command's usage and flags",
+		MaxArgs: 1,
+		Setup: func(*flag.FlagSet) cli.RunFunc {
+			return func(c *cli.Context) error {
+				if len(c.Args) == 1 {
+					cmd, _ := c.Router.Lookup(strings.Fields(c.Args[0]))
+					if cmd == nil {
+						return cli.Usagef("unknown command %q", c.Args[0])
+					}
+					c.Router.WriteCommandHelp(c.Stdout, cmd)
+					return nil
+				}
+				fmt.Fprint(c.Stdout, usageHeader)
+				c.Router.WriteCommands(c.Stdout)
+				fmt.Fprint(c.Stdout, usageFooter)
+				return nil
+			}
+		},
+	},
+	{
+		Name:    "completion",
+		Summary: "Print a shell completion script for herd's commands and flags",
+		Args:    "<bash|zsh|fish>",
+		Choices: cli.Shells,
+		MinArgs: 1, MaxArgs: 1,
+		Setup: func(*flag.FlagSet) cli.RunFunc {
+			return func(c *cli.Context) error {
+				if err := c.Router.WriteCompletion(c.Stdout, c.Args[0]); err != nil {
+					return cli.Usagef("%v", err)
+				}
+				return nil
+			}
+		},
+	},
+	{
+		Name:    "version",
+		Summary: "Print herd's version",
+		Aliases: []string{"--version", "-v"},
+		Setup: func(*flag.FlagSet) cli.RunFunc {
+			return func(c *cli.Context) error {
+				fmt.Fprintln(c.Stdout, version)
+				return nil
+			}
+		},
+	},
+	{
+		// Called by Claude Code hooks — must be fast and produce no
+		// terminal output.
+		Name:    "hook",
+		Summary: "Handle a hook event (called by Claude Code, not directly)",
+		Args:    "<event>",
+		MinArgs: 1, MaxArgs: -1,
+		Setup: func(*flag.FlagSet) cli.RunFunc {
+			return func(c *cli.Context) error {
+				if err := hook.Run(c.Args[0]); err != nil {
+					// Hooks must not fail loudly (Claude would surface the error).
+					return cli.Exit(1)
+				}
+				return nil
+			}
+		},
+	},
+	{
+		// Writes herd hooks into ~/.claude/settings.json.
+		Name:    "install",
+		Summary: "Install Claude Code hooks into ~/.claude/settings.json",
+		Setup: func(*flag.FlagSet) cli.RunFunc {
+			return func(c *cli.Context) error {
+				self, err := os.Executable()
+				if err != nil {
+					return fmt.Errorf("finding executable path: %w", err)
+				}
+				// Resolve any symlinks to get the real path
+				self, err = filepath.EvalSymlinks(self)
+				if err != nil {
+					return fmt.Errorf("resolving executable path: %w", err)
+				}
+				if err := hook.Install(self); err != nil {
+					return fmt.Errorf("installing hooks: %w", err)
+				}
+				fmt.Fprintf(c.Stdout, "hooks installed → ~/.claude/settings.json\n")
+				fmt.Fprintf(c.Stdout, "using herd at: %s\n", self)
+				return nil
+			}
+		},
+	},
+	{
+		// Removes herd's hooks and, with --purge, its data directory.
+		Name:        "uninstall",
+		Summary:     "Remove herd's hooks from ~/.claude/settings.json, keeping any others, and with --purge delete ~/.herd",
+		Args:        "[--purge]",
+		Destructive: true,
+		Setup: func(fs *flag.FlagSet) cli.RunFunc {
+			purge := fs.Bool("purge", false, "also delete ~/.herd")
+			return func(c *cli.Context) error {
+				var actions []cli.Action
+				cmds, err := hook.InstalledCommands()
+				if err != nil {
+					return err
+				}
+				if len(cmds) > 0 {
+					actions = append(actions, cli.Action{
+						Desc: fmt.Sprintf("remove %d herd hook(s) from ~/.claude/settings.json", len(cmds)),
+						Do:   hook.Uninstall,
+					})
+				}
+				if _, err := os.Stat(herdDir()); *purge && err == nil {
+					actions = append(actions, cli.Action{
+						Desc: "delete " + herdDir(),
+						Do:   func() error { return os.RemoveAll(herdDir()) },
+					})
+				}
+				return c.Apply(actions)
+			}
+		},
+	},
+	{
+		// Bundles config, names, groups, snippets, templates and presets into one
+		// JSON document.
+		Name:    "export-config",
+		Files:   true,
+		Summary: "Write config, names, groups, snippets, templates and presets as JSON (stdout by default)",
+		Args:    "[file]",
+		MaxArgs: 1,
+		Setup: func(*flag.FlagSet) cli.RunFunc {
+			return func(c *cli.Context) error {
+				out := c.Stdout
+				if len(c.Args) == 1 && c.Args[0] != "-" {
+					f, err := os.Create(c.Args[0])
+					if err != nil {
+						return fmt.Errorf("creating export file: %w", err)
+					}
+					defer f.Close()
+					out = f
+				}
+				if err := bundle.Export(herdDir(), out); err != nil {
+					return fmt.Errorf("exporting config: %w", err)
+				}
+				return nil
+			}
+		},
+	},
+	{
+		// Replaces the config and merges names, groups, snippets, templates and
+		// presets from a bundle.
+		Name:    "import-config",
+		Files:   true,
+		Summary: "Load a bundle written by export-config",
+		Args:    "<file|->",
+		MinArgs: 1, MaxArgs: 1,
+		Setup: func(*flag.FlagSet) cli.RunFunc {
+			return func(c *cli.Context) error {
+				in := c.Stdin
+				if c.Args[0] != "-" {
+					f, err := os.Open(c.Args[0])
+					if err != nil {
+						return fmt.Errorf("opening bundle: %w", err)
+					}
+					defer f.Close()
+					in = f
+				}
+				if err := bundle.Import(herdDir(), in); err != nil {
+					return fmt.Errorf("importing config: %w", err)
+				}
+				fmt.Fprintln(c.Stdout, "config imported → ~/.herd")
+				return nil
+			}
+		},
+	},
+	{
+		// Monitors sessions without a terminal until interrupted.
+		Name:    "daemon",
+		Summary: "Monitor sessions headless, serving ~/.herd/daemon.sock and, with --http, the HTTP API on addr",
+		Args:    "[--http <addr> [--pprof]]",
+		Setup: func(fs *flag.FlagSet) cli.RunFunc {
+			httpAddr := fs.String("http", "", "also serve the HTTP API on `addr`")
+			profile := fs.Bool("pprof", false, "serve Go runtime profiles under /debug/pprof/ on the HTTP API")
+			return func(c *cli.Context) error {
+				watcher, err := state.NewWatcher()
+				if err != nil {
+					fmt.Fprintf(c.Stderr, "warning: could not watch state dir: %v\n", err)
+				}
+				var events <-chan state.SessionState
+				if watcher != nil {
+					defer watcher.Close()
+					events = watcher.Events()
+				}
+				ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+				defer stop()
+				d := daemon.New(&tmux.Client{}, state.ReadAll)
+				d.SetProfiling(*profile)
+				if *httpAddr != "" {
+					go func() {
+						if err := d.ListenHTTP(ctx, *httpAddr, config.Load); err != nil {
+							fmt.Fprintln(c.Stderr, "error serving HTTP API:", err)
+							stop()
+						}
+					}()
+				}
+				if err := d.Run(ctx, daemon.SocketPath(), events); err != nil {
+					return fmt.Errorf("running daemon: %w", err)
+				}
+				return nil
+			}
+		},
+	},
+	{
+		// Prints the session list held by a running daemon.
+		Name:    "sessions",
+		Summary: "List sessions known to a running daemon",
+		Setup: func(*flag.FlagSet) cli.RunFunc {
+			return func(c *cli.Context) error {
+				snap, err := daemon.Query(daemon.SocketPath())
+				if err != nil {
+					return err
+				}
+				tw := tabwriter.NewWriter(c.Stdout, 0, 0, 2, ' ', 0)
+				fmt.Fprintln(tw, "PANE\tSTATE\tTOOL\tPROJECT")
+				for _, s := range snap.Sessions {
+					fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", s.TmuxPane, s.State, s.CurrentTool, s.ProjectPath)
+				}
+				return tw.Flush()
+			}
+		},
+	},
+	{
+		// Lists sessions for scripts, from a running daemon when there is
+		// one and by discovering them otherwise.
+		Name:    "status",
+		Summary: "List Claude sessions with their state, without the TUI; --json for scripts",
+		Args:    "[--json]",
+		Setup: func(fs *flag.FlagSet) cli.RunFunc {
+			asJSON := fs.Bool("json", false, "print the sessions as JSON")
+			return func(c *cli.Context) error {
+				snap, err := sessionSnapshot()
+				if err != nil {
+					return err
+				}
+				if *asJSON {
+					enc := json.NewEncoder(c.Stdout)
+					enc.SetIndent("", "  ")
+					return enc.Encode(snap)
+				}
+				if len(snap.Sessions) == 0 {
+					fmt.Fprintln(c.Stdout, "No Claude sessions found.")
+					return nil
+				}
+				home, _ := os.UserHomeDir()
+				tw := tabwriter.NewWriter(c.Stdout, 0, 0, 2, ' ', 0)
+				fmt.Fprintln(tw, "PANE\tSTATE\tTOOL\tBRANCH\tPROJECT\tUPDATED")
+				for _, s := range snap.Sessions {
+					project := s.ProjectPath
+					if home != "" && strings.HasPrefix(project, home) {
+						project = "~" + project[len(home):]
+					}
+					updated := "-"
+					if !s.UpdatedAt.IsZero() {
+						updated = report.FormatDuration(time.Since(s.UpdatedAt)) + " ago"
+					}
+					fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", s.TmuxPane, s.State, dash(s.CurrentTool), dash(s.GitBranch), project, updated)
+				}
+				return tw.Flush()
+			}
+		},
+	},
+	{
+		// Prints a tmux-format summary of sessions needing attention, from
+		// a running daemon when there is one and by discovering sessions
+		// otherwise.
+		Name:    "status-line",
+		Summary: "Summarise sessions needing attention for the tmux status bar; --sessions names up to n of them",
+		Args:    "[--sessions <n>]",
+		Setup: func(fs *flag.FlagSet) cli.RunFunc {
+			perSession := fs.Int("sessions", 0, "name up to `n` sessions instead of counting them")
+			return func(c *cli.Context) error {
+				snap, err := daemon.Query(daemon.SocketPath())
+				if err != nil {
+					d := daemon.New(&tmux.Client{}, state.ReadAll)
+					if err := d.Refresh(); err != nil {
+						return cli.Exit(1)
+					}
+					snap = d.Snapshot()
+				}
+				fmt.Fprintln(c.Stdout, statusline.Format(snap.Sessions, *perSession))
+				return nil
+			}
+		},
+	},
+	{
+		// Summarises the hook history per project, or with --csv exports
+		// working time per project and branch.
+		Name:    "report",
+		Summary: "Summarise recent activity per project (default 24h; durations like 90m, 24h or 7d); --csv exports working time per project and branch; --actions lists what herd did to sessions",
+		Args:    "[--since <dur>] [--csv | --actions]",
+		Setup: func(fs *flag.FlagSet) cli.RunFunc {
+			sinceFlag := fs.String("since", "24h", "report window, a `dur`ation such as 90m, 24h or 7d")
+			asCSV := fs.Bool("csv", false, "write working time per project and branch as CSV")
+			actions := fs.Bool("actions", false, "list the keys, prompts, kills and resizes herd sent to sessions")
+			return func(c *cli.Context) error {
+				window, err := report.ParseSince(*sinceFlag)
+				if err != nil {
+					return cli.Usagef("%v", err)
+				}
+				now := time.Now()
+				since := now.Add(-window)
+				readFrom := since
+				if *asCSV {
+					// Read the whole log so a run already under way at since is
+					// billed from since rather than dropped.
+					readFrom = time.Time{}
+				}
+				events, err := history.ReadSince(history.Path(), readFrom)
+				if err != nil {
+					return fmt.Errorf("reading history: %w", err)
+				}
+				if *asCSV {
+					return report.WriteCSV(c.Stdout, report.TimeByBranch(events, since, now))
+				}
+				if *actions {
+					return report.WriteActions(c.Stdout, events, since)
+				}
+				return report.Write(c.Stdout, report.Summarize(events, now), since)
+			}
+		},
+	},
+	{
+		// Opens review mode's diff viewer on any repository, without a
+		// session to review for.
+		Name:    "diff",
+		Summary: "Browse the uncommitted changes of the repository at path (default .) in herd's diff viewer",
+		Args:    "[path]",
+		Files:   true,
+		MaxArgs: 1,
+		Setup: func(*flag.FlagSet) cli.RunFunc {
+			return func(c *cli.Context) error {
+				dir := "."
+				if len(c.Args) > 0 {
+					dir = c.Args[0]
+				}
+				viewer, err := tui.NewDiffViewer(dir)
+				if errors.Is(err, tui.ErrNoChanges) {
+					fmt.Fprintln(c.Stdout, "No changes to review")
+					return nil
+				}
+				if err != nil {
+					return err
+				}
+				_, err = tea.NewProgram(viewer, tea.WithAltScreen()).Run()
+				return err
+			}
+		},
+	},
+	{
+		// Runs the TUI on made-up sessions; the demo's own process gets a
+		// throwaway home so nothing it does touches the real one.
+		Name:    "demo",
+		Aliases: []string{"--demo"},
+		Summary: "Run the TUI on made-up sessions that cycle through Claude's states, without tmux or Claude",
+		Setup: func(*flag.FlagSet) cli.RunFunc {
+			return func(c *cli.Context) error {
+				if home := os.Getenv("HERD_DEMO_HOME"); home != "" {
+					return runDemo(home)
+				}
+				return inScratchHome(c, "HERD_DEMO_HOME", "demo")
+			}
+		},
+	},
+	{
+		Name:    "preset",
+		Aliases: []string{"--preset"},
+		Summary: "Run the TUI laid out as a preset from the config: its view, open and folded groups, filter and selected session",
+		Args:    "<name>",
+		MinArgs: 1,
+		MaxArgs: 1,
+		Setup: func(*flag.FlagSet) cli.RunFunc {
+			return func(c *cli.Context) error {
+				presets := config.Load().Presets
+				p, ok := presets[c.Args[0]]
+				if !ok {
+					if len(presets) == 0 {
+						return fmt.Errorf("no preset %q: the config defines none", c.Args[0])
+					}
+					return fmt.Errorf("no preset %q; the config defines %s", c.Args[0], strings.Join(slices.Sorted(maps.Keys(presets)), ", "))
+				}
+				return runTUI(&p)
+			}
+		},
+	},
+	{
+		// Renders in a scratch home too, so the user's names, groups and
+		// sidebar order don't leak into the picture.
+		Name:    "render",
+		Summary: "Print the screen a JSON fixture of sessions describes, as herd would draw it with your config, for snapshot tests of layouts",
+		Args:    "--fixture <file> [--width <n>] [--height <n>] [--config <file>]",
+		Setup: func(fs *flag.FlagSet) cli.RunFunc {
+			fixture := fs.String("fixture", "", "the JSON fixture describing the sessions to draw")
+			width := fs.Int("width", 120, "screen width in columns")
+			height := fs.Int("height", 40, "screen height in rows")
+			cfgFile := fs.String("config", filepath.Join(herdDir(), "config.json"), "render with the config in `file`")
+			return func(c *cli.Context) error {
+				if *fixture == "" {
+					return cli.Usagef("--fixture is required")
+				}
+				if *width < 20 || *height < 5 {
+					return cli.Usagef("the screen must be at least 20×5")
+				}
+				if home := os.Getenv("HERD_RENDER_HOME"); home != "" {
+					return runRender(c, home, *fixture, *cfgFile, *width, *height)
+				}
+				fixturePath, err := filepath.Abs(*fixture)
+				if err != nil {
+					return err
+				}
+				cfgPath, err := filepath.Abs(*cfgFile)
+				if err != nil {
+					return err
+				}
+				return inScratchHome(c, "HERD_RENDER_HOME", "render", "--fixture", fixturePath, "--config", cfgPath,
+					"--width", strconv.Itoa(*width), "--height", strconv.Itoa(*height))
+			}
+		},
+	},
+	{
+		// Removes state files left by sessions whose panes are gone.
+		Name:        "gc",
+		Summary:     "Delete hook state left by sessions whose panes are gone",
+		Args:        "[--min-age <dur>]",
+		Destructive: true,
+		Setup: func(fs *flag.FlagSet) cli.RunFunc {
+			minAge := fs.Duration("min-age", time.Hour, "keep state files updated within this `dur`ation")
+			return func(c *cli.Context) error {
+				states, err := state.ReadAll()
+				if err != nil {
+					return err
+				}
+				live, err := livePanes()
+				if err != nil {
+					return err
+				}
+				var actions []cli.Action
+				for _, st := range states {
+					if live[st.TmuxPane] || time.Since(st.UpdatedAt) < *minAge {
+						continue
+					}
+					desc := "remove state for session " + st.SessionID
+					if st.ProjectPath != "" {
+						desc += " (" + st.ProjectPath + ")"
+					}
+					actions = append(actions, cli.Action{Desc: desc, Do: func() error { return state.Remove(st.SessionID) }})
+				}
+				return c.Apply(actions)
+			}
+		},
+	},
+	{
+		// Removes a repo's linked worktrees that no session is using and
+		// whose branches are merged.
+		Name:        "worktree prune",
+		Files:       true,
+		Summary:     "Remove linked worktrees no session uses whose branches are merged",
+		Args:        "[--unmerged] [--delete-branch] [repo]",
+		MaxArgs:     1,
+		Destructive: true,
+		Setup: func(fs *flag.FlagSet) cli.RunFunc {
+			unmerged := fs.Bool("unmerged", false, "also remove worktrees whose branch isn't merged or is detached")
+			deleteBranch := fs.Bool("delete-branch", false, "delete each removed worktree's branch if it is merged")
+			return func(c *cli.Context) error {
+				repo := "."
+				if len(c.Args) == 1 {
+					repo = c.Args[0]
+				}
+				worktrees, err := git.ListWorktrees(repo)
+				if err != nil {
+					return fmt.Errorf("listing worktrees in %s: %w", repo, err)
+				}
+				var main git.Worktree
+				for _, wt := range worktrees {
+					if wt.IsMain {
+						main = wt
+					}
+				}
+				panes, err := tmux.ListPanes()
+				if err != nil && !errors.Is(err, tmux.ErrNoServer) {
+					return err
+				}
+
+				var actions []cli.Action
+				for _, wt := range worktrees {
+					if wt.IsMain || wt.IsBare {
+						continue
+					}
+					if pane := paneWithin(panes, wt.Path); pane != "" {
+						if c.Verbose {
+							fmt.Fprintf(c.Stdout, "keeping %s: pane %s is in it\n", wt.Path, pane)
+						}
+						continue
+					}
+					merged := wt.Branch != "" && main.Branch != "" && git.IsMerged(main.Path, wt.Branch, main.Branch)
+					if !merged && !*unmerged {
+						if c.Verbose {
+							fmt.Fprintf(c.Stdout, "keeping %s: not merged into %s\n", wt.Path, main.Branch)
+						}
+						continue
+					}
+					actions = append(actions, cli.Action{
+						Desc: "remove worktree " + wt.Path,
+						Do:   func() error { return git.RemoveWorktree(main.Path, wt.Path) },
+					})
+					if merged && *deleteBranch {
+						actions = append(actions, cli.Action{
+							Desc: "delete branch " + wt.Branch,
+							Do:   func() error { return git.DeleteBranch(main.Path, wt.Branch) },
+						})
+					}
+				}
+				return c.Apply(actions)
+			}
+		},
+	},
+	{
+		// Kills the panes of the sessions named by pane ID, session name or
+		// project.
+		Name:        "kill",
+		Summary:     "Kill sessions by pane ID, name or project",
+		Args:        "<target>...",
+		MinArgs:     1,
+		MaxArgs:     -1,
+		Destructive: true,
+		Setup: func(*flag.FlagSet) cli.RunFunc {
+			return func(c *cli.Context) error {
+				client := &tmux.Client{}
+				sessions, err := session.Discover(client)
+				if err != nil {
+					return err
+				}
+				name := func(s session.Session) string { return names.Get(s.Key()) }
+				var actions []cli.Action
+				seen := map[string]bool{}
+				for _, target := range c.Args {
+					matches := session.Match(sessions, target, name)
+					switch {
+					case len(matches) == 0:
+						return fmt.Errorf("no session matches %q", target)
+					case len(matches) > 1:
+						var which []string
+						for _, s := range matches {
+							which = append(which, s.TmuxPane+" ("+s.DisplayName()+")")
+						}
+						return fmt.Errorf("%q matches %d sessions: %s; name one by pane ID", target, len(matches), strings.Join(which, ", "))
+					}
+					s := matches[0]
+					if seen[s.TmuxPane] {
+						continue
+					}
+					seen[s.TmuxPane] = true
+					actions = append(actions, cli.Action{
+						Desc: fmt.Sprintf("kill pane %s (%s)", s.TmuxPane, s.DisplayName()),
+						Do:   func() error { return client.KillPane(s.TmuxPane) },
+					})
+				}
+				return c.Apply(actions)
+			}
+		},
+	},
+	{
+		// Names, groups or pins a session from a script, in the stores the
+		// TUI reads, so setup scripts can arrange a herd before opening it.
+		Name:    "set",
+		Summary: "Set a session's name or group (empty clears it), or pin it (on or off); the session is named by pane ID, name, key or project",
+		Args:    "name|group|pin <target> [<value>]",
+		Choices: organize.Fields,
+		MinArgs: 2,
+		MaxArgs: 3,
+		Setup: func(*flag.FlagSet) cli.RunFunc {
+			return func(c *cli.Context) error {
+				field, target, value := c.Args[0], c.Args[1], ""
+				if len(c.Args) == 3 {
+					value = c.Args[2]
+				}
+				if !slices.Contains(organize.Fields, field) {
+					return cli.Usagef("unknown setting %q", field)
+				}
+				snap, err := sessionSnapshot()
+				if err != nil {
+					return err
+				}
+				matches := snap.Find(target)
+				switch {
+				case len(matches) == 0:
+					return fmt.Errorf("no session matches %q", target)
+				case len(matches) > 1:
+					var which []string
+					for _, s := range matches {
+						which = append(which, s.TmuxPane+" ("+s.ProjectPath+")")
+					}
+					return fmt.Errorf("%q matches %d sessions: %s; name one by pane ID", target, len(matches), strings.Join(which, ", "))
+				}
+				return organize.Set(matches[0].Key, field, value)
+			}
+		},
+	},
+}
+
+// inScratchHome reruns herd with args in a fresh home directory, removed
+// afterwards, so nothing the rerun reads or writes touches the real ~/.herd.
+// The rerun finds the directory in the environment variable env.
+func inScratchHome(c *cli.Context, env string, args ...string) error {
+	home, err := os.MkdirTemp("", "herd-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(home)
+	self, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command(self, args...)
+	cmd.Env = append(os.Environ(), "HOME="+home, env+"="+home)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, c.Stdout, c.Stderr
+	err = cmd.Run()
+	var exit *exec.ExitError
+	if errors.As(err, &exit) {
+		return cli.Exit(exit.ExitCode())
+	}
+	return err
+}
+
+// runRender prints the screen the fixture at path describes, drawn in home
+// with the config copied from cfgPath if there is one.
+func runRender(c *cli.Context, home, path, cfgPath string, width, height int) error {
+	f, err := tui.ReadFixture(path)
+	if err != nil {
+		return err
+	}
+	if data, err := os.ReadFile(cfgPath); err == nil {
+		dir := filepath.Join(home, ".herd")
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, "config.json"), data, 0o644); err != nil {
+			return err
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	screen, err := tui.RenderFixture(f, width, height)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(c.Stdout, screen)
+	return err
+}
+
+// runDemo runs the TUI against a demo.Demo set up in home until it quits.
+func runDemo(home string) error {
+	d, err := demo.New(home)
+	if err != nil {
+		return fmt.Errorf("setting up demo: %w", err)
+	}
+	ctx, stop := context.WithCancel(context.Background())
+	defer stop()
+	go d.Run(ctx)
+	_, err = tea.NewProgram(tui.New(d.Watcher(), d), tea.WithAltScreen(), tea.WithMouseCellMotion()).Run()
+	_ = sidebar.Flush()
+	return err
+}
+
+// sessionSnapshot returns the session list of a running daemon, or else
+// discovers sessions now; without a tmux server there are none.
+func sessionSnapshot() (daemon.Snapshot, error) {
+	if snap, err := daemon.Query(daemon.SocketPath()); err == nil {
+		return snap, nil
+	}
+	d := daemon.New(&tmux.Client{}, state.ReadAll)
+	if err := d.Refresh(); err != nil {
+		if errors.Is(err, tmux.ErrNoServer) {
+			return daemon.Snapshot{Sessions: []daemon.SessionInfo{}, UpdatedAt: time.Now()}, nil
+		}
+		return daemon.Snapshot{}, err
+	}
+	return d.Snapshot(), nil
+}
+
+// dash returns s, or "-" when it is empty, for table columns.
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// livePanes returns the IDs of every tmux pane; none when no server runs.
+func livePanes() (map[string]bool, error) {
+	panes, err := tmux.ListPanes()
+	if err != nil && !errors.Is(err, tmux.ErrNoServer) {
+		return nil, err
+	}
+	live := make(map[string]bool, len(panes))
+	for _, p := range panes {
+		live[p.ID] = true
+	}
+	return live, nil
+}
+
+// paneWithin returns the ID of a pane whose working directory is dir or
+// below it, or "".
+func paneWithin(panes []tmux.Pane, dir string) string {
+	for _, p := range panes {
+		rel, err := filepath.Rel(dir, p.CurrentPath)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, "../") {
+			return p.ID
+		}
+	}
+	return ""
+}
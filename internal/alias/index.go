@@ -0,0 +1,169 @@
+// Package alias keeps per-session metadata (names, groups, pins) attached to
+// the logically-same Claude session when its key changes — e.g. when a pane
+// key is replaced by a session key once hooks report the session ID, or when
+// Claude is restarted in the same project in a fresh pane.
+package alias
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/shnupta/herd/internal/session"
+)
+
+// staleAfter is how long an entry for a session that is no longer live is kept
+// as a migration candidate before it is pruned from the index.
+const staleAfter = 30 * 24 * time.Hour
+
+// touchInterval limits how often LastSeen refreshes on live sessions cause
+// the index to be rewritten.
+const touchInterval = time.Hour
+
+// Entry records the identifying attributes last seen for a session key.
+type Entry struct {
+	SessionID   string    `json:"session_id,omitempty"`
+	TmuxPane    string    `json:"tmux_pane,omitempty"`
+	ProjectPath string    `json:"project_path,omitempty"`
+	LastSeen    time.Time `json:"last_seen"`
+}
+
+// Index is a persisted map of session key → Entry used to work out which
+// vanished key a newly-seen session replaces.
+type Index struct {
+	path    string
+	mu      sync.Mutex
+	entries map[string]Entry
+	now     func() time.Time
+}
+
+// NewIndex creates an Index backed by the given file path and loads it.
+// A missing or unreadable file yields an empty index.
+func NewIndex(path string) *Index {
+	ix := &Index{path: path, entries: make(map[string]Entry), now: time.Now}
+	if data, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(data, &ix.entries)
+		if ix.entries == nil {
+			ix.entries = make(map[string]Entry)
+		}
+	}
+	return ix
+}
+
+// Reconcile records the live sessions in the index and returns a map of
+// old key → new key for every live session that replaces a key which is no
+// longer live. Callers migrate their stores along these pairs.
+//
+// Keys built from a Claude session ID are already stable across panes, so a
+// new key is matched to a vanished one by, in priority order:
+//  1. the same tmux pane and project path (pane key → session key once hooks
+//     fire, or respawn-pane in place),
+//  2. the same project path, only when exactly one vanished key and exactly
+//     one new key share that path — ambiguous restarts are left alone.
+func (ix *Index) Reconcile(live []session.Session) map[string]string {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	now := ix.now()
+	liveKeys := make(map[string]bool, len(live))
+	for _, s := range live {
+		liveKeys[s.Key()] = true
+	}
+
+	var fresh []session.Session
+	for _, s := range live {
+		if _, known := ix.entries[s.Key()]; !known {
+			fresh = append(fresh, s)
+		}
+	}
+
+	dead := make(map[string]Entry)
+	for k, e := range ix.entries {
+		if !liveKeys[k] {
+			dead[k] = e
+		}
+	}
+
+	migrations := make(map[string]string)
+	claim := func(oldKey string, s session.Session) {
+		migrations[oldKey] = s.Key()
+		delete(dead, oldKey)
+	}
+	matched := make(map[string]bool)
+
+	// 1. Same pane and project path.
+	for _, s := range fresh {
+		if s.TmuxPane == "" {
+			continue
+		}
+		for k, e := range dead {
+			if e.TmuxPane == s.TmuxPane && e.ProjectPath == s.ProjectPath {
+				claim(k, s)
+				matched[s.Key()] = true
+				break
+			}
+		}
+	}
+
+	// 2. Unambiguous project path.
+	deadByPath := make(map[string][]string)
+	for k, e := range dead {
+		if e.ProjectPath != "" {
+			deadByPath[e.ProjectPath] = append(deadByPath[e.ProjectPath], k)
+		}
+	}
+	freshByPath := make(map[string][]session.Session)
+	for _, s := range fresh {
+		if !matched[s.Key()] && s.ProjectPath != "" {
+			freshByPath[s.ProjectPath] = append(freshByPath[s.ProjectPath], s)
+		}
+	}
+	for path, candidates := range freshByPath {
+		if len(candidates) == 1 && len(deadByPath[path]) == 1 {
+			claim(deadByPath[path][0], candidates[0])
+		}
+	}
+
+	changed := len(migrations) > 0
+	for oldKey := range migrations {
+		delete(ix.entries, oldKey)
+	}
+	for _, s := range live {
+		prev, known := ix.entries[s.Key()]
+		e := Entry{SessionID: s.ID, TmuxPane: s.TmuxPane, ProjectPath: s.ProjectPath, LastSeen: prev.LastSeen}
+		if !known || e.SessionID != prev.SessionID || e.TmuxPane != prev.TmuxPane || e.ProjectPath != prev.ProjectPath || now.Sub(prev.LastSeen) >= touchInterval {
+			e.LastSeen = now
+			changed = true
+		}
+		ix.entries[s.Key()] = e
+	}
+	for k, e := range ix.entries {
+		if !liveKeys[k] && now.Sub(e.LastSeen) > staleAfter {
+			delete(ix.entries, k)
+			changed = true
+		}
+	}
+
+	if changed {
+		_ = ix.save()
+	}
+	return migrations
+}
+
+// save writes the index to disk. Caller must hold mu.
+func (ix *Index) save() error {
+	if err := os.MkdirAll(filepath.Dir(ix.path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(ix.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := ix.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, ix.path)
+}
@@ -0,0 +1,95 @@
+package alias
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shnupta/herd/internal/session"
+)
+
+func TestReconcileFirstRunHasNoMigrations(t *testing.T) {
+	ix := NewIndex(filepath.Join(t.TempDir(), "keys.json"))
+	got := ix.Reconcile([]session.Session{
+		{TmuxPane: "%1", ProjectPath: "/proj/a"},
+		{ID: "abc", TmuxPane: "%2", ProjectPath: "/proj/b"},
+	})
+	if len(got) != 0 {
+		t.Errorf("Reconcile() on empty index = %v, want no migrations", got)
+	}
+}
+
+func TestReconcilePaneKeyToSessionKey(t *testing.T) {
+	ix := NewIndex(filepath.Join(t.TempDir(), "keys.json"))
+	ix.Reconcile([]session.Session{{TmuxPane: "%5", ProjectPath: "/proj/a"}})
+
+	// Hooks fire and the session learns its ID: key changes pane:%5 → session:abc.
+	got := ix.Reconcile([]session.Session{{ID: "abc", TmuxPane: "%5", ProjectPath: "/proj/a"}})
+	if got["pane:%5"] != "session:abc" {
+		t.Errorf("Reconcile() = %v, want pane:%%5 → session:abc", got)
+	}
+}
+
+func TestReconcileRestartInSameProject(t *testing.T) {
+	ix := NewIndex(filepath.Join(t.TempDir(), "keys.json"))
+	ix.Reconcile([]session.Session{{ID: "old", TmuxPane: "%3", ProjectPath: "/proj/a"}})
+
+	// Claude restarted in a fresh pane in the same project with a new ID.
+	got := ix.Reconcile([]session.Session{{ID: "new", TmuxPane: "%9", ProjectPath: "/proj/a"}})
+	if got["session:old"] != "session:new" {
+		t.Errorf("Reconcile() = %v, want session:old → session:new", got)
+	}
+}
+
+func TestReconcileAmbiguousPathIsSkipped(t *testing.T) {
+	ix := NewIndex(filepath.Join(t.TempDir(), "keys.json"))
+	ix.Reconcile([]session.Session{
+		{ID: "a1", TmuxPane: "%1", ProjectPath: "/proj/a"},
+		{ID: "a2", TmuxPane: "%2", ProjectPath: "/proj/a"},
+	})
+
+	got := ix.Reconcile([]session.Session{{ID: "a3", TmuxPane: "%7", ProjectPath: "/proj/a"}})
+	if len(got) != 0 {
+		t.Errorf("Reconcile() = %v, want no migration when two old keys share the path", got)
+	}
+}
+
+func TestReconcileLiveKeysAreNotClaimed(t *testing.T) {
+	ix := NewIndex(filepath.Join(t.TempDir(), "keys.json"))
+	ix.Reconcile([]session.Session{{ID: "a1", TmuxPane: "%1", ProjectPath: "/proj/a"}})
+
+	// A second session in the same project while the first is still running.
+	got := ix.Reconcile([]session.Session{
+		{ID: "a1", TmuxPane: "%1", ProjectPath: "/proj/a"},
+		{ID: "a2", TmuxPane: "%2", ProjectPath: "/proj/a"},
+	})
+	if len(got) != 0 {
+		t.Errorf("Reconcile() = %v, want live keys left alone", got)
+	}
+}
+
+func TestReconcilePersistsAcrossRestarts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	NewIndex(path).Reconcile([]session.Session{{ID: "old", TmuxPane: "%3", ProjectPath: "/proj/a"}})
+
+	// herd restarts; the index is reloaded from disk.
+	got := NewIndex(path).Reconcile([]session.Session{{ID: "new", TmuxPane: "%4", ProjectPath: "/proj/a"}})
+	if got["session:old"] != "session:new" {
+		t.Errorf("Reconcile() after reload = %v, want session:old → session:new", got)
+	}
+}
+
+func TestReconcilePrunesStaleEntries(t *testing.T) {
+	ix := NewIndex(filepath.Join(t.TempDir(), "keys.json"))
+	start := time.Now()
+	ix.now = func() time.Time { return start }
+	ix.Reconcile([]session.Session{{ID: "old", TmuxPane: "%3", ProjectPath: "/proj/a"}})
+
+	ix.now = func() time.Time { return start.Add(staleAfter + time.Hour) }
+	ix.Reconcile(nil)
+
+	got := ix.Reconcile([]session.Session{{ID: "new", TmuxPane: "%4", ProjectPath: "/proj/a"}})
+	if len(got) != 0 {
+		t.Errorf("Reconcile() = %v, want stale entry pruned", got)
+	}
+}
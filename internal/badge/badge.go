@@ -0,0 +1,67 @@
+// Package badge evaluates user-defined regex rules against captured pane
+// output to produce named sidebar badges.
+package badge
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/shnupta/herd/internal/config"
+)
+
+// Badge is a rule result to display for a session.
+type Badge struct {
+	Name  string
+	Text  string
+	Color string
+}
+
+// Rule is a compiled config.BadgeRule.
+type Rule struct {
+	config.BadgeRule
+	re *regexp.Regexp
+}
+
+// Compile compiles rules, returning an error naming the first bad pattern.
+func Compile(rules []config.BadgeRule) ([]Rule, error) {
+	out := make([]Rule, 0, len(rules))
+	for _, r := range rules {
+		re, err := regexp.Compile(r.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("badge rule %q: %w", r.Name, err)
+		}
+		out = append(out, Rule{BadgeRule: r, re: re})
+	}
+	return out, nil
+}
+
+// Evaluate returns one badge per rule name that matches output, in the order
+// names first appear in rules. Among rules sharing a name, the one matching
+// latest in output wins; ties go to the later rule.
+func Evaluate(rules []Rule, output string) []Badge {
+	type hit struct {
+		badge Badge
+		pos   int
+	}
+	best := make(map[string]hit)
+	var order []string
+	for _, r := range rules {
+		locs := r.re.FindAllStringIndex(output, -1)
+		if len(locs) == 0 {
+			continue
+		}
+		pos := locs[len(locs)-1][0]
+		prev, seen := best[r.Name]
+		if !seen {
+			order = append(order, r.Name)
+		}
+		if !seen || pos >= prev.pos {
+			best[r.Name] = hit{Badge{Name: r.Name, Text: r.Text, Color: r.Color}, pos}
+		}
+	}
+	badges := make([]Badge, 0, len(order))
+	for _, name := range order {
+		badges = append(badges, best[name].badge)
+	}
+	return badges
+}
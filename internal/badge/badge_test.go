@@ -0,0 +1,55 @@
+package badge
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/shnupta/herd/internal/config"
+)
+
+func mustCompile(t *testing.T, rules ...config.BadgeRule) []Rule {
+	t.Helper()
+	compiled, err := Compile(rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return compiled
+}
+
+func TestEvaluateLatestMatchWins(t *testing.T) {
+	rules := mustCompile(t,
+		config.BadgeRule{Name: "tests", Pattern: `All tests passed`, Text: "✓", Color: "green"},
+		config.BadgeRule{Name: "tests", Pattern: `FAIL`, Text: "✗", Color: "red"},
+		config.BadgeRule{Name: "lint", Pattern: `(?i)error:`, Text: "E", Color: "red"},
+	)
+
+	got := Evaluate(rules, "FAIL pkg\n...\nAll tests passed\n")
+	want := []Badge{{Name: "tests", Text: "✓", Color: "green"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Evaluate() = %+v, want %+v", got, want)
+	}
+
+	got = Evaluate(rules, "All tests passed\nERROR: lint\nFAIL pkg\n")
+	want = []Badge{
+		{Name: "tests", Text: "✗", Color: "red"},
+		{Name: "lint", Text: "E", Color: "red"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Evaluate() = %+v, want %+v", got, want)
+	}
+}
+
+func TestEvaluateNoMatch(t *testing.T) {
+	rules := mustCompile(t, config.BadgeRule{Name: "x", Pattern: "nope", Text: "!"})
+	if got := Evaluate(rules, "nothing here"); len(got) != 0 {
+		t.Errorf("Evaluate() = %+v, want none", got)
+	}
+}
+
+func TestCompileBadPattern(t *testing.T) {
+	_, err := Compile([]config.BadgeRule{{Name: "broken", Pattern: "("}})
+	if err == nil || !strings.Contains(err.Error(), `"broken"`) {
+		t.Errorf("Compile() error = %v, want one naming the rule", err)
+	}
+}
@@ -0,0 +1,219 @@
+// Package bundle exports and imports herd's durable configuration and
+// per-session metadata as a single JSON document, for syncing across machines
+// or sharing with teammates. Transient state (hook state files, sidebar pins
+// and ordering, the key alias index) is deliberately left out.
+package bundle
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/shnupta/herd/internal/config"
+	"github.com/shnupta/herd/internal/snippets"
+	"github.com/shnupta/herd/internal/store"
+)
+
+// Version is the bundle format version written by Export.
+const Version = 1
+
+// Bundle is the on-the-wire export format.
+type Bundle struct {
+	Version int               `json:"version"`
+	Config  config.Config     `json:"config"`
+	Names   map[string]string `json:"names,omitempty"`
+	Groups  map[string]string `json:"groups,omitempty"`
+
+	Snippets map[string][]snippets.Snippet `json:"snippets,omitempty"`
+
+	// Templates and Presets are lifted out of Config so that importing
+	// merges them by name instead of replacing the local ones.
+	Templates []config.Template        `json:"templates,omitempty"`
+	Presets   map[string]config.Preset `json:"presets,omitempty"`
+}
+
+// Export reads the config, names, groups, snippets, session templates and
+// presets from herdDir (normally ~/.herd) and writes them to w as indented
+// JSON. Credentials in the config are left out (see stripSecrets), as a
+// bundle is made to be shared.
+func Export(herdDir string, w io.Writer) error {
+	saved, err := snippets.NewStore(filepath.Join(herdDir, "snippets.json")).All()
+	if err != nil {
+		return fmt.Errorf("reading snippets: %w", err)
+	}
+	cfg, err := loadRawConfig(filepath.Join(herdDir, "config.json"))
+	if err != nil {
+		return fmt.Errorf("reading config: %w", err)
+	}
+	stripSecrets(&cfg)
+	b := Bundle{
+		Version: Version,
+		Config:  cfg,
+		Names:   loadStore(filepath.Join(herdDir, "names.json")).All(),
+		Groups:  loadStore(filepath.Join(herdDir, "groups.json")).All(),
+
+		Snippets: saved,
+
+		Templates: cfg.Templates,
+		Presets:   cfg.Presets,
+	}
+	b.Config.Templates, b.Config.Presets = nil, nil
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(b)
+}
+
+// Import reads a bundle from r and applies it to herdDir. The config is
+// replaced wholesale, except that local credentials are kept (see
+// keepSecrets); names and groups are merged, so entries in the bundle
+// win but local entries for other sessions are kept. Snippets are added to
+// any saved locally. Templates and presets are merged by name into the local
+// ones, those in the bundle winning.
+func Import(herdDir string, r io.Reader) error {
+	var b Bundle
+	if err := json.NewDecoder(r).Decode(&b); err != nil {
+		return fmt.Errorf("parsing bundle: %w", err)
+	}
+	if b.Version < 1 || b.Version > Version {
+		return fmt.Errorf("unsupported bundle version %d (this herd understands up to %d)", b.Version, Version)
+	}
+
+	cfgPath := filepath.Join(herdDir, "config.json")
+	local, err := loadRawConfig(cfgPath)
+	if err != nil {
+		return fmt.Errorf("reading config: %w", err)
+	}
+	cfg := b.Config
+	keepSecrets(&cfg, local)
+	// Bundles from before Templates and Presets were lifted out carry them
+	// in Config.
+	cfg.Templates = mergeTemplates(local.Templates, b.Config.Templates, b.Templates)
+	cfg.Presets = mergePresets(local.Presets, b.Config.Presets, b.Presets)
+	if err := config.SaveTo(cfgPath, cfg); err != nil {
+		return fmt.Errorf("writing config: %w", err)
+	}
+	if len(b.Names) > 0 {
+		if err := loadStore(filepath.Join(herdDir, "names.json")).Merge(b.Names); err != nil {
+			return fmt.Errorf("writing names: %w", err)
+		}
+	}
+	if len(b.Groups) > 0 {
+		if err := loadStore(filepath.Join(herdDir, "groups.json")).Merge(b.Groups); err != nil {
+			return fmt.Errorf("writing groups: %w", err)
+		}
+	}
+	if len(b.Snippets) > 0 {
+		if err := snippets.NewStore(filepath.Join(herdDir, "snippets.json")).Merge(b.Snippets); err != nil {
+			return fmt.Errorf("writing snippets: %w", err)
+		}
+	}
+	return nil
+}
+
+// stripSecrets clears the credentials in cfg: the API and hub tokens, task
+// source tokens, and the headers sent to webhooks and the snapshot paste
+// service, which carry their auth.
+func stripSecrets(cfg *config.Config) {
+	cfg.APIToken, cfg.HubToken = "", ""
+	cfg.TaskSources = slices.Clone(cfg.TaskSources)
+	for i := range cfg.TaskSources {
+		cfg.TaskSources[i].Token = ""
+	}
+	cfg.Webhooks = slices.Clone(cfg.Webhooks)
+	for i := range cfg.Webhooks {
+		cfg.Webhooks[i].Headers = nil
+	}
+	cfg.SnapshotUpload.Headers = nil
+}
+
+// keepSecrets puts local's credentials, those stripSecrets clears, into an
+// imported cfg wherever local has them. Task sources, webhooks and the
+// snapshot service are matched by URL.
+func keepSecrets(cfg *config.Config, local config.Config) {
+	if local.APIToken != "" {
+		cfg.APIToken = local.APIToken
+	}
+	if local.HubToken != "" {
+		cfg.HubToken = local.HubToken
+	}
+	for i, ts := range cfg.TaskSources {
+		for _, l := range local.TaskSources {
+			if l.Type == ts.Type && l.URL == ts.URL && l.Token != "" {
+				cfg.TaskSources[i].Token = l.Token
+			}
+		}
+	}
+	for i, wh := range cfg.Webhooks {
+		for _, l := range local.Webhooks {
+			if l.URL == wh.URL && len(l.Headers) > 0 {
+				cfg.Webhooks[i].Headers = l.Headers
+			}
+		}
+	}
+	if local.SnapshotUpload.URL == cfg.SnapshotUpload.URL && len(local.SnapshotUpload.Headers) > 0 {
+		cfg.SnapshotUpload.Headers = local.SnapshotUpload.Headers
+	}
+}
+
+// mergeTemplates returns base with each of the overlays' templates added,
+// replacing any of base's with the same name in place.
+func mergeTemplates(base []config.Template, overlays ...[]config.Template) []config.Template {
+	merged := append([]config.Template(nil), base...)
+	for _, overlay := range overlays {
+	next:
+		for _, t := range overlay {
+			for i := range merged {
+				if merged[i].Name == t.Name {
+					merged[i] = t
+					continue next
+				}
+			}
+			merged = append(merged, t)
+		}
+	}
+	return merged
+}
+
+// mergePresets returns base with each of the overlays' presets added, later
+// ones winning.
+func mergePresets(base map[string]config.Preset, overlays ...map[string]config.Preset) map[string]config.Preset {
+	var merged map[string]config.Preset
+	for _, m := range append([]map[string]config.Preset{base}, overlays...) {
+		for name, p := range m {
+			if merged == nil {
+				merged = make(map[string]config.Preset)
+			}
+			merged[name] = p
+		}
+	}
+	return merged
+}
+
+func loadStore(path string) *store.Store {
+	s := store.NewStore(path)
+	_ = s.Load()
+	return s
+}
+
+// loadRawConfig reads the config file without filling in defaults, so that
+// machine-specific defaults such as the home directory aren't exported. A
+// missing file is an empty config.
+func loadRawConfig(path string) (config.Config, error) {
+	var cfg config.Config
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
@@ -0,0 +1,194 @@
+package bundle
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/shnupta/herd/internal/config"
+	"github.com/shnupta/herd/internal/snippets"
+	"github.com/shnupta/herd/internal/store"
+)
+
+func TestExportImportRoundTrip(t *testing.T) {
+	src := t.TempDir()
+	if err := config.SaveTo(filepath.Join(src, "config.json"), config.Config{
+		ProjectDirs:                []string{"~/code"},
+		DangerouslySkipPermissions: true,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	_ = store.NewStore(filepath.Join(src, "names.json")).Set("session:a", "api")
+	_ = store.NewStore(filepath.Join(src, "groups.json")).Set("session:a", "backend")
+	_ = snippets.NewStore(filepath.Join(src, "snippets.json")).Add("session:a", snippets.Snippet{Name: "trace", Text: "panic: boom"})
+
+	var buf bytes.Buffer
+	if err := Export(src, &buf); err != nil {
+		t.Fatalf("Export() error: %v", err)
+	}
+
+	dst := t.TempDir()
+	_ = store.NewStore(filepath.Join(dst, "names.json")).Set("session:b", "local")
+	if err := Import(dst, &buf); err != nil {
+		t.Fatalf("Import() error: %v", err)
+	}
+
+	cfg := config.LoadFrom(filepath.Join(dst, "config.json"))
+	if len(cfg.ProjectDirs) != 1 || cfg.ProjectDirs[0] != "~/code" || !cfg.DangerouslySkipPermissions {
+		t.Errorf("imported config = %+v", cfg)
+	}
+	names := loadStore(filepath.Join(dst, "names.json"))
+	if names.Get("session:a") != "api" {
+		t.Errorf("imported name = %q, want \"api\"", names.Get("session:a"))
+	}
+	if names.Get("session:b") != "local" {
+		t.Errorf("local name lost on import: got %q", names.Get("session:b"))
+	}
+	if got := loadStore(filepath.Join(dst, "groups.json")).Get("session:a"); got != "backend" {
+		t.Errorf("imported group = %q, want \"backend\"", got)
+	}
+	if got := snippets.NewStore(filepath.Join(dst, "snippets.json")).Get("session:a"); len(got) != 1 || got[0].Text != "panic: boom" {
+		t.Errorf("imported snippets = %+v", got)
+	}
+}
+
+func TestImportMergesTemplatesAndPresets(t *testing.T) {
+	src := t.TempDir()
+	if err := config.SaveTo(filepath.Join(src, "config.json"), config.Config{
+		Templates: []config.Template{{Name: "fix", Prompt: "fix the tests"}},
+		Presets:   map[string]config.Preset{"morning": {View: "queue"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := Export(src, &buf); err != nil {
+		t.Fatalf("Export() error: %v", err)
+	}
+	var b Bundle
+	if err := json.Unmarshal(buf.Bytes(), &b); err != nil {
+		t.Fatal(err)
+	}
+	if len(b.Templates) != 1 || len(b.Presets) != 1 || b.Config.Templates != nil || b.Config.Presets != nil {
+		t.Errorf("bundle = %+v, want templates and presets outside the config", b)
+	}
+
+	dst := t.TempDir()
+	if err := config.SaveTo(filepath.Join(dst, "config.json"), config.Config{
+		Templates: []config.Template{{Name: "fix", Prompt: "old"}, {Name: "local"}},
+		Presets:   map[string]config.Preset{"morning": {View: "stats"}, "late": {View: "activity"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Import(dst, &buf); err != nil {
+		t.Fatalf("Import() error: %v", err)
+	}
+	cfg, err := loadRawConfig(filepath.Join(dst, "config.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantTemplates := []config.Template{{Name: "fix", Prompt: "fix the tests"}, {Name: "local"}}
+	if !reflect.DeepEqual(cfg.Templates, wantTemplates) {
+		t.Errorf("templates = %+v, want %+v", cfg.Templates, wantTemplates)
+	}
+	wantPresets := map[string]config.Preset{"morning": {View: "queue"}, "late": {View: "activity"}}
+	if !reflect.DeepEqual(cfg.Presets, wantPresets) {
+		t.Errorf("presets = %+v, want %+v", cfg.Presets, wantPresets)
+	}
+}
+
+func TestExportStripsAndImportKeepsSecrets(t *testing.T) {
+	src := t.TempDir()
+	if err := config.SaveTo(filepath.Join(src, "config.json"), config.Config{
+		APIToken:       "api-secret",
+		HubToken:       "hub-secret",
+		TaskSources:    []config.TaskSource{{Type: "jira", URL: "https://acme.atlassian.net", Token: "jira-secret", Project: "~/code"}},
+		Webhooks:       []config.Webhook{{URL: "https://hooks.example/a", Headers: map[string]string{"Authorization": "hook-secret"}}},
+		SnapshotUpload: config.SnapshotUpload{URL: "https://paste.example", Headers: map[string]string{"X-Key": "paste-secret"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := Export(src, &buf); err != nil {
+		t.Fatalf("Export() error: %v", err)
+	}
+	if strings.Contains(buf.String(), "secret") {
+		t.Fatalf("export leaked a credential:\n%s", buf.String())
+	}
+	if !strings.Contains(buf.String(), "https://hooks.example/a") {
+		t.Errorf("export dropped the webhook itself:\n%s", buf.String())
+	}
+	if cfg, _ := loadRawConfig(filepath.Join(src, "config.json")); cfg.TaskSources[0].Token != "jira-secret" || cfg.Webhooks[0].Headers == nil {
+		t.Errorf("export changed the local config: %+v", cfg)
+	}
+
+	dst := t.TempDir()
+	if err := config.SaveTo(filepath.Join(dst, "config.json"), config.Config{
+		APIToken:    "local-api",
+		TaskSources: []config.TaskSource{{Type: "jira", URL: "https://acme.atlassian.net", Token: "local-jira"}},
+		Webhooks:    []config.Webhook{{URL: "https://hooks.example/a", Headers: map[string]string{"Authorization": "local-hook"}}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Import(dst, &buf); err != nil {
+		t.Fatalf("Import() error: %v", err)
+	}
+	cfg, err := loadRawConfig(filepath.Join(dst, "config.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.APIToken != "local-api" || cfg.TaskSources[0].Token != "local-jira" || cfg.Webhooks[0].Headers["Authorization"] != "local-hook" {
+		t.Errorf("import lost local credentials: %+v", cfg)
+	}
+	if cfg.TaskSources[0].Project != "~/code" {
+		t.Errorf("import dropped the task source's project: %+v", cfg.TaskSources[0])
+	}
+}
+
+func TestExportRejectsCorruptConfig(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte("{nope"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Export(dir, &bytes.Buffer{}); err == nil || !strings.Contains(err.Error(), "reading config") {
+		t.Errorf("Export() error = %v, want the corrupt config reported", err)
+	}
+}
+
+func TestExportOmitsDefaults(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Export(t.TempDir(), &buf); err != nil {
+		t.Fatal(err)
+	}
+	home, _ := os.UserHomeDir()
+	if home != "" && strings.Contains(buf.String(), home) {
+		t.Errorf("export leaked default home directory: %s", buf.String())
+	}
+}
+
+func TestImportRejectsBadInput(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"not json", "nope", "parsing bundle"},
+		{"missing version", `{"config":{}}`, "unsupported bundle version 0"},
+		{"future version", `{"version":99}`, "unsupported bundle version 99"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			err := Import(dir, strings.NewReader(tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Import() error = %v, want containing %q", err, tt.want)
+			}
+			if _, statErr := os.Stat(filepath.Join(dir, "config.json")); !os.IsNotExist(statErr) {
+				t.Error("rejected bundle should not write config")
+			}
+		})
+	}
+}
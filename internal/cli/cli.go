@@ -0,0 +1,272 @@
+// Package cli routes herd's subcommands: each command declares its flags and
+// arguments, and destructive ones share --dry-run, --yes and --verbose so
+// they behave the same way under a script.
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+)
+
+// RunFunc runs a command once its flags are parsed.
+type RunFunc func(c *Context) error
+
+// Command is one herd subcommand.
+type Command struct {
+	// Name is the word after herd, e.g. "report" or "worktree prune".
+	Name string
+	// Aliases are other names for a one-word command, e.g. "--help".
+	Aliases []string
+	// Args describes the command's flags and arguments for usage lines,
+	// e.g. "[--since <dur>] [--csv]".
+	Args string
+	// Summary describes the command in help, in a sentence or two.
+	Summary string
+	// Choices are the values the first argument takes, offered by shell
+	// completion, e.g. "bash", "zsh" and "fish".
+	Choices []string
+	// Files marks commands whose arguments are paths, so shell completion
+	// offers files.
+	Files bool
+	// MinArgs and MaxArgs bound the positional arguments; MaxArgs < 0
+	// means any number.
+	MinArgs, MaxArgs int
+	// Destructive commands get --dry-run, --yes and --verbose, and should
+	// make their changes through Context.Apply.
+	Destructive bool
+	// Setup registers the command's flags and returns its RunFunc.
+	Setup func(fs *flag.FlagSet) RunFunc
+}
+
+// Usage is the command's usage line.
+func (cmd *Command) Usage() string {
+	u := "herd " + cmd.Name
+	if cmd.Args != "" {
+		u += " " + cmd.Args
+	}
+	return u
+}
+
+// Context is what a running command sees.
+type Context struct {
+	Args   []string // positional arguments
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+
+	DryRun  bool // describe the changes without making them
+	Yes     bool // skip the confirmation prompt
+	Verbose bool // report each change as it is made
+
+	// Interactive reports whether a confirmation prompt can be answered;
+	// without it and without --yes, Apply refuses to make changes.
+	Interactive bool
+
+	// Router is the router running the command, for commands such as help
+	// that describe the others.
+	Router *Router
+}
+
+// UsageError reports that a command was invoked wrongly; the router prints
+// the command's usage line with it.
+type UsageError struct{ Msg string }
+
+func (e *UsageError) Error() string { return e.Msg }
+
+// Usagef returns a *UsageError.
+func Usagef(format string, args ...any) error {
+	return &UsageError{Msg: fmt.Sprintf(format, args...)}
+}
+
+// ExitError makes the router exit with Code without printing anything, for
+// commands that must stay quiet or have already reported their failure.
+type ExitError struct{ Code int }
+
+func (e *ExitError) Error() string { return fmt.Sprintf("exit status %d", e.Code) }
+
+// Exit returns an *ExitError.
+func Exit(code int) error { return &ExitError{Code: code} }
+
+// ErrDeclined is returned by Apply when the user answers no.
+var ErrDeclined = errors.New("cancelled")
+
+// Action is one change a destructive command makes.
+type Action struct {
+	Desc string // e.g. "remove worktree ~/wt/repo-feat"
+	Do   func() error
+}
+
+// Apply makes actions the way every destructive command does: with
+// --dry-run it only lists them; otherwise it lists them and asks before
+// running them unless --yes was given, then runs each in turn, reporting
+// them with --verbose. A failed action doesn't stop the rest; their errors
+// are joined.
+func (c *Context) Apply(actions []Action) error {
+	if len(actions) == 0 {
+		fmt.Fprintln(c.Stdout, "nothing to do")
+		return nil
+	}
+	if c.DryRun {
+		for _, a := range actions {
+			fmt.Fprintln(c.Stdout, "would", a.Desc)
+		}
+		return nil
+	}
+	if !c.Yes {
+		if !c.Interactive {
+			return errors.New("not a terminal; pass --yes to confirm or --dry-run to preview")
+		}
+		for _, a := range actions {
+			fmt.Fprintln(c.Stdout, " ", a.Desc)
+		}
+		if !c.confirm(fmt.Sprintf("Proceed with %d change(s)? [y/N] ", len(actions))) {
+			return ErrDeclined
+		}
+	}
+	var errs []error
+	for _, a := range actions {
+		if err := a.Do(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", a.Desc, err))
+			continue
+		}
+		if c.Verbose {
+			fmt.Fprintln(c.Stdout, "✓", a.Desc)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (c *Context) confirm(prompt string) bool {
+	fmt.Fprint(c.Stdout, prompt)
+	line, _ := bufio.NewReader(c.Stdin).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
+
+// Router dispatches herd's arguments to a Command.
+type Router struct {
+	Commands []*Command
+
+	Stdin       io.Reader
+	Stdout      io.Writer
+	Stderr      io.Writer
+	Interactive bool
+}
+
+// NewRouter returns a Router over commands wired to the process's standard
+// streams.
+func NewRouter(commands ...*Command) *Router {
+	return &Router{
+		Commands:    commands,
+		Stdin:       os.Stdin,
+		Stdout:      os.Stdout,
+		Stderr:      os.Stderr,
+		Interactive: term.IsTerminal(os.Stdin.Fd()),
+	}
+}
+
+// Lookup returns the command args name, preferring the longest match so
+// "worktree prune" wins over a "worktree" command, and the arguments after
+// its name. It returns nil if no command matches.
+func (r *Router) Lookup(args []string) (*Command, []string) {
+	var best *Command
+	var rest []string
+	for _, cmd := range r.Commands {
+		if len(args) > 0 && slices.Contains(cmd.Aliases, args[0]) {
+			return cmd, args[1:]
+		}
+		words := strings.Fields(cmd.Name)
+		if len(words) > len(args) || (best != nil && len(words) <= len(strings.Fields(best.Name))) {
+			continue
+		}
+		match := true
+		for i, w := range words {
+			if args[i] != w {
+				match = false
+				break
+			}
+		}
+		if match {
+			best, rest = cmd, args[len(words):]
+		}
+	}
+	return best, rest
+}
+
+// Run runs the command args names and returns the process exit code: 0 on
+// success, 1 when the command fails and 2 when it was invoked wrongly.
+// ok is false when no command matches args.
+func (r *Router) Run(args []string) (code int, ok bool) {
+	cmd, rest := r.Lookup(args)
+	if cmd == nil {
+		return 0, false
+	}
+	return r.run(cmd, rest), true
+}
+
+// flagSet returns cmd's flags, bound to c, and its RunFunc.
+func (cmd *Command) flagSet(c *Context) (*flag.FlagSet, RunFunc) {
+	fs := flag.NewFlagSet(cmd.Name, flag.ContinueOnError)
+	if cmd.Destructive {
+		fs.BoolVar(&c.DryRun, "dry-run", false, "list the changes without making them")
+		fs.BoolVar(&c.Yes, "yes", false, "make the changes without asking")
+		fs.BoolVar(&c.Verbose, "verbose", false, "report each change as it is made")
+	}
+	return fs, cmd.Setup(fs)
+}
+
+// Flags returns the command's flags, sorted by name.
+func (cmd *Command) Flags() []*flag.Flag {
+	fs, _ := cmd.flagSet(&Context{})
+	var flags []*flag.Flag
+	fs.VisitAll(func(f *flag.Flag) { flags = append(flags, f) })
+	return flags
+}
+
+func (r *Router) run(cmd *Command, args []string) int {
+	c := &Context{Stdin: r.Stdin, Stdout: r.Stdout, Stderr: r.Stderr, Interactive: r.Interactive, Router: r}
+	fs, run := cmd.flagSet(c)
+	fs.SetOutput(r.Stderr)
+	fs.Usage = func() { r.WriteCommandHelp(r.Stderr, cmd) }
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	c.Args = fs.Args()
+	if len(c.Args) < cmd.MinArgs || (cmd.MaxArgs >= 0 && len(c.Args) > cmd.MaxArgs) {
+		fmt.Fprintln(r.Stderr, "usage:", cmd.Usage())
+		return 2
+	}
+
+	err := run(c)
+	var usage *UsageError
+	var exit *ExitError
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &exit):
+		return exit.Code
+	case errors.As(err, &usage):
+		fmt.Fprintf(r.Stderr, "%s\nusage: %s\n", usage.Msg, cmd.Usage())
+		return 2
+	case errors.Is(err, ErrDeclined):
+		fmt.Fprintln(r.Stderr, err)
+		return 1
+	default:
+		fmt.Fprintln(r.Stderr, "error:", err)
+		return 1
+	}
+}
@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"strings"
+	"testing"
+)
+
+// newTestRouter returns a Router over commands with captured output and
+// stdin reading input.
+func newTestRouter(input string, interactive bool, commands ...*Command) (*Router, *bytes.Buffer, *bytes.Buffer) {
+	var stdout, stderr bytes.Buffer
+	return &Router{
+		Commands:    commands,
+		Stdin:       strings.NewReader(input),
+		Stdout:      &stdout,
+		Stderr:      &stderr,
+		Interactive: interactive,
+	}, &stdout, &stderr
+}
+
+// removeCmd is a destructive command that records which of its arguments
+// it "removed".
+func removeCmd(removed *[]string) *Command {
+	return &Command{
+		Name:        "rm",
+		Args:        "<name>...",
+		MinArgs:     1,
+		MaxArgs:     -1,
+		Destructive: true,
+		Setup: func(*flag.FlagSet) RunFunc {
+			return func(c *Context) error {
+				var actions []Action
+				for _, name := range c.Args {
+					actions = append(actions, Action{Desc: "remove " + name, Do: func() error {
+						if name == "bad" {
+							return errors.New("refused")
+						}
+						*removed = append(*removed, name)
+						return nil
+					}})
+				}
+				return c.Apply(actions)
+			}
+		},
+	}
+}
+
+func TestApplyDryRunChangesNothing(t *testing.T) {
+	var removed []string
+	r, out, _ := newTestRouter("", false, removeCmd(&removed))
+	if code, _ := r.Run([]string{"rm", "--dry-run", "a", "b"}); code != 0 {
+		t.Fatalf("exit code = %d, want 0", code)
+	}
+	if len(removed) != 0 {
+		t.Errorf("removed = %v, want nothing on a dry run", removed)
+	}
+	if want := "would remove a\nwould remove b\n"; out.String() != want {
+		t.Errorf("output = %q, want %q", out, want)
+	}
+}
+
+func TestApplyConfirms(t *testing.T) {
+	for _, tc := range []struct {
+		answer string
+		code   int
+		want   int
+	}{
+		{"y\n", 0, 2},
+		{"yes\n", 0, 2},
+		{"n\n", 1, 0},
+		{"", 1, 0},
+	} {
+		var removed []string
+		r, _, _ := newTestRouter(tc.answer, true, removeCmd(&removed))
+		code, _ := r.Run([]string{"rm", "a", "b"})
+		if code != tc.code || len(removed) != tc.want {
+			t.Errorf("answer %q: code = %d, removed = %v; want %d and %d removals", tc.answer, code, removed, tc.code, tc.want)
+		}
+	}
+}
+
+func TestApplyRefusesWithoutTerminalOrYes(t *testing.T) {
+	var removed []string
+	r, _, errOut := newTestRouter("y\n", false, removeCmd(&removed))
+	if code, _ := r.Run([]string{"rm", "a"}); code != 1 || len(removed) != 0 {
+		t.Errorf("code = %d, removed = %v; want a refusal", code, removed)
+	}
+	if !strings.Contains(errOut.String(), "--yes") {
+		t.Errorf("stderr = %q, want a hint at --yes", errOut)
+	}
+
+	r, out, _ := newTestRouter("", false, removeCmd(&removed))
+	if code, _ := r.Run([]string{"rm", "--yes", "--verbose", "a", "bad", "c"}); code != 1 {
+		t.Errorf("code = %d, want 1 for the failed removal", code)
+	}
+	if strings.Join(removed, ",") != "a,c" {
+		t.Errorf("removed = %v, want the rest done despite the failure", removed)
+	}
+	if want := "✓ remove a\n✓ remove c\n"; out.String() != want {
+		t.Errorf("verbose output = %q, want %q", out, want)
+	}
+}
+
+func TestRouterLookup(t *testing.T) {
+	worktree := &Command{Name: "worktree", MaxArgs: -1}
+	prune := &Command{Name: "worktree prune", MaxArgs: -1}
+	help := &Command{Name: "help", Aliases: []string{"--help", "-h"}}
+	r, _, _ := newTestRouter("", false, worktree, prune, help)
+
+	for _, tc := range []struct {
+		args []string
+		want *Command
+		rest int
+	}{
+		{[]string{"worktree", "prune", "."}, prune, 1},
+		{[]string{"worktree", "list"}, worktree, 1},
+		{[]string{"-h"}, help, 0},
+		{[]string{"nope"}, nil, 0},
+	} {
+		cmd, rest := r.Lookup(tc.args)
+		if cmd != tc.want || len(rest) != tc.rest {
+			t.Errorf("Lookup(%v) = %v, %v", tc.args, cmd, rest)
+		}
+	}
+}
+
+func TestRouterUsageErrors(t *testing.T) {
+	var removed []string
+	r, _, errOut := newTestRouter("", false, removeCmd(&removed))
+	if code, _ := r.Run([]string{"rm"}); code != 2 {
+		t.Errorf("code = %d, want 2 for missing arguments", code)
+	}
+	if !strings.Contains(errOut.String(), "usage: herd rm <name>...") {
+		t.Errorf("stderr = %q, want the usage line", errOut)
+	}
+	if code, _ := r.Run([]string{"rm", "--bogus", "a"}); code != 2 {
+		t.Errorf("code = %d, want 2 for an unknown flag", code)
+	}
+	if _, ok := r.Run([]string{"nope"}); ok {
+		t.Error("Run should report an unknown command")
+	}
+}
@@ -0,0 +1,213 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// Shells are the shells WriteCompletion supports.
+var Shells = []string{"bash", "zsh", "fish"}
+
+// compNode is a point in the command tree: the words typed so far and what
+// may follow them.
+type compNode struct {
+	path  string   // e.g. "worktree" or "worktree prune"; "" at the root
+	subs  []string // words that may come next: subcommands or choices
+	descs []string // summaries for subs, "" for choices
+	flags []string // e.g. "--dry-run"
+	files bool
+}
+
+// completionTree flattens the router's commands into nodes, deepest first
+// so a shell matching prefixes in order finds "worktree prune" before
+// "worktree".
+func (r *Router) completionTree() []*compNode {
+	nodes := map[string]*compNode{"": {}}
+	node := func(path string) *compNode {
+		if nodes[path] == nil {
+			nodes[path] = &compNode{path: path}
+		}
+		return nodes[path]
+	}
+	for _, cmd := range r.Commands {
+		words := strings.Fields(cmd.Name)
+		for i, w := range words {
+			parent := node(strings.Join(words[:i], " "))
+			if !slices.Contains(parent.subs, w) {
+				desc := ""
+				if i == len(words)-1 {
+					desc = cmd.Summary
+				}
+				parent.subs = append(parent.subs, w)
+				parent.descs = append(parent.descs, desc)
+			}
+		}
+		n := node(cmd.Name)
+		for _, f := range cmd.Flags() {
+			n.flags = append(n.flags, "--"+f.Name)
+		}
+		for _, c := range cmd.Choices {
+			n.subs = append(n.subs, c)
+			n.descs = append(n.descs, "")
+		}
+		n.files = cmd.Files
+	}
+
+	var out []*compNode
+	for _, n := range nodes {
+		out = append(out, n)
+	}
+	slices.SortFunc(out, func(a, b *compNode) int {
+		if d := len(strings.Fields(b.path)) - len(strings.Fields(a.path)); d != 0 {
+			return d
+		}
+		return strings.Compare(a.path, b.path)
+	})
+	return out
+}
+
+// WriteCompletion writes a completion script for shell, one of Shells,
+// completing the commands, their flags and their choices.
+func (r *Router) WriteCompletion(w io.Writer, shell string) error {
+	switch shell {
+	case "bash":
+		writeBash(w, r.completionTree())
+	case "zsh":
+		writeZsh(w, r.completionTree())
+	case "fish":
+		writeFish(w, r.completionTree())
+	default:
+		return fmt.Errorf("unsupported shell %q (want %s)", shell, strings.Join(Shells, ", "))
+	}
+	return nil
+}
+
+// Bash and zsh find the command by joining the words typed before the
+// cursor, flags aside, and matching the longest command path they start
+// with; positional arguments after it don't disturb the match.
+
+func writeBash(w io.Writer, tree []*compNode) {
+	fmt.Fprint(w, `# bash completion for herd
+# Load with: source <(herd completion bash)
+_herd() {
+	local cur=${COMP_WORDS[COMP_CWORD]} cmd="" subs="" flags="" i
+	for ((i = 1; i < COMP_CWORD; i++)); do
+		[[ ${COMP_WORDS[i]} == -* ]] || cmd+=" ${COMP_WORDS[i]}"
+	done
+	case "$cmd " in
+`)
+	for _, n := range tree {
+		fmt.Fprintf(w, "\t%s) subs=%s flags=%s ;;\n", casePattern(n.path), shQuote(strings.Join(n.subs, " ")), shQuote(strings.Join(n.flags, " ")))
+	}
+	fmt.Fprint(w, `	esac
+	if [[ $cur == -* ]]; then
+		COMPREPLY=($(compgen -W "$flags" -- "$cur"))
+	elif [[ -n $subs ]]; then
+		COMPREPLY=($(compgen -W "$subs" -- "$cur"))
+	fi
+}
+complete -o default -F _herd herd
+`)
+}
+
+func writeZsh(w io.Writer, tree []*compNode) {
+	fmt.Fprint(w, `#compdef herd
+# zsh completion for herd
+# Load with: source <(herd completion zsh), or save as _herd in $fpath.
+_herd() {
+	local cmd="" i files=0
+	local -a subs flags
+	for ((i = 2; i < CURRENT; i++)); do
+		[[ ${words[i]} == -* ]] || cmd+=" ${words[i]}"
+	done
+	case "$cmd " in
+`)
+	for _, n := range tree {
+		var subs []string
+		for i, s := range n.subs {
+			entry := s
+			if n.descs[i] != "" {
+				entry += ":" + strings.ReplaceAll(firstSentence(n.descs[i]), ":", `\:`)
+			}
+			subs = append(subs, shQuote(entry))
+		}
+		files := 0
+		if n.files {
+			files = 1
+		}
+		fmt.Fprintf(w, "\t%s) subs=(%s) flags=(%s) files=%d ;;\n", casePattern(n.path), strings.Join(subs, " "), strings.Join(n.flags, " "), files)
+	}
+	fmt.Fprint(w, `	esac
+	if [[ ${words[CURRENT]} == -* ]]; then
+		compadd -- $flags
+	elif (( $#subs )); then
+		_describe 'herd command' subs
+	elif (( files )); then
+		_files
+	fi
+}
+if [[ $zsh_eval_context[-1] == loadautofunc ]]; then
+	_herd "$@"
+else
+	compdef _herd herd
+fi
+`)
+}
+
+func writeFish(w io.Writer, tree []*compNode) {
+	fmt.Fprint(w, "# fish completion for herd\n# Load with: herd completion fish | source\ncomplete -c herd -f\n")
+	for i := len(tree) - 1; i >= 0; i-- {
+		n := tree[i]
+		cond := "__fish_use_subcommand"
+		if n.path != "" {
+			var seen []string
+			for _, word := range strings.Fields(n.path) {
+				seen = append(seen, "__fish_seen_subcommand_from "+word)
+			}
+			cond = strings.Join(seen, "; and ")
+		}
+		for j, s := range n.subs {
+			line := fmt.Sprintf("complete -c herd -n %s -a %s", fishQuote(cond), fishQuote(s))
+			if n.descs[j] != "" {
+				line += " -d " + fishQuote(firstSentence(n.descs[j]))
+			}
+			fmt.Fprintln(w, line)
+		}
+		for _, f := range n.flags {
+			fmt.Fprintf(w, "complete -c herd -n %s -l %s\n", fishQuote(cond), strings.TrimPrefix(f, "--"))
+		}
+		if n.files {
+			fmt.Fprintf(w, "complete -c herd -n %s -F\n", fishQuote(cond))
+		}
+	}
+}
+
+// casePattern matches the words typed so far, with a trailing space, when
+// they start with path; the root matches only when nothing was typed.
+func casePattern(path string) string {
+	if path == "" {
+		return `" "`
+	}
+	return `" ` + path + ` "*`
+}
+
+// shQuote quotes s for bash and zsh.
+func shQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// fishQuote quotes s for fish, which escapes within single quotes.
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s) + "'"
+}
+
+// firstSentence trims a summary to its first sentence, for completion
+// menus.
+func firstSentence(s string) string {
+	if i := strings.Index(s, ". "); i >= 0 {
+		return s[:i]
+	}
+	return strings.TrimSuffix(s, ".")
+}
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"bytes"
+	"flag"
+	"strings"
+	"testing"
+)
+
+func completionRouter() *Router {
+	noFlags := func(*flag.FlagSet) RunFunc { return nil }
+	r, _, _ := newTestRouter("", false,
+		&Command{Name: "completion", Summary: "Print a completion script. Load it in your shell.", Choices: Shells, Setup: noFlags},
+		&Command{Name: "report", Summary: "Summarise activity", Setup: func(fs *flag.FlagSet) RunFunc {
+			fs.Bool("csv", false, "")
+			return nil
+		}},
+		&Command{Name: "worktree prune", Summary: "Remove merged worktrees", Destructive: true, Files: true, Setup: noFlags},
+	)
+	return r
+}
+
+func TestCompletionTreeDeepestFirst(t *testing.T) {
+	var paths []string
+	for _, n := range completionRouter().completionTree() {
+		paths = append(paths, n.path)
+	}
+	if got := strings.Join(paths, "|"); got != "worktree prune|completion|report|worktree|" {
+		t.Errorf("paths = %q, want deepest first with the root last", got)
+	}
+}
+
+func TestWriteCompletion(t *testing.T) {
+	r := completionRouter()
+	for shell, wants := range map[string][]string{
+		"bash": {
+			`" worktree prune "*) subs='' flags='--dry-run --verbose --yes' ;;`,
+			`" completion "*) subs='bash zsh fish' flags='' ;;`,
+			`" ") subs='completion report worktree' flags='' ;;`,
+			"complete -o default -F _herd herd",
+		},
+		"zsh": {
+			`" worktree prune "*) subs=() flags=(--dry-run --verbose --yes) files=1 ;;`,
+			`" ") subs=('completion:Print a completion script' 'report:Summarise activity' 'worktree') flags=() files=0 ;;`,
+			"compdef _herd herd",
+		},
+		"fish": {
+			`complete -c herd -n '__fish_use_subcommand' -a 'report' -d 'Summarise activity'`,
+			`complete -c herd -n '__fish_seen_subcommand_from worktree' -a 'prune' -d 'Remove merged worktrees'`,
+			`complete -c herd -n '__fish_seen_subcommand_from worktree; and __fish_seen_subcommand_from prune' -l dry-run`,
+			`complete -c herd -n '__fish_seen_subcommand_from worktree; and __fish_seen_subcommand_from prune' -F`,
+			`complete -c herd -n '__fish_seen_subcommand_from completion' -a 'fish'`,
+		},
+	} {
+		var out bytes.Buffer
+		if err := r.WriteCompletion(&out, shell); err != nil {
+			t.Fatalf("WriteCompletion(%s) error: %v", shell, err)
+		}
+		for _, want := range wants {
+			if !strings.Contains(out.String(), want) {
+				t.Errorf("%s script lacks %q:\n%s", shell, want, out.String())
+			}
+		}
+	}
+	if err := r.WriteCompletion(&bytes.Buffer{}, "tcsh"); err == nil {
+		t.Error("WriteCompletion(tcsh) should fail")
+	}
+}
+
+func TestQuoting(t *testing.T) {
+	if got := shQuote("herd's"); got != `'herd'\''s'` {
+		t.Errorf("shQuote = %s", got)
+	}
+	if got := fishQuote(`herd's \n`); got != `'herd\'s \\n'` {
+		t.Errorf("fishQuote = %s", got)
+	}
+}
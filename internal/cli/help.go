@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Help layout: usages are indented two spaces and summaries start at
+// column helpIndent, wrapped to helpWidth.
+const (
+	helpIndent = 24
+	helpWidth  = 78
+)
+
+// WriteCommands writes a help line for each command: its usage and
+// summary, the summary moving below a usage too long to share its line.
+func (r *Router) WriteCommands(w io.Writer) {
+	for _, cmd := range r.Commands {
+		writeEntry(w, cmd.Usage(), cmd.Summary)
+	}
+}
+
+// WriteCommandHelp writes cmd's usage, summary and flags.
+func (r *Router) WriteCommandHelp(w io.Writer, cmd *Command) {
+	fmt.Fprintln(w, "usage:", cmd.Usage())
+	if cmd.Summary != "" {
+		fmt.Fprintln(w)
+		for _, line := range wrap(cmd.Summary, helpWidth) {
+			fmt.Fprintln(w, line)
+		}
+	}
+	if flags := cmd.Flags(); len(flags) > 0 {
+		fmt.Fprintln(w, "\nFlags:")
+		for _, f := range flags {
+			term, desc := flagUsage(f)
+			writeEntry(w, term, desc)
+		}
+	}
+}
+
+// flagUsage returns a flag as written on the command line, e.g.
+// "--since <dur>", and its description. As with the flag package, a
+// back-quoted word in the usage names the value.
+func flagUsage(f *flag.Flag) (string, string) {
+	value, usage := flag.UnquoteUsage(f)
+	if isBool(f) {
+		return "--" + f.Name, usage
+	}
+	return "--" + f.Name + " <" + value + ">", usage
+}
+
+func isBool(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// writeEntry writes term and its description in the help layout.
+func writeEntry(w io.Writer, term, desc string) {
+	pad := strings.Repeat(" ", helpIndent)
+	lines := wrap(desc, helpWidth-helpIndent)
+	head := "  " + term
+	if len(head) >= helpIndent || len(lines) == 0 {
+		fmt.Fprintln(w, head)
+	} else {
+		fmt.Fprintln(w, head+pad[len(head):]+lines[0])
+		lines = lines[1:]
+	}
+	for _, line := range lines {
+		fmt.Fprintln(w, pad+line)
+	}
+}
+
+// wrap splits text into lines of at most width runes, breaking between
+// words.
+func wrap(text string, width int) []string {
+	var lines []string
+	var line string
+	for _, word := range strings.Fields(text) {
+		switch {
+		case line == "":
+			line = word
+		case len([]rune(line))+1+len([]rune(word)) > width:
+			lines = append(lines, line)
+			line = word
+		default:
+			line += " " + word
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
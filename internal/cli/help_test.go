@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"bytes"
+	"flag"
+	"strings"
+	"testing"
+)
+
+func TestWriteCommandsWrapsLongEntries(t *testing.T) {
+	r, _, _ := newTestRouter("", false,
+		&Command{Name: "sessions", Summary: "List sessions"},
+		&Command{Name: "worktree prune", Args: "[--unmerged] [repo]", Summary: "Remove linked worktrees no session uses whose branches are merged into the main one"},
+	)
+	var out bytes.Buffer
+	r.WriteCommands(&out)
+	want := `  herd sessions         List sessions
+  herd worktree prune [--unmerged] [repo]
+                        Remove linked worktrees no session uses whose branches
+                        are merged into the main one
+`
+	if out.String() != want {
+		t.Errorf("WriteCommands() =\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestWriteCommandHelpListsFlags(t *testing.T) {
+	cmd := &Command{
+		Name:        "gc",
+		Summary:     "Delete stale state.",
+		Destructive: true,
+		Setup: func(fs *flag.FlagSet) RunFunc {
+			fs.Duration("min-age", 0, "keep state updated within this `dur`ation")
+			return nil
+		},
+	}
+	r, _, _ := newTestRouter("", false, cmd)
+	var out bytes.Buffer
+	r.WriteCommandHelp(&out, cmd)
+	for _, want := range []string{"usage: herd gc\n", "Delete stale state.", "  --dry-run ", "  --min-age <dur>       keep state updated within this duration", "  --yes "} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("help lacks %q:\n%s", want, out.String())
+		}
+	}
+}
@@ -4,6 +4,9 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
 )
 
 // Config holds herd configuration.
@@ -15,13 +18,376 @@ type Config struct {
 	// DangerouslySkipPermissions if true, launches Claude with --dangerously-skip-permissions.
 	// This skips the permission prompt for tool use.
 	DangerouslySkipPermissions bool `json:"dangerously_skip_permissions,omitempty"`
+
+	// Launch sets how Claude is started for new sessions in matching
+	// projects: the command, its flags and its environment.
+	Launch []Launch `json:"launch,omitempty"`
+
+	// Actions are quick actions offered for sessions in matching projects.
+	Actions []Action `json:"actions,omitempty"`
+
+	// TestCommands are the commands `T` runs for sessions in matching projects.
+	TestCommands []TestCommand `json:"test_commands,omitempty"`
+
+	// BadgeRules set sidebar badges from patterns in sessions' visible output.
+	BadgeRules []BadgeRule `json:"badge_rules,omitempty"`
+
+	// PromptTemplates are named Go text/templates that `herd daemon --http`
+	// renders and sends to a session on POST /prompt.
+	PromptTemplates map[string]string `json:"prompt_templates,omitempty"`
+
+	// APIToken, when set, must be sent as a bearer token on every HTTP API
+	// request.
+	APIToken string `json:"api_token,omitempty"`
+
+	// HubURL is another herd daemon's HTTP API, e.g. http://lead:7777,
+	// whose sessions the TUI lists read-only under its own. HubToken is
+	// that daemon's api_token.
+	HubURL   string `json:"hub_url,omitempty"`
+	HubToken string `json:"hub_token,omitempty"`
+
+	// UnblockPrompt is a Go text/template sent to a session when the session
+	// it is blocked on (see `B`) finishes. It may use {{.blocker}},
+	// {{.blocker_project}} and {{.project}}. Empty only announces it in tmux.
+	UnblockPrompt string `json:"unblock_prompt,omitempty"`
+
+	// TaskSources are issue trackers whose open tickets `b` offers to start
+	// sessions for.
+	TaskSources []TaskSource `json:"task_sources,omitempty"`
+
+	// ReviewIgnore lists files left out of review diffs, per project.
+	ReviewIgnore []ReviewIgnore `json:"review_ignore,omitempty"`
+
+	// ReviewTodoFile is where `w` in review mode appends feedback, relative
+	// to the repo root. Defaults to TODO.review.md.
+	ReviewTodoFile string `json:"review_todo_file,omitempty"`
+
+	// EditorURL, when set, is opened by `e` in review mode instead of
+	// running $EDITOR, with {path} (absolute) and {line} filled in: e.g.
+	// "vscode://file{path}:{line}".
+	EditorURL string `json:"editor_url,omitempty"`
+
+	// ReviewMaxLineBytes cuts diff lines longer than this short in review
+	// mode, such as minified JS. Defaults to 4096.
+	ReviewMaxLineBytes int `json:"review_max_line_bytes,omitempty"`
+
+	// ReviewLazyFileLines leaves a file whose diff has more lines than this
+	// unloaded in review mode until asked for. Defaults to 5000; negative
+	// loads every file.
+	ReviewLazyFileLines int `json:"review_lazy_file_lines,omitempty"`
+
+	// FeedbackTemplate reshapes the feedback review mode sends. Empty
+	// fields keep herd's wording.
+	FeedbackTemplate FeedbackTemplate `json:"feedback_template,omitempty"`
+
+	// WorktreeSetup prepares worktrees created from herd, per project.
+	WorktreeSetup []WorktreeSetup `json:"worktree_setup,omitempty"`
+
+	// WorktreeWarnGB is the combined size of a repo's linked worktrees, in
+	// GiB, above which the worktree panel warns. Defaults to 20; negative
+	// turns the warning off.
+	WorktreeWarnGB float64 `json:"worktree_warn_gb,omitempty"`
+
+	// GroupByTmuxSession groups sessions that have no custom group or team
+	// by the tmux session they run in.
+	GroupByTmuxSession bool `json:"group_by_tmux_session,omitempty"`
+
+	// FocusMinutes is the focus timer length `f` offers. Defaults to 25.
+	FocusMinutes int `json:"focus_minutes,omitempty"`
+
+	// StaleAfterMinutes moves sessions idle for longer into a collapsed
+	// "stale" group at the bottom of the sidebar. 0 turns this off.
+	StaleAfterMinutes int `json:"stale_after_minutes,omitempty"`
+
+	// UrgentAlert alerts the terminal when a session needs a plan approved or
+	// sent a notification while herd's window is out of sight: "bell" rings
+	// the bell, flagging herd's window in tmux; "notify" also posts an OSC
+	// 777 desktop notification and "osc9" an OSC 9 one, for terminals that
+	// only speak that. Empty turns alerts off.
+	UrgentAlert string `json:"urgent_alert,omitempty"`
+
+	// AlertStates are the states whose arrival UrgentAlert announces, of
+	// "waiting", "plan_ready" and "notifying". Empty means the last two.
+	AlertStates []string `json:"alert_states,omitempty"`
+
+	// FrameStats shows how often herd redraws and how long a frame takes
+	// to draw, at the start of the help line.
+	FrameStats bool `json:"frame_stats,omitempty"`
+
+	// HideHints leaves out the hint at the start of the help line on what
+	// to do with the selected session in its state.
+	HideHints bool `json:"hide_hints,omitempty"`
+
+	// InsertComposer makes i open the prompt composer, which sends the
+	// prompt whole once written, instead of forwarding each key as typed.
+	InsertComposer bool `json:"insert_composer,omitempty"`
+
+	// LocalEcho shows text typed in insert mode or sent from the queue's
+	// reply bar at the bottom of the session's output, marked pending, until
+	// a capture shows it, for tmux servers reached over a slow link.
+	LocalEcho bool `json:"local_echo,omitempty"`
+
+	// Capture tunes how deep and how often session output is captured, per
+	// project or session.
+	Capture []Capture `json:"capture,omitempty"`
+
+	// Squads are named sets of sessions `Q` launches together.
+	Squads []Squad `json:"squads,omitempty"`
+
+	// Presets are named ways for the TUI to open, picked with
+	// `herd --preset <name>`.
+	Presets map[string]Preset `json:"presets,omitempty"`
+
+	// Templates are kinds of session `n` can start, chosen in its picker
+	// with ctrl+t.
+	Templates []Template `json:"templates,omitempty"`
+
+	// Webhooks are URLs herd POSTs a JSON event to, such as a submitted
+	// review.
+	Webhooks []Webhook `json:"webhooks,omitempty"`
+
+	// MultiSession lists projects (~ is expanded; subdirectories count)
+	// where running several sessions side by side is routine, so launching
+	// one next to a live session doesn't offer to switch to it instead.
+	MultiSession []string `json:"multi_session,omitempty"`
+
+	// Encrypt lists the stores sealed at rest with a key from the OS
+	// keychain: any of "snippets", "prompts" and "reviews".
+	Encrypt []string `json:"encrypt,omitempty"`
+
+	// Locale picks the language of the TUI, e.g. "de"; when empty it
+	// comes from LC_ALL, LC_MESSAGES or LANG.
+	Locale string `json:"locale,omitempty"`
+
+	// RecordPrompts has hooks keep the text of each session's latest
+	// prompt and notification, shortened, in its state and the history
+	// log. Off by default, as prompts can hold anything.
+	RecordPrompts bool `json:"record_prompts,omitempty"`
+
+	// ShardState writes each session's hook state into a subdirectory of
+	// ~/.herd/sessions for its project, easing directory churn with
+	// hundreds of sessions. Files in either layout are read.
+	ShardState bool `json:"shard_state,omitempty"`
+
+	// SnapshotUpload is the paste service `H` uploads HTML snapshots to.
+	// Without a URL snapshots are only saved locally.
+	SnapshotUpload SnapshotUpload `json:"snapshot_upload,omitempty"`
+}
+
+// SnapshotUpload is a paste service that takes a POSTed file and answers
+// with its link. Field, when set, sends the file as that field of a
+// multipart form; otherwise it is the raw request body. Headers are sent
+// with the request, e.g. for auth.
+type SnapshotUpload struct {
+	URL     string            `json:"url,omitempty"`
+	Field   string            `json:"field,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// Webhook is a URL notified of herd events. Events lists the event names it
+// wants ("review_submitted", "review_approved", "needs_attention"); empty
+// means all. Project
+// works as for Action. Headers are sent with every request, e.g. for auth.
+type Webhook struct {
+	URL     string            `json:"url"`
+	Events  []string          `json:"events,omitempty"`
+	Project string            `json:"project,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// Capture sets the output capture for sessions in Project (as for Action)
+// or with the custom name Session; zero fields are left to broader entries
+// and the defaults. Scrollback is the lines of history captured (default
+// 2000), IntervalMS the milliseconds between captures (default 100, the
+// minimum) and IdleIntervalMS the same for idle sessions (default
+// IntervalMS). Filters are Go regexps; captured lines matching any of
+// them, from this entry or a broader one, are not shown.
+type Capture struct {
+	Project        string   `json:"project,omitempty"`
+	Session        string   `json:"session,omitempty"`
+	Scrollback     int      `json:"scrollback,omitempty"`
+	IntervalMS     int      `json:"interval_ms,omitempty"`
+	IdleIntervalMS int      `json:"idle_interval_ms,omitempty"`
+	Filters        []string `json:"filters,omitempty"`
+}
+
+// Preset lays the TUI out as it opens. View is the view it starts in:
+// "queue" (the attention queue), "stats" or "activity", else the session
+// list. Expand and Collapse name groups to open or fold; Select is the
+// session to select, "urgent" for the one waiting longest on you or a pane
+// ID, name or project as `herd kill` takes; Filter is a filter to apply.
+type Preset struct {
+	View     string   `json:"view,omitempty"`
+	Expand   []string `json:"expand,omitempty"`
+	Collapse []string `json:"collapse,omitempty"`
+	Select   string   `json:"select,omitempty"`
+	Filter   string   `json:"filter,omitempty"`
+}
+
+// Template is a kind of session to start, such as "fix failing tests":
+// Claude in Project (or the project picked, when empty) with Flags added to
+// its command line and Prompt as its opening prompt.
+type Template struct {
+	Name    string `json:"name"`
+	Project string `json:"project,omitempty"`
+	Flags   string `json:"flags,omitempty"`
+	Prompt  string `json:"prompt,omitempty"`
+}
+
+// Squad is a set of sessions launched as one group, named after the squad.
+// Pin pins the group once it is up.
+type Squad struct {
+	Name    string        `json:"name"`
+	Pin     bool          `json:"pin,omitempty"`
+	Members []SquadMember `json:"members"`
+}
+
+// SquadMember is one session of a squad. Project is the directory (a repo
+// or one of its worktrees) Claude starts in; Prompt, when set, is its
+// opening prompt and Name its sidebar name.
+type SquadMember struct {
+	Project string `json:"project"`
+	Name    string `json:"name,omitempty"`
+	Prompt  string `json:"prompt,omitempty"`
+}
+
+// WorktreeSetup readies a fresh worktree. Copy lists untracked files or
+// directories (globs relative to the repo root, like ".env*") copied from
+// the main worktree; PostCreate is a shell command run in a pane below the
+// new session, e.g. "npm install". Project works as for Action.
+type WorktreeSetup struct {
+	Project    string   `json:"project,omitempty"`
+	Copy       []string `json:"copy,omitempty"`
+	PostCreate string   `json:"post_create,omitempty"`
+}
+
+// FeedbackTemplate holds Go text/templates for review feedback. Preamble
+// and Closing may use {{.project}}, {{.count}} and {{.files}}; Comment is
+// rendered per comment with {{.file}}, {{.line}}, {{.code}}, {{.comment}}
+// and {{.thread}}.
+type FeedbackTemplate struct {
+	Preamble string `json:"preamble,omitempty"`
+	Comment  string `json:"comment,omitempty"`
+	Closing  string `json:"closing,omitempty"`
+}
+
+// ReviewIgnore hides files matching Patterns from review diffs. Project
+// works as for Action. A pattern without a slash matches file names
+// anywhere ("*.lock"), one ending in a slash matches a directory anywhere
+// ("vendor/"), and any other is matched against the path from the repo
+// root ("web/dist/*.js").
+type ReviewIgnore struct {
+	Project  string   `json:"project,omitempty"`
+	Patterns []string `json:"patterns"`
+}
+
+// ProjectConfig is read from .herd.json at a project's root, for settings
+// that belong with the repo rather than the user.
+type ProjectConfig struct {
+	// ReviewIgnore adds patterns, as for ReviewIgnore, for this repo.
+	ReviewIgnore []string `json:"review_ignore,omitempty"`
+
+	// ReviewTodoFile overrides the user's ReviewTodoFile for this repo.
+	ReviewTodoFile string `json:"review_todo_file,omitempty"`
+
+	// Worktree adds files to copy to, and overrides the post-create
+	// command of, the user's WorktreeSetup for this repo.
+	Worktree WorktreeSetup `json:"worktree,omitempty"`
+}
+
+// LoadProject reads dir/.herd.json. A missing or invalid file is empty.
+func LoadProject(dir string) ProjectConfig {
+	var pc ProjectConfig
+	data, err := os.ReadFile(filepath.Join(dir, ".herd.json"))
+	if err != nil {
+		return pc
+	}
+	_ = json.Unmarshal(data, &pc)
+	return pc
+}
+
+// TaskSource configures one issue tracker. Type is "jira" or "linear".
+type TaskSource struct {
+	Type string `json:"type"`
+
+	// URL is the Jira site (https://acme.atlassian.net) or an override for
+	// the Linear GraphQL endpoint.
+	URL string `json:"url,omitempty"`
+
+	// Email and Token authenticate to Jira Cloud; without Email the token is
+	// sent as a bearer PAT. TokenEnv names an environment variable to read
+	// the token from instead of storing it here.
+	Email    string `json:"email,omitempty"`
+	Token    string `json:"token,omitempty"`
+	TokenEnv string `json:"token_env,omitempty"`
+
+	// Query is the JQL for Jira; it defaults to the user's open issues.
+	Query string `json:"query,omitempty"`
+
+	// Project is the directory sessions for this source's tickets start in.
+	Project string `json:"project"`
+}
+
+// Label names the source in errors.
+func (t TaskSource) Label() string {
+	if t.URL != "" {
+		return t.Type + " " + t.URL
+	}
+	return t.Type
+}
+
+// BadgeRule sets the badge Name to Text while Pattern (a Go regexp) matches
+// a session's visible output. When several rules share a Name, the one whose
+// match appears furthest down the screen wins — so "FAIL" after "PASS" shows
+// the failure.
+type BadgeRule struct {
+	Name    string `json:"name"`
+	Pattern string `json:"pattern"`
+	Text    string `json:"text"`
+	Color   string `json:"color,omitempty"` // green, red, amber, blue, purple, cyan or #rrggbb
+}
+
+// Launch sets how sessions in Project (as for Action) are started. Command
+// replaces "claude", e.g. "npx @anthropic-ai/claude-code"; Flags are added
+// to its command line, e.g. "--model opus"; Env is set in the new window.
+// Where several entries match, a deeper Project's Command and Flags win,
+// and Env adds up with the deeper entry's values winning.
+type Launch struct {
+	Project string            `json:"project,omitempty"`
+	Command string            `json:"command,omitempty"`
+	Flags   string            `json:"flags,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+}
+
+// TestCommand is a project's test command. Project works as for Action; when
+// several match, the one with the deepest Project wins.
+type TestCommand struct {
+	Project string `json:"project,omitempty"`
+	Command string `json:"command"`
+}
+
+// Action is a user-defined quick action. Exactly one of Shell or Prompt
+// should be set: Shell runs in a pane split below the session, Prompt is
+// sent to Claude.
+type Action struct {
+	Label string `json:"label"`
+
+	// Project limits the action to sessions at or below this directory
+	// (~ is expanded). Empty means every project.
+	Project string `json:"project,omitempty"`
+
+	Shell  string `json:"shell,omitempty"`
+	Prompt string `json:"prompt,omitempty"`
 }
 
 // DefaultConfig returns the default configuration.
 func DefaultConfig() Config {
 	home, _ := os.UserHomeDir()
 	return Config{
-		ProjectDirs: []string{home},
+		ProjectDirs:         []string{home},
+		WorktreeWarnGB:      20,
+		FocusMinutes:        25,
+		ReviewLazyFileLines: 5000,
 	}
 }
 
@@ -51,6 +417,50 @@ func LoadFrom(path string) Config {
 		cfg.ProjectDirs = loaded.ProjectDirs
 	}
 	cfg.DangerouslySkipPermissions = loaded.DangerouslySkipPermissions
+	cfg.Launch = loaded.Launch
+	cfg.Actions = loaded.Actions
+	cfg.TestCommands = loaded.TestCommands
+	cfg.BadgeRules = loaded.BadgeRules
+	cfg.PromptTemplates = loaded.PromptTemplates
+	cfg.APIToken = loaded.APIToken
+	cfg.HubURL = loaded.HubURL
+	cfg.HubToken = loaded.HubToken
+	cfg.TaskSources = loaded.TaskSources
+	cfg.UnblockPrompt = loaded.UnblockPrompt
+	cfg.ReviewIgnore = loaded.ReviewIgnore
+	cfg.ReviewTodoFile = loaded.ReviewTodoFile
+	cfg.EditorURL = loaded.EditorURL
+	cfg.ReviewMaxLineBytes = loaded.ReviewMaxLineBytes
+	if loaded.ReviewLazyFileLines != 0 {
+		cfg.ReviewLazyFileLines = loaded.ReviewLazyFileLines
+	}
+	cfg.FeedbackTemplate = loaded.FeedbackTemplate
+	cfg.WorktreeSetup = loaded.WorktreeSetup
+	cfg.Squads = loaded.Squads
+	cfg.Presets = loaded.Presets
+	cfg.Templates = loaded.Templates
+	cfg.Webhooks = loaded.Webhooks
+	cfg.SnapshotUpload = loaded.SnapshotUpload
+	cfg.MultiSession = loaded.MultiSession
+	cfg.ShardState = loaded.ShardState
+	cfg.RecordPrompts = loaded.RecordPrompts
+	cfg.Encrypt = loaded.Encrypt
+	cfg.Locale = loaded.Locale
+	cfg.Capture = loaded.Capture
+	cfg.UrgentAlert = loaded.UrgentAlert
+	cfg.AlertStates = loaded.AlertStates
+	cfg.FrameStats = loaded.FrameStats
+	cfg.HideHints = loaded.HideHints
+	cfg.InsertComposer = loaded.InsertComposer
+	cfg.LocalEcho = loaded.LocalEcho
+	cfg.GroupByTmuxSession = loaded.GroupByTmuxSession
+	cfg.StaleAfterMinutes = loaded.StaleAfterMinutes
+	if loaded.FocusMinutes > 0 {
+		cfg.FocusMinutes = loaded.FocusMinutes
+	}
+	if loaded.WorktreeWarnGB != 0 {
+		cfg.WorktreeWarnGB = loaded.WorktreeWarnGB
+	}
 
 	return cfg
 }
@@ -74,6 +484,12 @@ func Load() Config {
 	return LoadFrom(configPath())
 }
 
+// Exists reports whether the config file has been written.
+func Exists() bool {
+	_, err := os.Stat(configPath())
+	return err == nil
+}
+
 // Save writes the config to disk.
 func Save(cfg Config) error {
 	return SaveTo(configPath(), cfg)
@@ -95,3 +511,201 @@ func (c Config) GetProjectDirs() []string {
 
 	return dirs
 }
+
+// ActionsFor returns the actions that apply to a session in projectPath, in
+// config order.
+func (c Config) ActionsFor(projectPath string) []Action {
+	var out []Action
+	for _, a := range c.Actions {
+		if a.Project == "" || isWithin(projectPath, expandHome(a.Project)) {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// MultiSessionFor reports whether projectPath is within a MultiSession
+// project.
+func (c Config) MultiSessionFor(projectPath string) bool {
+	for _, dir := range c.MultiSession {
+		if isWithin(projectPath, expandHome(dir)) {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhooksFor returns the webhooks that want event for a session in
+// projectPath, in config order.
+func (c Config) WebhooksFor(projectPath, event string) []Webhook {
+	var out []Webhook
+	for _, w := range c.Webhooks {
+		if w.Project != "" && !isWithin(projectPath, expandHome(w.Project)) {
+			continue
+		}
+		if len(w.Events) > 0 && !slices.Contains(w.Events, event) {
+			continue
+		}
+		out = append(out, w)
+	}
+	return out
+}
+
+// LaunchFor returns how to start a session in projectPath: the matching
+// entries merged from the broadest in, with Command "claude" if none sets
+// one.
+func (c Config) LaunchFor(projectPath string) Launch {
+	var matches []Launch
+	for _, l := range c.Launch {
+		if l.Project == "" || isWithin(projectPath, expandHome(l.Project)) {
+			matches = append(matches, l)
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return len(expandHome(matches[i].Project)) < len(expandHome(matches[j].Project))
+	})
+
+	out := Launch{Command: "claude", Env: make(map[string]string)}
+	for _, l := range matches {
+		if l.Command != "" {
+			out.Command = l.Command
+		}
+		if l.Flags != "" {
+			out.Flags = l.Flags
+		}
+		for k, v := range l.Env {
+			out.Env[k] = v
+		}
+	}
+	return out
+}
+
+// TestCommandFor returns the test command for a session in projectPath, or
+// "" if none is configured.
+func (c Config) TestCommandFor(projectPath string) string {
+	best, bestLen := "", -1
+	for _, tc := range c.TestCommands {
+		dir := expandHome(tc.Project)
+		if tc.Project != "" && !isWithin(projectPath, dir) {
+			continue
+		}
+		if l := len(dir); l > bestLen {
+			best, bestLen = tc.Command, l
+		}
+	}
+	return best
+}
+
+// ReviewIgnoreFor returns the review ignore patterns for the repo at
+// gitRoot: those from every matching ReviewIgnore entry, then the repo's
+// own .herd.json.
+func (c Config) ReviewIgnoreFor(gitRoot string) []string {
+	var out []string
+	for _, ri := range c.ReviewIgnore {
+		if ri.Project == "" || isWithin(gitRoot, expandHome(ri.Project)) {
+			out = append(out, ri.Patterns...)
+		}
+	}
+	return append(out, LoadProject(gitRoot).ReviewIgnore...)
+}
+
+// ReviewTodoPathFor returns where review feedback is written as a todo file
+// for the repo at gitRoot.
+func (c Config) ReviewTodoPathFor(gitRoot string) string {
+	name := c.ReviewTodoFile
+	if pc := LoadProject(gitRoot); pc.ReviewTodoFile != "" {
+		name = pc.ReviewTodoFile
+	}
+	if name == "" {
+		name = "TODO.review.md"
+	}
+	name = expandHome(name)
+	if filepath.IsAbs(name) {
+		return name
+	}
+	return filepath.Join(gitRoot, name)
+}
+
+// WorktreeSetupFor returns the setup for new worktrees of the repo whose
+// main worktree is repoRoot: files to copy from every matching entry and the
+// repo's .herd.json, and the post-create command from .herd.json or else
+// the deepest matching entry.
+func (c Config) WorktreeSetupFor(repoRoot string) WorktreeSetup {
+	var out WorktreeSetup
+	bestLen := -1
+	for _, ws := range c.WorktreeSetup {
+		dir := expandHome(ws.Project)
+		if ws.Project != "" && !isWithin(repoRoot, dir) {
+			continue
+		}
+		out.Copy = append(out.Copy, ws.Copy...)
+		if ws.PostCreate != "" && len(dir) > bestLen {
+			out.PostCreate, bestLen = ws.PostCreate, len(dir)
+		}
+	}
+	pc := LoadProject(repoRoot).Worktree
+	out.Copy = append(out.Copy, pc.Copy...)
+	if pc.PostCreate != "" {
+		out.PostCreate = pc.PostCreate
+	}
+	return out
+}
+
+// CaptureFor returns the capture settings for a session in projectPath with
+// custom name (empty if none): defaults, overridden by entries for every
+// project containing it from the broadest in, then by entries naming the
+// session. Filters add up across all of them.
+func (c Config) CaptureFor(projectPath, name string) Capture {
+	var matches []Capture
+	for _, cp := range c.Capture {
+		if cp.Session != "" {
+			continue
+		}
+		if cp.Project == "" || isWithin(projectPath, expandHome(cp.Project)) {
+			matches = append(matches, cp)
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return len(expandHome(matches[i].Project)) < len(expandHome(matches[j].Project))
+	})
+	for _, cp := range c.Capture {
+		if cp.Session != "" && cp.Session == name {
+			matches = append(matches, cp)
+		}
+	}
+
+	out := Capture{Scrollback: 2000, IntervalMS: 100}
+	for _, cp := range matches {
+		if cp.Scrollback > 0 {
+			out.Scrollback = cp.Scrollback
+		}
+		if cp.IntervalMS > 0 {
+			out.IntervalMS = cp.IntervalMS
+		}
+		if cp.IdleIntervalMS > 0 {
+			out.IdleIntervalMS = cp.IdleIntervalMS
+		}
+		out.Filters = append(out.Filters, cp.Filters...)
+	}
+	out.IntervalMS = max(out.IntervalMS, 100)
+	if out.IdleIntervalMS == 0 {
+		out.IdleIntervalMS = out.IntervalMS
+	}
+	out.IdleIntervalMS = max(out.IdleIntervalMS, 100)
+	return out
+}
+
+// isWithin reports whether path is dir or a descendant of it.
+func isWithin(path, dir string) bool {
+	rel, err := filepath.Rel(filepath.Clean(dir), filepath.Clean(path))
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, "../")
+}
+
+// expandHome expands a leading ~ to the home directory.
+func expandHome(p string) string {
+	if len(p) > 0 && p[0] == '~' {
+		home, _ := os.UserHomeDir()
+		return filepath.Join(home, p[1:])
+	}
+	return p
+}
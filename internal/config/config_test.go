@@ -3,6 +3,8 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -109,3 +111,208 @@ func TestSaveToCreatesDirectory(t *testing.T) {
 		t.Fatalf("SaveTo() error when directory missing: %v", err)
 	}
 }
+
+func TestLoadFromActions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	data := `{"actions": [{"label": "Test", "project": "/code/app", "shell": "go test ./..."}]}`
+	os.WriteFile(path, []byte(data), 0644)
+
+	cfg := LoadFrom(path)
+	if len(cfg.Actions) != 1 || cfg.Actions[0].Shell != "go test ./..." {
+		t.Errorf("Actions = %+v, want one shell action", cfg.Actions)
+	}
+}
+
+func TestActionsFor(t *testing.T) {
+	cfg := Config{Actions: []Action{
+		{Label: "everywhere", Prompt: "status?"},
+		{Label: "app", Project: "/code/app", Shell: "make"},
+		{Label: "other", Project: "/code/other", Shell: "make"},
+	}}
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"/code/app", []string{"everywhere", "app"}},
+		{"/code/app/sub", []string{"everywhere", "app"}},
+		{"/code/application", []string{"everywhere"}},
+		{"/elsewhere", []string{"everywhere"}},
+	}
+	for _, tt := range tests {
+		got := cfg.ActionsFor(tt.path)
+		var labels []string
+		for _, a := range got {
+			labels = append(labels, a.Label)
+		}
+		if strings.Join(labels, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("ActionsFor(%q) = %v, want %v", tt.path, labels, tt.want)
+		}
+	}
+}
+
+func TestMultiSessionFor(t *testing.T) {
+	cfg := Config{MultiSession: []string{"/code/mono"}}
+	for path, want := range map[string]bool{
+		"/code/mono":     true,
+		"/code/mono/web": true,
+		"/code/monorepo": false,
+		"/elsewhere":     false,
+	} {
+		if got := cfg.MultiSessionFor(path); got != want {
+			t.Errorf("MultiSessionFor(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestWebhooksFor(t *testing.T) {
+	cfg := Config{Webhooks: []Webhook{
+		{URL: "all"},
+		{URL: "approvals", Events: []string{"review_approved"}},
+		{URL: "app", Project: "/code/app"},
+	}}
+
+	tests := []struct {
+		path, event string
+		want        []string
+	}{
+		{"/code/app", "review_submitted", []string{"all", "app"}},
+		{"/code/app", "review_approved", []string{"all", "approvals", "app"}},
+		{"/elsewhere", "review_submitted", []string{"all"}},
+	}
+	for _, tt := range tests {
+		var urls []string
+		for _, w := range cfg.WebhooksFor(tt.path, tt.event) {
+			urls = append(urls, w.URL)
+		}
+		if strings.Join(urls, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("WebhooksFor(%q, %q) = %v, want %v", tt.path, tt.event, urls, tt.want)
+		}
+	}
+}
+
+func TestTestCommandFor(t *testing.T) {
+	cfg := Config{TestCommands: []TestCommand{
+		{Command: "make test"},
+		{Project: "/code/app/web", Command: "npm test"},
+		{Project: "/code/app", Command: "go test ./..."},
+	}}
+
+	tests := map[string]string{
+		"/code/app":         "go test ./...",
+		"/code/app/web/src": "npm test",
+		"/elsewhere":        "make test",
+	}
+	for path, want := range tests {
+		if got := cfg.TestCommandFor(path); got != want {
+			t.Errorf("TestCommandFor(%q) = %q, want %q", path, got, want)
+		}
+	}
+	if got := (Config{}).TestCommandFor("/code/app"); got != "" {
+		t.Errorf("TestCommandFor with no config = %q, want empty", got)
+	}
+}
+
+func TestReviewIgnoreFor(t *testing.T) {
+	repo := t.TempDir()
+	if err := os.WriteFile(filepath.Join(repo, ".herd.json"), []byte(`{"review_ignore": ["gen/"]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := Config{ReviewIgnore: []ReviewIgnore{
+		{Patterns: []string{"*.lock"}},
+		{Project: repo, Patterns: []string{"vendor/"}},
+		{Project: "/elsewhere", Patterns: []string{"*.go"}},
+	}}
+	got := cfg.ReviewIgnoreFor(repo)
+	want := []string{"*.lock", "vendor/", "gen/"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("ReviewIgnoreFor = %q, want %q", got, want)
+	}
+}
+
+func TestReviewTodoPathFor(t *testing.T) {
+	repo := t.TempDir()
+	if got := (Config{}).ReviewTodoPathFor(repo); got != filepath.Join(repo, "TODO.review.md") {
+		t.Errorf("default path = %q", got)
+	}
+	cfg := Config{ReviewTodoFile: ".claude/review.md"}
+	if got := cfg.ReviewTodoPathFor(repo); got != filepath.Join(repo, ".claude", "review.md") {
+		t.Errorf("configured path = %q", got)
+	}
+	if err := os.WriteFile(filepath.Join(repo, ".herd.json"), []byte(`{"review_todo_file": "docs/FEEDBACK.md"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.ReviewTodoPathFor(repo); got != filepath.Join(repo, "docs", "FEEDBACK.md") {
+		t.Errorf("repo override = %q", got)
+	}
+}
+
+func TestWorktreeSetupFor(t *testing.T) {
+	repo := t.TempDir()
+	cfg := Config{WorktreeSetup: []WorktreeSetup{
+		{Copy: []string{".env"}, PostCreate: "make setup"},
+		{Project: repo, PostCreate: "npm install"},
+		{Project: "/elsewhere", Copy: []string{"nope"}},
+	}}
+	got := cfg.WorktreeSetupFor(repo)
+	if strings.Join(got.Copy, ",") != ".env" || got.PostCreate != "npm install" {
+		t.Errorf("WorktreeSetupFor = %+v", got)
+	}
+
+	if err := os.WriteFile(filepath.Join(repo, ".herd.json"), []byte(`{"worktree": {"copy": [".vscode/"], "post_create": "pnpm i"}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got = cfg.WorktreeSetupFor(repo)
+	if strings.Join(got.Copy, ",") != ".env,.vscode/" || got.PostCreate != "pnpm i" {
+		t.Errorf("with .herd.json WorktreeSetupFor = %+v", got)
+	}
+}
+
+func TestCaptureFor(t *testing.T) {
+	cfg := Config{Capture: []Capture{
+		{Project: "/code/build", Scrollback: 10000, Filters: []string{`^Building`}},
+		{IdleIntervalMS: 5000},
+		{Project: "/code", IntervalMS: 250, Filters: []string{`^\s*[⠋⠙⠹]`}},
+		{Session: "logs", IntervalMS: 50},
+	}}
+	tests := []struct {
+		project, name string
+		want          Capture
+	}{
+		{"/tmp/x", "", Capture{Scrollback: 2000, IntervalMS: 100, IdleIntervalMS: 5000}},
+		{"/code/build/app", "", Capture{Scrollback: 10000, IntervalMS: 250, IdleIntervalMS: 5000, Filters: []string{`^\s*[⠋⠙⠹]`, `^Building`}}},
+		{"/code/build", "logs", Capture{Scrollback: 10000, IntervalMS: 100, IdleIntervalMS: 5000, Filters: []string{`^\s*[⠋⠙⠹]`, `^Building`}}},
+	}
+	for _, tt := range tests {
+		if got := cfg.CaptureFor(tt.project, tt.name); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("CaptureFor(%q, %q) = %+v, want %+v", tt.project, tt.name, got, tt.want)
+		}
+	}
+	if got := (Config{}).CaptureFor("/code", ""); got.IdleIntervalMS != 100 {
+		t.Errorf("idle interval defaults to %d, want the interval", got.IdleIntervalMS)
+	}
+}
+
+func TestLaunchFor(t *testing.T) {
+	cfg := Config{Launch: []Launch{
+		{Project: "/code/app/web", Flags: "--model haiku", Env: map[string]string{"NODE_ENV": "test"}},
+		{Flags: "--model opus", Env: map[string]string{"NODE_ENV": "dev", "TZ": "UTC"}},
+		{Project: "/code/app", Command: "npx @anthropic-ai/claude-code"},
+	}}
+
+	got := cfg.LaunchFor("/code/app/web/src")
+	want := Launch{
+		Command: "npx @anthropic-ai/claude-code",
+		Flags:   "--model haiku",
+		Env:     map[string]string{"NODE_ENV": "test", "TZ": "UTC"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LaunchFor(web) = %+v, want %+v", got, want)
+	}
+	if got := cfg.LaunchFor("/elsewhere"); got.Command != "claude" || got.Flags != "--model opus" {
+		t.Errorf("LaunchFor(elsewhere) = %+v, want claude --model opus", got)
+	}
+	if got := (Config{}).LaunchFor("/code/app"); got.Command != "claude" || got.Flags != "" || len(got.Env) != 0 {
+		t.Errorf("LaunchFor with no config = %+v, want plain claude", got)
+	}
+}
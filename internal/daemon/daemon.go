@@ -0,0 +1,342 @@
+// Package daemon runs herd's session monitoring headless and serves the
+// current session list to clients over a unix socket, so monitoring,
+// alerts and webhooks continue while no TUI is open. A TUI started while a
+// daemon runs takes its session list from the daemon and leaves alerting
+// to it.
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shnupta/herd/internal/config"
+	"github.com/shnupta/herd/internal/history"
+	"github.com/shnupta/herd/internal/names"
+	"github.com/shnupta/herd/internal/notify"
+	"github.com/shnupta/herd/internal/session"
+	"github.com/shnupta/herd/internal/state"
+	"github.com/shnupta/herd/internal/tmux"
+	"github.com/shnupta/herd/internal/webhook"
+)
+
+// refreshInterval matches the TUI's session list refresh.
+const refreshInterval = 3 * time.Second
+
+// SessionInfo is the client-facing view of one session.
+type SessionInfo struct {
+	Key            string    `json:"key"`
+	Name           string    `json:"name,omitempty"`
+	ID             string    `json:"session_id,omitempty"`
+	TmuxPane       string    `json:"tmux_pane"`
+	TmuxSession    string    `json:"tmux_session,omitempty"`
+	WindowIndex    int       `json:"window_index"`
+	PaneIndex      int       `json:"pane_index"`
+	ProjectPath    string    `json:"project_path"`
+	GitRoot        string    `json:"git_root,omitempty"`
+	GitBranch      string    `json:"git_branch,omitempty"`
+	State          string    `json:"state"`
+	CurrentTool    string    `json:"current_tool,omitempty"`
+	Model          string    `json:"model,omitempty"`
+	PermissionMode string    `json:"permission_mode,omitempty"`
+	ContextTokens  int       `json:"context_tokens,omitempty"`
+	ContextPercent int       `json:"context_percent,omitempty"`
+	TranscriptPath string    `json:"transcript_path,omitempty"`
+	Prompt         string    `json:"prompt,omitempty"`
+	Message        string    `json:"message,omitempty"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// Session turns info back into the session it was made from, for clients
+// such as the TUI that work with sessions.
+func (info SessionInfo) Session() session.Session {
+	return session.Session{
+		ID:             info.ID,
+		TmuxPane:       info.TmuxPane,
+		TmuxSession:    info.TmuxSession,
+		WindowIndex:    info.WindowIndex,
+		PaneIndex:      info.PaneIndex,
+		ProjectPath:    info.ProjectPath,
+		GitRoot:        info.GitRoot,
+		GitBranch:      info.GitBranch,
+		State:          session.ParseState(info.State),
+		CurrentTool:    info.CurrentTool,
+		UpdatedAt:      info.UpdatedAt,
+		PermissionMode: info.PermissionMode,
+		Model:          info.Model,
+		ContextTokens:  info.ContextTokens,
+		TranscriptPath: info.TranscriptPath,
+		Prompt:         info.Prompt,
+		Message:        info.Message,
+	}
+}
+
+// Snapshot is what the daemon sends to each client.
+type Snapshot struct {
+	Sessions  []SessionInfo `json:"sessions"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+// SocketPath returns the default socket location, ~/.herd/daemon.sock.
+func SocketPath() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".herd", "daemon.sock")
+}
+
+// Daemon discovers sessions, applies hook state and keeps the latest Snapshot.
+type Daemon struct {
+	client    tmux.ClientIface
+	readState func() ([]state.SessionState, error)
+
+	// historyPath is the log prompts sent over HTTP are recorded in.
+	historyPath string
+
+	// profiling serves ProfileHandler under /debug/pprof/ on the HTTP API.
+	profiling bool
+
+	// loadConfig is read on every refresh, for urgent_alert, alert_states
+	// and webhooks.
+	loadConfig func() config.Config
+
+	// states are the sessions' states at the last refresh, by tmux pane,
+	// to tell which have just come to need the user; nil before the first.
+	// Only Refresh uses them.
+	states map[string]session.State
+
+	mu   sync.Mutex
+	snap Snapshot
+}
+
+// clientTTYs and writeTTY are variables so tests can stub them.
+var (
+	clientTTYs = tmux.ClientTTYs
+	writeTTY   = func(path, seq string) error {
+		f, err := os.OpenFile(path, os.O_WRONLY, 0)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = f.WriteString(seq)
+		return err
+	}
+)
+
+// New creates a Daemon that discovers sessions with client and reads hook
+// state with readState (normally state.ReadAll).
+func New(client tmux.ClientIface, readState func() ([]state.SessionState, error)) *Daemon {
+	return &Daemon{client: client, readState: readState, historyPath: history.Path(), loadConfig: config.Load}
+}
+
+// SetProfiling turns the pprof endpoints of the HTTP API on or off. It
+// must be called before the API is served.
+func (d *Daemon) SetProfiling(on bool) { d.profiling = on }
+
+// Refresh rediscovers sessions and updates the snapshot, announcing those
+// that have come to need the user since the last refresh.
+func (d *Daemon) Refresh() error {
+	sessions, err := session.Discover(d.client)
+	if err != nil {
+		return err
+	}
+	if states, err := d.readState(); err == nil {
+		session.ApplyStates(sessions, states)
+	}
+
+	// The TUI may have renamed sessions since the last refresh.
+	_ = names.Reload()
+
+	snap := Snapshot{Sessions: make([]SessionInfo, 0, len(sessions)), UpdatedAt: time.Now()}
+	for _, s := range sessions {
+		snap.Sessions = append(snap.Sessions, SessionInfo{
+			Key:            s.Key(),
+			Name:           names.Get(s.Key()),
+			ID:             s.ID,
+			TmuxPane:       s.TmuxPane,
+			TmuxSession:    s.TmuxSession,
+			WindowIndex:    s.WindowIndex,
+			PaneIndex:      s.PaneIndex,
+			ProjectPath:    s.ProjectPath,
+			GitRoot:        s.GitRoot,
+			GitBranch:      s.GitBranch,
+			State:          s.State.String(),
+			CurrentTool:    s.CurrentTool,
+			Model:          s.Model,
+			PermissionMode: s.PermissionMode,
+			ContextTokens:  s.ContextTokens,
+			ContextPercent: s.ContextPercent(),
+			TranscriptPath: s.TranscriptPath,
+			Prompt:         s.Prompt,
+			Message:        s.Message,
+			UpdatedAt:      s.UpdatedAt,
+		})
+	}
+	d.mu.Lock()
+	d.snap = snap
+	d.mu.Unlock()
+
+	before := d.states
+	d.states = make(map[string]session.State, len(sessions))
+	for _, s := range sessions {
+		d.states[s.TmuxPane] = s.State
+	}
+	if before != nil {
+		cfg := d.loadConfig()
+		d.announce(cfg, notify.Arrivals(before, sessions, cfg.AlertStates))
+	}
+	return nil
+}
+
+// announce tells the user that sessions need them: on every attached tmux
+// client's terminal, as urgent_alert configures, and to the webhooks that
+// want needs_attention events. Deliveries happen in the background.
+func (d *Daemon) announce(cfg config.Config, sessions []session.Session) {
+	if len(sessions) == 0 {
+		return
+	}
+	var who []string
+	var deliveries []func() []error
+	for _, s := range sessions {
+		name := names.Get(s.Key())
+		if name == "" {
+			name = filepath.Base(s.ProjectPath)
+		}
+		who = append(who, name)
+		if hooks := cfg.WebhooksFor(s.ProjectPath, webhook.NeedsAttention); len(hooks) > 0 {
+			ev := webhook.Event{
+				Event: webhook.NeedsAttention,
+				Time:  time.Now(),
+				State: s.State.String(),
+				Session: webhook.Session{
+					Key:     s.Key(),
+					Pane:    s.TmuxPane,
+					Name:    names.Get(s.Key()),
+					Project: s.ProjectPath,
+					Branch:  s.GitBranch,
+				},
+			}
+			deliveries = append(deliveries, func() []error { return webhook.Send(context.Background(), hooks, ev) })
+		}
+	}
+
+	style, client := cfg.UrgentAlert, d.client
+	go func() {
+		if notify.Valid(style) {
+			seq := notify.Alert(style, "herd", strings.Join(who, ", ")+" needs you")
+			ttys, _ := clientTTYs()
+			for _, tty := range ttys {
+				_ = writeTTY(tty, seq)
+			}
+		}
+		for _, deliver := range deliveries {
+			for _, err := range deliver() {
+				_ = client.DisplayMessage("herd: " + err.Error())
+			}
+		}
+	}()
+}
+
+// Snapshot returns the latest snapshot.
+func (d *Daemon) Snapshot() Snapshot {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.snap
+}
+
+// Run listens on sockPath and refreshes on a timer and on every hook state
+// event from events (which may be nil) until ctx is cancelled.
+func (d *Daemon) Run(ctx context.Context, sockPath string, events <-chan state.SessionState) error {
+	ln, err := listen(sockPath)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(sockPath)
+	go func() {
+		<-ctx.Done()
+		ln.Close()
+	}()
+	go d.serve(ln)
+
+	_ = d.Refresh()
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			_ = d.Refresh()
+		case _, ok := <-events:
+			if !ok {
+				events = nil
+				continue
+			}
+			_ = d.Refresh()
+		}
+	}
+}
+
+// serve answers each connection with the current snapshot as one JSON line.
+func (d *Daemon) serve(ln net.Listener) {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer conn.Close()
+			_ = conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+			_ = json.NewEncoder(conn).Encode(d.Snapshot())
+		}()
+	}
+}
+
+// listen opens the unix socket, clearing a stale socket file left by a
+// daemon that did not shut down cleanly. It refuses to start a second daemon.
+func listen(sockPath string) (net.Listener, error) {
+	if err := os.MkdirAll(filepath.Dir(sockPath), 0o755); err != nil {
+		return nil, err
+	}
+	if conn, err := net.DialTimeout("unix", sockPath, time.Second); err == nil {
+		conn.Close()
+		return nil, fmt.Errorf("herd daemon already running on %s", sockPath)
+	}
+	if err := os.Remove(sockPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	return net.Listen("unix", sockPath)
+}
+
+// Running reports whether a daemon answers on sockPath.
+func Running(sockPath string) bool {
+	if sockPath == "" {
+		return false
+	}
+	conn, err := net.DialTimeout("unix", sockPath, time.Second)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+// Query fetches the current snapshot from a running daemon.
+func Query(sockPath string) (Snapshot, error) {
+	var snap Snapshot
+	conn, err := net.DialTimeout("unix", sockPath, time.Second)
+	if err != nil {
+		return snap, fmt.Errorf("herd daemon not running: %w", err)
+	}
+	defer conn.Close()
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if err := json.NewDecoder(conn).Decode(&snap); err != nil {
+		return snap, fmt.Errorf("reading daemon snapshot: %w", err)
+	}
+	return snap, nil
+}
@@ -0,0 +1,156 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shnupta/herd/internal/config"
+	"github.com/shnupta/herd/internal/state"
+	"github.com/shnupta/herd/internal/tmux"
+	"github.com/shnupta/herd/internal/tmux/tmuxtest"
+	"github.com/shnupta/herd/internal/webhook"
+)
+
+// shortSocket returns a socket path short enough for the unix socket limit,
+// which t.TempDir() paths can exceed on macOS.
+func shortSocket(t *testing.T) string {
+	t.Helper()
+	dir, err := os.MkdirTemp("", "herdd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return filepath.Join(dir, "d.sock")
+}
+
+func testDaemon() *Daemon {
+	client := &tmuxtest.MockClient{Panes: []tmux.Pane{
+		{ID: "%1", SessionName: "0", CurrentCmd: "claude", CurrentPath: "/tmp"},
+		{ID: "%2", SessionName: "0", CurrentCmd: "zsh", CurrentPath: "/tmp"},
+	}}
+	return New(client, func() ([]state.SessionState, error) {
+		return []state.SessionState{{SessionID: "abc", TmuxPane: "%1", State: "waiting"}}, nil
+	})
+}
+
+func TestRefreshBuildsSnapshot(t *testing.T) {
+	d := testDaemon()
+	if err := d.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	snap := d.Snapshot()
+	if len(snap.Sessions) != 1 {
+		t.Fatalf("Sessions = %+v, want only the claude pane", snap.Sessions)
+	}
+	if got := snap.Sessions[0]; got.Key != "session:abc" || got.State != "waiting" {
+		t.Errorf("session = %+v, want session:abc waiting", got)
+	}
+}
+
+func TestRunServesQueries(t *testing.T) {
+	sock := shortSocket(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- testDaemon().Run(ctx, sock, nil) }()
+
+	var snap Snapshot
+	var err error
+	for deadline := time.Now().Add(3 * time.Second); time.Now().Before(deadline); time.Sleep(20 * time.Millisecond) {
+		if snap, err = Query(sock); err == nil && len(snap.Sessions) > 0 {
+			break
+		}
+	}
+	if err != nil || len(snap.Sessions) != 1 {
+		t.Fatalf("Query() = %+v, %v; want one session", snap, err)
+	}
+
+	// A second daemon on the same socket must refuse to start.
+	if err := testDaemon().Run(context.Background(), sock, nil); err == nil || !strings.Contains(err.Error(), "already running") {
+		t.Errorf("second Run() error = %v, want already running", err)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Run() = %v", err)
+	}
+	if _, err := os.Stat(sock); !os.IsNotExist(err) {
+		t.Error("socket file should be removed on shutdown")
+	}
+}
+
+func TestQueryWithoutDaemon(t *testing.T) {
+	if _, err := Query(shortSocket(t)); err == nil || !strings.Contains(err.Error(), "not running") {
+		t.Errorf("Query() error = %v, want not running", err)
+	}
+}
+
+func TestRefreshAnnouncesArrivals(t *testing.T) {
+	hooked := make(chan webhook.Event, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var ev webhook.Event
+		_ = json.NewDecoder(r.Body).Decode(&ev)
+		hooked <- ev
+	}))
+	defer srv.Close()
+
+	written := make(chan string, 2)
+	origTTYs, origWrite := clientTTYs, writeTTY
+	t.Cleanup(func() { clientTTYs, writeTTY = origTTYs, origWrite })
+	clientTTYs = func() ([]string, error) { return []string{"/dev/pts/1", "/dev/pts/2"}, nil }
+	writeTTY = func(path, seq string) error {
+		written <- path + " " + seq
+		return nil
+	}
+
+	st := "working"
+	d := New(&tmuxtest.MockClient{Panes: []tmux.Pane{{ID: "%1", SessionName: "0", CurrentCmd: "claude", CurrentPath: "/code/api"}}},
+		func() ([]state.SessionState, error) {
+			return []state.SessionState{{SessionID: "abc", TmuxPane: "%1", State: st}}, nil
+		})
+	d.loadConfig = func() config.Config {
+		return config.Config{UrgentAlert: "bell", Webhooks: []config.Webhook{{URL: srv.URL, Events: []string{webhook.NeedsAttention}}}}
+	}
+	if err := d.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	st = "plan_ready"
+	if err := d.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tty := range []string{"/dev/pts/1", "/dev/pts/2"} {
+		select {
+		case got := <-written:
+			if !strings.HasSuffix(got, " \a") {
+				t.Errorf("wrote %q, want a bell", got)
+			}
+		case <-time.After(3 * time.Second):
+			t.Fatalf("no alert written to %s", tty)
+		}
+	}
+	select {
+	case ev := <-hooked:
+		if ev.Event != webhook.NeedsAttention || ev.State != "plan_ready" || ev.Session.Pane != "%1" {
+			t.Errorf("webhook event = %+v", ev)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("no needs_attention webhook")
+	}
+
+	// Staying plan-ready is not news.
+	if err := d.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case got := <-written:
+		t.Errorf("alerted again without a transition: %q", got)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,316 @@
+package daemon
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/shnupta/herd/internal/config"
+	"github.com/shnupta/herd/internal/history"
+	"github.com/shnupta/herd/internal/organize"
+	"github.com/shnupta/herd/internal/prompt"
+	"github.com/shnupta/herd/internal/tmux"
+)
+
+// PromptRequest asks the daemon to render a configured prompt template and
+// send it to one session.
+type PromptRequest struct {
+	Template string            `json:"template"`
+	Vars     map[string]string `json:"vars,omitempty"`
+
+	// Target selects the session by custom name, session key, project path
+	// or project directory name. It must match exactly one session.
+	Target string `json:"target"`
+}
+
+// PromptResponse reports where a prompt was sent.
+type PromptResponse struct {
+	Key      string `json:"key"`
+	TmuxPane string `json:"tmux_pane"`
+	Prompt   string `json:"prompt"`
+}
+
+// SetRequest asks the daemon to set one session's custom name or group, or
+// to pin or unpin it (see organize.Set).
+type SetRequest struct {
+	Target string `json:"target"` // as in PromptRequest
+	Field  string `json:"field"`  // "name", "group" or "pin"
+	Value  string `json:"value"`  // empty clears a name or group; "on" or "off" for pin
+}
+
+// SetResponse reports which session a setting was applied to.
+type SetResponse struct {
+	Key      string `json:"key"`
+	TmuxPane string `json:"tmux_pane"`
+}
+
+// Handler serves the HTTP API: GET /sessions returns the current Snapshot,
+// POST /prompt sends a rendered template (see PromptRequest) and POST /set
+// names, groups or pins a session (see SetRequest). loadConfig is called
+// per request so template and token edits apply without a restart. Without
+// an API token only requests from the loopback interface are served, as
+// anyone who can reach the API can type into Claude. Requests carrying an
+// Origin header, and POSTs that aren't JSON, are refused whatever the token,
+// so a web page open in the user's browser can't forge one.
+func (d *Daemon) Handler(loadConfig func() config.Config) http.Handler {
+	mux := http.NewServeMux()
+	if d.profiling {
+		mux.Handle("/debug/pprof/", ProfileHandler())
+	}
+	mux.HandleFunc("GET /sessions", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, d.Snapshot())
+	})
+	mux.HandleFunc("POST /prompt", func(w http.ResponseWriter, r *http.Request) {
+		var req PromptRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			httpError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+		resp, status, err := d.sendPrompt(loadConfig(), req)
+		if err != nil {
+			httpError(w, status, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, resp)
+	})
+	mux.HandleFunc("POST /set", func(w http.ResponseWriter, r *http.Request) {
+		var req SetRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			httpError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+		target, status, err := d.findOne(req.Target)
+		if err != nil {
+			httpError(w, status, err.Error())
+			return
+		}
+		if err := organize.Set(target.Key, req.Field, req.Value); err != nil {
+			httpError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, SetResponse{Key: target.Key, TmuxPane: target.TmuxPane})
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Origin") != "" {
+			httpError(w, http.StatusForbidden, "requests from web pages are refused")
+			return
+		}
+		if r.Method == http.MethodPost {
+			if ct, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); ct != "application/json" {
+				httpError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+				return
+			}
+		}
+		if token := loadConfig().APIToken; token != "" {
+			got := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+				httpError(w, http.StatusUnauthorized, "missing or wrong API token")
+				return
+			}
+		} else if host, _, _ := net.SplitHostPort(r.RemoteAddr); !isLoopback(host) {
+			httpError(w, http.StatusForbidden, "set api_token to serve requests from other hosts")
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// sendPrompt renders req's template and types it into the target session.
+// The returned status is the HTTP code to report when err is non-nil.
+func (d *Daemon) sendPrompt(cfg config.Config, req PromptRequest) (PromptResponse, int, error) {
+	tmpl, ok := cfg.PromptTemplates[req.Template]
+	if !ok {
+		return PromptResponse{}, http.StatusNotFound, fmt.Errorf("no prompt template %q", req.Template)
+	}
+	text, err := prompt.Render(tmpl, req.Vars)
+	if err != nil {
+		return PromptResponse{}, http.StatusBadRequest, err
+	}
+	target, status, err := d.findOne(req.Target)
+	if err != nil {
+		return PromptResponse{}, status, err
+	}
+	if err := d.checkPane(target.TmuxPane); err != nil {
+		return PromptResponse{}, http.StatusConflict, err
+	}
+	if err := d.client.SendKeys(target.TmuxPane, text); err != nil {
+		return PromptResponse{}, http.StatusBadGateway, err
+	}
+	_ = history.AppendTo(d.historyPath, history.Event{
+		Kind: history.KindAction, SessionID: target.ID, TmuxPane: target.TmuxPane, Project: target.ProjectPath,
+		Action: history.ActionPrompt, Trigger: "http template " + req.Template, Detail: history.Text(text),
+	})
+	return PromptResponse{Key: target.Key, TmuxPane: target.TmuxPane, Prompt: text}, 0, nil
+}
+
+// findOne returns the one session target selects. The returned status is
+// the HTTP code to report when err is non-nil.
+func (d *Daemon) findOne(target string) (SessionInfo, int, error) {
+	matches := d.Snapshot().Find(target)
+	switch {
+	case len(matches) == 0:
+		return SessionInfo{}, http.StatusNotFound, fmt.Errorf("no session matches %q", target)
+	case len(matches) > 1:
+		return SessionInfo{}, http.StatusConflict, fmt.Errorf("%d sessions match %q", len(matches), target)
+	}
+	return matches[0], 0, nil
+}
+
+// Find returns the sessions whose custom name, key, tmux pane ID, project
+// path or project directory name equals target. A leading ~ in target is
+// expanded.
+func (s Snapshot) Find(target string) []SessionInfo {
+	if target == "" {
+		return nil
+	}
+	path := target
+	if strings.HasPrefix(path, "~") {
+		home, _ := os.UserHomeDir()
+		path = filepath.Join(home, path[1:])
+	}
+	var out []SessionInfo
+	for _, info := range s.Sessions {
+		switch {
+		case info.Name == target,
+			info.Key == target,
+			info.TmuxPane == target,
+			filepath.Clean(info.ProjectPath) == filepath.Clean(path),
+			filepath.Base(info.ProjectPath) == target:
+			out = append(out, info)
+		}
+	}
+	return out
+}
+
+// checkPane reports why pane mustn't be typed into, or nil if it still runs
+// Claude (see tmux.PaneRunsClaude): the snapshot may be seconds old.
+func (d *Daemon) checkPane(pane string) error {
+	cmd, ok, err := tmux.PaneRunsClaude(d.client, pane)
+	switch {
+	case err != nil:
+		return fmt.Errorf("pane %s is gone, nothing sent", pane)
+	case !ok:
+		return fmt.Errorf("pane %s is running %s, not Claude, nothing sent", pane, cmd)
+	}
+	return nil
+}
+
+// isLoopback reports whether host, an address's host part, is only
+// reachable from this machine.
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// ListenHTTP serves Handler on addr until ctx is cancelled. It refuses an
+// addr other hosts can reach unless an API token is set.
+func (d *Daemon) ListenHTTP(ctx context.Context, addr string, loadConfig func() config.Config) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	if !isLoopback(host) && loadConfig().APIToken == "" {
+		return fmt.Errorf("not serving the HTTP API on %s without api_token set; use 127.0.0.1 or set a token", addr)
+	}
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           d.Handler(loadConfig),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// QueryHTTP fetches the current snapshot from the HTTP API of a daemon at
+// baseURL, e.g. http://lead.local:7777, sending token when it is set.
+func QueryHTTP(baseURL, token string) (Snapshot, error) {
+	var snap Snapshot
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(baseURL, "/")+"/sessions", nil)
+	if err != nil {
+		return snap, err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return snap, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var body struct {
+			Error string `json:"error"`
+		}
+		msg := resp.Status
+		if json.NewDecoder(resp.Body).Decode(&body) == nil && body.Error != "" {
+			msg += ": " + body.Error
+		}
+		return snap, fmt.Errorf("%s: %s", baseURL, msg)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&snap); err != nil {
+		return snap, fmt.Errorf("reading %s snapshot: %w", baseURL, err)
+	}
+	return snap, nil
+}
+
+// httpClient is what QueryHTTP asks with; a hub that has gone away must
+// not hold up the caller's refresh for long.
+var httpClient = &http.Client{Timeout: 5 * time.Second}
+
+// ProfileHandler serves Go's runtime profiles under /debug/pprof/, as
+// net/http/pprof does, for diagnosing a slow daemon or TUI.
+func ProfileHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	return mux
+}
+
+// ListenProfiles serves ProfileHandler on addr, for HERD_PPROF. The profiles
+// are served without a token, so addr must be a loopback address.
+func ListenProfiles(addr string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	if !isLoopback(host) {
+		return fmt.Errorf("not serving profiles on %s, which other hosts can reach; use 127.0.0.1", addr)
+	}
+	srv := &http.Server{Addr: addr, Handler: ProfileHandler(), ReadHeaderTimeout: 5 * time.Second}
+	return srv.ListenAndServe()
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func httpError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
@@ -0,0 +1,277 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shnupta/herd/internal/config"
+	"github.com/shnupta/herd/internal/history"
+	"github.com/shnupta/herd/internal/state"
+	"github.com/shnupta/herd/internal/tmux"
+	"github.com/shnupta/herd/internal/tmux/tmuxtest"
+)
+
+func apiDaemon(t *testing.T) (*Daemon, *tmuxtest.MockClient) {
+	t.Helper()
+	client := &tmuxtest.MockClient{Panes: []tmux.Pane{
+		{ID: "%1", SessionName: "0", CurrentCmd: "claude", CurrentPath: "/code/api"},
+		{ID: "%2", SessionName: "0", CurrentCmd: "claude", CurrentPath: "/code/web"},
+		{ID: "%3", SessionName: "0", CurrentCmd: "claude", CurrentPath: "/other/web"},
+	}}
+	d := New(client, func() ([]state.SessionState, error) { return nil, nil })
+	d.historyPath = filepath.Join(t.TempDir(), "history.jsonl")
+	if err := d.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	return d, client
+}
+
+func apiConfig(token string) func() config.Config {
+	return func() config.Config {
+		return config.Config{
+			APIToken:        token,
+			PromptTemplates: map[string]string{"fix-build": "Fix the failing build on {{.branch}}"},
+		}
+	}
+}
+
+func post(t *testing.T, h http.Handler, body, token string) *httptest.ResponseRecorder {
+	t.Helper()
+	return postTo(t, h, "/prompt", body, token)
+}
+
+func postTo(t *testing.T, h http.Handler, path, body, token string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.RemoteAddr = "127.0.0.1:50000"
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestPromptSendsRenderedTemplate(t *testing.T) {
+	d, client := apiDaemon(t)
+	h := d.Handler(apiConfig(""))
+
+	rec := post(t, h, `{"template":"fix-build","vars":{"branch":"feat/x"},"target":"api"}`, "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body)
+	}
+	var resp PromptResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.TmuxPane != "%1" || resp.Prompt != "Fix the failing build on feat/x" {
+		t.Errorf("response = %+v", resp)
+	}
+	if len(client.SendKeysCalls) != 1 || client.SendKeysCalls[0] != "%1:Fix the failing build on feat/x" {
+		t.Errorf("SendKeysCalls = %v", client.SendKeysCalls)
+	}
+	events, err := history.ReadSince(d.historyPath, time.Time{})
+	if err != nil || len(events) != 1 || events[0].Action != history.ActionPrompt || events[0].Trigger != "http template fix-build" || events[0].TmuxPane != "%1" {
+		t.Errorf("history = %+v, %v; want the prompt recorded", events, err)
+	}
+}
+
+func TestPromptErrors(t *testing.T) {
+	d, client := apiDaemon(t)
+	h := d.Handler(apiConfig(""))
+
+	tests := []struct {
+		name, body string
+		want       int
+	}{
+		{"unknown template", `{"template":"nope","target":"api"}`, http.StatusNotFound},
+		{"missing var", `{"template":"fix-build","target":"api"}`, http.StatusBadRequest},
+		{"no session", `{"template":"fix-build","vars":{"branch":"x"},"target":"ghost"}`, http.StatusNotFound},
+		{"ambiguous", `{"template":"fix-build","vars":{"branch":"x"},"target":"web"}`, http.StatusConflict},
+		{"bad json", `{`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := post(t, h, tt.body, ""); rec.Code != tt.want {
+				t.Errorf("status = %d, want %d (body %s)", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+	if len(client.SendKeysCalls) != 0 {
+		t.Errorf("SendKeysCalls = %v, want nothing sent", client.SendKeysCalls)
+	}
+
+	// A full project path disambiguates.
+	if rec := post(t, h, `{"template":"fix-build","vars":{"branch":"x"},"target":"/other/web"}`, ""); rec.Code != http.StatusOK {
+		t.Errorf("status = %d for full path target", rec.Code)
+	}
+}
+
+func TestSetErrors(t *testing.T) {
+	d, _ := apiDaemon(t)
+	h := d.Handler(apiConfig(""))
+
+	tests := []struct {
+		name, body string
+		want       int
+	}{
+		{"unknown field", `{"target":"%1","field":"colour","value":"red"}`, http.StatusBadRequest},
+		{"bad pin", `{"target":"api","field":"pin","value":"maybe"}`, http.StatusBadRequest},
+		{"no session", `{"target":"ghost","field":"name","value":"x"}`, http.StatusNotFound},
+		{"ambiguous", `{"target":"web","field":"name","value":"x"}`, http.StatusConflict},
+		{"bad json", `{`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := postTo(t, h, "/set", tt.body, ""); rec.Code != tt.want {
+				t.Errorf("status = %d, want %d (body %s)", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
+
+func TestAPIToken(t *testing.T) {
+	d, _ := apiDaemon(t)
+	h := d.Handler(apiConfig("s3cret"))
+	body := `{"template":"fix-build","vars":{"branch":"x"},"target":"api"}`
+
+	if rec := post(t, h, body, ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("no token: status = %d, want 401", rec.Code)
+	}
+	if rec := post(t, h, body, "wrong"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("wrong token: status = %d, want 401", rec.Code)
+	}
+	if rec := post(t, h, body, "s3cret"); rec.Code != http.StatusOK {
+		t.Errorf("right token: status = %d, want 200", rec.Code)
+	}
+}
+
+func TestGetSessions(t *testing.T) {
+	d, _ := apiDaemon(t)
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/sessions", nil)
+	req.RemoteAddr = "127.0.0.1:50000"
+	d.Handler(apiConfig("")).ServeHTTP(rec, req)
+	var snap Snapshot
+	if err := json.NewDecoder(rec.Body).Decode(&snap); err != nil || len(snap.Sessions) != 3 {
+		t.Errorf("GET /sessions = %+v, %v; want three sessions", snap, err)
+	}
+}
+
+func TestProfilingEndpoints(t *testing.T) {
+	d, _ := apiDaemon(t)
+	get := func(token string) int {
+		req := httptest.NewRequest(http.MethodGet, "/debug/pprof/", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		d.Handler(apiConfig("s3cret")).ServeHTTP(rec, req)
+		return rec.Code
+	}
+	if code := get("s3cret"); code != http.StatusNotFound {
+		t.Errorf("pprof served without --pprof: %d", code)
+	}
+	d.SetProfiling(true)
+	if code := get("s3cret"); code != http.StatusOK {
+		t.Errorf("GET /debug/pprof/ = %d, want 200", code)
+	}
+	if code := get(""); code != http.StatusUnauthorized {
+		t.Errorf("pprof without the API token = %d, want 401", code)
+	}
+}
+
+func TestListenProfilesOnlyOnLoopback(t *testing.T) {
+	for _, addr := range []string{":6060", "0.0.0.0:6060", "192.0.2.1:6060"} {
+		if err := ListenProfiles(addr); err == nil || !strings.Contains(err.Error(), "other hosts") {
+			t.Errorf("ListenProfiles(%s) = %v, want it refused", addr, err)
+		}
+	}
+}
+
+func TestNoTokenServesOnlyLoopback(t *testing.T) {
+	d, client := apiDaemon(t)
+	h := d.Handler(apiConfig(""))
+	req := httptest.NewRequest(http.MethodPost, "/prompt", strings.NewReader(`{"template":"fix-build","vars":{"branch":"x"},"target":"api"}`))
+	req.RemoteAddr = "192.0.2.7:50000"
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden || len(client.SendKeysCalls) != 0 {
+		t.Errorf("remote request without a token: status = %d, sent %q", rec.Code, client.SendKeysCalls)
+	}
+
+	for _, addr := range []string{":7777", "0.0.0.0:7777", "192.0.2.1:7777"} {
+		if err := d.ListenHTTP(context.Background(), addr, apiConfig("")); err == nil || !strings.Contains(err.Error(), "api_token") {
+			t.Errorf("ListenHTTP(%s) without a token = %v, want it refused", addr, err)
+		}
+	}
+}
+
+func TestRefusesBrowserRequests(t *testing.T) {
+	d, client := apiDaemon(t)
+	h := d.Handler(apiConfig(""))
+	body := `{"template":"fix-build","vars":{"branch":"x"},"target":"api"}`
+	tests := []struct {
+		name, contentType, origin string
+		want                      int
+	}{
+		{"form post", "application/x-www-form-urlencoded", "", http.StatusUnsupportedMediaType},
+		{"text post", "text/plain", "", http.StatusUnsupportedMediaType},
+		{"cross-origin json", "application/json", "https://evil.example", http.StatusForbidden},
+		{"null origin", "application/json; charset=utf-8", "null", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/prompt", strings.NewReader(body))
+			req.RemoteAddr = "127.0.0.1:50000"
+			req.Header.Set("Content-Type", tt.contentType)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+	if len(client.SendKeysCalls) != 0 {
+		t.Errorf("SendKeysCalls = %v, want nothing sent", client.SendKeysCalls)
+	}
+}
+
+func TestPromptRefusesPaneWithoutClaude(t *testing.T) {
+	d, client := apiDaemon(t)
+	orig := tmux.ClaudeUnder
+	t.Cleanup(func() { tmux.ClaudeUnder = orig })
+	tmux.ClaudeUnder = func(int) bool { return false }
+	client.Panes[0].CurrentCmd = "zsh"
+	client.Panes[0].PID = 42
+
+	rec := post(t, d.Handler(apiConfig("")), `{"template":"fix-build","vars":{"branch":"x"},"target":"api"}`, "")
+	if rec.Code != http.StatusConflict || !strings.Contains(rec.Body.String(), "running zsh") || len(client.SendKeysCalls) != 0 {
+		t.Errorf("status = %d, body %s, sent %q", rec.Code, rec.Body, client.SendKeysCalls)
+	}
+}
+
+func TestQueryHTTP(t *testing.T) {
+	d, _ := apiDaemon(t)
+	srv := httptest.NewServer(d.Handler(apiConfig("s3cret")))
+	defer srv.Close()
+
+	snap, err := QueryHTTP(srv.URL+"/", "s3cret")
+	if err != nil || len(snap.Sessions) != 3 {
+		t.Fatalf("QueryHTTP = %+v, %v; want three sessions", snap, err)
+	}
+	if _, err := QueryHTTP(srv.URL, "wrong"); err == nil || !strings.Contains(err.Error(), "wrong API token") {
+		t.Errorf("wrong token: err = %v, want the API's error", err)
+	}
+}
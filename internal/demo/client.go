@@ -0,0 +1,184 @@
+package demo
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/shnupta/herd/internal/tmux"
+)
+
+// errNoPane is what tmux calls on a pane that isn't running fail with.
+var errNoPane = errors.New("demo: no such pane")
+
+func (d *Demo) ListPanes() ([]tmux.Pane, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	panes := make([]tmux.Pane, len(d.sessions))
+	for i, s := range d.sessions {
+		panes[i] = s.pane
+	}
+	return panes, nil
+}
+
+func (d *Demo) CapturePane(paneID string, scrollbackLines int) (string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	s := d.find(paneID)
+	if s == nil {
+		return "", errNoPane
+	}
+	return s.screen(d.home, s.pane.Width), nil
+}
+
+func (d *Demo) PaneCommand(paneID string) (cmd string, pid int, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if s := d.find(paneID); s != nil {
+		return s.pane.CurrentCmd, s.pane.PID, nil
+	}
+	return "", 0, errNoPane
+}
+
+func (d *Demo) CursorPosition(paneID string) (x, y int, err error) { return 0, 0, nil }
+func (d *Demo) AlternateOn(paneID string) (bool, error)            { return false, nil }
+func (d *Demo) PaneShared(paneID string) (bool, error)             { return false, nil }
+func (d *Demo) CurrentSession() (string, error)                    { return "demo", nil }
+func (d *Demo) HerdWindowActive() (bool, error)                    { return true, nil }
+func (d *Demo) DisplayMessage(text string) error                   { return nil }
+
+func (d *Demo) PaneWidth(paneID string) (int, error) {
+	w, _, err := d.size(paneID)
+	return w, err
+}
+
+func (d *Demo) PaneHeight(paneID string) (int, error) {
+	_, h, err := d.size(paneID)
+	return h, err
+}
+
+func (d *Demo) PaneInfo(paneID string) (cursorX, cursorY, paneHeight int, err error) {
+	_, h, err := d.size(paneID)
+	return 0, 0, h, err
+}
+
+func (d *Demo) size(paneID string) (width, height int, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if s := d.find(paneID); s != nil {
+		return s.pane.Width, s.pane.Height, nil
+	}
+	return 0, 0, errNoPane
+}
+
+func (d *Demo) ClientWidth() (int, error)  { return d.width, nil }
+func (d *Demo) ClientHeight() (int, error) { return d.height, nil }
+
+// SendLiteral types text into the pane's prompt.
+func (d *Demo) SendLiteral(paneID, text string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	s := d.find(paneID)
+	if s == nil {
+		return errNoPane
+	}
+	s.input += text
+	return nil
+}
+
+// SendKeyName presses a key in the pane: Enter submits what was typed, or
+// answers a plan or permission prompt with its default.
+func (d *Demo) SendKeyName(paneID, key string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	s := d.find(paneID)
+	if s == nil {
+		return errNoPane
+	}
+	switch key {
+	case "Enter":
+		if text := s.input; text != "" {
+			s.input = ""
+			d.submit(s, text)
+		} else if st := s.proj.steps[s.at].state; st == "plan_ready" || st == "notifying" {
+			d.enter(s, (s.at+1)%len(s.proj.steps), time.Now())
+		}
+	case "BSpace":
+		if r := []rune(s.input); len(r) > 0 {
+			s.input = string(r[:len(r)-1])
+		}
+	case "C-u", "Escape":
+		s.input = ""
+	}
+	return nil
+}
+
+// PasteText pastes text into the pane's prompt, as SendLiteral types it.
+func (d *Demo) PasteText(paneID, text string) error {
+	return d.SendLiteral(paneID, text)
+}
+
+// SendKeys types text and submits it.
+func (d *Demo) SendKeys(paneID, text string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	s := d.find(paneID)
+	if s == nil {
+		return errNoPane
+	}
+	s.input = ""
+	d.submit(s, strings.TrimSpace(text))
+	return nil
+}
+
+func (d *Demo) ResizePane(paneID string, width int) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if s := d.find(paneID); s != nil {
+		s.pane.Width = width
+		return nil
+	}
+	return errNoPane
+}
+
+func (d *Demo) ResizeWindow(paneID string, width, height int) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if s := d.find(paneID); s != nil {
+		s.pane.Width, s.pane.Height = width, height
+		return nil
+	}
+	return errNoPane
+}
+
+func (d *Demo) ResizePaneAuto(paneID string) error { return nil }
+func (d *Demo) SwitchToPane(paneID string) error   { return nil }
+
+// KillPane ends the pane's session.
+func (d *Demo) KillPane(paneID string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for i, s := range d.sessions {
+		if s.pane.ID == paneID {
+			d.sessions = append(d.sessions[:i], d.sessions[i+1:]...)
+			return d.store.Remove(s.id)
+		}
+	}
+	return errNoPane
+}
+
+// NewWindow starts a fresh session in path; the command is ignored and the
+// session waits for a prompt.
+func (d *Demo) NewWindow(tmuxSession, path, cmd string) (string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	s := d.add(path, project{name: filepath.Base(path), model: "claude-sonnet-4-5", steps: newSessionSteps})
+	d.enter(s, 0, time.Now())
+	return s.pane.ID, nil
+}
+
+// SplitWindow is NewWindow: panes have no layout in a demo.
+func (d *Demo) SplitWindow(targetPane, path, cmd string) (string, error) {
+	return d.NewWindow("", path, cmd)
+}
@@ -0,0 +1,269 @@
+// Package demo drives herd's TUI with made-up sessions that cycle through
+// Claude's states, for screenshots, trying herd out and working on the UI
+// without tmux or Claude. A Demo stands in for tmux and feeds state changes
+// through a state.FakeWatcher, as the TUI's tests do.
+package demo
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shnupta/herd/internal/history"
+	"github.com/shnupta/herd/internal/state"
+	"github.com/shnupta/herd/internal/tmux"
+)
+
+// seedScale stretches the steps replayed into the history log, so the
+// sidebar timelines show an hour of varied work rather than a blur.
+const seedScale = 40
+
+// maxLines caps how much of a session's output is kept.
+const maxLines = 300
+
+// agent is a made-up Claude session in a made-up pane.
+type agent struct {
+	pane  tmux.Pane
+	id    string
+	proj  project
+	at    int // index of the current step
+	since time.Time
+	lines []string
+	input string // typed but not yet submitted
+}
+
+// Demo is a fake tmux server running made-up sessions. It implements
+// tmux.ClientIface and is safe for concurrent use.
+type Demo struct {
+	home    string
+	store   *state.Store // home's state directory, read when the TUI rediscovers sessions
+	watcher *state.FakeWatcher
+
+	mu       sync.Mutex
+	sessions []*agent
+	nextPane int
+	width    int
+	height   int
+	changed  []state.SessionState // state changes not yet sent to watcher
+}
+
+var _ tmux.ClientIface = (*Demo)(nil)
+
+// New sets up the demo's projects under home/code, as git repositories on
+// their branches where git is available, and writes the last hour of their
+// made-up states to home's history log.
+func New(home string) (*Demo, error) {
+	d := &Demo{
+		home:    home,
+		store:   state.NewStore(filepath.Join(home, ".herd", "sessions")),
+		watcher: state.NewFakeWatcher(),
+		width:   100, height: 40, nextPane: 1,
+	}
+	now := time.Now()
+	for i, p := range projects {
+		dir := filepath.Join(home, "code", p.name)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, err
+		}
+		initRepo(dir, p.branch)
+		s := d.add(dir, p)
+		if err := d.seed(s, i, now); err != nil {
+			return nil, err
+		}
+		if err := d.store.Write(d.stateOf(s, now)); err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+// initRepo makes dir a git repository with one commit on branch, so the
+// sidebar has branches to show; without git the demo runs without them.
+func initRepo(dir, branch string) {
+	git := func(args ...string) error {
+		cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=herd", "-c", "user.email=demo@herd.invalid"}, args...)...)
+		return cmd.Run()
+	}
+	if git("init", "-q") != nil {
+		return
+	}
+	_ = git("symbolic-ref", "HEAD", "refs/heads/"+branch)
+	_ = git("commit", "-q", "--allow-empty", "-m", "Start")
+}
+
+// Watcher returns the watcher the demo's state changes arrive on.
+func (d *Demo) Watcher() *state.FakeWatcher { return d.watcher }
+
+// Run moves the sessions along their steps until ctx is done, then closes
+// the watcher.
+func (d *Demo) Run(ctx context.Context) {
+	defer d.watcher.Close()
+	tick := time.NewTicker(time.Second)
+	defer tick.Stop()
+	for {
+		d.flush(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-tick.C:
+			d.advance(now)
+		}
+	}
+}
+
+// flush sends the pending state changes to the watcher.
+func (d *Demo) flush(ctx context.Context) {
+	d.mu.Lock()
+	changed := d.changed
+	d.changed = nil
+	d.mu.Unlock()
+	for _, ss := range changed {
+		if ctx.Err() != nil {
+			return
+		}
+		d.watcher.Send(ss)
+	}
+}
+
+// advance moves each session whose step has run its course on to the next.
+func (d *Demo) advance(now time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, s := range d.sessions {
+		if now.Sub(s.since) >= s.proj.steps[s.at].dur {
+			d.enter(s, (s.at+1)%len(s.proj.steps), now)
+		}
+	}
+}
+
+// enter moves s to step i, printing what the step says, writing its state
+// as a hook would and queueing it for the watcher. d.mu must be held.
+func (d *Demo) enter(s *agent, i int, now time.Time) {
+	s.at, s.since = i, now
+	s.say(s.proj.steps[i].say)
+	ss := d.stateOf(s, now)
+	_ = d.store.Write(ss)
+	d.changed = append(d.changed, ss)
+	_ = history.AppendTo(d.historyPath(), history.Event{Time: now, Kind: history.KindState, SessionID: s.id, TmuxPane: s.pane.ID, Project: s.pane.CurrentPath, State: ss.State})
+}
+
+// stateOf is the hook state s reports at now.
+func (d *Demo) stateOf(s *agent, now time.Time) state.SessionState {
+	st := s.proj.steps[s.at]
+	return state.SessionState{
+		SessionID:      s.id,
+		TmuxPane:       s.pane.ID,
+		State:          st.state,
+		CurrentTool:    st.tool,
+		ProjectPath:    s.pane.CurrentPath,
+		UpdatedAt:      now,
+		PermissionMode: "default",
+		Model:          s.proj.model,
+		ContextTokens:  s.proj.context,
+	}
+}
+
+func (d *Demo) historyPath() string { return filepath.Join(d.home, ".herd", "history.jsonl") }
+
+// seed replays s's steps, slowed by seedScale and starting at the i'th,
+// into the history log over the hour before now, leaving s in the step the
+// replay reaches.
+func (d *Demo) seed(s *agent, i int, now time.Time) error {
+	at := i % len(s.proj.steps)
+	for t := now.Add(-time.Hour); ; at = (at + 1) % len(s.proj.steps) {
+		st := s.proj.steps[at]
+		ev := history.Event{Time: t, Kind: history.KindState, SessionID: s.id, TmuxPane: s.pane.ID, Project: s.pane.CurrentPath, State: st.state}
+		if err := history.AppendTo(d.historyPath(), ev); err != nil {
+			return err
+		}
+		if t = t.Add(st.dur * seedScale); !t.Before(now) {
+			break
+		}
+	}
+	s.at, s.since = at, now
+	s.say(s.proj.steps[at].say)
+	return nil
+}
+
+// add starts a session running p in dir. d.mu must be held or d not yet
+// shared.
+func (d *Demo) add(dir string, p project) *agent {
+	id := fmt.Sprintf("%%%d", d.nextPane)
+	d.nextPane++
+	s := &agent{
+		pane: tmux.Pane{
+			ID: id, SessionID: "$1", SessionName: "demo", WindowIndex: len(d.sessions) + 1,
+			CurrentCmd: "claude", CurrentPath: dir, Width: d.width, Height: d.height,
+		},
+		id:   fmt.Sprintf("demo-%s-%d", p.name, d.nextPane-1),
+		proj: p,
+	}
+	d.sessions = append(d.sessions, s)
+	return s
+}
+
+// find returns the session in pane, or nil.
+func (d *Demo) find(pane string) *agent {
+	for _, s := range d.sessions {
+		if s.pane.ID == pane {
+			return s
+		}
+	}
+	return nil
+}
+
+// submit answers a prompt typed into s: it is echoed and s gets to work.
+// d.mu must be held.
+func (d *Demo) submit(s *agent, text string) {
+	s.say("> " + text)
+	for n := 1; n <= len(s.proj.steps); n++ {
+		if i := (s.at + n) % len(s.proj.steps); s.proj.steps[i].state == "working" {
+			d.enter(s, i, time.Now())
+			return
+		}
+	}
+}
+
+// say appends text to s's output.
+func (s *agent) say(text string) {
+	s.lines = append(s.lines, "")
+	s.lines = append(s.lines, strings.Split(text, "\n")...)
+	if n := len(s.lines); n > maxLines {
+		s.lines = s.lines[n-maxLines:]
+	}
+}
+
+// screen draws s the way Claude's terminal UI looks, width columns wide.
+func (s *agent) screen(home string, width int) string {
+	width = max(20, min(width, 100))
+	box := func(lines ...string) []string {
+		inner := width - 4
+		out := []string{"╭" + strings.Repeat("─", width-2) + "╮"}
+		for _, l := range lines {
+			r := []rune(l)
+			if len(r) > inner {
+				r = r[:inner]
+			}
+			out = append(out, "│ "+string(r)+strings.Repeat(" ", inner-len(r))+" │")
+		}
+		return append(out, "╰"+strings.Repeat("─", width-2)+"╯")
+	}
+	cwd := s.pane.CurrentPath
+	if rel, err := filepath.Rel(home, cwd); err == nil && !strings.HasPrefix(rel, "..") {
+		cwd = "~/" + rel
+	}
+	out := box("✻ Welcome to Claude Code!", "", "  cwd: "+cwd)
+	out = append(out, s.lines...)
+	out = append(out, "")
+	if s.proj.steps[s.at].state == "working" {
+		out = append(out, "✻ Working… (esc to interrupt)", "")
+	}
+	out = append(out, box("> "+s.input)...)
+	out = append(out, "  ? for shortcuts")
+	return strings.Join(out, "\n")
+}
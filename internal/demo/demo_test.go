@@ -0,0 +1,78 @@
+package demo
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shnupta/herd/internal/history"
+	"github.com/shnupta/herd/internal/session"
+)
+
+func TestDemoSessions(t *testing.T) {
+	home := t.TempDir()
+	d, err := New(home)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sessions, err := session.Discover(d)
+	if err != nil || len(sessions) != len(projects) {
+		t.Fatalf("Discover() = %d sessions, %v; want %d", len(sessions), err, len(projects))
+	}
+	screen, err := d.CapturePane(sessions[0].TmuxPane, 0)
+	if err != nil || !strings.Contains(screen, "cwd: ~/code/api-server") {
+		t.Errorf("CapturePane() = %q, %v", screen, err)
+	}
+
+	events, err := history.ReadSince(d.historyPath(), time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	timelines := history.Timelines(events, time.Now().Add(-time.Hour), time.Now(), 10)
+	if len(timelines) != len(projects) {
+		t.Errorf("seeded history has %d timelines, want %d", len(timelines), len(projects))
+	}
+}
+
+func TestDemoAdvancesAndAnswers(t *testing.T) {
+	d, err := New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := d.sessions[0]
+	d.mu.Lock()
+	d.enter(s, 3, time.Now()) // api-server waiting for an answer
+	d.changed = nil
+	d.mu.Unlock()
+
+	if err := d.SendKeys(s.pane.ID, "yes, wire it in"); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.proj.steps[s.at].state; got != "working" {
+		t.Errorf("after a prompt the session is %q, want working", got)
+	}
+	if len(d.changed) != 1 || d.changed[0].State != "working" || d.changed[0].SessionID != s.id {
+		t.Errorf("queued states = %+v", d.changed)
+	}
+	screen, _ := d.CapturePane(s.pane.ID, 0)
+	if !strings.Contains(screen, "> yes, wire it in") {
+		t.Errorf("the prompt should be echoed:\n%s", screen)
+	}
+
+	at := s.at
+	d.advance(s.since.Add(s.proj.steps[at].dur))
+	if s.at != (at+1)%len(s.proj.steps) {
+		t.Errorf("advance left the session at step %d, want %d", s.at, at+1)
+	}
+
+	if err := d.KillPane(s.pane.ID); err != nil {
+		t.Fatal(err)
+	}
+	if panes, _ := d.ListPanes(); len(panes) != len(projects)-1 {
+		t.Errorf("%d panes after a kill, want %d", len(panes), len(projects)-1)
+	}
+	if err := d.SendKeys(s.pane.ID, "hello"); err == nil {
+		t.Error("sending to a killed pane should fail")
+	}
+}
@@ -0,0 +1,75 @@
+package demo
+
+import "time"
+
+// step is one stretch of a made-up session: the state it reports, the tool
+// it is using and what it prints to its pane on entering the step.
+type step struct {
+	state string
+	tool  string
+	dur   time.Duration
+	say   string
+}
+
+// project is a made-up session: where it runs and the steps it cycles
+// through.
+type project struct {
+	name    string // directory under ~/code
+	branch  string
+	model   string
+	context int // tokens in its latest turn
+	steps   []step
+}
+
+// projects are the sessions a demo starts with.
+var projects = []project{
+	{
+		name: "api-server", branch: "feat/rate-limits", model: "claude-sonnet-4-5", context: 48_000,
+		steps: []step{
+			{"working", "Read", 4 * time.Second, "● Read(internal/limit/bucket.go)\n  ⎿  Read 142 lines"},
+			{"working", "Edit", 5 * time.Second, "● Update(internal/limit/bucket.go)\n  ⎿  Updated internal/limit/bucket.go with 12 additions and 3 removals"},
+			{"working", "Bash", 6 * time.Second, "● Bash(go test ./internal/limit/...)\n  ⎿  ok  	api/internal/limit	0.412s"},
+			{"waiting", "", 12 * time.Second, "● The token bucket now refills per API key. Do you want me to wire it\n  into the router middleware as well?"},
+		},
+	},
+	{
+		name: "web-app", branch: "settings-tabs", model: "claude-sonnet-4-5", context: 91_000,
+		steps: []step{
+			{"plan_ready", "", 14 * time.Second, "Here is Claude's plan:\n\n  1. Split the settings page into tabs\n  2. Move billing to its own route\n  3. Add tests for the new tabs\n\n Would you like to proceed?\n ❯ 1. Yes, and auto-accept edits\n   2. Yes, and manually approve edits\n   3. No, keep planning"},
+			{"working", "Write", 6 * time.Second, "● Write(src/settings/Tabs.tsx)\n  ⎿  Wrote 88 lines to src/settings/Tabs.tsx"},
+			{"working", "Bash", 7 * time.Second, "● Bash(npm test -- settings)\n  ⎿  Tests: 14 passed, 14 total"},
+			{"idle", "", 15 * time.Second, "● Settings now uses tabs and billing lives at /settings/billing."},
+		},
+	},
+	{
+		name: "payments", branch: "stripe-webhooks", model: "claude-opus-4-1", context: 132_000,
+		steps: []step{
+			{"working", "Grep", 5 * time.Second, "● Search(pattern: \"webhook\", path: \"internal\")\n  ⎿  Found 7 files"},
+			{"notifying", "", 10 * time.Second, "● Claude needs your permission to use Bash\n\n  stripe listen --forward-to localhost:8080/webhooks\n\n ❯ 1. Yes\n   2. No, and tell Claude what to do differently"},
+			{"working", "Bash", 8 * time.Second, "● Bash(stripe listen --forward-to localhost:8080/webhooks)\n  ⎿  Ready! Your webhook signing secret is whsec_…"},
+			{"waiting", "", 10 * time.Second, "● Webhooks arrive and are verified. Shall I add idempotency keys next?"},
+		},
+	},
+	{
+		name: "docs-site", branch: "main", model: "claude-sonnet-4-5", context: 12_000,
+		steps: []step{
+			{"idle", "", 25 * time.Second, "● The getting-started guide is updated for the new CLI flags."},
+			{"working", "Edit", 5 * time.Second, "● Update(content/guide/install.md)\n  ⎿  Updated content/guide/install.md with 4 additions"},
+		},
+	},
+	{
+		name: "mobile", branch: "offline-sync", model: "claude-sonnet-4-5", context: 67_000,
+		steps: []step{
+			{"working", "Task", 12 * time.Second, "● Task(Find every place the app writes to the local database)\n  ⎿  Done (23 tool uses · 41.2k tokens · 1m 12s)"},
+			{"working", "MultiEdit", 8 * time.Second, "● Update(lib/sync/queue.dart)\n  ⎿  Updated lib/sync/queue.dart with 31 additions and 9 removals"},
+			{"waiting", "", 9 * time.Second, "● Offline edits now queue and replay on reconnect. Should conflicts\n  prefer the server copy or the newest edit?"},
+		},
+	},
+}
+
+// newSessionSteps is what a session started from the TUI does: nothing
+// until it is given a prompt.
+var newSessionSteps = []step{
+	{"idle", "", time.Hour, "✻ Welcome to Claude Code!"},
+	{"working", "Read", 5 * time.Second, "● Read(README.md)\n  ⎿  Read 60 lines"},
+}
@@ -0,0 +1,58 @@
+package diff
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+var imageExts = map[string]bool{
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true,
+	".webp": true, ".bmp": true, ".ico": true, ".tif": true, ".tiff": true,
+}
+
+// IsImage reports whether the file looks like an image from its extension.
+func (f *FileDiff) IsImage() bool {
+	return imageExts[strings.ToLower(filepath.Ext(f.GetFilePath()))]
+}
+
+// BinarySizes returns the size of a binary file at HEAD and in the working
+// tree of the repo at dir. A side where the file doesn't exist (added or
+// deleted) is -1.
+func BinarySizes(dir string, f *FileDiff) (oldSize, newSize int64) {
+	oldSize, newSize = -1, -1
+	cmd := exec.Command("git", "cat-file", "-s", "HEAD:"+f.OldPath)
+	cmd.Dir = dir
+	if out, err := cmd.Output(); err == nil {
+		if n, err := strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64); err == nil {
+			oldSize = n
+		}
+	}
+	if info, err := os.Stat(filepath.Join(dir, f.NewPath)); err == nil {
+		newSize = info.Size()
+	}
+	return oldSize, newSize
+}
+
+// ExtractOld writes the HEAD version of path in the repo at dir to a
+// temporary file with the same extension and returns its path, so it can be
+// opened next to the working copy.
+func ExtractOld(dir, path string) (string, error) {
+	cmd := exec.Command("git", "show", "HEAD:"+path)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	tmp, err := os.CreateTemp("", "herd-head-*"+filepath.Ext(path))
+	if err != nil {
+		return "", err
+	}
+	defer tmp.Close()
+	if _, err := tmp.Write(out); err != nil {
+		return "", err
+	}
+	return tmp.Name(), nil
+}
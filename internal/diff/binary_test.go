@@ -0,0 +1,67 @@
+package diff
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func gitRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init"},
+		{"config", "user.email", "test@test.com"},
+		{"config", "user.name", "Test"},
+	} {
+		if out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	return dir
+}
+
+func commitAll(t *testing.T, dir string) {
+	t.Helper()
+	for _, args := range [][]string{{"add", "-A"}, {"commit", "-m", "c"}} {
+		if out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+}
+
+func TestBinarySizesAndExtractOld(t *testing.T) {
+	dir := gitRepo(t)
+	img := filepath.Join(dir, "logo.png")
+	if err := os.WriteFile(img, []byte("\x89PNG old"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	commitAll(t, dir)
+	if err := os.WriteFile(img, []byte("\x89PNG new and longer"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	f := &FileDiff{OldPath: "logo.png", NewPath: "logo.png", Binary: true}
+	if !f.IsImage() {
+		t.Error("logo.png should be an image")
+	}
+	oldSize, newSize := BinarySizes(dir, f)
+	if oldSize != 8 || newSize != 19 {
+		t.Errorf("sizes = %d → %d, want 8 → 19", oldSize, newSize)
+	}
+
+	path, err := ExtractOld(dir, "logo.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(path)
+	if got, _ := os.ReadFile(path); string(got) != "\x89PNG old" || filepath.Ext(path) != ".png" {
+		t.Errorf("extracted %q to %s", got, path)
+	}
+
+	added := &FileDiff{OldPath: "new.bin", NewPath: "new.bin", Binary: true}
+	if oldSize, _ := BinarySizes(dir, added); oldSize != -1 {
+		t.Errorf("added file old size = %d, want -1", oldSize)
+	}
+}
@@ -0,0 +1,63 @@
+package diff
+
+import (
+	"path"
+	"strings"
+)
+
+// Ignore drops files matching any of patterns from the diff and returns how
+// many were dropped. See config.ReviewIgnore for the pattern forms.
+func (d *Diff) Ignore(patterns []string) int {
+	if len(patterns) == 0 {
+		return 0
+	}
+	kept := d.Files[:0]
+	dropped := 0
+	for _, f := range d.Files {
+		if matchesAny(patterns, f.GetFilePath()) {
+			dropped++
+			continue
+		}
+		kept = append(kept, f)
+	}
+	d.Files = kept
+	return dropped
+}
+
+func matchesAny(patterns []string, p string) bool {
+	for _, pattern := range patterns {
+		if MatchIgnore(pattern, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchIgnore reports whether the repo-relative path p matches pattern.
+func MatchIgnore(pattern, p string) bool {
+	pattern = strings.TrimPrefix(pattern, "/")
+	if dir, ok := strings.CutSuffix(pattern, "/"); ok {
+		segs := strings.Split(p, "/")
+		if strings.Contains(dir, "/") {
+			// Anchored directory: match it against each leading path.
+			for i := 1; i < len(segs); i++ {
+				if ok, _ := path.Match(dir, strings.Join(segs[:i], "/")); ok {
+					return true
+				}
+			}
+			return false
+		}
+		for _, seg := range segs[:len(segs)-1] {
+			if ok, _ := path.Match(dir, seg); ok {
+				return true
+			}
+		}
+		return false
+	}
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, path.Base(p))
+		return ok
+	}
+	ok, _ := path.Match(pattern, p)
+	return ok
+}
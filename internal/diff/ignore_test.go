@@ -0,0 +1,47 @@
+package diff
+
+import "testing"
+
+func TestMatchIgnore(t *testing.T) {
+	tests := []struct {
+		pattern, path string
+		want          bool
+	}{
+		{"*.lock", "Cargo.lock", true},
+		{"*.lock", "sub/dir/yarn.lock", true},
+		{"go.sum", "go.sum", true},
+		{"go.sum", "go.sum.bak", false},
+		{"vendor/", "vendor/github.com/x/y.go", true},
+		{"vendor/", "third_party/vendor/a.go", true},
+		{"vendor/", "vendor.go", false},
+		{"web/dist/", "web/dist/app.js", true},
+		{"web/dist/", "other/web/dist/app.js", false},
+		{"*_generated.go", "internal/api/types_generated.go", true},
+		{"internal/api/*.pb.go", "internal/api/x.pb.go", true},
+		{"internal/api/*.pb.go", "internal/api/v1/x.pb.go", false},
+		{"/package-lock.json", "package-lock.json", true},
+	}
+	for _, tt := range tests {
+		if got := MatchIgnore(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("MatchIgnore(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestDiffIgnore(t *testing.T) {
+	d, err := Parse("diff --git a/main.go b/main.go\n" +
+		"--- a/main.go\n+++ b/main.go\n@@ -1 +1 @@\n-a\n+b\n" +
+		"diff --git a/go.sum b/go.sum\n" +
+		"--- a/go.sum\n+++ b/go.sum\n@@ -1 +1 @@\n-a\n+b\n" +
+		"diff --git a/vendor/x/x.go b/vendor/x/x.go\n" +
+		"--- a/vendor/x/x.go\n+++ b/vendor/x/x.go\n@@ -1 +1 @@\n-a\n+b\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := d.Ignore([]string{"go.sum", "vendor/"}); n != 2 {
+		t.Errorf("Ignore dropped %d files, want 2", n)
+	}
+	if d.TotalFiles() != 1 || d.Files[0].GetFilePath() != "main.go" {
+		t.Errorf("kept %+v, want only main.go", d.Files)
+	}
+}
@@ -2,6 +2,9 @@ package diff
 
 import (
 	"bufio"
+	"bytes"
+	"fmt"
+	"io"
 	"os/exec"
 	"path/filepath"
 	"regexp"
@@ -41,6 +44,10 @@ type FileDiff struct {
 	NewPath string
 	Hunks   []Hunk
 	Binary  bool
+
+	// Unloaded counts the diff lines of a file too big to load up front
+	// (see Options.LazyFileLines); its Hunks are empty until Load.
+	Unloaded int
 }
 
 // Diff represents a complete git diff.
@@ -50,131 +57,213 @@ type Diff struct {
 
 var hunkHeaderRegex = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
 
+// DefaultMaxLineBytes is where a diff line is cut short when Options
+// doesn't say.
+const DefaultMaxLineBytes = 4096
+
+// Options say which changes to read and tune parsing for very large
+// diffs.
+type Options struct {
+	// Source is the changes GitDiff and Load read; the zero value is the
+	// uncommitted ones.
+	Source Source
+
+	// MaxLineBytes cuts longer lines (minified JS, lockfiles) short, ending
+	// them with "…". 0 means DefaultMaxLineBytes.
+	MaxLineBytes int
+
+	// LazyFileLines leaves the hunks of a file with more diff lines than
+	// this unloaded, counting them in Unloaded instead; Load reads them
+	// later. 0 loads every file.
+	LazyFileLines int
+}
+
 // Parse parses a unified diff string into a structured Diff.
 func Parse(diffText string) (*Diff, error) {
-	diff := &Diff{}
-	scanner := bufio.NewScanner(strings.NewReader(diffText))
-
-	var currentFile *FileDiff
-	var currentHunk *Hunk
-	var oldLineNum, newLineNum int
-
-	for scanner.Scan() {
-		line := scanner.Text()
-
-		// New file diff starts with "diff --git"
-		if strings.HasPrefix(line, "diff --git ") {
-			if currentFile != nil {
-				if currentHunk != nil {
-					currentFile.Hunks = append(currentFile.Hunks, *currentHunk)
-				}
-				diff.Files = append(diff.Files, *currentFile)
-			}
-			currentFile = &FileDiff{}
-			currentHunk = nil
-
-			// Parse paths from "diff --git a/path b/path"
-			parts := strings.SplitN(line, " ", 4)
-			if len(parts) >= 4 {
-				currentFile.OldPath = strings.TrimPrefix(parts[2], "a/")
-				currentFile.NewPath = strings.TrimPrefix(parts[3], "b/")
+	return ParseReader(strings.NewReader(diffText), Options{})
+}
+
+// ParseReader parses a unified diff as it is read from r, without holding
+// more than one line of it, or any unloaded file's hunks, in memory.
+func ParseReader(r io.Reader, opts Options) (*Diff, error) {
+	if opts.MaxLineBytes <= 0 {
+		opts.MaxLineBytes = DefaultMaxLineBytes
+	}
+	p := &parser{diff: &Diff{}, opts: opts}
+	br := bufio.NewReader(r)
+	for {
+		line, err := readLine(br, opts.MaxLineBytes)
+		if err != nil && (err != io.EOF || line == "") {
+			p.finishFile()
+			if err == io.EOF {
+				err = nil
 			}
-			continue
+			return p.diff, err
 		}
+		p.line(line)
+	}
+}
 
-		if currentFile == nil {
+// readLine reads the next line from br without its newline, keeping at most
+// max bytes of it. The rest of a longer line is read and thrown away.
+func readLine(br *bufio.Reader, max int) (string, error) {
+	var buf []byte
+	cut := false
+	for {
+		chunk, err := br.ReadSlice('\n')
+		chunk = bytes.TrimSuffix(chunk, []byte("\n"))
+		if room := max - len(buf); len(chunk) > room {
+			chunk, cut = chunk[:room], true
+		}
+		buf = append(buf, chunk...)
+		if err == bufio.ErrBufferFull {
 			continue
 		}
+		if cut {
+			return strings.ToValidUTF8(string(buf), "") + "…", err
+		}
+		return strings.TrimSuffix(string(buf), "\r"), err
+	}
+}
 
-		// Check for binary file
-		if strings.HasPrefix(line, "Binary files") {
-			currentFile.Binary = true
-			continue
+// parser holds the file and hunk being read.
+type parser struct {
+	diff                   *Diff
+	opts                   Options
+	file                   *FileDiff
+	hunk                   *Hunk
+	oldLineNum, newLineNum int
+}
+
+// finishFile adds the file being read, if any, to the diff.
+func (p *parser) finishFile() {
+	if p.file == nil {
+		return
+	}
+	p.finishHunk()
+	p.diff.Files = append(p.diff.Files, *p.file)
+	p.file = nil
+}
+
+// finishHunk adds the hunk being read, if any, to the file, unless the file
+// has grown too big to load.
+func (p *parser) finishHunk() {
+	if p.hunk != nil && p.file.Unloaded == 0 {
+		p.file.Hunks = append(p.file.Hunks, *p.hunk)
+	}
+	p.hunk = nil
+}
+
+// line parses the next line of the diff.
+func (p *parser) line(line string) {
+	// New file diff starts with "diff --git"
+	if strings.HasPrefix(line, "diff --git ") {
+		p.finishFile()
+		p.file = &FileDiff{}
+
+		// Parse paths from "diff --git a/path b/path"
+		parts := strings.SplitN(line, " ", 4)
+		if len(parts) >= 4 {
+			p.file.OldPath = strings.TrimPrefix(parts[2], "a/")
+			p.file.NewPath = strings.TrimPrefix(parts[3], "b/")
 		}
+		return
+	}
 
-		// Parse --- and +++ lines for file paths
-		if strings.HasPrefix(line, "--- ") {
-			path := strings.TrimPrefix(line, "--- ")
-			if path != "/dev/null" {
-				currentFile.OldPath = strings.TrimPrefix(path, "a/")
-			}
-			continue
+	f := p.file
+	if f == nil {
+		return
+	}
+
+	// Check for binary file
+	if strings.HasPrefix(line, "Binary files") {
+		f.Binary = true
+		return
+	}
+
+	// Parse --- and +++ lines for file paths
+	if strings.HasPrefix(line, "--- ") {
+		path := strings.TrimPrefix(line, "--- ")
+		if path != "/dev/null" {
+			f.OldPath = strings.TrimPrefix(path, "a/")
 		}
-		if strings.HasPrefix(line, "+++ ") {
-			path := strings.TrimPrefix(line, "+++ ")
-			if path != "/dev/null" {
-				currentFile.NewPath = strings.TrimPrefix(path, "b/")
-			}
-			continue
+		return
+	}
+	if strings.HasPrefix(line, "+++ ") {
+		path := strings.TrimPrefix(line, "+++ ")
+		if path != "/dev/null" {
+			f.NewPath = strings.TrimPrefix(path, "b/")
 		}
+		return
+	}
 
-		// Parse hunk header
-		if matches := hunkHeaderRegex.FindStringSubmatch(line); matches != nil {
-			if currentHunk != nil {
-				currentFile.Hunks = append(currentFile.Hunks, *currentHunk)
-			}
-
-			oldStart, _ := strconv.Atoi(matches[1])
-			oldCount := 1
-			if matches[2] != "" {
-				oldCount, _ = strconv.Atoi(matches[2])
-			}
-			newStart, _ := strconv.Atoi(matches[3])
-			newCount := 1
-			if matches[4] != "" {
-				newCount, _ = strconv.Atoi(matches[4])
-			}
+	// Parse hunk header
+	if matches := hunkHeaderRegex.FindStringSubmatch(line); matches != nil {
+		p.finishHunk()
 
-			currentHunk = &Hunk{
-				OldStart: oldStart,
-				OldCount: oldCount,
-				NewStart: newStart,
-				NewCount: newCount,
-				Header:   line,
-			}
-			oldLineNum = oldStart
-			newLineNum = newStart
-			continue
+		oldStart, _ := strconv.Atoi(matches[1])
+		oldCount := 1
+		if matches[2] != "" {
+			oldCount, _ = strconv.Atoi(matches[2])
+		}
+		newStart, _ := strconv.Atoi(matches[3])
+		newCount := 1
+		if matches[4] != "" {
+			newCount, _ = strconv.Atoi(matches[4])
 		}
 
-		// Parse diff lines
-		if currentHunk != nil && len(line) > 0 {
-			diffLine := Line{}
-			switch line[0] {
-			case '+':
-				diffLine.Type = LineAdded
-				diffLine.Content = line[1:]
-				diffLine.NewNum = newLineNum
-				newLineNum++
-			case '-':
-				diffLine.Type = LineRemoved
-				diffLine.Content = line[1:]
-				diffLine.OldNum = oldLineNum
-				oldLineNum++
-			case ' ':
-				diffLine.Type = LineContext
-				diffLine.Content = line[1:]
-				diffLine.OldNum = oldLineNum
-				diffLine.NewNum = newLineNum
-				oldLineNum++
-				newLineNum++
-			default:
-				// Could be "\ No newline at end of file" or other
-				continue
-			}
-			currentHunk.Lines = append(currentHunk.Lines, diffLine)
+		p.hunk = &Hunk{
+			OldStart: oldStart,
+			OldCount: oldCount,
+			NewStart: newStart,
+			NewCount: newCount,
+			Header:   line,
 		}
+		p.oldLineNum = oldStart
+		p.newLineNum = newStart
+		return
 	}
 
-	// Don't forget the last file/hunk
-	if currentFile != nil {
-		if currentHunk != nil {
-			currentFile.Hunks = append(currentFile.Hunks, *currentHunk)
-		}
-		diff.Files = append(diff.Files, *currentFile)
+	// Parse diff lines
+	if p.hunk == nil || len(line) == 0 {
+		return
+	}
+	diffLine := Line{}
+	switch line[0] {
+	case '+':
+		diffLine.Type = LineAdded
+		diffLine.Content = line[1:]
+		diffLine.NewNum = p.newLineNum
+		p.newLineNum++
+	case '-':
+		diffLine.Type = LineRemoved
+		diffLine.Content = line[1:]
+		diffLine.OldNum = p.oldLineNum
+		p.oldLineNum++
+	case ' ':
+		diffLine.Type = LineContext
+		diffLine.Content = line[1:]
+		diffLine.OldNum = p.oldLineNum
+		diffLine.NewNum = p.newLineNum
+		p.oldLineNum++
+		p.newLineNum++
+	default:
+		// Could be "\ No newline at end of file" or other
+		return
 	}
 
-	return diff, scanner.Err()
+	if f.Unloaded > 0 {
+		f.Unloaded++
+		return
+	}
+	p.hunk.Lines = append(p.hunk.Lines, diffLine)
+	if lazy := p.opts.LazyFileLines; lazy > 0 {
+		if n := f.TotalLines() + len(p.hunk.Lines); n > lazy {
+			// Too big to load up front: drop what was read and just count.
+			f.Hunks, p.hunk.Lines = nil, nil
+			f.Unloaded = n
+		}
+	}
 }
 
 // GetGitDiff runs git diff in the specified directory and returns the output.
@@ -194,6 +283,65 @@ func GetGitDiff(dir string) (string, error) {
 	return string(out), nil
 }
 
+// GitDiff parses opts.Source's changes in dir as git diff writes them,
+// for diffs too big to hold as text.
+func GitDiff(dir string, opts Options) (*Diff, error) {
+	d, err := streamDiff(dir, opts, opts.Source.args()...)
+	if err != nil && opts.Source.Kind == SourceWorking {
+		// Try without HEAD (for repos with no commits yet)
+		return streamDiff(dir, opts, "diff")
+	}
+	return d, err
+}
+
+// Load reads the hunks of a file left unloaded by LazyFileLines from
+// opts.Source's changes in dir.
+func (f *FileDiff) Load(dir string, opts Options) error {
+	if f.Unloaded == 0 {
+		return nil
+	}
+	opts.LazyFileLines = 0
+	paths := []string{f.GetFilePath()}
+	if f.OldPath != "" && f.OldPath != f.NewPath {
+		paths = append(paths, f.OldPath)
+	}
+	d, err := streamDiff(dir, opts, opts.Source.args(paths...)...)
+	if err != nil {
+		return err
+	}
+	for _, loaded := range d.Files {
+		if loaded.GetFilePath() == f.GetFilePath() {
+			*f = loaded
+			return nil
+		}
+	}
+	return fmt.Errorf("%s has no changes any more", f.GetFilePath())
+}
+
+// streamDiff parses the output of git with args, run in dir, as it comes.
+func streamDiff(dir string, opts Options, args ...string) (*Diff, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	d, parseErr := ParseReader(out, opts)
+	_, _ = io.Copy(io.Discard, out) // let git finish if parsing stopped early
+	if err := cmd.Wait(); err != nil {
+		if msg, _, _ := strings.Cut(strings.TrimSpace(stderr.String()), "\n"); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+	return d, parseErr
+}
+
 // GetGitDiffCached runs git diff --cached in the specified directory.
 func GetGitDiffCached(dir string) (string, error) {
 	cmd := exec.Command("git", "diff", "--cached")
@@ -238,6 +386,31 @@ func (f *FileDiff) TotalLines() int {
 	return total
 }
 
+// Unified renders the file's changes back as a unified diff, headers
+// first, for quoting elsewhere.
+func (f *FileDiff) Unified() string {
+	var sb strings.Builder
+	sb.WriteString("--- a/" + f.OldPath + "\n+++ b/" + f.NewPath + "\n")
+	if f.Binary {
+		sb.WriteString("Binary files differ\n")
+	}
+	for _, h := range f.Hunks {
+		sb.WriteString(h.Header + "\n")
+		for _, l := range h.Lines {
+			switch l.Type {
+			case LineAdded:
+				sb.WriteByte('+')
+			case LineRemoved:
+				sb.WriteByte('-')
+			default:
+				sb.WriteByte(' ')
+			}
+			sb.WriteString(l.Content + "\n")
+		}
+	}
+	return sb.String()
+}
+
 // IsEmpty returns true if the diff has no files.
 func (d *Diff) IsEmpty() bool {
 	return len(d.Files) == 0
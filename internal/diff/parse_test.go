@@ -1,6 +1,11 @@
 package diff
 
 import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -157,3 +162,165 @@ func TestTotalFiles(t *testing.T) {
 		t.Errorf("TotalFiles() = %d, want 3", d.TotalFiles())
 	}
 }
+
+func TestUnifiedRoundTrips(t *testing.T) {
+	raw := "diff --git a/hello.go b/hello.go\n" +
+		"--- a/hello.go\n" +
+		"+++ b/hello.go\n" +
+		"@@ -1,2 +1,2 @@ func main() {\n" +
+		" context\n" +
+		"-removed\n" +
+		"+added\n"
+
+	d, err := Parse(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strings.TrimPrefix(raw, "diff --git a/hello.go b/hello.go\n")
+	if got := d.Files[0].Unified(); got != want {
+		t.Errorf("Unified() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestParseReaderCutsLongLines(t *testing.T) {
+	long := strings.Repeat("x", 200*1024) // beyond bufio.Scanner's 64 KB limit
+	raw := "diff --git a/min.js b/min.js\n" +
+		"--- a/min.js\n" +
+		"+++ b/min.js\n" +
+		"@@ -1 +1,2 @@\n" +
+		"-" + long + "\n" +
+		"+short\r\n" +
+		"+" + strings.Repeat("y", 99) + "\n"
+
+	d, err := ParseReader(strings.NewReader(raw), Options{MaxLineBytes: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := d.Files[0].Hunks[0].Lines
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3", len(lines))
+	}
+	if want := strings.Repeat("x", 99) + "…"; lines[0].Content != want {
+		t.Errorf("long line = %d bytes, want cut at 100", len(lines[0].Content))
+	}
+	if lines[1].Content != "short" {
+		t.Errorf("CRLF line = %q", lines[1].Content)
+	}
+	if lines[2].Content != strings.Repeat("y", 99) {
+		t.Error("a line that fits exactly should not be cut")
+	}
+
+	if _, err := Parse(raw); err != nil {
+		t.Errorf("Parse of a long line: %v", err)
+	}
+}
+
+func TestParseReaderLeavesBigFilesUnloaded(t *testing.T) {
+	var big strings.Builder
+	big.WriteString("diff --git a/big.txt b/big.txt\n--- a/big.txt\n+++ b/big.txt\n@@ -0,0 +1,50 @@\n")
+	for i := 0; i < 50; i++ {
+		fmt.Fprintf(&big, "+line %d\n", i)
+	}
+	raw := big.String() + "diff --git a/small.txt b/small.txt\n--- a/small.txt\n+++ b/small.txt\n@@ -1 +1 @@\n-a\n+b\n"
+
+	d, err := ParseReader(strings.NewReader(raw), Options{LazyFileLines: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Files) != 2 {
+		t.Fatalf("got %d files, want 2", len(d.Files))
+	}
+	if f := d.Files[0]; f.Unloaded != 50 || len(f.Hunks) != 0 {
+		t.Errorf("big file: Unloaded = %d with %d hunks, want 50 and none", f.Unloaded, len(f.Hunks))
+	}
+	if f := d.Files[1]; f.Unloaded != 0 || f.TotalLines() != 2 {
+		t.Errorf("small file: Unloaded = %d, %d lines", f.Unloaded, f.TotalLines())
+	}
+}
+
+func TestGitDiffAndLoad(t *testing.T) {
+	dir := gitRepo(t)
+	write := func(name, text string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(text), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("big.txt", "")
+	write("small.txt", "a\n")
+	commitAll(t, dir)
+	write("big.txt", strings.Repeat("new\n", 30))
+	write("small.txt", "b\n")
+
+	d, err := GitDiff(dir, Options{LazyFileLines: 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Files) != 2 || d.Files[0].Unloaded != 30 || d.Files[1].TotalLines() != 2 {
+		t.Fatalf("GitDiff = %+v", d.Files)
+	}
+	if err := d.Files[0].Load(dir, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	if f := d.Files[0]; f.Unloaded != 0 || f.TotalLines() != 30 || f.GetFilePath() != "big.txt" {
+		t.Errorf("loaded file: Unloaded = %d, %d lines", f.Unloaded, f.TotalLines())
+	}
+}
+
+func TestGitDiffSources(t *testing.T) {
+	dir := gitRepo(t)
+	write := func(name, text string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(text), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("first.txt", "a\n")
+	commitAll(t, dir) // root commit
+	write("second.txt", "b\n")
+	commitAll(t, dir)
+	write("staged.txt", "c\n")
+	if out, err := exec.Command("git", "-C", dir, "add", "staged.txt").CombinedOutput(); err != nil {
+		t.Fatalf("git add: %v\n%s", err, out)
+	}
+	write("first.txt", "changed\n")
+
+	for _, tc := range []struct {
+		src  Source
+		want []string
+	}{
+		{Source{}, []string{"first.txt", "staged.txt"}},
+		{Source{Kind: SourceStaged}, []string{"staged.txt"}},
+		{Source{Kind: SourceCommit, Rev: "HEAD"}, []string{"second.txt"}},
+		{Source{Kind: SourceCommit, Rev: "HEAD~1"}, []string{"first.txt"}},
+		{Source{Kind: SourceRange, Rev: "HEAD~1...HEAD"}, []string{"second.txt"}},
+	} {
+		d, err := GitDiff(dir, Options{Source: tc.src})
+		if err != nil {
+			t.Errorf("%s: %v", tc.src, err)
+			continue
+		}
+		var got []string
+		for _, f := range d.Files {
+			got = append(got, f.GetFilePath())
+		}
+		if strings.Join(got, " ") != strings.Join(tc.want, " ") {
+			t.Errorf("%s: files %v, want %v", tc.src, got, tc.want)
+		}
+	}
+
+	if _, err := GitDiff(dir, Options{Source: Source{Kind: SourceCommit, Rev: "nope"}}); err == nil {
+		t.Error("an unknown commit should fail")
+	}
+
+	// A rev that looks like an option is still read as a rev.
+	out := filepath.Join(t.TempDir(), "written")
+	for _, kind := range []SourceKind{SourceCommit, SourceRange} {
+		if _, err := GitDiff(dir, Options{Source: Source{Kind: kind, Rev: "--output=" + out}}); err == nil {
+			t.Errorf("kind %d: an option as the rev should fail", kind)
+		}
+		if _, err := os.Stat(out); err == nil {
+			t.Fatalf("kind %d: git took the rev as --output", kind)
+		}
+	}
+}
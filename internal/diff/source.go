@@ -0,0 +1,54 @@
+package diff
+
+// SourceKind is which changes a Source reads.
+type SourceKind int
+
+const (
+	SourceWorking SourceKind = iota // Uncommitted changes, against HEAD
+	SourceStaged                    // The index, against HEAD
+	SourceCommit                    // One commit, against its first parent
+	SourceRange                     // A revision range, e.g. origin/main...HEAD
+)
+
+// Source names the changes a diff is read from. The zero Source is the
+// working tree.
+type Source struct {
+	Kind SourceKind
+	Rev  string // The commit of SourceCommit or the range of SourceRange
+}
+
+// String names the source for display.
+func (s Source) String() string {
+	switch s.Kind {
+	case SourceStaged:
+		return "staged"
+	case SourceCommit:
+		return "commit " + s.Rev
+	case SourceRange:
+		return s.Rev
+	}
+	return "working tree"
+}
+
+// args returns the git command line that writes the source's diff, limited
+// to paths when there are any. Rev is typed by the user, so it is kept
+// from being read as an option such as --output.
+func (s Source) args(paths ...string) []string {
+	var args []string
+	switch s.Kind {
+	case SourceStaged:
+		args = []string{"diff", "--cached"}
+	case SourceCommit:
+		// show rather than diff rev^!, which has no parent to compare a
+		// root commit with.
+		args = []string{"show", "--format=", "--first-parent", "--end-of-options", s.Rev}
+	case SourceRange:
+		args = []string{"diff", "--end-of-options", s.Rev}
+	default:
+		args = []string{"diff", "HEAD"}
+	}
+	if len(paths) > 0 {
+		args = append(append(args, "--"), paths...)
+	}
+	return args
+}
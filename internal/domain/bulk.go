@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BulkEntry is one session row in the bulk-edit buffer.
+type BulkEntry struct {
+	Key     string // session Key(); identifies the row, must not be edited
+	Project string // informational only; ignored when parsing
+	Name    string
+	Group   string
+	Pinned  bool
+}
+
+const bulkHeader = `# herd bulk edit — one session per line:
+#   key | project | name | group | pin
+# Edit the name, group and pin columns ("pin" to pin, empty to unpin).
+# The key and project columns are read-only. Lines starting with # are ignored.
+# Write \| for a | inside a column and \\ for a backslash.
+`
+
+// FormatBulk renders entries as an editable, pipe-separated text buffer.
+func FormatBulk(entries []BulkEntry) string {
+	var sb strings.Builder
+	sb.WriteString(bulkHeader)
+	for _, e := range entries {
+		pin := ""
+		if e.Pinned {
+			pin = "pin"
+		}
+		fmt.Fprintf(&sb, "%s | %s | %s | %s | %s\n", bulkEscaper.Replace(e.Key), bulkEscaper.Replace(e.Project),
+			bulkEscaper.Replace(e.Name), bulkEscaper.Replace(e.Group), pin)
+	}
+	return sb.String()
+}
+
+var bulkEscaper = strings.NewReplacer(`\`, `\\`, "|", `\|`)
+
+// splitBulk splits line on the pipes FormatBulk didn't escape and unescapes
+// each column.
+func splitBulk(line string) []string {
+	var cols []string
+	var col strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case c == '\\' && i+1 < len(line) && (line[i+1] == '|' || line[i+1] == '\\'):
+			i++
+			col.WriteByte(line[i])
+		case c == '|':
+			cols = append(cols, col.String())
+			col.Reset()
+		default:
+			col.WriteByte(c)
+		}
+	}
+	return append(cols, col.String())
+}
+
+// ParseBulk parses a buffer produced by FormatBulk (and edited by the user).
+// Blank lines and # comments are skipped. Every other line must have exactly
+// five pipe-separated columns, with \| and \\ escaping a pipe or backslash
+// inside one; the returned error names the first bad line.
+func ParseBulk(text string) ([]BulkEntry, error) {
+	var entries []BulkEntry
+	seen := make(map[string]bool)
+	for i, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		cols := splitBulk(trimmed)
+		if len(cols) != 5 {
+			return nil, fmt.Errorf("line %d: want 5 columns separated by |, got %d", i+1, len(cols))
+		}
+		for j := range cols {
+			cols[j] = strings.TrimSpace(cols[j])
+		}
+		if cols[0] == "" {
+			return nil, fmt.Errorf("line %d: missing session key", i+1)
+		}
+		if seen[cols[0]] {
+			return nil, fmt.Errorf("line %d: duplicate session key %s", i+1, cols[0])
+		}
+		seen[cols[0]] = true
+
+		var pinned bool
+		switch strings.ToLower(cols[4]) {
+		case "", "-", "no":
+		case "pin", "pinned", "yes", "y", "x":
+			pinned = true
+		default:
+			return nil, fmt.Errorf("line %d: pin column must be \"pin\" or empty, got %q", i+1, cols[4])
+		}
+
+		entries = append(entries, BulkEntry{
+			Key:     cols[0],
+			Project: cols[1],
+			Name:    cols[2],
+			Group:   cols[3],
+			Pinned:  pinned,
+		})
+	}
+	return entries, nil
+}
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFormatParseBulkRoundTrip(t *testing.T) {
+	entries := []BulkEntry{
+		{Key: "session:abc", Project: "~/code/alpha", Name: "api", Group: "backend", Pinned: true},
+		{Key: "pane:%5", Project: "~/code/beta"},
+		{Key: "session:def", Project: `~/code/a|b`, Name: "fix | lint", Group: `ops\|`},
+	}
+	got, err := ParseBulk(FormatBulk(entries))
+	if err != nil {
+		t.Fatalf("ParseBulk() error: %v", err)
+	}
+	if !reflect.DeepEqual(got, entries) {
+		t.Errorf("round trip = %+v, want %+v", got, entries)
+	}
+}
+
+func TestParseBulkSkipsCommentsAndBlanks(t *testing.T) {
+	text := "# header\n\n  session:a | p | n | g | pin  \n"
+	got, err := ParseBulk(text)
+	if err != nil {
+		t.Fatalf("ParseBulk() error: %v", err)
+	}
+	want := []BulkEntry{{Key: "session:a", Project: "p", Name: "n", Group: "g", Pinned: true}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseBulk() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseBulkErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"too few columns", "session:a | p | n", "line 1"},
+		{"missing key", " | p | n | g | ", "missing session key"},
+		{"duplicate key", "k | p | | | \nk | p | | | ", "duplicate"},
+		{"bad pin", "k | p | n | g | maybe", "pin column"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseBulk(tt.text)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ParseBulk() error = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+}
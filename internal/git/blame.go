@@ -0,0 +1,56 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Blame is who last changed a line, and when.
+type Blame struct {
+	Commit string
+	Author string
+	Time   time.Time
+}
+
+// BlameLines blames the file at path, relative to dir, as of HEAD. Entry i
+// is line i+1. It fails for a file HEAD doesn't have.
+func BlameLines(dir, path string) ([]Blame, error) {
+	out, err := exec.Command("git", "-C", dir, "blame", "--line-porcelain", "HEAD", "--", path).Output()
+	if err != nil {
+		return nil, err
+	}
+	return parseBlame(out), nil
+}
+
+// parseBlame reads git blame --line-porcelain output: for each line a
+// "<commit> <orig> <final>" header, then "key value" fields, then the line
+// itself after a tab.
+func parseBlame(data []byte) []Blame {
+	var lines []Blame
+	var current Blame
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	fresh := true
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "\t"):
+			lines = append(lines, current)
+			fresh = true
+		case fresh:
+			current = Blame{Commit: strings.SplitN(line, " ", 2)[0]}
+			fresh = false
+		case strings.HasPrefix(line, "author "):
+			current.Author = strings.TrimPrefix(line, "author ")
+		case strings.HasPrefix(line, "author-time "):
+			if sec, err := strconv.ParseInt(strings.TrimPrefix(line, "author-time "), 10, 64); err == nil {
+				current.Time = time.Unix(sec, 0)
+			}
+		}
+	}
+	return lines
+}
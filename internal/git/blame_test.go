@@ -0,0 +1,61 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestBlameLines(t *testing.T) {
+	repo := t.TempDir()
+	git := func(env []string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", repo}, args...)...)
+		cmd.Env = append(os.Environ(), env...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(text string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repo, "a.txt"), []byte(text), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	author := func(name, date string) []string {
+		return []string{"GIT_AUTHOR_NAME=" + name, "GIT_AUTHOR_EMAIL=" + name + "@test.com", "GIT_AUTHOR_DATE=" + date,
+			"GIT_COMMITTER_NAME=" + name, "GIT_COMMITTER_EMAIL=" + name + "@test.com"}
+	}
+	git(nil, "init")
+	write("one\ntwo\n")
+	git(nil, "add", ".")
+	git(author("Ada", "2024-01-02T03:04:05Z"), "commit", "-m", "first")
+	write("one\nTWO\nthree\n")
+	git(nil, "add", ".")
+	git(author("Bob", "2025-06-07T08:09:10Z"), "commit", "-m", "second")
+	write("uncommitted\n")
+
+	lines, err := BlameLines(repo, "a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want HEAD's 3: %+v", len(lines), lines)
+	}
+	for i, want := range []string{"Ada", "Bob", "Bob"} {
+		if lines[i].Author != want {
+			t.Errorf("line %d author = %q, want %q", i+1, lines[i].Author, want)
+		}
+	}
+	if got := lines[0].Time.UTC().Format("2006-01-02"); got != "2024-01-02" {
+		t.Errorf("line 1 time = %s", got)
+	}
+	if lines[0].Commit == lines[1].Commit || len(lines[1].Commit) != 40 {
+		t.Errorf("commits = %q, %q", lines[0].Commit, lines[1].Commit)
+	}
+
+	if _, err := BlameLines(repo, "missing.txt"); err == nil {
+		t.Error("blaming a file HEAD doesn't have should fail")
+	}
+}
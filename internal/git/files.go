@@ -0,0 +1,65 @@
+package git
+
+import (
+	"errors"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+// ListFiles returns the paths, relative to dir, of the files in dir's
+// repository that git tracks, plus the untracked ones .gitignore doesn't
+// exclude.
+func ListFiles(dir string) ([]string, error) {
+	out, err := exec.Command("git", "-C", dir, "ls-files", "--cached", "--others", "--exclude-standard", "--deduplicate", "-z").Output()
+	if err != nil {
+		return nil, err
+	}
+	files := splitNUL(out)
+	sort.Strings(files)
+	return files, nil
+}
+
+// Ignored reports which of names, entries of dir, .gitignore excludes. It
+// fails outside a repository.
+func Ignored(dir string, names []string) (map[string]bool, error) {
+	cmd := exec.Command("git", "-C", dir, "check-ignore", "-z", "--stdin")
+	cmd.Stdin = strings.NewReader(strings.Join(names, "\x00"))
+	out, err := cmd.Output()
+	var exit *exec.ExitError
+	if err != nil && !(errors.As(err, &exit) && exit.ExitCode() == 1) { // 1: none ignored
+		return nil, err
+	}
+	ignored := make(map[string]bool)
+	for _, name := range splitNUL(out) {
+		ignored[name] = true
+	}
+	return ignored, nil
+}
+
+// Changes returns the files under dir, relative to it, that are untracked
+// (and not ignored) and those modified since the last commit.
+func Changes(dir string) (untracked, modified []string, err error) {
+	list := func(flag string) ([]string, error) {
+		out, err := exec.Command("git", "-C", dir, "ls-files", flag, "--exclude-standard", "-z").Output()
+		return splitNUL(out), err
+	}
+	if untracked, err = list("--others"); err != nil {
+		return nil, nil, err
+	}
+	if modified, err = list("--modified"); err != nil {
+		return nil, nil, err
+	}
+	return untracked, modified, nil
+}
+
+// splitNUL splits git's -z output into its non-empty fields.
+func splitNUL(out []byte) []string {
+	var fields []string
+	for _, f := range strings.Split(string(out), "\x00") {
+		if f != "" {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
@@ -0,0 +1,92 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestListFilesSkipsIgnored(t *testing.T) {
+	repo := t.TempDir()
+	write := func(name, text string) {
+		t.Helper()
+		p := filepath.Join(repo, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(text), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if out, err := exec.Command("git", "init", repo).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+	write(".gitignore", "build/\n")
+	write("main.go", "package main\n")
+	write("build/out", "binary\n")
+	write("pkg/lib.go", "package pkg\n")
+	if out, err := exec.Command("git", "-C", repo, "add", "main.go").CombinedOutput(); err != nil {
+		t.Fatalf("git add: %v\n%s", err, out)
+	}
+
+	files, err := ListFiles(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{".gitignore", "main.go", "pkg/lib.go"}; !slices.Equal(files, want) {
+		t.Errorf("ListFiles = %q, want %q", files, want)
+	}
+
+	files, err = ListFiles(filepath.Join(repo, "pkg"))
+	if err != nil || !slices.Equal(files, []string{"lib.go"}) {
+		t.Errorf("ListFiles(pkg) = %q, %v, want paths relative to pkg", files, err)
+	}
+}
+
+func TestIgnoredAndChanges(t *testing.T) {
+	repo := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		if out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repo, name), []byte(name+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	git("init")
+	git("config", "user.email", "test@test.com")
+	git("config", "user.name", "Test")
+	if err := os.WriteFile(filepath.Join(repo, ".gitignore"), []byte("*.log\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	write("old.go")
+	git("add", ".")
+	git("commit", "-m", "first")
+	if err := os.WriteFile(filepath.Join(repo, "old.go"), []byte("changed\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	write("new.go")
+	write("run.log")
+
+	ignored, err := Ignored(repo, []string{"old.go", "new.go", "run.log"})
+	if err != nil || len(ignored) != 1 || !ignored["run.log"] {
+		t.Errorf("Ignored = %v, %v, want just run.log", ignored, err)
+	}
+	if ignored, err := Ignored(repo, []string{"old.go"}); err != nil || len(ignored) != 0 {
+		t.Errorf("Ignored with none ignored = %v, %v", ignored, err)
+	}
+	if _, err := Ignored(t.TempDir(), []string{"x"}); err == nil {
+		t.Error("Ignored outside a repository should fail")
+	}
+
+	untracked, modified, err := Changes(repo)
+	if err != nil || !slices.Equal(untracked, []string{"new.go"}) || !slices.Equal(modified, []string{"old.go"}) {
+		t.Errorf("Changes = %q, %q, %v; want new.go untracked, old.go modified", untracked, modified, err)
+	}
+}
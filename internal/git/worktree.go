@@ -3,10 +3,13 @@ package git
 import (
 	"bufio"
 	"bytes"
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // Worktree represents a single git worktree.
@@ -66,9 +69,16 @@ func parseWorktrees(data []byte) []Worktree {
 }
 
 // AddWorktree creates a new git worktree at path on the given branch.
-// If the branch doesn't exist it creates it; if it already exists, checks it out.
-func AddWorktree(repoRoot, path, branch string) error {
-	cmd := exec.Command("git", "-C", repoRoot, "worktree", "add", "-b", branch, path)
+// If the branch doesn't exist it creates it from base (HEAD when empty),
+// fetching base first when it names a remote branch; if it already exists,
+// checks it out and base is ignored.
+func AddWorktree(repoRoot, path, branch, base string) error {
+	args := []string{"-C", repoRoot, "worktree", "add", "-b", branch, path}
+	if base != "" {
+		fetchRemoteRef(repoRoot, base)
+		args = append(args, base)
+	}
+	cmd := exec.Command("git", args...)
 	if err := cmd.Run(); err != nil {
 		// Branch may already exist — try checking it out directly.
 		cmd = exec.Command("git", "-C", repoRoot, "worktree", "add", path, branch)
@@ -77,6 +87,58 @@ func AddWorktree(repoRoot, path, branch string) error {
 	return nil
 }
 
+// fetchRemoteRef updates ref from its remote when it is a remote-tracking
+// name like origin/main, so the new branch starts from the latest commit.
+// Failures are ignored: the local copy of the ref is still usable.
+func fetchRemoteRef(repoRoot, ref string) {
+	remote, branch, ok := strings.Cut(ref, "/")
+	if !ok {
+		return
+	}
+	out, err := exec.Command("git", "-C", repoRoot, "remote").Output()
+	if err != nil {
+		return
+	}
+	for _, r := range strings.Fields(string(out)) {
+		if r == remote {
+			_ = exec.Command("git", "-C", repoRoot, "fetch", remote, branch).Run()
+			return
+		}
+	}
+}
+
+// DiskUsage returns the total size of the regular files under path.
+// Unreadable entries are skipped.
+func DiskUsage(path string) int64 {
+	var total int64
+	_ = filepath.WalkDir(path, func(_ string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				total += info.Size()
+			}
+		}
+		return nil
+	})
+	return total
+}
+
+// LastCommitTime returns when the commit checked out in the worktree at
+// path was made.
+func LastCommitTime(path string) (time.Time, error) {
+	out, err := exec.Command("git", "-C", path, "log", "-1", "--format=%ct").Output()
+	if err != nil {
+		return time.Time{}, err
+	}
+	secs, err := strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(secs, 0), nil
+}
+
 // DefaultWorktreePath returns the conventional path for a new worktree.
 // e.g. repoRoot=/dev/herd, branch=feat/payments → ~/.herd/worktrees/herd-feat-payments
 func DefaultWorktreePath(repoRoot, branch string) string {
@@ -87,7 +149,51 @@ func DefaultWorktreePath(repoRoot, branch string) string {
 
 // RemoveWorktree removes the git worktree at path within the given repo.
 func RemoveWorktree(repoRoot, path string) error {
-	return exec.Command("git", "-C", repoRoot, "worktree", "remove", path).Run()
+	if out, err := exec.Command("git", "-C", repoRoot, "worktree", "remove", path).CombinedOutput(); err != nil {
+		return fmt.Errorf("git worktree remove: %s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// ForceRemoveWorktree removes the git worktree at path even though it has
+// uncommitted changes, which are lost.
+func ForceRemoveWorktree(repoRoot, path string) error {
+	if out, err := exec.Command("git", "-C", repoRoot, "worktree", "remove", "--force", path).CombinedOutput(); err != nil {
+		return fmt.Errorf("git worktree remove --force: %s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// Uncommitted lists the changed and untracked files in the worktree at
+// path as `git status --porcelain` reports them, e.g. " M main.go"; none
+// means git worktree remove will go ahead without --force.
+func Uncommitted(path string) ([]string, error) {
+	out, err := exec.Command("git", "-C", path, "status", "--porcelain").Output()
+	if err != nil {
+		return nil, fmt.Errorf("git status in %s: %w", path, err)
+	}
+	var files []string
+	for _, line := range strings.Split(strings.TrimRight(string(out), "\n"), "\n") {
+		if line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
+
+// IsMerged reports whether branch's tip is already contained in into, so
+// deleting branch loses nothing.
+func IsMerged(repoRoot, branch, into string) bool {
+	return exec.Command("git", "-C", repoRoot, "merge-base", "--is-ancestor", branch, into).Run() == nil
+}
+
+// DeleteBranch deletes a fully merged local branch; git refuses unmerged
+// ones.
+func DeleteBranch(repoRoot, branch string) error {
+	if out, err := exec.Command("git", "-C", repoRoot, "branch", "-d", branch).CombinedOutput(); err != nil {
+		return fmt.Errorf("git branch -d %s: %s", branch, strings.TrimSpace(string(out)))
+	}
+	return nil
 }
 
 // sanitiseBranch replaces path-unsafe characters with "-".
@@ -103,3 +209,63 @@ func sanitiseBranch(branch string) string {
 	}
 	return b.String()
 }
+
+// CopyUntracked copies the files and directories in src matching patterns
+// (globs relative to src) to the same places under dst, skipping any that
+// already exist there. It returns the relative paths copied. Patterns come
+// from the repo's .herd.json, so an absolute pattern, or a match outside src,
+// is an error.
+func CopyUntracked(src, dst string, patterns []string) ([]string, error) {
+	var copied []string
+	for _, pattern := range patterns {
+		if filepath.IsAbs(pattern) {
+			return copied, fmt.Errorf("copy pattern %q is absolute", pattern)
+		}
+		matches, err := filepath.Glob(filepath.Join(src, pattern))
+		if err != nil {
+			return copied, err
+		}
+		for _, match := range matches {
+			if rel, err := filepath.Rel(src, match); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				return copied, fmt.Errorf("copy pattern %q matches %s, outside the repo", pattern, match)
+			}
+			err := filepath.WalkDir(match, func(p string, d os.DirEntry, err error) error {
+				if err != nil || d.IsDir() {
+					return err
+				}
+				rel, err := filepath.Rel(src, p)
+				if err != nil {
+					return err
+				}
+				target := filepath.Join(dst, rel)
+				if _, err := os.Lstat(target); err == nil {
+					return nil
+				}
+				if err := copyFile(p, target); err != nil {
+					return err
+				}
+				copied = append(copied, rel)
+				return nil
+			})
+			if err != nil {
+				return copied, err
+			}
+		}
+	}
+	return copied, nil
+}
+
+func copyFile(src, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(dst, data, info.Mode().Perm())
+}
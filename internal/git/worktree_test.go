@@ -2,9 +2,11 @@ package git
 
 import (
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestParseWorktrees_MainOnly(t *testing.T) {
@@ -100,3 +102,186 @@ func TestSanitiseBranch(t *testing.T) {
 		}
 	}
 }
+
+func TestCopyUntracked(t *testing.T) {
+	src, dst := t.TempDir(), t.TempDir()
+	files := map[string]string{
+		".env":                  "SECRET=1",
+		".env.local":            "LOCAL=1",
+		"config/settings.local": "debug",
+		"main.go":               "package main",
+	}
+	for name, body := range files {
+		path := filepath.Join(src, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Already present in the new worktree: left alone.
+	if err := os.WriteFile(filepath.Join(dst, ".env.local"), []byte("mine"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	copied, err := CopyUntracked(src, dst, []string{".env*", "config/", "missing.json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(copied, ",") != ".env,config/settings.local" {
+		t.Errorf("copied = %v", copied)
+	}
+	if got, _ := os.ReadFile(filepath.Join(dst, ".env.local")); string(got) != "mine" {
+		t.Errorf("existing file overwritten: %q", got)
+	}
+	info, err := os.Stat(filepath.Join(dst, ".env"))
+	if err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf(".env not copied with its mode: %v %v", info, err)
+	}
+	if _, err := os.Stat(filepath.Join(dst, "main.go")); err == nil {
+		t.Error("unmatched file copied")
+	}
+}
+
+func TestCopyUntrackedStaysInRepo(t *testing.T) {
+	root, dst := t.TempDir(), t.TempDir()
+	src := filepath.Join(root, "repo")
+	if err := os.MkdirAll(src, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "id_rsa"), []byte("key"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for _, pattern := range []string{"../id_*", filepath.Join(root, "id_rsa")} {
+		if copied, err := CopyUntracked(src, dst, []string{pattern}); err == nil || len(copied) != 0 {
+			t.Errorf("CopyUntracked(%q) = %v, %v; want it refused", pattern, copied, err)
+		}
+	}
+	if entries, _ := os.ReadDir(dst); len(entries) != 0 {
+		t.Errorf("copied %v from outside the repo", entries)
+	}
+}
+
+func TestAddWorktreeFromBase(t *testing.T) {
+	repo := t.TempDir()
+	run := func(args ...string) string {
+		t.Helper()
+		out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	run("init")
+	run("config", "user.email", "test@test.com")
+	run("config", "user.name", "Test")
+	run("commit", "--allow-empty", "-m", "first")
+	run("tag", "v1")
+	run("commit", "--allow-empty", "-m", "second")
+
+	wt := filepath.Join(t.TempDir(), "wt")
+	if err := AddWorktree(repo, wt, "fix/old", "v1"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := run("rev-parse", "fix/old"), run("rev-parse", "v1"); got != want {
+		t.Errorf("fix/old at %s, want v1 (%s)", got, want)
+	}
+}
+
+func TestIsMergedAndDeleteBranch(t *testing.T) {
+	repo := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		if out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	run("init", "-b", "main")
+	run("config", "user.email", "test@test.com")
+	run("config", "user.name", "Test")
+	run("commit", "--allow-empty", "-m", "first")
+	run("branch", "done")
+	run("checkout", "-b", "wip")
+	run("commit", "--allow-empty", "-m", "unmerged")
+	run("checkout", "main")
+
+	if !IsMerged(repo, "done", "main") || IsMerged(repo, "wip", "main") {
+		t.Error("IsMerged should hold for done only")
+	}
+	if err := DeleteBranch(repo, "done"); err != nil {
+		t.Errorf("DeleteBranch(done) = %v", err)
+	}
+	if err := DeleteBranch(repo, "wip"); err == nil || !strings.Contains(err.Error(), "not fully merged") {
+		t.Errorf("DeleteBranch(wip) = %v, want git's refusal", err)
+	}
+}
+
+func TestDiskUsageAndLastCommitTime(t *testing.T) {
+	repo := t.TempDir()
+	for _, args := range [][]string{
+		{"init"},
+		{"config", "user.email", "test@test.com"},
+		{"config", "user.name", "Test"},
+	} {
+		if out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	if _, err := LastCommitTime(repo); err == nil {
+		t.Error("LastCommitTime on a repo without commits should fail")
+	}
+	cmd := exec.Command("git", "-C", repo, "commit", "--allow-empty", "-m", "c")
+	cmd.Env = append(os.Environ(), "GIT_COMMITTER_DATE=2026-10-01T12:00:00Z")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("commit: %v\n%s", err, out)
+	}
+	at, err := LastCommitTime(repo)
+	if err != nil || !at.Equal(time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("LastCommitTime = %v, %v", at, err)
+	}
+
+	before := DiskUsage(repo)
+	if err := os.WriteFile(filepath.Join(repo, "blob"), make([]byte, 4096), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := DiskUsage(repo) - before; got != 4096 {
+		t.Errorf("DiskUsage grew by %d, want 4096", got)
+	}
+}
+
+func TestUncommittedAndForceRemove(t *testing.T) {
+	repo := filepath.Join(t.TempDir(), "repo")
+	wt := filepath.Join(t.TempDir(), "wt")
+	run := func(args ...string) {
+		t.Helper()
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	run("init", "-b", "main", repo)
+	run("-C", repo, "config", "user.email", "test@test.com")
+	run("-C", repo, "config", "user.name", "Test")
+	run("-C", repo, "commit", "--allow-empty", "-m", "first")
+	run("-C", repo, "worktree", "add", "-b", "wip", wt)
+
+	if files, err := Uncommitted(wt); err != nil || len(files) != 0 {
+		t.Fatalf("Uncommitted(clean) = %q, %v", files, err)
+	}
+	if err := os.WriteFile(filepath.Join(wt, "notes.txt"), []byte("draft"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	files, err := Uncommitted(wt)
+	if err != nil || len(files) != 1 || files[0] != "?? notes.txt" {
+		t.Fatalf("Uncommitted(dirty) = %q, %v; want the untracked file", files, err)
+	}
+	if err := RemoveWorktree(repo, wt); err == nil {
+		t.Fatal("RemoveWorktree removed a dirty worktree")
+	}
+	if err := ForceRemoveWorktree(repo, wt); err != nil {
+		t.Fatalf("ForceRemoveWorktree = %v", err)
+	}
+	if _, err := os.Stat(wt); !os.IsNotExist(err) {
+		t.Errorf("worktree still exists: %v", err)
+	}
+}
@@ -25,6 +25,10 @@ func NewStore(path string) *store.Store {
 	return s
 }
 
+// Reload re-reads the groups file, picking up groups set by another herd
+// process.
+func Reload() error { return defaultStore.Load() }
+
 // Get returns the custom group name for the given session key, or "" if not set.
 func Get(key string) string { return defaultStore.Get(key) }
 
@@ -34,3 +38,7 @@ func Set(key, value string) error { return defaultStore.Set(key, value) }
 
 // Delete removes the custom group assignment for the given key.
 func Delete(key string) error { return defaultStore.Delete(key) }
+
+// Rename moves the custom group assignment from oldKey to newKey, keeping any
+// existing value already stored under newKey.
+func Rename(oldKey, newKey string) error { return defaultStore.Rename(oldKey, newKey) }
@@ -0,0 +1,156 @@
+// Package history keeps an append-only log of session events in
+// ~/.herd/history.jsonl, one JSON object per line, for reports.
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/shnupta/herd/internal/seal"
+)
+
+// Event kinds.
+const (
+	KindState  = "state"  // the session's hook state changed; State is set
+	KindPrompt = "prompt" // the user submitted a prompt
+	KindFile   = "file"   // a tool edited File
+	KindReview = "review" // review feedback was sent from herd
+	KindAction = "action" // herd acted on the session's pane; Action is set
+)
+
+// Actions herd takes on a session's pane, recorded as KindAction events.
+const (
+	ActionPrompt = "prompt" // typed a prompt and submitted it
+	ActionKeys   = "keys"   // sent keystrokes
+	ActionKill   = "kill"   // killed the pane
+	ActionResize = "resize" // resized the pane's window
+)
+
+// Event is one line of the history log.
+type Event struct {
+	Time      time.Time `json:"time"`
+	Kind      string    `json:"kind"`
+	SessionID string    `json:"session_id,omitempty"`
+	TmuxPane  string    `json:"tmux_pane,omitempty"`
+	Project   string    `json:"project,omitempty"`
+	State     string    `json:"state,omitempty"`
+	Tool      string    `json:"tool,omitempty"` // the tool running, with State
+	File      string    `json:"file,omitempty"`
+
+	// Branch is the project's git branch, recorded when a prompt starts a
+	// turn; working time is billed to it.
+	Branch string `json:"branch,omitempty"`
+
+	// Action, Trigger and Detail describe a KindAction event: what herd
+	// did, the user action or automation that made it, and what was sent
+	// (cut to DetailLimit). With record_prompts, Detail also holds a
+	// KindPrompt event's prompt and a notifying state's message. Detail is
+	// sealed in the file when prompts are (see seal.SealText).
+	Action  string `json:"action,omitempty"`
+	Trigger string `json:"trigger,omitempty"`
+	Detail  string `json:"detail,omitempty"`
+}
+
+// DetailLimit caps the runes of an Event's Detail.
+const DetailLimit = 200
+
+// recordText is whether Text keeps what it is given, from config
+// record_prompts.
+var recordText atomic.Bool
+
+// SetRecordText sets whether the free text of actions, such as prompts and
+// keystrokes sent to a pane, is kept in the log.
+func SetRecordText(on bool) { recordText.Store(on) }
+
+// Text returns text for an action's Detail when text is being recorded
+// (see SetRecordText), else "". It is for what the user or a script typed,
+// as opposed to details herd makes up such as a worktree path.
+func Text(text string) string {
+	if !recordText.Load() {
+		return ""
+	}
+	return text
+}
+
+// Path returns the default log location, ~/.herd/history.jsonl.
+func Path() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".herd", "history.jsonl")
+}
+
+// Append adds ev to the default log.
+func Append(ev Event) error { return AppendTo(Path(), ev) }
+
+// AppendTo adds ev to the log at path. Each event is written with a single
+// O_APPEND write so concurrent hook processes don't interleave lines. An
+// event whose Detail can't be sealed is not written.
+func AppendTo(path string, ev Event) error {
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+	if r := []rune(ev.Detail); len(r) > DetailLimit {
+		ev.Detail = string(r[:DetailLimit]) + "…"
+	}
+	detail, err := seal.SealText("prompts", ev.Detail)
+	if err != nil {
+		return err
+	}
+	ev.Detail = detail
+	line, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// ReadSince returns the events at path at or after since, in file order.
+// A missing log is empty; malformed lines are skipped. Sealed details are
+// opened, or left empty if they can't be.
+func ReadSince(path string, since time.Time) ([]Event, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var events []Event
+	var sealErr error // once a detail won't open, the rest won't either
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		var ev Event
+		if err := json.Unmarshal(sc.Bytes(), &ev); err != nil {
+			continue
+		}
+		if !ev.Time.Before(since) {
+			switch {
+			case !seal.IsSealedText(ev.Detail):
+			case sealErr != nil:
+				ev.Detail = ""
+			default:
+				ev.Detail, sealErr = seal.OpenText(ev.Detail)
+			}
+			events = append(events, ev)
+		}
+	}
+	return events, sc.Err()
+}
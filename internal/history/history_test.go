@@ -0,0 +1,101 @@
+package history
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shnupta/herd/internal/seal"
+)
+
+func TestAppendAndReadSince(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "history.jsonl")
+	base := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	for i, kind := range []string{KindPrompt, KindState, KindFile} {
+		if err := AppendTo(path, Event{Time: base.Add(time.Duration(i) * time.Hour), Kind: kind, SessionID: "s1"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	events, err := ReadSince(path, base.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 || events[0].Kind != KindState || events[1].Kind != KindFile {
+		t.Errorf("ReadSince() = %+v, want the state and file events", events)
+	}
+}
+
+func TestReadSinceSkipsBadLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	data := "not json\n" + `{"time":"2026-10-17T09:00:00Z","kind":"prompt"}` + "\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	events, err := ReadSince(path, time.Time{})
+	if err != nil || len(events) != 1 {
+		t.Errorf("ReadSince() = %v, %v; want one event", events, err)
+	}
+}
+
+func TestReadSinceMissingFile(t *testing.T) {
+	events, err := ReadSince(filepath.Join(t.TempDir(), "nope.jsonl"), time.Time{})
+	if err != nil || events != nil {
+		t.Errorf("ReadSince() = %v, %v; want empty", events, err)
+	}
+}
+
+func TestAppendCutsLongDetail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	long := strings.Repeat("é", DetailLimit+10)
+	if err := AppendTo(path, Event{Kind: KindAction, Action: ActionPrompt, Trigger: "compose", Detail: long}); err != nil {
+		t.Fatal(err)
+	}
+	events, err := ReadSince(path, time.Time{})
+	if err != nil || len(events) != 1 {
+		t.Fatalf("ReadSince() = %v, %v; want one event", events, err)
+	}
+	if got := []rune(events[0].Detail); len(got) != DetailLimit+1 || got[DetailLimit] != '…' {
+		t.Errorf("Detail has %d runes, want %d ending in …", len(got), DetailLimit+1)
+	}
+}
+
+func TestText(t *testing.T) {
+	t.Cleanup(func() { SetRecordText(false) })
+	if got := Text("rm -rf build"); got != "" {
+		t.Errorf("Text() without record_prompts = %q, want it dropped", got)
+	}
+	SetRecordText(true)
+	if got := Text("rm -rf build"); got != "rm -rf build" {
+		t.Errorf("Text() with record_prompts = %q", got)
+	}
+}
+
+func TestAppendSealsDetail(t *testing.T) {
+	t.Setenv("HERD_STORAGE_KEY", base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{5}, 32)))
+	seal.Enable([]string{"prompts"})
+	t.Cleanup(func() { seal.Enable(nil) })
+
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	if err := AppendTo(path, Event{Kind: KindAction, Action: ActionPrompt, Trigger: "compose", Detail: "the deploy password is hunter2"}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "hunter2") {
+		t.Errorf("detail written in the clear: %s", data)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0o600 {
+		t.Errorf("log mode = %v, want 0600", info.Mode().Perm())
+	}
+	events, err := ReadSince(path, time.Time{})
+	if err != nil || len(events) != 1 || events[0].Detail != "the deploy password is hunter2" {
+		t.Errorf("ReadSince() = %+v, %v; want the detail opened", events, err)
+	}
+}
@@ -0,0 +1,84 @@
+package history
+
+import "time"
+
+// Timelines divides [since, now) into slots equal spans and returns, for
+// each session ID with state events, the state it spent longest in during
+// each slot. A state lasts until the session's next state event, or until
+// now for its last; time before a session's first event in events is
+// unknown and a slot with nothing known is "".
+func Timelines(events []Event, since, now time.Time, slots int) map[string][]string {
+	if slots <= 0 || !now.After(since) {
+		return nil
+	}
+	type run struct {
+		state      string
+		start, end time.Time
+	}
+	runs := make(map[string][]run)
+	for _, ev := range events {
+		if ev.Kind != KindState || ev.SessionID == "" || ev.Time.After(now) {
+			continue
+		}
+		rs := runs[ev.SessionID]
+		if n := len(rs); n > 0 {
+			rs[n-1].end = ev.Time
+		}
+		runs[ev.SessionID] = append(rs, run{state: ev.State, start: ev.Time, end: now})
+	}
+
+	width := now.Sub(since) / time.Duration(slots)
+	out := make(map[string][]string, len(runs))
+	for id, rs := range runs {
+		timeline := make([]string, slots)
+		known := false
+		for i := range timeline {
+			from := since.Add(time.Duration(i) * width)
+			to := from.Add(width)
+			spent := make(map[string]time.Duration)
+			var best string
+			for _, r := range rs {
+				overlap := earlier(r.end, to).Sub(later(r.start, from))
+				if overlap <= 0 {
+					continue
+				}
+				spent[r.state] += overlap
+				if best == "" || spent[r.state] > spent[best] {
+					best = r.state
+				}
+			}
+			timeline[i] = best
+			known = known || best != ""
+		}
+		if known {
+			out[id] = timeline
+		}
+	}
+	return out
+}
+
+func earlier(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+func later(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+// ActionCounts returns, for each session ID herd acted on in [since, now),
+// how many KindAction events it has.
+func ActionCounts(events []Event, since, now time.Time) map[string]int {
+	out := make(map[string]int)
+	for _, ev := range events {
+		if ev.Kind == KindAction && ev.SessionID != "" && !ev.Time.Before(since) && ev.Time.Before(now) {
+			out[ev.SessionID]++
+		}
+	}
+	return out
+}
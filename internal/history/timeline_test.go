@@ -0,0 +1,43 @@
+package history
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestTimelines(t *testing.T) {
+	since := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	now := since.Add(time.Hour)
+	at := func(min int) time.Time { return since.Add(time.Duration(min) * time.Minute) }
+	events := []Event{
+		// Working from before the window until 9:25, waiting until 9:38,
+		// then idle.
+		{Time: at(-10), Kind: KindState, SessionID: "s1", State: "working"},
+		{Time: at(5), Kind: KindPrompt, SessionID: "s1"},
+		{Time: at(25), Kind: KindState, SessionID: "s1", State: "waiting"},
+		{Time: at(38), Kind: KindState, SessionID: "s1", State: "idle"},
+		// First heard of at 9:40.
+		{Time: at(40), Kind: KindState, SessionID: "s2", State: "working"},
+		// Only prompts: no timeline.
+		{Time: at(10), Kind: KindPrompt, SessionID: "s3"},
+	}
+
+	got := Timelines(events, since, now, 6) // 10-minute slots
+	want := map[string][]string{
+		"s1": {"working", "working", "working", "waiting", "idle", "idle"},
+		"s2": {"", "", "", "", "working", "working"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Timelines() = %v, want %v", got, want)
+	}
+	for id, w := range want {
+		if !slices.Equal(got[id], w) {
+			t.Errorf("%s = %v, want %v", id, got[id], w)
+		}
+	}
+
+	if Timelines(events, now, since, 6) != nil || Timelines(events, since, now, 0) != nil {
+		t.Error("an empty window or no slots should give no timelines")
+	}
+}
@@ -5,9 +5,15 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"os/exec"
+	"strings"
 	"time"
 
+	"github.com/shnupta/herd/internal/config"
+	"github.com/shnupta/herd/internal/history"
+	"github.com/shnupta/herd/internal/seal"
 	"github.com/shnupta/herd/internal/state"
+	"github.com/shnupta/herd/internal/transcript"
 )
 
 // hookInput is the JSON Claude Code sends to hook commands via stdin.
@@ -16,16 +22,35 @@ type hookInput struct {
 	ToolName  string          `json:"tool_name"`
 	ToolInput json.RawMessage `json:"tool_input"`
 	Message   string          `json:"message"` // for Notification
+	Prompt    string          `json:"prompt"`  // for UserPromptSubmit
+
+	// PermissionMode is "default", "plan", "acceptEdits" or "bypassPermissions".
+	PermissionMode string `json:"permission_mode"`
+	TranscriptPath string `json:"transcript_path"`
 }
 
+// textLimit caps the runes of the prompt and message kept in a session's
+// state.
+const textLimit = 120
+
+// recordPrompts keeps prompt and notification text in state and history,
+// from config record_prompts.
+var recordPrompts bool
+
 // Run processes a hook event. eventType is one of:
 // "UserPromptSubmit", "PreToolUse", "PostToolUse", "Stop", "Notification".
 func Run(eventType string) error {
-	return process(eventType, os.Stdin, state.Write)
+	cfg := config.Load()
+	state.SetSharded(cfg.ShardState)
+	seal.Enable(cfg.Encrypt)
+	recordPrompts = cfg.RecordPrompts
+	return process(eventType, os.Stdin, state.Write, history.Append)
 }
 
-// process handles hook event logic with injectable reader and write function for testability.
-func process(eventType string, r io.Reader, write func(state.SessionState) error) error {
+// process handles hook event logic with injectable reader, state write and
+// history record functions for testability. History is best-effort: a failed
+// record never fails the hook.
+func process(eventType string, r io.Reader, write func(state.SessionState) error, record func(history.Event) error) error {
 	var input hookInput
 	if err := json.NewDecoder(r).Decode(&input); err != nil {
 		return fmt.Errorf("decode stdin: %w", err)
@@ -41,6 +66,23 @@ func process(eventType string, r io.Reader, write func(state.SessionState) error
 		CurrentTool: input.ToolName,
 		ProjectPath: cwd(),
 		UpdatedAt:   time.Now(),
+
+		PermissionMode: input.PermissionMode,
+		TranscriptPath: input.TranscriptPath,
+	}
+	info := transcript.Read(input.TranscriptPath)
+	s.Model = info.Model
+	s.ContextTokens = info.ContextTokens
+	if recordPrompts {
+		// The transcript may not have the prompt being submitted yet.
+		prompt := info.Prompt
+		if eventType == "UserPromptSubmit" {
+			prompt = input.Prompt
+		}
+		s.Prompt = cut(prompt, textLimit)
+		if eventType == "Notification" {
+			s.Message = cut(input.Message, textLimit)
+		}
 	}
 
 	switch eventType {
@@ -64,7 +106,85 @@ func process(eventType string, r io.Reader, write func(state.SessionState) error
 		s.State = "unknown"
 	}
 
-	return write(s)
+	if err := write(s); err != nil {
+		return err
+	}
+	var branch string
+	if eventType == "UserPromptSubmit" {
+		branch = gitBranch(s.ProjectPath)
+	}
+	for _, ev := range historyEvents(eventType, input, s, branch) {
+		_ = record(ev)
+	}
+	return nil
+}
+
+// editTools are the tools whose tool_input names a file they change.
+var editTools = map[string]bool{"Edit": true, "MultiEdit": true, "Write": true, "NotebookEdit": true}
+
+// historyEvents returns the history entries for one hook event: always the
+// new state, plus the prompt or edited file when there is one.
+func historyEvents(eventType string, input hookInput, s state.SessionState, branch string) []history.Event {
+	base := history.Event{
+		Time:      s.UpdatedAt,
+		SessionID: s.SessionID,
+		TmuxPane:  s.TmuxPane,
+		Project:   s.ProjectPath,
+		Branch:    branch,
+	}
+	stateEv := base
+	stateEv.Kind = history.KindState
+	stateEv.State = s.State
+	stateEv.Tool = s.CurrentTool
+	if recordPrompts && eventType == "Notification" {
+		stateEv.Detail = input.Message
+	}
+	events := []history.Event{stateEv}
+
+	switch {
+	case eventType == "UserPromptSubmit":
+		ev := base
+		ev.Kind = history.KindPrompt
+		if recordPrompts {
+			ev.Detail = input.Prompt
+		}
+		events = append(events, ev)
+	case eventType == "PostToolUse" && editTools[input.ToolName]:
+		var ti struct {
+			FilePath     string `json:"file_path"`
+			NotebookPath string `json:"notebook_path"`
+		}
+		_ = json.Unmarshal(input.ToolInput, &ti)
+		if file := ti.FilePath + ti.NotebookPath; file != "" {
+			ev := base
+			ev.Kind = history.KindFile
+			ev.File = file
+			events = append(events, ev)
+		}
+	}
+	return events
+}
+
+// gitBranch returns dir's current branch, or "" outside a repository or on a
+// detached HEAD. A variable so tests need no repository.
+var gitBranch = func(dir string) string {
+	out, err := exec.Command("git", "-C", dir, "rev-parse", "--abbrev-ref", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	if b := strings.TrimSpace(string(out)); b != "HEAD" {
+		return b
+	}
+	return ""
+}
+
+// cut returns s on one line, cut to n runes.
+func cut(s string, n int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if r := []rune(s); len(r) > n {
+		return string(r[:n-1]) + "…"
+	}
+	return s
 }
 
 func cwd() string {
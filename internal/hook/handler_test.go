@@ -2,12 +2,18 @@ package hook
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/shnupta/herd/internal/history"
 	"github.com/shnupta/herd/internal/state"
 )
 
+// discard drops history events in tests that only check state.
+func discard(history.Event) error { return nil }
+
 func makeInput(sessionID, toolName string) string {
 	if toolName != "" {
 		return `{"session_id":"` + sessionID + `","tool_name":"` + toolName + `"}`
@@ -23,7 +29,7 @@ func captureWrite(t *testing.T, eventType, input string) state.SessionState {
 		got = s
 		called = true
 		return nil
-	})
+	}, discard)
 	if err != nil {
 		t.Fatalf("process(%q) error: %v", eventType, err)
 	}
@@ -99,7 +105,7 @@ func TestProcessEmptySessionIDIsNoOp(t *testing.T) {
 	err := process("UserPromptSubmit", strings.NewReader(`{"session_id":""}`), func(s state.SessionState) error {
 		called = true
 		return nil
-	})
+	}, discard)
 	if err != nil {
 		t.Fatalf("process() error: %v", err)
 	}
@@ -112,7 +118,7 @@ func TestProcessInvalidJSON(t *testing.T) {
 	err := process("UserPromptSubmit", strings.NewReader(`{not valid json}`), func(s state.SessionState) error {
 		t.Fatal("write should not be called for invalid JSON")
 		return nil
-	})
+	}, discard)
 	if err == nil {
 		t.Fatal("process() should return error for invalid JSON")
 	}
@@ -125,7 +131,7 @@ func TestProcessWriteError(t *testing.T) {
 	writeErr := fmt.Errorf("disk full")
 	err := process("Stop", strings.NewReader(makeInput("sess-err", "")), func(s state.SessionState) error {
 		return writeErr
-	})
+	}, discard)
 	if err == nil {
 		t.Fatal("process() should propagate write error")
 	}
@@ -152,7 +158,7 @@ func TestProcessEmptyReader(t *testing.T) {
 	err := process("Stop", strings.NewReader(""), func(s state.SessionState) error {
 		t.Fatal("write should not be called for empty input")
 		return nil
-	})
+	}, discard)
 	if err == nil {
 		t.Fatal("process() should return error for empty input")
 	}
@@ -168,3 +174,109 @@ func TestProcessToolInputPassthrough(t *testing.T) {
 		t.Errorf("CurrentTool = %q, want Write", got.CurrentTool)
 	}
 }
+
+func TestProcessPermissionMode(t *testing.T) {
+	input := `{"session_id":"sess-perm","permission_mode":"acceptEdits"}`
+	got := captureWrite(t, "Stop", input)
+	if got.PermissionMode != "acceptEdits" {
+		t.Errorf("PermissionMode = %q, want acceptEdits", got.PermissionMode)
+	}
+}
+
+func TestProcessReadsModelFromTranscript(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "transcript.jsonl")
+	line := `{"type":"assistant","message":{"model":"claude-opus-4-1"}}` + "\n"
+	if err := os.WriteFile(path, []byte(line), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	input := fmt.Sprintf(`{"session_id":"sess-model","transcript_path":%q}`, path)
+	got := captureWrite(t, "Stop", input)
+	if got.Model != "claude-opus-4-1" {
+		t.Errorf("Model = %q, want claude-opus-4-1", got.Model)
+	}
+}
+
+func TestProcessRecordsHistory(t *testing.T) {
+	defer func(orig func(string) string) { gitBranch = orig }(gitBranch)
+	gitBranch = func(string) string { return "feat/x" }
+
+	var got []history.Event
+	record := func(ev history.Event) error { got = append(got, ev); return nil }
+	write := func(state.SessionState) error { return nil }
+
+	if err := process("UserPromptSubmit", strings.NewReader(makeInput("sess-h", "")), write, record); err != nil {
+		t.Fatal(err)
+	}
+	input := `{"session_id":"sess-h","tool_name":"Edit","tool_input":{"file_path":"/code/main.go"}}`
+	if err := process("PostToolUse", strings.NewReader(input), write, record); err != nil {
+		t.Fatal(err)
+	}
+
+	var kinds []string
+	for _, ev := range got {
+		kinds = append(kinds, ev.Kind)
+	}
+	if want := "state,prompt,state,file"; strings.Join(kinds, ",") != want {
+		t.Fatalf("kinds = %v, want %s", kinds, want)
+	}
+	if got[0].Branch != "feat/x" || got[1].Branch != "feat/x" {
+		t.Errorf("prompt turn not tagged with its branch: %+v", got[:2])
+	}
+	if got[2].Branch != "" {
+		t.Errorf("branch looked up for %+v; only prompts need it", got[2])
+	}
+	if got[2].Tool != "Edit" {
+		t.Errorf("state event tool = %q, want Edit", got[2].Tool)
+	}
+	if got[0].State != "working" || got[3].File != "/code/main.go" || got[3].SessionID != "sess-h" {
+		t.Errorf("events = %+v", got)
+	}
+}
+
+func TestProcessIgnoresHistoryErrors(t *testing.T) {
+	record := func(history.Event) error { return fmt.Errorf("read-only fs") }
+	err := process("Stop", strings.NewReader(makeInput("sess-ro", "")), func(state.SessionState) error { return nil }, record)
+	if err != nil {
+		t.Errorf("process() = %v, want history errors ignored", err)
+	}
+}
+
+func TestProcessRecordsPromptsWhenEnabled(t *testing.T) {
+	defer func(orig func(string) string) { gitBranch = orig }(gitBranch)
+	gitBranch = func(string) string { return "" }
+	prompt := `{"session_id":"sess-p","prompt":"plan the\nDB migration ` + strings.Repeat("x", 200) + `"}`
+	notify := `{"session_id":"sess-p","message":"Claude needs your permission to use Bash"}`
+
+	var events []history.Event
+	record := func(ev history.Event) error { events = append(events, ev); return nil }
+	var last state.SessionState
+	write := func(s state.SessionState) error { last = s; return nil }
+
+	if err := process("UserPromptSubmit", strings.NewReader(prompt), write, record); err != nil {
+		t.Fatal(err)
+	}
+	if last.Prompt != "" || events[1].Detail != "" {
+		t.Fatalf("prompt recorded without record_prompts: %q, %q", last.Prompt, events[1].Detail)
+	}
+
+	recordPrompts = true
+	defer func() { recordPrompts = false }()
+	events = nil
+	if err := process("UserPromptSubmit", strings.NewReader(prompt), write, record); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(last.Prompt, "plan the DB migration") || len([]rune(last.Prompt)) != textLimit {
+		t.Errorf("state Prompt = %q, want it on one line and cut to %d runes", last.Prompt, textLimit)
+	}
+	if events[1].Kind != history.KindPrompt || !strings.HasPrefix(events[1].Detail, "plan the\nDB") {
+		t.Errorf("prompt event = %+v", events[1])
+	}
+
+	events = nil
+	if err := process("Notification", strings.NewReader(notify), write, record); err != nil {
+		t.Fatal(err)
+	}
+	if last.Message != "Claude needs your permission to use Bash" || events[0].Detail != last.Message {
+		t.Errorf("notification: state %+v, event %+v", last, events[0])
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 )
 
 // New hooks format: matcher is a regex string (omit to match everything).
@@ -76,3 +78,110 @@ func claudeSettingsPath() string {
 	home, _ := os.UserHomeDir()
 	return filepath.Join(home, ".claude", "settings.json")
 }
+
+// InstalledCommands returns the herd hook commands in
+// ~/.claude/settings.json, in event order.
+func InstalledCommands() ([]string, error) {
+	_, hooks, err := readHooks()
+	if err != nil {
+		return nil, err
+	}
+	var cmds []string
+	for _, event := range sortedKeys(hooks) {
+		for _, rule := range hooks[event] {
+			for _, h := range ruleCommands(rule) {
+				if isHerdCommand(h) {
+					cmds = append(cmds, h)
+				}
+			}
+		}
+	}
+	return cmds, nil
+}
+
+// Uninstall removes the herd hooks from ~/.claude/settings.json, keeping
+// any other hooks and settings. Events left without hooks are dropped, as
+// is the hooks key once empty.
+func Uninstall() error {
+	raw, hooks, err := readHooks()
+	if err != nil {
+		return err
+	}
+	for event, rules := range hooks {
+		var kept []json.RawMessage
+		for _, rule := range rules {
+			if !slices.ContainsFunc(ruleCommands(rule), isHerdCommand) {
+				kept = append(kept, rule)
+			}
+		}
+		if len(kept) == 0 {
+			delete(hooks, event)
+		} else {
+			hooks[event] = kept
+		}
+	}
+	if len(hooks) == 0 {
+		delete(raw, "hooks")
+	} else {
+		hooksJSON, err := json.Marshal(hooks)
+		if err != nil {
+			return err
+		}
+		raw["hooks"] = json.RawMessage(hooksJSON)
+	}
+	data, err := json.MarshalIndent(raw, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(claudeSettingsPath(), data, 0o644)
+}
+
+// readHooks reads settings.json, returning the whole document and its hooks
+// by event. Rules are left raw so fields herd doesn't know survive a
+// rewrite.
+func readHooks() (map[string]json.RawMessage, map[string][]json.RawMessage, error) {
+	raw := map[string]json.RawMessage{}
+	hooks := map[string][]json.RawMessage{}
+	data, err := os.ReadFile(claudeSettingsPath())
+	if os.IsNotExist(err) {
+		return raw, hooks, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, nil, fmt.Errorf("parse settings: %w", err)
+	}
+	if h, ok := raw["hooks"]; ok {
+		if err := json.Unmarshal(h, &hooks); err != nil {
+			return nil, nil, fmt.Errorf("parse hooks: %w", err)
+		}
+	}
+	return raw, hooks, nil
+}
+
+func ruleCommands(rule json.RawMessage) []string {
+	var r hookRule
+	_ = json.Unmarshal(rule, &r)
+	cmds := make([]string, len(r.Hooks))
+	for i, h := range r.Hooks {
+		cmds[i] = h.Command
+	}
+	return cmds
+}
+
+// isHerdCommand reports whether cmd is one Install wrote: a herd binary,
+// wherever it lives, running "hook <event>".
+func isHerdCommand(cmd string) bool {
+	f := strings.Fields(cmd)
+	return len(f) == 3 && f[1] == "hook" && strings.HasPrefix(filepath.Base(f[0]), "herd")
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -237,3 +238,52 @@ func TestMakeRule(t *testing.T) {
 		t.Errorf("Matcher = %q, want empty", rule.Matcher)
 	}
 }
+
+func TestUninstallKeepsOtherHooksAndSettings(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	if err := Install("/bin/herd"); err != nil {
+		t.Fatal(err)
+	}
+	// Add a hook of the user's own alongside herd's.
+	path := filepath.Join(home, ".claude", "settings.json")
+	data, _ := os.ReadFile(path)
+	var raw map[string]json.RawMessage
+	_ = json.Unmarshal(data, &raw)
+	var hooks map[string][]json.RawMessage
+	_ = json.Unmarshal(raw["hooks"], &hooks)
+	hooks["Stop"] = append(hooks["Stop"], json.RawMessage(`{"hooks":[{"type":"command","command":"say done"}],"timeout":5}`))
+	raw["hooks"], _ = json.Marshal(hooks)
+	raw["theme"] = json.RawMessage(`"dark"`)
+	data, _ = json.Marshal(raw)
+	_ = os.WriteFile(path, data, 0o644)
+
+	if cmds, err := InstalledCommands(); err != nil || len(cmds) != 5 {
+		t.Fatalf("InstalledCommands() = %v, %v; want herd's 5", cmds, err)
+	}
+	if err := Uninstall(); err != nil {
+		t.Fatalf("Uninstall() error: %v", err)
+	}
+	if cmds, _ := InstalledCommands(); len(cmds) != 0 {
+		t.Errorf("InstalledCommands() after Uninstall = %v", cmds)
+	}
+
+	data, _ = os.ReadFile(path)
+	raw = nil
+	hooks = nil
+	_ = json.Unmarshal(data, &raw)
+	_ = json.Unmarshal(raw["hooks"], &hooks)
+	if string(raw["theme"]) != `"dark"` {
+		t.Errorf("theme = %s, want it kept", raw["theme"])
+	}
+	if len(hooks) != 1 || len(hooks["Stop"]) != 1 || !strings.Contains(string(hooks["Stop"][0]), `"timeout"`) {
+		t.Errorf("hooks = %s, want only the user's Stop hook, intact", raw["hooks"])
+	}
+}
+
+func TestUninstallWithoutSettings(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if cmds, err := InstalledCommands(); err != nil || len(cmds) != 0 {
+		t.Errorf("InstalledCommands() = %v, %v; want none", cmds, err)
+	}
+}
@@ -0,0 +1,131 @@
+// Package i18n translates herd's user-facing strings. The English text is
+// the key: T looks it up in the catalog of the current locale and falls
+// back to the English when there is no translation. Catalogs are JSON
+// objects from English to translated text, built in under locales/ and
+// overridden, string by string, by ~/.herd/locales/<locale>.json.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+//go:embed locales/*.json
+var builtin embed.FS
+
+var (
+	mu      sync.RWMutex
+	locale  string
+	catalog map[string]string
+)
+
+// T returns msg in the current locale.
+func T(msg string) string {
+	mu.RLock()
+	defer mu.RUnlock()
+	if t, ok := catalog[msg]; ok && t != "" {
+		return t
+	}
+	return msg
+}
+
+// Tf translates format and formats args with it, as fmt.Sprintf. A
+// translation must keep the format's verbs, in the same order.
+func Tf(format string, args ...any) string {
+	return fmt.Sprintf(T(format), args...)
+}
+
+// Locale returns the locale in use, "" for English.
+func Locale() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return locale
+}
+
+// Resolve picks the locale from configured, or when that is empty from the
+// environment as gettext does: LC_ALL, LC_MESSAGES, then LANG. The C and
+// POSIX locales mean English.
+func Resolve(configured string) string {
+	for _, l := range []string{configured, os.Getenv("LC_ALL"), os.Getenv("LC_MESSAGES"), os.Getenv("LANG")} {
+		if l == "" {
+			continue
+		}
+		if l == "C" || l == "POSIX" || strings.HasPrefix(l, "C.") {
+			return ""
+		}
+		return l
+	}
+	return ""
+}
+
+// SetLocale loads the catalog for l, e.g. "de", "pt_BR" or "de_DE.UTF-8":
+// the language's catalog, with the region's on top of it where there is
+// one. English, or a locale without any catalog, leaves strings as they
+// are; a catalog that doesn't parse is an error.
+func SetLocale(l string) error {
+	home, _ := os.UserHomeDir()
+	return setLocale(l, filepath.Join(home, ".herd", "locales"))
+}
+
+func setLocale(l, userDir string) error {
+	merged := make(map[string]string)
+	found := ""
+	for _, name := range candidates(l) {
+		n, err := load(builtin, "locales/"+name+".json", merged)
+		if err != nil {
+			return err
+		}
+		u, err := load(os.DirFS(userDir), name+".json", merged)
+		if err != nil {
+			return err
+		}
+		if n+u > 0 {
+			found = name
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	locale, catalog = found, merged
+	return nil
+}
+
+// candidates lists the catalog names for l, least specific first.
+func candidates(l string) []string {
+	l, _, _ = strings.Cut(l, ".") // drop the encoding
+	l, _, _ = strings.Cut(l, "@") // and the modifier
+	l = strings.ReplaceAll(l, "-", "_")
+	if l == "" || l == "en" || strings.HasPrefix(l, "en_") {
+		return nil
+	}
+	if lang, _, ok := strings.Cut(l, "_"); ok {
+		return []string{lang, l}
+	}
+	return []string{l}
+}
+
+// load adds the catalog at name in fsys to into, returning how many
+// strings it had; a missing catalog has none.
+func load(fsys fs.FS, name string, into map[string]string) (int, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	var c map[string]string
+	if err := json.Unmarshal(data, &c); err != nil {
+		return 0, fmt.Errorf("locale catalog %s: %w", name, err)
+	}
+	for k, v := range c {
+		into[k] = v
+	}
+	return len(c), nil
+}
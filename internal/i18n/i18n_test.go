@@ -0,0 +1,142 @@
+package i18n
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestSetLocale(t *testing.T) {
+	t.Cleanup(func() { setLocale("", "") })
+	dir := t.TempDir()
+	write := func(name, data string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("de.json", `{"Files": "Akten", "Snippets": "Schnipsel"}`)
+	write("de_AT.json", `{"Snippets": "Schnipserl"}`)
+
+	if err := setLocale("de_AT.UTF-8", dir); err != nil {
+		t.Fatal(err)
+	}
+	if got := Locale(); got != "de_AT" {
+		t.Errorf("Locale() = %q, want de_AT", got)
+	}
+	for msg, want := range map[string]string{
+		"Files":          "Akten",              // user catalog over the builtin one
+		"Snippets":       "Schnipserl",         // region over language
+		"Rename Session": "Sitzung umbenennen", // builtin
+		"no such string": "no such string",     // English fallback
+	} {
+		if got := T(msg); got != want {
+			t.Errorf("T(%q) = %q, want %q", msg, got, want)
+		}
+	}
+	if got, want := Tf("Attention Queue (%d)", 3), "Warteschlange (3)"; got != want {
+		t.Errorf("Tf() = %q, want %q", got, want)
+	}
+
+	if err := setLocale("en_GB", dir); err != nil || Locale() != "" || T("Files") != "Files" {
+		t.Errorf("English: Locale() = %q, T(Files) = %q, %v", Locale(), T("Files"), err)
+	}
+
+	write("fr.json", `{not json`)
+	if err := setLocale("fr", dir); err == nil {
+		t.Error("a broken catalog should be an error")
+	}
+}
+
+func TestResolve(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "de_DE.UTF-8")
+	if got := Resolve(""); got != "de_DE.UTF-8" {
+		t.Errorf("Resolve from LANG = %q", got)
+	}
+	if got := Resolve("pt_BR"); got != "pt_BR" {
+		t.Errorf("configured locale should win, got %q", got)
+	}
+	t.Setenv("LC_ALL", "C.UTF-8")
+	if got := Resolve(""); got != "" {
+		t.Errorf("the C locale is English, got %q", got)
+	}
+}
+
+// TestCatalogs checks the built-in catalogs: each parses, translates
+// strings herd still uses and keeps the verbs of format strings.
+func TestCatalogs(t *testing.T) {
+	used := literals(t, "..")
+	entries, err := fs.Glob(builtin, "locales/*.json")
+	if err != nil || len(entries) == 0 {
+		t.Fatalf("no catalogs: %v", err)
+	}
+	for _, name := range entries {
+		data, _ := fs.ReadFile(builtin, name)
+		var c map[string]string
+		if err := json.Unmarshal(data, &c); err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		for k, v := range c {
+			if !used[k] {
+				t.Errorf("%s: %q is not a string herd uses", name, k)
+			}
+			if kv, vv := verbs(k), verbs(v); kv != vv {
+				t.Errorf("%s: %q has verbs %q, its translation %q", name, k, kv, vv)
+			}
+		}
+	}
+}
+
+// literals collects the string literals in the Go files under root.
+func literals(t *testing.T, root string) map[string]bool {
+	t.Helper()
+	used := make(map[string]bool)
+	fset := token.NewFileSet()
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return err
+		}
+		f, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return err
+		}
+		ast.Inspect(f, func(n ast.Node) bool {
+			if lit, ok := n.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+				if s, err := strconv.Unquote(lit.Value); err == nil {
+					used[s] = true
+				}
+			}
+			return true
+		})
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return used
+}
+
+// verbs returns the formatting verbs in s, in order.
+func verbs(s string) string {
+	var out []string
+	for i := 0; i < len(s)-1; i++ {
+		if s[i] != '%' {
+			continue
+		}
+		i++
+		if s[i] != '%' {
+			out = append(out, "%"+string(s[i]))
+		}
+	}
+	return strings.Join(out, " ")
+}
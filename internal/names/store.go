@@ -25,6 +25,10 @@ func NewStore(path string) *store.Store {
 	return s
 }
 
+// Reload re-reads the names file, picking up labels set by another herd
+// process.
+func Reload() error { return defaultStore.Load() }
+
 // Get returns the custom label for the given key, or "" if not set.
 func Get(key string) string { return defaultStore.Get(key) }
 
@@ -33,3 +37,7 @@ func Set(key, label string) error { return defaultStore.Set(key, label) }
 
 // Delete removes the custom label for the given key.
 func Delete(key string) error { return defaultStore.Delete(key) }
+
+// Rename moves the custom label from oldKey to newKey, keeping any existing
+// value already stored under newKey.
+func Rename(oldKey, newKey string) error { return defaultStore.Rename(oldKey, newKey) }
@@ -0,0 +1,70 @@
+// Package notify works out which sessions have just come to need the user,
+// as alert_states configures, and builds the terminal sequences that tell
+// them: a bell, or a desktop notification over OSC 777 or OSC 9. The TUI
+// writes them to its own terminal; the daemon to every tmux client's.
+package notify
+
+import (
+	"strings"
+
+	"github.com/shnupta/herd/internal/session"
+)
+
+// AlertsOn reports whether arriving in st is announced, given the
+// configured alert_states (plan-ready or notifying when empty).
+func AlertsOn(states []string, st session.State) bool {
+	if len(states) == 0 {
+		return st == session.StatePlanReady || st == session.StateNotifying
+	}
+	for _, name := range states {
+		if session.ParseState(name) == st && st != session.StateUnknown {
+			return true
+		}
+	}
+	return false
+}
+
+// Arrivals returns the sessions that have entered one of states since
+// before, which maps tmux panes to their earlier state. A pane before
+// doesn't know about is new rather than arriving anywhere.
+func Arrivals(before map[string]session.State, sessions []session.Session, states []string) []session.Session {
+	var out []session.Session
+	for _, s := range sessions {
+		prev, ok := before[s.TmuxPane]
+		if ok && prev != s.State && AlertsOn(states, s.State) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// Valid reports whether style is an urgent_alert style that alerts.
+func Valid(style string) bool {
+	return style == "bell" || style == "notify" || style == "osc9"
+}
+
+// Notification returns the escape sequence posting a desktop notification
+// in style: OSC 9 for "osc9", else OSC 777.
+func Notification(style, title, body string) string {
+	if style == "osc9" {
+		clean := strings.NewReplacer("\a", "", "\x1b", "").Replace
+		return "\x1b]9;" + clean(title+": "+body) + "\a"
+	}
+	clean := strings.NewReplacer(";", ",", "\a", "", "\x1b", "").Replace
+	return "\x1b]777;notify;" + clean(title) + ";" + clean(body) + "\a"
+}
+
+// Alert returns what to write to a terminal for style: a bell, after a
+// desktop notification unless style is "bell".
+func Alert(style, title, body string) string {
+	if style == "bell" {
+		return "\a"
+	}
+	return Notification(style, title, body) + "\a"
+}
+
+// Passthrough wraps seq in a DCS passthrough, for a program inside tmux to
+// reach the outer terminal (which needs allow-passthrough on).
+func Passthrough(seq string) string {
+	return "\x1bPtmux;" + strings.ReplaceAll(seq, "\x1b", "\x1b\x1b") + "\x1b\\"
+}
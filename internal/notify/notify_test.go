@@ -0,0 +1,40 @@
+package notify
+
+import (
+	"testing"
+
+	"github.com/shnupta/herd/internal/session"
+)
+
+func TestArrivals(t *testing.T) {
+	before := map[string]session.State{"%1": session.StateWorking, "%2": session.StatePlanReady, "%3": session.StateWorking}
+	sessions := []session.Session{
+		{TmuxPane: "%1", State: session.StatePlanReady}, // arrived
+		{TmuxPane: "%2", State: session.StatePlanReady}, // already there
+		{TmuxPane: "%3", State: session.StateWaiting},   // not an alert state by default
+		{TmuxPane: "%4", State: session.StateNotifying}, // new
+	}
+	got := Arrivals(before, sessions, nil)
+	if len(got) != 1 || got[0].TmuxPane != "%1" {
+		t.Errorf("Arrivals = %+v, want %%1", got)
+	}
+	if got := Arrivals(before, sessions, []string{"waiting"}); len(got) != 1 || got[0].TmuxPane != "%3" {
+		t.Errorf("Arrivals with alert_states waiting = %+v, want %%3", got)
+	}
+}
+
+func TestAlert(t *testing.T) {
+	tests := []struct{ style, want string }{
+		{"bell", "\a"},
+		{"notify", "\x1b]777;notify;herd;api, web needs you\a\a"},
+		{"osc9", "\x1b]9;herd: api; web needs you\a\a"},
+	}
+	for _, tt := range tests {
+		if got := Alert(tt.style, "herd", "api; web needs you"); got != tt.want {
+			t.Errorf("Alert(%s) = %q, want %q", tt.style, got, tt.want)
+		}
+	}
+	if Valid("") || Valid("flash") {
+		t.Error("Valid accepts a style that doesn't alert")
+	}
+}
@@ -0,0 +1,74 @@
+// Package organize sets how sessions are arranged in the sidebar — custom
+// names, groups and pins — in the stores the TUI reads, for the CLI and
+// the HTTP API. A running TUI picks the changes up on its next refresh.
+package organize
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shnupta/herd/internal/groups"
+	"github.com/shnupta/herd/internal/names"
+	"github.com/shnupta/herd/internal/sidebar"
+)
+
+// Fields are the settings Set accepts.
+var Fields = []string{"name", "group", "pin"}
+
+// Set sets field of the session with key to value: a custom name or
+// group, empty to clear it, or "on" or "off" for pin.
+func Set(key, field, value string) error {
+	switch field {
+	case "name":
+		if value == "" {
+			return names.Delete(key)
+		}
+		return names.Set(key, value)
+	case "group":
+		if value == "" {
+			return groups.Delete(key)
+		}
+		return groups.Set(key, value)
+	case "pin":
+		on, err := parseSwitch(value)
+		if err != nil {
+			return err
+		}
+		return pin(sidebar.Load, sidebar.Save, key, on)
+	}
+	return fmt.Errorf("unknown setting %q: want one of %s", field, strings.Join(Fields, ", "))
+}
+
+// parseSwitch reads an on/off value; empty means on.
+func parseSwitch(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "", "on", "true", "yes":
+		return true, nil
+	case "off", "false", "no":
+		return false, nil
+	}
+	return false, fmt.Errorf("pin takes on or off, not %q", value)
+}
+
+// pin pins the session with key below those pinned already, or unpins it,
+// in the sidebar state load reads and save writes.
+func pin(load func() (*sidebar.State, error), save func(*sidebar.State) error, key string, on bool) error {
+	st, err := load()
+	if err != nil {
+		return err
+	}
+	_, pinned := st.Pinned[key]
+	switch {
+	case on && !pinned:
+		last := 0
+		for _, order := range st.Pinned {
+			last = max(last, order)
+		}
+		st.Pinned[key] = last + 1
+	case !on && pinned:
+		delete(st.Pinned, key)
+	default:
+		return nil
+	}
+	return save(st)
+}
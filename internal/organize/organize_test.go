@@ -0,0 +1,40 @@
+package organize
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/shnupta/herd/internal/sidebar"
+)
+
+func TestPin(t *testing.T) {
+	store := sidebar.NewStore(filepath.Join(t.TempDir(), "sidebar.json"))
+	set := func(key string, on bool) {
+		t.Helper()
+		if err := pin(store.Load, store.Save, key, on); err != nil {
+			t.Fatal(err)
+		}
+	}
+	set("session:a", true)
+	set("session:b", true)
+	set("session:a", true) // already pinned: keeps its place
+	st, _ := store.Load()
+	if st.Pinned["session:a"] != 1 || st.Pinned["session:b"] != 2 {
+		t.Errorf("pinned = %v, want a then b", st.Pinned)
+	}
+
+	set("session:a", false)
+	st, _ = store.Load()
+	if _, ok := st.Pinned["session:a"]; ok || len(st.Pinned) != 1 {
+		t.Errorf("pinned = %v after unpinning a", st.Pinned)
+	}
+}
+
+func TestSetRejects(t *testing.T) {
+	if err := Set("session:a", "colour", "red"); err == nil {
+		t.Error("an unknown setting should be an error")
+	}
+	if err := Set("session:a", "pin", "maybe"); err == nil {
+		t.Error("pin should take only on or off")
+	}
+}
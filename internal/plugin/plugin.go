@@ -0,0 +1,176 @@
+// Package plugin runs external executables from ~/.herd/plugins that extend
+// herd with sidebar badges, overlay panels and key-bound actions.
+//
+// Each call starts the plugin afresh, writes one JSON Request to its stdin and
+// reads one JSON Response from its stdout, so a plugin can be a short script
+// in any language. A plugin that fails or answers with invalid JSON is
+// skipped rather than breaking the TUI.
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// callTimeout bounds a single plugin invocation.
+const callTimeout = 5 * time.Second
+
+// Request types sent to plugins.
+const (
+	Describe = "describe" // answer with a Manifest
+	Badges   = "badges"   // answer with badges for Request.Sessions
+	Run      = "action"   // run Request.Action against Request.Session
+)
+
+// Session is the view of a session passed to plugins.
+type Session struct {
+	Key         string `json:"key"`
+	ID          string `json:"session_id,omitempty"`
+	TmuxPane    string `json:"tmux_pane"`
+	ProjectPath string `json:"project_path"`
+	GitBranch   string `json:"git_branch,omitempty"`
+	State       string `json:"state"`
+}
+
+// Request is written to a plugin's stdin.
+type Request struct {
+	Type     string    `json:"type"`
+	Action   string    `json:"action,omitempty"`
+	Session  *Session  `json:"session,omitempty"`
+	Sessions []Session `json:"sessions,omitempty"`
+}
+
+// Action is a plugin action bound to a normal-mode key.
+type Action struct {
+	ID    string `json:"id"`
+	Label string `json:"label"`
+	Key   string `json:"key,omitempty"`
+}
+
+// Manifest is a plugin's answer to a describe request.
+type Manifest struct {
+	Name    string   `json:"name"`
+	Badges  bool     `json:"badges,omitempty"` // wants badges requests on each session refresh
+	Actions []Action `json:"actions,omitempty"`
+}
+
+// Badge is a plugin-supplied sidebar badge.
+type Badge struct {
+	Text  string `json:"text"`
+	Color string `json:"color,omitempty"`
+}
+
+// Response is read from a plugin's stdout. Which fields matter depends on
+// the request type.
+type Response struct {
+	Manifest
+
+	// Badges maps session key → badges, for badges requests.
+	SessionBadges map[string][]Badge `json:"session_badges,omitempty"`
+
+	// For action requests: Panel text is shown in an overlay titled
+	// PanelTitle, and Prompt is sent to the session's Claude.
+	PanelTitle string `json:"panel_title,omitempty"`
+	Panel      string `json:"panel,omitempty"`
+	Prompt     string `json:"prompt,omitempty"`
+
+	// Error is shown to the user instead of any other result.
+	Error string `json:"error,omitempty"`
+}
+
+// Plugin is a described plugin executable.
+type Plugin struct {
+	Path string
+	Manifest
+}
+
+// Dir returns the default plugin directory, ~/.herd/plugins.
+func Dir() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".herd", "plugins")
+}
+
+// Load describes every executable file in dir, in name order. A missing dir
+// yields no plugins; plugins that fail to describe themselves are returned as
+// errors alongside the ones that loaded.
+func Load(dir string) ([]*Plugin, []error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, nil
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	var plugins []*Plugin
+	var errs []error
+	for _, e := range entries {
+		if e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil || info.Mode()&0o111 == 0 {
+			continue
+		}
+		p := &Plugin{Path: filepath.Join(dir, e.Name())}
+		resp, err := p.Call(Request{Type: Describe})
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		p.Manifest = resp.Manifest
+		if p.Name == "" {
+			p.Name = e.Name()
+		}
+		plugins = append(plugins, p)
+	}
+	return plugins, errs
+}
+
+// Call runs the plugin with req on stdin and decodes its response.
+func (p *Plugin) Call(req Request) (Response, error) {
+	var resp Response
+	in, err := json.Marshal(req)
+	if err != nil {
+		return resp, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), callTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, p.Path)
+	cmd.Stdin = bytes.NewReader(in)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return resp, fmt.Errorf("plugin %s: %w: %s", filepath.Base(p.Path), err, msg)
+		}
+		return resp, fmt.Errorf("plugin %s: %w", filepath.Base(p.Path), err)
+	}
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return resp, fmt.Errorf("plugin %s: invalid response: %w", filepath.Base(p.Path), err)
+	}
+	if resp.Error != "" {
+		return resp, fmt.Errorf("plugin %s: %s", filepath.Base(p.Path), resp.Error)
+	}
+	return resp, nil
+}
+
+// ActionForKey returns the first plugin action bound to key.
+func ActionForKey(plugins []*Plugin, key string) (*Plugin, Action, bool) {
+	for _, p := range plugins {
+		for _, a := range p.Actions {
+			if a.Key != "" && a.Key == key {
+				return p, a, true
+			}
+		}
+	}
+	return nil, Action{}, false
+}
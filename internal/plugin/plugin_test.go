@@ -0,0 +1,88 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writePlugin writes an executable shell script plugin into dir.
+func writePlugin(t *testing.T, dir, name, script string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// echoPlugin answers describe with a manifest and action requests with the
+// request it was given, so tests can check what herd sent.
+const echoPlugin = `req=$(cat)
+case "$req" in
+  *'"type":"describe"'*) echo '{"name":"echo","badges":true,"actions":[{"id":"hi","label":"Say hi","key":"ctrl+y"}]}' ;;
+  *'"type":"badges"'*) echo '{"session_badges":{"pane:%1":[{"text":"CI ok","color":"green"}]}}' ;;
+  *) printf '{"panel_title":"echo","panel":%s}' "$(printf '%s' "$req" | sed 's/"/\\"/g; s/^/"/; s/$/"/')" ;;
+esac
+`
+
+func TestLoadDescribesExecutables(t *testing.T) {
+	dir := t.TempDir()
+	writePlugin(t, dir, "echo", echoPlugin)
+	writePlugin(t, dir, "broken", "echo not json\n")
+	if err := os.WriteFile(filepath.Join(dir, "README"), []byte("not a plugin"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	plugins, errs := Load(dir)
+	if len(plugins) != 1 || plugins[0].Name != "echo" {
+		t.Fatalf("Load() = %+v, want only the echo plugin", plugins)
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "broken") {
+		t.Errorf("errs = %v, want one error naming broken", errs)
+	}
+	if !plugins[0].Badges || len(plugins[0].Actions) != 1 {
+		t.Errorf("manifest = %+v, want badges and one action", plugins[0].Manifest)
+	}
+}
+
+func TestLoadMissingDir(t *testing.T) {
+	plugins, errs := Load(filepath.Join(t.TempDir(), "nope"))
+	if plugins != nil || errs != nil {
+		t.Errorf("Load() = %v, %v; want nothing", plugins, errs)
+	}
+}
+
+func TestCallSendsRequest(t *testing.T) {
+	dir := t.TempDir()
+	writePlugin(t, dir, "echo", echoPlugin)
+	plugins, _ := Load(dir)
+
+	p, a, ok := ActionForKey(plugins, "ctrl+y")
+	if !ok {
+		t.Fatal("ActionForKey(ctrl+y) not found")
+	}
+	resp, err := p.Call(Request{Type: Run, Action: a.ID, Session: &Session{Key: "pane:%1", TmuxPane: "%1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(resp.Panel, `"action":"hi"`) || !strings.Contains(resp.Panel, `"tmux_pane":"%1"`) {
+		t.Errorf("Panel = %q, want the echoed action request", resp.Panel)
+	}
+
+	resp, err = p.Call(Request{Type: Badges, Sessions: []Session{{Key: "pane:%1"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := resp.SessionBadges["pane:%1"]; len(b) != 1 || b[0].Text != "CI ok" {
+		t.Errorf("SessionBadges = %+v, want CI ok", resp.SessionBadges)
+	}
+}
+
+func TestCallSurfacesPluginError(t *testing.T) {
+	dir := t.TempDir()
+	writePlugin(t, dir, "cranky", `echo '{"error":"no token"}'`+"\n")
+	p := &Plugin{Path: filepath.Join(dir, "cranky")}
+	if _, err := p.Call(Request{Type: Run}); err == nil || !strings.Contains(err.Error(), "no token") {
+		t.Errorf("Call() error = %v, want no token", err)
+	}
+}
@@ -0,0 +1,111 @@
+// Package progress reads how far a long-running command has got from its
+// output: counts such as "12/40 tests" or "[3/8]", percentages and
+// text progress bars like npm's.
+package progress
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// tail is how many of the output's last non-blank lines are searched,
+// enough to see past Claude's prompt box to the tool's output; a count
+// further up belongs to a command that has moved on.
+const tail = 12
+
+// Progress is how far a command has got: Done of Total when it counts
+// items, otherwise Percent.
+type Progress struct {
+	Done, Total int
+	Percent     int
+}
+
+// String renders p compactly, e.g. "12/40" or "42%".
+func (p Progress) String() string {
+	if p.Total > 0 {
+		return fmt.Sprintf("%d/%d", p.Done, p.Total)
+	}
+	return fmt.Sprintf("%d%%", p.Percent)
+}
+
+var (
+	// "12/40 tests", "tests 12/40", "[3/8]", "(3/8)"
+	countRe = regexp.MustCompile(`(?i)(?:[\[(](\d+)\s*/\s*(\d+)[\])])|(?:\b(\d+)\s*/\s*(\d+)\s+(?:tests?|specs?|files?|packages?|steps?|tasks?|modules?|chunks?|items?|suites?|crates?)\b)|(?:\b(?:tests?|specs?|files?|packages?|steps?|tasks?|modules?|suites?|crates?)\s*:?\s*(\d+)\s*/\s*(\d+)\b)`)
+	// jest and vitest: "Tests: 3 passed, 10 total"
+	totalRe = regexp.MustCompile(`(?i)(\d+)\s+passed,\s+(?:\d+\s+\w+,\s+)*(\d+)\s+total`)
+	// "42%", "42.5%"
+	percentRe = regexp.MustCompile(`(?:^|[^\d.])(\d{1,3})(?:\.\d+)?\s?%`)
+	// "[#####-----]", "[=====>    ]", npm's "⸨████░░░░⸩"
+	barRe = regexp.MustCompile(`[\[|▕⸨]([#=█▓■━]*>?)([-\s.░▒·─]*)[\]|▏⸩]`)
+	// "█████░░░░░" without brackets
+	blockRe = regexp.MustCompile(`([█▓■]+)([░▒]+)`)
+	// Claude's own status line, whose percentage is the context left
+	claudeRe = regexp.MustCompile(`(?i)auto-compact|context left`)
+)
+
+// Parse returns the progress shown nearest the end of output, looking at
+// its last few lines. ok is false when none of them shows any.
+func Parse(output string) (p Progress, ok bool) {
+	lines := strings.Split(output, "\n")
+	seen := 0
+	for i := len(lines) - 1; i >= 0 && seen < tail; i-- {
+		line := strings.TrimSpace(lines[i])
+		if line == "" {
+			continue
+		}
+		seen++
+		if claudeRe.MatchString(line) {
+			continue
+		}
+		if p, ok := parseLine(line); ok {
+			return p, true
+		}
+	}
+	return Progress{}, false
+}
+
+// parseLine finds progress in one line, preferring counts to percentages
+// to bars.
+func parseLine(line string) (Progress, bool) {
+	if m := totalRe.FindStringSubmatch(line); m != nil {
+		if p, ok := count(m[1], m[2]); ok {
+			return p, true
+		}
+	}
+	if m := countRe.FindStringSubmatch(line); m != nil {
+		for i := 1; i+1 < len(m); i += 2 {
+			if m[i] != "" {
+				if p, ok := count(m[i], m[i+1]); ok {
+					return p, true
+				}
+			}
+		}
+	}
+	if m := percentRe.FindStringSubmatch(line); m != nil {
+		if n, _ := strconv.Atoi(m[1]); n <= 100 {
+			return Progress{Percent: n}, true
+		}
+	}
+	for _, re := range []*regexp.Regexp{barRe, blockRe} {
+		if m := re.FindStringSubmatch(line); m != nil {
+			done := len([]rune(m[1]))
+			total := done + len([]rune(m[2]))
+			if total >= 5 && done > 0 {
+				return Progress{Percent: done * 100 / total}, true
+			}
+		}
+	}
+	return Progress{}, false
+}
+
+// count is done of total, when that makes sense.
+func count(done, total string) (Progress, bool) {
+	d, err1 := strconv.Atoi(done)
+	t, err2 := strconv.Atoi(total)
+	if err1 != nil || err2 != nil || t == 0 || d > t {
+		return Progress{}, false
+	}
+	return Progress{Done: d, Total: t, Percent: d * 100 / t}, true
+}
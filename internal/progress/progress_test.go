@@ -0,0 +1,44 @@
+package progress
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		output string
+		want   string // "" for none
+	}{
+		{"● Bash(go test ./...)\n  ⎿  12/40 tests", "12/40"},
+		{"[3/8] Linking CXX executable app", "3/8"},
+		{"Tests: 3 failed, 14 passed, 20 total", "14/20"},
+		{"Downloading 42.5% of 120MB", "42%"},
+		{"[#####-----] fetchMetadata", "50%"},
+		{"⸨████████░░░░░░░░░░⸩ ⠙ idealTree", "44%"},
+		{"███████░░░ 70 MB", "70%"},
+		{"Compiling 3/8 crates", "3/8"},
+		{"50%\nfinished, next step\n\n", "50%"},
+		{"released on 1/2/2024", ""},
+		{"Context left until auto-compact: 9%", ""},
+		{"nothing to see", ""},
+		{"[9/3] odd", ""},
+	}
+	for _, tt := range tests {
+		p, ok := Parse(tt.output)
+		got := ""
+		if ok {
+			got = p.String()
+		}
+		if got != tt.want {
+			t.Errorf("Parse(%q) = %q, want %q", tt.output, got, tt.want)
+		}
+	}
+}
+
+func TestParseLooksOnlyAtTheEnd(t *testing.T) {
+	out := "[1/4] building\n"
+	for i := 0; i < tail; i++ {
+		out += "more output\n"
+	}
+	if p, ok := Parse(out); ok {
+		t.Errorf("Parse found %v far up the output", p)
+	}
+}
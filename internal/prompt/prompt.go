@@ -0,0 +1,26 @@
+// Package prompt renders user-defined prompt templates.
+package prompt
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// Render executes text as a Go text/template with vars available as
+// {{.name}}. Referencing a variable that wasn't supplied is an error, so a
+// caller that forgets one never sends a half-filled prompt.
+func Render(text string, vars map[string]string) (string, error) {
+	t, err := template.New("prompt").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("parsing template: %w", err)
+	}
+	if vars == nil {
+		vars = map[string]string{}
+	}
+	var sb strings.Builder
+	if err := t.Execute(&sb, vars); err != nil {
+		return "", fmt.Errorf("rendering template: %w", err)
+	}
+	return sb.String(), nil
+}
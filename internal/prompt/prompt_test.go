@@ -0,0 +1,31 @@
+package prompt
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	got, err := Render("Fix the failing build on {{.branch}}: {{.url}}", map[string]string{
+		"branch": "main",
+		"url":    "https://ci/1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Fix the failing build on main: https://ci/1"; got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+}
+
+func TestRenderMissingVar(t *testing.T) {
+	if _, err := Render("Fix {{.branch}}", nil); err == nil || !strings.Contains(err.Error(), "branch") {
+		t.Errorf("Render() error = %v, want missing branch", err)
+	}
+}
+
+func TestRenderBadTemplate(t *testing.T) {
+	if _, err := Render("{{.branch", nil); err == nil {
+		t.Error("Render() should reject an unterminated action")
+	}
+}
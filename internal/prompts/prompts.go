@@ -0,0 +1,110 @@
+// Package prompts keeps the history of prompts herd has sent to each
+// session, keyed by session key, in ~/.herd/prompts.json.
+package prompts
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/shnupta/herd/internal/seal"
+)
+
+// Limit is how many prompts are kept per session; older ones are dropped.
+const Limit = 100
+
+// Entry is one sent prompt. Source says what sent it: "reply", "action",
+// "review", "unblock", "plugin" or "command".
+type Entry struct {
+	Text   string    `json:"text"`
+	Source string    `json:"source,omitempty"`
+	SentAt time.Time `json:"sent_at"`
+}
+
+// Store persists prompt history for a specific file path.
+type Store struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewStore creates a Store backed by the given file path.
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// DefaultPath returns ~/.herd/prompts.json.
+func DefaultPath() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".herd", "prompts.json")
+}
+
+// Get returns the prompts sent to key, oldest first.
+func (s *Store) Get(key string) []Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	all, _ := s.read()
+	return all[key]
+}
+
+// Add records e as the newest prompt sent to key. An earlier entry with the
+// same text is dropped, so resending a prompt moves it to the end.
+func (s *Store) Add(key string, e Entry) error {
+	return s.update(func(all map[string][]Entry) {
+		list := all[key][:0:0]
+		for _, have := range all[key] {
+			if have.Text != e.Text {
+				list = append(list, have)
+			}
+		}
+		list = append(list, e)
+		if len(list) > Limit {
+			list = list[len(list)-Limit:]
+		}
+		all[key] = list
+	})
+}
+
+// Rename moves oldKey's history to newKey, after any newKey already has.
+func (s *Store) Rename(oldKey, newKey string) error {
+	return s.update(func(all map[string][]Entry) {
+		if list, ok := all[oldKey]; ok {
+			all[newKey] = append(all[newKey], list...)
+			delete(all, oldKey)
+		}
+	})
+}
+
+func (s *Store) update(fn func(map[string][]Entry)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	all, err := s.read()
+	if err != nil {
+		return err
+	}
+	fn(all)
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return err
+	}
+	return seal.WriteFile("prompts", s.path, data, 0o644)
+}
+
+func (s *Store) read() (map[string][]Entry, error) {
+	all := make(map[string][]Entry)
+	data, err := seal.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return all, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+	return all, nil
+}
@@ -0,0 +1,43 @@
+package prompts
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+func TestAddMovesRepeatsToTheEnd(t *testing.T) {
+	s := NewStore(filepath.Join(t.TempDir(), "prompts.json"))
+	for _, text := range []string{"one", "two", "one"} {
+		if err := s.Add("session:a", Entry{Text: text}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got := NewStore(s.path).Get("session:a")
+	if len(got) != 2 || got[0].Text != "two" || got[1].Text != "one" {
+		t.Errorf("history = %+v, want two then one", got)
+	}
+}
+
+func TestAddKeepsTheNewest(t *testing.T) {
+	s := NewStore(filepath.Join(t.TempDir(), "prompts.json"))
+	for i := 0; i < Limit+5; i++ {
+		_ = s.Add("session:a", Entry{Text: fmt.Sprint(i)})
+	}
+	got := s.Get("session:a")
+	if len(got) != Limit || got[0].Text != "5" {
+		t.Errorf("kept %d entries starting at %q, want %d from 5", len(got), got[0].Text, Limit)
+	}
+}
+
+func TestRenameAppends(t *testing.T) {
+	s := NewStore(filepath.Join(t.TempDir(), "prompts.json"))
+	_ = s.Add("session:a", Entry{Text: "existing"})
+	_ = s.Add("pane:%1", Entry{Text: "old"})
+	if err := s.Rename("pane:%1", "session:a"); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Get("session:a"); len(got) != 2 || got[1].Text != "old" || len(s.Get("pane:%1")) != 0 {
+		t.Errorf("after rename = %+v", got)
+	}
+}
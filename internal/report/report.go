@@ -0,0 +1,259 @@
+// Package report summarises the history log per project.
+package report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/shnupta/herd/internal/history"
+)
+
+// Project is one project's activity over the report window.
+type Project struct {
+	Path     string
+	Sessions int
+	Working  time.Duration
+	Prompts  int
+	Files    int // distinct files edited
+	Reviews  int
+}
+
+// Run is one stretch of a session in the working state.
+type Run struct {
+	SessionID  string
+	Project    string
+	Branch     string // from the session's latest prompt; "" if unknown
+	Start, End time.Time
+}
+
+// workingRuns pairs each session's move into "working" with its next other
+// state. A session still working at the end of the log runs until now.
+func workingRuns(events []history.Event, now time.Time) []Run {
+	var runs []Run
+	open := make(map[string]Run)      // session → run in progress
+	branch := make(map[string]string) // session → branch of its latest prompt
+	for _, ev := range sortedByTime(events) {
+		if ev.Branch != "" {
+			branch[ev.SessionID] = ev.Branch
+		}
+		if ev.Kind != history.KindState {
+			continue
+		}
+		r, running := open[ev.SessionID]
+		switch {
+		case ev.State == "working" && !running:
+			open[ev.SessionID] = Run{SessionID: ev.SessionID, Project: ev.Project, Branch: branch[ev.SessionID], Start: ev.Time}
+		case ev.State != "working" && running:
+			r.End = ev.Time
+			runs = append(runs, r)
+			delete(open, ev.SessionID)
+		}
+	}
+	for _, r := range open {
+		r.End = now
+		runs = append(runs, r)
+	}
+	return runs
+}
+
+func sortedByTime(events []history.Event) []history.Event {
+	sorted := append([]history.Event(nil), events...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Time.Before(sorted[j].Time) })
+	return sorted
+}
+
+// Summarize aggregates events per project, busiest first.
+func Summarize(events []history.Event, now time.Time) []Project {
+	type acc struct {
+		Project
+		sessions map[string]bool
+		files    map[string]bool
+	}
+	byPath := make(map[string]*acc)
+	get := func(path string) *acc {
+		a, ok := byPath[path]
+		if !ok {
+			a = &acc{Project: Project{Path: path}, sessions: map[string]bool{}, files: map[string]bool{}}
+			byPath[path] = a
+		}
+		return a
+	}
+
+	for _, ev := range events {
+		a := get(ev.Project)
+		if ev.SessionID != "" {
+			a.sessions[ev.SessionID] = true
+		}
+		switch ev.Kind {
+		case history.KindPrompt:
+			a.Prompts++
+		case history.KindFile:
+			a.files[ev.File] = true
+		case history.KindReview:
+			a.Reviews++
+		}
+	}
+	for _, r := range workingRuns(events, now) {
+		get(r.Project).Working += r.End.Sub(r.Start)
+	}
+
+	out := make([]Project, 0, len(byPath))
+	for _, a := range byPath {
+		a.Sessions = len(a.sessions)
+		a.Files = len(a.files)
+		out = append(out, a.Project)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Working != out[j].Working {
+			return out[i].Working > out[j].Working
+		}
+		return out[i].Path < out[j].Path
+	})
+	return out
+}
+
+// BranchTime is the working time billed to one project and branch.
+type BranchTime struct {
+	Project string
+	Branch  string
+	Working time.Duration
+}
+
+// TimeByBranch totals working time per project and branch between since and
+// now, clipping runs that straddle since. Rows are sorted by project, then
+// branch.
+func TimeByBranch(events []history.Event, since, now time.Time) []BranchTime {
+	type key struct{ project, branch string }
+	totals := make(map[key]time.Duration)
+	for _, r := range workingRuns(events, now) {
+		start := r.Start
+		if start.Before(since) {
+			start = since
+		}
+		if r.End.After(start) {
+			totals[key{r.Project, r.Branch}] += r.End.Sub(start)
+		}
+	}
+	out := make([]BranchTime, 0, len(totals))
+	for k, d := range totals {
+		out = append(out, BranchTime{Project: k.project, Branch: k.branch, Working: d})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Project != out[j].Project {
+			return out[i].Project < out[j].Project
+		}
+		return out[i].Branch < out[j].Branch
+	})
+	return out
+}
+
+// WriteCSV writes rows as CSV with a header: project, branch, working
+// seconds and working hours to two decimal places.
+func WriteCSV(w io.Writer, rows []BranchTime) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"project", "branch", "working_seconds", "working_hours"})
+	for _, r := range rows {
+		secs := int64(r.Working.Round(time.Second) / time.Second)
+		_ = cw.Write([]string{r.Project, r.Branch, strconv.FormatInt(secs, 10), strconv.FormatFloat(r.Working.Hours(), 'f', 2, 64)})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Write prints projects as a plain-text report for the window starting at
+// since.
+func Write(w io.Writer, projects []Project, since time.Time) error {
+	fmt.Fprintf(w, "herd report since %s\n", since.Format("Mon 2 Jan 15:04"))
+	if len(projects) == 0 {
+		_, err := fmt.Fprintln(w, "\nNo activity recorded. History is written by herd's hooks ('herd install').")
+		return err
+	}
+	home, _ := os.UserHomeDir()
+	for _, p := range projects {
+		path := p.Path
+		if home != "" && strings.HasPrefix(path, home) {
+			path = "~" + path[len(home):]
+		}
+		if path == "" {
+			path = "(unknown project)"
+		}
+		fmt.Fprintf(w, "\n%s\n", path)
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(tw, "  sessions\t%d\n", p.Sessions)
+		fmt.Fprintf(tw, "  working\t%s\n", FormatDuration(p.Working))
+		fmt.Fprintf(tw, "  prompts\t%d\n", p.Prompts)
+		fmt.Fprintf(tw, "  files changed\t%d\n", p.Files)
+		fmt.Fprintf(tw, "  reviews\t%d\n", p.Reviews)
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteActions lists, oldest first, what herd did to sessions: one line per
+// KindAction event with its time, project, pane, action, the user action or
+// automation that triggered it and what was sent.
+func WriteActions(w io.Writer, events []history.Event, since time.Time) error {
+	fmt.Fprintf(w, "herd actions since %s\n\n", since.Format("Mon 2 Jan 15:04"))
+	var n int
+	home, _ := os.UserHomeDir()
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, ev := range sortedByTime(events) {
+		if ev.Kind != history.KindAction || ev.Time.Before(since) {
+			continue
+		}
+		n++
+		project := ev.Project
+		if home != "" && strings.HasPrefix(project, home) {
+			project = "~" + project[len(home):]
+		}
+		if project == "" {
+			project = "-"
+		}
+		detail := strings.Join(strings.Fields(ev.Detail), " ")
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", ev.Time.Local().Format("Jan 2 15:04:05"), project, ev.TmuxPane, ev.Action, ev.Trigger, detail)
+	}
+	if n == 0 {
+		_, err := fmt.Fprintln(w, "No actions recorded.")
+		return err
+	}
+	return tw.Flush()
+}
+
+// FormatDuration renders d as e.g. "2h14m" or "45s".
+func FormatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	switch {
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	case d >= time.Minute:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	default:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	}
+}
+
+// ParseSince parses a report window such as "24h", "90m" or "7d" (days are
+// not understood by time.ParseDuration).
+func ParseSince(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		var n int
+		if _, err := fmt.Sscanf(days, "%d", &n); err != nil || n <= 0 || fmt.Sprint(n) != days {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return d, nil
+}
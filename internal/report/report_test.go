@@ -0,0 +1,133 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shnupta/herd/internal/history"
+)
+
+func TestSummarize(t *testing.T) {
+	t0 := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	at := func(m int) time.Time { return t0.Add(time.Duration(m) * time.Minute) }
+	events := []history.Event{
+		{Time: at(0), Kind: history.KindPrompt, SessionID: "a", Project: "/api"},
+		{Time: at(0), Kind: history.KindState, SessionID: "a", Project: "/api", State: "working"},
+		{Time: at(5), Kind: history.KindState, SessionID: "a", Project: "/api", State: "working"},
+		{Time: at(6), Kind: history.KindFile, SessionID: "a", Project: "/api", File: "/api/x.go"},
+		{Time: at(7), Kind: history.KindFile, SessionID: "a", Project: "/api", File: "/api/x.go"},
+		{Time: at(30), Kind: history.KindState, SessionID: "a", Project: "/api", State: "waiting"},
+		{Time: at(31), Kind: history.KindReview, SessionID: "a", Project: "/api"},
+		// Still working at the end of the log.
+		{Time: at(50), Kind: history.KindState, SessionID: "b", Project: "/web", State: "working"},
+	}
+
+	got := Summarize(events, at(60))
+	want := []Project{
+		{Path: "/api", Sessions: 1, Working: 30 * time.Minute, Prompts: 1, Files: 1, Reviews: 1},
+		{Path: "/web", Sessions: 1, Working: 10 * time.Minute},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Summarize() = %+v", got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("project %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	var buf bytes.Buffer
+	since := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	if err := Write(&buf, []Project{{Path: "/api", Sessions: 2, Working: 134 * time.Minute, Files: 3}}, since); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{"since Fri 16 Oct 09:00", "/api", "working        2h14m", "files changed  3"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestWriteActions(t *testing.T) {
+	since := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	events := []history.Event{
+		{Time: since.Add(2 * time.Minute), Kind: history.KindAction, Project: "/api", TmuxPane: "%1", Action: history.ActionKill, Trigger: "kill key"},
+		{Time: since.Add(time.Minute), Kind: history.KindAction, Project: "/api", TmuxPane: "%1", Action: history.ActionPrompt, Trigger: "compose", Detail: "fix\nthe build"},
+		{Time: since.Add(3 * time.Minute), Kind: history.KindPrompt, Project: "/api"},
+		{Time: since.Add(-time.Minute), Kind: history.KindAction, Action: history.ActionResize},
+	}
+	var buf bytes.Buffer
+	if err := WriteActions(&buf, events, since); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 || !strings.Contains(lines[2], "prompt  compose   fix the build") || !strings.Contains(lines[3], "kill    kill key") {
+		t.Errorf("WriteActions() =\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := WriteActions(&buf, nil, since); err != nil || !strings.Contains(buf.String(), "No actions recorded.") {
+		t.Errorf("WriteActions(nil) = %q, %v", buf.String(), err)
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	tests := map[string]time.Duration{"24h": 24 * time.Hour, "90m": 90 * time.Minute, "7d": 7 * 24 * time.Hour}
+	for in, want := range tests {
+		if got, err := ParseSince(in); err != nil || got != want {
+			t.Errorf("ParseSince(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	for _, bad := range []string{"", "d", "-1h", "1.5d", "soon"} {
+		if _, err := ParseSince(bad); err == nil {
+			t.Errorf("ParseSince(%q) should fail", bad)
+		}
+	}
+}
+
+func TestTimeByBranch(t *testing.T) {
+	t0 := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	at := func(m int) time.Time { return t0.Add(time.Duration(m) * time.Minute) }
+	events := []history.Event{
+		{Time: at(0), Kind: history.KindState, SessionID: "a", Project: "/api", State: "working", Branch: "main"},
+		{Time: at(0), Kind: history.KindPrompt, SessionID: "a", Project: "/api", Branch: "main"},
+		{Time: at(20), Kind: history.KindState, SessionID: "a", Project: "/api", State: "waiting"},
+		{Time: at(30), Kind: history.KindState, SessionID: "a", Project: "/api", State: "working", Branch: "feat/x"},
+		{Time: at(45), Kind: history.KindState, SessionID: "a", Project: "/api", State: "waiting"},
+		// No prompt seen: the branch is unknown.
+		{Time: at(40), Kind: history.KindState, SessionID: "b", Project: "/web", State: "working"},
+	}
+
+	// The window opens at minute 10, clipping the first run to 10 minutes.
+	got := TimeByBranch(events, at(10), at(50))
+	want := []BranchTime{
+		{Project: "/api", Branch: "feat/x", Working: 15 * time.Minute},
+		{Project: "/api", Branch: "main", Working: 10 * time.Minute},
+		{Project: "/web", Working: 10 * time.Minute},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("TimeByBranch() = %+v", got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("row %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	rows := []BranchTime{{Project: "/api", Branch: "main", Working: 90 * time.Minute}, {Project: "/a,b", Working: 30 * time.Second}}
+	if err := WriteCSV(&buf, rows); err != nil {
+		t.Fatal(err)
+	}
+	want := "project,branch,working_seconds,working_hours\n/api,main,5400,1.50\n\"/a,b\",,30,0.01\n"
+	if buf.String() != want {
+		t.Errorf("CSV =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
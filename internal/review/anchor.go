@@ -0,0 +1,151 @@
+package review
+
+import (
+	"github.com/shnupta/herd/internal/diff"
+)
+
+// anchorContext is how many lines either side of a comment are recorded to
+// tell apart identical lines when re-anchoring.
+const anchorContext = 3
+
+// patchLine renders a diff line as it appears in the patch.
+func patchLine(l diff.Line) string {
+	switch l.Type {
+	case diff.LineAdded:
+		return "+" + l.Content
+	case diff.LineRemoved:
+		return "-" + l.Content
+	}
+	return " " + l.Content
+}
+
+func findFile(d *diff.Diff, path string) *diff.FileDiff {
+	for i := range d.Files {
+		if d.Files[i].GetFilePath() == path {
+			return &d.Files[i]
+		}
+	}
+	return nil
+}
+
+// Anchor records the text at and around each comment's line in d, so that
+// Reanchor can find the line again once the working tree has moved on.
+func (r *Review) Anchor(d *diff.Diff) {
+	for i := range r.Comments {
+		c := &r.Comments[i]
+		if c.Outdated {
+			continue
+		}
+		f := findFile(d, c.FilePath)
+		if f == nil || c.HunkIndex < 0 || c.HunkIndex >= len(f.Hunks) {
+			continue
+		}
+		lines := f.Hunks[c.HunkIndex].Lines
+		if c.LineIndex < 0 || c.LineIndex >= len(lines) {
+			continue
+		}
+		c.Line = patchLine(lines[c.LineIndex])
+		c.Before = nil
+		for j := max(0, c.LineIndex-anchorContext); j < c.LineIndex; j++ {
+			c.Before = append(c.Before, patchLine(lines[j]))
+		}
+		c.After = nil
+		for j := c.LineIndex + 1; j < min(len(lines), c.LineIndex+1+anchorContext); j++ {
+			c.After = append(c.After, patchLine(lines[j]))
+		}
+	}
+}
+
+// Reanchor moves each comment to where its anchored line now sits in d, the
+// way git apply places a hunk whose offsets have drifted: the line itself
+// must match exactly, and surrounding context picks between repeats of it.
+// Comments whose line can no longer be placed are marked Outdated rather
+// than left pointing at whatever now occupies their old position. It
+// returns the number of outdated comments.
+func (r *Review) Reanchor(d *diff.Diff) int {
+	outdated := 0
+	for i := range r.Comments {
+		c := &r.Comments[i]
+		c.Outdated = !c.reanchor(d)
+		if c.Outdated {
+			outdated++
+		}
+	}
+	return outdated
+}
+
+// reanchor places c in d, reporting whether it found a home.
+func (c *Comment) reanchor(d *diff.Diff) bool {
+	f := findFile(d, c.FilePath)
+	if f == nil {
+		return false
+	}
+	if f.Unloaded > 0 {
+		// Too big to have been read yet: placed once the file is loaded.
+		return !c.Outdated
+	}
+	if c.Line == "" {
+		// Saved before anchors were recorded: trust the position if it
+		// still exists.
+		return c.HunkIndex >= 0 && c.HunkIndex < len(f.Hunks) &&
+			c.LineIndex >= 0 && c.LineIndex < len(f.Hunks[c.HunkIndex].Lines)
+	}
+
+	bestHunk, bestLine, bestScore, bestDist := -1, -1, -1, 0
+	candidates := 0
+	for hi, h := range f.Hunks {
+		for li, l := range h.Lines {
+			if patchLine(l) != c.Line {
+				continue
+			}
+			score, ok := contextScore(h.Lines, li, c.Before, c.After)
+			if !ok {
+				continue
+			}
+			candidates++
+			dist := abs(lineNum(l) - c.LineNum)
+			if score > bestScore || (score == bestScore && dist < bestDist) {
+				bestHunk, bestLine, bestScore, bestDist = hi, li, score, dist
+			}
+		}
+	}
+	// A repeated line with no context to go on could be any of them.
+	if bestHunk < 0 || (bestScore == 0 && candidates > 1) {
+		return false
+	}
+	c.HunkIndex = bestHunk
+	c.LineIndex = bestLine
+	c.LineNum = lineNum(f.Hunks[bestHunk].Lines[bestLine])
+	return true
+}
+
+// contextScore counts how many of the recorded context lines still sit
+// next to lines[i], working outwards from it on each side. Like git apply's
+// fuzz, outer lines may differ but the immediate neighbours must not: ok is
+// false when a side with recorded context doesn't match at all.
+func contextScore(lines []diff.Line, i int, before, after []string) (score int, ok bool) {
+	b := 0
+	for b < len(before) && i-b-1 >= 0 && patchLine(lines[i-b-1]) == before[len(before)-b-1] {
+		b++
+	}
+	a := 0
+	for a < len(after) && i+a+1 < len(lines) && patchLine(lines[i+a+1]) == after[a] {
+		a++
+	}
+	ok = (len(before) == 0 || b > 0) && (len(after) == 0 || a > 0)
+	return a + b, ok
+}
+
+func lineNum(l diff.Line) int {
+	if l.NewNum != 0 {
+		return l.NewNum
+	}
+	return l.OldNum
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,152 @@
+package review
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shnupta/herd/internal/diff"
+)
+
+func mustParse(t *testing.T, raw string) *diff.Diff {
+	t.Helper()
+	d, err := diff.Parse(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+const anchorBefore = "diff --git a/main.go b/main.go\n" +
+	"--- a/main.go\n" +
+	"+++ b/main.go\n" +
+	"@@ -10,3 +10,4 @@\n" +
+	" func run() {\n" +
+	"+\treturn nil\n" +
+	" }\n" +
+	" \n" +
+	"@@ -40,3 +41,4 @@\n" +
+	" func stop() {\n" +
+	"+\treturn nil\n" +
+	" }\n" +
+	" \n"
+
+func TestReanchorFollowsShiftedLine(t *testing.T) {
+	r := NewReview("s", "/p")
+	r.AddComment("main.go", 42, 1, 1, "wrap the error") // stop's return
+	r.Anchor(mustParse(t, anchorBefore))
+
+	// A new hunk lands above both, pushing stop's down to the third hunk.
+	after := mustParse(t, "diff --git a/main.go b/main.go\n"+
+		"--- a/main.go\n"+
+		"+++ b/main.go\n"+
+		"@@ -1,2 +1,3 @@\n"+
+		" package main\n"+
+		"+import \"errors\"\n"+
+		" \n"+
+		"@@ -10,3 +11,4 @@\n"+
+		" func run() {\n"+
+		"+\treturn nil\n"+
+		" }\n"+
+		" \n"+
+		"@@ -40,3 +42,4 @@\n"+
+		" func stop() {\n"+
+		"+\treturn nil\n"+
+		" }\n"+
+		" \n")
+
+	if n := r.Reanchor(after); n != 0 {
+		t.Fatalf("Reanchor reported %d outdated, want 0", n)
+	}
+	c := r.Comments[0]
+	if c.HunkIndex != 2 || c.LineIndex != 1 || c.LineNum != 43 {
+		t.Errorf("comment at hunk %d line %d (new %d), want hunk 2 line 1 (new 43)", c.HunkIndex, c.LineIndex, c.LineNum)
+	}
+	if r.GetCommentForLine("main.go", 2, 1) == nil {
+		t.Error("GetCommentForLine misses the re-anchored comment")
+	}
+}
+
+func TestReanchorMarksRemovedLineOutdated(t *testing.T) {
+	r := NewReview("s", "/p")
+	r.AddComment("main.go", 42, 1, 1, "wrap the error")
+	r.Anchor(mustParse(t, anchorBefore))
+
+	// The agent rewrote stop's body; only run's identical line remains, and
+	// its context doesn't match.
+	after := mustParse(t, "diff --git a/main.go b/main.go\n"+
+		"--- a/main.go\n"+
+		"+++ b/main.go\n"+
+		"@@ -10,3 +10,4 @@\n"+
+		" func run() {\n"+
+		"+\treturn nil\n"+
+		" }\n"+
+		" \n"+
+		"@@ -40,3 +41,4 @@\n"+
+		" func stop() {\n"+
+		"+\treturn errors.New(\"stopped\")\n"+
+		" }\n"+
+		" \n")
+
+	if n := r.Reanchor(after); n != 1 {
+		t.Fatalf("Reanchor reported %d outdated, want 1", n)
+	}
+	if r.GetCommentForLine("main.go", 1, 1) != nil {
+		t.Error("outdated comment still shows on its old line")
+	}
+	fb := r.FormatFeedback(after)
+	if !strings.Contains(fb, "since changed") || !strings.Contains(fb, "> +\treturn nil") || !strings.Contains(fb, "wrap the error") {
+		t.Errorf("feedback should carry the outdated comment with its old line:\n%s", fb)
+	}
+}
+
+func TestReanchorAmbiguousLineWithoutContext(t *testing.T) {
+	r := NewReview("s", "/p")
+	r.AddComment("main.go", 42, 1, 1, "check")
+	r.Comments[0].Line = "+\treturn nil"
+
+	if n := r.Reanchor(mustParse(t, anchorBefore)); n != 1 {
+		t.Errorf("two equally likely matches should leave the comment outdated, got %d outdated", n)
+	}
+}
+
+func TestReanchorKeepsLegacyComments(t *testing.T) {
+	r := NewReview("s", "/p")
+	r.AddComment("main.go", 11, 0, 1, "saved before anchors")
+	if n := r.Reanchor(mustParse(t, anchorBefore)); n != 0 {
+		t.Errorf("legacy comment at a valid position marked outdated")
+	}
+	r.AddComment("gone.go", 1, 0, 0, "file left the diff")
+	if n := r.Reanchor(mustParse(t, anchorBefore)); n != 1 {
+		t.Errorf("comment on a file no longer in the diff should be outdated, got %d", n)
+	}
+}
+
+func TestProgressSurvivesUnrelatedChanges(t *testing.T) {
+	r := NewReview("s", "/p")
+	d := mustParse(t, anchorBefore)
+	r.MarkViewed(HunkKey("main.go", &d.Files[0].Hunks[0]))
+	if viewed, total := r.Progress(d); viewed != 1 || total != 2 {
+		t.Fatalf("Progress = %d/%d, want 1/2", viewed, total)
+	}
+
+	// The first hunk moves but is unchanged; the second is rewritten.
+	after := mustParse(t, "diff --git a/main.go b/main.go\n"+
+		"--- a/main.go\n"+
+		"+++ b/main.go\n"+
+		"@@ -12,3 +12,4 @@\n"+
+		" func run() {\n"+
+		"+\treturn nil\n"+
+		" }\n"+
+		" \n"+
+		"@@ -40,3 +41,4 @@\n"+
+		" func stop() {\n"+
+		"+\treturn errors.New(\"stopped\")\n"+
+		" }\n"+
+		" \n")
+	if viewed, total := r.Progress(after); viewed != 1 || total != 2 {
+		t.Errorf("after the change Progress = %d/%d, want 1/2", viewed, total)
+	}
+	if !r.IsViewed(HunkKey("main.go", &after.Files[0].Hunks[0])) {
+		t.Error("moved but unchanged hunk lost its viewed mark")
+	}
+}
@@ -0,0 +1,57 @@
+package review
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+
+	"github.com/shnupta/herd/internal/diff"
+)
+
+// HunkKey identifies a hunk by its file and content, so a hunk read before
+// the diff changed still counts as read if it hasn't itself changed. h is
+// nil for a binary file, which has no hunks and is keyed by path alone.
+func HunkKey(path string, h *diff.Hunk) string {
+	if h == nil {
+		return path
+	}
+	sum := sha1.New()
+	for _, l := range h.Lines {
+		sum.Write([]byte(patchLine(l) + "\n"))
+	}
+	return path + "@" + hex.EncodeToString(sum.Sum(nil))[:12]
+}
+
+// MarkViewed records that the hunk with key has been read.
+func (r *Review) MarkViewed(key string) {
+	if r.Viewed == nil {
+		r.Viewed = make(map[string]bool)
+	}
+	r.Viewed[key] = true
+}
+
+// IsViewed reports whether the hunk with key has been read.
+func (r *Review) IsViewed(key string) bool {
+	return r.Viewed[key]
+}
+
+// Progress counts the hunks of d that have been read, and the total. A
+// binary file counts as one hunk.
+func (r *Review) Progress(d *diff.Diff) (viewed, total int) {
+	for i := range d.Files {
+		f := &d.Files[i]
+		if len(f.Hunks) == 0 {
+			total++
+			if r.IsViewed(HunkKey(f.GetFilePath(), nil)) {
+				viewed++
+			}
+			continue
+		}
+		for j := range f.Hunks {
+			total++
+			if r.IsViewed(HunkKey(f.GetFilePath(), &f.Hunks[j])) {
+				viewed++
+			}
+		}
+	}
+	return viewed, total
+}
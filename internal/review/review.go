@@ -5,10 +5,13 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/shnupta/herd/internal/diff"
+	"github.com/shnupta/herd/internal/prompt"
+	"github.com/shnupta/herd/internal/seal"
 )
 
 // Comment represents a review comment on a specific location.
@@ -19,6 +22,20 @@ type Comment struct {
 	LineIndex int       `json:"line_index"` // Index within the hunk's lines
 	Text      string    `json:"text"`
 	CreatedAt time.Time `json:"created_at"`
+
+	// Anchor text recorded on save so the comment can find its line again
+	// after the diff changes. Line carries the +/-/space prefix.
+	Line   string   `json:"line,omitempty"`
+	Before []string `json:"before,omitempty"`
+	After  []string `json:"after,omitempty"`
+
+	// Outdated is set when the commented line is no longer in the diff.
+	Outdated bool `json:"outdated,omitempty"`
+
+	// Replies continue the comment as a thread; SentAt is when it was
+	// last sent to the agent.
+	Replies []Reply   `json:"replies,omitempty"`
+	SentAt  time.Time `json:"sent_at,omitempty"`
 }
 
 // Review represents a complete review session.
@@ -28,6 +45,9 @@ type Review struct {
 	Comments    []Comment `json:"comments"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
+
+	// Viewed holds the HunkKey of every hunk the user has read past.
+	Viewed map[string]bool `json:"viewed,omitempty"`
 }
 
 // NewReview creates a new review for the given session.
@@ -78,7 +98,7 @@ func (r *Review) GetCommentsForFile(filePath string) []Comment {
 func (r *Review) GetCommentForLine(filePath string, hunkIndex, lineIndex int) *Comment {
 	for i := range r.Comments {
 		c := &r.Comments[i]
-		if c.FilePath == filePath && c.HunkIndex == hunkIndex && c.LineIndex == lineIndex {
+		if !c.Outdated && c.FilePath == filePath && c.HunkIndex == hunkIndex && c.LineIndex == lineIndex {
 			return c
 		}
 	}
@@ -90,20 +110,92 @@ func (r *Review) HasComments() bool {
 	return len(r.Comments) > 0
 }
 
+// Template shapes review feedback. Each field is a Go text/template
+// rendered with prompt.Render; an empty field uses DefaultTemplate's.
+//
+// Preamble and Closing see {{.project}}, {{.count}} (comments) and
+// {{.files}} (files commented on). Comment is rendered once per comment with
+// {{.file}}, {{.line}}, {{.code}} (the commented diff line, quoted with
+// "> ", or empty once that line has left the diff), {{.comment}} and
+// {{.thread}} (the replies so far, one "author: text" per line).
+type Template struct {
+	Preamble string
+	Comment  string
+	Closing  string
+}
+
+// DefaultTemplate is the feedback herd sends unless configured otherwise.
+var DefaultTemplate = Template{
+	Preamble: "Review of your recent changes:",
+	Comment:  "{{.file}}:{{.line}}\n{{if .code}}{{.code}}\n{{end}}Comment: {{.comment}}{{if .thread}}\n{{.thread}}{{end}}",
+	Closing:  "Please address this feedback.",
+}
+
 // FormatFeedback formats the review as feedback text to send to the agent.
 func (r *Review) FormatFeedback(d *diff.Diff) string {
-	if len(r.Comments) == 0 {
-		return ""
-	}
+	text, _ := r.FormatFeedbackWith(d, DefaultTemplate)
+	return text
+}
 
-	var sb strings.Builder
-	sb.WriteString("Review of your recent changes:\n\n")
+// FormatFeedbackWith formats the review's pending comments using t,
+// reporting template errors.
+func (r *Review) FormatFeedbackWith(d *diff.Diff, t Template) (string, error) {
+	var pending []Comment
+	for i := range r.Comments {
+		if r.Comments[i].Pending() {
+			pending = append(pending, r.Comments[i])
+		}
+	}
+	if len(pending) == 0 {
+		return "", nil
+	}
+	if t.Preamble == "" {
+		t.Preamble = DefaultTemplate.Preamble
+	}
+	if t.Comment == "" {
+		t.Comment = DefaultTemplate.Comment
+	}
+	if t.Closing == "" {
+		t.Closing = DefaultTemplate.Closing
+	}
 
 	// Group comments by file
 	commentsByFile := make(map[string][]Comment)
-	for _, c := range r.Comments {
+	var outdated []Comment
+	for _, c := range pending {
+		if c.Outdated {
+			outdated = append(outdated, c)
+			continue
+		}
 		commentsByFile[c.FilePath] = append(commentsByFile[c.FilePath], c)
 	}
+	summary := map[string]string{
+		"project": r.ProjectPath,
+		"count":   strconv.Itoa(len(pending)),
+		"files":   strconv.Itoa(countFiles(pending)),
+	}
+
+	var sb strings.Builder
+	preamble, err := prompt.Render(t.Preamble, summary)
+	if err != nil {
+		return "", fmt.Errorf("preamble: %w", err)
+	}
+	sb.WriteString(preamble + "\n\n")
+
+	writeComment := func(file string, c Comment, code string) error {
+		text, err := prompt.Render(t.Comment, map[string]string{
+			"file":    file,
+			"line":    strconv.Itoa(c.LineNum),
+			"code":    code,
+			"comment": c.Text,
+			"thread":  c.thread(),
+		})
+		if err != nil {
+			return fmt.Errorf("comment: %w", err)
+		}
+		sb.WriteString(text + "\n\n")
+		return nil
+	}
 
 	for _, file := range d.Files {
 		filePath := file.GetFilePath()
@@ -127,25 +219,66 @@ func (r *Review) FormatFeedback(d *diff.Diff) string {
 					endIdx = len(hunk.Lines)
 				}
 
-				sb.WriteString(fmt.Sprintf("%s:%d\n", filePath, comment.LineNum))
+				var code []string
 				for i := startIdx; i < endIdx; i++ {
-					line := hunk.Lines[i]
-					prefix := " "
-					switch line.Type {
-					case diff.LineAdded:
-						prefix = "+"
-					case diff.LineRemoved:
-						prefix = "-"
-					}
-					sb.WriteString(fmt.Sprintf("> %s%s\n", prefix, line.Content))
+					code = append(code, "> "+patchLine(hunk.Lines[i]))
+				}
+				if err := writeComment(filePath, comment, strings.Join(code, "\n")); err != nil {
+					return "", err
 				}
-				sb.WriteString(fmt.Sprintf("Comment: %s\n\n", comment.Text))
 			}
 		}
 	}
 
-	sb.WriteString("Please address this feedback.")
-	return sb.String()
+	if len(outdated) > 0 {
+		sb.WriteString("These comments are on lines that have since changed:\n\n")
+		for _, comment := range outdated {
+			code := ""
+			if comment.Line != "" {
+				code = "> " + comment.Line
+			}
+			if err := writeComment(comment.FilePath, comment, code); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	closing, err := prompt.Render(t.Closing, summary)
+	if err != nil {
+		return "", fmt.Errorf("closing: %w", err)
+	}
+	sb.WriteString(closing)
+	return sb.String(), nil
+}
+
+// countFiles counts the distinct files among comments.
+func countFiles(comments []Comment) int {
+	seen := make(map[string]bool)
+	for _, c := range comments {
+		seen[c.FilePath] = true
+	}
+	return len(seen)
+}
+
+// WriteTodo appends feedback to the markdown file at path under a dated
+// heading, for agents that pick up queued review files rather than having
+// feedback typed into their pane.
+func WriteTodo(path, feedback string, now time.Time) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if info, err := f.Stat(); err == nil && info.Size() > 0 {
+		if _, err := f.WriteString("\n"); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(f, "## Review — %s\n\n%s\n", now.Format("2006-01-02 15:04"), feedback)
+	return err
 }
 
 // Storage manages review persistence for a specific directory.
@@ -171,12 +304,12 @@ func (s *Storage) Save(r *Review) error {
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(s.path(r.SessionID), data, 0o644)
+	return seal.WriteFile("reviews", s.path(r.SessionID), data, 0o644)
 }
 
 // Load loads a review from the storage directory.
 func (s *Storage) Load(sessionID string) (*Review, error) {
-	data, err := os.ReadFile(s.path(sessionID))
+	data, err := seal.ReadFile(s.path(sessionID))
 	if err != nil {
 		return nil, err
 	}
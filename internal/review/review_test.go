@@ -1,8 +1,11 @@
 package review
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/shnupta/herd/internal/diff"
 )
@@ -180,3 +183,50 @@ func TestStorageLoadNonexistent(t *testing.T) {
 		t.Error("Load() of nonexistent session should return error")
 	}
 }
+
+func TestWriteTodoAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "TODO.review.md")
+	at := time.Date(2026, 10, 17, 9, 30, 0, 0, time.UTC)
+	if err := WriteTodo(path, "first feedback", at); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteTodo(path, "second feedback", at.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := os.ReadFile(path)
+	want := "## Review — 2026-10-17 09:30\n\nfirst feedback\n\n## Review — 2026-10-17 10:30\n\nsecond feedback\n"
+	if string(got) != want {
+		t.Errorf("todo file = %q, want %q", got, want)
+	}
+}
+
+func TestFormatFeedbackWithTemplate(t *testing.T) {
+	d, err := diff.Parse("diff --git a/main.go b/main.go\n--- a/main.go\n+++ b/main.go\n@@ -1,2 +1,2 @@\n context\n-old\n+new\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewReview("session1", "/project")
+	r.AddComment("main.go", 2, 0, 2, "use a better name")
+
+	// The default template keeps the original wording.
+	want := "Review of your recent changes:\n\nmain.go:2\n> +new\nComment: use a better name\n\nPlease address this feedback."
+	if got := r.FormatFeedback(d); got != want {
+		t.Errorf("FormatFeedback = %q, want %q", got, want)
+	}
+
+	got, err := r.FormatFeedbackWith(d, Template{
+		Preamble: "{{.count}} note(s) on {{.files}} file(s) in {{.project}}.",
+		Comment:  "- [ ] {{.file}} line {{.line}}: {{.comment}}",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = "1 note(s) on 1 file(s) in /project.\n\n- [ ] main.go line 2: use a better name\n\nPlease address this feedback."
+	if got != want {
+		t.Errorf("FormatFeedbackWith = %q, want %q", got, want)
+	}
+
+	if _, err := r.FormatFeedbackWith(d, Template{Comment: "{{.author}}"}); err == nil {
+		t.Error("unknown variable should be an error")
+	}
+}
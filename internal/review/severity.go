@@ -0,0 +1,38 @@
+package review
+
+import "strings"
+
+// severityLabels are the Conventional Comments labels a comment can start
+// with, e.g. "nit: rename this" or "issue (blocking): leaks the handle".
+var severityLabels = map[string]bool{
+	"blocker": true, "issue": true, "suggestion": true, "nit": true,
+	"question": true, "thought": true, "todo": true, "chore": true,
+	"note": true, "praise": true,
+}
+
+// Severity returns the label a comment's text starts with, or "comment"
+// when it has none.
+func Severity(text string) string {
+	head, _, ok := strings.Cut(strings.TrimSpace(text), ":")
+	if !ok {
+		return "comment"
+	}
+	// Drop decorations: "issue (blocking)" is an issue.
+	label, _, _ := strings.Cut(head, "(")
+	label = strings.ToLower(strings.TrimSpace(label))
+	if severityLabels[label] {
+		return label
+	}
+	return "comment"
+}
+
+// SeveritySummary counts the review's current comments by Severity.
+func (r *Review) SeveritySummary() map[string]int {
+	out := make(map[string]int)
+	for _, c := range r.Comments {
+		if !c.Outdated {
+			out[Severity(c.Text)]++
+		}
+	}
+	return out
+}
@@ -0,0 +1,35 @@
+package review
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSeverity(t *testing.T) {
+	tests := map[string]string{
+		"nit: rename this":               "nit",
+		"Issue (blocking): leaks handle": "issue",
+		"  suggestion: early return":     "suggestion",
+		"why not: use a map":             "comment",
+		"looks wrong":                    "comment",
+	}
+	for text, want := range tests {
+		if got := Severity(text); got != want {
+			t.Errorf("Severity(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
+
+func TestSeveritySummary(t *testing.T) {
+	r := &Review{Comments: []Comment{
+		{Text: "nit: spacing"},
+		{Text: "nit: naming"},
+		{Text: "issue: off by one"},
+		{Text: "issue: gone", Outdated: true},
+		{Text: "why?"},
+	}}
+	want := map[string]int{"nit": 2, "issue": 1, "comment": 1}
+	if got := r.SeveritySummary(); !reflect.DeepEqual(got, want) {
+		t.Errorf("SeveritySummary() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,137 @@
+package review
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Reply authors.
+const (
+	AuthorYou   = "you"
+	AuthorAgent = "agent"
+)
+
+// replyLimit caps how much of the agent's answer is kept on one thread.
+const replyLimit = 1000
+
+// Reply is a later message in a comment's thread: a follow-up from the
+// reviewer, or the agent's answer read from its transcript.
+type Reply struct {
+	Author    string    `json:"author"` // AuthorYou or AuthorAgent
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AddReply appends a reply to the comment at index.
+func (r *Review) AddReply(index int, author, text string) {
+	if index < 0 || index >= len(r.Comments) {
+		return
+	}
+	c := &r.Comments[index]
+	c.Replies = append(c.Replies, Reply{Author: author, Text: text, CreatedAt: time.Now()})
+	r.UpdatedAt = time.Now()
+}
+
+// Pending reports whether the comment has something the agent hasn't been
+// sent: it is new, or the reviewer replied since it was last sent.
+func (c *Comment) Pending() bool {
+	if c.SentAt.IsZero() {
+		return true
+	}
+	for _, rp := range c.Replies {
+		if rp.Author == AuthorYou && rp.CreatedAt.After(c.SentAt) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasPending reports whether any comment is pending.
+func (r *Review) HasPending() bool {
+	for i := range r.Comments {
+		if r.Comments[i].Pending() {
+			return true
+		}
+	}
+	return false
+}
+
+// MarkSent records that the pending comments were sent to the agent at.
+func (r *Review) MarkSent(at time.Time) {
+	for i := range r.Comments {
+		if c := &r.Comments[i]; c.Pending() {
+			c.SentAt = at
+		}
+	}
+	r.UpdatedAt = at
+}
+
+// LastSent returns when feedback was last sent, or the zero time if never.
+func (r *Review) LastSent() time.Time {
+	var last time.Time
+	for _, c := range r.Comments {
+		if c.SentAt.After(last) {
+			last = c.SentAt
+		}
+	}
+	return last
+}
+
+// RecordAgentReply adds the agent's answer, given everything it said since
+// the feedback went out, to each sent thread it hasn't answered yet. A
+// thread gets the paragraphs naming its file, or the whole answer when none
+// do. It returns how many threads were answered.
+func (r *Review) RecordAgentReply(text string, at time.Time) int {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return 0
+	}
+	paragraphs := strings.Split(text, "\n\n")
+	answered := 0
+	for i := range r.Comments {
+		c := &r.Comments[i]
+		if c.SentAt.IsZero() || !at.After(c.SentAt) || c.answeredSince(c.SentAt) {
+			continue
+		}
+		var mine []string
+		base := filepath.Base(c.FilePath)
+		for _, p := range paragraphs {
+			if strings.Contains(p, base) {
+				mine = append(mine, p)
+			}
+		}
+		reply := text
+		if len(mine) > 0 {
+			reply = strings.Join(mine, "\n\n")
+		}
+		if runes := []rune(reply); len(runes) > replyLimit {
+			reply = string(runes[:replyLimit]) + "…"
+		}
+		c.Replies = append(c.Replies, Reply{Author: AuthorAgent, Text: reply, CreatedAt: at})
+		answered++
+	}
+	if answered > 0 {
+		r.UpdatedAt = at
+	}
+	return answered
+}
+
+// answeredSince reports whether the agent replied to c after t.
+func (c *Comment) answeredSince(t time.Time) bool {
+	for _, rp := range c.Replies {
+		if rp.Author == AuthorAgent && rp.CreatedAt.After(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// thread renders c's replies for feedback, one "author: text" per reply.
+func (c *Comment) thread() string {
+	var lines []string
+	for _, rp := range c.Replies {
+		lines = append(lines, rp.Author+": "+rp.Text)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,70 @@
+package review
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shnupta/herd/internal/diff"
+)
+
+func TestThreadSendsOnlyWhatIsNew(t *testing.T) {
+	d, err := diff.Parse("diff --git a/main.go b/main.go\n" +
+		"--- a/main.go\n" +
+		"+++ b/main.go\n" +
+		"@@ -1,2 +1,2 @@\n" +
+		" context\n" +
+		"-old\n" +
+		"+new\n" +
+		"diff --git a/util.go b/util.go\n" +
+		"--- a/util.go\n" +
+		"+++ b/util.go\n" +
+		"@@ -1 +1 @@\n" +
+		"-a\n" +
+		"+b\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewReview("session1", "/project")
+	r.AddComment("main.go", 2, 0, 2, "check for nil")
+	r.AddComment("util.go", 1, 0, 1, "rename b")
+	if !r.HasPending() {
+		t.Fatal("new comments should be pending")
+	}
+
+	sent := time.Now()
+	r.MarkSent(sent)
+	if r.HasPending() || r.FormatFeedback(d) != "" {
+		t.Fatal("nothing should be pending once sent")
+	}
+	if !r.LastSent().Equal(sent) {
+		t.Errorf("LastSent = %v, want %v", r.LastSent(), sent)
+	}
+
+	answer := "Added a nil check to main.go.\n\nAlso tidied the imports."
+	if n := r.RecordAgentReply(answer, sent.Add(time.Minute)); n != 2 {
+		t.Fatalf("RecordAgentReply answered %d threads, want 2", n)
+	}
+	if got := r.Comments[0].Replies[0]; got.Author != AuthorAgent || got.Text != "Added a nil check to main.go." {
+		t.Errorf("main.go reply = %+v, want the paragraph naming main.go", got)
+	}
+	if got := r.Comments[1].Replies[0].Text; got != answer {
+		t.Errorf("util.go reply = %q, want the whole answer", got)
+	}
+	if n := r.RecordAgentReply("more", sent.Add(2*time.Minute)); n != 0 {
+		t.Errorf("threads answered twice: %d", n)
+	}
+
+	time.Sleep(time.Millisecond) // the reply must come after the send
+	r.AddReply(0, AuthorYou, "and log it")
+	if !r.Comments[0].Pending() || r.Comments[1].Pending() {
+		t.Fatal("only the thread replied to should be pending")
+	}
+	fb := r.FormatFeedback(d)
+	if want := "Comment: check for nil\nagent: Added a nil check to main.go.\nyou: and log it"; !strings.Contains(fb, want) {
+		t.Errorf("feedback lacks the thread %q:\n%s", want, fb)
+	}
+	if strings.Contains(fb, "rename b") {
+		t.Errorf("feedback repeats an answered thread:\n%s", fb)
+	}
+}
@@ -0,0 +1,195 @@
+// Package script runs user Lua scripts from ~/.herd/scripts on herd events.
+//
+// Each *.lua file is loaded into its own interpreter with a global `herd`
+// table. Scripts register handlers with herd.on(event, fn); fn receives a
+// table of string fields describing the event and can act through
+// herd.send_keys(pane, text), herd.set_name(key, name),
+// herd.set_group(key, group) and herd.notify(message).
+package script
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// Event names passed to herd.on.
+const (
+	StateChange     = "state_change"     // key, pane, session_id, project, state, previous_state
+	ReviewSubmitted = "review_submitted" // key, pane, project, feedback
+	WorktreeCreated = "worktree_created" // pane, path, branch
+)
+
+// handlerTimeout bounds one handler call, and the run of a script's top
+// level as it loads, so a runaway loop can't wedge herd.
+const handlerTimeout = 2 * time.Second
+
+// API is what scripts can do to herd.
+type API interface {
+	SendKeys(pane, text string) error
+	SetName(key, name string) error
+	SetGroup(key, group string) error
+	Notify(message string) error
+}
+
+// Event is one occurrence delivered to handlers.
+type Event struct {
+	Name   string
+	Fields map[string]string
+}
+
+type handler struct {
+	event string
+	file  string
+	L     *lua.LState
+	fn    *lua.LFunction
+}
+
+// Engine holds the loaded scripts. A nil *Engine is valid and has no handlers.
+type Engine struct {
+	mu       sync.Mutex // interpreters are not safe for concurrent use
+	states   []*lua.LState
+	handlers map[string][]handler
+}
+
+// Dir returns the default script directory, ~/.herd/scripts.
+func Dir() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".herd", "scripts")
+}
+
+// Load runs every *.lua file in dir, in name order, so it can register its
+// handlers. A missing dir yields an empty engine; scripts that fail to load
+// are returned as errors and contribute no handlers.
+func Load(dir string, api API) (*Engine, []error) {
+	e := &Engine{handlers: make(map[string][]handler)}
+	paths, _ := filepath.Glob(filepath.Join(dir, "*.lua"))
+	sort.Strings(paths)
+
+	var errs []error
+	for _, path := range paths {
+		L := lua.NewState()
+		var registered []handler
+		L.SetGlobal("herd", newModule(L, filepath.Base(path), api, &registered))
+		if err := doFile(L, path); err != nil {
+			L.Close()
+			errs = append(errs, fmt.Errorf("script %s: %w", filepath.Base(path), err))
+			continue
+		}
+		e.states = append(e.states, L)
+		for _, h := range registered {
+			e.handlers[h.event] = append(e.handlers[h.event], h)
+		}
+	}
+	return e, errs
+}
+
+// doFile runs the script at path in L within handlerTimeout.
+func doFile(L *lua.LState, path string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), handlerTimeout)
+	defer cancel()
+	L.SetContext(ctx)
+	defer L.RemoveContext()
+	return L.DoFile(path)
+}
+
+// newModule builds the `herd` table for one interpreter. Handlers registered
+// while the file runs are collected in registered.
+func newModule(L *lua.LState, file string, api API, registered *[]handler) *lua.LTable {
+	mod := L.NewTable()
+	check := func(L *lua.LState, err error) int {
+		if err != nil {
+			L.RaiseError("%s", err.Error())
+		}
+		return 0
+	}
+	L.SetFuncs(mod, map[string]lua.LGFunction{
+		"on": func(L *lua.LState) int {
+			*registered = append(*registered, handler{
+				event: L.CheckString(1),
+				file:  file,
+				L:     L,
+				fn:    L.CheckFunction(2),
+			})
+			return 0
+		},
+		"send_keys": func(L *lua.LState) int {
+			return check(L, api.SendKeys(L.CheckString(1), L.CheckString(2)))
+		},
+		"set_name": func(L *lua.LState) int {
+			return check(L, api.SetName(L.CheckString(1), L.OptString(2, "")))
+		},
+		"set_group": func(L *lua.LState) int {
+			return check(L, api.SetGroup(L.CheckString(1), L.OptString(2, "")))
+		},
+		"notify": func(L *lua.LState) int {
+			return check(L, api.Notify(L.CheckString(1)))
+		},
+	})
+	return mod
+}
+
+// Fire calls every handler registered for ev.Name, in load order, and
+// returns the errors of any that failed.
+func (e *Engine) Fire(ev Event) []error {
+	if e == nil {
+		return nil
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var errs []error
+	for _, h := range e.handlers[ev.Name] {
+		if err := h.call(ev); err != nil {
+			errs = append(errs, fmt.Errorf("script %s on %s: %w", h.file, ev.Name, err))
+		}
+	}
+	return errs
+}
+
+func (h handler) call(ev Event) error {
+	ctx, cancel := context.WithTimeout(context.Background(), handlerTimeout)
+	defer cancel()
+	h.L.SetContext(ctx)
+	defer h.L.RemoveContext()
+
+	arg := h.L.NewTable()
+	arg.RawSetString("event", lua.LString(ev.Name))
+	for k, v := range ev.Fields {
+		arg.RawSetString(k, lua.LString(v))
+	}
+	err := h.L.CallByParam(lua.P{Fn: h.fn, NRet: 0, Protect: true}, arg)
+	if apiErr, ok := err.(*lua.ApiError); ok {
+		return fmt.Errorf("%s", strings.TrimSpace(apiErr.Object.String()))
+	}
+	return err
+}
+
+// Handlers reports how many handlers are registered for event.
+func (e *Engine) Handlers(event string) int {
+	if e == nil {
+		return 0
+	}
+	return len(e.handlers[event])
+}
+
+// Close releases the interpreters.
+func (e *Engine) Close() {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, L := range e.states {
+		L.Close()
+	}
+	e.states = nil
+	e.handlers = nil
+}
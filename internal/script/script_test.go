@@ -0,0 +1,111 @@
+package script
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type fakeAPI struct {
+	sent, names, groups, notes []string
+}
+
+func (f *fakeAPI) SendKeys(pane, text string) error {
+	f.sent = append(f.sent, pane+":"+text)
+	return nil
+}
+func (f *fakeAPI) SetName(key, name string) error {
+	f.names = append(f.names, key+"="+name)
+	return nil
+}
+func (f *fakeAPI) SetGroup(key, group string) error {
+	f.groups = append(f.groups, key+"="+group)
+	return nil
+}
+func (f *fakeAPI) Notify(message string) error { f.notes = append(f.notes, message); return nil }
+
+func writeScript(t *testing.T, dir, name, src string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFireRunsHandlersWithAPI(t *testing.T) {
+	dir := t.TempDir()
+	writeScript(t, dir, "a.lua", `
+herd.on("state_change", function(ev)
+  if ev.state == "waiting" then
+    herd.notify(ev.project .. " needs you")
+    herd.set_group(ev.key, "attention")
+  end
+end)
+herd.on("worktree_created", function(ev)
+  herd.send_keys(ev.pane, "read the README on " .. ev.branch)
+  herd.set_name("pane:" .. ev.pane, ev.branch)
+end)
+`)
+	api := &fakeAPI{}
+	e, errs := Load(dir, api)
+	defer e.Close()
+	if len(errs) != 0 {
+		t.Fatalf("Load() errs = %v", errs)
+	}
+
+	e.Fire(Event{Name: StateChange, Fields: map[string]string{"key": "session:a", "project": "herd", "state": "working"}})
+	e.Fire(Event{Name: StateChange, Fields: map[string]string{"key": "session:a", "project": "herd", "state": "waiting"}})
+	e.Fire(Event{Name: WorktreeCreated, Fields: map[string]string{"pane": "%4", "branch": "feat/x"}})
+
+	if len(api.notes) != 1 || api.notes[0] != "herd needs you" {
+		t.Errorf("notes = %v, want one waiting notification", api.notes)
+	}
+	if len(api.groups) != 1 || api.groups[0] != "session:a=attention" {
+		t.Errorf("groups = %v", api.groups)
+	}
+	if len(api.sent) != 1 || api.sent[0] != "%4:read the README on feat/x" {
+		t.Errorf("sent = %v", api.sent)
+	}
+	if len(api.names) != 1 || api.names[0] != "pane:%4=feat/x" {
+		t.Errorf("names = %v", api.names)
+	}
+}
+
+func TestLoadReportsBrokenScripts(t *testing.T) {
+	dir := t.TempDir()
+	writeScript(t, dir, "bad.lua", `herd.on(`)
+	writeScript(t, dir, "good.lua", `herd.on("review_submitted", function(ev) end)`)
+	writeScript(t, dir, "spin.lua", `while true do end`)
+
+	e, errs := Load(dir, &fakeAPI{})
+	defer e.Close()
+	if len(errs) != 2 || !strings.Contains(errs[0].Error(), "bad.lua") || !strings.Contains(errs[1].Error(), "spin.lua") {
+		t.Errorf("errs = %v, want bad.lua and spin.lua", errs)
+	}
+	if e.Handlers(ReviewSubmitted) != 1 {
+		t.Errorf("Handlers(review_submitted) = %d, want 1", e.Handlers(ReviewSubmitted))
+	}
+}
+
+func TestFireReportsHandlerErrorsAndTimeouts(t *testing.T) {
+	dir := t.TempDir()
+	writeScript(t, dir, "oops.lua", `
+herd.on("review_submitted", function(ev) error("boom") end)
+herd.on("review_submitted", function(ev) while true do end end)
+`)
+	e, _ := Load(dir, &fakeAPI{})
+	defer e.Close()
+
+	errs := e.Fire(Event{Name: ReviewSubmitted})
+	if len(errs) != 2 || !strings.Contains(errs[0].Error(), "boom") {
+		t.Errorf("Fire() errs = %v, want boom and a timeout", errs)
+	}
+}
+
+func TestNilEngine(t *testing.T) {
+	var e *Engine
+	if errs := e.Fire(Event{Name: StateChange}); errs != nil {
+		t.Errorf("Fire() on nil engine = %v", errs)
+	}
+	e.Close()
+}
@@ -0,0 +1,257 @@
+// Package seal encrypts herd's stores of sensitive text at rest: snippets,
+// prompt history (with the text of the history log's events) and review
+// comments. A sealed file is AES-256-GCM under a
+// key kept in the OS keychain; files written before sealing was turned on
+// still read, and are sealed the next time they are saved.
+package seal
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// Kinds are the stores that can be sealed, as named in config.
+var Kinds = []string{"snippets", "prompts", "reviews"}
+
+// magic starts every sealed file.
+const magic = "herd-sealed-v1\n"
+
+// keychainService names herd's key in the OS keychain.
+const keychainService = "herd-storage"
+
+var (
+	mu      sync.Mutex
+	enabled = map[string]bool{}
+	cached  []byte
+)
+
+// Enable seals the given kinds of store from now on; the others are written
+// in the clear.
+func Enable(kinds []string) {
+	mu.Lock()
+	defer mu.Unlock()
+	enabled = make(map[string]bool, len(kinds))
+	for _, k := range kinds {
+		enabled[k] = true
+	}
+}
+
+// Enabled reports whether kind is sealed.
+func Enabled(kind string) bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return enabled[kind]
+}
+
+// WriteFile writes data to path, sealed if kind is. A sealed file is only
+// readable by its owner.
+func WriteFile(kind, path string, data []byte, perm os.FileMode) error {
+	if !Enabled(kind) {
+		return os.WriteFile(path, data, perm)
+	}
+	sealed, err := Seal(data)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, sealed, 0o600)
+}
+
+// ReadFile reads path, opening it if it is sealed.
+func ReadFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil || !IsSealed(data) {
+		return data, err
+	}
+	return Open(data)
+}
+
+// textPrefix starts text sealed by SealText.
+const textPrefix = "sealed:"
+
+// SealText seals text for a field of a file kept in the clear, such as a
+// line of the history log, if kind is sealed: Seal's output, base64-encoded
+// behind a marker.
+func SealText(kind, text string) (string, error) {
+	if !Enabled(kind) || text == "" {
+		return text, nil
+	}
+	sealed, err := Seal([]byte(text))
+	if err != nil {
+		return "", err
+	}
+	return textPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// IsSealedText reports whether text was sealed by SealText.
+func IsSealedText(text string) bool { return strings.HasPrefix(text, textPrefix) }
+
+// OpenText opens text written by SealText. Text that isn't sealed is
+// returned as it is.
+func OpenText(text string) (string, error) {
+	if !IsSealedText(text) {
+		return text, nil
+	}
+	data, err := base64.StdEncoding.DecodeString(text[len(textPrefix):])
+	if err != nil || !IsSealed(data) {
+		return "", errors.New("sealed text is corrupt")
+	}
+	plain, err := Open(data)
+	return string(plain), err
+}
+
+// IsSealed reports whether data was written by Seal.
+func IsSealed(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(magic))
+}
+
+// Seal encrypts data under the storage key.
+func Seal(data []byte) ([]byte, error) {
+	gcm, err := newGCM()
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	out := append([]byte(magic), nonce...)
+	return gcm.Seal(out, nonce, data, []byte(magic)), nil
+}
+
+// Open decrypts data written by Seal.
+func Open(data []byte) ([]byte, error) {
+	gcm, err := newGCM()
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimPrefix(data, []byte(magic))
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("sealed file is truncated")
+	}
+	nonce, body := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	plain, err := gcm.Open(nil, nonce, body, []byte(magic))
+	if err != nil {
+		return nil, errors.New("sealed file can't be opened with this storage key")
+	}
+	return plain, nil
+}
+
+func newGCM() (cipher.AEAD, error) {
+	key, err := storageKey()
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// storageKey returns the 32-byte key: HERD_STORAGE_KEY (base64) if set,
+// else the one in the OS keychain, created there on first use.
+func storageKey() ([]byte, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	if cached != nil {
+		return cached, nil
+	}
+	encoded := os.Getenv("HERD_STORAGE_KEY")
+	if encoded == "" {
+		var err error
+		if encoded, err = keychainGet(); err != nil {
+			return nil, err
+		}
+		if encoded == "" {
+			key := make([]byte, 32)
+			if _, err := rand.Read(key); err != nil {
+				return nil, err
+			}
+			encoded = base64.StdEncoding.EncodeToString(key)
+			if err := keychainSet(encoded); err != nil {
+				return nil, fmt.Errorf("saving the storage key to the keychain: %w", err)
+			}
+		}
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil || len(key) != 32 {
+		return nil, errors.New("storage key must be 32 bytes, base64-encoded")
+	}
+	cached = key
+	return key, nil
+}
+
+// keychainGet and keychainSet read and store the encoded key in the OS
+// keychain: the login keychain on macOS, the Secret Service (through
+// secret-tool) elsewhere. keychainGet returns "" when there is no key yet.
+// The key is never put on a command line, where other users' ps can see
+// it. They are variables so tests can stub them.
+var (
+	keychainGet = func() (string, error) {
+		var cmd *exec.Cmd
+		if runtime.GOOS == "darwin" {
+			cmd = exec.Command("security", "find-generic-password", "-s", keychainService, "-a", "herd", "-w")
+		} else {
+			cmd = exec.Command("secret-tool", "lookup", "service", keychainService)
+		}
+		out, err := cmd.Output()
+		if keyMissing(runtime.GOOS, err) {
+			return "", nil
+		}
+		var exit *exec.ExitError
+		if errors.As(err, &exit) {
+			return "", fmt.Errorf("reading the storage key from the keychain: %v: %s", err, strings.TrimSpace(string(exit.Stderr)))
+		}
+		if err != nil {
+			return "", fmt.Errorf("no OS keychain (%v); set HERD_STORAGE_KEY to a base64 32-byte key", err)
+		}
+		return strings.TrimSpace(string(out)), nil
+	}
+
+	keychainSet = func(encoded string) error {
+		var cmd *exec.Cmd
+		if runtime.GOOS == "darwin" {
+			// security -i reads its commands from stdin. It reports a
+			// failed command on its output rather than in its exit status.
+			cmd = exec.Command("security", "-i")
+			cmd.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -s %s -a herd -w %s\n", keychainService, encoded))
+		} else {
+			cmd = exec.Command("secret-tool", "store", "--label", "herd storage key", "service", keychainService)
+			cmd.Stdin = strings.NewReader(encoded)
+		}
+		out, err := cmd.CombinedOutput()
+		if err == nil && runtime.GOOS == "darwin" && len(bytes.TrimSpace(out)) > 0 {
+			err = errors.New("security add-generic-password failed")
+		}
+		if err != nil {
+			return fmt.Errorf("%v: %s", err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	}
+)
+
+// keyMissing reports whether err, from looking the key up on goos, means
+// only that there is no key yet: security exits 44 for an item that isn't
+// there, and secret-tool exits 1 without a word. Anything else is a
+// keychain that can't be read, which must not be taken for an empty one,
+// or a new key would replace the one sealed files need.
+func keyMissing(goos string, err error) bool {
+	var exit *exec.ExitError
+	if !errors.As(err, &exit) {
+		return false
+	}
+	if goos == "darwin" {
+		return exit.ExitCode() == 44
+	}
+	return exit.ExitCode() == 1 && len(bytes.TrimSpace(exit.Stderr)) == 0
+}
@@ -0,0 +1,152 @@
+package seal
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// reset forgets the cached key and what is enabled.
+func reset(t *testing.T) {
+	t.Helper()
+	cached = nil
+	Enable(nil)
+	t.Cleanup(func() { cached = nil; Enable(nil) })
+}
+
+func TestWriteFileSealsEnabledKinds(t *testing.T) {
+	reset(t)
+	t.Setenv("HERD_STORAGE_KEY", base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{7}, 32)))
+	dir := t.TempDir()
+	secret := []byte(`{"pane":[{"text":"the database password is hunter2"}]}`)
+
+	plain := filepath.Join(dir, "plain.json")
+	if err := WriteFile("prompts", plain, secret, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	Enable([]string{"prompts"})
+	sealed := filepath.Join(dir, "sealed.json")
+	if err := WriteFile("prompts", sealed, secret, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	raw, _ := os.ReadFile(sealed)
+	if !IsSealed(raw) || bytes.Contains(raw, []byte("hunter2")) {
+		t.Fatalf("sealed file holds the text in the clear: %q", raw)
+	}
+	if info, _ := os.Stat(sealed); info.Mode().Perm() != 0o600 {
+		t.Errorf("sealed file mode = %v, want 0600", info.Mode().Perm())
+	}
+	for _, path := range []string{plain, sealed} {
+		got, err := ReadFile(path)
+		if err != nil || !bytes.Equal(got, secret) {
+			t.Errorf("ReadFile(%s) = %q, %v", filepath.Base(path), got, err)
+		}
+	}
+
+	// Another key can't open it.
+	cached = nil
+	t.Setenv("HERD_STORAGE_KEY", base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{8}, 32)))
+	if _, err := ReadFile(sealed); err == nil {
+		t.Error("a sealed file should not open with a different key")
+	}
+}
+
+func TestSealText(t *testing.T) {
+	reset(t)
+	t.Setenv("HERD_STORAGE_KEY", base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{9}, 32)))
+
+	if got, err := SealText("prompts", "deploy the fix"); err != nil || got != "deploy the fix" {
+		t.Errorf("SealText() with prompts unsealed = %q, %v", got, err)
+	}
+	Enable([]string{"prompts"})
+	sealed, err := SealText("prompts", "deploy the fix")
+	if err != nil || strings.Contains(sealed, "deploy") {
+		t.Fatalf("SealText() = %q, %v; want it sealed", sealed, err)
+	}
+	for in, want := range map[string]string{sealed: "deploy the fix", "plain": "plain"} {
+		if got, err := OpenText(in); err != nil || got != want {
+			t.Errorf("OpenText(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := OpenText("sealed:!!"); err == nil {
+		t.Error("OpenText() of corrupt text succeeded")
+	}
+}
+
+func TestStorageKeyFromKeychain(t *testing.T) {
+	reset(t)
+	t.Setenv("HERD_STORAGE_KEY", "")
+	origGet, origSet := keychainGet, keychainSet
+	defer func() { keychainGet, keychainSet = origGet, origSet }()
+	var stored string
+	keychainGet = func() (string, error) { return stored, nil }
+	keychainSet = func(encoded string) error { stored = encoded; return nil }
+
+	first, err := Seal([]byte("note"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored == "" {
+		t.Fatal("the first seal should save a new key to the keychain")
+	}
+
+	// A later run reads the same key back.
+	cached = nil
+	got, err := Open(first)
+	if err != nil || string(got) != "note" {
+		t.Errorf("Open = %q, %v", got, err)
+	}
+
+	cached = nil
+	t.Setenv("HERD_STORAGE_KEY", "too short")
+	if _, err := Seal([]byte("x")); err == nil || !strings.Contains(err.Error(), "32 bytes") {
+		t.Errorf("a bad HERD_STORAGE_KEY should be refused, got %v", err)
+	}
+}
+
+func TestKeyMissingOnlyForNoItem(t *testing.T) {
+	exit := func(script string) error {
+		_, err := exec.Command("sh", "-c", script).Output()
+		return err
+	}
+	tests := []struct {
+		goos, script string
+		want         bool
+	}{
+		{"darwin", "exit 44", true},
+		{"darwin", "exit 1", false},  // e.g. the keychain is locked
+		{"darwin", "exit 36", false}, // user interaction not allowed
+		{"linux", "exit 1", true},    // secret-tool: no such item
+		{"linux", "echo no bus >&2; exit 1", false},
+		{"linux", "exit 0", false},
+	}
+	for _, tc := range tests {
+		if got := keyMissing(tc.goos, exit(tc.script)); got != tc.want {
+			t.Errorf("keyMissing(%s, %q) = %v, want %v", tc.goos, tc.script, got, tc.want)
+		}
+	}
+	if keyMissing("darwin", errors.New("exec: not found")) {
+		t.Error("a missing security binary is not a missing key")
+	}
+}
+
+func TestStorageKeyKeepsKeychainErrors(t *testing.T) {
+	reset(t)
+	t.Setenv("HERD_STORAGE_KEY", "")
+	origGet, origSet := keychainGet, keychainSet
+	defer func() { keychainGet, keychainSet = origGet, origSet }()
+	keychainGet = func() (string, error) { return "", errors.New("keychain locked") }
+	keychainSet = func(string) error {
+		t.Error("a key was saved over one that couldn't be read")
+		return nil
+	}
+	if _, err := Seal([]byte("x")); err == nil || !strings.Contains(err.Error(), "keychain locked") {
+		t.Errorf("Seal = %v, want the keychain's error", err)
+	}
+}
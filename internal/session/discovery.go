@@ -36,14 +36,33 @@ func Discover(client tmux.ClientIface) ([]Session, error) {
 		return v
 	}
 
-	return buildSessions(panes, cachedBranch, cachedRoot), nil
+	// The process table is read at most once, and only if some pane's
+	// foreground command isn't Claude itself.
+	var procs tmux.ProcessTable
+	isClaude := func(p tmux.Pane) bool {
+		if tmux.IsClaudePane(p.CurrentCmd) {
+			return true
+		}
+		if p.PID <= 0 {
+			return false
+		}
+		if procs == nil {
+			if procs, _ = tmux.ListProcesses(); procs == nil {
+				procs = tmux.ProcessTable{}
+			}
+		}
+		return procs.ClaudeInTree(p.PID)
+	}
+
+	return buildSessions(panes, isClaude, cachedBranch, cachedRoot), nil
 }
 
-// buildSessions converts tmux panes to Sessions using the provided lookup functions.
-func buildSessions(panes []tmux.Pane, branchFn func(string) string, rootFn func(string) string) []Session {
+// buildSessions converts the tmux panes isClaude accepts to Sessions using
+// the provided lookup functions.
+func buildSessions(panes []tmux.Pane, isClaude func(tmux.Pane) bool, branchFn func(string) string, rootFn func(string) string) []Session {
 	var sessions []Session
 	for _, p := range panes {
-		if !tmux.IsClaudePane(p.CurrentCmd) {
+		if !isClaude(p) {
 			continue
 		}
 		s := Session{
@@ -14,8 +14,10 @@ import (
 func noBranch(string) string { return "" }
 func noRoot(string) string   { return "" }
 
+func byCommand(p tmux.Pane) bool { return tmux.IsClaudePane(p.CurrentCmd) }
+
 func TestBuildSessionsEmpty(t *testing.T) {
-	sessions := buildSessions(nil, noBranch, noRoot)
+	sessions := buildSessions(nil, byCommand, noBranch, noRoot)
 	if sessions != nil {
 		t.Errorf("buildSessions(nil) = %v, want nil", sessions)
 	}
@@ -27,7 +29,7 @@ func TestBuildSessionsFiltersNonClaude(t *testing.T) {
 		{ID: "%2", CurrentCmd: "vim"},
 		{ID: "%3", CurrentCmd: "zsh"},
 	}
-	sessions := buildSessions(panes, noBranch, noRoot)
+	sessions := buildSessions(panes, byCommand, noBranch, noRoot)
 	if len(sessions) != 0 {
 		t.Errorf("buildSessions with non-claude panes = %d sessions, want 0", len(sessions))
 	}
@@ -44,7 +46,7 @@ func TestBuildSessionsWithVersionString(t *testing.T) {
 			CurrentCmd:  "2.1.47",
 		},
 	}
-	sessions := buildSessions(panes, func(dir string) string {
+	sessions := buildSessions(panes, byCommand, func(dir string) string {
 		return "main"
 	}, noRoot)
 
@@ -80,7 +82,7 @@ func TestBuildSessionsWithClaudeCommand(t *testing.T) {
 		{ID: "%10", CurrentCmd: "claude", CurrentPath: "/work"},
 		{ID: "%11", CurrentCmd: "bash", CurrentPath: "/work"},
 	}
-	sessions := buildSessions(panes, noBranch, noRoot)
+	sessions := buildSessions(panes, byCommand, noBranch, noRoot)
 	if len(sessions) != 1 {
 		t.Fatalf("buildSessions = %d sessions, want 1", len(sessions))
 	}
@@ -95,7 +97,7 @@ func TestBuildSessionsMixedPanes(t *testing.T) {
 		{ID: "%2", CurrentCmd: "bash"},
 		{ID: "%3", CurrentCmd: "claude"},
 	}
-	sessions := buildSessions(panes, noBranch, noRoot)
+	sessions := buildSessions(panes, byCommand, noBranch, noRoot)
 	if len(sessions) != 2 {
 		t.Errorf("buildSessions = %d sessions, want 2 (version + claude)", len(sessions))
 	}
@@ -105,7 +107,7 @@ func TestBuildSessionsWithGitRoot(t *testing.T) {
 	panes := []tmux.Pane{
 		{ID: "%1", CurrentCmd: "claude", CurrentPath: "/home/user/project/src"},
 	}
-	sessions := buildSessions(panes, noBranch, func(dir string) string {
+	sessions := buildSessions(panes, byCommand, noBranch, func(dir string) string {
 		return "/home/user/project"
 	})
 	if len(sessions) != 1 {
@@ -122,7 +124,7 @@ func TestBuildSessionsAllClaude(t *testing.T) {
 		{ID: "%2", CurrentCmd: "2.1.47", CurrentPath: "/b"},
 		{ID: "%3", CurrentCmd: "Claude", CurrentPath: "/c"},
 	}
-	sessions := buildSessions(panes, noBranch, noRoot)
+	sessions := buildSessions(panes, byCommand, noBranch, noRoot)
 	if len(sessions) != 3 {
 		t.Errorf("buildSessions = %d sessions, want 3", len(sessions))
 	}
@@ -133,7 +135,7 @@ func TestBuildSessionsBranchFnReceivesCorrectDir(t *testing.T) {
 		{ID: "%1", CurrentCmd: "claude", CurrentPath: "/specific/path"},
 	}
 	var calledWith string
-	sessions := buildSessions(panes, func(dir string) string {
+	sessions := buildSessions(panes, byCommand, func(dir string) string {
 		calledWith = dir
 		return "feature-branch"
 	}, noRoot)
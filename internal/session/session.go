@@ -52,6 +52,37 @@ type Session struct {
 	State       State
 	CurrentTool string // set when State == StateWorking
 	UpdatedAt   time.Time
+
+	// PermissionMode is the Claude permission mode from hooks ("default",
+	// "plan", "acceptEdits", "bypassPermissions"); empty until a hook fires.
+	PermissionMode string
+
+	// Model is the model ID of the latest assistant message, from hooks.
+	Model string
+
+	// ContextTokens is how many tokens of context the latest turn used.
+	ContextTokens int
+
+	// TranscriptPath is Claude's JSONL log of the session, from hooks.
+	TranscriptPath string
+
+	// Prompt and Message are the latest prompt and notification text,
+	// from hooks when record_prompts is on.
+	Prompt  string
+	Message string
+}
+
+// ContextWindow is the context size assumed when computing utilisation.
+const ContextWindow = 200_000
+
+// ContextPercent returns context utilisation as a percentage of
+// ContextWindow, capped at 100; 0 when no usage has been reported.
+func (s Session) ContextPercent() int {
+	pct := s.ContextTokens * 100 / ContextWindow
+	if pct > 100 {
+		pct = 100
+	}
+	return pct
 }
 
 // Key returns a unique identifier for the session, suitable for pinning/ordering.
@@ -103,3 +134,22 @@ func ParseState(s string) State {
 	}
 }
 
+
+// Match returns the sessions target names: a pane ID such as "%3", a
+// Claude session ID, a label given by name, a project path, or a project's
+// directory name or DisplayName. name may be nil.
+func Match(sessions []Session, target string, name func(Session) string) []Session {
+	if target == "" {
+		return nil
+	}
+	var out []Session
+	for _, s := range sessions {
+		switch {
+		case target == s.TmuxPane, s.ID != "" && target == s.ID,
+			name != nil && target == name(s),
+			s.ProjectPath != "" && (target == s.ProjectPath || target == filepath.Base(s.ProjectPath) || target == s.DisplayName()):
+			out = append(out, s)
+		}
+	}
+	return out
+}
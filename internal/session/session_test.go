@@ -1,6 +1,7 @@
 package session
 
 import (
+	"slices"
 	"testing"
 	"time"
 )
@@ -120,3 +121,54 @@ func TestKeyEmptyBoth(t *testing.T) {
 		t.Errorf("Key() with empty fields = %q, want %q", got, "pane:")
 	}
 }
+
+func TestContextPercent(t *testing.T) {
+	tests := []struct {
+		tokens int
+		want   int
+	}{
+		{0, 0},
+		{50_000, 25},
+		{ContextWindow * 2, 100},
+	}
+	for _, tt := range tests {
+		if got := (Session{ContextTokens: tt.tokens}).ContextPercent(); got != tt.want {
+			t.Errorf("ContextPercent() with %d tokens = %d, want %d", tt.tokens, got, tt.want)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	sessions := []Session{
+		{ID: "abc", TmuxPane: "%1", ProjectPath: "/home/user/dev/herd"},
+		{TmuxPane: "%2", ProjectPath: "/home/user/dev/porter"},
+		{TmuxPane: "%3", ProjectPath: "/home/user/work/herd"},
+	}
+	name := func(s Session) string {
+		if s.TmuxPane == "%2" {
+			return "docs"
+		}
+		return ""
+	}
+	panes := func(ss []Session) []string {
+		var out []string
+		for _, s := range ss {
+			out = append(out, s.TmuxPane)
+		}
+		return out
+	}
+	for target, want := range map[string][]string{
+		"%2":                  {"%2"},
+		"abc":                 {"%1"},
+		"docs":                {"%2"},
+		"herd":                {"%1", "%3"},
+		"work/herd":           {"%3"},
+		"/home/user/dev/herd": {"%1"},
+		"nope":                nil,
+		"":                    nil,
+	} {
+		if got := panes(Match(sessions, target, name)); !slices.Equal(got, want) {
+			t.Errorf("Match(%q) = %v, want %v", target, got, want)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package session
+
+import "github.com/shnupta/herd/internal/state"
+
+// ApplyStates copies hook-written state onto matching sessions in place.
+// A session matches a state file by Claude session ID when it has one,
+// otherwise by tmux pane. Sessions with no matching state are left untouched.
+func ApplyStates(sessions []Session, states []state.SessionState) {
+	byPane := make(map[string]state.SessionState)
+	byID := make(map[string]state.SessionState)
+	for _, s := range states {
+		if s.TmuxPane != "" {
+			byPane[s.TmuxPane] = s
+		}
+		if s.SessionID != "" {
+			byID[s.SessionID] = s
+		}
+	}
+	for i, sess := range sessions {
+		var st state.SessionState
+		var found bool
+		if sess.ID != "" {
+			st, found = byID[sess.ID]
+		}
+		if !found {
+			st, found = byPane[sess.TmuxPane]
+		}
+		if !found {
+			continue
+		}
+		sessions[i].ID = st.SessionID
+		sessions[i].State = ParseState(st.State)
+		sessions[i].CurrentTool = st.CurrentTool
+		sessions[i].UpdatedAt = st.UpdatedAt
+		sessions[i].PermissionMode = st.PermissionMode
+		sessions[i].Model = st.Model
+		sessions[i].ContextTokens = st.ContextTokens
+		sessions[i].TranscriptPath = st.TranscriptPath
+		sessions[i].Prompt = st.Prompt
+		sessions[i].Message = st.Message
+	}
+}
@@ -0,0 +1,29 @@
+package session
+
+import (
+	"testing"
+
+	"github.com/shnupta/herd/internal/state"
+)
+
+func TestApplyStatesMatchesByIDThenPane(t *testing.T) {
+	sessions := []Session{
+		{ID: "known", TmuxPane: "%1"},
+		{TmuxPane: "%2"},
+		{TmuxPane: "%3"},
+	}
+	ApplyStates(sessions, []state.SessionState{
+		{SessionID: "known", TmuxPane: "%9", State: "working", CurrentTool: "Bash"},
+		{SessionID: "fresh", TmuxPane: "%2", State: "waiting", Model: "claude-opus-4-1"},
+	})
+
+	if sessions[0].State != StateWorking || sessions[0].CurrentTool != "Bash" {
+		t.Errorf("sessions[0] = %+v, want matched by ID", sessions[0])
+	}
+	if sessions[1].ID != "fresh" || sessions[1].State != StateWaiting || sessions[1].Model != "claude-opus-4-1" {
+		t.Errorf("sessions[1] = %+v, want matched by pane and ID learned", sessions[1])
+	}
+	if sessions[2].State != StateUnknown || sessions[2].ID != "" {
+		t.Errorf("sessions[2] = %+v, want untouched", sessions[2])
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 )
 
 // State represents the persisted sidebar state.
@@ -20,6 +21,7 @@ type State struct {
 type Store struct {
 	path string
 	mu   sync.Mutex
+	seen time.Time // modification time of the file when last read or written
 }
 
 // NewStore creates a new Store backed by the given file path.
@@ -33,6 +35,10 @@ func (s *Store) Load() (*State, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	return s.load()
+}
+
+func (s *Store) load() (*State, error) {
 	data, err := os.ReadFile(s.path)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -51,10 +57,28 @@ func (s *Store) Load() (*State, error) {
 	if st.Pinned == nil {
 		st.Pinned = make(map[string]int)
 	}
+	if info, err := os.Stat(s.path); err == nil {
+		s.seen = info.ModTime()
+	}
 	return &st, nil
 }
 
-// Save writes the sidebar state to disk.
+// LoadChanged reads the sidebar state if another process has saved it
+// since the store last read or wrote it; changed is false otherwise.
+func (s *Store) LoadChanged() (st *State, changed bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	info, err := os.Stat(s.path)
+	if err != nil || info.ModTime().Equal(s.seen) {
+		return nil, false, nil
+	}
+	st, err = s.load()
+	return st, err == nil, err
+}
+
+// Save writes the sidebar state to disk, through a temporary file renamed
+// into place so a reader never sees half of it.
 func (s *Store) Save(st *State) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -67,14 +91,111 @@ func (s *Store) Save(st *State) error {
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(s.path, data, 0644)
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return err
+	}
+	if info, err := os.Stat(s.path); err == nil {
+		s.seen = info.ModTime()
+	}
+	return nil
 }
 
-var defaultStore *Store
+// saveDelay is how long SaveLater gathers changes before writing them.
+const saveDelay = 300 * time.Millisecond
+
+// Writer saves sidebar state on a background goroutine. Saves arriving
+// within its delay of the first unwritten one are coalesced into a single
+// write of the latest state.
+type Writer struct {
+	store *Store
+	delay time.Duration
+
+	writing sync.Mutex // held across a write, so writes land in order
+
+	mu      sync.Mutex
+	pending *State
+	timer   *time.Timer
+	err     error // from the last background write
+}
+
+// NewWriter creates a Writer saving to store delay after a change.
+func NewWriter(store *Store, delay time.Duration) *Writer {
+	return &Writer{store: store, delay: delay}
+}
+
+// Save schedules st to be written. st is copied, so the caller may go on
+// changing it.
+func (w *Writer) Save(st *State) {
+	cp := &State{Pinned: make(map[string]int, len(st.Pinned)), Order: append([]string(nil), st.Order...)}
+	for k, v := range st.Pinned {
+		cp.Pinned[k] = v
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.pending = cp
+	if w.timer == nil {
+		w.timer = time.AfterFunc(w.delay, func() {
+			if err := w.write(); err != nil {
+				w.mu.Lock()
+				w.err = err
+				w.mu.Unlock()
+			}
+		})
+	}
+}
+
+// Pending reports whether a save is waiting to be written.
+func (w *Writer) Pending() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.pending != nil
+}
+
+// Flush writes any pending state now, returning the error of that write
+// or else of the last background one.
+func (w *Writer) Flush() error {
+	w.mu.Lock()
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+	w.mu.Unlock()
+	if err := w.write(); err != nil {
+		return err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	err := w.err
+	w.err = nil
+	return err
+}
+
+// write saves the pending state, if any.
+func (w *Writer) write() error {
+	w.writing.Lock()
+	defer w.writing.Unlock()
+	w.mu.Lock()
+	st := w.pending
+	w.pending, w.timer = nil, nil
+	w.mu.Unlock()
+	if st == nil {
+		return nil
+	}
+	return w.store.Save(st)
+}
+
+var (
+	defaultStore  *Store
+	defaultWriter *Writer
+)
 
 func init() {
 	home, _ := os.UserHomeDir()
 	defaultStore = NewStore(filepath.Join(home, ".herd", "sidebar.json"))
+	defaultWriter = NewWriter(defaultStore, saveDelay)
 }
 
 // Load reads the sidebar state from disk using the default store.
@@ -87,6 +208,27 @@ func Save(s *State) error {
 	return defaultStore.Save(s)
 }
 
+// SaveLater schedules s to be written to the default store in the
+// background; see Writer.
+func SaveLater(s *State) {
+	defaultWriter.Save(s)
+}
+
+// LoadChanged reads the default store's state if another process has
+// saved it since this one last read or wrote it, and no save of this
+// process's own is waiting to overwrite it.
+func LoadChanged() (*State, bool, error) {
+	if defaultWriter.Pending() {
+		return nil, false, nil
+	}
+	return defaultStore.LoadChanged()
+}
+
+// Flush writes any state SaveLater has pending. Call it before exiting.
+func Flush() error {
+	return defaultWriter.Flush()
+}
+
 // Cleanup removes entries for projects that are no longer active.
 func (s *State) Cleanup(activeProjects map[string]bool) {
 	// Clean pinned
@@ -1,8 +1,10 @@
 package sidebar
 
 import (
+	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestAddToOrder(t *testing.T) {
@@ -101,3 +103,83 @@ func TestStoreSaveCreatesDirectory(t *testing.T) {
 		t.Fatalf("Save() error when directory doesn't exist: %v", err)
 	}
 }
+
+func TestWriterCoalescesSaves(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sidebar.json")
+	store := NewStore(path)
+	w := NewWriter(store, 50*time.Millisecond)
+
+	st := &State{Pinned: map[string]int{}}
+	for _, p := range []string{"/a", "/b", "/c"} {
+		st.AddToOrder(p)
+		w.Save(st)
+	}
+	st.Pinned["/a"] = 1 // changed after the last save: not written
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("saved before the delay: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if _, err := os.Stat(path); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("state never written")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	got, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Order) != 3 || len(got.Pinned) != 0 {
+		t.Errorf("written %+v, want the last saved state", got)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temporary file left behind: %v", err)
+	}
+}
+
+func TestWriterFlush(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "sidebar.json"))
+	w := NewWriter(store, time.Hour)
+	w.Save(&State{Pinned: map[string]int{"/a": 1}, Order: []string{"/a"}})
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	got, err := store.Load()
+	if err != nil || got.Pinned["/a"] != 1 {
+		t.Errorf("after Flush Load() = %+v, %v", got, err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Errorf("Flush with nothing pending = %v", err)
+	}
+}
+
+func TestStoreLoadChanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sidebar.json")
+	mine, theirs := NewStore(path), NewStore(path)
+	if err := mine.Save(&State{Pinned: map[string]int{"a": 1}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, changed, _ := mine.LoadChanged(); changed {
+		t.Error("the store's own save counted as a change")
+	}
+
+	// Another process saves a moment later.
+	if err := theirs.Save(&State{Pinned: map[string]int{"a": 1, "b": 2}}); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Second)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	st, changed, err := mine.LoadChanged()
+	if err != nil || !changed || st.Pinned["b"] != 2 {
+		t.Fatalf("LoadChanged() = %v, %v, %v; want the other save", st, changed, err)
+	}
+	if _, changed, _ := mine.LoadChanged(); changed {
+		t.Error("the same change reported twice")
+	}
+}
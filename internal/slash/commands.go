@@ -0,0 +1,119 @@
+// Package slash lists the Claude Code slash commands available to a session:
+// common built-ins plus custom commands from .claude/commands directories.
+package slash
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Command is a slash command that can be sent to a Claude session.
+type Command struct {
+	Name        string // including the leading slash, e.g. "/compact"
+	Description string
+	Source      string // "built-in", "project" or "user"
+}
+
+// builtins are the Claude Code commands most useful to fire from herd.
+var builtins = []Command{
+	{Name: "/compact", Description: "Summarise the conversation to free context"},
+	{Name: "/clear", Description: "Clear conversation history"},
+	{Name: "/cost", Description: "Show token usage and cost"},
+	{Name: "/review", Description: "Review a pull request"},
+	{Name: "/model", Description: "Switch model"},
+	{Name: "/context", Description: "Show context window usage"},
+	{Name: "/status", Description: "Show session status"},
+	{Name: "/memory", Description: "Edit CLAUDE.md memory files"},
+	{Name: "/init", Description: "Create a CLAUDE.md for the project"},
+}
+
+// Discover returns the built-in commands followed by custom commands from
+// <projectPath>/.claude/commands and <userDir>/commands (normally ~/.claude).
+// A project command shadows a user command of the same name. Missing
+// directories are ignored.
+func Discover(projectPath, userDir string) []Command {
+	cmds := make([]Command, 0, len(builtins))
+	for _, c := range builtins {
+		c.Source = "built-in"
+		cmds = append(cmds, c)
+	}
+
+	seen := make(map[string]bool)
+	var custom []Command
+	if projectPath != "" {
+		for _, c := range scanDir(filepath.Join(projectPath, ".claude", "commands"), "project") {
+			seen[c.Name] = true
+			custom = append(custom, c)
+		}
+	}
+	if userDir != "" {
+		for _, c := range scanDir(filepath.Join(userDir, "commands"), "user") {
+			if !seen[c.Name] {
+				custom = append(custom, c)
+			}
+		}
+	}
+	sort.Slice(custom, func(i, j int) bool { return custom[i].Name < custom[j].Name })
+	return append(cmds, custom...)
+}
+
+// scanDir finds *.md command files under dir. Files in subdirectories are
+// namespaced the way Claude Code names them: frontend/test.md → /frontend:test.
+func scanDir(dir, source string) []Command {
+	var cmds []Command
+	_ = filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(path) != ".md" {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return nil
+		}
+		name := strings.TrimSuffix(filepath.ToSlash(rel), ".md")
+		cmds = append(cmds, Command{
+			Name:        "/" + strings.ReplaceAll(name, "/", ":"),
+			Description: describe(path),
+			Source:      source,
+		})
+		return nil
+	})
+	return cmds
+}
+
+// describe returns the "description:" frontmatter value of a command file,
+// or else its first non-blank line outside the frontmatter.
+func describe(path string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	inFrontmatter := false
+	first := true
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if first && line == "---" {
+			inFrontmatter = true
+			first = false
+			continue
+		}
+		first = false
+		if inFrontmatter {
+			if line == "---" {
+				inFrontmatter = false
+			} else if v, ok := strings.CutPrefix(line, "description:"); ok {
+				return strings.Trim(strings.TrimSpace(v), `"'`)
+			}
+			continue
+		}
+		if line != "" {
+			return strings.TrimLeft(line, "# ")
+		}
+	}
+	return ""
+}
@@ -0,0 +1,52 @@
+package slash
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDiscoverBuiltinsOnly(t *testing.T) {
+	cmds := Discover(t.TempDir(), t.TempDir())
+	if len(cmds) != len(builtins) {
+		t.Fatalf("Discover() returned %d commands, want %d built-ins", len(cmds), len(builtins))
+	}
+	if cmds[0].Name != "/compact" || cmds[0].Source != "built-in" {
+		t.Errorf("first command = %+v, want built-in /compact", cmds[0])
+	}
+}
+
+func TestDiscoverCustomCommands(t *testing.T) {
+	project := t.TempDir()
+	user := t.TempDir()
+	writeFile(t, filepath.Join(project, ".claude", "commands", "deploy.md"), "---\ndescription: \"Ship it\"\n---\nbody")
+	writeFile(t, filepath.Join(project, ".claude", "commands", "fe", "test.md"), "\n# Run frontend tests\n")
+	writeFile(t, filepath.Join(user, "commands", "deploy.md"), "user version")
+	writeFile(t, filepath.Join(user, "commands", "standup.md"), "Write my standup")
+	writeFile(t, filepath.Join(user, "commands", "notes.txt"), "ignored")
+
+	custom := Discover(project, user)[len(builtins):]
+	want := []Command{
+		{Name: "/deploy", Description: "Ship it", Source: "project"},
+		{Name: "/fe:test", Description: "Run frontend tests", Source: "project"},
+		{Name: "/standup", Description: "Write my standup", Source: "user"},
+	}
+	if len(custom) != len(want) {
+		t.Fatalf("custom commands = %+v, want %+v", custom, want)
+	}
+	for i := range want {
+		if custom[i] != want[i] {
+			t.Errorf("custom[%d] = %+v, want %+v", i, custom[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package snapshot
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+)
+
+// palette is the xterm 16-colour palette, indexed by SGR colour number.
+var palette = [16]string{
+	"#000000", "#cd3131", "#0dbc79", "#e5e510", "#2472c8", "#bc3fbc", "#11a8cd", "#e5e5e5",
+	"#666666", "#f14c4c", "#23d18b", "#f5f543", "#3b8eea", "#d670d6", "#29b8db", "#ffffff",
+}
+
+// style is the SGR state applied to a run of text.
+type style struct {
+	fg, bg                       string
+	bold, dim, italic, underline bool
+	inverse                      bool
+}
+
+func (s style) css() string {
+	fg, bg := s.fg, s.bg
+	if s.inverse {
+		fg, bg = bg, fg
+		if fg == "" {
+			fg = "var(--bg)"
+		}
+		if bg == "" {
+			bg = "var(--fg)"
+		}
+	}
+	var parts []string
+	if fg != "" {
+		parts = append(parts, "color:"+fg)
+	}
+	if bg != "" {
+		parts = append(parts, "background:"+bg)
+	}
+	if s.bold {
+		parts = append(parts, "font-weight:bold")
+	}
+	if s.dim {
+		parts = append(parts, "opacity:.6")
+	}
+	if s.italic {
+		parts = append(parts, "font-style:italic")
+	}
+	if s.underline {
+		parts = append(parts, "text-decoration:underline")
+	}
+	return strings.Join(parts, ";")
+}
+
+// ansiToHTML converts text with ANSI SGR codes into escaped HTML, each
+// styled run wrapped in a span. Other escape sequences are dropped.
+func ansiToHTML(s string) string {
+	var b strings.Builder
+	var cur style
+	open := false
+	flush := func(text string) {
+		if text == "" {
+			return
+		}
+		b.WriteString(html.EscapeString(text))
+	}
+	setStyle := func(next style) {
+		if next == cur {
+			return
+		}
+		if open {
+			b.WriteString("</span>")
+			open = false
+		}
+		cur = next
+		if css := cur.css(); css != "" {
+			fmt.Fprintf(&b, `<span style="%s">`, css)
+			open = true
+		}
+	}
+
+	for len(s) > 0 {
+		i := strings.IndexByte(s, 0x1b)
+		if i < 0 {
+			flush(s)
+			break
+		}
+		flush(s[:i])
+		s = s[i:]
+		params, final, n := parseEscape(s)
+		s = s[n:]
+		if final == 'm' {
+			setStyle(applySGR(cur, params))
+		}
+	}
+	if open {
+		b.WriteString("</span>")
+	}
+	return b.String()
+}
+
+// parseEscape reads the escape sequence at the start of s, returning a CSI
+// sequence's parameters and final byte and the bytes consumed. Non-CSI
+// sequences return final 0.
+func parseEscape(s string) (params string, final byte, n int) {
+	if len(s) < 2 {
+		return "", 0, len(s)
+	}
+	switch s[1] {
+	case '[':
+		for j := 2; j < len(s); j++ {
+			if c := s[j]; c >= 0x40 && c <= 0x7e {
+				return s[2:j], c, j + 1
+			}
+		}
+		return "", 0, len(s)
+	case ']', 'P', '_':
+		// OSC, DCS and APC run to BEL or ST.
+		for j := 2; j < len(s); j++ {
+			if s[j] == 0x07 {
+				return "", 0, j + 1
+			}
+			if s[j] == 0x1b && j+1 < len(s) && s[j+1] == '\\' {
+				return "", 0, j + 2
+			}
+		}
+		return "", 0, len(s)
+	}
+	return "", 0, 2
+}
+
+// applySGR returns s with the SGR parameters applied.
+func applySGR(s style, params string) style {
+	if params == "" {
+		return style{}
+	}
+	codes := strings.FieldsFunc(params, func(r rune) bool { return r == ';' || r == ':' })
+	for i := 0; i < len(codes); i++ {
+		c, err := strconv.Atoi(codes[i])
+		if err != nil {
+			continue
+		}
+		switch {
+		case c == 0:
+			s = style{}
+		case c == 1:
+			s.bold = true
+		case c == 2:
+			s.dim = true
+		case c == 3:
+			s.italic = true
+		case c == 4:
+			s.underline = true
+		case c == 7:
+			s.inverse = true
+		case c == 22:
+			s.bold, s.dim = false, false
+		case c == 23:
+			s.italic = false
+		case c == 24:
+			s.underline = false
+		case c == 27:
+			s.inverse = false
+		case c >= 30 && c <= 37:
+			s.fg = palette[c-30]
+		case c >= 90 && c <= 97:
+			s.fg = palette[c-90+8]
+		case c == 39:
+			s.fg = ""
+		case c >= 40 && c <= 47:
+			s.bg = palette[c-40]
+		case c >= 100 && c <= 107:
+			s.bg = palette[c-100+8]
+		case c == 49:
+			s.bg = ""
+		case c == 38 || c == 48:
+			colour, used := extendedColour(codes[i+1:])
+			i += used
+			if c == 38 {
+				s.fg = colour
+			} else {
+				s.bg = colour
+			}
+		}
+	}
+	return s
+}
+
+// extendedColour parses the arguments of SGR 38 or 48 (5;n or 2;r;g;b),
+// returning the colour and how many arguments it used.
+func extendedColour(args []string) (string, int) {
+	if len(args) == 0 {
+		return "", 0
+	}
+	nums := make([]int, 0, 4)
+	for _, a := range args {
+		n, _ := strconv.Atoi(a)
+		nums = append(nums, n)
+	}
+	switch nums[0] {
+	case 5:
+		if len(nums) < 2 {
+			return "", len(nums)
+		}
+		return colour256(nums[1]), 2
+	case 2:
+		if len(nums) < 4 {
+			return "", len(nums)
+		}
+		return fmt.Sprintf("#%02x%02x%02x", clamp(nums[1]), clamp(nums[2]), clamp(nums[3])), 4
+	}
+	return "", 1
+}
+
+// colour256 returns the hex colour of an xterm 256-colour index.
+func colour256(n int) string {
+	switch {
+	case n < 0 || n > 255:
+		return ""
+	case n < 16:
+		return palette[n]
+	case n < 232:
+		n -= 16
+		level := func(v int) int {
+			if v == 0 {
+				return 0
+			}
+			return 55 + v*40
+		}
+		return fmt.Sprintf("#%02x%02x%02x", level(n/36), level(n/6%6), level(n%6))
+	default:
+		g := 8 + (n-232)*10
+		return fmt.Sprintf("#%02x%02x%02x", g, g, g)
+	}
+}
+
+func clamp(v int) int { return max(0, min(255, v)) }
@@ -0,0 +1,145 @@
+// Package snapshot renders a session's captured output as a self-contained,
+// read-only HTML page and optionally uploads it to a paste service, for
+// sharing what an agent did.
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/shnupta/herd/internal/config"
+)
+
+// uploadTimeout bounds one upload.
+const uploadTimeout = 30 * time.Second
+
+// Meta describes the session a snapshot was taken of.
+type Meta struct {
+	Name    string
+	Project string
+	Branch  string
+	State   string
+	Taken   time.Time
+}
+
+var page = template.Must(template.New("snapshot").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Meta.Name}} — herd snapshot</title>
+<style>
+:root { --bg: #1e1e1e; --fg: #d4d4d4; }
+body { margin: 0; background: var(--bg); color: var(--fg); font-family: ui-monospace, SFMono-Regular, Menlo, Consolas, monospace; }
+header { padding: 12px 16px; border-bottom: 1px solid #333; font-size: 13px; }
+header h1 { margin: 0 0 4px; font-size: 16px; }
+header span { color: #888; margin-right: 16px; }
+pre { margin: 0; padding: 16px; font-size: 13px; line-height: 1.35; white-space: pre; overflow-x: auto; }
+</style>
+</head>
+<body>
+<header>
+<h1>{{.Meta.Name}}</h1>
+<span>{{.Meta.Project}}</span>{{if .Meta.Branch}}<span>⎇ {{.Meta.Branch}}</span>{{end}}{{if .Meta.State}}<span>{{.Meta.State}}</span>{{end}}<span>{{.Meta.Taken.Format "2006-01-02 15:04:05 MST"}}</span>
+</header>
+<pre>{{.Body}}</pre>
+</body>
+</html>
+`))
+
+// HTML renders capture, which may hold ANSI colour codes, and meta as an
+// HTML page with no external resources.
+func HTML(meta Meta, capture string) ([]byte, error) {
+	var buf bytes.Buffer
+	err := page.Execute(&buf, struct {
+		Meta Meta
+		Body template.HTML
+	}{meta, template.HTML(ansiToHTML(capture))})
+	return buf.Bytes(), err
+}
+
+// DefaultDir returns ~/.herd/snapshots.
+func DefaultDir() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".herd", "snapshots")
+}
+
+var unsafeChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// Save writes page to dir, named after meta, and returns its path.
+func Save(dir string, meta Meta, page []byte) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	name := strings.Trim(unsafeChars.ReplaceAllString(meta.Name, "-"), "-")
+	if name == "" {
+		name = "session"
+	}
+	path := filepath.Join(dir, fmt.Sprintf("%s-%s.html", name, meta.Taken.Format("20060102-150405")))
+	return path, os.WriteFile(path, page, 0o644)
+}
+
+// Upload POSTs page to the paste service u and returns the link it
+// answers with. With u.Field set the page is sent as that file field of a
+// multipart form (as 0x0.st expects); otherwise it is the raw body.
+func Upload(ctx context.Context, u config.SnapshotUpload, filename string, page []byte) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, uploadTimeout)
+	defer cancel()
+
+	body, contentType := io.Reader(bytes.NewReader(page)), "text/html; charset=utf-8"
+	if u.Field != "" {
+		var buf bytes.Buffer
+		mw := multipart.NewWriter(&buf)
+		fw, err := mw.CreateFormFile(u.Field, filename)
+		if err != nil {
+			return "", err
+		}
+		if _, err := fw.Write(page); err != nil {
+			return "", err
+		}
+		if err := mw.Close(); err != nil {
+			return "", err
+		}
+		body, contentType = &buf, mw.FormDataContentType()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.URL, body)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("User-Agent", "herd")
+	for k, v := range u.Headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("snapshot upload: %w", err)
+	}
+	defer resp.Body.Close()
+	out, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err != nil {
+		return "", fmt.Errorf("snapshot upload: %w", err)
+	}
+	if resp.StatusCode/100 != 2 {
+		return "", fmt.Errorf("snapshot upload: %s", resp.Status)
+	}
+	link := strings.TrimSpace(string(out))
+	if loc := resp.Header.Get("Location"); link == "" && loc != "" {
+		link = loc
+	}
+	if link == "" {
+		return "", fmt.Errorf("snapshot upload: empty response")
+	}
+	return link, nil
+}
@@ -0,0 +1,87 @@
+package snapshot
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shnupta/herd/internal/config"
+)
+
+func TestANSIToHTML(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"plain <b>", "plain &lt;b&gt;"},
+		{"\x1b[31mred\x1b[0m done", `<span style="color:#cd3131">red</span> done`},
+		{"\x1b[1;38;5;196mhot\x1b[m", `<span style="color:#ff0000;font-weight:bold">hot</span>`},
+		{"\x1b[48;2;1;2;3mbg\x1b[49m", `<span style="background:#010203">bg</span>`},
+		{"\x1b]0;title\x07\x1b[2Kclean", "clean"},
+	}
+	for _, tt := range tests {
+		if got := ansiToHTML(tt.in); got != tt.want {
+			t.Errorf("ansiToHTML(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestHTMLAndSave(t *testing.T) {
+	meta := Meta{Name: "api/impl", Project: "/code/api", Branch: "main", Taken: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)}
+	page, err := HTML(meta, "\x1b[32mok\x1b[0m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"<title>api/impl — herd snapshot</title>", "/code/api", `<span style="color:#0dbc79">ok</span>`} {
+		if !strings.Contains(string(page), want) {
+			t.Errorf("page missing %q", want)
+		}
+	}
+
+	path, err := Save(t.TempDir(), meta, page)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(path) != "api-impl-20250102-030405.html" {
+		t.Errorf("saved as %s", filepath.Base(path))
+	}
+	if b, _ := os.ReadFile(path); string(b) != string(page) {
+		t.Error("saved file differs from page")
+	}
+}
+
+func TestUpload(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Key") != "k" {
+			http.Error(w, "no key", http.StatusUnauthorized)
+			return
+		}
+		f, _, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		b, _ := io.ReadAll(f)
+		got = string(b)
+		io.WriteString(w, "https://paste.example/abc\n")
+	}))
+	defer srv.Close()
+
+	link, err := Upload(context.Background(), config.SnapshotUpload{URL: srv.URL, Field: "file", Headers: map[string]string{"X-Key": "k"}}, "s.html", []byte("<html>"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if link != "https://paste.example/abc" || got != "<html>" {
+		t.Errorf("Upload() = %q, server got %q", link, got)
+	}
+
+	if _, err := Upload(context.Background(), config.SnapshotUpload{URL: srv.URL, Field: "file"}, "s.html", nil); err == nil {
+		t.Error("Upload() without the header succeeded, want the 401")
+	}
+}
@@ -0,0 +1,140 @@
+// Package snippets keeps named excerpts of session output, keyed by session
+// key, in ~/.herd/snippets.json.
+package snippets
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/shnupta/herd/internal/seal"
+)
+
+// Snippet is one saved excerpt.
+type Snippet struct {
+	Name      string    `json:"name"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Store persists snippets for a specific file path.
+type Store struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewStore creates a Store backed by the given file path.
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// DefaultPath returns ~/.herd/snippets.json.
+func DefaultPath() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".herd", "snippets.json")
+}
+
+// All returns every session key's snippets, oldest first. A missing file is
+// empty.
+func (s *Store) All() (map[string][]Snippet, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.read()
+}
+
+// Get returns the snippets saved for key, oldest first.
+func (s *Store) Get(key string) []Snippet {
+	all, _ := s.All()
+	return all[key]
+}
+
+// Add saves sn under key.
+func (s *Store) Add(key string, sn Snippet) error {
+	return s.update(func(all map[string][]Snippet) {
+		all[key] = append(all[key], sn)
+	})
+}
+
+// Delete removes key's snippet at index i.
+func (s *Store) Delete(key string, i int) error {
+	return s.update(func(all map[string][]Snippet) {
+		list := all[key]
+		if i < 0 || i >= len(list) {
+			return
+		}
+		list = append(list[:i:i], list[i+1:]...)
+		if len(list) == 0 {
+			delete(all, key)
+		} else {
+			all[key] = list
+		}
+	})
+}
+
+// Rename moves oldKey's snippets to newKey, after any newKey already has.
+func (s *Store) Rename(oldKey, newKey string) error {
+	return s.update(func(all map[string][]Snippet) {
+		if list, ok := all[oldKey]; ok {
+			all[newKey] = append(all[newKey], list...)
+			delete(all, oldKey)
+		}
+	})
+}
+
+// Merge adds the snippets in m, skipping any already saved under the same
+// key with the same name and creation time.
+func (s *Store) Merge(m map[string][]Snippet) error {
+	return s.update(func(all map[string][]Snippet) {
+		for key, list := range m {
+			for _, sn := range list {
+				if !contains(all[key], sn) {
+					all[key] = append(all[key], sn)
+				}
+			}
+		}
+	})
+}
+
+func contains(list []Snippet, sn Snippet) bool {
+	for _, have := range list {
+		if have.Name == sn.Name && have.CreatedAt.Equal(sn.CreatedAt) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Store) update(fn func(map[string][]Snippet)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	all, err := s.read()
+	if err != nil {
+		return err
+	}
+	fn(all)
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return err
+	}
+	return seal.WriteFile("snippets", s.path, data, 0o644)
+}
+
+func (s *Store) read() (map[string][]Snippet, error) {
+	all := make(map[string][]Snippet)
+	data, err := seal.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return all, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+	return all, nil
+}
@@ -0,0 +1,62 @@
+package snippets
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAddDeleteRoundtrip(t *testing.T) {
+	s := NewStore(filepath.Join(t.TempDir(), "snippets.json"))
+	if got := s.Get("session:a"); len(got) != 0 {
+		t.Fatalf("empty store returned %v", got)
+	}
+	for _, name := range []string{"one", "two", "three"} {
+		if err := s.Add("session:a", Snippet{Name: name, Text: name + " text"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Delete("session:a", 1); err != nil {
+		t.Fatal(err)
+	}
+	got := NewStore(s.path).Get("session:a")
+	if len(got) != 2 || got[0].Name != "one" || got[1].Name != "three" {
+		t.Errorf("after delete = %+v", got)
+	}
+
+	_ = s.Delete("session:a", 0)
+	_ = s.Delete("session:a", 0)
+	if all, _ := s.All(); len(all) != 0 {
+		t.Errorf("key kept after deleting its last snippet: %v", all)
+	}
+}
+
+func TestRenameAppends(t *testing.T) {
+	s := NewStore(filepath.Join(t.TempDir(), "snippets.json"))
+	_ = s.Add("pane:%1", Snippet{Name: "old"})
+	_ = s.Add("session:a", Snippet{Name: "existing"})
+	if err := s.Rename("pane:%1", "session:a"); err != nil {
+		t.Fatal(err)
+	}
+	all, _ := s.All()
+	if len(all) != 1 || len(all["session:a"]) != 2 || all["session:a"][1].Name != "old" {
+		t.Errorf("after rename = %+v", all)
+	}
+}
+
+func TestMergeSkipsDuplicates(t *testing.T) {
+	s := NewStore(filepath.Join(t.TempDir(), "snippets.json"))
+	at := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	_ = s.Add("session:a", Snippet{Name: "trace", CreatedAt: at})
+	err := s.Merge(map[string][]Snippet{
+		"session:a": {{Name: "trace", CreatedAt: at}, {Name: "decision", CreatedAt: at}},
+		"session:b": {{Name: "other", CreatedAt: at}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	all, _ := s.All()
+	if len(all["session:a"]) != 2 || len(all["session:b"]) != 1 {
+		t.Errorf("after merge = %+v", all)
+	}
+}
@@ -1,11 +1,15 @@
 package state
 
 import (
+	"crypto/sha1"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"time"
+
+	"github.com/shnupta/herd/internal/seal"
 )
 
 // SessionState is written by the hook binary and read by the TUI.
@@ -16,11 +20,35 @@ type SessionState struct {
 	CurrentTool string    `json:"current_tool,omitempty"`
 	ProjectPath string    `json:"project_path,omitempty"`
 	UpdatedAt   time.Time `json:"updated_at"`
+
+	// PermissionMode is the mode reported by the latest hook event:
+	// "default", "plan", "acceptEdits" or "bypassPermissions".
+	PermissionMode string `json:"permission_mode,omitempty"`
+
+	// Model is the model that produced the latest assistant message, read
+	// from the session transcript.
+	Model string `json:"model,omitempty"`
+
+	// ContextTokens is the prompt size of the latest assistant turn.
+	ContextTokens int `json:"context_tokens,omitempty"`
+
+	// TranscriptPath is the session's transcript, as the hook was told.
+	TranscriptPath string `json:"transcript_path,omitempty"`
+
+	// Prompt is the session's latest prompt and Message its latest
+	// notification, both cut short. Hooks only record them with the
+	// record_prompts option, and they are sealed in the file when prompts
+	// are (see seal.SealText).
+	Prompt  string `json:"prompt,omitempty"`
+	Message string `json:"message,omitempty"`
 }
 
-// Store manages session state files in a directory.
+// Store manages session state files in a directory. A sharded store writes
+// each session's file into a subdirectory for its project, so hundreds of
+// sessions don't churn one directory; either way both layouts are read.
 type Store struct {
-	dir string
+	dir     string
+	sharded bool
 }
 
 // NewStore creates a new Store for the given directory.
@@ -33,36 +61,71 @@ func (s *Store) Dir() string {
 	return s.dir
 }
 
-// Path returns the state file path for a given session ID.
+// SetSharded turns writing into per-project subdirectories on or off.
+func (s *Store) SetSharded(on bool) {
+	s.sharded = on
+}
+
+// Path returns the flat-layout state file path for a given session ID.
 func (s *Store) Path(sessionID string) string {
 	return filepath.Join(s.dir, sessionID+".json")
 }
 
+// pathFor returns where ss is written: its project's subdirectory in a
+// sharded store, else the flat path.
+func (s *Store) pathFor(ss SessionState) string {
+	if !s.sharded || ss.ProjectPath == "" {
+		return s.Path(ss.SessionID)
+	}
+	return filepath.Join(s.dir, shard(ss.ProjectPath), ss.SessionID+".json")
+}
+
+// shard names a project's subdirectory: its base name, for browsing, and a
+// hash of the full path, so same-named projects don't share one.
+func shard(project string) string {
+	sum := sha1.Sum([]byte(filepath.Clean(project)))
+	return filepath.Base(project) + "-" + hex.EncodeToString(sum[:4])
+}
+
 // Write atomically writes the state for a session.
 func (s *Store) Write(ss SessionState) error {
-	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+	path := s.pathFor(ss)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
 		return fmt.Errorf("mkdir: %w", err)
 	}
 
+	var err error
+	if ss.Prompt, err = seal.SealText("prompts", ss.Prompt); err != nil {
+		return fmt.Errorf("seal: %w", err)
+	}
+	if ss.Message, err = seal.SealText("prompts", ss.Message); err != nil {
+		return fmt.Errorf("seal: %w", err)
+	}
 	data, err := json.Marshal(ss)
 	if err != nil {
 		return fmt.Errorf("marshal: %w", err)
 	}
 
 	// Write to temp file then rename for atomicity.
-	tmp := s.Path(ss.SessionID) + ".tmp"
+	tmp := path + ".tmp"
 	if err := os.WriteFile(tmp, data, 0o644); err != nil {
 		return fmt.Errorf("write tmp: %w", err)
 	}
-	if err := os.Rename(tmp, s.Path(ss.SessionID)); err != nil {
+	if err := os.Rename(tmp, path); err != nil {
 		return fmt.Errorf("rename: %w", err)
 	}
+	if flat := s.Path(ss.SessionID); path != flat {
+		// Moved into its shard: drop the copy a flat store left behind.
+		_ = os.Remove(flat)
+	}
 	return nil
 }
 
-// ReadAll loads all session state files from the state directory.
+// ReadAll loads all session state files from the state directory and its
+// project subdirectories. Should a session have a file in both, the newer
+// state wins.
 func (s *Store) ReadAll() ([]SessionState, error) {
-	entries, err := os.ReadDir(s.dir)
+	paths, err := s.files()
 	if os.IsNotExist(err) {
 		return nil, nil
 	}
@@ -71,23 +134,81 @@ func (s *Store) ReadAll() ([]SessionState, error) {
 	}
 
 	var states []SessionState
-	for _, e := range entries {
-		if e.IsDir() || filepath.Ext(e.Name()) != ".json" {
-			continue
-		}
-		data, err := os.ReadFile(filepath.Join(s.dir, e.Name()))
+	index := make(map[string]int)
+	for _, path := range paths {
+		ss, err := s.read(path)
 		if err != nil {
 			continue
 		}
-		var ss SessionState
-		if err := json.Unmarshal(data, &ss); err != nil {
+		if i, ok := index[ss.SessionID]; ok {
+			if ss.UpdatedAt.After(states[i].UpdatedAt) {
+				states[i] = ss
+			}
 			continue
 		}
+		index[ss.SessionID] = len(states)
 		states = append(states, ss)
 	}
 	return states, nil
 }
 
+// files lists the state files, those at the top level first and then those
+// one level down in project subdirectories.
+func (s *Store) files() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	var paths, nested []string
+	for _, e := range entries {
+		path := filepath.Join(s.dir, e.Name())
+		if e.IsDir() {
+			sub, err := os.ReadDir(path)
+			if err != nil {
+				continue
+			}
+			for _, f := range sub {
+				if !f.IsDir() && filepath.Ext(f.Name()) == ".json" {
+					nested = append(nested, filepath.Join(path, f.Name()))
+				}
+			}
+			continue
+		}
+		if filepath.Ext(e.Name()) == ".json" {
+			paths = append(paths, path)
+		}
+	}
+	return append(paths, nested...), nil
+}
+
+// read loads one state file.
+func (s *Store) read(path string) (SessionState, error) {
+	var ss SessionState
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ss, err
+	}
+	if err := json.Unmarshal(data, &ss); err != nil {
+		return ss, err
+	}
+	// Text that won't open is dropped rather than failing the session.
+	ss.Prompt, _ = seal.OpenText(ss.Prompt)
+	ss.Message, _ = seal.OpenText(ss.Message)
+	return ss, nil
+}
+
+// Remove deletes the state file for a session, in whichever layout it was
+// written; a missing file is not an error.
+func (s *Store) Remove(sessionID string) error {
+	paths, _ := filepath.Glob(filepath.Join(s.dir, "*", sessionID+".json"))
+	for _, path := range append(paths, s.Path(sessionID)) {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
 var defaultStore *Store
 
 func init() {
@@ -98,7 +219,10 @@ func init() {
 // Dir returns the directory where state files are stored.
 func Dir() string { return defaultStore.Dir() }
 
-// Path returns the state file path for a given session ID.
+// SetSharded turns writing into per-project subdirectories on or off.
+func SetSharded(on bool) { defaultStore.SetSharded(on) }
+
+// Path returns the flat-layout state file path for a given session ID.
 func Path(sessionID string) string { return defaultStore.Path(sessionID) }
 
 // Write atomically writes the state for a session.
@@ -106,3 +230,6 @@ func Write(ss SessionState) error { return defaultStore.Write(ss) }
 
 // ReadAll loads all session state files from the state directory.
 func ReadAll() ([]SessionState, error) { return defaultStore.ReadAll() }
+
+// Remove deletes the state file for a session.
+func Remove(sessionID string) error { return defaultStore.Remove(sessionID) }
@@ -1,10 +1,15 @@
 package state
 
 import (
+	"bytes"
+	"encoding/base64"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/shnupta/herd/internal/seal"
 )
 
 func TestStoreDir(t *testing.T) {
@@ -240,3 +245,117 @@ func TestStoreReadAllSkipsUnreadableFile(t *testing.T) {
 		t.Errorf("ReadAll() = %d, want 1 (unreadable file skipped)", len(states))
 	}
 }
+
+func TestStoreRemove(t *testing.T) {
+	s := NewStore(t.TempDir())
+	if err := s.Write(SessionState{SessionID: "gone", State: "idle"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Remove("gone"); err != nil {
+		t.Fatalf("Remove() error: %v", err)
+	}
+	if states, _ := s.ReadAll(); len(states) != 0 {
+		t.Errorf("ReadAll() after Remove = %v, want none", states)
+	}
+	if err := s.Remove("gone"); err != nil {
+		t.Errorf("Remove() of a missing file = %v, want nil", err)
+	}
+}
+
+func TestShardedStore(t *testing.T) {
+	dir := t.TempDir()
+	s := NewStore(dir)
+
+	// A session first written flat, by an unsharded store.
+	flat := SessionState{SessionID: "s1", ProjectPath: "/code/api", State: "idle", UpdatedAt: time.Now()}
+	if err := s.Write(flat); err != nil {
+		t.Fatal(err)
+	}
+
+	s.SetSharded(true)
+	moved := flat
+	moved.State = "working"
+	moved.UpdatedAt = flat.UpdatedAt.Add(time.Second)
+	if err := s.Write(moved); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Write(SessionState{SessionID: "s2", ProjectPath: "/other/api", State: "waiting", UpdatedAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Write(SessionState{SessionID: "s3", State: "idle"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(s.Path("s1")); !os.IsNotExist(err) {
+		t.Error("the flat copy should go once the session is written to its shard")
+	}
+	shards, _ := filepath.Glob(filepath.Join(dir, "api-*"))
+	if len(shards) != 2 {
+		t.Errorf("shards = %q, want one per project even with the same name", shards)
+	}
+	if _, err := os.Stat(s.Path("s3")); err != nil {
+		t.Error("a session without a project should stay flat")
+	}
+
+	states, err := s.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]string{}
+	for _, ss := range states {
+		got[ss.SessionID] = ss.State
+	}
+	if len(states) != 3 || got["s1"] != "working" || got["s2"] != "waiting" || got["s3"] != "idle" {
+		t.Errorf("ReadAll() = %v", got)
+	}
+
+	// Both layouts at once, as after an older hook wrote flat again: the
+	// newer state wins.
+	stale := flat
+	stale.UpdatedAt = flat.UpdatedAt.Add(-time.Hour)
+	s.SetSharded(false)
+	if err := s.Write(stale); err != nil {
+		t.Fatal(err)
+	}
+	if states, _ := s.ReadAll(); len(states) != 3 {
+		t.Errorf("ReadAll() = %d states, want a session in both layouts once", len(states))
+	} else {
+		for _, ss := range states {
+			if ss.SessionID == "s1" && ss.State != "working" {
+				t.Errorf("s1 = %s, want the newer working state", ss.State)
+			}
+		}
+	}
+
+	for _, id := range []string{"s1", "s2", "s3"} {
+		if err := s.Remove(id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if states, _ := s.ReadAll(); len(states) != 0 {
+		t.Errorf("ReadAll() after Remove = %v, want none", states)
+	}
+}
+
+func TestStoreSealsPromptText(t *testing.T) {
+	t.Setenv("HERD_STORAGE_KEY", base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{6}, 32)))
+	seal.Enable([]string{"prompts"})
+	t.Cleanup(func() { seal.Enable(nil) })
+
+	store := NewStore(t.TempDir())
+	ss := SessionState{SessionID: "s1", State: "notifying", Prompt: "rotate the prod keys", Message: "needs your permission"}
+	if err := store.Write(ss); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(store.Path("s1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "prod keys") || strings.Contains(string(data), "permission") {
+		t.Errorf("prompt text written in the clear: %s", data)
+	}
+	all, err := store.ReadAll()
+	if err != nil || len(all) != 1 || all[0].Prompt != ss.Prompt || all[0].Message != ss.Message {
+		t.Errorf("ReadAll() = %+v, %v; want the text opened", all, err)
+	}
+}
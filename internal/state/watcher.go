@@ -2,6 +2,8 @@ package state
 
 import (
 	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/fsnotify/fsnotify"
 )
@@ -17,12 +19,16 @@ type WatcherIface interface {
 // compile-time check
 var _ WatcherIface = (*Watcher)(nil)
 
-// Watcher watches ~/.herd/sessions/ for state file changes.
+// pollInterval is how often a polling watcher rescans the state directory.
+const pollInterval = time.Second
+
+// Watcher watches ~/.herd/sessions/ for state file changes, through
+// fsnotify or, where that can't watch the directory, by polling it.
 type Watcher struct {
 	events chan SessionState
 	errors chan error
 	done   chan struct{}
-	fw     *fsnotify.Watcher
+	fw     *fsnotify.Watcher // nil when polling
 	store  *Store
 }
 
@@ -40,13 +46,19 @@ func NewWatcherForStore(store *Store) (*Watcher, error) {
 		return nil, err
 	}
 
+	// Exhausted inotify limits or a filesystem fsnotify can't watch, such
+	// as some network homes, fall back to rescanning the directory, as does
+	// HERD_STATE_POLL for one where events never arrive.
+	if os.Getenv("HERD_STATE_POLL") != "" {
+		return newPollingWatcher(store, pollInterval), nil
+	}
 	fw, err := fsnotify.NewWatcher()
 	if err != nil {
-		return nil, err
+		return newPollingWatcher(store, pollInterval), nil
 	}
-	if err := fw.Add(store.Dir()); err != nil {
+	if err := addDirs(fw, store.Dir()); err != nil {
 		fw.Close()
-		return nil, err
+		return newPollingWatcher(store, pollInterval), nil
 	}
 
 	w := &Watcher{
@@ -60,6 +72,39 @@ func NewWatcherForStore(store *Store) (*Watcher, error) {
 	return w, nil
 }
 
+// addDirs watches dir and its project subdirectories; fsnotify doesn't
+// watch recursively.
+func addDirs(fw *fsnotify.Watcher, dir string) error {
+	if err := fw.Add(dir); err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			if err := fw.Add(filepath.Join(dir, e.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// send delivers the state in the file at path, dropping it if the reader
+// has fallen behind.
+func (w *Watcher) send(path string) {
+	ss, err := w.store.read(path)
+	if err != nil {
+		return
+	}
+	select {
+	case w.events <- ss:
+	default:
+	}
+}
+
 func (w *Watcher) loop() {
 	defer close(w.events)
 	for {
@@ -74,22 +119,25 @@ func (w *Watcher) loop() {
 			if event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
 				continue
 			}
-			if len(event.Name) < 5 || event.Name[len(event.Name)-5:] != ".json" {
-				continue
-			}
-			states, err := w.store.ReadAll()
-			if err != nil {
-				continue
-			}
-			for _, s := range states {
-				if w.store.Path(s.SessionID) == event.Name {
-					select {
-					case w.events <- s:
-					default:
+			if filepath.Dir(event.Name) == w.store.Dir() && event.Op&fsnotify.Create != 0 {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					// A new project subdirectory: watch it, and catch up on
+					// files written before the watch began.
+					_ = w.fw.Add(event.Name)
+					if entries, err := os.ReadDir(event.Name); err == nil {
+						for _, e := range entries {
+							if filepath.Ext(e.Name()) == ".json" {
+								w.send(filepath.Join(event.Name, e.Name()))
+							}
+						}
 					}
-					break
+					continue
 				}
 			}
+			if filepath.Ext(event.Name) != ".json" {
+				continue
+			}
+			w.send(event.Name)
 		case err, ok := <-w.fw.Errors:
 			if !ok {
 				return
@@ -102,8 +150,76 @@ func (w *Watcher) loop() {
 	}
 }
 
+// Polling reports whether the watcher rescans the directory rather than
+// being told of changes.
+func (w *Watcher) Polling() bool { return w.fw == nil }
+
+// newPollingWatcher starts a watcher that rescans store's directory every
+// interval.
+func newPollingWatcher(store *Store, interval time.Duration) *Watcher {
+	w := &Watcher{
+		events: make(chan SessionState, 16),
+		errors: make(chan error, 4),
+		done:   make(chan struct{}),
+		store:  store,
+	}
+	go w.poll(w.scan(nil), interval)
+	return w
+}
+
+// fileStamp is what a scan compares to spot a changed file. Size catches
+// rewrites within the mtime granularity of coarse filesystems.
+type fileStamp struct {
+	mod  time.Time
+	size int64
+}
+
+// poll rescans every interval, starting from the stamps in seen.
+func (w *Watcher) poll(seen map[string]fileStamp, interval time.Duration) {
+	defer close(w.events)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+			seen = w.scan(seen)
+		}
+	}
+}
+
+// scan stamps the state files and sends the states of those new or changed
+// since seen. The first scan, with seen nil, only takes stamps, as fsnotify
+// reports only changes made after it starts.
+func (w *Watcher) scan(seen map[string]fileStamp) map[string]fileStamp {
+	paths, err := w.store.files()
+	if err != nil {
+		select {
+		case w.errors <- err:
+		default:
+		}
+		return seen
+	}
+	stamps := make(map[string]fileStamp, len(paths))
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		stamp := fileStamp{mod: info.ModTime(), size: info.Size()}
+		stamps[path] = stamp
+		if seen != nil && seen[path] != stamp {
+			w.send(path)
+		}
+	}
+	return stamps
+}
+
 // Close stops the watcher.
 func (w *Watcher) Close() {
 	close(w.done)
-	w.fw.Close()
+	if w.fw != nil {
+		w.fw.Close()
+	}
 }
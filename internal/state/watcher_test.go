@@ -267,3 +267,102 @@ func TestWatcherConcurrentAccess(t *testing.T) {
 		t.Fatal("timed out waiting for any concurrent event")
 	}
 }
+
+func TestPollingWatcherSeesChanges(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir)
+
+	before := SessionState{SessionID: "before", State: "idle", UpdatedAt: time.Now()}
+	if err := store.Write(before); err != nil {
+		t.Fatal(err)
+	}
+	w := newPollingWatcher(store, 10*time.Millisecond)
+	defer w.Close()
+	if !w.Polling() {
+		t.Fatal("Polling() = false")
+	}
+
+	next := func() SessionState {
+		t.Helper()
+		select {
+		case got := <-w.Events():
+			return got
+		case <-time.After(3 * time.Second):
+			t.Fatal("timed out waiting for a polled event")
+		}
+		return SessionState{}
+	}
+
+	// A file there from the start is only reported once it changes.
+	if err := store.Write(SessionState{SessionID: "new", State: "working", UpdatedAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	if got := next(); got.SessionID != "new" || got.State != "working" {
+		t.Errorf("got %s %s, want the new session working", got.SessionID, got.State)
+	}
+	before.State = "waiting"
+	if err := store.Write(before); err != nil {
+		t.Fatal(err)
+	}
+	if got := next(); got.SessionID != "before" || got.State != "waiting" {
+		t.Errorf("got %s %s, want before waiting", got.SessionID, got.State)
+	}
+
+	select {
+	case got := <-w.Events():
+		t.Errorf("unexpected event for %s", got.SessionID)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestWatcherPollsWhenAsked(t *testing.T) {
+	t.Setenv("HERD_STATE_POLL", "1")
+	w, err := NewWatcherForStore(NewStore(t.TempDir()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	if !w.Polling() {
+		t.Error("HERD_STATE_POLL should make the watcher poll")
+	}
+}
+
+func TestWatcherSeesShardedFiles(t *testing.T) {
+	for _, polling := range []bool{false, true} {
+		dir := t.TempDir()
+		store := NewStore(dir)
+		store.SetSharded(true)
+		if err := store.Write(SessionState{SessionID: "old", ProjectPath: "/code/old", State: "idle", UpdatedAt: time.Now()}); err != nil {
+			t.Fatal(err)
+		}
+
+		var w *Watcher
+		if polling {
+			w = newPollingWatcher(store, 10*time.Millisecond)
+		} else {
+			var err error
+			if w, err = NewWatcherForStore(store); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		// One shard existed when the watch began, the other is new.
+		for _, ss := range []SessionState{
+			{SessionID: "old", ProjectPath: "/code/old", State: "working", UpdatedAt: time.Now()},
+			{SessionID: "new", ProjectPath: "/code/new", State: "waiting", UpdatedAt: time.Now()},
+		} {
+			if err := store.Write(ss); err != nil {
+				t.Fatal(err)
+			}
+			select {
+			case got := <-w.Events():
+				if got.SessionID != ss.SessionID || got.State != ss.State {
+					t.Errorf("polling=%v: got %s %s, want %s %s", polling, got.SessionID, got.State, ss.SessionID, ss.State)
+				}
+			case <-time.After(3 * time.Second):
+				t.Fatalf("polling=%v: timed out waiting for %s", polling, ss.SessionID)
+			}
+		}
+		w.Close()
+	}
+}
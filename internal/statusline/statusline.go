@@ -0,0 +1,81 @@
+// Package statusline renders herd's summary for the tmux status bar, in
+// tmux's format syntax, for use as #(herd status-line) in status-right.
+package statusline
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/shnupta/herd/internal/daemon"
+)
+
+// nameWidth caps each per-session segment's name.
+const nameWidth = 12
+
+// attention ranks the states that need the user, most urgent first.
+var attention = map[string]int{
+	"waiting":    0,
+	"plan_ready": 1,
+	"notifying":  2,
+}
+
+var icons = map[string]string{
+	"waiting":    "⏸",
+	"plan_ready": "📋",
+	"notifying":  "🔔",
+}
+
+// Format summarises the sessions needing attention. With perSession 0 it is
+// a count ("⏸ 2"); otherwise it names up to perSession of them, most urgent
+// and longest-waiting first, and counts the rest ("⏸ api 📋 web +1"). It is
+// empty when nothing needs attention.
+func Format(sessions []daemon.SessionInfo, perSession int) string {
+	var need []daemon.SessionInfo
+	for _, s := range sessions {
+		if _, ok := attention[s.State]; ok {
+			need = append(need, s)
+		}
+	}
+	if len(need) == 0 {
+		return ""
+	}
+	if perSession <= 0 {
+		return fmt.Sprintf("#[fg=yellow]⏸ %d#[default]", len(need))
+	}
+
+	sort.SliceStable(need, func(i, j int) bool {
+		if ri, rj := attention[need[i].State], attention[need[j].State]; ri != rj {
+			return ri < rj
+		}
+		return need[i].UpdatedAt.Before(need[j].UpdatedAt)
+	})
+	var parts []string
+	for _, s := range need[:min(perSession, len(need))] {
+		parts = append(parts, icons[s.State]+" "+escape(label(s)))
+	}
+	if rest := len(need) - perSession; rest > 0 {
+		parts = append(parts, fmt.Sprintf("+%d", rest))
+	}
+	return "#[fg=yellow]" + strings.Join(parts, " ") + "#[default]"
+}
+
+// label is the session's custom name, or its project directory's name,
+// shortened to nameWidth.
+func label(s daemon.SessionInfo) string {
+	name := s.Name
+	if name == "" {
+		name = filepath.Base(s.ProjectPath)
+	}
+	if utf8.RuneCountInString(name) > nameWidth {
+		name = string([]rune(name)[:nameWidth-1]) + "…"
+	}
+	return name
+}
+
+// escape doubles # so tmux prints names literally.
+func escape(s string) string {
+	return strings.ReplaceAll(s, "#", "##")
+}
@@ -0,0 +1,35 @@
+package statusline
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shnupta/herd/internal/daemon"
+)
+
+func TestFormat(t *testing.T) {
+	t0 := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	sessions := []daemon.SessionInfo{
+		{State: "working", ProjectPath: "/code/busy"},
+		{State: "notifying", ProjectPath: "/code/ping", UpdatedAt: t0},
+		{State: "waiting", ProjectPath: "/code/web", UpdatedAt: t0.Add(time.Minute)},
+		{State: "waiting", Name: "api #2", ProjectPath: "/code/api", UpdatedAt: t0},
+		{State: "plan_ready", Name: "a-very-long-session-name", UpdatedAt: t0},
+	}
+	tests := []struct {
+		perSession int
+		want       string
+	}{
+		{0, "#[fg=yellow]⏸ 4#[default]"},
+		{2, "#[fg=yellow]⏸ api ##2 ⏸ web +2#[default]"},
+		{4, "#[fg=yellow]⏸ api ##2 ⏸ web 📋 a-very-long… 🔔 ping#[default]"},
+	}
+	for _, tt := range tests {
+		if got := Format(sessions, tt.perSession); got != tt.want {
+			t.Errorf("Format(%d) = %q, want %q", tt.perSession, got, tt.want)
+		}
+	}
+	if got := Format(sessions[:1], 3); got != "" {
+		t.Errorf("Format() with nothing waiting = %q, want empty", got)
+	}
+}
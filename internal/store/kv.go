@@ -73,6 +73,38 @@ func (s *Store) Delete(key string) error {
 	return s.save()
 }
 
+// Rename moves the value stored under oldKey to newKey and persists to disk.
+// It is a no-op when oldKey has no value. An existing value under newKey is
+// never overwritten; in that case the stale oldKey entry is simply dropped.
+func (s *Store) Rename(oldKey, newKey string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.data[oldKey]
+	if !ok || oldKey == newKey {
+		return nil
+	}
+	if _, exists := s.data[newKey]; !exists {
+		s.data[newKey] = v
+	}
+	delete(s.data, oldKey)
+	return s.save()
+}
+
+// Merge sets every key-value pair in m and persists to disk once. Keys not in
+// m are left untouched; empty values delete their key, as with Set.
+func (s *Store) Merge(m map[string]string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k, v := range m {
+		if v == "" {
+			delete(s.data, k)
+		} else {
+			s.data[k] = v
+		}
+	}
+	return s.save()
+}
+
 // All returns a copy of all key-value pairs.
 func (s *Store) All() map[string]string {
 	s.mu.Lock()
@@ -145,3 +145,70 @@ func TestAllReturnsCopy(t *testing.T) {
 		t.Fatalf("store mutated via All() copy: got %q for new key", got)
 	}
 }
+
+func TestRenameMovesValue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.json")
+	s := NewStore(path)
+	_ = s.Set("old", "v")
+
+	if err := s.Rename("old", "new"); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Get("new"); got != "v" {
+		t.Fatalf("expected \"v\" under new key, got %q", got)
+	}
+	if got := s.Get("old"); got != "" {
+		t.Fatalf("expected old key removed, got %q", got)
+	}
+
+	s2 := NewStore(path)
+	_ = s2.Load()
+	if got := s2.Get("new"); got != "v" {
+		t.Fatalf("expected rename persisted, got %q", got)
+	}
+}
+
+func TestRenameKeepsExistingDestination(t *testing.T) {
+	s := NewStore(filepath.Join(t.TempDir(), "data.json"))
+	_ = s.Set("old", "stale")
+	_ = s.Set("new", "current")
+
+	if err := s.Rename("old", "new"); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Get("new"); got != "current" {
+		t.Fatalf("destination overwritten: got %q, want \"current\"", got)
+	}
+	if got := s.Get("old"); got != "" {
+		t.Fatalf("expected old key dropped, got %q", got)
+	}
+}
+
+func TestRenameMissingKeyIsNoop(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.json")
+	s := NewStore(path)
+	if err := s.Rename("missing", "new"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected no file written for a no-op rename, stat err = %v", err)
+	}
+}
+
+func TestMergeSetsAndDeletes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.json")
+	s := NewStore(path)
+	_ = s.Set("keep", "1")
+	_ = s.Set("drop", "2")
+
+	if err := s.Merge(map[string]string{"drop": "", "add": "3"}); err != nil {
+		t.Fatal(err)
+	}
+
+	s2 := NewStore(path)
+	_ = s2.Load()
+	want := map[string]string{"keep": "1", "add": "3"}
+	if got := s2.All(); len(got) != len(want) || got["keep"] != "1" || got["add"] != "3" {
+		t.Fatalf("All() after Merge = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,230 @@
+// Package tasks lists open tickets from issue trackers configured in
+// task_sources, so a session can be started for one.
+package tasks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/shnupta/herd/internal/config"
+)
+
+// requestTimeout bounds one listing request.
+const requestTimeout = 15 * time.Second
+
+// Ticket is one open ticket.
+type Ticket struct {
+	ID          string
+	Title       string
+	URL         string
+	Description string
+
+	// Project is the directory a session for this ticket starts in, from the
+	// source's config.
+	Project string
+}
+
+// Source lists open tickets from one tracker.
+type Source interface {
+	Name() string
+	List(ctx context.Context) ([]Ticket, error)
+}
+
+// FromConfig builds the Source described by c.
+func FromConfig(c config.TaskSource) (Source, error) {
+	token := c.Token
+	if c.TokenEnv != "" {
+		token = os.Getenv(c.TokenEnv)
+	}
+	if token == "" {
+		return nil, fmt.Errorf("task source %q: no token (set token or token_env)", c.Label())
+	}
+	switch strings.ToLower(c.Type) {
+	case "jira":
+		if c.URL == "" {
+			return nil, fmt.Errorf("task source %q: jira needs url", c.Label())
+		}
+		jql := c.Query
+		if jql == "" {
+			jql = "assignee = currentUser() AND statusCategory != Done ORDER BY updated DESC"
+		}
+		return &Jira{BaseURL: strings.TrimRight(c.URL, "/"), Email: c.Email, Token: token, JQL: jql, Project: c.Project}, nil
+	case "linear":
+		endpoint := c.URL
+		if endpoint == "" {
+			endpoint = "https://api.linear.app/graphql"
+		}
+		return &Linear{Endpoint: endpoint, Token: token, Project: c.Project}, nil
+	default:
+		return nil, fmt.Errorf("task source %q: unknown type %q", c.Label(), c.Type)
+	}
+}
+
+// Jira lists issues matching a JQL query through the REST API v2.
+type Jira struct {
+	BaseURL string
+	Email   string // with Token, for basic auth; empty means Token is a bearer PAT
+	Token   string
+	JQL     string
+	Project string
+}
+
+func (j *Jira) Name() string { return "jira" }
+
+func (j *Jira) List(ctx context.Context) ([]Ticket, error) {
+	q := url.Values{
+		"jql":        {j.JQL},
+		"fields":     {"summary,description"},
+		"maxResults": {"50"},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, j.BaseURL+"/rest/api/2/search?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if j.Email != "" {
+		req.SetBasicAuth(j.Email, j.Token)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+j.Token)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	var resp struct {
+		Issues []struct {
+			Key    string `json:"key"`
+			Fields struct {
+				Summary     string `json:"summary"`
+				Description string `json:"description"`
+			} `json:"fields"`
+		} `json:"issues"`
+	}
+	if err := do(req, &resp); err != nil {
+		return nil, fmt.Errorf("jira: %w", err)
+	}
+	tickets := make([]Ticket, 0, len(resp.Issues))
+	for _, is := range resp.Issues {
+		tickets = append(tickets, Ticket{
+			ID:          is.Key,
+			Title:       is.Fields.Summary,
+			URL:         j.BaseURL + "/browse/" + is.Key,
+			Description: is.Fields.Description,
+			Project:     j.Project,
+		})
+	}
+	return tickets, nil
+}
+
+// Linear lists the viewer's open assigned issues through the GraphQL API.
+type Linear struct {
+	Endpoint string
+	Token    string
+	Project  string
+}
+
+func (l *Linear) Name() string { return "linear" }
+
+const linearQuery = `{ viewer { assignedIssues(first: 50, filter: {state: {type: {nin: ["completed", "canceled"]}}}) { nodes { identifier title url description } } } }`
+
+func (l *Linear) List(ctx context.Context) ([]Ticket, error) {
+	body, _ := json.Marshal(map[string]string{"query": linearQuery})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, l.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", l.Token)
+	req.Header.Set("Content-Type", "application/json")
+
+	var resp struct {
+		Data struct {
+			Viewer struct {
+				AssignedIssues struct {
+					Nodes []struct {
+						Identifier  string `json:"identifier"`
+						Title       string `json:"title"`
+						URL         string `json:"url"`
+						Description string `json:"description"`
+					} `json:"nodes"`
+				} `json:"assignedIssues"`
+			} `json:"viewer"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := do(req, &resp); err != nil {
+		return nil, fmt.Errorf("linear: %w", err)
+	}
+	if len(resp.Errors) > 0 {
+		return nil, fmt.Errorf("linear: %s", resp.Errors[0].Message)
+	}
+	nodes := resp.Data.Viewer.AssignedIssues.Nodes
+	tickets := make([]Ticket, 0, len(nodes))
+	for _, n := range nodes {
+		tickets = append(tickets, Ticket{
+			ID:          n.Identifier,
+			Title:       n.Title,
+			URL:         n.URL,
+			Description: n.Description,
+			Project:     l.Project,
+		})
+	}
+	return tickets, nil
+}
+
+// do sends req and decodes a 2xx JSON response into v.
+func do(req *http.Request, v any) error {
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// ListAll lists every configured source, skipping ones that fail. Tickets
+// keep source order; errors are returned alongside.
+func ListAll(sources []config.TaskSource) ([]Ticket, []error) {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	var all []Ticket
+	var errs []error
+	for _, c := range sources {
+		src, err := FromConfig(c)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		tickets, err := src.List(ctx)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		all = append(all, tickets...)
+	}
+	return all, errs
+}
+
+// Prompt is the opening prompt for a session started on t.
+func (t Ticket) Prompt() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Work on %s: %s", t.ID, t.Title)
+	if t.URL != "" {
+		fmt.Fprintf(&sb, " (%s)", t.URL)
+	}
+	if d := strings.TrimSpace(t.Description); d != "" {
+		sb.WriteString("\n\n" + d)
+	}
+	return sb.String()
+}
@@ -0,0 +1,89 @@
+package tasks
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/shnupta/herd/internal/config"
+)
+
+func TestJiraList(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "me@acme.io" || pass != "tok" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/rest/api/2/search" || !strings.Contains(r.URL.Query().Get("jql"), "currentUser()") {
+			t.Errorf("request = %s", r.URL)
+		}
+		io.WriteString(w, `{"issues":[{"key":"API-7","fields":{"summary":"Fix login","description":"It 500s"}}]}`)
+	}))
+	defer srv.Close()
+
+	src, err := FromConfig(config.TaskSource{Type: "jira", URL: srv.URL, Email: "me@acme.io", Token: "tok", Project: "/code/api"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tickets, err := src.List(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Ticket{ID: "API-7", Title: "Fix login", URL: srv.URL + "/browse/API-7", Description: "It 500s", Project: "/code/api"}
+	if len(tickets) != 1 || tickets[0] != want {
+		t.Errorf("List() = %+v, want [%+v]", tickets, want)
+	}
+}
+
+func TestLinearList(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "lin_tok" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		io.WriteString(w, `{"data":{"viewer":{"assignedIssues":{"nodes":[{"identifier":"WEB-3","title":"Dark mode","url":"https://linear.app/x/WEB-3"}]}}}}`)
+	}))
+	defer srv.Close()
+
+	t.Setenv("LINEAR_TOKEN", "lin_tok")
+	src, err := FromConfig(config.TaskSource{Type: "linear", URL: srv.URL, TokenEnv: "LINEAR_TOKEN"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tickets, err := src.List(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tickets) != 1 || tickets[0].ID != "WEB-3" || tickets[0].Title != "Dark mode" {
+		t.Errorf("List() = %+v", tickets)
+	}
+}
+
+func TestListAllKeepsWorkingSources(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	tickets, errs := ListAll([]config.TaskSource{
+		{Type: "jira", URL: srv.URL, Token: "t"},
+		{Type: "trello", Token: "t"},
+		{Type: "linear"},
+	})
+	if len(tickets) != 0 || len(errs) != 3 {
+		t.Fatalf("ListAll() = %v, %v; want three errors", tickets, errs)
+	}
+	if !strings.Contains(errs[0].Error(), "500") || !strings.Contains(errs[1].Error(), "unknown type") || !strings.Contains(errs[2].Error(), "no token") {
+		t.Errorf("errs = %v", errs)
+	}
+}
+
+func TestTicketPrompt(t *testing.T) {
+	got := Ticket{ID: "API-7", Title: "Fix login", URL: "https://j/API-7", Description: "It 500s\n"}.Prompt()
+	if want := "Work on API-7: Fix login (https://j/API-7)\n\nIt 500s"; got != want {
+		t.Errorf("Prompt() = %q, want %q", got, want)
+	}
+}
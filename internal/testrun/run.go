@@ -0,0 +1,115 @@
+// Package testrun runs a project's test command in the background and keeps
+// its combined output and result for display.
+package testrun
+
+import (
+	"bytes"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// maxOutput bounds the retained output; older bytes are dropped first since
+// the tail (failures, summary) is what matters.
+const maxOutput = 256 * 1024
+
+// Status is the lifecycle state of a Run.
+type Status int
+
+const (
+	Running Status = iota
+	Passed
+	Failed
+)
+
+func (s Status) String() string {
+	switch s {
+	case Running:
+		return "running"
+	case Passed:
+		return "passed"
+	default:
+		return "failed"
+	}
+}
+
+// Run is one execution of a test command.
+type Run struct {
+	Command string
+	Dir     string
+	Started time.Time
+
+	mu       sync.Mutex
+	out      bytes.Buffer
+	status   Status
+	finished time.Time
+	err      error
+}
+
+// Start launches command through `sh -c` in dir and returns immediately.
+// The returned Run collects output until the command exits.
+func Start(dir, command string) (*Run, error) {
+	r := &Run{Command: command, Dir: dir, Started: time.Now()}
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Dir = dir
+	cmd.Stdout = r
+	cmd.Stderr = r
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	go func() {
+		err := cmd.Wait()
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.finished = time.Now()
+		r.err = err
+		if err == nil {
+			r.status = Passed
+		} else {
+			r.status = Failed
+		}
+	}()
+	return r, nil
+}
+
+// Write appends command output. It implements io.Writer for exec.Cmd.
+func (r *Run) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.out.Write(p)
+	if over := r.out.Len() - maxOutput; over > 0 {
+		r.out.Next(over)
+	}
+	return len(p), nil
+}
+
+// Output returns the output collected so far.
+func (r *Run) Output() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.out.String()
+}
+
+// Status returns the current status.
+func (r *Run) Status() Status {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.status
+}
+
+// Err returns the command's exit error once it has failed, else nil.
+func (r *Run) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// Elapsed returns how long the run took, or has taken so far.
+func (r *Run) Elapsed() time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.finished.IsZero() {
+		return time.Since(r.Started)
+	}
+	return r.finished.Sub(r.Started)
+}
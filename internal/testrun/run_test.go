@@ -0,0 +1,57 @@
+package testrun
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func waitDone(t *testing.T, r *Run) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for r.Status() == Running {
+		if time.Now().After(deadline) {
+			t.Fatal("run did not finish")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestRunPasses(t *testing.T) {
+	dir := t.TempDir()
+	r, err := Start(dir, "echo ok; pwd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitDone(t, r)
+	if r.Status() != Passed {
+		t.Errorf("Status() = %v, want passed (err %v)", r.Status(), r.Err())
+	}
+	if out := r.Output(); !strings.Contains(out, "ok") || !strings.Contains(out, dir) {
+		t.Errorf("Output() = %q, want echo output run in %s", out, dir)
+	}
+}
+
+func TestRunFailsCapturesStderr(t *testing.T) {
+	r, err := Start(t.TempDir(), "echo boom >&2; exit 3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitDone(t, r)
+	if r.Status() != Failed || r.Err() == nil {
+		t.Errorf("Status() = %v, Err() = %v; want failed with an error", r.Status(), r.Err())
+	}
+	if !strings.Contains(r.Output(), "boom") {
+		t.Errorf("Output() = %q, want stderr captured", r.Output())
+	}
+}
+
+func TestWriteKeepsTail(t *testing.T) {
+	r := &Run{}
+	r.Write([]byte(strings.Repeat("a", maxOutput)))
+	r.Write([]byte("END"))
+	out := r.Output()
+	if len(out) != maxOutput || !strings.HasSuffix(out, "END") {
+		t.Errorf("Output() len = %d, suffix %q; want %d bytes ending in END", len(out), out[len(out)-3:], maxOutput)
+	}
+}
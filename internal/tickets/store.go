@@ -0,0 +1,41 @@
+// Package tickets records which issue-tracker ticket each session was
+// started for, keyed by session key, in ~/.herd/tickets.json.
+package tickets
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/shnupta/herd/internal/store"
+)
+
+var defaultStore *store.Store
+
+func init() {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return
+	}
+	defaultStore = store.NewStore(filepath.Join(home, ".herd", "tickets.json"))
+	_ = defaultStore.Load()
+}
+
+// NewStore creates a ticket store backed by the given file path.
+func NewStore(path string) *store.Store {
+	s := store.NewStore(path)
+	_ = s.Load()
+	return s
+}
+
+// Get returns the ticket ID recorded for the given session key, or "".
+func Get(key string) string { return defaultStore.Get(key) }
+
+// Set records the ticket ID for the given session key and persists to disk.
+func Set(key, ticketID string) error { return defaultStore.Set(key, ticketID) }
+
+// Rename moves the ticket from oldKey to newKey, keeping any existing value
+// already stored under newKey.
+func Rename(oldKey, newKey string) error { return defaultStore.Rename(oldKey, newKey) }
+
+// All returns every session key → ticket ID pair.
+func All() map[string]string { return defaultStore.All() }
@@ -0,0 +1,20 @@
+package tickets
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestTicketSurvivesKeyRename(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tickets.json")
+	s := NewStore(path)
+	if err := s.Set("pane:%4", "ENG-42"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Rename("pane:%4", "session:abc"); err != nil {
+		t.Fatal(err)
+	}
+	if got := NewStore(path).Get("session:abc"); got != "ENG-42" {
+		t.Errorf("Get(session:abc) = %q after rename, want ENG-42", got)
+	}
+}
@@ -3,7 +3,6 @@ package tmux
 import (
 	"fmt"
 	"os"
-	"os/exec"
 	"strconv"
 	"strings"
 )
@@ -52,7 +51,7 @@ func parsePaneLine(line string) (Pane, bool) {
 
 // ListPanes returns all panes across all tmux sessions.
 func ListPanes() ([]Pane, error) {
-	out, err := exec.Command("tmux", "list-panes", "-a", "-F", listFormat).Output()
+	out, err := run("list-panes", "-a", "-F", listFormat)
 	if err != nil {
 		return nil, fmt.Errorf("tmux list-panes: %w", err)
 	}
@@ -96,25 +95,66 @@ func isVersionString(s string) bool {
 // CapturePane returns the contents of a pane with ANSI SGR codes preserved.
 // tmux strips cursor-movement codes, so the output is safe to embed in a viewport.
 func CapturePane(paneID string, scrollbackLines int) (string, error) {
-	out, err := exec.Command(
-		"tmux", "capture-pane",
+	out, err := run(
+		"capture-pane",
 		"-p",                                      // print to stdout
 		"-e",                                      // preserve SGR escape codes
 		"-t", paneID,
 		"-S", fmt.Sprintf("-%d", scrollbackLines), // scrollback depth
-	).Output()
+	)
 	if err != nil {
 		return "", fmt.Errorf("tmux capture-pane %s: %w", paneID, err)
 	}
 	return string(out), nil
 }
 
+// AlternateOn reports whether a pane is on the alternate screen, i.e. is
+// running a full-screen program such as vim or less.
+func AlternateOn(paneID string) (bool, error) {
+	out, err := run("display", "-t", paneID, "-p", "#{alternate_on}")
+	if err != nil {
+		return false, fmt.Errorf("tmux display alternate_on: %w", err)
+	}
+	return strings.TrimSpace(string(out)) == "1", nil
+}
+
+// PaneShared reports whether resizing a pane's window would fight someone:
+// the pane is zoomed, or its window is on screen for a client, being the
+// active window of an attached session. Sessions run in windows other than
+// herd's, so an on-screen one has another viewer.
+func PaneShared(paneID string) (bool, error) {
+	out, err := run("display", "-t", paneID, "-p", "#{window_zoomed_flag} #{window_active} #{session_attached}")
+	if err != nil {
+		return false, fmt.Errorf("tmux display window flags: %w", err)
+	}
+	f := strings.Fields(string(out))
+	if len(f) != 3 {
+		return false, fmt.Errorf("unexpected window flags output: %s", out)
+	}
+	return f[0] == "1" || f[1] == "1" && f[2] != "0", nil
+}
+
+// PaneCommand returns a pane's foreground command and the PID of its first
+// process, failing if the pane no longer exists.
+func PaneCommand(paneID string) (cmd string, pid int, err error) {
+	out, err := run("display", "-t", paneID, "-p", "#{pane_current_command}\t#{pane_pid}")
+	if err != nil {
+		return "", 0, fmt.Errorf("tmux display pane_current_command: %w", err)
+	}
+	cmd, pidStr, ok := strings.Cut(strings.TrimRight(string(out), "\n"), "\t")
+	if !ok {
+		return "", 0, fmt.Errorf("unexpected pane command output: %s", out)
+	}
+	pid, _ = strconv.Atoi(pidStr)
+	return cmd, pid, nil
+}
+
 // CursorPosition returns the cursor X and Y position in a pane.
 // X is the column (0-indexed), Y is the row (0-indexed from top of visible area).
 func CursorPosition(paneID string) (x, y int, err error) {
-	out, err := exec.Command(
-		"tmux", "display", "-t", paneID, "-p", "#{cursor_x} #{cursor_y}",
-	).Output()
+	out, err := run(
+		"display", "-t", paneID, "-p", "#{cursor_x} #{cursor_y}",
+	)
 	if err != nil {
 		return 0, 0, fmt.Errorf("tmux display cursor: %w", err)
 	}
@@ -136,7 +176,7 @@ func CursorPosition(paneID string) (x, y int, err error) {
 // SendLiteral sends text as literal characters to a pane, without interpreting
 // the text as tmux key names.
 func SendLiteral(paneID, text string) error {
-	if err := exec.Command("tmux", "send-keys", "-t", paneID, "-l", text).Run(); err != nil {
+	if _, err := run("send-keys", "-t", paneID, "-l", text); err != nil {
 		return fmt.Errorf("tmux send-keys -l: %w", err)
 	}
 	return nil
@@ -144,12 +184,41 @@ func SendLiteral(paneID, text string) error {
 
 // SendKeyName sends a named tmux key to a pane (e.g. "Enter", "C-c", "BSpace").
 func SendKeyName(paneID, key string) error {
-	if err := exec.Command("tmux", "send-keys", "-t", paneID, key).Run(); err != nil {
+	if _, err := run("send-keys", "-t", paneID, key); err != nil {
 		return fmt.Errorf("tmux send-keys %s: %w", key, err)
 	}
 	return nil
 }
 
+// pasteBuffer is the tmux buffer PasteText goes through.
+const pasteBuffer = "herd-paste"
+
+// PasteText pastes text into a pane in one go, as a terminal would: a
+// program that asked for bracketed paste, as Claude does, sees it marked as
+// a paste, so a multi-line paste isn't submitted line by line. The text goes
+// through a file, as tmux limits the length of a command's arguments.
+func PasteText(paneID, text string) error {
+	f, err := os.CreateTemp("", "herd-paste-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	_, err = f.WriteString(text)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := run("load-buffer", "-b", pasteBuffer, f.Name()); err != nil {
+		return fmt.Errorf("tmux load-buffer: %w", err)
+	}
+	if _, err := run("paste-buffer", "-d", "-p", "-b", pasteBuffer, "-t", paneID); err != nil {
+		return fmt.Errorf("tmux paste-buffer: %w", err)
+	}
+	return nil
+}
+
 // SendKeys sends text literally followed by Enter to a pane.
 func SendKeys(paneID, text string) error {
 	if err := SendLiteral(paneID, text); err != nil {
@@ -158,11 +227,22 @@ func SendKeys(paneID, text string) error {
 	return SendKeyName(paneID, "Enter")
 }
 
+// DisplayMessage shows text in the tmux status line of the current client.
+func DisplayMessage(text string) error {
+	if _, err := run("display-message", "--", text); err != nil {
+		return fmt.Errorf("tmux display-message: %w", err)
+	}
+	return nil
+}
+
 // ResizePane sets an explicit width on the window containing the pane.
 // For single-pane windows (the common case for Claude sessions) resize-pane
 // cannot shrink the pane below the window width, so we resize the window itself.
 func ResizePane(paneID string, width int) error {
-	if err := exec.Command("tmux", "resize-window", "-t", paneID, "-x", strconv.Itoa(width)).Run(); err != nil {
+	if err := Require(FeatureResizeWindow); err != nil {
+		return err
+	}
+	if _, err := run("resize-window", "-t", paneID, "-x", strconv.Itoa(width)); err != nil {
 		return fmt.Errorf("tmux resize-window: %w", err)
 	}
 	return nil
@@ -170,7 +250,10 @@ func ResizePane(paneID string, width int) error {
 
 // ResizeWindow sets explicit width and height on the window containing the pane.
 func ResizeWindow(paneID string, width, height int) error {
-	if err := exec.Command("tmux", "resize-window", "-t", paneID, "-x", strconv.Itoa(width), "-y", strconv.Itoa(height)).Run(); err != nil {
+	if err := Require(FeatureResizeWindow); err != nil {
+		return err
+	}
+	if _, err := run("resize-window", "-t", paneID, "-x", strconv.Itoa(width), "-y", strconv.Itoa(height)); err != nil {
 		return fmt.Errorf("tmux resize-window: %w", err)
 	}
 	return nil
@@ -179,7 +262,10 @@ func ResizeWindow(paneID string, width, height int) error {
 // ResizePaneAuto removes any explicit size override on the window containing
 // the pane, letting tmux fit it to the attached client naturally.
 func ResizePaneAuto(paneID string) error {
-	if err := exec.Command("tmux", "resize-window", "-A", "-t", paneID).Run(); err != nil {
+	if err := Require(FeatureResizeWindow); err != nil {
+		return err
+	}
+	if _, err := run("resize-window", "-A", "-t", paneID); err != nil {
 		return fmt.Errorf("tmux resize-window -A: %w", err)
 	}
 	return nil
@@ -198,19 +284,19 @@ func SwitchToPane(paneID string) error {
 	}
 
 	// select-window makes the window containing the pane active in its session.
-	if err := exec.Command("tmux", "select-window", "-t", paneID).Run(); err != nil {
+	if _, err := run("select-window", "-t", paneID); err != nil {
 		return fmt.Errorf("tmux select-window: %w", err)
 	}
 	// select-pane makes this specific pane the active pane in that window.
-	if err := exec.Command("tmux", "select-pane", "-t", paneID).Run(); err != nil {
+	if _, err := run("select-pane", "-t", paneID); err != nil {
 		return fmt.Errorf("tmux select-pane: %w", err)
 	}
-	out, err := exec.Command("tmux", "display-message", "-t", paneID, "-p", "#{session_name}").Output()
+	out, err := run("display-message", "-t", paneID, "-p", "#{session_name}")
 	if err != nil {
 		return fmt.Errorf("tmux display-message: %w", err)
 	}
 	sess := strings.TrimSpace(string(out))
-	if err := exec.Command("tmux", "switch-client", "-t", sess).Run(); err != nil {
+	if _, err := run("switch-client", "-t", sess); err != nil {
 		return fmt.Errorf("tmux switch-client: %w", err)
 	}
 	return nil
@@ -218,7 +304,7 @@ func SwitchToPane(paneID string) error {
 
 // KillPane closes the given pane (and its window if it is the only pane).
 func KillPane(paneID string) error {
-	if err := exec.Command("tmux", "kill-pane", "-t", paneID).Run(); err != nil {
+	if _, err := run("kill-pane", "-t", paneID); err != nil {
 		return fmt.Errorf("tmux kill-pane: %w", err)
 	}
 	return nil
@@ -238,19 +324,15 @@ func KillPane(paneID string) error {
 // send cmd as keystrokes. The shell remains after cmd exits and its full
 // environment is available from the start.
 func NewWindow(tmuxSession, path, cmd string) (string, error) {
-	tmuxCmd := exec.Command(
-		"tmux", "new-window",
+	out, err := run(
+		"new-window",
 		"-d", // detached — don't switch to the new window
 		"-t", tmuxSession+":", // trailing colon = "this session, next window" (avoids numeric ambiguity)
 		"-c", path,
 		"-P", "-F", "#{pane_id}",
 		// no command → tmux starts the user's default shell
 	)
-	out, err := tmuxCmd.Output()
 	if err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
-			return "", fmt.Errorf("tmux new-window: %w: %s", err, strings.TrimSpace(string(exitErr.Stderr)))
-		}
 		return "", fmt.Errorf("tmux new-window: %w", err)
 	}
 	paneID := strings.TrimSpace(string(out))
@@ -263,6 +345,56 @@ func NewWindow(tmuxSession, path, cmd string) (string, error) {
 	return paneID, nil
 }
 
+// SplitWindow splits the window containing targetPane, opening a new pane
+// below it in path, types cmd into its shell (as NewWindow does) and returns
+// the new pane ID. The split is detached (-d) so focus stays where it is.
+func SplitWindow(targetPane, path, cmd string) (string, error) {
+	out, err := run(
+		"split-window",
+		"-d", "-v",
+		"-t", targetPane,
+		"-c", path,
+		"-P", "-F", "#{pane_id}",
+	)
+	if err != nil {
+		return "", fmt.Errorf("tmux split-window: %w", err)
+	}
+	paneID := strings.TrimSpace(string(out))
+	if cmd == "" {
+		return paneID, nil
+	}
+	if err := SendKeys(paneID, cmd); err != nil {
+		return paneID, fmt.Errorf("send command to split pane: %w", err)
+	}
+	return paneID, nil
+}
+
+// HerdWindowActive reports whether the window herd runs in is on screen:
+// the active window of a session some client is attached to.
+func HerdWindowActive() (bool, error) {
+	pane := os.Getenv("TMUX_PANE")
+	if pane == "" {
+		return false, fmt.Errorf("TMUX_PANE not set — is herd running inside tmux?")
+	}
+	out, err := run("display-message", "-t", pane, "-p", "#{window_active} #{session_attached}")
+	if err != nil {
+		return false, fmt.Errorf("tmux display-message: %w", err)
+	}
+	fields := strings.Fields(string(out))
+	return len(fields) == 2 && fields[0] == "1" && fields[1] != "0", nil
+}
+
+// ClientTTYs returns the terminal device of every client attached to the
+// tmux server, through which a program outside tmux, such as the daemon,
+// can ring or notify the user's terminals directly.
+func ClientTTYs() ([]string, error) {
+	out, err := run("list-clients", "-F", "#{client_tty}")
+	if err != nil {
+		return nil, fmt.Errorf("tmux list-clients: %w", err)
+	}
+	return strings.Fields(string(out)), nil
+}
+
 // CurrentSession returns the tmux session name herd is running in.
 // It targets $TMUX_PANE explicitly so the result is correct regardless of
 // which client tmux considers "current".
@@ -271,7 +403,7 @@ func CurrentSession() (string, error) {
 	if pane == "" {
 		return "", fmt.Errorf("TMUX_PANE not set — is herd running inside tmux?")
 	}
-	out, err := exec.Command("tmux", "display-message", "-t", pane, "-p", "#{session_name}").Output()
+	out, err := run("display-message", "-t", pane, "-p", "#{session_name}")
 	if err != nil {
 		return "", fmt.Errorf("tmux display-message: %w", err)
 	}
@@ -280,7 +412,7 @@ func CurrentSession() (string, error) {
 
 // PaneWidth returns the current width of a pane.
 func PaneWidth(paneID string) (int, error) {
-	out, err := exec.Command("tmux", "display-message", "-t", paneID, "-p", "#{pane_width}").Output()
+	out, err := run("display-message", "-t", paneID, "-p", "#{pane_width}")
 	if err != nil {
 		return 0, err
 	}
@@ -293,7 +425,7 @@ func PaneWidth(paneID string) (int, error) {
 
 // PaneHeight returns the current height of a pane.
 func PaneHeight(paneID string) (int, error) {
-	out, err := exec.Command("tmux", "display-message", "-t", paneID, "-p", "#{pane_height}").Output()
+	out, err := run("display-message", "-t", paneID, "-p", "#{pane_height}")
 	if err != nil {
 		return 0, err
 	}
@@ -309,10 +441,10 @@ func PaneHeight(paneID string) (int, error) {
 // cursorX is the column (0-indexed), cursorY is the row (0-indexed from top of
 // visible area), paneHeight is the height of the pane in rows.
 func PaneInfo(paneID string) (cursorX, cursorY, paneHeight int, err error) {
-	out, err := exec.Command(
-		"tmux", "display-message", "-t", paneID, "-p",
+	out, err := run(
+		"display-message", "-t", paneID, "-p",
 		"#{cursor_x} #{cursor_y} #{pane_height}",
-	).Output()
+	)
 	if err != nil {
 		return 0, 0, 0, fmt.Errorf("tmux display-message pane info: %w", err)
 	}
@@ -337,7 +469,7 @@ func PaneInfo(paneID string) (cursorX, cursorY, paneHeight int, err error) {
 
 // ClientWidth returns the width of the current tmux client.
 func ClientWidth() (int, error) {
-	out, err := exec.Command("tmux", "display-message", "-p", "#{client_width}").Output()
+	out, err := run("display-message", "-p", "#{client_width}")
 	if err != nil {
 		return 0, err
 	}
@@ -350,7 +482,7 @@ func ClientWidth() (int, error) {
 
 // ClientHeight returns the height of the current tmux client.
 func ClientHeight() (int, error) {
-	out, err := exec.Command("tmux", "display-message", "-p", "#{client_height}").Output()
+	out, err := run("display-message", "-p", "#{client_height}")
 	if err != nil {
 		return 0, err
 	}
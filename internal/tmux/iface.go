@@ -6,21 +6,28 @@ type ClientIface interface {
 	ListPanes() ([]Pane, error)
 	CapturePane(paneID string, scrollbackLines int) (string, error)
 	CursorPosition(paneID string) (x, y int, err error)
+	AlternateOn(paneID string) (bool, error)
+	PaneShared(paneID string) (bool, error)
+	PaneCommand(paneID string) (cmd string, pid int, err error)
 	SendLiteral(paneID, text string) error
 	SendKeyName(paneID, key string) error
 	SendKeys(paneID, text string) error
+	PasteText(paneID, text string) error
 	ResizePane(paneID string, width int) error
 	ResizeWindow(paneID string, width, height int) error
 	ResizePaneAuto(paneID string) error
 	SwitchToPane(paneID string) error
 	KillPane(paneID string) error
 	NewWindow(tmuxSession, path, cmd string) (string, error)
+	SplitWindow(targetPane, path, cmd string) (string, error)
 	CurrentSession() (string, error)
+	HerdWindowActive() (bool, error)
 	PaneWidth(paneID string) (int, error)
 	PaneHeight(paneID string) (int, error)
 	PaneInfo(paneID string) (cursorX, cursorY, paneHeight int, err error)
 	ClientWidth() (int, error)
 	ClientHeight() (int, error)
+	DisplayMessage(text string) error
 }
 
 // Client implements ClientIface by shelling out to real tmux commands.
@@ -32,18 +39,25 @@ var _ ClientIface = (*Client)(nil)
 func (c *Client) ListPanes() ([]Pane, error)                                    { return ListPanes() }
 func (c *Client) CapturePane(paneID string, scrollbackLines int) (string, error) { return CapturePane(paneID, scrollbackLines) }
 func (c *Client) CursorPosition(paneID string) (int, int, error)                { return CursorPosition(paneID) }
+func (c *Client) AlternateOn(paneID string) (bool, error)                       { return AlternateOn(paneID) }
+func (c *Client) PaneShared(paneID string) (bool, error)                        { return PaneShared(paneID) }
+func (c *Client) PaneCommand(paneID string) (string, int, error)                { return PaneCommand(paneID) }
 func (c *Client) SendLiteral(paneID, text string) error                         { return SendLiteral(paneID, text) }
 func (c *Client) SendKeyName(paneID, key string) error                          { return SendKeyName(paneID, key) }
 func (c *Client) SendKeys(paneID, text string) error                            { return SendKeys(paneID, text) }
+func (c *Client) PasteText(paneID, text string) error                           { return PasteText(paneID, text) }
 func (c *Client) ResizePane(paneID string, width int) error                     { return ResizePane(paneID, width) }
 func (c *Client) ResizeWindow(paneID string, width, height int) error           { return ResizeWindow(paneID, width, height) }
 func (c *Client) ResizePaneAuto(paneID string) error                            { return ResizePaneAuto(paneID) }
 func (c *Client) SwitchToPane(paneID string) error                              { return SwitchToPane(paneID) }
 func (c *Client) KillPane(paneID string) error                                  { return KillPane(paneID) }
 func (c *Client) NewWindow(tmuxSession, path, cmd string) (string, error)       { return NewWindow(tmuxSession, path, cmd) }
+func (c *Client) SplitWindow(targetPane, path, cmd string) (string, error)      { return SplitWindow(targetPane, path, cmd) }
 func (c *Client) CurrentSession() (string, error)                               { return CurrentSession() }
+func (c *Client) HerdWindowActive() (bool, error)                               { return HerdWindowActive() }
 func (c *Client) PaneWidth(paneID string) (int, error)                          { return PaneWidth(paneID) }
 func (c *Client) PaneHeight(paneID string) (int, error)                         { return PaneHeight(paneID) }
 func (c *Client) PaneInfo(paneID string) (int, int, int, error)                 { return PaneInfo(paneID) }
 func (c *Client) ClientWidth() (int, error)                                     { return ClientWidth() }
 func (c *Client) ClientHeight() (int, error)                                    { return ClientHeight() }
+func (c *Client) DisplayMessage(text string) error                              { return DisplayMessage(text) }
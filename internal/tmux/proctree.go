@@ -0,0 +1,126 @@
+package tmux
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// maxTreeDepth bounds how far below a pane's shell ClaudeInTree looks: far
+// enough for a wrapper chain like direnv → mise → npx → node.
+const maxTreeDepth = 6
+
+// jsRuntimes run Claude Code when it is installed from npm; claudeMarkers
+// are argument substrings that mark such a process as Claude.
+var (
+	jsRuntimes    = []string{"node", "bun", "deno", "npx", "bunx"}
+	claudeMarkers = []string{"@anthropic-ai/claude-code", "claude-code/cli"}
+)
+
+// Process is one entry of the process table.
+type Process struct {
+	PID  int
+	PPID int
+	Args []string // argv, split on whitespace
+}
+
+// ProcessTable indexes processes by parent PID.
+type ProcessTable map[int][]Process
+
+// ListProcesses reads the process table with ps, which works the same on
+// Linux and macOS.
+func ListProcesses() (ProcessTable, error) {
+	out, err := exec.Command("ps", "-A", "-o", "pid=,ppid=,args=").Output()
+	if err != nil {
+		return nil, fmt.Errorf("ps: %w", err)
+	}
+	return ParseProcesses(string(out)), nil
+}
+
+// ParseProcesses parses "pid ppid args..." lines, skipping malformed ones.
+func ParseProcesses(out string) ProcessTable {
+	table := make(ProcessTable)
+	for _, line := range strings.Split(out, "\n") {
+		f := strings.Fields(line)
+		if len(f) < 3 {
+			continue
+		}
+		pid, err1 := strconv.Atoi(f[0])
+		ppid, err2 := strconv.Atoi(f[1])
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		table[ppid] = append(table[ppid], Process{PID: pid, PPID: ppid, Args: f[2:]})
+	}
+	return table
+}
+
+// ClaudeInTree reports whether a descendant of pid is Claude, for panes
+// whose foreground command is a shell or wrapper (direnv, npx, mise) rather
+// than Claude itself.
+func (t ProcessTable) ClaudeInTree(pid int) bool {
+	level := []int{pid}
+	for depth := 0; depth < maxTreeDepth && len(level) > 0; depth++ {
+		var next []int
+		for _, parent := range level {
+			for _, p := range t[parent] {
+				if isClaudeProcess(p.Args) {
+					return true
+				}
+				next = append(next, p.PID)
+			}
+		}
+		level = next
+	}
+	return false
+}
+
+// ClaudeUnder reports whether Claude runs below pid, reading the process
+// table afresh. It is a variable so tests can stub it.
+var ClaudeUnder = func(pid int) bool {
+	procs, _ := ListProcesses()
+	return procs.ClaudeInTree(pid)
+}
+
+// PaneRunsClaude reports whether pane still runs Claude, as discovery
+// accepts it: Claude in the foreground, or under a wrapper such as direnv or
+// npx. It returns the pane's foreground command for the caller to name, and
+// an error when the pane is gone. tmux reuses the IDs of closed panes and
+// Claude may have exited to a shell, so anything typing into a pane it
+// listed a while ago checks it first.
+func PaneRunsClaude(client ClientIface, pane string) (cmd string, ok bool, err error) {
+	cmd, pid, err := client.PaneCommand(pane)
+	if err != nil {
+		return "", false, err
+	}
+	return cmd, IsClaudePane(cmd) || pid > 0 && ClaudeUnder(pid), nil
+}
+
+// isClaudeProcess reports whether argv is Claude: the claude binary (or its
+// version-named process title), or a JavaScript runtime running the claude
+// script or Claude Code's package.
+func isClaudeProcess(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	if IsClaudePane(filepath.Base(args[0])) {
+		return true
+	}
+	if !slices.Contains(jsRuntimes, filepath.Base(args[0])) {
+		return false
+	}
+	for _, a := range args[1:] {
+		if filepath.Base(a) == "claude" {
+			return true
+		}
+		for _, m := range claudeMarkers {
+			if strings.Contains(a, m) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,35 @@
+package tmux
+
+import "testing"
+
+func TestClaudeInTree(t *testing.T) {
+	procs := ParseProcesses(`
+  100     1 -zsh
+  101   100 direnv exec . mise exec -- claude
+  102   101 mise exec -- claude
+  103   102 2.1.47
+  200     1 -bash
+  201   200 node /usr/local/lib/node_modules/npm/bin/npx-cli.js @anthropic-ai/claude-code
+  300     1 -zsh
+  301   300 node /home/me/.npm-global/bin/claude --resume
+  400     1 -zsh
+  401   400 vim notes/claude
+  402   400 node server.js
+  garbage line
+`)
+	tests := []struct {
+		pid  int
+		want bool
+	}{
+		{100, true},  // direnv → mise → claude, named after its version
+		{200, true},  // npx running the package
+		{300, true},  // node running the claude script
+		{400, false}, // an editor and an unrelated node
+		{999, false}, // no such pane
+	}
+	for _, tt := range tests {
+		if got := procs.ClaudeInTree(tt.pid); got != tt.want {
+			t.Errorf("ClaudeInTree(%d) = %v, want %v", tt.pid, got, tt.want)
+		}
+	}
+}
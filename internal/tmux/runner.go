@@ -0,0 +1,142 @@
+package tmux
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// maxAttempts is how many times a tmux command is tried when it fails
+// transiently; retryDelay is the wait before the first retry, doubled after
+// each.
+const (
+	maxAttempts = 3
+	retryDelay  = 25 * time.Millisecond
+)
+
+// ErrNoServer is matched (with errors.Is) by a CommandError from a tmux
+// command that found no server to talk to.
+var ErrNoServer = errors.New("no tmux server running")
+
+// CommandError is a tmux command that failed, after any retries.
+type CommandError struct {
+	Args     []string // arguments after "tmux"
+	Stderr   string   // tmux's complaint, trimmed
+	Attempts int
+	Err      error // from exec
+}
+
+func (e *CommandError) Error() string {
+	msg := e.Err.Error()
+	if e.Stderr != "" {
+		msg += ": " + e.Stderr
+	}
+	if e.Attempts > 1 {
+		msg += fmt.Sprintf(" (after %d attempts)", e.Attempts)
+	}
+	return msg
+}
+
+func (e *CommandError) Unwrap() error { return e.Err }
+
+// Is matches ErrNoServer when tmux could not reach its server.
+func (e *CommandError) Is(target error) bool {
+	return target == ErrNoServer && noServer(e.Stderr)
+}
+
+func noServer(stderr string) bool {
+	return strings.Contains(stderr, "no server running") ||
+		strings.Contains(stderr, "error connecting to")
+}
+
+// transient reports whether stderr describes a failure worth retrying: the
+// server was busy or went away mid-command.
+func transient(stderr string) bool {
+	for _, s := range []string{
+		"server exited unexpectedly",
+		"lost server",
+		"resource temporarily unavailable",
+		"server busy",
+	} {
+		if strings.Contains(stderr, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// idempotent lists the tmux commands that are safe to retry: they only
+// read, or set something to a given value, so running one twice does no
+// more than running it once. A failure such as "lost server" may come after
+// tmux applied a command, so send-keys, paste-buffer, kill-pane, new-window
+// and the like are never retried: that could type a prompt twice or start a
+// second session.
+var idempotent = map[string]bool{
+	"capture-pane":    true,
+	"display":         true,
+	"display-message": true,
+	"has-session":     true,
+	"list-clients":    true,
+	"list-panes":      true,
+	"list-sessions":   true,
+	"list-windows":    true,
+	"resize-window":   true,
+	"select-pane":     true,
+	"select-window":   true,
+	"show-options":    true,
+	"switch-client":   true,
+}
+
+// logger receives a record per tmux command; it discards them until
+// SetLogger is called.
+var logger atomic.Pointer[slog.Logger]
+
+func init() {
+	logger.Store(slog.New(slog.DiscardHandler))
+}
+
+// SetLogger sends a debug record for every tmux command run, with its
+// arguments, duration and outcome, to l. Failures are logged at warn level.
+func SetLogger(l *slog.Logger) {
+	logger.Store(l)
+}
+
+// execTmux runs one tmux command; tests replace it.
+var execTmux = func(args []string) (stdout, stderr []byte, err error) {
+	cmd := exec.Command("tmux", args...)
+	var out, errOut bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &out, &errOut
+	err = cmd.Run()
+	return out.Bytes(), errOut.Bytes(), err
+}
+
+// run runs tmux with args and returns its stdout, retrying transient
+// failures of idempotent commands. A failure is returned as a
+// *CommandError.
+func run(args ...string) ([]byte, error) {
+	delay := retryDelay
+	for attempt := 1; ; attempt++ {
+		start := time.Now()
+		out, errOut, err := execTmux(args)
+		elapsed := time.Since(start)
+		if err == nil {
+			logger.Load().Debug("tmux", "args", args, "duration", elapsed, "attempt", attempt)
+			return out, nil
+		}
+		stderr := strings.TrimSpace(string(errOut))
+		retry := attempt < maxAttempts && len(args) > 0 && idempotent[args[0]] && transient(stderr)
+		logger.Load().Log(context.Background(), slog.LevelWarn, "tmux failed",
+			"args", args, "duration", elapsed, "attempt", attempt, "stderr", stderr, "err", err, "retry", retry)
+		if !retry {
+			return out, &CommandError{Args: args, Stderr: stderr, Attempts: attempt, Err: err}
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
@@ -0,0 +1,84 @@
+package tmux
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// fakeTmux makes execTmux answer from results in turn, recording calls.
+func fakeTmux(t *testing.T, results ...string) *int {
+	t.Helper()
+	orig := execTmux
+	t.Cleanup(func() { execTmux = orig })
+	calls := 0
+	execTmux = func(args []string) ([]byte, []byte, error) {
+		r := results[min(calls, len(results)-1)]
+		calls++
+		if r == "ok" {
+			return []byte("out\n"), nil, nil
+		}
+		return nil, []byte(r + "\n"), errors.New("exit status 1")
+	}
+	return &calls
+}
+
+func TestRunRetriesTransientFailures(t *testing.T) {
+	calls := fakeTmux(t, "server exited unexpectedly", "ok")
+	out, err := run("list-panes")
+	if err != nil || string(out) != "out\n" || *calls != 2 {
+		t.Fatalf("run() = %q, %v after %d calls; want output after a retry", out, err, *calls)
+	}
+}
+
+func TestRunGivesUpWithCommandError(t *testing.T) {
+	calls := fakeTmux(t, "lost server")
+	_, err := run("capture-pane", "-p", "-t", "%1")
+	var ce *CommandError
+	if !errors.As(err, &ce) || ce.Attempts != maxAttempts || *calls != maxAttempts {
+		t.Fatalf("err = %v after %d calls, want a CommandError after %d attempts", err, *calls, maxAttempts)
+	}
+	if ce.Stderr != "lost server" || strings.Join(ce.Args, " ") != "capture-pane -p -t %1" {
+		t.Errorf("CommandError = %+v", ce)
+	}
+}
+
+func TestRunDoesNotRetryCommandsThatAct(t *testing.T) {
+	for _, args := range [][]string{
+		{"send-keys", "-t", "%1", "-l", "run the tests"},
+		{"paste-buffer", "-t", "%1"},
+		{"kill-pane", "-t", "%1"},
+		{"new-window", "-t", "0", "claude"},
+		{"split-window", "-t", "%1", "claude"},
+	} {
+		calls := fakeTmux(t, "lost server", "ok")
+		if _, err := run(args...); err == nil || *calls != 1 {
+			t.Errorf("%s: err = %v after %d calls, want the failure without a retry", args[0], err, *calls)
+		}
+	}
+}
+
+func TestRunDoesNotRetryPermanentFailures(t *testing.T) {
+	calls := fakeTmux(t, "no server running on /tmp/tmux-0/default")
+	_, err := run("list-panes")
+	if *calls != 1 {
+		t.Errorf("tried %d times, want once", *calls)
+	}
+	if !errors.Is(err, ErrNoServer) {
+		t.Errorf("err = %v, want ErrNoServer", err)
+	}
+}
+
+func TestRunLogs(t *testing.T) {
+	fakeTmux(t, "can't find pane: %9")
+	var buf bytes.Buffer
+	SetLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	t.Cleanup(func() { SetLogger(slog.New(slog.DiscardHandler)) })
+
+	_, _ = run("select-pane", "-t", "%9")
+	if log := buf.String(); !strings.Contains(log, "level=WARN") || !strings.Contains(log, `stderr="can't find pane: %9"`) {
+		t.Errorf("log = %q, want a warning with stderr", log)
+	}
+}
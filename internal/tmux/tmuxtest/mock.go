@@ -2,7 +2,12 @@
 // Import this package only from _test.go files.
 package tmuxtest
 
-import "github.com/shnupta/herd/internal/tmux"
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/shnupta/herd/internal/tmux"
+)
 
 // MockClient is a test double for tmux.ClientIface.
 // Set fields before calling methods to control return values.
@@ -22,6 +27,12 @@ type MockClient struct {
 	PaneHeightVal int
 	PaneHeightErr error
 
+	AlternateOnVal bool
+	AlternateOnErr error
+
+	PaneSharedVal bool
+	PaneSharedErr error
+
 	PaneInfoCursorX int
 	PaneInfoCursorY int
 	PaneInfoHeight  int
@@ -35,9 +46,15 @@ type MockClient struct {
 	CurrentSessionVal string
 	CurrentSessionErr error
 
+	HerdWindowActiveVal bool
+	HerdWindowActiveErr error
+
 	NewWindowPane string
 	NewWindowErr  error
 
+	SplitWindowPane string
+	SplitWindowErr  error
+
 	ResizePaneErr     error
 	ResizeWindowErr   error
 	ResizePaneAutoErr error
@@ -46,13 +63,22 @@ type MockClient struct {
 	SendLiteralErr    error
 	SendKeyNameErr    error
 	SendKeysErr       error
+	PasteTextErr      error
+	DisplayMessageErr error
 
 	// Track calls for assertions.
+	CaptureCalls     []string // "pane:scrollback"
 	SendLiteralCalls []string
 	SendKeyCalls     []string
 	SendKeysCalls    []string
+	PasteCalls       []string
 	KilledPanes      []string
 	SwitchedPanes    []string
+	SplitCalls       []string // "target:path:cmd"
+	NewWindowCalls   []string // "session:path:cmd"
+	Messages         []string // DisplayMessage texts
+	ResizeCalls      []string // "pane:WxH"
+	AutoResizeCalls  []string
 }
 
 // Compile-time check that MockClient satisfies tmux.ClientIface.
@@ -63,9 +89,29 @@ func (m *MockClient) ListPanes() ([]tmux.Pane, error) {
 }
 
 func (m *MockClient) CapturePane(paneID string, scrollbackLines int) (string, error) {
+	m.CaptureCalls = append(m.CaptureCalls, paneID+":"+strconv.Itoa(scrollbackLines))
 	return m.CaptureOutput, m.CaptureErr
 }
 
+func (m *MockClient) AlternateOn(paneID string) (bool, error) {
+	return m.AlternateOnVal, m.AlternateOnErr
+}
+
+func (m *MockClient) PaneShared(paneID string) (bool, error) {
+	return m.PaneSharedVal, m.PaneSharedErr
+}
+
+// PaneCommand reports the command and PID of the pane in Panes, failing as
+// tmux does for one that isn't there.
+func (m *MockClient) PaneCommand(paneID string) (string, int, error) {
+	for _, p := range m.Panes {
+		if p.ID == paneID {
+			return p.CurrentCmd, p.PID, nil
+		}
+	}
+	return "", 0, fmt.Errorf("can't find pane: %s", paneID)
+}
+
 func (m *MockClient) CursorPosition(paneID string) (x, y int, err error) {
 	return m.CursorX, m.CursorY, m.CursorErr
 }
@@ -80,6 +126,11 @@ func (m *MockClient) SendKeyName(paneID, key string) error {
 	return m.SendKeyNameErr
 }
 
+func (m *MockClient) PasteText(paneID, text string) error {
+	m.PasteCalls = append(m.PasteCalls, paneID+":"+text)
+	return m.PasteTextErr
+}
+
 func (m *MockClient) SendKeys(paneID, text string) error {
 	m.SendKeysCalls = append(m.SendKeysCalls, paneID+":"+text)
 	return m.SendKeysErr
@@ -90,10 +141,12 @@ func (m *MockClient) ResizePane(paneID string, width int) error {
 }
 
 func (m *MockClient) ResizeWindow(paneID string, width, height int) error {
+	m.ResizeCalls = append(m.ResizeCalls, fmt.Sprintf("%s:%dx%d", paneID, width, height))
 	return m.ResizeWindowErr
 }
 
 func (m *MockClient) ResizePaneAuto(paneID string) error {
+	m.AutoResizeCalls = append(m.AutoResizeCalls, paneID)
 	return m.ResizePaneAutoErr
 }
 
@@ -108,9 +161,19 @@ func (m *MockClient) KillPane(paneID string) error {
 }
 
 func (m *MockClient) NewWindow(tmuxSession, path, cmd string) (string, error) {
+	m.NewWindowCalls = append(m.NewWindowCalls, tmuxSession+":"+path+":"+cmd)
 	return m.NewWindowPane, m.NewWindowErr
 }
 
+func (m *MockClient) SplitWindow(targetPane, path, cmd string) (string, error) {
+	m.SplitCalls = append(m.SplitCalls, targetPane+":"+path+":"+cmd)
+	return m.SplitWindowPane, m.SplitWindowErr
+}
+
+func (m *MockClient) HerdWindowActive() (bool, error) {
+	return m.HerdWindowActiveVal, m.HerdWindowActiveErr
+}
+
 func (m *MockClient) CurrentSession() (string, error) {
 	return m.CurrentSessionVal, m.CurrentSessionErr
 }
@@ -134,3 +197,8 @@ func (m *MockClient) ClientWidth() (int, error) {
 func (m *MockClient) ClientHeight() (int, error) {
 	return m.ClientHeightVal, m.ClientHeightErr
 }
+
+func (m *MockClient) DisplayMessage(text string) error {
+	m.Messages = append(m.Messages, text)
+	return m.DisplayMessageErr
+}
@@ -0,0 +1,120 @@
+package tmux
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Version is a tmux release, e.g. 3.3a is {Major: 3, Minor: 3}. Dev is set
+// for builds from master, which are assumed to have every feature.
+type Version struct {
+	Major, Minor int
+	Dev          bool
+	Raw          string // as tmux -V printed it, less the "tmux " prefix
+}
+
+// AtLeast reports whether v is major.minor or newer.
+func (v Version) AtLeast(major, minor int) bool {
+	if v.Dev {
+		return true
+	}
+	return v.Major > major || v.Major == major && v.Minor >= minor
+}
+
+func (v Version) String() string { return v.Raw }
+
+// ParseVersion parses the output of tmux -V: "tmux 3.3a", "tmux next-3.5",
+// "tmux openbsd-7.4" (treated as dev) or "tmux master".
+func ParseVersion(s string) (Version, error) {
+	raw := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(s), "tmux "))
+	v := Version{Raw: raw}
+	num := strings.TrimPrefix(raw, "next-")
+	if num == "master" || strings.HasPrefix(num, "openbsd-") {
+		v.Dev = true
+		return v, nil
+	}
+	major, rest, ok := strings.Cut(num, ".")
+	if !ok {
+		return v, fmt.Errorf("unrecognised tmux version %q", raw)
+	}
+	var err error
+	if v.Major, err = strconv.Atoi(major); err != nil {
+		return v, fmt.Errorf("unrecognised tmux version %q", raw)
+	}
+	end := 0
+	for end < len(rest) && rest[end] >= '0' && rest[end] <= '9' {
+		end++
+	}
+	if v.Minor, err = strconv.Atoi(rest[:end]); err != nil {
+		return v, fmt.Errorf("unrecognised tmux version %q", raw)
+	}
+	return v, nil
+}
+
+// detectVersion asks tmux for its version once per process; tests replace
+// it.
+var detectVersion = sync.OnceValues(func() (Version, error) {
+	out, err := run("-V")
+	if err != nil {
+		return Version{}, fmt.Errorf("tmux -V: %w", err)
+	}
+	return ParseVersion(string(out))
+})
+
+// CurrentVersion returns the version of the tmux on PATH.
+func CurrentVersion() (Version, error) { return detectVersion() }
+
+// Feature is a tmux capability herd uses that older releases lack.
+type Feature struct {
+	Name         string
+	Major, Minor int // first release herd can use it with
+}
+
+// Features herd gates on the tmux version.
+var (
+	// FeatureResizeWindow is sizing a window to herd's viewport. tmux has
+	// had resize-window since 2.9, but 3.0 and 3.1 fail the explicit -x/-y
+	// sizes herd asks for.
+	FeatureResizeWindow = Feature{Name: "window resizing", Major: 3, Minor: 2}
+)
+
+// Features lists every gated feature.
+var Features = []Feature{FeatureResizeWindow}
+
+// UnsupportedError is returned in place of running a command the tmux on
+// PATH is too old for.
+type UnsupportedError struct {
+	Feature Feature
+	Have    Version
+}
+
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf("%s needs tmux %d.%d or newer (found %s)", e.Feature.Name, e.Feature.Major, e.Feature.Minor, e.Have)
+}
+
+// Require returns an *UnsupportedError when tmux is older than f needs.
+// When the version can't be told, f is assumed to be there and tmux gets to
+// say otherwise.
+func Require(f Feature) error {
+	v, err := detectVersion()
+	if err != nil || v.AtLeast(f.Major, f.Minor) {
+		return nil
+	}
+	return &UnsupportedError{Feature: f, Have: v}
+}
+
+// Supports reports whether tmux is new enough for f.
+func Supports(f Feature) bool { return Require(f) == nil }
+
+// MissingFeatures returns the features the tmux on PATH is too old for.
+func MissingFeatures() []Feature {
+	var out []Feature
+	for _, f := range Features {
+		if !Supports(f) {
+			out = append(out, f)
+		}
+	}
+	return out
+}
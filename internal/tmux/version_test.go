@@ -0,0 +1,69 @@
+package tmux
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		in           string
+		major, minor int
+		dev          bool
+	}{
+		{"tmux 3.3a\n", 3, 3, false},
+		{"tmux 3.1", 3, 1, false},
+		{"tmux next-3.5", 3, 5, false},
+		{"tmux 2.9a", 2, 9, false},
+		{"tmux master", 0, 0, true},
+		{"tmux openbsd-7.4", 0, 0, true},
+	}
+	for _, tt := range tests {
+		v, err := ParseVersion(tt.in)
+		if err != nil || v.Major != tt.major || v.Minor != tt.minor || v.Dev != tt.dev {
+			t.Errorf("ParseVersion(%q) = %+v, %v", tt.in, v, err)
+		}
+	}
+	if _, err := ParseVersion("tmux banana"); err == nil {
+		t.Error("ParseVersion(banana) succeeded")
+	}
+}
+
+// fakeVersion makes tmux report v for the rest of the test.
+func fakeVersion(t *testing.T, v string) {
+	t.Helper()
+	orig := detectVersion
+	t.Cleanup(func() { detectVersion = orig })
+	detectVersion = func() (Version, error) { return ParseVersion(v) }
+}
+
+func TestResizeGatedOnOldTmux(t *testing.T) {
+	fakeVersion(t, "tmux 3.1c")
+	calls := fakeTmux(t, "ok")
+
+	err := ResizeWindow("%1", 80, 24)
+	var ue *UnsupportedError
+	if !errors.As(err, &ue) || ue.Feature != FeatureResizeWindow {
+		t.Fatalf("ResizeWindow() = %v, want UnsupportedError", err)
+	}
+	if *calls != 0 {
+		t.Errorf("ran tmux %d times, want none", *calls)
+	}
+	if got := ue.Error(); got != "window resizing needs tmux 3.2 or newer (found 3.1c)" {
+		t.Errorf("Error() = %q", got)
+	}
+	if missing := MissingFeatures(); len(missing) != 1 || missing[0] != FeatureResizeWindow {
+		t.Errorf("MissingFeatures() = %v, want resizing", missing)
+	}
+}
+
+func TestResizeRunsOnNewTmux(t *testing.T) {
+	fakeVersion(t, "tmux 3.4")
+	calls := fakeTmux(t, "ok")
+	if err := ResizeWindow("%1", 80, 24); err != nil || *calls != 1 {
+		t.Errorf("ResizeWindow() = %v after %d calls", err, *calls)
+	}
+	if len(MissingFeatures()) != 0 {
+		t.Errorf("MissingFeatures() = %v, want none", MissingFeatures())
+	}
+}
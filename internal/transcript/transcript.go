@@ -0,0 +1,170 @@
+// Package transcript extracts session details from the tail of a Claude Code
+// transcript file (the JSONL log named by a hook's transcript_path).
+package transcript
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// tailBytes bounds how much of the transcript is read. Transcripts grow to
+// many megabytes; the last assistant message is always near the end.
+const tailBytes = 256 * 1024
+
+// Info is what could be learned from the transcript tail.
+type Info struct {
+	Model string // e.g. "claude-sonnet-4-5-20250929"; empty if unknown
+
+	// ContextTokens is the prompt size of the last assistant turn (input plus
+	// cache reads and writes) — i.e. how much of the context window is in use.
+	ContextTokens int
+
+	// Prompt is the text of the last prompt the user typed; empty if it
+	// is too far back to be in the tail.
+	Prompt string
+}
+
+// entry is the subset of a transcript line herd cares about.
+type entry struct {
+	Type      string    `json:"type"`
+	Timestamp time.Time `json:"timestamp"`
+	IsMeta    bool      `json:"isMeta"`
+	Message   struct {
+		Model   string          `json:"model"`
+		Content json.RawMessage `json:"content"`
+		Usage   struct {
+			InputTokens              int `json:"input_tokens"`
+			CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+			CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+		} `json:"usage"`
+	} `json:"message"`
+}
+
+// Read returns Info from the last assistant message in the transcript at
+// path. A missing or unreadable file yields a zero Info.
+func Read(path string) Info {
+	data, err := readTail(path)
+	if err != nil {
+		return Info{}
+	}
+	return parse(data)
+}
+
+// readTail reads the last tailBytes of the file at path.
+func readTail(path string) ([]byte, error) {
+	if path == "" {
+		return nil, os.ErrNotExist
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if fi, err := f.Stat(); err == nil && fi.Size() > tailBytes {
+		if _, err := f.Seek(-tailBytes, io.SeekEnd); err != nil {
+			return nil, err
+		}
+	}
+	return io.ReadAll(f)
+}
+
+// ReplySince returns what the assistant said in the transcript at path
+// after since, its text blocks joined by blank lines, or "" when it said
+// nothing or the file can't be read. Only the tail of the file is read.
+func ReplySince(path string, since time.Time) string {
+	data, err := readTail(path)
+	if err != nil {
+		return ""
+	}
+	var texts []string
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(make([]byte, 0, 64*1024), tailBytes)
+	for sc.Scan() {
+		var e entry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			continue
+		}
+		if e.Type != "assistant" || !e.Timestamp.After(since) {
+			continue
+		}
+		var blocks []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		}
+		if err := json.Unmarshal(e.Message.Content, &blocks); err != nil {
+			continue
+		}
+		for _, b := range blocks {
+			if t := strings.TrimSpace(b.Text); b.Type == "text" && t != "" {
+				texts = append(texts, t)
+			}
+		}
+	}
+	return strings.Join(texts, "\n\n")
+}
+
+// parse scans JSONL data and keeps the details of the last assistant entry
+// and the last prompt. A partial first line (from seeking into the middle
+// of the file) fails to decode and is skipped.
+func parse(data []byte) Info {
+	var info Info
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(make([]byte, 0, 64*1024), tailBytes)
+	for sc.Scan() {
+		var e entry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			continue
+		}
+		if e.Type == "user" {
+			if p := promptText(e); p != "" {
+				info.Prompt = p
+			}
+			continue
+		}
+		if e.Type != "assistant" || e.Message.Model == "" || e.Message.Model == "<synthetic>" {
+			continue
+		}
+		info.Model = e.Message.Model
+		u := e.Message.Usage
+		info.ContextTokens = u.InputTokens + u.CacheCreationInputTokens + u.CacheReadInputTokens
+	}
+	return info
+}
+
+// promptText returns what the user typed in a user entry: its content as a
+// string, or its text blocks. Tool results, which also come back as user
+// entries, and Claude's own notes around slash commands are not prompts.
+func promptText(e entry) string {
+	if e.IsMeta {
+		return ""
+	}
+	var text string
+	if err := json.Unmarshal(e.Message.Content, &text); err != nil {
+		var blocks []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		}
+		if err := json.Unmarshal(e.Message.Content, &blocks); err != nil {
+			return ""
+		}
+		var texts []string
+		for _, b := range blocks {
+			if b.Type != "text" {
+				return ""
+			}
+			texts = append(texts, b.Text)
+		}
+		text = strings.Join(texts, "\n")
+	}
+	text = strings.TrimSpace(text)
+	if strings.HasPrefix(text, "<command-") || strings.HasPrefix(text, "<local-command-") {
+		return ""
+	}
+	return text
+}
@@ -0,0 +1,103 @@
+package transcript
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseKeepsLastAssistantModel(t *testing.T) {
+	data := strings.Join([]string{
+		`{"type":"user","message":{"role":"user"}}`,
+		`{"type":"assistant","message":{"model":"claude-opus-4-1"}}`,
+		`{"type":"assistant","message":{"model":"claude-sonnet-4-5"}}`,
+		`{"type":"assistant","message":{"model":"<synthetic>"}}`,
+		`{"type":"user","message":{"role":"user"}}`,
+	}, "\n")
+	if got := parse([]byte(data)).Model; got != "claude-sonnet-4-5" {
+		t.Errorf("Model = %q, want claude-sonnet-4-5", got)
+	}
+}
+
+func TestParseSkipsPartialLine(t *testing.T) {
+	data := `odel":"truncated"}}` + "\n" + `{"type":"assistant","message":{"model":"claude-haiku-4-5"}}`
+	if got := parse([]byte(data)).Model; got != "claude-haiku-4-5" {
+		t.Errorf("Model = %q, want claude-haiku-4-5", got)
+	}
+}
+
+func TestReadTailOfLargeFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "t.jsonl")
+	var sb strings.Builder
+	sb.WriteString(`{"type":"assistant","message":{"model":"old-model"}}` + "\n")
+	filler := `{"type":"user","message":{"content":"` + strings.Repeat("x", 1000) + `"}}` + "\n"
+	for sb.Len() < tailBytes+4096 {
+		sb.WriteString(filler)
+	}
+	sb.WriteString(`{"type":"assistant","message":{"model":"new-model"}}` + "\n")
+	if err := os.WriteFile(path, []byte(sb.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := Read(path).Model; got != "new-model" {
+		t.Errorf("Model = %q, want new-model", got)
+	}
+}
+
+func TestReadMissingFile(t *testing.T) {
+	if got := Read(filepath.Join(t.TempDir(), "missing.jsonl")); got != (Info{}) {
+		t.Errorf("Read(missing) = %+v, want zero Info", got)
+	}
+}
+
+func TestParseContextTokens(t *testing.T) {
+	data := strings.Join([]string{
+		`{"type":"assistant","message":{"model":"m","usage":{"input_tokens":1,"cache_read_input_tokens":10}}}`,
+		`{"type":"assistant","message":{"model":"m","usage":{"input_tokens":5,"cache_creation_input_tokens":300,"cache_read_input_tokens":90000,"output_tokens":700}}}`,
+	}, "\n")
+	if got := parse([]byte(data)).ContextTokens; got != 90305 {
+		t.Errorf("ContextTokens = %d, want 90305", got)
+	}
+}
+
+func TestReplySince(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "t.jsonl")
+	data := strings.Join([]string{
+		`{"type":"assistant","timestamp":"2026-10-01T10:00:00Z","message":{"content":[{"type":"text","text":"Before the feedback."}]}}`,
+		`{"type":"user","timestamp":"2026-10-01T10:05:00Z","message":{"content":"Review of your recent changes"}}`,
+		`{"type":"assistant","timestamp":"2026-10-01T10:05:10Z","message":{"content":[{"type":"text","text":"Fixed the nil check in main.go."},{"type":"tool_use","name":"Edit"}]}}`,
+		`{"type":"assistant","timestamp":"2026-10-01T10:06:00Z","message":{"content":[{"type":"text","text":"  All done.  "}]}}`,
+	}, "\n")
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	since := time.Date(2026, 10, 1, 10, 5, 0, 0, time.UTC)
+	if got, want := ReplySince(path, since), "Fixed the nil check in main.go.\n\nAll done."; got != want {
+		t.Errorf("ReplySince = %q, want %q", got, want)
+	}
+	if got := ReplySince(path, since.Add(time.Hour)); got != "" {
+		t.Errorf("ReplySince after the last message = %q, want empty", got)
+	}
+	if got := ReplySince("", since); got != "" {
+		t.Errorf("ReplySince without a transcript = %q", got)
+	}
+}
+
+func TestParsePrompt(t *testing.T) {
+	data := strings.Join([]string{
+		`{"type":"user","message":{"role":"user","content":"plan the DB migration"}}`,
+		`{"type":"assistant","message":{"model":"m","content":[{"type":"tool_use","name":"Read"}]}}`,
+		`{"type":"user","message":{"role":"user","content":[{"type":"tool_result","content":"file body"}]}}`,
+		`{"type":"user","isMeta":true,"message":{"role":"user","content":"Caveat: the messages below were generated by the user"}}`,
+		`{"type":"user","message":{"role":"user","content":"<command-name>/model</command-name>"}}`,
+		`{"type":"assistant","message":{"model":"m","content":[{"type":"text","text":"Here is the plan."}]}}`,
+	}, "\n")
+	if got := parse([]byte(data)).Prompt; got != "plan the DB migration" {
+		t.Errorf("Prompt = %q, want the typed prompt", got)
+	}
+	blocks := `{"type":"user","message":{"role":"user","content":[{"type":"text","text":"  fix the flaky test "}]}}`
+	if got := parse([]byte(blocks)).Prompt; got != "fix the flaky test" {
+		t.Errorf("Prompt from text blocks = %q", got)
+	}
+}
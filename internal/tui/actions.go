@@ -0,0 +1,132 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/shnupta/herd/internal/config"
+	"github.com/shnupta/herd/internal/history"
+	"github.com/shnupta/herd/internal/i18n"
+)
+
+// maxActionShortcuts is how many actions get a number-key shortcut.
+const maxActionShortcuts = 9
+
+// openActions shows the quick actions configured for the selected session's
+// project.
+func (m Model) openActions() Model {
+	sel := m.selectedSession()
+	if sel == nil {
+		return m
+	}
+	m.actions = config.Load().ActionsFor(sel.ProjectPath)
+	m.actionsSelected = 0
+	m.mode = ModeActions
+	return m
+}
+
+func (m Model) updateActionsMode(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m = m.recalcLayout()
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "q":
+			m.mode = ModeNormal
+			m.actions = nil
+			return m, nil
+		case "k", "up":
+			if m.actionsSelected > 0 {
+				m.actionsSelected--
+			}
+		case "j", "down":
+			if m.actionsSelected < len(m.actions)-1 {
+				m.actionsSelected++
+			}
+		case "enter":
+			return m.runAction(m.actionsSelected)
+		case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+			return m.runAction(int(msg.String()[0] - '1'))
+		}
+	}
+	return m, nil
+}
+
+// runAction executes action i against the selected session and returns to
+// normal mode. Shell actions run in the session's scratch pane so their output
+// stays next to it; prompts are typed into Claude.
+func (m Model) runAction(i int) (tea.Model, tea.Cmd) {
+	if i < 0 || i >= len(m.actions) {
+		return m, nil
+	}
+	a := m.actions[i]
+	m.mode = ModeNormal
+	m.actions = nil
+
+	sel := m.selectedSession()
+	if sel == nil {
+		return m, nil
+	}
+	var err error
+	switch {
+	case a.Shell != "":
+		var pane string
+		if pane, err = m.scratchPane(*sel); err == nil {
+			if err = m.checkShellPane(pane); err != nil {
+				_ = m.tmuxClient.DisplayMessage("herd: " + err.Error())
+			} else if err = m.tmuxClient.SendKeys(pane, a.Shell); err == nil {
+				m.audit(sel.TmuxPane, history.ActionKeys, "action "+a.Label, a.Shell+"  (in scratch pane "+pane+")")
+			}
+		}
+	case a.Prompt != "":
+		err = m.sendPrompt(sel.TmuxPane, a.Prompt, "action")
+	}
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.lastCapture = ""
+	m.forceViewportRefresh = true
+	return m, m.fetchCapture(sel.TmuxPane)
+}
+
+func (m Model) renderActionsOverlay() string {
+	var sb strings.Builder
+	sb.WriteString(styleOverlayTitle.Width(m.width).Render(i18n.T("Quick Actions")) + "\n\n")
+
+	kindStyle := lipgloss.NewStyle().Foreground(colSubtle)
+	if len(m.actions) == 0 {
+		project := ""
+		if sel := m.selectedSession(); sel != nil {
+			project = shortenPath(sel.ProjectPath)
+		}
+		sb.WriteString(pickerItemStyle.Render("No actions configured for "+project) + "\n")
+		sb.WriteString(pickerItemStyle.Render(kindStyle.Render(`Add "actions" to ~/.herd/config.json — see the README.`)) + "\n")
+	}
+	for i, a := range m.actions {
+		shortcut := " "
+		if i < maxActionShortcuts {
+			shortcut = fmt.Sprint(i + 1)
+		}
+		kind, detail := "shell", a.Shell
+		if a.Shell == "" {
+			kind, detail = "prompt", a.Prompt
+		}
+		line := fmt.Sprintf("[%s] %s  %s", shortcut, a.Label, kindStyle.Render(kind+": "+detail))
+		if i == m.actionsSelected {
+			sb.WriteString(pickerSelectedStyle.Width(m.width-4).Render("▸ "+line) + "\n")
+		} else {
+			sb.WriteString(pickerItemStyle.Render("  "+line) + "\n")
+		}
+	}
+
+	sb.WriteString("\n" + styleOverlayHelp.Render(i18n.T("[j/k] navigate  [enter/1-9] run  [esc] cancel")))
+	return sb.String()
+}
@@ -0,0 +1,87 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/shnupta/herd/internal/config"
+	"github.com/shnupta/herd/internal/tmux"
+	"github.com/shnupta/herd/internal/tmux/tmuxtest"
+)
+
+func withActions(m Model, actions ...config.Action) Model {
+	m.actions = actions
+	m.actionsSelected = 0
+	m.mode = ModeActions
+	return m
+}
+
+func TestActionShellRunsInScratchPane(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	mock := m.tmuxClient.(*tmuxtest.MockClient)
+	mock.SplitWindowPane = "%99"
+	mock.Panes = append(mock.Panes, tmux.Pane{ID: "%99", CurrentCmd: "zsh"})
+	m = withActions(m,
+		config.Action{Label: "Prompt", Prompt: "summarise progress"},
+		config.Action{Label: "Tests", Shell: "go test ./..."},
+	)
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'2'}})
+	m = updated.(Model)
+
+	if m.mode != ModeNormal {
+		t.Errorf("expected ModeNormal after running an action, got %d", m.mode)
+	}
+	if want := "%1:/home/user/project-alpha:"; len(mock.SplitCalls) != 1 || mock.SplitCalls[0] != want {
+		t.Errorf("SplitCalls = %v, want [%s]", mock.SplitCalls, want)
+	}
+	if len(mock.SendKeysCalls) != 1 || mock.SendKeysCalls[0] != "%99:go test ./..." {
+		t.Errorf("SendKeysCalls = %v, want the command sent to the scratch pane", mock.SendKeysCalls)
+	}
+}
+
+func TestActionPromptIsSentToClaude(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	m = withActions(m, config.Action{Label: "Prompt", Prompt: "summarise progress"})
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+
+	mock := m.tmuxClient.(*tmuxtest.MockClient)
+	if len(mock.SendKeysCalls) != 1 || mock.SendKeysCalls[0] != "%1:summarise progress" {
+		t.Errorf("SendKeysCalls = %v, want [%%1:summarise progress]", mock.SendKeysCalls)
+	}
+}
+
+func TestActionsOverlayEmptyHint(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	m = withActions(m)
+
+	if view := m.View(); !strings.Contains(view, "No actions configured") {
+		t.Errorf("expected empty-state hint in overlay, got:\n%s", view)
+	}
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	if updated.(Model).mode != ModeNormal {
+		t.Error("expected esc to close the actions overlay")
+	}
+}
+
+func TestActionShellRefusesScratchPaneRunningClaude(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	mock := m.tmuxClient.(*tmuxtest.MockClient)
+	// The remembered scratch pane's ID now belongs to a Claude session.
+	_ = m.scratch.Set(m.selectedSession().Key(), "%2")
+	m = withActions(m, config.Action{Label: "Tests", Shell: "go test ./..."})
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'1'}})
+	m = updated.(Model)
+	if len(mock.SendKeysCalls) != 0 || m.err == nil {
+		t.Errorf("sent %q, err = %v; want nothing sent to Claude", mock.SendKeysCalls, m.err)
+	}
+}
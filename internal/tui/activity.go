@@ -0,0 +1,206 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/shnupta/herd/internal/history"
+	"github.com/shnupta/herd/internal/i18n"
+	"github.com/shnupta/herd/internal/report"
+	"github.com/shnupta/herd/internal/session"
+)
+
+// activityWindow is how far back the activity panel reaches.
+const activityWindow = 24 * time.Hour
+
+// activityEntry is one line of a session's activity: a stretch in one
+// state (and tool) lasting dur, or a prompt, edit or herd action at at.
+type activityEntry struct {
+	at      time.Time
+	state   string // set for a stretch in a state
+	tool    string
+	dur     time.Duration
+	ongoing bool   // the stretch the session is still in
+	text    string // set for everything else
+}
+
+// activityLoadedMsg carries the selected session's activity read from the
+// history log.
+type activityLoadedMsg struct {
+	entries []activityEntry
+	err     error
+}
+
+func isActivityLoadedMsg(msg tea.Msg) bool { _, ok := msg.(activityLoadedMsg); return ok }
+
+// openActivity switches to the activity panel for the selected session
+// and reads its last day from the history log.
+func (m Model) openActivity() (Model, tea.Cmd) {
+	sel := m.selectedSession()
+	if sel == nil {
+		return m, nil
+	}
+	m.mode = ModeActivity
+	m.activityName = m.displayName(*sel)
+	m.activityLoading = true
+	m.activityErr = ""
+	m.activityView = viewport.New(m.width, maxInt(1, m.height-4))
+	path, s := m.historyPath, *sel
+	return m, func() tea.Msg {
+		now := time.Now()
+		events, err := history.ReadSince(path, now.Add(-activityWindow))
+		if err != nil {
+			return activityLoadedMsg{err: err}
+		}
+		return activityLoadedMsg{entries: buildActivity(events, s, now)}
+	}
+}
+
+// belongsTo reports whether ev was recorded for s: by session ID when both
+// have one, else by pane.
+func belongsTo(ev history.Event, s session.Session) bool {
+	if s.ID != "" && ev.SessionID != "" {
+		return ev.SessionID == s.ID
+	}
+	return s.TmuxPane != "" && ev.TmuxPane == s.TmuxPane
+}
+
+// buildActivity turns s's events into its activity, oldest first: state
+// events become stretches, merged while neither the state nor the tool
+// changes, and prompts, edits and herd's actions are listed between them.
+func buildActivity(events []history.Event, s session.Session, now time.Time) []activityEntry {
+	var out []activityEntry
+	open := -1 // index of the stretch still running
+	for _, ev := range events {
+		if !belongsTo(ev, s) {
+			continue
+		}
+		switch ev.Kind {
+		case history.KindState:
+			if open >= 0 && out[open].state == ev.State && out[open].tool == ev.Tool {
+				continue
+			}
+			if open >= 0 {
+				out[open].dur = ev.Time.Sub(out[open].at)
+			}
+			out = append(out, activityEntry{at: ev.Time, state: ev.State, tool: ev.Tool})
+			open = len(out) - 1
+		case history.KindPrompt:
+			text := i18n.T("prompt")
+			if ev.Detail != "" {
+				text += ": " + ev.Detail
+			}
+			out = append(out, activityEntry{at: ev.Time, text: "› " + text})
+		case history.KindFile:
+			out = append(out, activityEntry{at: ev.Time, text: "✎ " + relTo(ev.Project, ev.File)})
+		case history.KindAction:
+			text := "herd " + ev.Action
+			if ev.Trigger != "" {
+				text += " (" + ev.Trigger + ")"
+			}
+			if ev.Detail != "" {
+				text += ": " + ev.Detail
+			}
+			out = append(out, activityEntry{at: ev.Time, text: "⚙ " + text})
+		}
+	}
+	if open >= 0 {
+		out[open].dur = now.Sub(out[open].at)
+		out[open].ongoing = true
+	}
+	return out
+}
+
+// relTo returns file relative to dir when it is inside it.
+func relTo(dir, file string) string {
+	if rel, err := filepath.Rel(dir, file); err == nil && dir != "" && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return file
+}
+
+func (m Model) updateActivityMode(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m = m.recalcLayout()
+		m.activityView.Width = m.width
+		m.activityView.Height = maxInt(1, m.height-4)
+		return m, nil
+
+	case activityLoadedMsg:
+		m.activityLoading = false
+		if msg.err != nil {
+			m.activityErr = msg.err.Error()
+			return m, nil
+		}
+		m.activityView.SetContent(renderActivity(msg.entries, m.width))
+		m.activityView.GotoBottom()
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "q", "L":
+			m.mode = ModeNormal
+			m.activityView = viewport.Model{}
+			return m, nil
+		}
+	}
+	var cmd tea.Cmd
+	m.activityView, cmd = m.activityView.Update(msg)
+	return m, cmd
+}
+
+// renderActivity lays out entries a line each, newest at the bottom.
+func renderActivity(entries []activityEntry, width int) string {
+	if len(entries) == 0 {
+		return pickerItemStyle.Render(i18n.T("Nothing recorded for this session in the last day."))
+	}
+	subtle := lipgloss.NewStyle().Foreground(colSubtle)
+	var lines []string
+	day := ""
+	for _, e := range entries {
+		if d := e.at.Format("Mon 2 Jan"); d != day {
+			day = d
+			lines = append(lines, subtle.Render("── "+d))
+		}
+		line := subtle.Render(e.at.Format("15:04:05")) + "  "
+		if e.state == "" {
+			line += "  " + e.text
+		} else {
+			label := stateIcon(e.state) + " " + e.state
+			if e.tool != "" {
+				label += " · " + e.tool
+			}
+			dur := report.FormatDuration(e.dur)
+			if e.ongoing {
+				dur += "+"
+			}
+			line += fmt.Sprintf("%s  %s", label, subtle.Render(dur))
+		}
+		lines = append(lines, " "+line)
+	}
+	return truncateLines(strings.Join(lines, "\n"), width)
+}
+
+func (m Model) renderActivityPanel() string {
+	var sb strings.Builder
+	sb.WriteString(styleOverlayTitle.Width(m.width).Render(i18n.Tf("Activity — %s (last 24h)", m.activityName)) + "\n\n")
+	switch {
+	case m.activityLoading:
+		sb.WriteString(pickerItemStyle.Render(i18n.T("Reading history…")) + "\n")
+	case m.activityErr != "":
+		sb.WriteString(styleOverlayError.Render(m.activityErr) + "\n")
+	default:
+		sb.WriteString(m.activityView.View() + "\n")
+	}
+	sb.WriteString(styleOverlayHelp.Render(i18n.T("[j/k] scroll  [esc] close")))
+	return sb.String()
+}
@@ -0,0 +1,67 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shnupta/herd/internal/history"
+	"github.com/shnupta/herd/internal/session"
+)
+
+func TestBuildActivity(t *testing.T) {
+	t0 := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	at := func(min int) time.Time { return t0.Add(time.Duration(min) * time.Minute) }
+	s := session.Session{ID: "sess-aaa", TmuxPane: "%1"}
+	events := []history.Event{
+		{Time: at(0), Kind: history.KindState, SessionID: "sess-aaa", State: "working"},
+		{Time: at(0), Kind: history.KindPrompt, SessionID: "sess-aaa", Detail: "fix the tests"},
+		{Time: at(1), Kind: history.KindState, SessionID: "sess-aaa", State: "working", Tool: "Bash"},
+		{Time: at(2), Kind: history.KindState, SessionID: "sess-aaa", State: "working", Tool: "Bash"},
+		{Time: at(3), Kind: history.KindState, SessionID: "sess-bbb", State: "waiting"},
+		{Time: at(4), Kind: history.KindFile, SessionID: "sess-aaa", Project: "/code", File: "/code/main_test.go"},
+		{Time: at(5), Kind: history.KindState, SessionID: "sess-aaa", State: "waiting"},
+		{Time: at(6), Kind: history.KindAction, TmuxPane: "%1", Action: history.ActionKeys, Trigger: "approve"},
+	}
+	got := buildActivity(events, s, at(10))
+
+	want := []activityEntry{
+		{at: at(0), state: "working", dur: time.Minute},
+		{at: at(0), text: "› prompt: fix the tests"},
+		{at: at(1), state: "working", tool: "Bash", dur: 4 * time.Minute},
+		{at: at(4), text: "✎ main_test.go"},
+		{at: at(5), state: "waiting", dur: 5 * time.Minute, ongoing: true},
+		{at: at(6), text: "⚙ herd keys (approve)"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d entries, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestActivityPanel(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	now := time.Now()
+	_ = history.AppendTo(m.historyPath, history.Event{Time: now.Add(-time.Minute), Kind: history.KindState, SessionID: "sess-aaa", State: "working", Tool: "Edit"})
+
+	m = pressKey(t, m, "L")
+	if m.mode != ModeActivity {
+		t.Fatalf("mode = %v, want ModeActivity", m.mode)
+	}
+	m, cmd := m.openActivity()
+	updated, _ := m.Update(cmd())
+	m = updated.(Model)
+	if view := m.View(); !strings.Contains(view, "working · Edit") {
+		t.Errorf("activity panel missing the working stretch:\n%s", view)
+	}
+
+	m = pressKey(t, m, "esc")
+	if m.mode != ModeNormal {
+		t.Errorf("mode = %v after esc", m.mode)
+	}
+}
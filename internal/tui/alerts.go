@@ -0,0 +1,56 @@
+package tui
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/shnupta/herd/internal/daemon"
+	"github.com/shnupta/herd/internal/notify"
+	"github.com/shnupta/herd/internal/session"
+)
+
+// alertOut is where terminal alerts are written; herd's own terminal.
+var alertOut io.Writer = os.Stdout
+
+// urgentAlerts returns a Cmd that alerts the terminal, as configured by
+// urgent_alert, for each session that has just entered one of the
+// alert_states (plan-ready or notifying by default), unless herd's window
+// is already on screen or a daemon is running to alert instead.
+func (m Model) urgentAlerts(before map[string]session.State) tea.Cmd {
+	if !notify.Valid(m.urgentAlert) {
+		return nil
+	}
+	var names []string
+	for _, s := range notify.Arrivals(before, m.sessions, m.alertStates) {
+		names = append(names, m.displayName(s))
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	client, sock := m.tmuxClient, m.daemonSock
+	style := m.urgentAlert
+	return func() tea.Msg {
+		if daemon.Running(sock) {
+			return nil
+		}
+		if active, err := client.HerdWindowActive(); err == nil && active {
+			return nil
+		}
+		seq := "\a"
+		if style != "bell" {
+			seq = desktopNotification(style, "herd", strings.Join(names, ", ")+" needs you") + seq
+		}
+		_, _ = io.WriteString(alertOut, seq)
+		return nil
+	}
+}
+
+// desktopNotification returns the escape sequence posting a desktop
+// notification in style, wrapped for tmux to pass through to the outer
+// terminal.
+func desktopNotification(style, title, body string) string {
+	return notify.Passthrough(notify.Notification(style, title, body))
+}
@@ -0,0 +1,129 @@
+package tui
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shnupta/herd/internal/daemon"
+	"github.com/shnupta/herd/internal/session"
+	"github.com/shnupta/herd/internal/state"
+	"github.com/shnupta/herd/internal/tmux"
+	"github.com/shnupta/herd/internal/tmux/tmuxtest"
+)
+
+func TestUrgentAlerts(t *testing.T) {
+	var out bytes.Buffer
+	orig := alertOut
+	alertOut = &out
+	t.Cleanup(func() { alertOut = orig })
+
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	mock := m.tmuxClient.(*tmuxtest.MockClient)
+	before := sessionStates(m.sessions)
+	m.sessions[1].State = session.StatePlanReady
+
+	if cmd := m.urgentAlerts(before); cmd != nil {
+		t.Fatal("alerted with urgent_alert unset")
+	}
+
+	m.urgentAlert = "bell"
+	mock.HerdWindowActiveVal = true
+	m.urgentAlerts(before)()
+	if out.Len() != 0 {
+		t.Fatalf("alerted while herd's window is on screen: %q", out.String())
+	}
+
+	mock.HerdWindowActiveVal = false
+	m.urgentAlerts(before)()
+	if out.String() != "\a" {
+		t.Fatalf("bell wrote %q", out.String())
+	}
+
+	out.Reset()
+	m.urgentAlert = "notify"
+	m.urgentAlerts(before)()
+	if got := out.String(); !strings.Contains(got, "777;notify;herd;project-beta needs you") || !strings.HasSuffix(got, "\a") {
+		t.Errorf("notify wrote %q", got)
+	}
+
+	out.Reset()
+	m.urgentAlert = "osc9"
+	m.urgentAlerts(before)()
+	if got := out.String(); !strings.Contains(got, "]9;herd: project-beta needs you") || !strings.HasSuffix(got, "\a") {
+		t.Errorf("osc9 wrote %q", got)
+	}
+
+	// Leaving an urgent state, or staying in one, is not news.
+	if cmd := m.urgentAlerts(sessionStates(m.sessions)); cmd != nil {
+		t.Error("alerted without a transition")
+	}
+}
+
+func TestAlertStates(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	m.urgentAlert = "bell"
+	before := sessionStates(m.sessions)
+	m.sessions[0].State = session.StateWaiting
+
+	if cmd := m.urgentAlerts(before); cmd != nil {
+		t.Error("alerted on waiting, which is off by default")
+	}
+	m.alertStates = []string{"waiting"}
+	if cmd := m.urgentAlerts(before); cmd == nil {
+		t.Error("no alert on waiting with alert_states including it")
+	}
+	m.sessions[0].State = session.StatePlanReady
+	if cmd := m.urgentAlerts(before); cmd != nil {
+		t.Error("alerted on plan_ready, which alert_states leaves out")
+	}
+}
+
+func TestDaemonTakesOverDiscoveryAndAlerts(t *testing.T) {
+	var out bytes.Buffer
+	orig := alertOut
+	alertOut = &out
+	t.Cleanup(func() { alertOut = orig })
+
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	m.urgentAlert = "bell"
+	before := sessionStates(m.sessions)
+	m.sessions[1].State = session.StatePlanReady
+
+	// A socket path short enough for macOS's limit.
+	dir, err := os.MkdirTemp("", "herdd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	m.daemonSock = filepath.Join(dir, "d.sock")
+	d := daemon.New(&tmuxtest.MockClient{Panes: []tmux.Pane{
+		{ID: "%7", SessionName: "work", WindowIndex: 2, CurrentCmd: "claude", CurrentPath: "/code/api"},
+	}}, func() ([]state.SessionState, error) {
+		return []state.SessionState{{SessionID: "abc", TmuxPane: "%7", State: "waiting"}}, nil
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = d.Run(ctx, m.daemonSock, nil) }()
+	for deadline := time.Now().Add(3 * time.Second); !daemon.Running(m.daemonSock); time.Sleep(20 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("daemon didn't start")
+		}
+	}
+
+	msg, ok := m.discoverSessions()().(sessionsDiscoveredMsg)
+	if !ok || len(msg) != 1 || msg[0].TmuxPane != "%7" || msg[0].TmuxSession != "work" || msg[0].WindowIndex != 2 || msg[0].State != session.StateWaiting {
+		t.Errorf("discovered %+v, want the daemon's session", msg)
+	}
+	m.urgentAlerts(before)()
+	if out.Len() != 0 {
+		t.Errorf("alerted alongside the daemon: %q", out.String())
+	}
+}
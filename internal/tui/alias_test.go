@@ -0,0 +1,46 @@
+package tui
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/shnupta/herd/internal/session"
+)
+
+func TestMigrateKeysCarriesPinAndOrder(t *testing.T) {
+	m, fw := newTestModel(t, []session.Session{
+		{TmuxPane: "%5", ProjectPath: "/home/user/project-alpha"},
+		{ID: "sess-bbb", TmuxPane: "%2", ProjectPath: "/home/user/project-beta"},
+	})
+	defer fw.Close()
+
+	m.migrateKeys() // record the initial keys
+	m.pinned["pane:%5"] = 1
+	m.savedOrder = []string{"session:sess-bbb", "pane:%5"}
+
+	// Hooks report the session ID for %5 — its key becomes session:sess-new.
+	m.sessions[0].ID = "sess-new"
+	m.migrateKeys()
+
+	if _, ok := m.pinned["pane:%5"]; ok {
+		t.Error("expected old pane key to be unpinned")
+	}
+	if m.pinned["session:sess-new"] != 1 {
+		t.Errorf("expected pin carried to session:sess-new, got pinned=%v", m.pinned)
+	}
+	want := []string{"session:sess-bbb", "session:sess-new"}
+	if !reflect.DeepEqual(m.savedOrder, want) {
+		t.Errorf("savedOrder = %v, want %v", m.savedOrder, want)
+	}
+	if !m.sidebarDirty {
+		t.Error("expected sidebarDirty after a migration")
+	}
+}
+
+func TestRenameInOrderDropsDuplicate(t *testing.T) {
+	got := renameInOrder([]string{"a", "old", "new"}, "old", "new")
+	want := []string{"a", "new"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("renameInOrder() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,324 @@
+package tui
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/shnupta/herd/internal/diff"
+	"github.com/shnupta/herd/internal/git"
+	"github.com/shnupta/herd/internal/i18n"
+)
+
+// attachLimit caps how much of one file goes into a prompt; a longer file
+// is cut short with a note saying so.
+const attachLimit = 32 * 1024
+
+// attachment is a file or diff quoted after a composed prompt.
+type attachment struct {
+	label string // e.g. "internal/tui/view.go" or "diff of internal/tui/view.go"
+	lang  string // code fence info string
+	body  string
+}
+
+// attachCandidate is an entry of the attach picker; load reads it only
+// once chosen.
+type attachCandidate struct {
+	label string
+	load  func() (attachment, error)
+}
+
+// attachPicker filters the files or diffs that can be attached.
+type attachPicker struct {
+	title      string
+	filter     textinput.Model
+	candidates []attachCandidate
+	shown      []int // indices into candidates matching the filter
+	selected   int
+}
+
+// openCompose starts a prompt for the selected session, to which files and
+// diffs from its project can be attached before sending.
+func (m Model) openCompose() (Model, tea.Cmd) {
+	sel := m.selectedSession()
+	if sel == nil {
+		return m, nil
+	}
+	ta := textarea.New()
+	ta.Placeholder = i18n.T("prompt...")
+	ta.ShowLineNumbers = false
+	ta.CharLimit = 0
+	ta.SetWidth(maxInt(20, m.width-4))
+	ta.SetHeight(m.composeInputHeight())
+	ta.Focus()
+
+	m.composeInput = ta
+	m.composePane = sel.TmuxPane
+	m.composeDir = sel.ProjectPath
+	m.composeAttached = nil
+	m.composeErr = ""
+	m.attachPick = nil
+	m.mode = ModeCompose
+	return m, textarea.Blink
+}
+
+// composeInputHeight leaves room below the prompt for the attachment list.
+func (m Model) composeInputHeight() int {
+	return maxInt(3, (m.height-8)/2)
+}
+
+func (m Model) updateComposeMode(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.attachPick != nil {
+		return m.updateAttachPicker(msg)
+	}
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m = m.recalcLayout()
+		m.composeInput.SetWidth(maxInt(20, m.width-4))
+		m.composeInput.SetHeight(m.composeInputHeight())
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			m.mode = ModeNormal
+			m.composeAttached = nil
+			return m, nil
+		case "ctrl+s":
+			text := composePrompt(m.composeInput.Value(), m.composeAttached)
+			if text == "" {
+				return m, nil
+			}
+			if err := m.pastePrompt(m.composePane, text, "compose"); err != nil {
+				m.composeErr = err.Error()
+				return m, nil
+			}
+			m.mode = ModeNormal
+			m.composeAttached = nil
+			return m, nil
+		case "ctrl+f":
+			return m.openAttachPicker(i18n.T("Attach File"), fileCandidates)
+		case "ctrl+d":
+			return m.openAttachPicker(i18n.T("Attach Diff"), diffCandidates)
+		case "ctrl+b":
+			m.composeInput.Blur()
+			m.files = newFileBrowser(m.composeDir, true)
+			m.mode = ModeFiles
+			return m, nil
+		case "ctrl+x":
+			if n := len(m.composeAttached); n > 0 {
+				m.composeAttached = m.composeAttached[:n-1]
+			}
+			return m, nil
+		}
+	}
+	var cmd tea.Cmd
+	m.composeInput, cmd = m.composeInput.Update(msg)
+	return m, cmd
+}
+
+// openAttachPicker lists what list finds in the session's project.
+func (m Model) openAttachPicker(title string, list func(dir string) ([]attachCandidate, error)) (Model, tea.Cmd) {
+	candidates, err := list(m.composeDir)
+	if err != nil {
+		m.composeErr = err.Error()
+		return m, nil
+	}
+	if len(candidates) == 0 {
+		m.composeErr = i18n.T("nothing to attach")
+		return m, nil
+	}
+	fi := textinput.New()
+	fi.Placeholder = i18n.T("filter...")
+	fi.CharLimit = 200
+	m.composeErr = ""
+	m.composeInput.Blur()
+	m.attachPick = &attachPicker{title: title, filter: fi, candidates: candidates}
+	m.attachPick.refilter()
+	return m, m.attachPick.filter.Focus()
+}
+
+func (m Model) updateAttachPicker(msg tea.Msg) (tea.Model, tea.Cmd) {
+	p := m.attachPick
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "esc":
+			m.attachPick = nil
+			return m, m.composeInput.Focus()
+		case "up", "ctrl+p":
+			if p.selected > 0 {
+				p.selected--
+			}
+			return m, nil
+		case "down", "ctrl+n":
+			if p.selected < len(p.shown)-1 {
+				p.selected++
+			}
+			return m, nil
+		case "enter":
+			if p.selected < len(p.shown) {
+				a, err := p.candidates[p.shown[p.selected]].load()
+				if err != nil {
+					m.composeErr = err.Error()
+				} else {
+					m.composeAttached = append(m.composeAttached, a)
+				}
+			}
+			m.attachPick = nil
+			return m, m.composeInput.Focus()
+		}
+	}
+	var cmd tea.Cmd
+	before := p.filter.Value()
+	p.filter, cmd = p.filter.Update(msg)
+	if p.filter.Value() != before {
+		p.refilter()
+	}
+	return m, cmd
+}
+
+// refilter keeps the candidates whose labels contain the filter, ignoring
+// case.
+func (p *attachPicker) refilter() {
+	q := strings.ToLower(p.filter.Value())
+	p.shown = p.shown[:0]
+	for i, c := range p.candidates {
+		if strings.Contains(strings.ToLower(c.label), q) {
+			p.shown = append(p.shown, i)
+		}
+	}
+	p.selected = 0
+}
+
+// fileCandidates lists the files in dir's repository that .gitignore
+// doesn't exclude.
+func fileCandidates(dir string) ([]attachCandidate, error) {
+	files, err := git.ListFiles(dir)
+	if err != nil {
+		return nil, fmt.Errorf("listing files in %s: %w", shortenPath(dir), err)
+	}
+	out := make([]attachCandidate, len(files))
+	for i, f := range files {
+		out[i] = attachCandidate{label: f, load: func() (attachment, error) { return readAttachment(dir, f) }}
+	}
+	return out, nil
+}
+
+// readAttachment reads the file at rel under dir, refusing binary files.
+func readAttachment(dir, rel string) (attachment, error) {
+	data, err := os.ReadFile(filepath.Join(dir, rel))
+	if err != nil {
+		return attachment{}, err
+	}
+	if bytes.IndexByte(data, 0) >= 0 {
+		return attachment{}, fmt.Errorf("%s is a binary file", rel)
+	}
+	label, body := rel, string(data)
+	if len(body) > attachLimit {
+		// Cut at the start of the rune that would cross the limit.
+		cut := attachLimit
+		for cut > 0 && !utf8.RuneStart(body[cut]) {
+			cut--
+		}
+		body = body[:cut] + fmt.Sprintf("\n… (cut at %d KB of %d KB)", attachLimit/1024, len(data)/1024)
+		label = i18n.Tf("%s (truncated to its first %d KB)", rel, attachLimit/1024)
+	}
+	return attachment{label: label, lang: strings.TrimPrefix(filepath.Ext(rel), "."), body: body}, nil
+}
+
+// diffCandidates lists the uncommitted changes in dir's repository, a file
+// at a time.
+func diffCandidates(dir string) ([]attachCandidate, error) {
+	root, err := diff.GetGitRoot(dir)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not in a git repository", shortenPath(dir))
+	}
+	text, err := diff.GetGitDiff(root)
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := diff.Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]attachCandidate, len(parsed.Files))
+	for i := range parsed.Files {
+		f := &parsed.Files[i]
+		a := attachment{label: "diff of " + f.GetFilePath(), lang: "diff", body: f.Unified()}
+		out[i] = attachCandidate{label: a.label, load: func() (attachment, error) { return a, nil }}
+	}
+	return out, nil
+}
+
+// composePrompt joins a prompt and its attachments, each quoted in a code
+// fence under its label, or "" when there is nothing to send.
+func composePrompt(prompt string, attached []attachment) string {
+	var parts []string
+	if p := strings.TrimSpace(prompt); p != "" {
+		parts = append(parts, p)
+	}
+	for _, a := range attached {
+		fence := "```"
+		for strings.Contains(a.body, fence) {
+			fence += "`"
+		}
+		parts = append(parts, a.label+":\n"+fence+a.lang+"\n"+strings.TrimSuffix(a.body, "\n")+"\n"+fence)
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+func (m Model) renderComposeOverlay() string {
+	if p := m.attachPick; p != nil {
+		return m.renderAttachPicker(p)
+	}
+	var sb strings.Builder
+	sb.WriteString(styleOverlayTitle.Width(m.width).Render(i18n.T("Compose Prompt")+" — "+shortenPath(m.composeDir)) + "\n\n")
+	sb.WriteString(m.composeInput.View() + "\n\n")
+	subtle := lipgloss.NewStyle().Foreground(colSubtle)
+	if len(m.composeAttached) == 0 {
+		sb.WriteString(pickerItemStyle.Render(subtle.Render(i18n.T("No attachments"))) + "\n")
+	}
+	for _, a := range m.composeAttached {
+		lines := strings.Count(a.body, "\n") + 1
+		sb.WriteString(pickerItemStyle.Render("📎 "+a.label+"  "+subtle.Render(i18n.Tf("%d lines", lines))) + "\n")
+	}
+	sb.WriteString("\n")
+	if m.composeErr != "" {
+		sb.WriteString(styleOverlayError.Render(m.composeErr) + "\n")
+	}
+	sb.WriteString(styleOverlayHelp.Render(i18n.T("[ctrl+s] send  [ctrl+f] find file  [ctrl+b] browse files  [ctrl+d] attach diff  [ctrl+x] drop last  [esc] cancel")))
+	return sb.String()
+}
+
+func (m Model) renderAttachPicker(p *attachPicker) string {
+	var sb strings.Builder
+	sb.WriteString(styleOverlayTitle.Width(m.width).Render(p.title) + "\n\n")
+	sb.WriteString(styleOverlayInput.Render(p.filter.View()) + "\n\n")
+
+	// Keep the selection inside a window of the list that fits the screen.
+	rows := maxInt(1, m.height-8)
+	first := minInt(maxInt(0, p.selected-rows+1), maxInt(0, len(p.shown)-rows))
+	for i := first; i < minInt(first+rows, len(p.shown)); i++ {
+		label := p.candidates[p.shown[i]].label
+		if i == p.selected {
+			sb.WriteString(pickerSelectedStyle.Width(m.width-4).Render("▸ "+label) + "\n")
+		} else {
+			sb.WriteString(pickerItemStyle.Render("  "+label) + "\n")
+		}
+	}
+	if len(p.shown) == 0 {
+		sb.WriteString(pickerItemStyle.Render(i18n.T("No matches")) + "\n")
+	}
+	sb.WriteString("\n" + styleOverlayHelp.Render(i18n.Tf("%d of %d  [↑/↓] select  [enter] attach  [esc] back", len(p.shown), len(p.candidates))))
+	return sb.String()
+}
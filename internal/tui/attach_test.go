@@ -0,0 +1,147 @@
+package tui
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/shnupta/herd/internal/tmux/tmuxtest"
+)
+
+func TestComposeAttachesFilesAndDiffs(t *testing.T) {
+	repo := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		if out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, text string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repo, name), []byte(text), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run("init")
+	run("config", "user.email", "test@test.com")
+	run("config", "user.name", "Test")
+	write("main.go", "package main\n")
+	write("lib.go", "package main\n\nfunc lib() {}\n")
+	run("add", ".")
+	run("commit", "-m", "first")
+	write("main.go", "package main\n\nfunc main() {}\n")
+
+	sessions := testSessions()[:1]
+	sessions[0].ProjectPath = repo
+	m, fw := newTestModel(t, sessions)
+	defer fw.Close()
+	mock := m.tmuxClient.(*tmuxtest.MockClient)
+	ctrl := func(k tea.KeyType) {
+		t.Helper()
+		updated, _ := m.Update(tea.KeyMsg{Type: k})
+		m = updated.(Model)
+	}
+
+	m = pressKey(t, m, "A")
+	if m.mode != ModeCompose {
+		t.Fatalf("mode = %v, want ModeCompose", m.mode)
+	}
+	m.composeInput.SetValue("Why does lib exist?")
+
+	ctrl(tea.KeyCtrlF)
+	if m.attachPick == nil || len(m.attachPick.candidates) != 2 {
+		t.Fatalf("file picker = %+v, want main.go and lib.go", m.attachPick)
+	}
+	m = pressKey(t, m, "lib")
+	m = pressKey(t, m, "enter")
+
+	ctrl(tea.KeyCtrlD)
+	if m.attachPick == nil || len(m.attachPick.shown) != 1 {
+		t.Fatalf("diff picker = %+v, want the change to main.go", m.attachPick)
+	}
+	m = pressKey(t, m, "enter")
+	if len(m.composeAttached) != 2 || m.composeErr != "" {
+		t.Fatalf("attached = %+v, err = %q", m.composeAttached, m.composeErr)
+	}
+
+	ctrl(tea.KeyCtrlS)
+	if m.mode != ModeNormal || len(mock.PasteCalls) != 1 || len(mock.SendKeysCalls) != 0 {
+		t.Fatalf("mode = %v, pasted = %q, typed = %q; want one prompt pasted", m.mode, mock.PasteCalls, mock.SendKeysCalls)
+	}
+	if want := []string{"%1:Enter"}; !reflect.DeepEqual(mock.SendKeyCalls, want) {
+		t.Errorf("keys = %q, want %q to submit the paste", mock.SendKeyCalls, want)
+	}
+	sent := mock.PasteCalls[0]
+	for _, want := range []string{
+		"Why does lib exist?\n\nlib.go:\n```go\npackage main\n\nfunc lib() {}\n```",
+		"diff of main.go:\n```diff\n--- a/main.go\n+++ b/main.go\n",
+		"+func main() {}\n```",
+	} {
+		if !strings.Contains(sent, want) {
+			t.Errorf("sent prompt lacks %q:\n%s", want, sent)
+		}
+	}
+}
+
+func TestComposePromptFencesAroundBackticks(t *testing.T) {
+	got := composePrompt("  ", []attachment{{label: "README.md", lang: "md", body: "```sh\nls\n```\n"}})
+	want := "README.md:\n````md\n```sh\nls\n```\n````"
+	if got != want {
+		t.Errorf("composePrompt = %q, want %q", got, want)
+	}
+	if got := composePrompt(" \n", nil); got != "" {
+		t.Errorf("composePrompt with nothing = %q, want empty", got)
+	}
+}
+
+func TestInsertComposerSendsWholePrompt(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	mock := m.tmuxClient.(*tmuxtest.MockClient)
+	m.insertComposer = true
+	pane := m.selectedSession().TmuxPane
+
+	m = pressKey(t, m, "i")
+	if m.mode != ModeCompose || m.insertMode {
+		t.Fatalf("mode = %v, insertMode = %v; want the composer", m.mode, m.insertMode)
+	}
+	m = pressKey(t, m, "fix the flaky test")
+	m = pressKey(t, m, "enter")
+	m = pressKey(t, m, "then rerun it")
+	if len(mock.SendLiteralCalls)+len(mock.PasteCalls) != 0 {
+		t.Fatalf("sent while composing: %q %q", mock.SendLiteralCalls, mock.PasteCalls)
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	m = updated.(Model)
+	want := pane + ":fix the flaky test\nthen rerun it"
+	if m.mode != ModeNormal || len(mock.PasteCalls) != 1 || mock.PasteCalls[0] != want {
+		t.Errorf("mode = %v, sent = %q; want %q", m.mode, mock.PasteCalls, want)
+	}
+}
+
+func TestReadAttachmentCutsAtRune(t *testing.T) {
+	dir := t.TempDir()
+	// "é" is two bytes, so one straddles the limit.
+	body := strings.Repeat("a", attachLimit-1) + strings.Repeat("é", 10)
+	if err := os.WriteFile(filepath.Join(dir, "big.txt"), []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	a, err := readAttachment(dir, "big.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	kept, _, _ := strings.Cut(a.body, "\n…")
+	if !utf8.ValidString(a.body) || kept != strings.Repeat("a", attachLimit-1) {
+		t.Errorf("body cut to %d bytes, valid UTF-8 = %v", len(kept), utf8.ValidString(a.body))
+	}
+	if !strings.Contains(a.label, "truncated") {
+		t.Errorf("label = %q, want it to say the file was truncated", a.label)
+	}
+}
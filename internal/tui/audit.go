@@ -0,0 +1,72 @@
+package tui
+
+import (
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/shnupta/herd/internal/history"
+)
+
+// auditEvent describes action, taken on pane because of trigger, as a
+// history event for the session running there.
+func (m Model) auditEvent(pane, action, trigger, detail string) history.Event {
+	ev := history.Event{Kind: history.KindAction, TmuxPane: pane, Action: action, Trigger: trigger, Detail: detail}
+	for _, s := range m.sessions {
+		if s.TmuxPane == pane {
+			ev.SessionID, ev.Project = s.ID, s.ProjectPath
+			break
+		}
+	}
+	return ev
+}
+
+// audit records in the history log that herd took action on pane because
+// of trigger, e.g. the key or overlay the user acted in.
+func (m Model) audit(pane, action, trigger, detail string) {
+	_ = history.AppendTo(m.historyPath, m.auditEvent(pane, action, trigger, detail))
+}
+
+// auditCmd is audit for a Cmd that acts on pane later, off the Update
+// goroutine: it returns the log call to make once the action is done.
+func (m Model) auditCmd(pane, action, trigger, detail string) func() {
+	path, ev := m.historyPath, m.auditEvent(pane, action, trigger, detail)
+	return func() { _ = history.AppendTo(path, ev) }
+}
+
+// typeKey adds a key forwarded in insert mode to what is typed into panes
+// (several in group insert mode): typed text as it is, other keys by name
+// in angle brackets. Keystrokes are logged a line at a time rather than one
+// event each, when enter submits them, the panes change or insert mode
+// ends.
+func (m Model) typeKey(panes []string, msg tea.KeyMsg) Model {
+	if !slices.Equal(m.typedPanes, panes) {
+		m = m.flushTyped()
+		m.typedPanes = panes
+	}
+	if msg.Type == tea.KeyRunes {
+		m.typed += string(msg.Runes)
+	} else {
+		m.typed += "<" + msg.String() + ">"
+	}
+	if msg.Type == tea.KeyEnter {
+		m = m.flushTyped()
+	}
+	return m
+}
+
+// flushTyped records the keystrokes typed in insert mode since the last
+// flush, their text only with record_prompts.
+func (m Model) flushTyped() Model {
+	if m.typed != "" {
+		trigger := "insert mode"
+		if len(m.typedPanes) > 1 {
+			trigger = "group insert mode"
+		}
+		for _, pane := range m.typedPanes {
+			m.audit(pane, history.ActionKeys, trigger, history.Text(m.typed))
+		}
+	}
+	m.typed, m.typedPanes = "", nil
+	return m
+}
@@ -0,0 +1,90 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/shnupta/herd/internal/history"
+)
+
+func TestAuditRecordsHerdActions(t *testing.T) {
+	t.Cleanup(func() { history.SetRecordText(false) })
+	for _, record := range []bool{false, true} {
+		history.SetRecordText(record)
+		m, fw := newTestModel(t, testSessions())
+		defer fw.Close()
+
+		// Typing in insert mode is recorded a line at a time.
+		m = pressKey(t, m, "i")
+		for _, k := range []string{"h", "i", "enter"} {
+			m = pressKey(t, m, k)
+		}
+		m = pressKey(t, m, "o")
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlH})
+		m = updated.(Model)
+
+		if err := m.sendPrompt("%1", "fix the build", "compose"); err != nil {
+			t.Fatal(err)
+		}
+		m = pressKey(t, m, "x")
+
+		events, err := history.ReadSince(m.historyPath, time.Time{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, ev := range events {
+			if ev.Kind != history.KindAction || ev.Action == history.ActionResize {
+				continue
+			}
+			if ev.SessionID != "sess-aaa" || ev.TmuxPane != "%1" {
+				t.Errorf("event %+v should be alpha's", ev)
+			}
+			got = append(got, ev.Action+"|"+ev.Trigger+"|"+ev.Detail)
+		}
+		// What was typed and sent is kept only with record_prompts.
+		want := []string{
+			"keys|insert mode|",
+			"keys|insert mode|",
+			"prompt|compose|",
+			"kill|kill key|",
+		}
+		if record {
+			want = []string{
+				"keys|insert mode|hi<enter>",
+				"keys|insert mode|o",
+				"prompt|compose|fix the build",
+				"kill|kill key|",
+			}
+		}
+		if strings.Join(got, "\n") != strings.Join(want, "\n") {
+			t.Errorf("record_prompts %v: actions =\n%s\nwant\n%s", record, strings.Join(got, "\n"), strings.Join(want, "\n"))
+		}
+	}
+}
+
+func TestSidebarCountsHerdActions(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	now := time.Now()
+	for i := 0; i < 2; i++ {
+		ev := history.Event{Time: now.Add(-time.Minute), Kind: history.KindAction, SessionID: "sess-bbb", Action: history.ActionPrompt}
+		if err := history.AppendTo(m.historyPath, ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	updated, _ := m.Update(m.loadTimelines(now)())
+	m = updated.(Model)
+	view := ansi.Strip(m.View())
+	if !strings.Contains(view, "⚙2") {
+		t.Errorf("sidebar should count beta's two herd actions:\n%s", view)
+	}
+	if strings.Count(view, "⚙") != 1 {
+		t.Errorf("only beta was acted on:\n%s", view)
+	}
+}
@@ -0,0 +1,74 @@
+package tui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/shnupta/herd/internal/badge"
+)
+
+// badgesMsg carries the custom badges computed for each session key.
+type badgesMsg map[string][]badge.Badge
+
+// scanBadges captures the visible screen of every session and evaluates the
+// configured badge rules against it. It is a no-op without rules.
+func (m Model) scanBadges() tea.Cmd {
+	if len(m.badgeRules) == 0 || len(m.sessions) == 0 {
+		return nil
+	}
+	client := m.tmuxClient
+	rules := m.badgeRules
+	targets := make(map[string]string, len(m.sessions)) // key → pane
+	for _, s := range m.sessions {
+		targets[s.Key()] = s.TmuxPane
+	}
+	return func() tea.Msg {
+		result := make(badgesMsg, len(targets))
+		for key, pane := range targets {
+			out, err := client.CapturePane(pane, 0)
+			if err != nil {
+				continue
+			}
+			if badges := badge.Evaluate(rules, ansi.Strip(out)); len(badges) > 0 {
+				result[key] = badges
+			}
+		}
+		return result
+	}
+}
+
+// renderCustomBadges renders a session's rule and plugin badges, space
+// separated.
+func (m Model) renderCustomBadges(key string) string {
+	var parts []string
+	for _, b := range append(m.badges[key], m.pluginBadges[key]...) {
+		parts = append(parts, lipgloss.NewStyle().Foreground(badgeColor(b.Color)).Render(b.Text))
+	}
+	return strings.Join(parts, " ")
+}
+
+// badgeColor maps a rule's colour name onto the palette; anything else is
+// passed to lipgloss as-is (e.g. "#ff8800").
+func badgeColor(name string) lipgloss.Color {
+	switch strings.ToLower(name) {
+	case "":
+		return colSubtext
+	case "green":
+		return colGreen
+	case "red":
+		return colRed
+	case "amber", "yellow", "orange":
+		return colAmber
+	case "blue":
+		return colBlue
+	case "purple":
+		return colPurple
+	case "cyan":
+		return colCyan
+	default:
+		return lipgloss.Color(name)
+	}
+}
@@ -0,0 +1,46 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/shnupta/herd/internal/badge"
+	"github.com/shnupta/herd/internal/config"
+	"github.com/shnupta/herd/internal/tmux/tmuxtest"
+)
+
+func TestScanBadgesShowsRuleBadges(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	rules, err := badge.Compile([]config.BadgeRule{
+		{Name: "tests", Pattern: `All tests passed`, Text: "OK", Color: "green"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.badgeRules = rules
+	m.tmuxClient.(*tmuxtest.MockClient).CaptureOutput = "\x1b[32mAll tests passed\x1b[0m\n"
+
+	msg := m.scanBadges()()
+	updated, _ := m.Update(msg)
+	m = updated.(Model)
+
+	if len(m.badges) != len(m.sessions) {
+		t.Fatalf("badges for %d sessions, want %d", len(m.badges), len(m.sessions))
+	}
+	item := ansi.Strip(m.renderSessionItem(0, m.sessions[0], "", false, false))
+	if !strings.Contains(item, "OK") {
+		t.Errorf("session item missing rule badge:\n%s", item)
+	}
+}
+
+func TestScanBadgesWithoutRulesIsNoop(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	m.badgeRules = nil
+	if cmd := m.scanBadges(); cmd != nil {
+		t.Error("expected no scan without badge rules")
+	}
+}
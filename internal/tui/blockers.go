@@ -0,0 +1,170 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/shnupta/herd/internal/config"
+	"github.com/shnupta/herd/internal/i18n"
+	"github.com/shnupta/herd/internal/prompt"
+	"github.com/shnupta/herd/internal/session"
+	"github.com/shnupta/herd/internal/store"
+)
+
+// Blocked-on relationships live in m.blockers: blocked session key → the key
+// of the session it waits for.
+
+// renameBlockerKey carries a key change over both sides of every
+// relationship in s.
+func renameBlockerKey(s *store.Store, oldKey, newKey string) {
+	_ = s.Rename(oldKey, newKey)
+	for blocked, blocker := range s.All() {
+		if blocker == oldKey {
+			_ = s.Set(blocked, newKey)
+		}
+	}
+}
+
+// blockedOnName returns the display name of the session s is blocked on, or
+// "" when it isn't blocked on a live session.
+func (m Model) blockedOnName(s session.Session) string {
+	blocker := m.blockers.Get(s.Key())
+	if blocker == "" {
+		return ""
+	}
+	for _, other := range m.sessions {
+		if other.Key() == blocker {
+			return m.displayName(other)
+		}
+	}
+	return ""
+}
+
+// openBlockedOn shows the picker for what the selected session is blocked on.
+// The first choice clears the relationship; the rest are the other sessions.
+func (m Model) openBlockedOn() Model {
+	sel := m.selectedSession()
+	if sel == nil {
+		return m
+	}
+	m.blockedOnKey = sel.Key()
+	m.blockedOnChoices = []string{""}
+	m.blockedOnSelected = 0
+	current := m.blockers.Get(sel.Key())
+	for _, s := range m.sessions {
+		if s.Key() == sel.Key() {
+			continue
+		}
+		if s.Key() == current {
+			m.blockedOnSelected = len(m.blockedOnChoices)
+		}
+		m.blockedOnChoices = append(m.blockedOnChoices, s.Key())
+	}
+	m.mode = ModeBlockedOn
+	return m
+}
+
+func (m Model) updateBlockedOnMode(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m = m.recalcLayout()
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "q":
+			m.mode = ModeNormal
+			m.blockedOnChoices = nil
+			return m, nil
+		case "k", "up":
+			if m.blockedOnSelected > 0 {
+				m.blockedOnSelected--
+			}
+		case "j", "down":
+			if m.blockedOnSelected < len(m.blockedOnChoices)-1 {
+				m.blockedOnSelected++
+			}
+		case "enter":
+			if m.blockedOnSelected < len(m.blockedOnChoices) {
+				if err := m.blockers.Set(m.blockedOnKey, m.blockedOnChoices[m.blockedOnSelected]); err != nil {
+					m.err = err
+				}
+			}
+			m.mode = ModeNormal
+			m.blockedOnChoices = nil
+			m.itemsDirty = true
+			return m, nil
+		}
+	}
+	return m, nil
+}
+
+// releaseBlocked unblocks every session waiting on blocker: it sends them the
+// configured unblock_prompt, if any, announces it in tmux and forgets the
+// relationship.
+func (m *Model) releaseBlocked(blocker session.Session) {
+	tmpl := config.Load().UnblockPrompt
+	blockerName := m.displayName(blocker)
+	for _, s := range m.sessions {
+		if m.blockers.Get(s.Key()) != blocker.Key() {
+			continue
+		}
+		_ = m.blockers.Delete(s.Key())
+		m.itemsDirty = true
+		if tmpl != "" {
+			text, err := prompt.Render(tmpl, map[string]string{
+				"blocker":         blockerName,
+				"blocker_project": blocker.ProjectPath,
+				"project":         s.ProjectPath,
+			})
+			if err == nil {
+				_ = m.sendPrompt(s.TmuxPane, text, "unblock")
+			}
+		}
+		_ = m.tmuxClient.DisplayMessage(fmt.Sprintf("herd: %s unblocked — %s is done", m.displayName(s), blockerName))
+	}
+}
+
+// releaseNowWaiting unblocks the dependents of every session that has just
+// moved into waiting.
+func (m *Model) releaseNowWaiting(before map[string]session.State) {
+	for _, s := range m.sessions {
+		if prev, ok := before[s.TmuxPane]; ok && prev != session.StateWaiting && s.State == session.StateWaiting {
+			m.releaseBlocked(s)
+		}
+	}
+}
+
+func (m Model) renderBlockedOnOverlay() string {
+	var sb strings.Builder
+	name := m.blockedOnKey
+	for _, s := range m.sessions {
+		if s.Key() == m.blockedOnKey {
+			name = m.displayName(s)
+		}
+	}
+	sb.WriteString(styleOverlayTitle.Width(m.width).Render(i18n.T("Blocked On")+" — "+name) + "\n\n")
+
+	subtle := lipgloss.NewStyle().Foreground(colSubtle)
+	for i, key := range m.blockedOnChoices {
+		line := "(not blocked)"
+		for _, s := range m.sessions {
+			if s.Key() == key {
+				line = m.displayName(s) + "  " + subtle.Render(shortenPath(s.ProjectPath))
+			}
+		}
+		if i == m.blockedOnSelected {
+			sb.WriteString(pickerSelectedStyle.Width(m.width-4).Render("▸ "+line) + "\n")
+		} else {
+			sb.WriteString(pickerItemStyle.Render("  "+line) + "\n")
+		}
+	}
+	sb.WriteString("\n" + pickerItemStyle.Render(subtle.Render("Unblocked when that session next waits for input or its review is submitted without comments.")) + "\n")
+	sb.WriteString("\n" + styleOverlayHelp.Render(i18n.T("[j/k] navigate  [enter] set  [esc] cancel")))
+	return sb.String()
+}
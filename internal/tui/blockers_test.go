@@ -0,0 +1,89 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shnupta/herd/internal/state"
+	"github.com/shnupta/herd/internal/store"
+	"github.com/shnupta/herd/internal/tmux/tmuxtest"
+)
+
+func TestBlockedOnPickerSetsAndClears(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+
+	m = pressKey(t, m, "B")
+	if m.mode != ModeBlockedOn {
+		t.Fatalf("mode = %d, want blocked-on picker", m.mode)
+	}
+	// Choices: (not blocked), beta, gamma.
+	m = pressKey(t, m, "j")
+	m = pressKey(t, m, "enter")
+	if got := m.blockers.Get("session:sess-aaa"); got != "session:sess-bbb" {
+		t.Fatalf("blocker = %q, want session:sess-bbb", got)
+	}
+	if name := m.blockedOnName(m.sessions[0]); name != "project-beta" {
+		t.Errorf("blockedOnName = %q", name)
+	}
+
+	// Reopening starts on the current blocker; the first row clears it.
+	m = pressKey(t, m, "B")
+	if m.blockedOnSelected != 1 {
+		t.Errorf("picker opened on row %d, want the current blocker", m.blockedOnSelected)
+	}
+	m = pressKey(t, m, "k")
+	m = pressKey(t, m, "enter")
+	if got := m.blockers.Get("session:sess-aaa"); got != "" {
+		t.Errorf("blocker = %q after clearing", got)
+	}
+}
+
+func TestBlockedSessionReleasedWhenBlockerWaits(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	cfg := `{"unblock_prompt": "{{.blocker}} is done, carry on"}`
+	if err := os.MkdirAll(filepath.Join(home, ".herd"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, ".herd", "config.json"), []byte(cfg), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	mock := m.tmuxClient.(*tmuxtest.MockClient)
+	_ = m.blockers.Set("session:sess-ccc", "session:sess-aaa")
+
+	updated, _ := m.Update(stateUpdateMsg(state.SessionState{
+		SessionID: "sess-aaa", TmuxPane: "%1", State: "waiting", UpdatedAt: time.Now(),
+	}))
+	m = updated.(Model)
+
+	if got := m.blockers.Get("session:sess-ccc"); got != "" {
+		t.Errorf("relationship kept after release: %q", got)
+	}
+	if want := []string{"%3:project-alpha is done, carry on"}; !reflect.DeepEqual(mock.SendKeysCalls, want) {
+		t.Errorf("SendKeys = %q, want %q", mock.SendKeysCalls, want)
+	}
+	if len(mock.Messages) != 1 || !strings.Contains(mock.Messages[0], "project-gamma unblocked") {
+		t.Errorf("tmux messages = %q", mock.Messages)
+	}
+}
+
+func TestRenameBlockerKeyRewritesBothSides(t *testing.T) {
+	s := store.NewStore(filepath.Join(t.TempDir(), "blockers.json"))
+	_ = s.Set("pane:%1", "pane:%2")
+	_ = s.Set("pane:%3", "pane:%1")
+
+	renameBlockerKey(s, "pane:%1", "session:a")
+
+	want := map[string]string{"session:a": "pane:%2", "pane:%3": "session:a"}
+	if got := s.All(); !reflect.DeepEqual(got, want) {
+		t.Errorf("blockers = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,128 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/shnupta/herd/internal/i18n"
+	"github.com/shnupta/herd/internal/session"
+)
+
+// toggleMark marks the selected session to receive broadcasts, or unmarks
+// it.
+func (m *Model) toggleMark() {
+	sel := m.selectedSession()
+	if sel == nil {
+		return
+	}
+	if m.marked[sel.Key()] {
+		delete(m.marked, sel.Key())
+	} else {
+		m.marked[sel.Key()] = true
+	}
+}
+
+// markedSessions returns the live sessions marked for broadcast, in
+// sidebar order.
+func (m Model) markedSessions() []session.Session {
+	var out []session.Session
+	for _, s := range m.sessions {
+		if m.marked[s.Key()] {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// openBroadcast starts a prompt to send to every marked session.
+func (m Model) openBroadcast() (Model, tea.Cmd) {
+	if len(m.markedSessions()) == 0 {
+		_ = m.tmuxClient.DisplayMessage("herd: " + i18n.T("no sessions marked — mark them with v first"))
+		return m, nil
+	}
+	ta := textarea.New()
+	ta.Placeholder = i18n.T("prompt...")
+	ta.ShowLineNumbers = false
+	ta.CharLimit = 0
+	ta.SetWidth(maxInt(20, m.width-4))
+	ta.SetHeight(m.composeInputHeight())
+	ta.Focus()
+
+	m.broadcastInput = ta
+	m.broadcastErr = ""
+	m.mode = ModeBroadcast
+	return m, textarea.Blink
+}
+
+func (m Model) updateBroadcastMode(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m = m.recalcLayout()
+		m.broadcastInput.SetWidth(maxInt(20, m.width-4))
+		m.broadcastInput.SetHeight(m.composeInputHeight())
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			m.mode = ModeNormal
+			return m, nil
+		case "ctrl+s":
+			text := strings.TrimSpace(m.broadcastInput.Value())
+			if text == "" {
+				return m, nil
+			}
+			if failed := m.broadcast(text); len(failed) > 0 {
+				m.broadcastErr = i18n.Tf("not sent to %s", strings.Join(failed, ", "))
+				return m, nil
+			}
+			m.mode = ModeNormal
+			return m, nil
+		}
+	}
+	var cmd tea.Cmd
+	m.broadcastInput, cmd = m.broadcastInput.Update(msg)
+	return m, cmd
+}
+
+// broadcast sends text to every marked session, returning the names of
+// those it couldn't be sent to. The marks stay for the next prompt, unless
+// some sends failed: then only those sessions stay marked, so sending again
+// retries just them.
+func (m Model) broadcast(text string) (failed []string) {
+	var sent []string
+	for _, s := range m.markedSessions() {
+		if err := m.sendPrompt(s.TmuxPane, text, "broadcast"); err != nil {
+			failed = append(failed, fmt.Sprintf("%s (%v)", m.displayName(s), err))
+			continue
+		}
+		sent = append(sent, s.Key())
+	}
+	if len(failed) > 0 {
+		for _, k := range sent {
+			delete(m.marked, k)
+		}
+	}
+	return failed
+}
+
+func (m Model) renderBroadcastOverlay() string {
+	targets := m.markedSessions()
+	var sb strings.Builder
+	sb.WriteString(styleOverlayTitle.Width(m.width).Render(i18n.Tf("Broadcast to %d sessions", len(targets))) + "\n\n")
+	sb.WriteString(m.broadcastInput.View() + "\n\n")
+	for _, s := range targets {
+		sb.WriteString(pickerItemStyle.Render("◆ "+m.displayName(s)) + "\n")
+	}
+	sb.WriteString("\n")
+	if m.broadcastErr != "" {
+		sb.WriteString(styleOverlayError.Render(m.broadcastErr) + "\n")
+	}
+	sb.WriteString(styleOverlayHelp.Render(i18n.T("[ctrl+s] send to all  [esc] cancel")))
+	return sb.String()
+}
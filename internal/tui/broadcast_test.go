@@ -0,0 +1,65 @@
+package tui
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/shnupta/herd/internal/tmux/tmuxtest"
+)
+
+func TestBroadcastSendsToMarkedSessions(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	mock := m.tmuxClient.(*tmuxtest.MockClient)
+
+	m = pressKey(t, m, "V")
+	if m.mode != ModeNormal || len(mock.Messages) != 1 {
+		t.Fatalf("broadcast with nothing marked: mode = %v, messages = %v", m.mode, mock.Messages)
+	}
+
+	m = pressKey(t, m, "v")
+	m = pressKey(t, m, "j")
+	m = pressKey(t, m, "j")
+	m = pressKey(t, m, "v")
+	m = pressKey(t, m, "V")
+	if m.mode != ModeBroadcast {
+		t.Fatalf("mode = %v, want ModeBroadcast", m.mode)
+	}
+	m.broadcastInput.SetValue("rename Foo to Bar")
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	m = updated.(Model)
+
+	want := []string{"%1:rename Foo to Bar", "%3:rename Foo to Bar"}
+	if !reflect.DeepEqual(mock.SendKeysCalls, want) {
+		t.Errorf("sent %v, want %v", mock.SendKeysCalls, want)
+	}
+	if m.mode != ModeNormal {
+		t.Errorf("mode = %v after sending, want ModeNormal", m.mode)
+	}
+	if len(m.markedSessions()) != 2 {
+		t.Error("marks should stay for the next broadcast")
+	}
+}
+
+func TestBroadcastFailureKeepsUnsentMarked(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	mock := m.tmuxClient.(*tmuxtest.MockClient)
+	mock.SendKeysErr = errors.New("no such pane")
+	m.marked["session:sess-aaa"] = true
+
+	m = pressKey(t, m, "V")
+	m.broadcastInput.SetValue("hello")
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	m = updated.(Model)
+
+	if m.mode != ModeBroadcast || m.broadcastErr == "" {
+		t.Errorf("mode = %v, err = %q; want the overlay open with an error", m.mode, m.broadcastErr)
+	}
+	if !m.marked["session:sess-aaa"] {
+		t.Error("a session not reached should stay marked")
+	}
+}
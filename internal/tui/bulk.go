@@ -0,0 +1,226 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/shnupta/herd/internal/domain"
+	"github.com/shnupta/herd/internal/groups"
+	"github.com/shnupta/herd/internal/i18n"
+	"github.com/shnupta/herd/internal/names"
+)
+
+// bulkEditorDoneMsg carries the buffer back from an external $EDITOR run.
+type bulkEditorDoneMsg struct {
+	text string
+	err  error
+}
+
+// openBulkEdit fills the bulk-edit textarea with one row per session and
+// switches to ModeBulkEdit.
+func (m Model) openBulkEdit() (Model, tea.Cmd) {
+	entries := make([]domain.BulkEntry, 0, len(m.sessions))
+	for _, s := range m.sessions {
+		_, pinned := m.pinned[s.Key()]
+		entries = append(entries, domain.BulkEntry{
+			Key:     s.Key(),
+			Project: shortenPath(s.ProjectPath),
+			Name:    names.Get(s.Key()),
+			Group:   groups.Get(s.Key()),
+			Pinned:  pinned,
+		})
+	}
+
+	ta := textarea.New()
+	ta.ShowLineNumbers = false
+	ta.CharLimit = 0
+	ta.SetWidth(maxInt(20, m.width-4))
+	ta.SetHeight(maxInt(3, m.height-8))
+	ta.SetValue(domain.FormatBulk(entries))
+	ta.Focus()
+
+	m.bulkInput = ta
+	m.bulkErr = ""
+	m.mode = ModeBulkEdit
+	return m, textarea.Blink
+}
+
+func (m Model) updateBulkEditMode(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m = m.recalcLayout()
+		m.bulkInput.SetWidth(maxInt(20, m.width-4))
+		m.bulkInput.SetHeight(maxInt(3, m.height-8))
+		return m, nil
+
+	case bulkEditorDoneMsg:
+		if msg.err != nil {
+			m.bulkErr = "editor: " + msg.err.Error()
+		} else {
+			m.bulkInput.SetValue(msg.text)
+			m.bulkErr = ""
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			m.mode = ModeNormal
+			m.bulkErr = ""
+			return m, nil
+		case "ctrl+s":
+			entries, err := domain.ParseBulk(m.bulkInput.Value())
+			if err != nil {
+				m.bulkErr = err.Error()
+				return m, nil
+			}
+			m.applyBulkEdit(entries)
+			m.mode = ModeNormal
+			m.bulkErr = ""
+			return m, nil
+		case "ctrl+e":
+			return m, openBulkEditor(m.bulkInput.Value())
+		}
+	}
+
+	var cmd tea.Cmd
+	m.bulkInput, cmd = m.bulkInput.Update(msg)
+	return m, cmd
+}
+
+// applyBulkEdit writes names and groups for every live session listed in
+// entries, then reconciles pins. Grouped sessions are pinned as a unit, so a
+// group is pinned when any of its listed members is marked "pin".
+func (m *Model) applyBulkEdit(entries []domain.BulkEntry) {
+	live := make(map[string]bool, len(m.sessions))
+	for _, s := range m.sessions {
+		live[s.Key()] = true
+	}
+
+	byKey := make(map[string]domain.BulkEntry, len(entries))
+	for _, e := range entries {
+		if !live[e.Key] {
+			continue // session vanished while editing, or a mistyped key
+		}
+		byKey[e.Key] = e
+		if names.Get(e.Key) != e.Name {
+			_ = names.Set(e.Key, e.Name)
+		}
+		if groups.Get(e.Key) != e.Group {
+			_ = groups.Set(e.Key, e.Group)
+		}
+	}
+
+	groupPinned := make(map[string]bool)
+	for _, s := range m.sessions {
+		if e, ok := byKey[s.Key()]; ok && e.Pinned {
+			if gKey, _ := m.groupKeyAndName(s); gKey != "" {
+				groupPinned[gKey] = true
+			}
+		}
+	}
+
+	for _, s := range m.sessions {
+		e, listed := byKey[s.Key()]
+		gKey, _ := m.groupKeyAndName(s)
+		if !listed && gKey == "" {
+			continue
+		}
+		want := e.Pinned
+		if gKey != "" {
+			want = groupPinned[gKey]
+		}
+		_, isPinned := m.pinned[s.Key()]
+		switch {
+		case want && !isPinned:
+			m.pinCounter++
+			m.pinned[s.Key()] = m.pinCounter
+		case !want && isPinned:
+			delete(m.pinned, s.Key())
+		}
+	}
+
+	m.sortSessions()
+	m.saveSidebarState()
+	m.itemsDirty = true
+}
+
+// openBulkEditor writes text to a temp file and suspends the TUI to edit it in
+// $VISUAL / $EDITOR (falling back to vi). The edited buffer is delivered as a
+// bulkEditorDoneMsg so it can be reviewed in the overlay before applying.
+func openBulkEditor(text string) tea.Cmd {
+	f, err := os.CreateTemp("", "herd-bulk-*.txt")
+	if err != nil {
+		return func() tea.Msg { return bulkEditorDoneMsg{err: err} }
+	}
+	path := f.Name()
+	_, err = f.WriteString(text)
+	f.Close()
+	if err != nil {
+		os.Remove(path)
+		return func() tea.Msg { return bulkEditorDoneMsg{err: err} }
+	}
+
+	return tea.ExecProcess(editorCommand(path), func(err error) tea.Msg {
+		defer os.Remove(path)
+		if err != nil {
+			return bulkEditorDoneMsg{err: err}
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return bulkEditorDoneMsg{err: err}
+		}
+		return bulkEditorDoneMsg{text: string(data)}
+	})
+}
+
+// editorCommand builds the command that opens path in the user's editor.
+// $VISUAL and $EDITOR may carry arguments (e.g. "code --wait").
+func editorCommand(path string) *exec.Cmd {
+	return editorCommandAt(path, 0)
+}
+
+// editorCommandAt is editorCommand with the cursor put on line, when line
+// is positive, in the way the editor understands.
+func editorCommandAt(path string, line int) *exec.Cmd {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	fields := strings.Fields(editor)
+	if len(fields) == 0 {
+		fields = []string{"vi"}
+	}
+	args := fields[1:]
+	at := fmt.Sprintf("%s:%d", path, line)
+	switch base := filepath.Base(fields[0]); {
+	case line <= 0:
+		args = append(args, path)
+	case base == "code" || base == "code-insiders" || base == "codium" || base == "cursor":
+		args = append(args, "--goto", at)
+	case base == "subl" || base == "zed" || base == "hx" || base == "helix":
+		args = append(args, at)
+	default: // vi, vim, nvim, nano, emacs, micro, kak...
+		args = append(args, fmt.Sprintf("+%d", line), path)
+	}
+	return exec.Command(fields[0], args...)
+}
+
+func (m Model) renderBulkEditOverlay() string {
+	var sb strings.Builder
+	sb.WriteString(styleOverlayTitle.Width(m.width).Render(i18n.T("Bulk Edit Sessions")) + "\n\n")
+	sb.WriteString(m.bulkInput.View() + "\n\n")
+	if m.bulkErr != "" {
+		sb.WriteString(styleOverlayError.Render(m.bulkErr) + "\n")
+	}
+	sb.WriteString(styleOverlayHelp.Render(i18n.T("[ctrl+s] apply  [ctrl+e] open in $EDITOR  [esc] cancel")))
+	return sb.String()
+}
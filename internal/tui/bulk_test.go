@@ -0,0 +1,97 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/shnupta/herd/internal/domain"
+)
+
+func TestBulkEditOpenAndCancel(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'E'}})
+	m = updated.(Model)
+	if m.mode != ModeBulkEdit {
+		t.Fatalf("expected ModeBulkEdit after E, got %d", m.mode)
+	}
+	for _, s := range testSessions() {
+		if !strings.Contains(m.bulkInput.Value(), s.Key()) {
+			t.Errorf("bulk buffer missing row for %s", s.Key())
+		}
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	m = updated.(Model)
+	if m.mode != ModeNormal {
+		t.Errorf("expected ModeNormal after esc, got %d", m.mode)
+	}
+}
+
+func TestBulkEditParseErrorStaysOpen(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+
+	m, _ = m.openBulkEdit()
+	m.bulkInput.SetValue("session:sess-aaa | only three | columns")
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	m = updated.(Model)
+
+	if m.mode != ModeBulkEdit {
+		t.Errorf("expected to stay in ModeBulkEdit on parse error, got %d", m.mode)
+	}
+	if m.bulkErr == "" {
+		t.Error("expected a parse error to be shown")
+	}
+}
+
+func TestApplyBulkEditPins(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	m.pinned["session:sess-aaa"] = 1
+	m.pinCounter = 1
+
+	m.applyBulkEdit([]domain.BulkEntry{
+		{Key: "session:sess-aaa", Pinned: false},
+		{Key: "session:sess-ccc", Pinned: true},
+		{Key: "session:gone", Pinned: true},
+	})
+
+	if _, ok := m.pinned["session:sess-aaa"]; ok {
+		t.Error("expected sess-aaa to be unpinned")
+	}
+	if _, ok := m.pinned["session:sess-ccc"]; !ok {
+		t.Error("expected sess-ccc to be pinned")
+	}
+	if _, ok := m.pinned["session:gone"]; ok {
+		t.Error("expected unknown keys to be ignored")
+	}
+	if m.sessions[0].Key() != "session:sess-ccc" {
+		t.Errorf("expected pinned session sorted first, got %s", m.sessions[0].Key())
+	}
+}
+
+func TestEditorCommandAtLine(t *testing.T) {
+	for _, tc := range []struct {
+		editor string
+		want   string
+	}{
+		{"", "vi +12 /p/main.go"},
+		{"nvim", "nvim +12 /p/main.go"},
+		{"code --wait", "code --wait --goto /p/main.go:12"},
+		{"/usr/local/bin/hx", "/usr/local/bin/hx /p/main.go:12"},
+	} {
+		t.Setenv("VISUAL", "")
+		t.Setenv("EDITOR", tc.editor)
+		if got := strings.Join(editorCommandAt("/p/main.go", 12).Args, " "); got != tc.want {
+			t.Errorf("EDITOR=%q: got %q, want %q", tc.editor, got, tc.want)
+		}
+	}
+	t.Setenv("EDITOR", "code --wait")
+	if got := strings.Join(editorCommand("/p/main.go").Args, " "); got != "code --wait /p/main.go" {
+		t.Errorf("without a line: got %q", got)
+	}
+}
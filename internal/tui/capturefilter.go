@@ -0,0 +1,69 @@
+package tui
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// filterRes caches the capture filters compiled so far, by pattern; a
+// pattern that doesn't compile maps to nil and filters nothing.
+var (
+	filterMu  sync.Mutex
+	filterRes = make(map[string]*regexp.Regexp)
+)
+
+// compileFilters returns the compiled regexps of patterns.
+func compileFilters(patterns []string) []*regexp.Regexp {
+	filterMu.Lock()
+	defer filterMu.Unlock()
+	out := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		re, ok := filterRes[p]
+		if !ok {
+			re, _ = regexp.Compile(p)
+			filterRes[p] = re
+		}
+		if re != nil {
+			out = append(out, re)
+		}
+	}
+	return out
+}
+
+// filterCapture drops the lines of content matching any of res.
+func filterCapture(content string, res []*regexp.Regexp) string {
+	if len(res) == 0 {
+		return content
+	}
+	lines := strings.Split(content, "\n")
+	kept := lines[:0]
+	for _, l := range lines {
+		if !matchesAny(l, res) {
+			kept = append(kept, l)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+func matchesAny(line string, res []*regexp.Regexp) bool {
+	for _, re := range res {
+		if re.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// displayCapture is what herd shows of content captured from paneID: the
+// lines its session's capture filters match dropped and trailing blank
+// lines trimmed.
+func (m Model) displayCapture(paneID, content string) string {
+	for _, s := range m.sessions {
+		if s.TmuxPane == paneID {
+			content = filterCapture(content, compileFilters(m.captureFor(s).Filters))
+			break
+		}
+	}
+	return cleanCapture(content)
+}
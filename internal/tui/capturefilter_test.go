@@ -0,0 +1,35 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shnupta/herd/internal/config"
+)
+
+func TestCaptureFiltersDropNoisyLines(t *testing.T) {
+	m, _ := newTestModel(t, testSessions())
+	m.capture = []config.Capture{
+		{Project: "/home/user/project-alpha", Filters: []string{`^\s*[⠋⠙⠹⠸]`, `\d+% \|`}},
+		{Filters: []string{`[`}}, // doesn't compile, so ignored
+	}
+	sel := m.selectedSession()
+	if sel == nil || sel.TmuxPane != "%1" {
+		t.Fatalf("selected %+v, want alpha", sel)
+	}
+
+	content := "● Bash(npm install)\n⠋ resolving\n 42% |████      |\n  ⎿  added 12 packages\n"
+	updated, _ := m.Update(captureMsg{paneID: "%1", content: content})
+	m = updated.(Model)
+	got := m.viewport.View()
+	if strings.Contains(got, "resolving") || strings.Contains(got, "42%") {
+		t.Errorf("filtered lines still shown:\n%s", got)
+	}
+	if !strings.Contains(got, "npm install") || !strings.Contains(got, "added 12 packages") {
+		t.Errorf("other lines should stay:\n%s", got)
+	}
+
+	if got := m.displayCapture("%2", content); !strings.Contains(got, "resolving") {
+		t.Errorf("beta has no filters, but got:\n%s", got)
+	}
+}
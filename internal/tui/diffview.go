@@ -0,0 +1,93 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/shnupta/herd/internal/config"
+	"github.com/shnupta/herd/internal/diff"
+	"github.com/shnupta/herd/internal/review"
+)
+
+// ErrNoChanges is returned by NewDiffViewer for a repository with nothing
+// to show.
+var ErrNoChanges = errors.New("no uncommitted changes")
+
+// DiffViewer is review mode's diff viewer run on its own, for `herd diff`:
+// the same navigation, blame, refresh and editor jumps, without comments or
+// a session to send them to.
+type DiffViewer struct {
+	review ReviewModel
+}
+
+// viewerSkips are the review keys that only make sense with a session.
+var viewerSkips = []key.Binding{
+	reviewKeys.Comment, reviewKeys.Reply, reviewKeys.Delete, reviewKeys.Submit,
+	reviewKeys.SubmitTo, reviewKeys.WriteTodo, reviewKeys.Approve, reviewKeys.Pause,
+}
+
+// NewDiffViewer reads the uncommitted changes of the repository dir is in,
+// filtered and loaded as review mode would.
+func NewDiffViewer(dir string) (DiffViewer, error) {
+	root, err := diff.GetGitRoot(dir)
+	if err != nil {
+		return DiffViewer{}, fmt.Errorf("%s is not in a git repository", dir)
+	}
+	cfg := config.Load()
+	opts := diff.Options{MaxLineBytes: cfg.ReviewMaxLineBytes, LazyFileLines: max(0, cfg.ReviewLazyFileLines)}
+	parsed, err := diff.GitDiff(root, opts)
+	if err != nil {
+		return DiffViewer{}, err
+	}
+	ignored := parsed.Ignore(cfg.ReviewIgnoreFor(root))
+	if parsed.IsEmpty() {
+		return DiffViewer{}, ErrNoChanges
+	}
+
+	// A fresh review rather than NewReviewModel's, which resumes the one
+	// saved for a session.
+	rm := ReviewModel{
+		diff:        parsed,
+		review:      review.NewReview("", root),
+		projectPath: root,
+		viewOnly:    true,
+		ignored:     ignored,
+		ignore:      cfg.ReviewIgnoreFor(root),
+		editorURL:   cfg.EditorURL,
+		diffOpts:    opts,
+	}
+	rm.buildFlatLines()
+	return DiffViewer{review: rm}, nil
+}
+
+func (v DiffViewer) Init() tea.Cmd {
+	return nil
+}
+
+func (v DiffViewer) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		if msg.String() == "ctrl+c" {
+			return v, tea.Quit
+		}
+		if !v.review.treeFocus {
+			for _, b := range viewerSkips {
+				if key.Matches(msg, b) {
+					return v, nil
+				}
+			}
+		}
+	}
+	updated, cmd := v.review.Update(msg)
+	v.review = updated.(ReviewModel)
+	if v.review.cancelled {
+		return v, tea.Quit
+	}
+	return v, cmd
+}
+
+func (v DiffViewer) View() string {
+	return v.review.View()
+}
@@ -0,0 +1,69 @@
+package tui
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestDiffViewerBrowsesWithoutASession(t *testing.T) {
+	t.Setenv("HOME", t.TempDir()) // default config, and nowhere to save a review
+	repo := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		if out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	run("init")
+	run("config", "user.email", "test@test.com")
+	run("config", "user.name", "Test")
+	if err := os.MkdirAll(filepath.Join(repo, "pkg"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repo, "pkg", "a.go"), []byte("one\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	run("add", ".")
+	run("commit", "-m", "first")
+
+	if _, err := NewDiffViewer(repo); !errors.Is(err, ErrNoChanges) {
+		t.Errorf("clean repo: err = %v, want ErrNoChanges", err)
+	}
+	if err := os.WriteFile(filepath.Join(repo, "pkg", "a.go"), []byte("two\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Any directory of the repository will do.
+	v, err := NewDiffViewer(filepath.Join(repo, "pkg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m tea.Model = v
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	view := m.View()
+	if !strings.Contains(view, "pkg/a.go") || !strings.Contains(view, "+two") {
+		t.Errorf("view should show the change:\n%s", view)
+	}
+	if strings.Contains(view, "comments") || strings.Contains(view, "[s/S] submit") {
+		t.Errorf("view should leave out comments and submitting:\n%s", view)
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	if m.(DiffViewer).review.commenting {
+		t.Error("c should not start a comment")
+	}
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	if cmd == nil {
+		t.Fatal("q should quit")
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Error("q should quit the program")
+	}
+}
@@ -0,0 +1,36 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/shnupta/herd/internal/config"
+	"github.com/shnupta/herd/internal/session"
+)
+
+// duplicateSession returns the live session already running in projectPath,
+// which launching another there would duplicate, or nil if there is none or
+// the project is configured for several (multi_session).
+func (m Model) duplicateSession(projectPath string) *session.Session {
+	for i := range m.sessions {
+		if m.sessions[i].ProjectPath == projectPath {
+			if config.Load().MultiSessionFor(projectPath) {
+				return nil
+			}
+			return &m.sessions[i]
+		}
+	}
+	return nil
+}
+
+// switchToSession closes any overlay and selects the session in pane.
+func (m Model) switchToSession(pane string) (Model, tea.Cmd) {
+	m.mode = ModeNormal
+	for i, s := range m.sessions {
+		if s.TmuxPane == pane {
+			m.selected = i
+		}
+	}
+	m.lastCapture = ""
+	m.pendingGotoBottom = true
+	return m, tea.Batch(tickCapture(), tickSessionRefresh(), m.fetchCapture(pane))
+}
@@ -0,0 +1,109 @@
+package tui
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// echoTimeout is how long local echo shows text no capture has confirmed,
+// as text that wraps or that Claude reformats may never be found.
+const echoTimeout = 5 * time.Second
+
+// echoLines is how many lines at the bottom of a capture are searched for
+// text still being typed: those of Claude's input box.
+const echoLines = 10
+
+// localEcho is text sent to a pane that its output doesn't show yet.
+type localEcho struct {
+	text string
+	at   time.Time
+	sent bool // a whole prompt, found anywhere in the output; else still being typed
+}
+
+var styleEcho = lipgloss.NewStyle().Foreground(colSubtext).Italic(true)
+
+// echoKey adds a key typed into panes to what they echo: typed text is
+// added and backspace takes a character off, while enter and keys that
+// move the cursor leave nothing to predict.
+func (m Model) echoKey(panes []string, msg tea.KeyMsg) {
+	if !m.localEcho {
+		return
+	}
+	for _, pane := range panes {
+		e := m.echoes[pane]
+		if e.sent {
+			e = localEcho{}
+		}
+		switch {
+		case msg.Type == tea.KeyRunes:
+			e.text += strings.ReplaceAll(string(msg.Runes), "\n", " ")
+		case msg.Type == tea.KeyBackspace && e.text != "":
+			r := []rune(e.text)
+			e.text = string(r[:len(r)-1])
+		default:
+			e.text = ""
+		}
+		if e.text == "" {
+			delete(m.echoes, pane)
+			continue
+		}
+		e.at = time.Now()
+		m.echoes[pane] = e
+	}
+}
+
+// echoSent echoes a prompt sent to pane whole.
+func (m Model) echoSent(pane, text string) {
+	if m.localEcho {
+		m.echoes[pane] = localEcho{text: strings.Join(strings.Fields(text), " "), at: time.Now(), sent: true}
+	}
+}
+
+// confirmEcho drops pane's echo once content, its latest capture, shows
+// the text: typed text at the end of a line of the input box, a sent prompt
+// anywhere.
+func (m Model) confirmEcho(pane, content string) {
+	e, ok := m.echoes[pane]
+	if !ok {
+		return
+	}
+	lines := strings.Split(strings.TrimRight(ansi.Strip(content), "\n"), "\n")
+	if e.sent {
+		if strings.Contains(strings.Join(strings.Fields(strings.Join(lines, " ")), " "), e.text) {
+			delete(m.echoes, pane)
+		}
+		return
+	}
+	for _, l := range lines[max(0, len(lines)-echoLines):] {
+		if strings.HasSuffix(strings.TrimRight(l, " │"), strings.TrimRight(e.text, " ")) {
+			delete(m.echoes, pane)
+			return
+		}
+	}
+}
+
+// expireEchoes drops echoes no capture has confirmed in echoTimeout.
+func (m Model) expireEchoes(now time.Time) {
+	for pane, e := range m.echoes {
+		if now.Sub(e.at) >= echoTimeout {
+			delete(m.echoes, pane)
+		}
+	}
+}
+
+// withEcho shows pane's echo on the last line of view, a viewport of
+// output, styled as pending.
+func (m Model) withEcho(pane, view string, width int) string {
+	e, ok := m.echoes[pane]
+	if !ok {
+		return view
+	}
+	line := styleEcho.Render(ansi.Truncate("⋯ "+e.text, max(0, width), "…"))
+	lines := strings.Split(view, "\n")
+	lines[len(lines)-1] = line
+	return strings.Join(lines, "\n")
+}
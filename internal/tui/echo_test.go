@@ -0,0 +1,63 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestLocalEchoUntilCaptured(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	m.localEcho = true
+	pane := m.selectedSession().TmuxPane
+
+	m = pressKey(t, m, "i")
+	for _, k := range []string{"f", "i", "x"} {
+		m = pressKey(t, m, k)
+	}
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	m = pressKey(t, updated.(Model), "x")
+	if got := ansi.Strip(m.withEcho(pane, "a\nb", 40)); got != "a\n⋯ fix" {
+		t.Fatalf("echoed view = %q, want the pending text on the last line", got)
+	}
+
+	// A capture from before the keys landed leaves the echo up.
+	updated, _ = m.Update(captureMsg{paneID: pane, content: "> f\n"})
+	m = updated.(Model)
+	if _, ok := m.echoes[pane]; !ok {
+		t.Fatal("echo dropped by a capture without the text")
+	}
+	updated, _ = m.Update(captureMsg{paneID: pane, content: "done\n│ > fix      │\n  ? for shortcuts\n"})
+	m = updated.(Model)
+	if _, ok := m.echoes[pane]; ok {
+		t.Error("echo kept after a capture showed the text")
+	}
+}
+
+func TestLocalEchoOfSentPrompt(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	m.localEcho = true
+
+	m.echoSent("%2", "use the\nexisting helper")
+	m.confirmEcho("%2", "> use the existing\n  helper\n\n✻ Working\n")
+	if _, ok := m.echoes["%2"]; ok {
+		t.Error("sent prompt still echoed once shown, wrapped")
+	}
+
+	m.echoSent("%2", "never shown")
+	m.expireEchoes(time.Now().Add(echoTimeout))
+	if len(m.echoes) != 0 {
+		t.Errorf("echoes %v kept past the timeout", m.echoes)
+	}
+
+	m.localEcho = false
+	m.echoSent("%2", "off")
+	if view := m.withEcho("%2", "a", 40); strings.Contains(view, "off") {
+		t.Errorf("echo shown with local_echo off: %q", view)
+	}
+}
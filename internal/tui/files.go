@@ -0,0 +1,324 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/shnupta/herd/internal/git"
+	"github.com/shnupta/herd/internal/i18n"
+)
+
+// fileEditedMsg reports that the editor opened from the file browser
+// exited.
+type fileEditedMsg struct{ err error }
+
+func isFileEditedMsg(msg tea.Msg) bool { _, ok := msg.(fileEditedMsg); return ok }
+
+// fileNode is a file or directory in the browser. A directory's children
+// are read the first time it is opened.
+type fileNode struct {
+	name     string
+	rel      string // path relative to the browser's root
+	dir      bool
+	depth    int
+	open     bool
+	loaded   bool
+	children []*fileNode
+}
+
+// fileBrowser is a tree of a session's project, hiding what .gitignore
+// excludes and marking what changed since the last commit.
+type fileBrowser struct {
+	root     string
+	top      []*fileNode
+	rows     []*fileNode // the visible nodes, in display order
+	selected int
+	changes  map[string]string // rel path → "new" or "modified"; directories hold "new" or "modified" when anything below them does
+	compose  bool              // opened from the composer: enter attaches the file
+	err      string
+}
+
+// newFileBrowser reads the top level of root.
+func newFileBrowser(root string, compose bool) *fileBrowser {
+	b := &fileBrowser{root: root, compose: compose}
+	b.loadChanges()
+	top, err := b.readDir("", 0)
+	if err != nil {
+		b.err = err.Error()
+	}
+	b.top = top
+	b.flatten()
+	return b
+}
+
+// loadChanges marks the untracked and modified files and their parent
+// directories; outside a repository nothing is marked.
+func (b *fileBrowser) loadChanges() {
+	b.changes = make(map[string]string)
+	untracked, modified, err := git.Changes(b.root)
+	if err != nil {
+		return
+	}
+	mark := func(files []string, change string) {
+		for _, f := range files {
+			for p := f; p != "." && p != "/"; p = filepath.Dir(p) {
+				if b.changes[p] == "" || p == f {
+					b.changes[p] = change
+				}
+			}
+		}
+	}
+	mark(modified, "modified")
+	mark(untracked, "new")
+}
+
+// readDir lists the entries of the directory at rel, directories first,
+// leaving out .git and whatever .gitignore excludes.
+func (b *fileBrowser) readDir(rel string, depth int) ([]*fileNode, error) {
+	dir := filepath.Join(b.root, rel)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	ignored, err := git.Ignored(dir, names)
+	if err != nil {
+		ignored = nil // not a repository: show everything
+	}
+	var nodes []*fileNode
+	for _, e := range entries {
+		if e.Name() == ".git" || ignored[e.Name()] {
+			continue
+		}
+		isDir := e.IsDir()
+		if e.Type()&os.ModeSymlink != 0 {
+			if info, err := os.Stat(filepath.Join(dir, e.Name())); err == nil {
+				isDir = info.IsDir()
+			}
+		}
+		nodes = append(nodes, &fileNode{name: e.Name(), rel: filepath.Join(rel, e.Name()), dir: isDir, depth: depth})
+	}
+	sort.SliceStable(nodes, func(i, j int) bool { return nodes[i].dir && !nodes[j].dir })
+	return nodes, nil
+}
+
+// flatten lists the nodes of the open directories in display order.
+func (b *fileBrowser) flatten() {
+	b.rows = b.rows[:0]
+	var walk func([]*fileNode)
+	walk = func(nodes []*fileNode) {
+		for _, n := range nodes {
+			b.rows = append(b.rows, n)
+			if n.dir && n.open {
+				walk(n.children)
+			}
+		}
+	}
+	walk(b.top)
+	b.selected = minInt(b.selected, maxInt(0, len(b.rows)-1))
+}
+
+// toggle opens or closes the directory n, reading it the first time.
+func (b *fileBrowser) toggle(n *fileNode) {
+	if !n.loaded {
+		children, err := b.readDir(n.rel, n.depth+1)
+		if err != nil {
+			b.err = err.Error()
+			return
+		}
+		n.children, n.loaded = children, true
+	}
+	n.open = !n.open
+	b.flatten()
+}
+
+// current returns the selected node, or nil in an empty tree.
+func (b *fileBrowser) current() *fileNode {
+	if b.selected < len(b.rows) {
+		return b.rows[b.selected]
+	}
+	return nil
+}
+
+// parent returns the row index of n's directory, or -1 at the top level.
+func (b *fileBrowser) parent(n *fileNode) int {
+	dir := filepath.Dir(n.rel)
+	for i, r := range b.rows {
+		if r.rel == dir {
+			return i
+		}
+	}
+	return -1
+}
+
+// openFiles browses the selected session's project.
+func (m Model) openFiles() Model {
+	sel := m.selectedSession()
+	if sel == nil || sel.ProjectPath == "" {
+		return m
+	}
+	m.files = newFileBrowser(sel.ProjectPath, false)
+	m.mode = ModeFiles
+	return m
+}
+
+func (m Model) updateFilesMode(msg tea.Msg) (tea.Model, tea.Cmd) {
+	b := m.files
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m = m.recalcLayout()
+		return m, nil
+
+	case fileEditedMsg:
+		if msg.err != nil {
+			b.err = "editor: " + msg.err.Error()
+		}
+		b.loadChanges()
+		return m, nil
+
+	case tea.KeyMsg:
+		b.err = ""
+		n := b.current()
+		switch msg.String() {
+		case "esc", "q":
+			return m.closeFiles()
+		case "k", "up":
+			if b.selected > 0 {
+				b.selected--
+			}
+		case "j", "down":
+			if b.selected < len(b.rows)-1 {
+				b.selected++
+			}
+		case "h", "left":
+			if n == nil {
+				break
+			}
+			if n.dir && n.open {
+				b.toggle(n)
+			} else if i := b.parent(n); i >= 0 {
+				b.selected = i
+			}
+		case "l", "right":
+			if n != nil && n.dir && !n.open {
+				b.toggle(n)
+			}
+		case "enter":
+			switch {
+			case n == nil:
+			case n.dir:
+				b.toggle(n)
+			case b.compose:
+				return m.attachFile(n.rel)
+			default:
+				return m, m.editFile(n.rel)
+			}
+		case "e":
+			if n != nil && !n.dir {
+				return m, m.editFile(n.rel)
+			}
+		case "a":
+			if n != nil && !n.dir {
+				return m.attachFile(n.rel)
+			}
+		}
+	}
+	return m, nil
+}
+
+// closeFiles leaves the browser, back to the composer if it was opened
+// from there.
+func (m Model) closeFiles() (Model, tea.Cmd) {
+	compose := m.files.compose
+	m.files = nil
+	if compose {
+		m.mode = ModeCompose
+		return m, m.composeInput.Focus()
+	}
+	m.mode = ModeNormal
+	return m, nil
+}
+
+// attachFile attaches the file at rel to the composer, starting a prompt
+// for the selected session if the browser wasn't opened from one.
+func (m Model) attachFile(rel string) (Model, tea.Cmd) {
+	a, err := readAttachment(m.files.root, rel)
+	if err != nil {
+		m.files.err = err.Error()
+		return m, nil
+	}
+	var cmd tea.Cmd
+	if !m.files.compose {
+		m, cmd = m.openCompose()
+	} else {
+		m, cmd = m.closeFiles()
+	}
+	m.files = nil
+	m.composeAttached = append(m.composeAttached, a)
+	return m, cmd
+}
+
+// editFile suspends herd to open the file at rel in the user's editor.
+func (m Model) editFile(rel string) tea.Cmd {
+	return tea.ExecProcess(editorCommand(filepath.Join(m.files.root, rel)), func(err error) tea.Msg {
+		return fileEditedMsg{err: err}
+	})
+}
+
+func (m Model) renderFilesOverlay() string {
+	b := m.files
+	var sb strings.Builder
+	sb.WriteString(styleOverlayTitle.Width(m.width).Render(i18n.T("Files")+" — "+shortenPath(b.root)) + "\n\n")
+	if len(b.rows) == 0 {
+		sb.WriteString(pickerItemStyle.Render("No files") + "\n")
+	}
+
+	// Keep the selection inside a window of the tree that fits the screen.
+	rows := maxInt(1, m.height-5)
+	first := minInt(maxInt(0, b.selected-rows+1), maxInt(0, len(b.rows)-rows))
+	for i := first; i < minInt(first+rows, len(b.rows)); i++ {
+		n := b.rows[i]
+		icon := "  "
+		if n.dir {
+			icon = "▸ "
+			if n.open {
+				icon = "▾ "
+			}
+		}
+		line := strings.Repeat("  ", n.depth) + icon + n.name
+		if n.dir {
+			line += "/"
+		}
+		switch b.changes[n.rel] {
+		case "new":
+			line += lipgloss.NewStyle().Foreground(colGreen).Render("  +")
+		case "modified":
+			line += lipgloss.NewStyle().Foreground(colAmber).Render("  ~")
+		}
+		if i == b.selected {
+			sb.WriteString(pickerSelectedStyle.Width(m.width-4).Render(line) + "\n")
+		} else {
+			sb.WriteString(pickerItemStyle.Render(line) + "\n")
+		}
+	}
+	sb.WriteString("\n")
+	if b.err != "" {
+		sb.WriteString(styleOverlayError.Render(b.err) + "\n")
+	}
+	if b.compose {
+		sb.WriteString(styleOverlayHelp.Render(i18n.T("[j/k] move  [l/h] open/close  [enter] attach  [e] edit  [esc] back  + new  ~ modified")))
+	} else {
+		sb.WriteString(styleOverlayHelp.Render(i18n.T("[j/k] move  [l/h] open/close  [enter] edit  [a] attach to a prompt  [esc] close  + new  ~ modified")))
+	}
+	return sb.String()
+}
@@ -0,0 +1,88 @@
+package tui
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestFileBrowser(t *testing.T) {
+	repo := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		if out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, text string) {
+		t.Helper()
+		p := filepath.Join(repo, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(text), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run("init")
+	run("config", "user.email", "test@test.com")
+	run("config", "user.name", "Test")
+	write(".gitignore", "bin/\n")
+	write("main.go", "package main\n")
+	run("add", ".")
+	run("commit", "-m", "first")
+	write("bin/herd", "binary")
+	write("pkg/new.go", "package pkg\n")
+
+	sessions := testSessions()[:1]
+	sessions[0].ProjectPath = repo
+	m, fw := newTestModel(t, sessions)
+	defer fw.Close()
+
+	m = pressKey(t, m, "F")
+	if m.mode != ModeFiles || m.files == nil {
+		t.Fatalf("mode = %v, want ModeFiles", m.mode)
+	}
+	var names []string
+	for _, n := range m.files.rows {
+		names = append(names, n.rel)
+	}
+	if got := strings.Join(names, " "); got != "pkg .gitignore main.go" {
+		t.Errorf("top level = %q, want directories first and bin/ ignored", got)
+	}
+	if m.files.changes["pkg"] != "new" || m.files.changes["main.go"] != "" {
+		t.Errorf("changes = %v, want pkg marked new", m.files.changes)
+	}
+
+	m = pressKey(t, m, "l")
+	if len(m.files.rows) != 4 || m.files.rows[1].rel != filepath.Join("pkg", "new.go") {
+		t.Fatalf("rows after opening pkg = %d, want pkg/new.go beneath it", len(m.files.rows))
+	}
+	m = pressKey(t, m, "j")
+	m = pressKey(t, m, "h") // to the parent
+	if m.files.selected != 0 {
+		t.Errorf("selected = %d, want pkg", m.files.selected)
+	}
+	m = pressKey(t, m, "j")
+	m = pressKey(t, m, "a")
+	if m.mode != ModeCompose || len(m.composeAttached) != 1 || m.composeAttached[0].label != filepath.Join("pkg", "new.go") {
+		t.Fatalf("mode = %v, attached = %+v; want a prompt with pkg/new.go", m.mode, m.composeAttached)
+	}
+
+	// From the composer, enter attaches and returns to it.
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlB})
+	m = updated.(Model)
+	if m.mode != ModeFiles || !m.files.compose {
+		t.Fatalf("mode = %v, want the browser over the composer", m.mode)
+	}
+	m = pressKey(t, m, "down")
+	m = pressKey(t, m, "down")
+	m = pressKey(t, m, "enter")
+	if m.mode != ModeCompose || len(m.composeAttached) != 2 || m.composeAttached[1].label != "main.go" {
+		t.Errorf("mode = %v, attached = %+v; want main.go attached too", m.mode, m.composeAttached)
+	}
+}
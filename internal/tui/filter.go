@@ -0,0 +1,69 @@
+package tui
+
+import (
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/shnupta/herd/internal/i18n"
+)
+
+// filterBar is the sidebar's filter input. It owns the text being typed;
+// the root filters the session list by Query after each message and leaves
+// filter mode once the bar is Closed.
+type filterBar struct {
+	input  textinput.Model
+	closed bool
+}
+
+func newFilterBar() filterBar {
+	in := textinput.New()
+	in.Placeholder = i18n.T("filter...")
+	in.CharLimit = 100
+	return filterBar{input: in}
+}
+
+// Open focuses the bar for typing, keeping the current query.
+func (f filterBar) Open() filterBar {
+	f.input.Focus()
+	f.closed = false
+	return f
+}
+
+// SetQuery replaces the query, as a preset or a state filter does.
+func (f filterBar) SetQuery(q string) filterBar {
+	f.input.SetValue(q)
+	return f
+}
+
+func (f filterBar) Query() string { return f.input.Value() }
+
+// Closed reports whether the last message closed the bar: enter keeps the
+// query, esc clears it, as does backspace with nothing left to delete.
+func (f filterBar) Closed() bool { return f.closed }
+
+func (f filterBar) Update(msg tea.Msg) (filterBar, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "esc":
+			f.input.Reset()
+			f.closed = true
+			return f, nil
+		case "enter":
+			f.input.Blur()
+			f.closed = true
+			return f, nil
+		case "backspace":
+			if f.input.Value() == "" {
+				f.closed = true
+				return f, nil
+			}
+		}
+	}
+	var cmd tea.Cmd
+	f.input, cmd = f.input.Update(msg)
+	return f, cmd
+}
+
+func (f filterBar) View() string {
+	return styleFilter.Render("/" + f.input.Value() + "▎")
+}
@@ -0,0 +1,106 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/shnupta/herd/internal/i18n"
+)
+
+// focusTimer is a countdown attached to a session.
+type focusTimer struct {
+	length time.Duration
+	ends   time.Time
+}
+
+// openFocusTimer asks how long to focus on the selected session, offering
+// the running timer's length or focus_minutes.
+func (m Model) openFocusTimer() (Model, tea.Cmd) {
+	sel := m.selectedSession()
+	if sel == nil {
+		return m, nil
+	}
+	key := sel.Key()
+	minutes := m.focusMinutes
+	if t, ok := m.focusTimers[key]; ok {
+		minutes = int(t.length / time.Minute)
+	}
+	in := textinput.New()
+	in.Placeholder = "25"
+	in.CharLimit = 4
+	in.SetValue(strconv.Itoa(minutes))
+	in.CursorEnd()
+	return m.openPrompt(ModeFocusTimer, promptOverlay{
+		title:  i18n.T("Focus Timer") + " — " + m.displayName(*sel),
+		input:  in,
+		suffix: " minutes",
+		help:   i18n.T("[enter] start  [esc] cancel  (0 or empty stops the timer)"),
+		parse: func(text string) (tea.Msg, error) {
+			minutes, err := strconv.Atoi(text)
+			if text != "" && (err != nil || minutes < 0) {
+				return nil, fmt.Errorf("focus timer: %q is not a number of minutes", text)
+			}
+			return focusSetMsg{key: key, minutes: minutes}, nil
+		},
+	})
+}
+
+// setFocusTimer starts a timer of minutes on the session with key, or
+// stops its timer for 0.
+func (m Model) setFocusTimer(key string, minutes int) {
+	if minutes == 0 {
+		delete(m.focusTimers, key)
+		return
+	}
+	length := time.Duration(minutes) * time.Minute
+	m.focusTimers[key] = focusTimer{length: length, ends: time.Now().Add(length)}
+}
+
+// expireFocusTimers drops the timers that have run out by now and returns
+// a Cmd announcing them: a tmux message, the terminal bell and a desktop
+// notification.
+func (m Model) expireFocusTimers(now time.Time) (Model, tea.Cmd) {
+	var done []string
+	for _, s := range m.sessions {
+		if t, ok := m.focusTimers[s.Key()]; ok && !now.Before(t.ends) {
+			delete(m.focusTimers, s.Key())
+			done = append(done, fmt.Sprintf("%s (%d min)", m.displayName(s), int(t.length/time.Minute)))
+		}
+	}
+	if len(done) == 0 {
+		return m, nil
+	}
+	client := m.tmuxClient
+	text := "Focus time is up: " + strings.Join(done, ", ")
+	style := m.urgentAlert
+	return m, func() tea.Msg {
+		_ = client.DisplayMessage("herd: " + text)
+		_, _ = io.WriteString(alertOut, desktopNotification(style, "herd", text)+"\a")
+		return nil
+	}
+}
+
+// focusRemaining renders the time left on a session's focus timer for the
+// output header, or "" without one.
+func (m Model) focusRemaining(key string, now time.Time) string {
+	t, ok := m.focusTimers[key]
+	if !ok {
+		return ""
+	}
+	left := t.ends.Sub(now).Round(time.Second)
+	if left < 0 {
+		left = 0
+	}
+	col := colAmber
+	if left < time.Minute {
+		col = colRed
+	}
+	return lipgloss.NewStyle().Foreground(col).Render(fmt.Sprintf("⏱ %d:%02d", int(left/time.Minute), int(left%time.Minute/time.Second)))
+}
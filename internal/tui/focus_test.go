@@ -0,0 +1,73 @@
+package tui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/shnupta/herd/internal/tmux/tmuxtest"
+)
+
+func TestFocusTimer(t *testing.T) {
+	var out bytes.Buffer
+	orig := alertOut
+	alertOut = &out
+	t.Cleanup(func() { alertOut = orig })
+
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	mock := m.tmuxClient.(*tmuxtest.MockClient)
+	m.focusMinutes = 25
+
+	m = pressKey(t, m, "f")
+	if m.mode != ModeFocusTimer || promptOf(t, m).input.Value() != "25" {
+		t.Fatalf("mode = %v, input = %q; want the prompt offering 25", m.mode, promptOf(t, m).input.Value())
+	}
+	m = setPrompt(t, m, "10")
+	m = pressKey(t, m, "enter")
+	timer, ok := m.focusTimers[m.sessions[0].Key()]
+	if m.mode != ModeNormal || !ok || timer.length != 10*time.Minute {
+		t.Fatalf("mode = %v, timers = %v; want a 10-minute timer on alpha", m.mode, m.focusTimers)
+	}
+	if header := ansi.Strip(m.renderOutputHeader()); !strings.Contains(header, "⏱ 9:5") && !strings.Contains(header, "⏱ 10:00") {
+		t.Errorf("header should count down from 10:00: %q", header)
+	}
+
+	m, cmd := m.expireFocusTimers(timer.ends.Add(-time.Second))
+	if cmd != nil {
+		t.Fatal("timer expired early")
+	}
+	m, cmd = m.expireFocusTimers(timer.ends)
+	if cmd == nil {
+		t.Fatal("timer did not expire")
+	}
+	cmd()
+	if len(m.focusTimers) != 0 {
+		t.Errorf("expired timer kept: %v", m.focusTimers)
+	}
+	if len(mock.Messages) != 1 || !strings.Contains(mock.Messages[0], "project-alpha (10 min)") {
+		t.Errorf("tmux messages = %q", mock.Messages)
+	}
+	if !strings.HasSuffix(out.String(), "\a") || !strings.Contains(out.String(), "777;notify") {
+		t.Errorf("alert = %q, want a desktop notification and bell", out.String())
+	}
+
+	// 0 stops a running timer; junk is refused.
+	m.focusTimers[m.sessions[0].Key()] = focusTimer{length: time.Minute, ends: time.Now().Add(time.Minute)}
+	m = pressKey(t, m, "f")
+	m = setPrompt(t, m, "soon")
+	m = pressKey(t, m, "enter")
+	if m.mode != ModeFocusTimer || !strings.Contains(m.View(), "not a number of minutes") {
+		t.Errorf("mode = %v; want the prompt kept with an error:\n%s", m.mode, m.View())
+	}
+	m = setPrompt(t, m, "0")
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if len(m.focusTimers) != 0 || m.err != nil {
+		t.Errorf("timers = %v, err = %v; want the timer stopped", m.focusTimers, m.err)
+	}
+}
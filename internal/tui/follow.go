@@ -0,0 +1,53 @@
+package tui
+
+// selectionAnchor remembers which session is selected across a refresh,
+// which can reorder the sessions and change their keys.
+type selectionAnchor struct {
+	key, pane string
+}
+
+// anchorSelection returns the anchor of the selected session; the zero
+// anchor when none is.
+func (m *Model) anchorSelection() selectionAnchor {
+	sel := m.selectedSession()
+	if sel == nil {
+		return selectionAnchor{}
+	}
+	return selectionAnchor{key: sel.Key(), pane: sel.TmuxPane}
+}
+
+// followSelection selects the session a names again after a refresh: the
+// one with its key, the key migrations carried it to (the session ID was
+// learned, or Claude came back in a new pane), or failing those its pane.
+// The scroll position and insert mode stay with it; when it is gone they
+// are reset rather than carried over to whichever session takes its place.
+func (m *Model) followSelection(a selectionAnchor, migrations map[string]string) {
+	if a == (selectionAnchor{}) {
+		m.selected = minInt(m.selected, maxInt(0, len(m.sessions)-1))
+		return
+	}
+	keys := []string{a.key}
+	if k, ok := migrations[a.key]; ok {
+		keys = append(keys, k)
+	}
+	for _, key := range keys {
+		for i, s := range m.sessions {
+			if s.Key() == key {
+				m.selected = i
+				return
+			}
+		}
+	}
+	for i, s := range m.sessions {
+		if a.pane != "" && s.TmuxPane == a.pane {
+			m.selected = i
+			return
+		}
+	}
+
+	m.selected = minInt(m.selected, maxInt(0, len(m.sessions)-1))
+	m.insertMode = false
+	m.lastCapture = ""
+	m.forceViewportRefresh = true
+	m.pendingGotoBottom = true
+}
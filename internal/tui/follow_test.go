@@ -0,0 +1,46 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/shnupta/herd/internal/session"
+)
+
+func TestSelectionFollowsReplacedPane(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	m.migrateKeys() // record the initial keys
+	m.selected = 0
+	m.insertMode = true
+
+	// Claude comes back in a new pane for alpha, listed last.
+	beta, gamma := m.sessions[1], m.sessions[2]
+	beta.ID, beta.State = "", session.StateUnknown
+	moved := session.Session{TmuxPane: "%9", ProjectPath: "/home/user/project-alpha"}
+	result, _ := m.Update(sessionsDiscoveredMsg{beta, gamma, moved})
+	m = result.(Model)
+
+	if sel := m.selectedSession(); sel == nil || sel.TmuxPane != "%9" {
+		t.Fatalf("selected %+v, want the session in %%9", sel)
+	}
+	if !m.insertMode {
+		t.Error("insert mode should stay with the followed session")
+	}
+}
+
+func TestSelectionLostLeavesInsertMode(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	m.selected = 2
+	m.insertMode = true
+
+	result, _ := m.Update(sessionsDiscoveredMsg{m.sessions[0], m.sessions[1]})
+	m = result.(Model)
+
+	if m.selected != 1 {
+		t.Errorf("selected = %d, want 1", m.selected)
+	}
+	if m.insertMode {
+		t.Error("insert mode should not pass to another session")
+	}
+}
@@ -0,0 +1,78 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// frameMaxAge is how long a drawn frame is reused at most, so durations
+// and timers on screen keep ticking while nothing else changes.
+const frameMaxAge = time.Second
+
+// frameCache keeps the last frame View drew. Bubbletea asks for a frame
+// after every message, and most — capture ticks, unchanged captures — change
+// nothing on screen, so redrawing the sidebar for them is wasted work with
+// many sessions. It is shared by the copies of a Model.
+type frameCache struct {
+	valid bool
+	view  string
+	at    time.Time
+
+	// Render statistics for the frame_stats indicator.
+	took    time.Duration // to draw the last frame
+	draws   []time.Time   // when frames were drawn in the last second
+	skipped int           // frames reused since the last draw
+}
+
+// View draws the screen, reusing the last frame when no message since has
+// changed anything.
+func (m Model) View() string {
+	f := m.frame
+	if f == nil {
+		return m.render()
+	}
+	now := time.Now()
+	if f.valid && now.Sub(f.at) < frameMaxAge {
+		f.skipped++
+		return f.view
+	}
+	view := m.render()
+	f.took = time.Since(now)
+	f.valid, f.view, f.at, f.skipped = true, view, now, 0
+	f.draws = append(f.draws, now)
+	for len(f.draws) > 0 && now.Sub(f.draws[0]) > time.Second {
+		f.draws = f.draws[1:]
+	}
+	return view
+}
+
+// invalidateFrame makes the next View draw afresh unless msg, about to be
+// handled, is known to leave the screen as it is.
+func (m Model) invalidateFrame(msg tea.Msg) {
+	if m.frame == nil {
+		return
+	}
+	switch msg := msg.(type) {
+	case spinner.TickMsg, tickMsg:
+		// The spinner isn't drawn, and capture ticks only fetch.
+		return
+	case captureMsg:
+		sel := m.selectedSession()
+		if m.mode == ModeNormal && !m.forceViewportRefresh && (sel == nil || sel.TmuxPane != msg.paneID || msg.content == m.lastCapture) {
+			return
+		}
+	}
+	m.frame.valid = false
+}
+
+// frameStats renders the frame rate and the time the last frame took to
+// draw, e.g. "⏱ 12fps 3.1ms".
+func (m Model) frameStats() string {
+	if m.frame == nil {
+		return ""
+	}
+	return fmt.Sprintf("⏱ %dfps %.1fms", len(m.frame.draws), float64(m.frame.took.Microseconds())/1000)
+}
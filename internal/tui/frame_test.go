@@ -0,0 +1,43 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestViewReusesFrameUntilSomethingChanges(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+
+	update := func(msg tea.Msg) {
+		t.Helper()
+		updated, _ := m.Update(msg)
+		m = updated.(Model)
+	}
+	update(captureMsg{paneID: "%1", content: "first output"})
+	if !strings.Contains(m.View(), "first output") {
+		t.Fatal("capture not drawn")
+	}
+
+	// Capture ticks and an unchanged capture reuse the frame.
+	update(tickMsg{})
+	update(captureMsg{paneID: "%1", content: "first output"})
+	m.View()
+	if m.frame.skipped != 1 {
+		t.Errorf("skipped %d frames, want 1", m.frame.skipped)
+	}
+
+	update(captureMsg{paneID: "%1", content: "second output"})
+	if v := m.View(); !strings.Contains(v, "second output") || m.frame.skipped != 0 {
+		t.Errorf("new output not redrawn (skipped %d):\n%s", m.frame.skipped, v)
+	}
+
+	m.showFrameStats = true
+	m = pressKey(t, m, "j")
+	if v := ansi.Strip(m.View()); !strings.Contains(v, "fps") {
+		t.Errorf("frame stats missing from the help line:\n%s", v)
+	}
+}
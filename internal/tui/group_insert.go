@@ -0,0 +1,43 @@
+package tui
+
+import (
+	"time"
+
+	"github.com/shnupta/herd/internal/i18n"
+)
+
+// insertPanes returns the panes insert mode types into: every session of
+// the group in group insert mode, else the selected session's.
+func (m Model) insertPanes() []string {
+	if m.insertGroup != "" {
+		return m.groupPanes(m.insertGroup)
+	}
+	if sel := m.selectedSession(); sel != nil {
+		return []string{sel.TmuxPane}
+	}
+	return nil
+}
+
+// openGroupInsert starts insert mode for the group under the cursor, every
+// key going to all of its sessions at once, as tmux's synchronize-panes
+// does. It only starts if every session still runs Claude.
+func (m Model) openGroupInsert() Model {
+	groupKey, groupName := m.groupUnderCursor()
+	if groupKey == "" {
+		_ = m.tmuxClient.DisplayMessage("herd: " + i18n.T("session is not in a group"))
+		return m
+	}
+	for _, pane := range m.groupPanes(groupKey) {
+		if m.guardPane(pane) != nil {
+			return m
+		}
+	}
+	m.insertMode = true
+	m.insertGroup, m.insertGroupName = groupKey, groupName
+	m.insertChecked = time.Now()
+	return m
+}
+
+func (m Model) renderGroupInsertBar() string {
+	return styleHelpGroupInsert.Width(m.width).Render("  " + i18n.Tf("GROUP INSERT  typing into all %d sessions of %s  [ctrl+h] exit", len(m.groupPanes(m.insertGroup)), m.insertGroupName))
+}
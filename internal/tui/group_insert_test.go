@@ -0,0 +1,55 @@
+package tui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/shnupta/herd/internal/tmux/tmuxtest"
+)
+
+func TestGroupInsertTypesIntoEverySession(t *testing.T) {
+	sessions := testSessions()
+	sessions[2].TmuxSession = "1"
+	m, fw := newTestModel(t, sessions)
+	defer fw.Close()
+	mock := m.tmuxClient.(*tmuxtest.MockClient)
+	m.groupByTmux = true
+	m.itemsDirty = true
+	m.width = 120
+	for i, s := range m.sessions {
+		if s.TmuxPane == "%1" {
+			m.selected = i
+		}
+	}
+
+	m = pressKey(t, m, "G")
+	if !m.insertMode || m.insertGroup != "tmux:0" {
+		t.Fatalf("insertMode = %v, group = %q; want tmux:0", m.insertMode, m.insertGroup)
+	}
+	if help := m.renderHelp(); !strings.Contains(help, "GROUP INSERT") || !strings.Contains(help, "all 2 sessions of 0") {
+		t.Errorf("help = %q", help)
+	}
+	m = pressKey(t, m, "1")
+	m = pressKey(t, m, "enter")
+	if want := []string{"%1:1", "%2:1"}; !reflect.DeepEqual(mock.SendLiteralCalls, want) {
+		t.Errorf("typed %q, want %q", mock.SendLiteralCalls, want)
+	}
+	if want := []string{"%1:Enter", "%2:Enter"}; !reflect.DeepEqual(mock.SendKeyCalls, want) {
+		t.Errorf("sent keys %q, want %q", mock.SendKeyCalls, want)
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlH})
+	m = updated.(Model)
+	if m.insertMode {
+		t.Error("ctrl+h left group insert mode on")
+	}
+
+	// A pane that no longer runs Claude keeps the group out of insert mode.
+	mock.Panes[1].CurrentCmd = "zsh"
+	if m = pressKey(t, m, "G"); m.insertMode {
+		t.Error("group insert started with a shell in the group")
+	}
+}
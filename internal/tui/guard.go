@@ -0,0 +1,55 @@
+package tui
+
+import (
+	"errors"
+	"time"
+
+	"github.com/shnupta/herd/internal/i18n"
+	"github.com/shnupta/herd/internal/tmux"
+)
+
+// paneRecheck is how long insert mode trusts a pane it checked before
+// checking it again, so typing isn't slowed by a tmux call per key.
+const paneRecheck = time.Second
+
+// checkPane reports why pane can't be typed into, or nil if it still runs
+// Claude (see tmux.PaneRunsClaude): a prompt typed into a shell would run as
+// shell commands.
+func (m Model) checkPane(pane string) error {
+	return checkPane(m.tmuxClient, pane)
+}
+
+func checkPane(client tmux.ClientIface, pane string) error {
+	cmd, ok, err := tmux.PaneRunsClaude(client, pane)
+	switch {
+	case err != nil:
+		return errors.New(i18n.Tf("pane %s is gone, nothing sent", pane))
+	case !ok:
+		return errors.New(i18n.Tf("pane %s is running %s, not Claude, nothing sent", pane, cmd))
+	}
+	return nil
+}
+
+// checkShellPane is checkPane for a pane that should run a shell, such as
+// a scratch pane: a command meant for the shell mustn't reach Claude as a
+// prompt.
+func (m Model) checkShellPane(pane string) error {
+	_, claude, err := tmux.PaneRunsClaude(m.tmuxClient, pane)
+	switch {
+	case err != nil:
+		return errors.New(i18n.Tf("pane %s is gone, nothing sent", pane))
+	case claude:
+		return errors.New(i18n.Tf("pane %s is running Claude, not a shell, nothing sent", pane))
+	}
+	return nil
+}
+
+// guardPane checks pane before herd types into it, telling the user through
+// tmux if it mustn't.
+func (m Model) guardPane(pane string) error {
+	err := m.checkPane(pane)
+	if err != nil {
+		_ = m.tmuxClient.DisplayMessage("herd: " + err.Error())
+	}
+	return err
+}
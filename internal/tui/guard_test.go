@@ -0,0 +1,68 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shnupta/herd/internal/tmux"
+	"github.com/shnupta/herd/internal/tmux/tmuxtest"
+)
+
+func TestSendPromptRefusesRecycledPane(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	mock := m.tmuxClient.(*tmuxtest.MockClient)
+	orig := tmux.ClaudeUnder
+	t.Cleanup(func() { tmux.ClaudeUnder = orig })
+	tmux.ClaudeUnder = func(int) bool { return false }
+
+	if err := m.sendPrompt("%1", "run the tests", "test"); err != nil {
+		t.Fatalf("prompt to a Claude pane: %v", err)
+	}
+
+	// %2 has been closed and %1's Claude has exited to the shell.
+	mock.Panes = mock.Panes[:1]
+	mock.Panes[0].CurrentCmd = "zsh"
+	mock.Panes[0].PID = 42
+	mock.SendKeysCalls = nil
+	for _, pane := range []string{"%1", "%2"} {
+		if err := m.sendPrompt(pane, "run the tests", "test"); err == nil {
+			t.Errorf("prompt to %s sent", pane)
+		}
+	}
+	if len(mock.SendKeysCalls) != 0 {
+		t.Errorf("sent %q to panes without Claude", mock.SendKeysCalls)
+	}
+	if len(mock.Messages) != 2 || !strings.Contains(mock.Messages[0], "running zsh") || !strings.Contains(mock.Messages[1], "%2 is gone") {
+		t.Errorf("tmux messages = %q", mock.Messages)
+	}
+
+	// Claude under a wrapper is still Claude.
+	tmux.ClaudeUnder = func(pid int) bool { return pid == 42 }
+	if err := m.sendPrompt("%1", "run the tests", "test"); err != nil {
+		t.Errorf("prompt to wrapped Claude: %v", err)
+	}
+}
+
+func TestInsertModeStopsAtRecycledPane(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	mock := m.tmuxClient.(*tmuxtest.MockClient)
+	pane := m.selectedSession().TmuxPane
+
+	m = pressKey(t, m, "i")
+	m = pressKey(t, m, "x")
+	if !m.insertMode || len(mock.SendLiteralCalls) != 1 {
+		t.Fatalf("insertMode = %v, sent %q", m.insertMode, mock.SendLiteralCalls)
+	}
+
+	mock.Panes = nil
+	m.insertChecked = m.insertChecked.Add(-paneRecheck)
+	m = pressKey(t, m, "y")
+	if m.insertMode || len(mock.SendLiteralCalls) != 1 {
+		t.Errorf("after %s closed: insertMode = %v, sent %q", pane, m.insertMode, mock.SendLiteralCalls)
+	}
+	if len(mock.Messages) != 1 || !strings.Contains(mock.Messages[0], "is gone") {
+		t.Errorf("tmux messages = %q", mock.Messages)
+	}
+}
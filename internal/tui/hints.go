@@ -0,0 +1,46 @@
+package tui
+
+import (
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/shnupta/herd/internal/i18n"
+	"github.com/shnupta/herd/internal/session"
+)
+
+// stateHint returns what the user can do next with the selected session in
+// its state, for the start of the help line, or "".
+func (m Model) stateHint() string {
+	if m.hideHints || m.cursorOnGroup != "" {
+		return ""
+	}
+	sel := m.selectedSession()
+	if sel == nil {
+		return ""
+	}
+	switch sel.State {
+	case session.StatePlanReady:
+		return i18n.T("plan ready — [y] approve it, [Y] keep planning with feedback, [d] review its changes")
+	case session.StateWaiting:
+		return i18n.T("waiting on you — [a] answer in the queue, [i] type into the pane, [t] jump to it")
+	case session.StateNotifying:
+		return i18n.T("wants your attention — [a] open the queue, [t] jump to the pane")
+	case session.StateIdle:
+		return i18n.T("idle — [i] send the next prompt, [d] review its changes, [L] see what it did")
+	case session.StateWorking:
+		return i18n.T("working — [?] search its output, [L] follow its activity")
+	}
+	return i18n.T("no hook events yet — [I] installs herd's hooks")
+}
+
+// renderStateHint renders hint on its state's tint, as the session row is.
+func (m Model) renderStateHint(hint string) string {
+	st := ""
+	if sel := m.selectedSession(); sel != nil {
+		st = sel.State.String()
+	}
+	return lipgloss.NewStyle().
+		Background(stateBg(st)).
+		Foreground(colText).
+		Padding(0, 1).
+		Render(hint)
+}
@@ -0,0 +1,36 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/shnupta/herd/internal/session"
+)
+
+func TestHelpHintFollowsState(t *testing.T) {
+	sessions := testSessions()
+	sessions[1].State = session.StatePlanReady
+	m, fw := newTestModel(t, sessions)
+	defer fw.Close()
+	m.width = 120
+	for i, s := range m.sessions {
+		if s.TmuxPane == "%2" {
+			m.selected = i
+		}
+	}
+
+	help := m.renderHelp()
+	if !strings.Contains(help, "plan ready — [y] approve") {
+		t.Errorf("help %q lacks the plan hint", help)
+	}
+	if h, w := lipgloss.Height(help), lipgloss.Width(help); h != 1 || w != m.width {
+		t.Errorf("help is %d×%d, want one line %d wide", w, h, m.width)
+	}
+
+	m.hideHints = true
+	if help := m.renderHelp(); strings.Contains(help, "plan ready") {
+		t.Errorf("hide_hints still shows %q", help)
+	}
+}
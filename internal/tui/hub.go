@@ -0,0 +1,102 @@
+package tui
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/shnupta/herd/internal/daemon"
+	"github.com/shnupta/herd/internal/i18n"
+)
+
+// hubMsg carries the sessions of the hub at url, a herd daemon serving its
+// HTTP API elsewhere.
+type hubMsg struct {
+	url      string
+	sessions []daemon.SessionInfo
+	err      error
+}
+
+// fetchHub asks the hub at hubURL for its sessions, or does nothing when no
+// hub_url is configured.
+func fetchHub(hubURL, token string) tea.Cmd {
+	if hubURL == "" {
+		return nil
+	}
+	return func() tea.Msg {
+		snap, err := daemon.QueryHTTP(hubURL, token)
+		return hubMsg{url: hubURL, sessions: snap.Sessions, err: err}
+	}
+}
+
+// applyHub keeps the hub's sessions for the sidebar. On an error the last
+// list is kept, marked stale by the error, so a blip doesn't empty it.
+func (m Model) applyHub(msg hubMsg) Model {
+	if msg.url != m.hubURL {
+		m.hubSessions = nil
+	}
+	m.hubURL = msg.url
+	m.hubErr = msg.err
+	if msg.err == nil {
+		m.hubSessions = msg.sessions
+	}
+	return m
+}
+
+// hubShare is the part of the sidebar's height the hub section may take,
+// one over hubShare, so a busy hub can't push the local sessions out.
+const hubShare = 3
+
+// renderHub renders the hub's sessions, for under the local ones, in at
+// most height lines; a last line counts those that don't fit. They are
+// read-only: the cursor never lands on them and nothing is sent to them.
+func (m Model) renderHub(height int) string {
+	if m.hubURL == "" {
+		return ""
+	}
+	host := m.hubURL
+	if u, err := url.Parse(m.hubURL); err == nil && u.Host != "" {
+		host = u.Host
+	}
+	meta := lipgloss.NewStyle().Foreground(colSubtle)
+	lines := []string{styleGroupHeader.Render(i18n.Tf("hub %s", host) + " " + meta.Render(fmt.Sprintf("(%d)", len(m.hubSessions))))}
+	if m.hubErr != nil {
+		lines = append(lines, styleOverlayError.Render(ansi.Truncate(i18n.T("unreachable: ")+m.hubErr.Error(), sessionPaneWidth-2, "…")))
+	}
+	height = max(height, len(lines)+1)
+	for i, s := range m.hubSessions {
+		if len(lines) == height-1 && i < len(m.hubSessions)-1 {
+			lines = append(lines, styleSessionItem.Render(meta.Render(i18n.Tf("… and %d more", len(m.hubSessions)-i))))
+			break
+		}
+		name := s.Name
+		if name == "" {
+			name = filepath.Base(s.ProjectPath)
+		}
+		line := stateIcon(s.State) + " " + name
+		if s.GitBranch != "" {
+			line += " " + meta.Render(s.GitBranch)
+		}
+		lines = append(lines, styleSessionItem.Render(ansi.Truncate(line, sessionPaneWidth-2, "…")))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// withHub puts the hub section under list, the sidebar's local sessions, in
+// height lines: the hub gets up to its share and list is cut to the rest.
+func (m Model) withHub(list string, height int) string {
+	hub := m.renderHub(height / hubShare)
+	if hub == "" {
+		return list
+	}
+	lines := strings.Split(list, "\n")
+	if room := max(height-lipgloss.Height(hub)-1, 0); len(lines) > room {
+		lines = lines[:room]
+	}
+	return strings.Join(lines, "\n") + "\n\n" + hub
+}
@@ -0,0 +1,83 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/shnupta/herd/internal/daemon"
+)
+
+func TestHubSessionsListedReadOnly(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/sessions" || r.Header.Get("Authorization") != "Bearer t0k" {
+			http.Error(w, `{"error":"missing or wrong API token"}`, http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(daemon.Snapshot{Sessions: []daemon.SessionInfo{
+			{Key: "session:remote", Name: "lead-api", ProjectPath: "/srv/api", State: "working", GitBranch: "main"},
+		}})
+	}))
+	defer srv.Close()
+
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	if fetchHub("", "") != nil {
+		t.Fatal("fetchHub without a hub_url should do nothing")
+	}
+
+	updated, _ := m.Update(fetchHub(srv.URL, "t0k")())
+	m = updated.(Model)
+	list := ansi.Strip(m.View())
+	if !strings.Contains(list, "hub "+strings.TrimPrefix(srv.URL, "http://")) || !strings.Contains(list, "lead-api") {
+		t.Fatalf("sidebar missing the hub's session:\n%s", list)
+	}
+	if len(m.sessions) != len(testSessions()) {
+		t.Errorf("hub sessions joined the local list: %d sessions", len(m.sessions))
+	}
+
+	// A failed fetch keeps the last list and says why.
+	updated, _ = m.Update(fetchHub(srv.URL, "wrong")())
+	m = updated.(Model)
+	if list := ansi.Strip(m.View()); !strings.Contains(list, "lead-api") || !strings.Contains(list, "unreachable") {
+		t.Errorf("sidebar after a failed fetch:\n%s", list)
+	}
+}
+
+func TestHubKeepsToItsShareOfTheSidebar(t *testing.T) {
+	var hub []daemon.SessionInfo
+	for i := 0; i < 40; i++ {
+		hub = append(hub, daemon.SessionInfo{Key: fmt.Sprintf("session:r%d", i), Name: fmt.Sprintf("remote-%d", i), State: "idle"})
+	}
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	m = m.applyHub(hubMsg{url: "http://lead:7777", sessions: hub})
+
+	view := ansi.Strip(m.View())
+	if h := lipgloss.Height(view); h > m.height {
+		t.Errorf("view is %d lines, taller than the %d-line terminal", h, m.height)
+	}
+	if !strings.Contains(view, "remote-0") || strings.Contains(view, "remote-39") || !strings.Contains(view, "more") {
+		t.Errorf("hub section should be cut to its share with a count of the rest:\n%s", view)
+	}
+	if !strings.Contains(view, "alpha") {
+		t.Errorf("local sessions pushed out by the hub:\n%s", view)
+	}
+	if lines := strings.Count(m.renderHub((m.height-2)/hubShare), "\n") + 1; lines > (m.height-2)/hubShare {
+		t.Errorf("hub section is %d lines, over its share of %d", lines, (m.height-2)/hubShare)
+	}
+
+	// With no local sessions the landing page still lists the hub's.
+	m, fw2 := newTestModel(t, nil)
+	defer fw2.Close()
+	m = m.applyHub(hubMsg{url: "http://lead:7777", sessions: hub[:2]})
+	if view := ansi.Strip(m.View()); !strings.Contains(view, "remote-1") {
+		t.Errorf("landing page hides the hub:\n%s", view)
+	}
+}
@@ -1,13 +1,26 @@
 package tui
 
 import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/x/exp/teatest"
+	"github.com/shnupta/herd/internal/alias"
+	"github.com/shnupta/herd/internal/config"
+	"github.com/shnupta/herd/internal/prompts"
+	"github.com/shnupta/herd/internal/review"
 	"github.com/shnupta/herd/internal/session"
+	"github.com/shnupta/herd/internal/snippets"
 	"github.com/shnupta/herd/internal/state"
+	"github.com/shnupta/herd/internal/store"
+	"github.com/shnupta/herd/internal/teams"
 	"github.com/shnupta/herd/internal/tmux"
 	"github.com/shnupta/herd/internal/tmux/tmuxtest"
 )
@@ -53,6 +66,21 @@ func newTestModel(t *testing.T, sessions []session.Session) (Model, *state.FakeW
 		Panes: makePanes(sessions),
 	}
 	m := New(fw, mock)
+	// Keep the key-alias index out of the real ~/.herd.
+	m.aliases = alias.NewIndex(filepath.Join(t.TempDir(), "keys.json"))
+	m.scratch = store.NewStore(filepath.Join(t.TempDir(), "scratch.json"))
+	m.blockers = store.NewStore(filepath.Join(t.TempDir(), "blockers.json"))
+	m.onboarding = store.NewStore(filepath.Join(t.TempDir(), "onboarding.json"))
+	m.setupPending = false // keep the getting-started panel out of the way
+	m.snippets = snippets.NewStore(filepath.Join(t.TempDir(), "snippets.json"))
+	m.prompts = prompts.NewStore(filepath.Join(t.TempDir(), "prompts.json"))
+	m.snapshotDir = t.TempDir()
+	m.historyPath = filepath.Join(t.TempDir(), "history.jsonl")
+	m.daemonSock = "" // and its session list out of any daemon running for real
+	// Reviews kept after submitting would otherwise land in ~/.herd.
+	origSave := saveReview
+	saveReview = func(*review.Review) error { return nil }
+	t.Cleanup(func() { saveReview = origSave })
 	// Pre-seed sessions so we don't rely on async discovery timing.
 	m.sessions = sessions
 	m.itemsDirty = true
@@ -153,6 +181,40 @@ func TestFilterMode(t *testing.T) {
 	}
 }
 
+func TestFilterMatchesTeamLabels(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	dir := t.TempDir()
+	team := teams.Team{Name: "backend", Members: []teams.Member{{Name: "reviewer", TmuxPaneID: "%2"}}}
+	data, _ := json.Marshal(team)
+	if err := os.MkdirAll(filepath.Join(dir, "backend"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "backend", "config.json"), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	m.teamsStore = teams.NewStore(dir)
+	if err := m.teamsStore.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct{ query, pane, hit string }{
+		{"REVIEW", "%2", "member"},
+		{"backend", "%2", "team"},
+		{"alpha", "%1", ""},
+	} {
+		m.filterQuery = tc.query
+		m.updateFilter()
+		if len(m.filtered) != 1 || m.sessions[m.filtered[0]].TmuxPane != tc.pane {
+			t.Errorf("%q matched %v, want only %s", tc.query, m.filtered, tc.pane)
+			continue
+		}
+		if got := m.filterHits[tc.pane]; got != tc.hit {
+			t.Errorf("%q matched on %q, want %q", tc.query, got, tc.hit)
+		}
+	}
+}
+
 func TestStateUpdateMessage(t *testing.T) {
 	sessions := testSessions()
 	m, fw := newTestModel(t, sessions)
@@ -277,6 +339,74 @@ func TestGroupCollapseWithGroupedSessions(t *testing.T) {
 	}
 }
 
+func TestGroupByTmuxSession(t *testing.T) {
+	sessions := testSessions()
+	sessions[0].TmuxSession = "api"
+	sessions[1].TmuxSession = "api"
+	sessions[2].TmuxSession = "web"
+	m, fw := newTestModel(t, sessions)
+	defer fw.Close()
+
+	for _, it := range m.viewItems() {
+		if it.isHeader {
+			t.Fatalf("grouped by tmux session without the option: %+v", it)
+		}
+	}
+
+	m.groupByTmux = true
+	m.itemsDirty = true
+	var headers []string
+	for _, it := range m.viewItems() {
+		if it.isHeader {
+			headers = append(headers, fmt.Sprintf("%s/%d", it.groupName, it.count))
+		}
+	}
+	if strings.Join(headers, " ") != "api/2 web/1" {
+		t.Errorf("group headers = %v, want api/2 web/1", headers)
+	}
+}
+
+func TestStaleSessionsCollapseIntoLastGroup(t *testing.T) {
+	sessions := testSessions()
+	sessions[0].State, sessions[0].UpdatedAt = session.StateIdle, time.Now().Add(-3*time.Hour)
+	sessions[2].UpdatedAt = time.Now().Add(-10 * time.Minute)
+	m, fw := newTestModel(t, sessions)
+	defer fw.Close()
+
+	describe := func() string {
+		var rows []string
+		for _, it := range m.viewItems() {
+			if it.isHeader {
+				rows = append(rows, fmt.Sprintf("[%s/%d]", it.groupName, it.count))
+			} else {
+				rows = append(rows, m.sessions[it.sessionIdx].TmuxPane)
+			}
+		}
+		return strings.Join(rows, " ")
+	}
+	if got := describe(); got != "%1 %2 %3" {
+		t.Errorf("rows without stale_after_minutes = %s", got)
+	}
+
+	m.staleAfter = time.Hour
+	m.itemsDirty = true
+	if got := describe(); got != "%2 %3 [stale/1]" {
+		t.Errorf("rows = %s, want alpha collapsed into a trailing stale group", got)
+	}
+
+	m.collapsedGroups[staleGroupKey] = false
+	m.itemsDirty = true
+	if got := describe(); got != "%2 %3 [stale/1] %1" {
+		t.Errorf("expanded rows = %s", got)
+	}
+
+	m.pinned[sessions[0].Key()] = 1
+	m.itemsDirty = true
+	if got := describe(); got != "%1 %2 %3" {
+		t.Errorf("rows with alpha pinned = %s, want it kept out of the stale group", got)
+	}
+}
+
 func TestViewOutputContainsSessionNames(t *testing.T) {
 	sessions := testSessions()
 	m, fw := newTestModel(t, sessions)
@@ -341,3 +471,118 @@ func bytesContains(haystack, needle []byte) bool {
 	}
 	return false
 }
+
+func TestCaptureSettingsPerSession(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	mock := m.tmuxClient.(*tmuxtest.MockClient)
+	m.capture = []config.Capture{{Project: "/home/user/project-alpha", Scrollback: 8000, IntervalMS: 1000}}
+
+	m.fetchCapture("%1")()
+	m.fetchCapture("%2")()
+	if want := []string{"%1:8000", "%2:2000"}; !reflect.DeepEqual(mock.CaptureCalls, want) {
+		t.Errorf("CaptureCalls = %v, want %v", mock.CaptureCalls, want)
+	}
+
+	t0 := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	for _, tick := range []struct {
+		after time.Duration
+		want  time.Time
+	}{
+		{0, t0},
+		{300 * time.Millisecond, t0}, // within alpha's 1s interval: skipped
+		{time.Second, t0.Add(time.Second)},
+	} {
+		updated, _ := m.Update(tickMsg(t0.Add(tick.after)))
+		m = updated.(Model)
+		if !m.lastCaptureAt.Equal(tick.want) {
+			t.Errorf("after tick at +%v lastCaptureAt = %v, want %v", tick.after, m.lastCaptureAt, tick.want)
+		}
+	}
+}
+
+func TestAlternateScreenShowsPlaceholder(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	mock := m.tmuxClient.(*tmuxtest.MockClient)
+	mock.CaptureOutput = "\x1b[?1049h garbled"
+	mock.AlternateOnVal = true
+
+	updated, _ := m.Update(m.fetchCapture("%1")())
+	view := updated.(Model).View()
+	if !strings.Contains(view, "full-screen program") || strings.Contains(view, "garbled") {
+		t.Errorf("view should show the placeholder, not the capture:\n%s", view)
+	}
+	if len(mock.CaptureCalls) != 0 {
+		t.Errorf("captured %v while on the alternate screen", mock.CaptureCalls)
+	}
+}
+
+func TestSharedPaneIsNotResized(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	mock := m.tmuxClient.(*tmuxtest.MockClient)
+	mock.PaneSharedVal = true
+
+	msg := m.resizePaneToViewport("%1", 80, 24)()
+	updated, cmd := m.Update(msg)
+	m = updated.(Model)
+	if len(mock.ResizeCalls) != 0 {
+		t.Errorf("resized a shared pane: %v", mock.ResizeCalls)
+	}
+	if view := m.View(); !strings.Contains(view, "view only (shared)") {
+		t.Errorf("header should say the pane is shared:\n%s", view)
+	}
+	runCmds(cmd)
+	if len(mock.AutoResizeCalls) != 1 {
+		t.Errorf("auto-resize calls = %v, want the window handed back to tmux", mock.AutoResizeCalls)
+	}
+
+	// The other viewer leaves: herd sizes the window again.
+	mock.PaneSharedVal = false
+	updated, cmd = m.Update(m.checkPaneShared("%1")())
+	m = updated.(Model)
+	runCmds(cmd)
+	if len(mock.ResizeCalls) != 1 || strings.Contains(m.View(), "view only") {
+		t.Errorf("after the viewer left: resizes %v, header still shared: %v", mock.ResizeCalls, strings.Contains(m.View(), "view only"))
+	}
+}
+
+// runCmds runs cmd, and every command of it if it is a tea.Batch.
+func runCmds(cmd tea.Cmd) {
+	if cmd == nil {
+		return
+	}
+	if batch, ok := cmd().(tea.BatchMsg); ok {
+		for _, c := range batch {
+			runCmds(c)
+		}
+	}
+}
+
+func TestPasteGoesInOneCall(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	mock := m.tmuxClient.(*tmuxtest.MockClient)
+	pane := m.selectedSession().TmuxPane
+	paste := func(text string) {
+		t.Helper()
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(text), Paste: true})
+		m = updated.(Model)
+	}
+
+	m = pressKey(t, m, "i")
+	paste("line one\nline two")
+	if want := []string{pane + ":line one\nline two"}; !reflect.DeepEqual(mock.PasteCalls, want) || len(mock.SendLiteralCalls) != 0 {
+		t.Errorf("pasted %q, typed %q; want %q pasted", mock.PasteCalls, mock.SendLiteralCalls, want)
+	}
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlH})
+	m = updated.(Model)
+
+	// Text inputs take a paste as typed text.
+	m = pressKey(t, m, "/")
+	paste("gamma")
+	if m.filterQuery != "gamma" || len(m.filtered) != 1 {
+		t.Errorf("filter %q matched %d sessions, want gamma's one", m.filterQuery, len(m.filtered))
+	}
+}
@@ -19,10 +19,41 @@ type keyMap struct {
 	MoveUp      key.Binding
 	MoveDown    key.Binding
 	Rename      key.Binding
+	BulkEdit    key.Binding
 	ToggleGroup key.Binding
 	SetGroup    key.Binding
+	Model       key.Binding
+	Palette     key.Binding
+	Actions     key.Binding
+	Scratch     key.Binding
+	RunTests    key.Binding
+	TestPanel   key.Binding
+	Tickets     key.Binding
+	Merge       key.Binding
+	GroupInsert key.Binding
+	Queue       key.Binding
+	BlockedOn   key.Binding
+	Stats       key.Binding
+	SaveSnippet key.Binding
+	Snippets    key.Binding
+	Squads      key.Binding
+	Menu        key.Binding
+	Snapshot    key.Binding
+	Teardown    key.Binding
+	Focus       key.Binding
+	Compose     key.Binding
+	Files       key.Binding
+	Mark        key.Binding
+	Broadcast   key.Binding
+	Search      key.Binding
+	Activity    key.Binding
+	Onboarding  key.Binding
+	ApprovePlan key.Binding
+	RejectPlan  key.Binding
 }
 
+// keys is built before the locale is set, so each help desc is a catalog key
+// that is passed through i18n.T where it is shown.
 var keys = keyMap{
 	Up: key.NewBinding(
 		key.WithKeys("k", "up"),
@@ -88,6 +119,10 @@ var keys = keyMap{
 		key.WithKeys("e"),
 		key.WithHelp("e", "rename"),
 	),
+	BulkEdit: key.NewBinding(
+		key.WithKeys("E"),
+		key.WithHelp("E", "bulk edit"),
+	),
 	ToggleGroup: key.NewBinding(
 		key.WithKeys(" "),
 		key.WithHelp("space", "collapse group"),
@@ -96,4 +131,116 @@ var keys = keyMap{
 		key.WithKeys("g"),
 		key.WithHelp("g", "set group"),
 	),
+	Model: key.NewBinding(
+		key.WithKeys("M"),
+		key.WithHelp("M", "switch model"),
+	),
+	Palette: key.NewBinding(
+		key.WithKeys(":"),
+		key.WithHelp(":", "slash commands"),
+	),
+	Actions: key.NewBinding(
+		key.WithKeys("."),
+		key.WithHelp(".", "quick actions"),
+	),
+	Scratch: key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "scratch shell"),
+	),
+	RunTests: key.NewBinding(
+		key.WithKeys("T"),
+		key.WithHelp("T", "run tests"),
+	),
+	TestPanel: key.NewBinding(
+		key.WithKeys("o"),
+		key.WithHelp("o", "toggle test output"),
+	),
+	Tickets: key.NewBinding(
+		key.WithKeys("b"),
+		key.WithHelp("b", "start from ticket"),
+	),
+	Merge: key.NewBinding(
+		key.WithKeys("m"),
+		key.WithHelp("m", "merged group output"),
+	),
+	GroupInsert: key.NewBinding(
+		key.WithKeys("G"),
+		key.WithHelp("G", "type into the whole group"),
+	),
+	Queue: key.NewBinding(
+		key.WithKeys("a"),
+		key.WithHelp("a", "attention queue"),
+	),
+	BlockedOn: key.NewBinding(
+		key.WithKeys("B"),
+		key.WithHelp("B", "blocked on"),
+	),
+	Stats: key.NewBinding(
+		key.WithKeys("S"),
+		key.WithHelp("S", "working time stats"),
+	),
+	SaveSnippet: key.NewBinding(
+		key.WithKeys("c"),
+		key.WithHelp("c", "save visible output as snippet"),
+	),
+	Snippets: key.NewBinding(
+		key.WithKeys("P"),
+		key.WithHelp("P", "snippets"),
+	),
+	Squads: key.NewBinding(
+		key.WithKeys("Q"),
+		key.WithHelp("Q", "launch squad"),
+	),
+	Menu: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "session menu"),
+	),
+	Snapshot: key.NewBinding(
+		key.WithKeys("H"),
+		key.WithHelp("H", "share HTML snapshot"),
+	),
+	Teardown: key.NewBinding(
+		key.WithKeys("X"),
+		key.WithHelp("X", "kill and clean up worktree"),
+	),
+	Focus: key.NewBinding(
+		key.WithKeys("f"),
+		key.WithHelp("f", "focus timer"),
+	),
+	Compose: key.NewBinding(
+		key.WithKeys("A"),
+		key.WithHelp("A", "prompt with files attached"),
+	),
+	Files: key.NewBinding(
+		key.WithKeys("F"),
+		key.WithHelp("F", "browse project files"),
+	),
+	Mark: key.NewBinding(
+		key.WithKeys("v"),
+		key.WithHelp("v", "mark for broadcast"),
+	),
+	Broadcast: key.NewBinding(
+		key.WithKeys("V"),
+		key.WithHelp("V", "broadcast to marked"),
+	),
+	Search: key.NewBinding(
+		key.WithKeys("?"),
+		key.WithHelp("?", "search output"),
+	),
+	Activity: key.NewBinding(
+		key.WithKeys("L"),
+		key.WithHelp("L", "activity log"),
+	),
+	Onboarding: key.NewBinding(
+		key.WithKeys("O"),
+		key.WithHelp("O", "getting started"),
+	),
+	ApprovePlan: key.NewBinding(
+		key.WithKeys("y"),
+		key.WithHelp("y", "approve plan"),
+	),
+	RejectPlan: key.NewBinding(
+		key.WithKeys("Y"),
+		key.WithHelp("Y", "keep planning, with feedback"),
+	),
 }
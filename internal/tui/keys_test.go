@@ -0,0 +1,38 @@
+package tui
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// TestNormalModeKeysAreUnique guards against a new binding silently shadowing
+// an existing one in updateNormal's key switch.
+func TestNormalModeKeysAreUnique(t *testing.T) {
+	v := reflect.ValueOf(keys)
+	owner := make(map[string]string)
+	for i := 0; i < v.NumField(); i++ {
+		name := v.Type().Field(i).Name
+		b, ok := v.Field(i).Interface().(key.Binding)
+		if !ok {
+			continue
+		}
+		for _, k := range b.Keys() {
+			if prev, dup := owner[k]; dup {
+				t.Errorf("key %q bound to both %s and %s", k, prev, name)
+			}
+			owner[k] = name
+		}
+	}
+}
+
+// TestEveryModeIsRouted makes sure a new Mode isn't added without a route,
+// which would silently hand its keys to normal mode.
+func TestEveryModeIsRouted(t *testing.T) {
+	for mode := ModeReview; mode < numModes; mode++ {
+		if _, ok := modeRoutes[mode]; !ok {
+			t.Errorf("mode %d has no entry in modeRoutes", mode)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/shnupta/herd/internal/i18n"
+	"github.com/shnupta/herd/internal/plugin"
+	"github.com/shnupta/herd/internal/session"
+)
+
+// menuItem is one entry of the session context menu. key is the normal-mode
+// key the entry stands for; choosing the entry presses it, or runs the
+// plugin action when plugin is set.
+type menuItem struct {
+	label  string
+	key    string
+	plugin *plugin.Plugin
+	action plugin.Action
+}
+
+// sessionMenuItems lists what can be done to the selected session: the
+// normal-mode bindings that act on it, its plugin actions, and kill last.
+func (m Model) sessionMenuItems() []menuItem {
+	bindings := []key.Binding{
+		keys.Jump, keys.Insert, keys.Rename, keys.SetGroup, keys.Pin,
+		keys.BlockedOn, keys.Review, keys.Worktree, keys.Scratch,
+		keys.RunTests, keys.TestPanel, keys.Actions, keys.Palette,
+		keys.Model, keys.SaveSnippet, keys.Snippets, keys.Snapshot, keys.Focus,
+		keys.Compose, keys.Files, keys.Mark, keys.Search,
+		keys.Activity,
+	}
+	if sel := m.selectedSession(); sel != nil {
+		if gKey, _ := m.groupKeyAndName(*sel); gKey != "" {
+			bindings = append(bindings, keys.Merge, keys.GroupInsert)
+		}
+		if sel.State == session.StatePlanReady {
+			bindings = append([]key.Binding{keys.ApprovePlan, keys.RejectPlan}, bindings...)
+		}
+	}
+	var items []menuItem
+	for _, b := range bindings {
+		items = append(items, menuItem{label: i18n.T(b.Help().Desc), key: b.Keys()[0]})
+	}
+	for _, p := range m.plugins {
+		for _, a := range p.Actions {
+			items = append(items, menuItem{label: a.Label, key: a.Key, plugin: p, action: a})
+		}
+	}
+	if sel := m.selectedSession(); sel != nil && inLinkedWorktree(*sel) {
+		items = append(items, menuItem{label: i18n.T(keys.Teardown.Help().Desc), key: keys.Teardown.Keys()[0]})
+	}
+	return append(items, menuItem{label: i18n.T(keys.Kill.Help().Desc), key: keys.Kill.Keys()[0]})
+}
+
+// menuFirstRow is the screen row of the first menu entry, below the title
+// and a blank line.
+const menuFirstRow = 2
+
+// openMenu selects the session at idx and opens its context menu: on
+// right-click or with enter for the selected session.
+func (m Model) openMenu(idx int) (Model, tea.Cmd) {
+	var cmd tea.Cmd
+	if m.selected != idx {
+		m.selected = idx
+		m.cursorOnGroup = ""
+		m.itemsDirty = true
+		m, cmd = m.selectSession()
+		m.forceViewportRefresh = true
+	}
+	m.menuItems = m.sessionMenuItems()
+	m.menuSelected = 0
+	m.mode = ModeMenu
+	return m, cmd
+}
+
+func (m Model) updateMenuMode(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m = m.recalcLayout()
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "q":
+			m.mode = ModeNormal
+			return m, nil
+		case "k", "up":
+			if m.menuSelected > 0 {
+				m.menuSelected--
+			}
+		case "j", "down":
+			if m.menuSelected < len(m.menuItems)-1 {
+				m.menuSelected++
+			}
+		case "enter":
+			return m.runMenuItem(m.menuSelected)
+		}
+
+	case tea.MouseMsg:
+		if msg.Action != tea.MouseActionPress {
+			return m, nil
+		}
+		if i := msg.Y - menuFirstRow; msg.Button == tea.MouseButtonLeft && i >= 0 && i < len(m.menuItems) {
+			return m.runMenuItem(i)
+		}
+		// A click anywhere else dismisses the menu.
+		m.mode = ModeNormal
+	}
+	return m, nil
+}
+
+// runMenuItem closes the menu and presses the entry's key, which the
+// normal-mode handler then receives like a typed one.
+func (m Model) runMenuItem(i int) (tea.Model, tea.Cmd) {
+	if i >= len(m.menuItems) {
+		return m, nil
+	}
+	m.mode = ModeNormal
+	it := m.menuItems[i]
+	if it.plugin != nil {
+		return m, m.runPluginAction(it.plugin, it.action)
+	}
+	press := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(it.key)}
+	return m, func() tea.Msg { return press }
+}
+
+func (m Model) renderMenuOverlay() string {
+	var sb strings.Builder
+	title := i18n.T("Session")
+	if sel := m.selectedSession(); sel != nil {
+		title = m.displayName(*sel)
+	}
+	sb.WriteString(styleOverlayTitle.Width(m.width).Render(title) + "\n\n")
+
+	subtle := lipgloss.NewStyle().Foreground(colSubtle)
+	for i, it := range m.menuItems {
+		line := fmt.Sprintf("%-32s", it.label) + subtle.Render(it.key)
+		if i == m.menuSelected {
+			sb.WriteString(pickerSelectedStyle.Width(m.width-4).Render("▸ "+line) + "\n")
+		} else {
+			sb.WriteString(pickerItemStyle.Render("  "+line) + "\n")
+		}
+	}
+
+	sb.WriteString("\n" + styleOverlayHelp.Render(i18n.T("[j/k] navigate  [enter/click] run  [esc] close")))
+	return sb.String()
+}
@@ -0,0 +1,107 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/shnupta/herd/internal/session"
+	"github.com/shnupta/herd/internal/tmux/tmuxtest"
+)
+
+func click(t *testing.T, m Model, button tea.MouseButton, x, y int) (Model, tea.Cmd) {
+	t.Helper()
+	updated, cmd := m.Update(tea.MouseMsg{X: x, Y: y, Button: button, Action: tea.MouseActionPress})
+	return updated.(Model), cmd
+}
+
+func TestRightClickMenuRunsAction(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+
+	// Sessions take two rows each below the header, so row 3 is the second.
+	m, _ = click(t, m, tea.MouseButtonRight, 2, 3)
+	if m.mode != ModeMenu || m.selected != 1 {
+		t.Fatalf("mode = %d, selected = %d; want the menu for the second session", m.mode, m.selected)
+	}
+	if view := m.View(); !strings.Contains(view, "rename") || !strings.Contains(view, "kill session") {
+		t.Errorf("menu view missing actions:\n%s", view)
+	}
+
+	m, cmd := click(t, m, tea.MouseButtonLeft, 4, menuFirstRow+2) // jump, insert, rename
+	if m.mode != ModeNormal || cmd == nil {
+		t.Fatalf("mode = %d after choosing Rename, want normal with the key pending", m.mode)
+	}
+	updated, _ := m.Update(cmd())
+	got := updated.(Model)
+	if got.mode != ModeRename {
+		t.Fatalf("mode = %d, want renaming", got.mode)
+	}
+	done, _ := promptOf(t, got).Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if res, _ := done.Result().(renameMsg); res.key != m.sessions[1].Key() {
+		t.Errorf("rename result %#v; want renaming the second session", done.Result())
+	}
+}
+
+func TestMenuKeyListsSessionActions(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	m = pressKey(t, m, "enter")
+	if m.mode != ModeMenu {
+		t.Fatalf("mode = %d, want menu", m.mode)
+	}
+	items := m.menuItems
+	if last := items[len(items)-1]; last.key != "x" {
+		t.Errorf("last item = %+v, want kill", last)
+	}
+	for _, it := range items {
+		if it.key == "m" {
+			t.Error("merge offered for an ungrouped session")
+		}
+	}
+	view := m.View()
+	for _, want := range []string{"snippets", "P", "run tests", "T"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("menu missing %q:\n%s", want, view)
+		}
+	}
+
+	m = pressKey(t, m, "j")
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if updated, _ = updated.(Model).Update(cmd()); !updated.(Model).insertMode {
+		t.Error("choosing the second item should enter insert mode")
+	}
+}
+
+func TestClickStatePillFilters(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+
+	// Pills are right-aligned, so the last one, idle, ends just before the
+	// final column.
+	m, _ = click(t, m, tea.MouseButtonLeft, m.width-3, 0)
+	if m.filterQuery != "state:idle" {
+		t.Fatalf("filterQuery = %q, want state:idle", m.filterQuery)
+	}
+	got := m.filteredSessions()
+	if len(got) != 1 || got[0].State != session.StateIdle {
+		t.Errorf("filtered = %+v, want only the idle session", got)
+	}
+
+	m, _ = click(t, m, tea.MouseButtonLeft, m.width-3, 0)
+	if m.filterQuery != "" {
+		t.Errorf("second click left filter %q, want it cleared", m.filterQuery)
+	}
+}
+
+func TestClickOutputHeaderJumps(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	mock := m.tmuxClient.(*tmuxtest.MockClient)
+
+	click(t, m, tea.MouseButtonLeft, sessionPaneWidth+5, 1)
+	if len(mock.SwitchedPanes) != 1 || mock.SwitchedPanes[0] != "%1" {
+		t.Errorf("SwitchedPanes = %v, want [%%1]", mock.SwitchedPanes)
+	}
+}
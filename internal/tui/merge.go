@@ -0,0 +1,313 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/shnupta/herd/internal/i18n"
+)
+
+const (
+	mergeInterval     = 500 * time.Millisecond
+	mergeScrollback   = 200  // lines of history captured per pane each poll
+	mergeInitialLines = 5    // context shown per session when the view opens
+	mergeMaxLines     = 2000 // merged lines kept; older ones are dropped
+)
+
+// mergeColours tell sessions apart in the merged stream, assigned in the
+// order sessions first appear.
+var mergeColours = []lipgloss.Color{colBlue, colGreen, colAmber, colPurple, colCyan, colRed, colGold}
+
+// mergeTickMsg schedules the next poll of the merged group. gen ties it to
+// one opening of the view so a stale chain dies when the view is reopened.
+type mergeTickMsg struct{ gen int }
+
+// mergeCaptureMsg carries one poll of every pane in the merged group.
+type mergeCaptureMsg struct {
+	gen      int
+	at       time.Time
+	panes    []string // in sidebar order
+	captures map[string]string
+}
+
+func isMergeMsg(msg tea.Msg) bool {
+	switch msg.(type) {
+	case mergeTickMsg, mergeCaptureMsg:
+		return true
+	}
+	return false
+}
+
+// mergeLine is one line of the merged stream.
+type mergeLine struct {
+	at     time.Time
+	label  string
+	colour int
+	text   string
+}
+
+// mergeFeed tracks what has been taken from one pane. A line joins the
+// stream once it has survived two consecutive polls, which keeps spinners
+// and timers that redraw every poll out of it.
+type mergeFeed struct {
+	colour int
+	prev   []string // the previous poll's lines
+	shown  []string // lines already emitted that are still on screen
+}
+
+// advance takes the pane's latest lines and returns the feed's next state and
+// the lines that have newly settled.
+func (f mergeFeed) advance(cur []string) (mergeFeed, []string) {
+	stable := intersectLines(cur, f.prev)
+	fresh := subtractLines(stable, f.shown)
+	f.prev = cur
+	f.shown = stable
+	return f, fresh
+}
+
+// intersectLines returns the lines of a also in b, as a multiset, in a's order.
+func intersectLines(a, b []string) []string {
+	count := make(map[string]int, len(b))
+	for _, l := range b {
+		count[l]++
+	}
+	var out []string
+	for _, l := range a {
+		if count[l] > 0 {
+			count[l]--
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
+// subtractLines returns the lines of a not in b, as a multiset, in a's order.
+func subtractLines(a, b []string) []string {
+	count := make(map[string]int, len(b))
+	for _, l := range b {
+		count[l]++
+	}
+	var out []string
+	for _, l := range a {
+		if count[l] > 0 {
+			count[l]--
+			continue
+		}
+		out = append(out, l)
+	}
+	return out
+}
+
+// mergeCaptureLines splits a capture into its non-blank lines, without
+// colour codes or trailing spaces.
+func mergeCaptureLines(content string) []string {
+	var out []string
+	for _, l := range strings.Split(ansi.Strip(content), "\n") {
+		l = strings.TrimRight(l, " \t")
+		if strings.TrimSpace(l) != "" {
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
+// groupUnderCursor returns the group under the cursor: the collapsed
+// header it rests on, or the selected session's group. The key is empty if
+// there is none.
+func (m Model) groupUnderCursor() (groupKey, groupName string) {
+	groupKey = m.cursorOnGroup
+	if groupKey == "" {
+		if sel := m.selectedSession(); sel != nil {
+			return m.groupKeyAndName(*sel)
+		}
+		return "", ""
+	}
+	for _, item := range m.viewItems() {
+		if item.isHeader && item.groupKey == groupKey {
+			groupName = item.groupName
+		}
+	}
+	return groupKey, groupName
+}
+
+// openMerge starts the merged output view for the group under the cursor.
+func (m Model) openMerge() (Model, tea.Cmd) {
+	if m.cursorOnGroup == "" && m.selectedSession() == nil {
+		return m, nil
+	}
+	groupKey, groupName := m.groupUnderCursor()
+	if groupKey == "" {
+		_ = m.tmuxClient.DisplayMessage("herd: session is not in a group")
+		return m, nil
+	}
+
+	m.mode = ModeMerge
+	m.mergeGen++
+	m.mergeGroup = groupKey
+	m.mergeGroupName = groupName
+	m.mergeLines = nil
+	m.mergeFeeds = make(map[string]mergeFeed)
+	m.merge = viewport.New(m.width, maxInt(1, m.height-4))
+	return m, m.captureMerge()
+}
+
+// mergePanes returns the panes of the merged group, in sidebar order.
+func (m *Model) mergePanes() []string {
+	return m.groupPanes(m.mergeGroup)
+}
+
+// groupPanes returns the panes of the sessions in group groupKey, in
+// sidebar order.
+func (m Model) groupPanes(groupKey string) []string {
+	var panes []string
+	for _, s := range m.sessions {
+		if key, _ := m.groupKeyAndName(s); key == groupKey {
+			panes = append(panes, s.TmuxPane)
+		}
+	}
+	return panes
+}
+
+// captureMerge polls every pane in the merged group.
+func (m Model) captureMerge() tea.Cmd {
+	client := m.tmuxClient
+	gen := m.mergeGen
+	panes := m.mergePanes()
+	return func() tea.Msg {
+		captures := make(map[string]string, len(panes))
+		for _, p := range panes {
+			if content, err := client.CapturePane(p, mergeScrollback); err == nil {
+				captures[p] = content
+			}
+		}
+		return mergeCaptureMsg{gen: gen, at: time.Now(), panes: panes, captures: captures}
+	}
+}
+
+func tickMerge(gen int) tea.Cmd {
+	return tea.Tick(mergeInterval, func(time.Time) tea.Msg { return mergeTickMsg{gen: gen} })
+}
+
+// applyMergeCapture appends the lines that settled in each pane since the
+// last poll. A pane seen for the first time contributes its last few lines.
+func (m Model) applyMergeCapture(msg mergeCaptureMsg) Model {
+	labels := make(map[string]string, len(m.sessions))
+	for _, s := range m.sessions {
+		labels[s.TmuxPane] = m.displayName(s)
+	}
+	added := false
+	for _, pane := range msg.panes {
+		content, ok := msg.captures[pane]
+		if !ok {
+			continue
+		}
+		cur := mergeCaptureLines(content)
+		feed, seen := m.mergeFeeds[pane]
+		var fresh []string
+		if seen {
+			feed, fresh = feed.advance(cur)
+		} else {
+			feed = mergeFeed{colour: len(m.mergeFeeds) % len(mergeColours), prev: cur, shown: cur}
+			fresh = cur[maxInt(0, len(cur)-mergeInitialLines):]
+		}
+		m.mergeFeeds[pane] = feed
+		for _, text := range fresh {
+			m.mergeLines = append(m.mergeLines, mergeLine{at: msg.at, label: labels[pane], colour: feed.colour, text: text})
+			added = true
+		}
+	}
+	if len(m.mergeLines) > mergeMaxLines {
+		m.mergeLines = m.mergeLines[len(m.mergeLines)-mergeMaxLines:]
+	}
+	if added {
+		atBottom := m.merge.AtBottom()
+		m.merge.SetContent(m.renderMergeLines())
+		if atBottom {
+			m.merge.GotoBottom()
+		}
+	}
+	return m
+}
+
+func (m Model) updateMergeMode(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m = m.recalcLayout()
+		m.merge.Width = m.width
+		m.merge.Height = maxInt(1, m.height-4)
+		m.merge.SetContent(m.renderMergeLines())
+		return m, nil
+
+	case mergeTickMsg:
+		if msg.gen != m.mergeGen {
+			return m, nil
+		}
+		return m, m.captureMerge()
+
+	case mergeCaptureMsg:
+		if msg.gen != m.mergeGen {
+			return m, nil
+		}
+		m = m.applyMergeCapture(msg)
+		return m, tickMerge(m.mergeGen)
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "q":
+			m.mode = ModeNormal
+			m.mergeGen++ // stops the poll chain
+			m.mergeLines = nil
+			m.mergeFeeds = nil
+			m.merge = viewport.Model{}
+			m.lastCapture = ""
+			m.forceViewportRefresh = true
+			return m, nil
+		case "G":
+			m.merge.GotoBottom()
+			return m, nil
+		}
+	}
+	var cmd tea.Cmd
+	m.merge, cmd = m.merge.Update(msg)
+	return m, cmd
+}
+
+// renderMergeLines formats the stream as "time label │ text", labels padded
+// to a common width and coloured per session.
+func (m Model) renderMergeLines() string {
+	width := 0
+	for _, l := range m.mergeLines {
+		width = maxInt(width, lipgloss.Width(l.label))
+	}
+	subtle := lipgloss.NewStyle().Foreground(colSubtle)
+	var sb strings.Builder
+	for i, l := range m.mergeLines {
+		if i > 0 {
+			sb.WriteByte('\n')
+		}
+		label := lipgloss.NewStyle().Foreground(mergeColours[l.colour]).Bold(true).
+			Render(fmt.Sprintf("%-*s", width, l.label))
+		sb.WriteString(subtle.Render(l.at.Format("15:04:05")) + " " + label + subtle.Render(" │ ") + l.text)
+	}
+	return truncateLines(sb.String(), m.merge.Width)
+}
+
+func (m Model) renderMergeView() string {
+	var sb strings.Builder
+	sb.WriteString(styleOverlayTitle.Width(m.width).Render(i18n.Tf("Group: %s — merged output", m.mergeGroupName)) + "\n\n")
+	if len(m.mergeLines) == 0 {
+		sb.WriteString(pickerItemStyle.Render("Waiting for output…") + "\n")
+	} else {
+		sb.WriteString(m.merge.View() + "\n")
+	}
+	sb.WriteString(styleOverlayHelp.Render(i18n.T("[j/k] scroll  [G] follow  [esc] close")))
+	return sb.String()
+}
@@ -0,0 +1,116 @@
+package tui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestMergeFeedEmitsSettledLinesOnce(t *testing.T) {
+	f := mergeFeed{prev: []string{"a", "> "}, shown: []string{"a", "> "}}
+
+	// "b" is new and the spinner ticks: nothing has settled yet.
+	f, fresh := f.advance([]string{"a", "b", "✻ Thinking (1s)", "> "})
+	if len(fresh) != 0 {
+		t.Fatalf("first poll emitted %q, want nothing", fresh)
+	}
+	// "b" survives a second poll; the spinner line never does.
+	f, fresh = f.advance([]string{"a", "b", "✻ Thinking (2s)", "> "})
+	if !reflect.DeepEqual(fresh, []string{"b"}) {
+		t.Fatalf("second poll emitted %q, want [b]", fresh)
+	}
+	_, fresh = f.advance([]string{"a", "b", "✻ Thinking (3s)", "> "})
+	if len(fresh) != 0 {
+		t.Fatalf("third poll emitted %q, want nothing", fresh)
+	}
+}
+
+func TestMergeFeedRepeatedLines(t *testing.T) {
+	f := mergeFeed{prev: []string{"ok"}, shown: []string{"ok"}}
+	f, _ = f.advance([]string{"ok", "ok"})
+	_, fresh := f.advance([]string{"ok", "ok"})
+	if !reflect.DeepEqual(fresh, []string{"ok"}) {
+		t.Fatalf("emitted %q, want the second ok once", fresh)
+	}
+}
+
+func TestApplyMergeCaptureInterleavesPanes(t *testing.T) {
+	sessions := testSessions()[:2]
+	m, fw := newTestModel(t, sessions)
+	defer fw.Close()
+	m.mode = ModeMerge
+	m.mergeGen = 1
+	m.mergeFeeds = make(map[string]mergeFeed)
+	m.merge = viewport.New(m.width, 20)
+
+	t0 := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	poll := func(at time.Time, alpha, beta string) {
+		updated, cmd := m.Update(mergeCaptureMsg{
+			gen:      1,
+			at:       at,
+			panes:    []string{"%1", "%2"},
+			captures: map[string]string{"%1": alpha, "%2": beta},
+		})
+		m = updated.(Model)
+		if cmd == nil {
+			t.Fatal("capture should schedule the next poll")
+		}
+	}
+	poll(t0, "alpha 1\n\n", "beta 1")
+	poll(t0.Add(time.Second), "alpha 1\nalpha 2", "beta 1\nbeta 2")
+	poll(t0.Add(2*time.Second), "alpha 1\nalpha 2", "beta 1\nbeta 2")
+
+	var got []string
+	for _, l := range m.mergeLines {
+		got = append(got, l.at.Format("05")+" "+l.label+" "+l.text)
+	}
+	want := []string{
+		"00 project-alpha alpha 1",
+		"00 project-beta beta 1",
+		"02 project-alpha alpha 2",
+		"02 project-beta beta 2",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("merged lines = %q, want %q", got, want)
+	}
+	if m.mergeFeeds["%1"].colour == m.mergeFeeds["%2"].colour {
+		t.Error("sessions should get distinct colours")
+	}
+	if view := m.View(); !strings.Contains(view, "alpha 2") || !strings.Contains(view, "beta 2") {
+		t.Errorf("view missing merged lines:\n%s", view)
+	}
+
+	// A poll from an earlier opening of the view is dropped.
+	updated, _ := m.Update(mergeCaptureMsg{gen: 0, at: t0, panes: []string{"%1"}, captures: map[string]string{"%1": "stale\nstale"}})
+	if n := len(updated.(Model).mergeLines); n != len(want) {
+		t.Errorf("stale poll changed the stream to %d lines", n)
+	}
+}
+
+func TestMergeUngroupedSessionStaysNormal(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	m, _ = m.openMerge()
+	if m.mode != ModeNormal {
+		t.Fatalf("mode = %d, want normal for an ungrouped session", m.mode)
+	}
+}
+
+func TestMergeEscReturnsToNormal(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	m.mode = ModeMerge
+	m.mergeGen = 3
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	got := updated.(Model)
+	if got.mode != ModeNormal {
+		t.Fatalf("mode = %d, want normal", got.mode)
+	}
+	if got.mergeGen == 3 {
+		t.Error("closing should invalidate the running poll chain")
+	}
+}
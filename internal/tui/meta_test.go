@@ -0,0 +1,110 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/shnupta/herd/internal/state"
+	"github.com/shnupta/herd/internal/tmux/tmuxtest"
+)
+
+func TestApplyStatesCopiesPermissionMode(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+
+	m = m.applyStates([]state.SessionState{
+		{SessionID: "sess-bbb", TmuxPane: "%2", State: "waiting", PermissionMode: "bypassPermissions"},
+	})
+
+	if got := m.sessions[1].PermissionMode; got != "bypassPermissions" {
+		t.Fatalf("PermissionMode = %q, want bypassPermissions", got)
+	}
+	item := ansi.Strip(m.renderSessionItem(1, m.sessions[1], "", false, false))
+	if !strings.Contains(item, "bypass") {
+		t.Errorf("sidebar item missing bypass badge:\n%s", item)
+	}
+}
+
+func TestMetaLineShowsRecordedPrompt(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+
+	m = m.applyStates([]state.SessionState{
+		{SessionID: "sess-aaa", TmuxPane: "%1", State: "waiting", Prompt: "plan the DB migration"},
+		{SessionID: "sess-bbb", TmuxPane: "%2", State: "notifying", Message: "Claude needs your permission to use Bash"},
+	})
+	if got := sessionMeta(m.sessions[0]); got != "waiting: plan the DB migration" {
+		t.Errorf("waiting meta = %q", got)
+	}
+	if got := sessionMeta(m.sessions[1]); got != "Claude needs your permission to use Bash" {
+		t.Errorf("notifying meta = %q", got)
+	}
+}
+
+func TestPermissionBadgeDefaultIsEmpty(t *testing.T) {
+	for _, mode := range []string{"", "default"} {
+		if got := permissionBadge(mode); got != "" {
+			t.Errorf("permissionBadge(%q) = %q, want empty", mode, got)
+		}
+	}
+}
+
+func TestShortModelName(t *testing.T) {
+	tests := map[string]string{
+		"claude-sonnet-4-5-20250929": "sonnet-4-5",
+		"claude-opus-4-1":            "opus-4-1",
+		"":                           "",
+	}
+	for in, want := range tests {
+		if got := shortModelName(in); got != want {
+			t.Errorf("shortModelName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestModelKeySendsSlashModel(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'M'}})
+	m = updated.(Model)
+
+	mock := m.tmuxClient.(*tmuxtest.MockClient)
+	if len(mock.SendKeysCalls) != 1 || !strings.Contains(mock.SendKeysCalls[0], "/model") {
+		t.Errorf("SendKeysCalls = %v, want one /model call", mock.SendKeysCalls)
+	}
+	if !m.insertMode {
+		t.Error("expected insert mode after M")
+	}
+}
+
+func TestContextGauge(t *testing.T) {
+	if got := contextGauge(0); got != "" {
+		t.Errorf("contextGauge(0) = %q, want empty", got)
+	}
+	if got := ansi.Strip(contextGauge(42)); got != "▰▰▱▱▱" {
+		t.Errorf("contextGauge(42) = %q, want ▰▰▱▱▱", got)
+	}
+	if got := ansi.Strip(contextGauge(100)); got != "▰▰▰▰▰" {
+		t.Errorf("contextGauge(100) = %q, want ▰▰▰▰▰", got)
+	}
+}
+
+func TestSessionItemWithBadgesStaysTwoLines(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+
+	s := m.sessions[1] // waiting — the longest meta text
+	s.PermissionMode = "bypassPermissions"
+	s.ContextTokens = 170_000
+	item := ansi.Strip(m.renderSessionItem(1, s, "", false, false))
+	if lines := strings.Count(item, "\n") + 1; lines != 2 {
+		t.Errorf("session item rendered %d lines, want 2:\n%s", lines, item)
+	}
+	if !strings.Contains(item, "bypass") || !strings.Contains(item, "▰▰▰▰▱") {
+		t.Errorf("session item missing badges:\n%s", item)
+	}
+}
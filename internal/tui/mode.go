@@ -11,4 +11,28 @@ const (
 	ModeRename
 	ModeGroupSet
 	ModeWorktree
+	ModeBulkEdit
+	ModePalette
+	ModeActions
+	ModePluginPanel
+	ModeTickets
+	ModeMerge
+	ModeQueue
+	ModeBlockedOn
+	ModeStats
+	ModeSnippetName
+	ModeSnippets
+	ModeSquads
+	ModeMenu
+	ModeTeardown
+	ModeFocusTimer
+	ModeCompose
+	ModeFiles
+	ModeBroadcast
+	ModeSearch
+	ModeActivity
+	ModeOnboarding
+	ModePlanReject
+
+	numModes // sentinel for tests; keep last
 )
@@ -2,19 +2,37 @@ package tui
 
 import (
 	"os"
+	"path/filepath"
 	"sort"
 	"time"
 
 	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/shnupta/herd/internal/alias"
+	"github.com/shnupta/herd/internal/badge"
+	"github.com/shnupta/herd/internal/config"
+	"github.com/shnupta/herd/internal/daemon"
 	"github.com/shnupta/herd/internal/groups"
+	"github.com/shnupta/herd/internal/history"
+	"github.com/shnupta/herd/internal/names"
+	"github.com/shnupta/herd/internal/plugin"
+	"github.com/shnupta/herd/internal/progress"
+	"github.com/shnupta/herd/internal/prompts"
+	"github.com/shnupta/herd/internal/script"
 	"github.com/shnupta/herd/internal/session"
+	"github.com/shnupta/herd/internal/snapshot"
 	"github.com/shnupta/herd/internal/sidebar"
+	"github.com/shnupta/herd/internal/snippets"
 	"github.com/shnupta/herd/internal/state"
+	"github.com/shnupta/herd/internal/tasks"
+	"github.com/shnupta/herd/internal/store"
 	"github.com/shnupta/herd/internal/teams"
+	"github.com/shnupta/herd/internal/testrun"
+	"github.com/shnupta/herd/internal/tickets"
 	"github.com/shnupta/herd/internal/tmux"
 )
 
@@ -47,9 +65,21 @@ type errMsg struct{ err error }
 
 type worktreeLaunchedMsg string
 
+// worktreeCreatedMsg reports a new worktree and the session launched in it.
+type worktreeCreatedMsg struct {
+	pane, path, branch string
+	copied             int   // untracked files copied from the main worktree
+	setupErr           error // copying or starting the post-create command failed
+}
+
 type worktreeRemovedMsg struct{ sessionPane string }
 
-// Model is the root BubbleTea model.
+// Model is the root BubbleTea model. Its state is split into embedded
+// sub-structs by concern — sidebar, output and overlays — so each handler
+// touches a narrow slice of it; fields are promoted, so m.pinned and friends
+// read as before. The filter bar and the one-line prompts are sub-models
+// with their own Update and View: the root routes messages to them and acts
+// on what they return, never the other way round (see overlay.go).
 type Model struct {
 	// Dimensions
 	width  int
@@ -59,49 +89,130 @@ type Model struct {
 	sessions []session.Session
 	selected int
 
-	// Output panel
-	viewport          viewport.Model
-	lastCapture         string // raw content from last capture-pane
-	atBottom            bool   // whether viewport was at the bottom before update
-	pendingGotoBottom   bool   // true after a session switch; forces GotoBottom on next capture
-	forceViewportRefresh bool  // explicit signal to re-render viewport on next capture
-
-	// Input
-	insertMode bool // true when keystrokes are forwarded to the selected pane
+	sidebarState
+	outputState
+	overlayState
 
 	// Modal state
 	mode Mode // current input mode (ModeNormal, ModeReview, etc.)
 
-	// Filter
-	filterInput  textinput.Model    // text input for filter
-	filterQuery  string             // current filter query
-	filtered     []int              // indices of sessions that match filter
+	// Pending selection after new session creation
+	pendingSelectPane   string // pane ID to select after next session discovery
+	pendingQuickRetried bool   // true once the one quick 500ms retry has fired
+
+	// Key aliasing — carries names/groups/pins over when a session's key changes
+	aliases *alias.Index
+
+	// Scratch shell panes, session key → tmux pane ID
+	scratch *store.Store
+
+	// Blocked-on relationships, blocked session key → blocker session key
+	blockers *store.Store
+
+	// Getting-started milestones reached, and whether the panel was
+	// dismissed; setupPending until the startup check is made
+	onboarding   *store.Store
+	setupPending bool
+
+	// Saved output snippets per session key
+	snippets *snippets.Store
+
+	// Prompts sent to each session, by session key
+	prompts *prompts.Store
+
+	// Where `H` saves HTML snapshots
+	snapshotDir string
+
+	// Focus timers by session key, and the length `f` offers first
+	focusTimers  map[string]focusTimer
+	focusMinutes int
+
+	// Recent states per session ID for the sidebar timelines, and how often
+	// herd acted on each in the same window, read from the history log at
+	// historyPath
+	historyPath string
+	timelines   map[string][]string
+	timelineActs map[string]int
+	timelinesAt time.Time
+
+	// Keystrokes forwarded to typedPanes in insert mode and not yet
+	// recorded in the history log (see typeKey)
+	typed      string
+	typedPanes []string
+
+	// The size herd last gave each pane's window, so only changes are
+	// recorded
+	sizedTo map[string]string
+
+	// Panes redrawing after a resize, and the keys held for them
+	settling map[string]*settle
 
-	// Diff review
-	reviewModel *ReviewModel // the review sub-model
+	// Test runs, latest per session key
+	testRuns map[string]*testrun.Run
 
-	// Project picker
-	pickerModel *PickerModel // the picker sub-model
+	// Custom badges from config badge_rules, refreshed with the session list
+	badgeRules []badge.Rule
+	badges     map[string][]badge.Badge
 
-	// Worktree panel
-	worktreeModel *WorktreeModel // the worktree sub-model
+	// Progress read from the output of working sessions, by session key
+	toolProgress map[string]progress.Progress
 
-	// Rename
-	renameInput textinput.Model  // text input for the rename overlay
-	renameKey   string           // session key being renamed
+	// Capture depth and frequency from config capture, reloaded with the
+	// session list
+	capture []config.Capture
 
-	// Group-set
-	groupSetInput textinput.Model // text input for the group name
-	groupSetKey   string          // session key being re-grouped
+	// Terminal alert on urgent transitions, from config urgent_alert
+	urgentAlert string
+	alertStates []string
+
+	// The socket of a herd daemon; while one runs, sessions come from it
+	// and it does the alerting
+	daemonSock string
+
+	// Plugins from ~/.herd/plugins and the badges they last reported
+	plugins      []*plugin.Plugin
+	pluginBadges map[string][]badge.Badge
+
+	// Lua event handlers from ~/.herd/scripts
+	scripts *script.Engine
+
+	// State
+	spinner      spinner.Model
+	stateWatcher state.WatcherIface
+	err          error
+	ready        bool
+
+	// Why the tmux server couldn't be reached, while it can't
+	tmuxLost error
+
+	// Startup preset to apply on the first discovery (nil once applied)
+	preset *config.Preset
+
+	// Tmux client (injected; defaults to *tmux.Client in production)
+	tmuxClient tmux.ClientIface
+}
+
+// sidebarState is the session list's presentation state: filtering,
+// grouping, pinning and ordering.
+type sidebarState struct {
+	// Filter
+	filter      filterBar         // filter input while it is typed into
+	filterQuery string            // current filter query
+	filtered    []int             // indices of sessions that match filter
+	filterHits  map[string]string // pane → label the filter matched, e.g. "name"
+
+	// The hub's sessions, shown read-only under the local ones; hubErr is
+	// why the last fetch from hubURL failed
+	hubURL      string
+	hubSessions []daemon.SessionInfo
+	hubErr      error
 
 	// Session grouping
 	teamsStore      *teams.Store    // reads ~/.claude/teams for auto-grouping
 	collapsedGroups map[string]bool // groupKey → true when collapsed
 	cursorOnGroup   string          // non-empty when cursor rests on a collapsed group header
-
-	// Pending selection after new session creation
-	pendingSelectPane    string // pane ID to select after next session discovery
-	pendingQuickRetried  bool   // true once the one quick 500ms retry has fired
+	groupByTmux     bool            // fall back to grouping by tmux session name
+	staleAfter      time.Duration   // idle this long moves a session to the stale group; 0 never
 
 	// Pinning and ordering (keyed by session key: "session:<id>" or "pane:<id>")
 	pinned       map[string]int // sessionKey -> pin order (lower = pinned earlier)
@@ -109,18 +220,161 @@ type Model struct {
 	savedOrder   []string       // persisted order of session keys
 	sidebarDirty bool           // true if sidebar state needs saving
 
+	// Sessions marked to receive a broadcast prompt, by session key
+	marked map[string]bool
+
 	// Sidebar item cache
 	cachedItems []viewItem
 	itemsDirty  bool
 
-	// State
-	spinner     spinner.Model
-	stateWatcher state.WatcherIface
-	err         error
-	ready       bool
+	// The last frame drawn, and whether to show how long frames take
+	frame          *frameCache
+	showFrameStats bool
 
-	// Tmux client (injected; defaults to *tmux.Client in production)
-	tmuxClient tmux.ClientIface
+	// Leave the state hints out of the help line
+	hideHints bool
+}
+
+// outputState is the right-hand output column: the captured pane viewport
+// and the test panel beneath it.
+type outputState struct {
+	viewport             viewport.Model
+	lastCapture          string // raw content from last capture-pane
+	atBottom             bool   // whether viewport was at the bottom before update
+	pendingGotoBottom    bool   // true after a session switch; forces GotoBottom on next capture
+	forceViewportRefresh bool   // explicit signal to re-render viewport on next capture
+
+	// When the selected pane was last captured, for capture intervals
+	lastCaptureAt time.Time
+
+	// Pane found zoomed or on another client's screen, left unresized
+	sharedPane string
+
+	// When insert mode last found its pane still running Claude
+	insertChecked time.Time
+
+	insertMode    bool // true when keystrokes are forwarded to the selected pane
+	testPanelOpen bool // test output panel shown below the viewport
+
+	// i opens the prompt composer rather than insert mode
+	insertComposer bool
+
+	// Text sent to each pane that no capture has shown yet, drawn over the
+	// output while localEcho is on
+	localEcho bool
+	echoes    map[string]localEcho
+
+	// The group insert mode types into, by key and name; empty when it
+	// types into the selected session alone
+	insertGroup, insertGroupName string
+}
+
+// overlayState holds the sub-models and inputs of the full-screen modes.
+// Only the one matching Model.mode is live at a time.
+type overlayState struct {
+	reviewModel   *ReviewModel   // diff review
+	pickerModel   *PickerModel   // new-session project picker
+	paletteModel  *PaletteModel  // slash-command palette
+	worktreeModel *WorktreeModel // worktree panel
+
+	// Quick actions
+	actions         []config.Action
+	actionsSelected int
+
+	// Ticket picker
+	tickets         []tasks.Ticket
+	ticketsSelected int
+	ticketsLoading  bool
+	ticketsErr      string
+	ticketsDupPane  string // live session in the ticket's project, pending confirmation
+
+	// Session context menu
+	menuItems    []menuItem
+	menuSelected int
+
+	// Squad picker
+	squads         []config.Squad
+	squadsSelected int
+	squadsErr      string
+
+	// Worktree session teardown
+	teardown *teardownPlan
+
+	// One-line prompts: rename, group, focus timer, snippet name and plan
+	// feedback
+	overlay overlay
+
+	// Prompt composer for pane composePane, with files and diffs from
+	// composeDir attached; attachPick is the picker over it when open
+	composeInput    textarea.Model
+	composePane     string
+	composeDir      string
+	composeAttached []attachment
+	composeErr      string
+	attachPick      *attachPicker
+
+	// Broadcast prompt for the marked sessions
+	broadcastInput textarea.Model
+	broadcastErr   string
+
+	// Search through the selected session's output
+	search *outputSearch
+
+	// File browser
+	files *fileBrowser
+
+	// Bulk edit
+	bulkInput textarea.Model // editable name/group/pin buffer for all sessions
+	bulkErr   string         // parse error shown under the buffer
+
+	// Plugin panel
+	pluginPanel      viewport.Model // scrollable text returned by a plugin action
+	pluginPanelTitle string
+
+	// Group merge view
+	merge          viewport.Model
+	mergeGen       int    // bumped per opening; stale polls are dropped
+	mergeGroup     string // group key being merged
+	mergeGroupName string
+	mergeLines     []mergeLine
+	mergeFeeds     map[string]mergeFeed // pane → what has been taken from it
+
+	// Blocked-on picker
+	blockedOnKey      string   // session key being edited
+	blockedOnChoices  []string // "" (not blocked), then the other sessions' keys
+	blockedOnSelected int
+
+	// Snippets: capture-and-name, then the per-session panel
+	snippetKey      string // session key the snippet belongs to
+	snippetList     []snippets.Snippet
+	snippetSelected int
+	snippetView     viewport.Model // preview of the selected snippet
+
+	// Getting-started panel: the milestones as last checked
+	onboardingSteps onboardingMsg
+	onboardingErr   string
+
+	// Activity panel for the session named activityName
+	activityView    viewport.Model
+	activityName    string
+	activityLoading bool
+	activityErr     string
+
+	// Working-time stats
+	statsRows    []statsRow
+	statsLoading bool
+	statsErr     string
+
+	// Attention queue
+	queueView     viewport.Model       // the current session's output
+	queuePane     string               // current session; "" when the queue is empty
+	queueHandled  map[string]time.Time // pane → UpdatedAt when approved or replied to
+	queueSkipped  map[string]bool      // panes skipped this time round
+	queueReplying bool
+	queueInput    textinput.Model
+	queueHistory  []prompts.Entry // prompts sent to the current session
+	queueRecall   int             // index into queueHistory being recalled; len when none
+	queueDraft    string          // what was typed before recalling
 }
 
 const (
@@ -134,25 +388,13 @@ func New(w state.WatcherIface, tc tmux.ClientIface) Model {
 	sp := spinner.New()
 	sp.Spinner = spinner.Dot
 
-	fi := textinput.New()
-	fi.Placeholder = "filter..."
-	fi.CharLimit = 100
-
-	ri := textinput.New()
-	ri.Placeholder = "session name..."
-	ri.CharLimit = 100
-
-	gi := textinput.New()
-	gi.Placeholder = "group name (empty to auto-detect)..."
-	gi.CharLimit = 100
-
 	// Load persisted sidebar state
 	pinned := make(map[string]int)
 	var savedOrder []string
 	var pinCounter int
-	if sidebarState, err := sidebar.Load(); err == nil {
-		pinned = sidebarState.Pinned
-		savedOrder = sidebarState.Order
+	if saved, err := sidebar.Load(); err == nil {
+		pinned = saved.Pinned
+		savedOrder = saved.Order
 		// Find max pin order to set counter
 		for _, order := range pinned {
 			if order > pinCounter {
@@ -166,20 +408,67 @@ func New(w state.WatcherIface, tc tmux.ClientIface) Model {
 	ts := teams.NewStore(home + "/.claude/teams")
 	_ = ts.Load()
 
+	scratch := store.NewStore(filepath.Join(home, ".herd", "scratch.json"))
+	_ = scratch.Load()
+
+	blockers := store.NewStore(filepath.Join(home, ".herd", "blockers.json"))
+	_ = blockers.Load()
+
+	onboarding := store.NewStore(filepath.Join(home, ".herd", "onboarding.json"))
+	_ = onboarding.Load()
+
+	cfg := config.Load()
+
+	// Invalid rules are skipped wholesale, as an invalid config file is.
+	badgeRules, _ := badge.Compile(cfg.BadgeRules)
+
+	// Scripts that fail to load are skipped; the rest still run.
+	scripts, _ := script.Load(script.Dir(), scriptAPI{client: tc, historyPath: history.Path()})
+
 	return Model{
-		spinner:         sp,
-		stateWatcher:    w,
-		atBottom:        true,
-		filterInput:     fi,
-		renameInput:     ri,
-		groupSetInput:   gi,
-		pinned:          pinned,
-		pinCounter:      pinCounter,
-		savedOrder:      savedOrder,
-		teamsStore:      ts,
-		collapsedGroups: make(map[string]bool),
-		itemsDirty:      true,
-		tmuxClient:      tc,
+		sidebarState: sidebarState{
+			filter:          newFilterBar(),
+			teamsStore:      ts,
+			collapsedGroups: map[string]bool{staleGroupKey: true},
+			groupByTmux:     cfg.GroupByTmuxSession,
+			staleAfter:      time.Duration(cfg.StaleAfterMinutes) * time.Minute,
+			pinned:          pinned,
+			pinCounter:      pinCounter,
+			savedOrder:      savedOrder,
+			marked:          make(map[string]bool),
+			itemsDirty:      true,
+			frame:           &frameCache{},
+			showFrameStats:  cfg.FrameStats,
+			hideHints:       cfg.HideHints,
+		},
+		outputState: outputState{
+			atBottom:       true,
+			insertComposer: cfg.InsertComposer,
+			localEcho:      cfg.LocalEcho,
+			echoes:         make(map[string]localEcho),
+		},
+		spinner:      sp,
+		stateWatcher: w,
+		aliases:      alias.NewIndex(filepath.Join(home, ".herd", "keys.json")),
+		scratch:      scratch,
+		blockers:     blockers,
+		onboarding:   onboarding,
+		setupPending: onboarding.Get("dismissed") == "", // the panel opens on its own until dismissed
+		snippets:     snippets.NewStore(snippets.DefaultPath()),
+		prompts:      prompts.NewStore(prompts.DefaultPath()),
+		snapshotDir:  snapshot.DefaultDir(),
+		historyPath:  history.Path(),
+		focusTimers:  make(map[string]focusTimer),
+		sizedTo:      make(map[string]string),
+		settling:     make(map[string]*settle),
+		focusMinutes: cfg.FocusMinutes,
+		badgeRules:   badgeRules,
+		capture:      cfg.Capture,
+		urgentAlert:  cfg.UrgentAlert,
+		alertStates:  cfg.AlertStates,
+		daemonSock:   daemon.SocketPath(),
+		scripts:      scripts,
+		tmuxClient:   tc,
 	}
 }
 
@@ -188,21 +477,34 @@ func (m Model) Init() tea.Cmd {
 		m.discoverSessions(),
 		tickCapture(),
 		tickSessionRefresh(),
+		m.loadTimelines(time.Now()),
 		waitForStateEvent(m.stateWatcher),
+		loadPlugins(),
 		m.spinner.Tick,
 	)
 }
 
 // discoverSessions triggers async session discovery.
 func (m Model) discoverSessions() tea.Cmd {
-	client := m.tmuxClient
+	client, sock := m.tmuxClient, m.daemonSock
 	return func() tea.Msg {
-		sessions, err := session.Discover(client)
-		if err != nil {
-			return errMsg{err}
+		return discover(client, sock)
+	}
+}
+
+// discover takes the session list from the daemon on sock if one runs, as
+// it already keeps one, and else discovers sessions itself.
+func discover(client tmux.ClientIface, sock string) tea.Msg {
+	if daemon.Running(sock) {
+		if snap, err := daemon.Query(sock); err == nil {
+			sessions := make([]session.Session, len(snap.Sessions))
+			for i, info := range snap.Sessions {
+				sessions[i] = info.Session()
+			}
+			return sessionsDiscoveredMsg(sessions)
 		}
-		return sessionsDiscoveredMsg(sessions)
 	}
+	return discovered(session.Discover(client))
 }
 
 // tickCapture returns a command that fires after pollInterval.
@@ -222,13 +524,9 @@ func tickSessionRefresh() tea.Cmd {
 // pendingDiscoveryTick schedules a quick session re-discovery for when a newly
 // created pane hasn't appeared yet (Claude may still be initialising).
 func (m Model) pendingDiscoveryTick() tea.Cmd {
-	client := m.tmuxClient
+	client, sock := m.tmuxClient, m.daemonSock
 	return tea.Tick(pendingDiscoveryInterval, func(t time.Time) tea.Msg {
-		sessions, err := session.Discover(client)
-		if err != nil {
-			return errMsg{err}
-		}
-		return sessionsDiscoveredMsg(sessions)
+		return discover(client, sock)
 	})
 }
 
@@ -329,27 +627,67 @@ func (m *Model) saveSidebarState() {
 		Pinned: m.pinned,
 		Order:  order,
 	}
-	_ = sidebar.Save(state) // Best effort, ignore errors
+	sidebar.SaveLater(state) // Best effort, written in the background
 	m.sidebarDirty = false
 }
 
+// reloadSidebarState picks up the names, groups and pins another process
+// (herd set, the HTTP API) has changed since they were last read.
+func (m *Model) reloadSidebarState() {
+	_ = names.Reload()
+	_ = groups.Reload()
+	saved, changed, err := sidebar.LoadChanged()
+	if err != nil || !changed {
+		return
+	}
+	m.pinned = saved.Pinned
+	for _, order := range m.pinned {
+		m.pinCounter = max(m.pinCounter, order)
+	}
+	m.itemsDirty = true
+}
+
 // ── Group helpers ──────────────────────────────────────────────────────────
 
 // groupKeyAndName returns the group key and human-readable name for a session.
 // Returns ("", "") when the session has no group assignment, meaning it should
 // appear as a flat item with no header in the sidebar.
 //
-// Priority: explicit custom group > agent team membership > flat.
+// Priority: stale (idle past stale_after_minutes and unpinned) > explicit
+// custom group > agent team membership > tmux session (when
+// group_by_tmux_session is set) > flat.
 func (m *Model) groupKeyAndName(s session.Session) (key, name string) {
+	if m.isStale(s) {
+		return staleGroupKey, "stale"
+	}
 	if custom := groups.Get(s.Key()); custom != "" {
 		return "custom:" + custom, custom
 	}
 	if team := m.teamsStore.TeamForSession(s.TmuxPane, s.ID); team != "" {
 		return "team:" + team, team
 	}
+	if m.groupByTmux && s.TmuxSession != "" {
+		return "tmux:" + s.TmuxSession, s.TmuxSession
+	}
 	return "", "" // no group — render flat
 }
 
+// staleGroupKey is the group of sessions idle past staleAfter. It starts
+// collapsed and always comes last.
+const staleGroupKey = "stale:"
+
+// isStale reports whether s has sat idle for staleAfter or longer. Pinned
+// sessions are never stale.
+func (m *Model) isStale(s session.Session) bool {
+	if m.staleAfter <= 0 || s.State != session.StateIdle || s.UpdatedAt.IsZero() {
+		return false
+	}
+	if _, pinned := m.pinned[s.Key()]; pinned {
+		return false
+	}
+	return s.IdleFor() >= m.staleAfter
+}
+
 // worstState returns the highest-priority state from the provided slice.
 // Priority: Working > Waiting > PlanReady > Notifying > Idle > Unknown.
 func worstState(states []session.State) session.State {
@@ -411,28 +749,10 @@ func (m *Model) buildViewItems() []viewItem {
 		groupMap[gKey].sessions = append(groupMap[gKey].sessions, i)
 	}
 
-	emittedGroups := make(map[string]bool)
+	// The stale group goes at the bottom, out of the way.
+	emittedGroups := map[string]bool{staleGroupKey: true}
 	var items []viewItem
-
-	for i, s := range m.sessions {
-		gKey, _ := m.groupKeyAndName(s)
-
-		if gKey == "" {
-			// Ungrouped session — flat item, no header.
-			items = append(items, viewItem{
-				isHeader:   false,
-				sessionIdx: i,
-			})
-			continue
-		}
-
-		// Already emitted all members of this group at the position of the
-		// first member — skip subsequent encounters.
-		if emittedGroups[gKey] {
-			continue
-		}
-		emittedGroups[gKey] = true
-
+	emitGroup := func(gKey string) {
 		g := groupMap[gKey]
 		var states []session.State
 		for _, idx := range g.sessions {
@@ -455,6 +775,30 @@ func (m *Model) buildViewItems() []viewItem {
 			}
 		}
 	}
+
+	for i, s := range m.sessions {
+		gKey, _ := m.groupKeyAndName(s)
+
+		if gKey == "" {
+			// Ungrouped session — flat item, no header.
+			items = append(items, viewItem{
+				isHeader:   false,
+				sessionIdx: i,
+			})
+			continue
+		}
+
+		// Already emitted all members of this group at the position of the
+		// first member — skip subsequent encounters.
+		if emittedGroups[gKey] {
+			continue
+		}
+		emittedGroups[gKey] = true
+		emitGroup(gKey)
+	}
+	if groupMap[staleGroupKey] != nil {
+		emitGroup(staleGroupKey)
+	}
 	return items
 }
 
@@ -719,3 +1063,59 @@ func (m *Model) cleanupSidebarState() {
 		m.sidebarDirty = true
 	}
 }
+
+// migrateKeys carries custom names, groups, pins and saved order over from
+// keys that are no longer live to the sessions that replaced them (see
+// alias.Index.Reconcile). It must run after applyStates so sessions have their
+// IDs, and before cleanupSidebarState would prune the old keys' pins. It
+// returns the old key → new key pairs it migrated.
+func (m *Model) migrateKeys() map[string]string {
+	if m.aliases == nil {
+		return nil
+	}
+	migrations := m.aliases.Reconcile(m.sessions)
+	for oldKey, newKey := range migrations {
+		_ = names.Rename(oldKey, newKey)
+		_ = groups.Rename(oldKey, newKey)
+		_ = tickets.Rename(oldKey, newKey)
+		renameBlockerKey(m.blockers, oldKey, newKey)
+		_ = m.snippets.Rename(oldKey, newKey)
+		_ = m.prompts.Rename(oldKey, newKey)
+		if order, ok := m.pinned[oldKey]; ok {
+			if _, exists := m.pinned[newKey]; !exists {
+				m.pinned[newKey] = order
+			}
+			delete(m.pinned, oldKey)
+		}
+		m.savedOrder = renameInOrder(m.savedOrder, oldKey, newKey)
+		if m.marked[oldKey] {
+			delete(m.marked, oldKey)
+			m.marked[newKey] = true
+		}
+		m.sidebarDirty = true
+		m.itemsDirty = true
+	}
+	return migrations
+}
+
+// renameInOrder replaces oldKey with newKey in order, dropping oldKey instead
+// when newKey is already present so the order never holds duplicates.
+func renameInOrder(order []string, oldKey, newKey string) []string {
+	hasNew := false
+	for _, k := range order {
+		if k == newKey {
+			hasNew = true
+			break
+		}
+	}
+	result := make([]string, 0, len(order))
+	for _, k := range order {
+		switch {
+		case k != oldKey:
+			result = append(result, k)
+		case !hasNew:
+			result = append(result, newKey)
+		}
+	}
+	return result
+}
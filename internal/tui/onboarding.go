@@ -0,0 +1,161 @@
+package tui
+
+import (
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/shnupta/herd/internal/config"
+	"github.com/shnupta/herd/internal/history"
+	"github.com/shnupta/herd/internal/hook"
+	"github.com/shnupta/herd/internal/i18n"
+)
+
+// onboardingMsg carries which setup milestones are met, checked off the UI
+// goroutine. startup is set for the check made once sessions are first
+// discovered, which opens the panel if a step is left.
+type onboardingMsg struct {
+	hooks, sessions, reviewed, config bool
+	startup                           bool
+}
+
+// done reports whether every milestone is met.
+func (o onboardingMsg) done() bool {
+	return o.hooks && o.sessions && o.reviewed && o.config
+}
+
+// hooksInstalled and configExists are variables so tests can stub them.
+var (
+	hooksInstalled = func() bool {
+		cmds, err := hook.InstalledCommands()
+		return err == nil && len(cmds) > 0
+	}
+	configExists = config.Exists
+)
+
+// checkOnboarding checks the setup milestones. A session seen or a review
+// found in the history log is remembered, so neither is looked for again.
+func (m Model) checkOnboarding(startup bool) tea.Cmd {
+	st, path := m.onboarding, m.historyPath
+	if len(m.sessions) > 0 {
+		_ = st.Set("sessions", "true")
+	}
+	return func() tea.Msg {
+		msg := onboardingMsg{
+			hooks:    hooksInstalled(),
+			sessions: st.Get("sessions") != "",
+			reviewed: st.Get("reviewed") != "",
+			config:   configExists(),
+			startup:  startup,
+		}
+		if !msg.reviewed {
+			events, _ := history.ReadSince(path, time.Time{})
+			if slices.ContainsFunc(events, func(ev history.Event) bool { return ev.Kind == history.KindReview }) {
+				msg.reviewed = true
+				_ = st.Set("reviewed", "true")
+			}
+		}
+		return msg
+	}
+}
+
+// onboardingChecked records the milestones met and, on the startup check,
+// opens the panel over the session list if one is left.
+func (m Model) onboardingChecked(msg onboardingMsg) Model {
+	m.onboardingSteps = msg
+	if msg.startup && !msg.done() && m.mode == ModeNormal {
+		m.mode = ModeOnboarding
+	}
+	return m
+}
+
+// openOnboarding opens the getting-started panel and re-checks its steps.
+func (m Model) openOnboarding() (Model, tea.Cmd) {
+	m.mode = ModeOnboarding
+	return m, m.checkOnboarding(false)
+}
+
+func (m Model) updateOnboardingMode(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m = m.recalcLayout()
+		return m, nil
+
+	case tea.KeyMsg:
+		m.onboardingErr = ""
+		switch msg.String() {
+		case "esc", "q":
+			m.mode = ModeNormal
+		case "x":
+			_ = m.onboarding.Set("dismissed", "true")
+			m.mode = ModeNormal
+		case "I":
+			selfPath, _ := os.Executable()
+			if err := hook.Install(selfPath); err != nil {
+				m.onboardingErr = err.Error()
+				return m, nil
+			}
+			return m, m.checkOnboarding(false)
+		case "c":
+			if !configExists() {
+				if err := config.Save(config.DefaultConfig()); err != nil {
+					m.onboardingErr = err.Error()
+					return m, nil
+				}
+			}
+			return m, m.checkOnboarding(false)
+		case "n", "d":
+			// Steps taken from the session list, as there.
+			m.mode = ModeNormal
+			return m.updateNormal(msg)
+		}
+	}
+	return m, nil
+}
+
+func (m Model) renderOnboarding() string {
+	var sb strings.Builder
+	sb.WriteString(styleOverlayTitle.Width(m.width).Render(i18n.T("Getting Started")) + "\n\n")
+
+	o := m.onboardingSteps
+	o.sessions = o.sessions || len(m.sessions) > 0
+	steps := []struct {
+		done       bool
+		label, how string
+	}{
+		{o.hooks, i18n.T("Hooks installed"), i18n.T("[I] install them into ~/.claude/settings.json")},
+		{o.sessions, i18n.T("First session detected"), i18n.T("[n] start Claude in a project")},
+		{o.reviewed, i18n.T("First review submitted"), i18n.T("[d] review the selected session's changes")},
+		{o.config, i18n.T("Config created"), i18n.T("[c] write ~/.herd/config.json with the defaults")},
+	}
+	labelW := 0
+	for _, s := range steps {
+		labelW = max(labelW, lipgloss.Width(s.label))
+	}
+	subtle := lipgloss.NewStyle().Foreground(colSubtle)
+	for _, s := range steps {
+		mark := lipgloss.NewStyle().Foreground(colSubtle).Render("○")
+		how := subtle.Render(s.how)
+		if s.done {
+			mark = lipgloss.NewStyle().Foreground(colGreen).Render("✓")
+			how = ""
+		}
+		line := mark + " " + s.label + strings.Repeat(" ", labelW-lipgloss.Width(s.label)) + "   " + how
+		sb.WriteString(pickerItemStyle.Render(strings.TrimRight(line, " ")) + "\n")
+	}
+	if o.done() {
+		sb.WriteString("\n" + pickerItemStyle.Render(lipgloss.NewStyle().Foreground(colGreen).Render(i18n.T("All set."))) + "\n")
+	}
+	if m.onboardingErr != "" {
+		sb.WriteString("\n" + pickerItemStyle.Render(lipgloss.NewStyle().Foreground(colRed).Render(m.onboardingErr)) + "\n")
+	}
+
+	sb.WriteString("\n" + styleOverlayHelp.Render(i18n.T("[I/n/d/c] take a step  [x] don't show again  [esc] close  ·  [O] reopens this")))
+	return sb.String()
+}
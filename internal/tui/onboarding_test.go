@@ -0,0 +1,60 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shnupta/herd/internal/history"
+)
+
+func TestOnboardingOpensUntilDismissed(t *testing.T) {
+	hooks, cfg := true, false
+	defer func(h func() bool, c func() bool) { hooksInstalled, configExists = h, c }(hooksInstalled, configExists)
+	hooksInstalled = func() bool { return hooks }
+	configExists = func() bool { return cfg }
+
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	result, _ := m.Update(m.checkOnboarding(true)())
+	m = result.(Model)
+	if m.mode != ModeOnboarding {
+		t.Fatalf("mode = %v, want the panel open with steps left", m.mode)
+	}
+	view := m.renderOnboarding()
+	for _, want := range []string{"✓ Hooks installed", "✓ First session detected", "○ First review submitted", "[d] review"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("panel lacks %q:\n%s", want, view)
+		}
+	}
+
+	// A review in the history log counts, and is remembered.
+	if err := history.AppendTo(m.historyPath, history.Event{Time: time.Now(), Kind: history.KindReview}); err != nil {
+		t.Fatal(err)
+	}
+	cfg = true
+	result, _ = m.Update(m.checkOnboarding(false)())
+	m = result.(Model)
+	if !m.onboardingSteps.done() || m.onboarding.Get("reviewed") == "" {
+		t.Errorf("steps = %+v, want all done", m.onboardingSteps)
+	}
+
+	m = pressKey(t, m, "x")
+	if m.mode != ModeNormal || m.onboarding.Get("dismissed") == "" {
+		t.Errorf("x left mode %v, dismissed %q", m.mode, m.onboarding.Get("dismissed"))
+	}
+}
+
+func TestOnboardingStaysShutWhenDone(t *testing.T) {
+	defer func(h func() bool, c func() bool) { hooksInstalled, configExists = h, c }(hooksInstalled, configExists)
+	hooksInstalled = func() bool { return true }
+	configExists = func() bool { return true }
+
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	_ = m.onboarding.Set("reviewed", "true")
+	result, _ := m.Update(m.checkOnboarding(true)())
+	if m = result.(Model); m.mode != ModeNormal {
+		t.Errorf("mode = %v, want the panel left shut", m.mode)
+	}
+}
@@ -0,0 +1,159 @@
+package tui
+
+import (
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/shnupta/herd/internal/groups"
+	"github.com/shnupta/herd/internal/names"
+	"github.com/shnupta/herd/internal/snippets"
+)
+
+// overlay is a mode that owns its state, such as the prompt for a session's
+// name. The root Model routes messages to the live overlay and draws its
+// View; once it has a Result the root closes it and handles the result. An
+// overlay can't reach the root Model, so what it may ask of it is spelled
+// out by the result types below.
+type overlay interface {
+	Update(msg tea.Msg) (overlay, tea.Cmd)
+	View(width, height int) string
+
+	// Result is nil while the overlay is open, then what it asks of the
+	// root; overlayCancelledMsg if it was dismissed.
+	Result() tea.Msg
+}
+
+// Results of the overlays.
+type (
+	overlayCancelledMsg struct{}
+
+	renameMsg     struct{ key, name string }  // "" clears the name
+	groupSetMsg   struct{ key, group string } // "" returns to the detected group
+	planRejectMsg struct{ pane, feedback string }
+
+	// focusSetMsg sets key's focus timer; 0 minutes stops it.
+	focusSetMsg struct {
+		key     string
+		minutes int
+	}
+
+	snippetSaveMsg struct{ key, name, text string }
+)
+
+// updateOverlay routes msg to the live overlay, handling its result once it
+// has one.
+func (m Model) updateOverlay(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.overlay == nil {
+		m.mode = ModeNormal
+		return m.updateNormal(msg)
+	}
+	if msg, ok := msg.(tea.WindowSizeMsg); ok {
+		m.width = msg.Width
+		m.height = msg.Height
+		m = m.recalcLayout()
+	}
+	next, cmd := m.overlay.Update(msg)
+	m.overlay = next
+	res := next.Result()
+	if res == nil {
+		return m, cmd
+	}
+	m.overlay = nil
+	m.mode = ModeNormal
+	return m.overlayDone(res), cmd
+}
+
+// overlayDone carries out what a closed overlay asked for.
+func (m Model) overlayDone(res tea.Msg) Model {
+	switch res := res.(type) {
+	case renameMsg:
+		if res.name == "" {
+			_ = names.Delete(res.key)
+		} else {
+			_ = names.Set(res.key, res.name)
+		}
+		m.itemsDirty = true
+	case groupSetMsg:
+		_ = groups.Set(res.key, res.group)
+		m.itemsDirty = true
+	case focusSetMsg:
+		m.setFocusTimer(res.key, res.minutes)
+	case snippetSaveMsg:
+		err := m.snippets.Add(res.key, snippets.Snippet{Name: res.name, Text: res.text, CreatedAt: time.Now()})
+		if err != nil {
+			m.err = err
+		}
+	case planRejectMsg:
+		m.rejectPlan(res.pane, res.feedback)
+	}
+	return m
+}
+
+// openPrompt shows p in mode, focusing its input.
+func (m Model) openPrompt(mode Mode, p promptOverlay) (Model, tea.Cmd) {
+	cmd := p.input.Focus()
+	m.overlay = p
+	m.mode = mode
+	return m, cmd
+}
+
+// promptOverlay asks for one line of text: full-screen under title, or as
+// a bar in place of the help line when title is empty. On enter, parse
+// turns the trimmed text into the overlay's result or an error to show.
+type promptOverlay struct {
+	title   string
+	input   textinput.Model
+	suffix  string // after the input, e.g. " minutes"
+	preview string // shown under the input, cut to fit
+	help    string
+	parse   func(text string) (tea.Msg, error)
+
+	err    error
+	result tea.Msg
+}
+
+func (p promptOverlay) Update(msg tea.Msg) (overlay, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "esc":
+			p.result = overlayCancelledMsg{}
+			return p, nil
+		case "enter":
+			res, err := p.parse(strings.TrimSpace(p.input.Value()))
+			if err != nil {
+				p.err = err
+				return p, nil
+			}
+			p.result = res
+			return p, nil
+		}
+	}
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(msg)
+	return p, cmd
+}
+
+func (p promptOverlay) Result() tea.Msg { return p.result }
+
+func (p promptOverlay) View(width, height int) string {
+	if p.title == "" {
+		return styleHelpFilter.Width(width).Render("  " + p.input.View() + "  " + p.help)
+	}
+	var sb strings.Builder
+	sb.WriteString(styleOverlayTitle.Width(width).Render(p.title) + "\n\n")
+	sb.WriteString(styleOverlayInput.Render(p.input.View()+p.suffix) + "\n\n")
+	if p.preview != "" {
+		lines := strings.Split(p.preview, "\n")
+		preview := strings.Join(lines[:minInt(len(lines), maxInt(1, height-8))], "\n")
+		sb.WriteString(pickerItemStyle.Render(lipgloss.NewStyle().Foreground(colSubtext).Render(truncateLines(preview, width-4))) + "\n\n")
+	}
+	if p.err != nil {
+		sb.WriteString(lipgloss.NewStyle().Foreground(colRed).Render(p.err.Error()) + "\n\n")
+	}
+	sb.WriteString(styleOverlayHelp.Render(p.help))
+	return sb.String()
+}
@@ -0,0 +1,53 @@
+package tui
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// promptOf returns m's open prompt, failing the test without one.
+func promptOf(t *testing.T, m Model) promptOverlay {
+	t.Helper()
+	p, ok := m.overlay.(promptOverlay)
+	if !ok {
+		t.Fatalf("overlay = %T, want a prompt", m.overlay)
+	}
+	return p
+}
+
+// setPrompt replaces the text in m's open prompt.
+func setPrompt(t *testing.T, m Model, text string) Model {
+	t.Helper()
+	p := promptOf(t, m)
+	p.input.SetValue(text)
+	m.overlay = p
+	return m
+}
+
+func TestPromptOverlayResults(t *testing.T) {
+	p := promptOverlay{
+		input: textinput.New(),
+		parse: func(text string) (tea.Msg, error) {
+			if text == "bad" {
+				return nil, errors.New("no")
+			}
+			return renameMsg{key: "k", name: text}, nil
+		},
+	}
+	p.input.SetValue(" bad ")
+	next, _ := p.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if next.Result() != nil || next.(promptOverlay).err == nil {
+		t.Fatalf("result %v, err %v; want the prompt kept with the error", next.Result(), next.(promptOverlay).err)
+	}
+
+	p.input.SetValue(" good ")
+	if next, _ = p.Update(tea.KeyMsg{Type: tea.KeyEnter}); next.Result() != (renameMsg{key: "k", name: "good"}) {
+		t.Errorf("result = %#v, want the trimmed text parsed", next.Result())
+	}
+	if next, _ = p.Update(tea.KeyMsg{Type: tea.KeyEsc}); next.Result() != (overlayCancelledMsg{}) {
+		t.Errorf("esc result = %#v, want cancelled", next.Result())
+	}
+}
@@ -0,0 +1,180 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/shnupta/herd/internal/i18n"
+	"github.com/shnupta/herd/internal/slash"
+)
+
+// PaletteModel is a filterable picker of slash commands to send to the
+// selected session.
+type PaletteModel struct {
+	textinput textinput.Model
+	commands  []slash.Command
+	filtered  []slash.Command
+	selected  int
+	width     int
+	height    int
+
+	// Result
+	chosen    string
+	cancelled bool
+}
+
+// NewPaletteModel creates a palette listing the given commands.
+func NewPaletteModel(commands []slash.Command, width, height int) PaletteModel {
+	ti := textinput.New()
+	ti.Placeholder = i18n.T("Search commands...")
+	ti.Focus()
+	ti.CharLimit = 256
+	ti.Width = min(50, width-10)
+
+	return PaletteModel{
+		textinput: ti,
+		commands:  commands,
+		filtered:  commands,
+		width:     width,
+		height:    height,
+	}
+}
+
+func (m PaletteModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m PaletteModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.textinput.Width = min(50, m.width-10)
+
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, pickerKeys.Cancel):
+			m.cancelled = true
+			return m, nil
+
+		case key.Matches(msg, pickerKeys.Select):
+			// Typed arguments after a matching command are kept, so
+			// "/review 123" sends "/review 123".
+			input := strings.TrimSpace(m.textinput.Value())
+			if len(m.filtered) > 0 && m.selected < len(m.filtered) {
+				m.chosen = m.filtered[m.selected].Name
+				if name, args, ok := strings.Cut(input, " "); ok && name == m.chosen {
+					m.chosen += " " + strings.TrimSpace(args)
+				}
+			} else if strings.HasPrefix(input, "/") {
+				m.chosen = input
+			}
+			return m, nil
+
+		case key.Matches(msg, pickerKeys.Up):
+			if m.selected > 0 {
+				m.selected--
+			}
+			return m, nil
+
+		case key.Matches(msg, pickerKeys.Down):
+			if m.selected < len(m.filtered)-1 {
+				m.selected++
+			}
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.textinput, cmd = m.textinput.Update(msg)
+	m.filterCommands()
+	return m, cmd
+}
+
+// filterCommands matches the first word of the input against command names
+// and the whole input against descriptions.
+func (m *PaletteModel) filterCommands() {
+	input := strings.ToLower(strings.TrimSpace(m.textinput.Value()))
+	if input == "" {
+		m.filtered = m.commands
+	} else {
+		word, _, _ := strings.Cut(input, " ")
+		m.filtered = nil
+		for _, c := range m.commands {
+			name := strings.ToLower(c.Name)
+			if strings.Contains(name, strings.TrimPrefix(word, "/")) || strings.Contains(strings.ToLower(c.Description), input) {
+				m.filtered = append(m.filtered, c)
+			}
+		}
+	}
+	if m.selected >= len(m.filtered) {
+		m.selected = max(0, len(m.filtered)-1)
+	}
+}
+
+func (m PaletteModel) View() string {
+	var sb strings.Builder
+
+	sb.WriteString(pickerTitleStyle.Width(m.width).Render(i18n.T("Slash Commands")) + "\n\n")
+	sb.WriteString(pickerInputStyle.Render(m.textinput.View()) + "\n\n")
+
+	maxVisible := m.height - 8
+	if maxVisible < 3 {
+		maxVisible = 3
+	}
+	start := 0
+	if m.selected >= maxVisible {
+		start = m.selected - maxVisible + 1
+	}
+	end := min(start+maxVisible, len(m.filtered))
+
+	nameW := 0
+	for _, c := range m.filtered {
+		nameW = max(nameW, lipgloss.Width(c.Name))
+	}
+	descStyle := lipgloss.NewStyle().Foreground(colSubtext)
+	sourceStyle := lipgloss.NewStyle().Foreground(colSubtle)
+
+	if len(m.filtered) == 0 {
+		if strings.HasPrefix(strings.TrimSpace(m.textinput.Value()), "/") {
+			sb.WriteString(pickerItemStyle.Render("▸ "+i18n.T("send as typed")) + "\n")
+		} else {
+			sb.WriteString(pickerItemStyle.Render(i18n.T("No matching commands")) + "\n")
+		}
+	}
+	for i := start; i < end; i++ {
+		c := m.filtered[i]
+		line := c.Name + strings.Repeat(" ", nameW-lipgloss.Width(c.Name)) + "  "
+		if i == m.selected {
+			line += c.Description
+			if c.Source != "built-in" {
+				line += "  (" + c.Source + ")"
+			}
+			sb.WriteString(pickerSelectedStyle.Width(m.width-4).Render("▸ "+line) + "\n")
+			continue
+		}
+		line += descStyle.Render(c.Description)
+		if c.Source != "built-in" {
+			line += "  " + sourceStyle.Render("("+c.Source+")")
+		}
+		sb.WriteString(pickerItemStyle.Render("  "+line) + "\n")
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(pickerHelpStyle.Render(i18n.T("[↑/↓] navigate  [enter] send  [esc] cancel  (type args after the command name)")))
+	return sb.String()
+}
+
+// Chosen returns the command line to send, empty if none.
+func (m PaletteModel) Chosen() string {
+	return m.chosen
+}
+
+// Cancelled returns true if the palette was cancelled.
+func (m PaletteModel) Cancelled() bool {
+	return m.cancelled
+}
@@ -0,0 +1,65 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/shnupta/herd/internal/slash"
+	"github.com/shnupta/herd/internal/tmux/tmuxtest"
+)
+
+func typeInto(m tea.Model, s string) tea.Model {
+	for _, r := range s {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	return m
+}
+
+func TestPaletteFilterAndArgs(t *testing.T) {
+	cmds := []slash.Command{
+		{Name: "/compact", Description: "Summarise", Source: "built-in"},
+		{Name: "/review", Description: "Review a pull request", Source: "built-in"},
+	}
+	var m tea.Model = NewPaletteModel(cmds, 80, 24)
+	m = typeInto(m, "/rev 42")
+	pm := m.(PaletteModel)
+	if len(pm.filtered) != 1 || pm.filtered[0].Name != "/review" {
+		t.Fatalf("filtered = %+v, want only /review", pm.filtered)
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	// Args are only kept when the typed word is the full command name.
+	if got := m.(PaletteModel).Chosen(); got != "/review" {
+		t.Errorf("Chosen() = %q, want /review", got)
+	}
+
+	m = NewPaletteModel(cmds, 80, 24)
+	m = typeInto(m, "/review 42")
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if got := m.(PaletteModel).Chosen(); got != "/review 42" {
+		t.Errorf("Chosen() = %q, want \"/review 42\"", got)
+	}
+}
+
+func TestPaletteSendsToSelectedPane(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+
+	var tm tea.Model = m
+	tm, _ = tm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{':'}})
+	if tm.(Model).mode != ModePalette {
+		t.Fatalf("expected ModePalette after :, got %d", tm.(Model).mode)
+	}
+	tm = typeInto(tm, "compact")
+	tm, _ = tm.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	final := tm.(Model)
+	if final.mode != ModeNormal {
+		t.Errorf("expected ModeNormal after sending, got %d", final.mode)
+	}
+	mock := final.tmuxClient.(*tmuxtest.MockClient)
+	if len(mock.SendKeysCalls) != 1 || mock.SendKeysCalls[0] != "%1:/compact" {
+		t.Errorf("SendKeysCalls = %v, want [%%1:/compact]", mock.SendKeysCalls)
+	}
+}
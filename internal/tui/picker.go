@@ -3,6 +3,7 @@ package tui
 import (
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strings"
 
@@ -10,11 +11,25 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 
 	"github.com/shnupta/herd/internal/config"
+	"github.com/shnupta/herd/internal/i18n"
+	"github.com/shnupta/herd/internal/names"
+	"github.com/shnupta/herd/internal/session"
 	"github.com/shnupta/herd/internal/tmux"
 )
 
+// previewLines is how many of a running session's last output lines the
+// picker shows.
+const previewLines = 5
+
+// pickerPreviewMsg carries the last output lines of running sessions, by
+// pane, for the picker's previews.
+type pickerPreviewMsg map[string][]string
+
+func isPickerPreviewMsg(msg tea.Msg) bool { _, ok := msg.(pickerPreviewMsg); return ok }
+
 // PickerModel is a project picker for creating new sessions.
 type PickerModel struct {
 	textinput textinput.Model
@@ -24,24 +39,40 @@ type PickerModel struct {
 	width     int
 	height    int
 
+	// Sessions already running in a project, by path, and their last
+	// output lines by pane. Projects in multi are configured for several
+	// sessions, so enter launches another there rather than switching.
+	existing map[string][]session.Session
+	previews pickerPreviewMsg
+	multi    map[string]bool
+
+	// Configured session templates and the one chosen, -1 for none
+	templates []config.Template
+	template  int
+
 	// Result
 	chosenPath string
+	switchPane string
 	cancelled  bool
 }
 
 // PickerKeyMap defines key bindings for the picker.
 type PickerKeyMap struct {
-	Up     key.Binding
-	Down   key.Binding
-	Select key.Binding
-	Cancel key.Binding
+	Up      key.Binding
+	Down    key.Binding
+	Select  key.Binding
+	Another  key.Binding
+	Template key.Binding
+	Cancel   key.Binding
 }
 
 var pickerKeys = PickerKeyMap{
-	Up:     key.NewBinding(key.WithKeys("up", "ctrl+p")),
-	Down:   key.NewBinding(key.WithKeys("down", "ctrl+n")),
-	Select: key.NewBinding(key.WithKeys("enter")),
-	Cancel: key.NewBinding(key.WithKeys("esc", "ctrl+c")),
+	Up:       key.NewBinding(key.WithKeys("up", "ctrl+p")),
+	Down:     key.NewBinding(key.WithKeys("down", "ctrl+n")),
+	Select:   key.NewBinding(key.WithKeys("enter")),
+	Another:  key.NewBinding(key.WithKeys("tab")),
+	Template: key.NewBinding(key.WithKeys("ctrl+t")),
+	Cancel:   key.NewBinding(key.WithKeys("esc", "ctrl+c")),
 }
 
 var (
@@ -71,10 +102,12 @@ var (
 			PaddingLeft(1)
 )
 
-// NewPickerModel creates a new project picker.
-func NewPickerModel(existingPaths []string) PickerModel {
+// NewPickerModel creates a new project picker. Projects of the running
+// sessions are listed first-class, and choosing one offers to switch to its
+// session rather than launch another.
+func NewPickerModel(sessions []session.Session) PickerModel {
 	ti := textinput.New()
-	ti.Placeholder = "Search projects..."
+	ti.Placeholder = i18n.T("Search projects...")
 	ti.Focus()
 	ti.CharLimit = 256
 	ti.Width = 50
@@ -82,8 +115,14 @@ func NewPickerModel(existingPaths []string) PickerModel {
 	// Deduplicate and sort paths
 	seen := make(map[string]bool)
 	var projects []string
-	for _, p := range existingPaths {
-		if p != "" && !seen[p] {
+	existing := make(map[string][]session.Session)
+	for _, s := range sessions {
+		p := s.ProjectPath
+		if p == "" {
+			continue
+		}
+		existing[p] = append(existing[p], s)
+		if !seen[p] {
 			seen[p] = true
 			projects = append(projects, p)
 		}
@@ -91,6 +130,10 @@ func NewPickerModel(existingPaths []string) PickerModel {
 
 	// Load config and scan configured project directories
 	cfg := config.Load()
+	multi := make(map[string]bool)
+	for p := range existing {
+		multi[p] = cfg.MultiSessionFor(p)
+	}
 	for _, dir := range cfg.GetProjectDirs() {
 		if entries, err := os.ReadDir(dir); err == nil {
 			for _, e := range entries {
@@ -111,9 +154,48 @@ func NewPickerModel(existingPaths []string) PickerModel {
 		textinput: ti,
 		projects:  projects,
 		filtered:  projects,
+		existing:  existing,
+		multi:     multi,
+		templates: cfg.Templates,
+		template:  -1,
+	}
+}
+
+// fetchPickerPreviews captures the visible screen of each running session
+// for the picker's previews.
+func fetchPickerPreviews(sessions []session.Session, client tmux.ClientIface) tea.Cmd {
+	if len(sessions) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		previews := make(pickerPreviewMsg)
+		for _, s := range sessions {
+			out, err := client.CapturePane(s.TmuxPane, 0)
+			if err != nil {
+				continue
+			}
+			lines := strings.Split(cleanCapture(out), "\n")
+			lines = lines[max(0, len(lines)-previewLines):]
+			for i, l := range lines {
+				lines[i] = strings.TrimRight(ansi.Strip(l), " ")
+			}
+			previews[s.TmuxPane] = lines
+		}
+		return previews
 	}
 }
 
+// highlighted returns the path enter would act on, empty if none.
+func (m PickerModel) highlighted() string {
+	if customPath := m.getCustomPath(); customPath != "" {
+		return customPath
+	}
+	if m.isCustomPathMode() || m.selected >= len(m.filtered) {
+		return ""
+	}
+	return m.filtered[m.selected]
+}
+
 func (m PickerModel) Init() tea.Cmd {
 	return textinput.Blink
 }
@@ -127,6 +209,10 @@ func (m PickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 		m.textinput.Width = min(50, m.width-10)
 
+	case pickerPreviewMsg:
+		m.previews = msg
+		return m, nil
+
 	case tea.KeyMsg:
 		switch {
 		case key.Matches(msg, pickerKeys.Cancel):
@@ -134,14 +220,24 @@ func (m PickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 
 		case key.Matches(msg, pickerKeys.Select):
-			// Check if input is a custom path
-			if customPath := m.getCustomPath(); customPath != "" {
-				m.chosenPath = customPath
-			} else if len(m.filtered) > 0 && m.selected < len(m.filtered) {
-				m.chosenPath = m.filtered[m.selected]
+			// A project with a session already running switches to it,
+			// guarding against duplicate agents on one repo. A template
+			// always starts a session, being new work.
+			path := m.highlighted()
+			if running := m.existing[path]; len(running) > 0 && !m.multi[path] && m.template < 0 {
+				m.switchPane = running[0].TmuxPane
+			} else {
+				m.chosenPath = path
 			}
 			return m, nil
 
+		case key.Matches(msg, pickerKeys.Another):
+			m.chosenPath = m.highlighted()
+			return m, nil
+
+		case key.Matches(msg, pickerKeys.Template):
+			return m.nextTemplate(), nil
+
 		case key.Matches(msg, pickerKeys.Up):
 			if m.selected > 0 {
 				m.selected--
@@ -190,15 +286,22 @@ func (m PickerModel) View() string {
 	var sb strings.Builder
 
 	// Title
-	title := pickerTitleStyle.Width(m.width).Render("New Session — Select Project")
+	title := pickerTitleStyle.Width(m.width).Render(i18n.T("New Session — Select Project"))
 	sb.WriteString(title + "\n\n")
 
 	// Search input
 	input := pickerInputStyle.Render(m.textinput.View())
 	sb.WriteString(input + "\n\n")
+	if m.template >= 0 {
+		sb.WriteString(m.renderTemplate() + "\n\n")
+	}
 
-	// Project list
+	// Project list, leaving room for a preview of a running session
+	running := m.existing[m.highlighted()]
 	maxVisible := m.height - 8
+	if len(running) > 0 {
+		maxVisible -= previewLines + 2
+	}
 	if maxVisible < 3 {
 		maxVisible = 3
 	}
@@ -220,15 +323,15 @@ func (m PickerModel) View() string {
 			Foreground(lipgloss.Color("#10B981")).
 			Bold(true).
 			PaddingLeft(2)
-		sb.WriteString(customStyle.Render("▸ " + shortenPath(customPath) + " (custom)") + "\n")
+		sb.WriteString(customStyle.Render("▸ " + shortenPath(customPath) + " " + i18n.T("(custom)")) + "\n")
 	} else if m.isCustomPathMode() {
 		// Input looks like a path but isn't valid
 		invalidStyle := lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#EF4444")).
 			PaddingLeft(2)
-		sb.WriteString(invalidStyle.Render("  Invalid directory path") + "\n")
+		sb.WriteString(invalidStyle.Render("  " + i18n.T("Invalid directory path")) + "\n")
 	} else if len(m.filtered) == 0 {
-		sb.WriteString(pickerItemStyle.Render("No matching projects") + "\n")
+		sb.WriteString(pickerItemStyle.Render(i18n.T("No matching projects")) + "\n")
 	} else {
 		for i := start; i < end; i++ {
 			p := m.filtered[i]
@@ -242,22 +345,109 @@ func (m PickerModel) View() string {
 		}
 	}
 
+	if len(running) > 0 {
+		sb.WriteString("\n" + m.renderPreview(running))
+	}
+
 	// Help
 	sb.WriteString("\n")
-	helpText := "[↑/↓] navigate  [enter] select  [esc] cancel"
+	helpText := i18n.T("[↑/↓] navigate  [enter] select  [esc] cancel")
+	if len(running) > 0 && m.template < 0 {
+		helpText = i18n.T("[↑/↓] navigate  [enter] switch to existing  [tab] launch another  [esc] cancel")
+		if m.multi[m.highlighted()] {
+			helpText = i18n.T("[↑/↓] navigate  [enter] launch another (multi-session project)  [esc] cancel")
+		}
+	}
 	if !m.isCustomPathMode() {
-		helpText += "  [type path] custom dir"
+		helpText += "  " + i18n.T("[type path] custom dir")
+	}
+	if len(m.templates) > 0 {
+		helpText += "  " + i18n.T("[ctrl+t] template")
 	}
 	sb.WriteString(pickerHelpStyle.Render(helpText))
 
 	return sb.String()
 }
 
+// nextTemplate chooses the next template, or none after the last. One with
+// a project of its own puts that project in the search as a custom path.
+func (m PickerModel) nextTemplate() PickerModel {
+	if len(m.templates) == 0 {
+		return m
+	}
+	m.template++
+	if m.template == len(m.templates) {
+		m.template = -1
+		return m
+	}
+	if p := m.templates[m.template].Project; p != "" {
+		m.textinput.SetValue(p)
+		m.textinput.CursorEnd()
+		m.filterProjects()
+	}
+	return m
+}
+
+// renderTemplate describes the chosen template: its extra flags and the
+// start of its opening prompt.
+func (m PickerModel) renderTemplate() string {
+	t := m.templates[m.template]
+	line := i18n.T("Template: ") + t.Name
+	if t.Flags != "" {
+		line += "  claude " + t.Flags
+	}
+	if t.Prompt != "" {
+		line += "  “" + strings.Join(strings.Fields(t.Prompt), " ") + "”"
+	}
+	style := lipgloss.NewStyle().Foreground(colAmber).PaddingLeft(2)
+	return style.Render(ansi.Truncate(line, max(10, m.width-4), "…"))
+}
+
+// renderPreview shows the first running session of the highlighted project:
+// its state and last few output lines, noting any more sessions there.
+func (m PickerModel) renderPreview(running []session.Session) string {
+	s := running[0]
+	subtle := lipgloss.NewStyle().Foreground(colSubtle)
+	head := stateIcon(s.State.String()) + " " + stateLabel(s.State.String(), s.CurrentTool) + "  " + subtle.Render(s.TmuxPane)
+	if name := names.Get(s.Key()); name != "" {
+		head += "  " + name
+	}
+	if len(running) > 1 {
+		head += subtle.Render("  " + i18n.Tf("+%d more running here", len(running)-1))
+	}
+	var sb strings.Builder
+	sb.WriteString(pickerItemStyle.Render(i18n.T("Already running: ")+head) + "\n")
+	lines, ok := m.previews[s.TmuxPane]
+	if !ok {
+		lines = []string{"…"}
+	}
+	width := max(10, m.width-8)
+	for _, l := range lines {
+		sb.WriteString(pickerItemStyle.Render(subtle.Render("│ "+ansi.Truncate(l, width, "…"))) + "\n")
+	}
+	return sb.String()
+}
+
 // ChosenPath returns the selected project path, empty if none.
 func (m PickerModel) ChosenPath() string {
 	return m.chosenPath
 }
 
+// SwitchPane returns the pane of the running session chosen instead of a
+// new one, empty if none.
+func (m PickerModel) SwitchPane() string {
+	return m.switchPane
+}
+
+// Template returns the template chosen to start the session with, nil if
+// none.
+func (m PickerModel) Template() *config.Template {
+	if m.template < 0 {
+		return nil
+	}
+	return &m.templates[m.template]
+}
+
 // Cancelled returns true if the picker was cancelled.
 func (m PickerModel) Cancelled() bool {
 	return m.cancelled
@@ -266,19 +456,65 @@ func (m PickerModel) Cancelled() bool {
 // LaunchSession creates a new tmux window with claude in the given directory.
 // Returns the new pane ID on success.
 func LaunchSession(projectPath string, client tmux.ClientIface) (string, error) {
+	return LaunchSessionWithPrompt(projectPath, "", client)
+}
+
+// LaunchSessionWithPrompt is LaunchSession with an opening prompt passed to
+// claude on its command line, so it starts work without waiting for input.
+func LaunchSessionWithPrompt(projectPath, prompt string, client tmux.ClientIface) (string, error) {
+	return LaunchSessionFromTemplate(projectPath, config.Template{Prompt: prompt}, client)
+}
+
+// LaunchSessionFromTemplate is LaunchSession with t's flags added to the
+// claude command line and its prompt as the opening prompt.
+func LaunchSessionFromTemplate(projectPath string, t config.Template, client tmux.ClientIface) (string, error) {
 	sess, err := client.CurrentSession()
 	if err != nil {
 		return "", err
 	}
 
-	// Build claude command with config options
-	cmd := "claude"
-	cfg := config.Load()
+	return client.NewWindow(sess, projectPath, claudeCommand(config.Load(), projectPath, t))
+}
+
+// claudeCommand is the shell command that starts Claude in projectPath: the
+// project's launch command and flags, then the template's, with the launch
+// environment set in front.
+func claudeCommand(cfg config.Config, projectPath string, t config.Template) string {
+	launch := cfg.LaunchFor(projectPath)
+
+	var parts []string
+	keys := make([]string, 0, len(launch.Env))
+	for k := range launch.Env {
+		if envName.MatchString(k) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		parts = append(parts, k+"="+shellQuote(launch.Env[k]))
+	}
+
+	parts = append(parts, launch.Command)
 	if cfg.DangerouslySkipPermissions {
-		cmd = "claude --dangerously-skip-permissions"
+		parts = append(parts, "--dangerously-skip-permissions")
+	}
+	for _, flags := range []string{launch.Flags, t.Flags} {
+		if flags != "" {
+			parts = append(parts, flags)
+		}
+	}
+	if t.Prompt != "" {
+		parts = append(parts, shellQuote(t.Prompt))
 	}
+	return strings.Join(parts, " ")
+}
+
+// envName matches the names sh accepts in an assignment.
+var envName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
 
-	return client.NewWindow(sess, projectPath, cmd)
+// shellQuote single-quotes s for sh.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
 }
 
 func shortenPath(p string) string {
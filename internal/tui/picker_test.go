@@ -0,0 +1,114 @@
+package tui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/shnupta/herd/internal/config"
+	"github.com/shnupta/herd/internal/tmux/tmuxtest"
+)
+
+func TestPickerPreviewsRunningSession(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	mock := m.tmuxClient.(*tmuxtest.MockClient)
+	mock.CaptureOutput = "old line\n\x1b[32mAll tests pass\x1b[0m\n\n"
+
+	m = pressKey(t, m, "n")
+	if m.mode != ModePicker {
+		t.Fatalf("mode = %d, want picker", m.mode)
+	}
+	updated, _ := m.Update(fetchPickerPreviews(m.sessions, mock)())
+	m = updated.(Model)
+
+	m = pressKey(t, m, "project-alpha")
+	view := m.View()
+	for _, want := range []string{"Already running", "%1", "All tests pass", "[enter] switch to existing", "[tab] launch another"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("picker view missing %q:\n%s", want, view)
+		}
+	}
+
+	// enter switches to the running session instead of launching.
+	m.selected = 2 // gamma
+	m = pressKey(t, m, "enter")
+	if m.mode != ModeNormal || m.selectedSession().TmuxPane != "%1" || len(mock.NewWindowCalls) != 0 {
+		t.Errorf("enter: mode %d, selected %s, launched %v; want a switch to %%1", m.mode, m.selectedSession().TmuxPane, mock.NewWindowCalls)
+	}
+}
+
+func TestPickerLaunchAnother(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	mock := m.tmuxClient.(*tmuxtest.MockClient)
+	mock.CurrentSessionVal = "0"
+
+	m = pressKey(t, m, "n")
+	m = pressKey(t, m, "project-alpha")
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m = updated.(Model)
+	if m.mode != ModeNormal || len(mock.NewWindowCalls) != 1 || !strings.Contains(mock.NewWindowCalls[0], "/home/user/project-alpha") {
+		t.Errorf("tab: mode %d, launched %v; want a new session in project-alpha", m.mode, mock.NewWindowCalls)
+	}
+}
+
+func TestPickerStartsFromTemplate(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	mock := m.tmuxClient.(*tmuxtest.MockClient)
+	mock.CurrentSessionVal = "0"
+	ctrlT := func() {
+		t.Helper()
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+		m = updated.(Model)
+	}
+
+	m = pressKey(t, m, "n")
+	m.pickerModel.templates = []config.Template{
+		{Name: "fix tests", Flags: "--model opus", Prompt: "Fix the failing tests; don't change behaviour."},
+		{Name: "review", Prompt: "Review the PR"},
+	}
+	ctrlT()
+	ctrlT()
+	ctrlT()
+	if m.pickerModel.Template() != nil {
+		t.Fatalf("ctrl+t past the last template chose %+v, want none", m.pickerModel.Template())
+	}
+	ctrlT()
+	if view := m.View(); !strings.Contains(view, "Template: fix tests") {
+		t.Errorf("picker doesn't show the template:\n%s", view)
+	}
+
+	// project-alpha has a session, but a template starts another.
+	m = pressKey(t, m, "project-alpha")
+	m = pressKey(t, m, "enter")
+	want := "0:/home/user/project-alpha:claude --model opus 'Fix the failing tests; don'\\''t change behaviour.'"
+	if m.mode != ModeNormal || !reflect.DeepEqual(mock.NewWindowCalls, []string{want}) {
+		t.Errorf("launched %q, want %q", mock.NewWindowCalls, want)
+	}
+}
+
+func TestClaudeCommandUsesLaunchConfig(t *testing.T) {
+	cfg := config.Config{
+		DangerouslySkipPermissions: true,
+		Launch: []config.Launch{{
+			Project: "/code/app",
+			Command: "npx @anthropic-ai/claude-code",
+			Flags:   "--model opus",
+			Env:     map[string]string{"TZ": "UTC", "API_BASE": "https://x/?a=1&b=2", "not valid": "dropped"},
+		}},
+	}
+	tmpl := config.Template{Flags: "--verbose", Prompt: "go"}
+
+	got := claudeCommand(cfg, "/code/app/web", tmpl)
+	want := "API_BASE='https://x/?a=1&b=2' TZ='UTC' npx @anthropic-ai/claude-code --dangerously-skip-permissions --model opus --verbose 'go'"
+	if got != want {
+		t.Errorf("claudeCommand = %q, want %q", got, want)
+	}
+	if got := claudeCommand(cfg, "/elsewhere", config.Template{}); got != "claude --dangerously-skip-permissions" {
+		t.Errorf("claudeCommand outside the project = %q", got)
+	}
+}
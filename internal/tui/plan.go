@@ -0,0 +1,78 @@
+package tui
+
+import (
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/shnupta/herd/internal/history"
+	"github.com/shnupta/herd/internal/i18n"
+	"github.com/shnupta/herd/internal/session"
+)
+
+// planSession returns the selected session if it has a plan waiting on the
+// user, telling the user through tmux if not.
+func (m Model) planSession() *session.Session {
+	sel := m.selectedSession()
+	if sel == nil {
+		return nil
+	}
+	if sel.State != session.StatePlanReady {
+		_ = m.tmuxClient.DisplayMessage("herd: " + i18n.Tf("%s has no plan waiting", m.displayName(*sel)))
+		return nil
+	}
+	return sel
+}
+
+// approvePlan accepts the selected session's plan. Enter takes the
+// highlighted option of Claude's plan prompt, its default "yes".
+func (m Model) approvePlan() {
+	sel := m.planSession()
+	if sel == nil {
+		return
+	}
+	if m.guardPane(sel.TmuxPane) != nil {
+		return
+	}
+	if m.tmuxClient.SendKeyName(sel.TmuxPane, "Enter") == nil {
+		m.audit(sel.TmuxPane, history.ActionKeys, "plan approve", "<enter>")
+	}
+}
+
+// openPlanReject opens the bar for telling the selected session what to
+// change in its plan, under the output so the plan stays in view.
+func (m Model) openPlanReject() (Model, tea.Cmd) {
+	sel := m.planSession()
+	if sel == nil {
+		return m, nil
+	}
+	in := textinput.New()
+	in.Prompt = i18n.T("keep planning: ")
+	in.Placeholder = i18n.T("what to change (optional)")
+	pane := sel.TmuxPane
+	return m.openPrompt(ModePlanReject, promptOverlay{
+		input: in,
+		help:  i18n.T("[enter] reject the plan  [esc] cancel"),
+		parse: func(text string) (tea.Msg, error) {
+			return planRejectMsg{pane: pane, feedback: text}, nil
+		},
+	})
+}
+
+// rejectPlan turns down pane's plan with Escape, which leaves Claude
+// planning, then sends feedback as the next prompt if there is any.
+func (m Model) rejectPlan(pane, feedback string) {
+	if m.guardPane(pane) != nil {
+		return
+	}
+	if err := m.tmuxClient.SendKeyName(pane, "Escape"); err != nil {
+		_ = m.tmuxClient.DisplayMessage("herd: " + err.Error())
+		return
+	}
+	m.audit(pane, history.ActionKeys, "plan reject", "<escape>")
+	if feedback == "" {
+		return
+	}
+	if err := m.sendPrompt(pane, feedback, "plan feedback"); err != nil {
+		_ = m.tmuxClient.DisplayMessage("herd: " + err.Error())
+	}
+}
@@ -0,0 +1,77 @@
+package tui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/shnupta/herd/internal/session"
+	"github.com/shnupta/herd/internal/tmux/tmuxtest"
+)
+
+// planModel returns a test model with %2's plan waiting and selected.
+func planModel(t *testing.T) (Model, *tmuxtest.MockClient) {
+	t.Helper()
+	sessions := testSessions()
+	sessions[1].State = session.StatePlanReady
+	m, fw := newTestModel(t, sessions)
+	t.Cleanup(func() { fw.Close() })
+	for i, s := range m.sessions {
+		if s.TmuxPane == "%2" {
+			m.selected = i
+		}
+	}
+	return m, m.tmuxClient.(*tmuxtest.MockClient)
+}
+
+func TestApprovePlan(t *testing.T) {
+	m, mock := planModel(t)
+
+	m = pressKey(t, m, "y")
+	if !reflect.DeepEqual(mock.SendKeyCalls, []string{"%2:Enter"}) {
+		t.Errorf("approve sent %q, want Enter to %%2", mock.SendKeyCalls)
+	}
+
+	// Nothing is sent to a session without a plan.
+	mock.SendKeyCalls = nil
+	for i, s := range m.sessions {
+		if s.TmuxPane == "%1" {
+			m.selected = i
+		}
+	}
+	m = pressKey(t, m, "y")
+	if len(mock.SendKeyCalls) != 0 || len(mock.Messages) != 1 {
+		t.Errorf("approve without a plan sent %q, messages %q", mock.SendKeyCalls, mock.Messages)
+	}
+	if m = pressKey(t, m, "Y"); m.mode != ModeNormal {
+		t.Errorf("reject without a plan opened mode %d", m.mode)
+	}
+}
+
+func TestRejectPlanWithFeedback(t *testing.T) {
+	m, mock := planModel(t)
+
+	m = pressKey(t, m, "Y")
+	if m.mode != ModePlanReject || !strings.Contains(m.View(), "reject the plan") {
+		t.Fatalf("mode %d, want the plan reject bar:\n%s", m.mode, m.View())
+	}
+	m = pressKey(t, m, "split the migration out")
+	m = pressKey(t, m, "enter")
+	if m.mode != ModeNormal {
+		t.Errorf("mode %d after enter, want normal", m.mode)
+	}
+	if !reflect.DeepEqual(mock.SendKeyCalls, []string{"%2:Escape"}) {
+		t.Errorf("reject sent keys %q, want Escape to %%2", mock.SendKeyCalls)
+	}
+	if !reflect.DeepEqual(mock.SendKeysCalls, []string{"%2:split the migration out"}) {
+		t.Errorf("reject sent %q, want the feedback to %%2", mock.SendKeysCalls)
+	}
+
+	// Cancelling sends nothing.
+	mock.SendKeyCalls, mock.SendKeysCalls = nil, nil
+	m = pressKey(t, m, "Y")
+	m = pressKey(t, m, "esc")
+	if m.mode != ModeNormal || len(mock.SendKeyCalls)+len(mock.SendKeysCalls) != 0 {
+		t.Errorf("cancel left mode %d, sent %q %q", m.mode, mock.SendKeyCalls, mock.SendKeysCalls)
+	}
+}
@@ -0,0 +1,156 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/shnupta/herd/internal/badge"
+	"github.com/shnupta/herd/internal/i18n"
+	"github.com/shnupta/herd/internal/plugin"
+	"github.com/shnupta/herd/internal/session"
+)
+
+// pluginsLoadedMsg carries the plugins described at startup.
+type pluginsLoadedMsg []*plugin.Plugin
+
+// pluginBadgesMsg carries plugin badges for each session key.
+type pluginBadgesMsg map[string][]badge.Badge
+
+// pluginResultMsg is a plugin action's response.
+type pluginResultMsg struct {
+	pane string
+	resp plugin.Response
+	err  error
+}
+
+// loadPlugins describes the executables in ~/.herd/plugins off the UI
+// goroutine. Plugins that fail to describe themselves are left out.
+func loadPlugins() tea.Cmd {
+	return func() tea.Msg {
+		plugins, _ := plugin.Load(plugin.Dir())
+		return pluginsLoadedMsg(plugins)
+	}
+}
+
+// pluginSession converts a session to the view passed to plugins.
+func pluginSession(s session.Session) plugin.Session {
+	return plugin.Session{
+		Key:         s.Key(),
+		ID:          s.ID,
+		TmuxPane:    s.TmuxPane,
+		ProjectPath: s.ProjectPath,
+		GitBranch:   s.GitBranch,
+		State:       s.State.String(),
+	}
+}
+
+// scanPluginBadges asks every badge-contributing plugin for badges. It is a
+// no-op when no plugin wants them.
+func (m Model) scanPluginBadges() tea.Cmd {
+	var wanted []*plugin.Plugin
+	for _, p := range m.plugins {
+		if p.Badges {
+			wanted = append(wanted, p)
+		}
+	}
+	if len(wanted) == 0 || len(m.sessions) == 0 {
+		return nil
+	}
+	sessions := make([]plugin.Session, 0, len(m.sessions))
+	for _, s := range m.sessions {
+		sessions = append(sessions, pluginSession(s))
+	}
+	return func() tea.Msg {
+		result := make(pluginBadgesMsg)
+		for _, p := range wanted {
+			resp, err := p.Call(plugin.Request{Type: plugin.Badges, Sessions: sessions})
+			if err != nil {
+				continue
+			}
+			for key, badges := range resp.SessionBadges {
+				for _, b := range badges {
+					result[key] = append(result[key], badge.Badge{Name: p.Name, Text: b.Text, Color: b.Color})
+				}
+			}
+		}
+		return result
+	}
+}
+
+// runPluginAction runs a plugin action against the selected session.
+func (m Model) runPluginAction(p *plugin.Plugin, a plugin.Action) tea.Cmd {
+	sel := m.selectedSession()
+	if sel == nil {
+		return nil
+	}
+	s := pluginSession(*sel)
+	return func() tea.Msg {
+		resp, err := p.Call(plugin.Request{Type: plugin.Run, Action: a.ID, Session: &s})
+		return pluginResultMsg{pane: s.TmuxPane, resp: resp, err: err}
+	}
+}
+
+// applyPluginResult sends any prompt to the session and opens the panel
+// overlay when the plugin returned panel text.
+func (m Model) applyPluginResult(msg pluginResultMsg) (Model, tea.Cmd) {
+	if msg.err != nil {
+		m.err = msg.err
+		return m, nil
+	}
+	if msg.resp.Prompt != "" {
+		if err := m.sendPrompt(msg.pane, msg.resp.Prompt, "plugin"); err != nil {
+			m.err = err
+			return m, nil
+		}
+	}
+	if msg.resp.Panel != "" {
+		m.pluginPanelTitle = msg.resp.PanelTitle
+		m.pluginPanel = viewport.New(m.width, maxInt(1, m.height-4))
+		m.pluginPanel.SetContent(msg.resp.Panel)
+		m.mode = ModePluginPanel
+		return m, nil
+	}
+	m.lastCapture = ""
+	m.forceViewportRefresh = true
+	return m, m.fetchCapture(msg.pane)
+}
+
+func (m Model) updatePluginPanelMode(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m = m.recalcLayout()
+		m.pluginPanel.Width = m.width
+		m.pluginPanel.Height = maxInt(1, m.height-4)
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "q":
+			m.mode = ModeNormal
+			m.pluginPanel = viewport.Model{}
+			m.pluginPanelTitle = ""
+			m.lastCapture = ""
+			m.forceViewportRefresh = true
+			return m, nil
+		}
+	}
+	var cmd tea.Cmd
+	m.pluginPanel, cmd = m.pluginPanel.Update(msg)
+	return m, cmd
+}
+
+func (m Model) renderPluginPanel() string {
+	title := m.pluginPanelTitle
+	if title == "" {
+		title = i18n.T("Plugin")
+	}
+	var sb strings.Builder
+	sb.WriteString(styleOverlayTitle.Width(m.width).Render(title) + "\n\n")
+	sb.WriteString(m.pluginPanel.View() + "\n")
+	sb.WriteString(styleOverlayHelp.Render(i18n.T("[j/k] scroll  [esc] close")))
+	return sb.String()
+}
@@ -0,0 +1,85 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/shnupta/herd/internal/plugin"
+	"github.com/shnupta/herd/internal/tmux/tmuxtest"
+)
+
+func TestPluginKeyRunsActionAndOpensPanel(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+
+	dir := t.TempDir()
+	script := "#!/bin/sh\ncat >/dev/null\necho '{\"panel_title\":\"Deploys\",\"panel\":\"prod: green\"}'\n"
+	if err := os.WriteFile(filepath.Join(dir, "deploys"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	m.plugins = []*plugin.Plugin{{
+		Path:     filepath.Join(dir, "deploys"),
+		Manifest: plugin.Manifest{Name: "deploys", Actions: []plugin.Action{{ID: "show", Key: "ctrl+y"}}},
+	}}
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlY})
+	if cmd == nil {
+		t.Fatal("ctrl+y returned no command, want the plugin action")
+	}
+	var result pluginResultMsg
+	for _, msg := range flattenBatch(cmd) {
+		if r, ok := msg.(pluginResultMsg); ok {
+			result = r
+		}
+	}
+	if result.err != nil || result.pane != "%1" {
+		t.Fatalf("result = %+v, want a response for %%1", result)
+	}
+
+	updated, _ := m.Update(result)
+	m = updated.(Model)
+	if m.mode != ModePluginPanel || !strings.Contains(m.View(), "prod: green") {
+		t.Errorf("mode = %v, want the plugin panel showing its text", m.mode)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if updated.(Model).mode != ModeNormal {
+		t.Error("esc should close the plugin panel")
+	}
+}
+
+func TestPluginPromptIsSentToSession(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	mock := m.tmuxClient.(*tmuxtest.MockClient)
+
+	updated, _ := m.Update(pluginResultMsg{pane: "%2", resp: plugin.Response{Prompt: "fix the lint"}})
+	m = updated.(Model)
+	if m.mode != ModeNormal {
+		t.Errorf("mode = %v, want normal with no panel text", m.mode)
+	}
+	if len(mock.SendKeysCalls) != 1 || !strings.Contains(mock.SendKeysCalls[0], "fix the lint") {
+		t.Errorf("SendKeysCalls = %v, want the prompt sent", mock.SendKeysCalls)
+	}
+}
+
+// flattenBatch runs cmd and any batched commands it returns, collecting the
+// resulting messages.
+func flattenBatch(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	msg := cmd()
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		var out []tea.Msg
+		for _, c := range batch {
+			out = append(out, flattenBatch(c)...)
+		}
+		return out
+	}
+	return []tea.Msg{msg}
+}
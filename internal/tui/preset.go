@@ -0,0 +1,85 @@
+package tui
+
+import (
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/shnupta/herd/internal/config"
+	"github.com/shnupta/herd/internal/names"
+	"github.com/shnupta/herd/internal/session"
+)
+
+// WithPreset returns m set to open laid out as p once its sessions are
+// discovered.
+func (m Model) WithPreset(p config.Preset) Model {
+	m.preset = &p
+	return m
+}
+
+// applyPreset lays the TUI out as the startup preset asks, once, on the
+// first discovery.
+func (m Model) applyPreset() (Model, tea.Cmd) {
+	p := m.preset
+	if p == nil {
+		return m, nil
+	}
+	m.preset = nil
+	for _, s := range m.sessions {
+		gKey, gName := m.groupKeyAndName(s)
+		if gKey == "" {
+			continue
+		}
+		switch {
+		case slices.Contains(p.Expand, gKey), slices.Contains(p.Expand, gName):
+			m.collapsedGroups[gKey] = false
+		case slices.Contains(p.Collapse, gKey), slices.Contains(p.Collapse, gName):
+			m.collapsedGroups[gKey] = true
+		}
+	}
+	m.itemsDirty = true
+	if p.Filter != "" {
+		m.filterQuery = p.Filter
+		m.filter = m.filter.SetQuery(p.Filter)
+		m.updateFilter()
+	}
+	if idx := m.presetSelection(p.Select); idx >= 0 && idx != m.selected {
+		m.selected = idx
+		m.cursorOnGroup = ""
+		// Open the selected session's group so the cursor has a row.
+		if gKey, _ := m.groupKeyAndName(m.sessions[idx]); gKey != "" {
+			m.collapsedGroups[gKey] = false
+		}
+		m.lastCapture = ""
+		m.forceViewportRefresh = true
+		m.pendingGotoBottom = true
+	}
+	switch p.View {
+	case "queue":
+		return m.openQueue()
+	case "stats":
+		return m.openStats()
+	case "activity":
+		return m.openActivity()
+	}
+	return m, nil
+}
+
+// presetSelection returns the index of the session target names, or -1.
+// "urgent" is the session that has been waiting on the user longest.
+func (m Model) presetSelection(target string) int {
+	if target != "urgent" {
+		matches := session.Match(m.sessions, target, func(s session.Session) string { return names.Get(s.Key()) })
+		if len(matches) == 0 {
+			return -1
+		}
+		return slices.IndexFunc(m.sessions, func(s session.Session) bool { return s.TmuxPane == matches[0].TmuxPane })
+	}
+	best := -1
+	for i, s := range m.sessions {
+		if needsAttention(s) && (best < 0 || s.UpdatedAt.Before(m.sessions[best].UpdatedAt)) {
+			best = i
+		}
+	}
+	return best
+}
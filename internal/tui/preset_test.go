@@ -0,0 +1,60 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shnupta/herd/internal/config"
+	"github.com/shnupta/herd/internal/session"
+)
+
+func TestPresetSelectsMostUrgent(t *testing.T) {
+	sessions := testSessions()
+	now := time.Now()
+	sessions[1].UpdatedAt = now.Add(-time.Minute)
+	sessions[2].State, sessions[2].UpdatedAt = session.StatePlanReady, now.Add(-time.Hour)
+	m, fw := newTestModel(t, sessions)
+	defer fw.Close()
+	m = m.WithPreset(config.Preset{View: "stats", Select: "urgent"})
+
+	result, _ := m.Update(sessionsDiscoveredMsg(m.sessions))
+	m = result.(Model)
+
+	if sel := m.selectedSession(); sel == nil || sel.TmuxPane != "%3" {
+		t.Errorf("selected %+v, want %%3, waiting longest", sel)
+	}
+	if m.mode != ModeStats {
+		t.Errorf("mode = %v, want stats", m.mode)
+	}
+	if m.preset != nil {
+		t.Error("the preset should apply once")
+	}
+}
+
+func TestPresetGroupsAndTarget(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	m.groupByTmux = true
+	m.collapsedGroups["tmux:0"] = true
+	m = m.WithPreset(config.Preset{Expand: []string{"0"}, Select: "project-beta"})
+
+	result, _ := m.Update(sessionsDiscoveredMsg(m.sessions))
+	m = result.(Model)
+
+	if m.collapsedGroups["tmux:0"] {
+		t.Error("group 0 should be expanded by name")
+	}
+	if sel := m.selectedSession(); sel == nil || sel.TmuxPane != "%2" {
+		t.Errorf("selected %+v, want %%2", sel)
+	}
+
+	m = m.WithPreset(config.Preset{Collapse: []string{"tmux:0"}, Select: "nothing"})
+	result, _ = m.Update(sessionsDiscoveredMsg(m.sessions))
+	m = result.(Model)
+	if !m.collapsedGroups["tmux:0"] {
+		t.Error("group should be collapsed by key")
+	}
+	if sel := m.selectedSession(); sel == nil || sel.TmuxPane != "%2" {
+		t.Errorf("an unknown target moved the selection to %+v", sel)
+	}
+}
@@ -0,0 +1,67 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/shnupta/herd/internal/progress"
+	"github.com/shnupta/herd/internal/session"
+)
+
+// progressMsg carries the progress read from each working session's
+// screen, by session key.
+type progressMsg map[string]progress.Progress
+
+// scanProgress captures the visible screen of every working session and
+// reads how far its tool has got.
+func (m Model) scanProgress() tea.Cmd {
+	targets := make(map[string]string) // key → pane
+	for _, s := range m.sessions {
+		if s.State == session.StateWorking {
+			targets[s.Key()] = s.TmuxPane
+		}
+	}
+	if len(targets) == 0 && len(m.toolProgress) == 0 {
+		return nil
+	}
+	client := m.tmuxClient
+	return func() tea.Msg {
+		result := make(progressMsg, len(targets))
+		for key, pane := range targets {
+			out, err := client.CapturePane(pane, 0)
+			if err != nil {
+				continue
+			}
+			if p, ok := progress.Parse(ansi.Strip(out)); ok {
+				result[key] = p
+			}
+		}
+		return result
+	}
+}
+
+// noteProgress updates s's progress from content just captured from it,
+// keeping the selected session's indicator as fresh as its output.
+func (m Model) noteProgress(s session.Session, content string) Model {
+	p, ok := progress.Parse(ansi.Strip(cleanCapture(content)))
+	if !ok || s.State != session.StateWorking {
+		delete(m.toolProgress, s.Key())
+		return m
+	}
+	if m.toolProgress == nil {
+		m.toolProgress = make(progressMsg)
+	}
+	m.toolProgress[s.Key()] = p
+	return m
+}
+
+// renderProgress renders s's progress for its meta line, e.g. "⟳12/40",
+// while it is working.
+func (m Model) renderProgress(s session.Session) string {
+	p, ok := m.toolProgress[s.Key()]
+	if !ok || s.State != session.StateWorking {
+		return ""
+	}
+	return lipgloss.NewStyle().Foreground(colBlue).Render("⟳" + p.String())
+}
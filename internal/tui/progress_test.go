@@ -0,0 +1,42 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/shnupta/herd/internal/session"
+	"github.com/shnupta/herd/internal/tmux/tmuxtest"
+)
+
+func TestProgressShowsWhileWorking(t *testing.T) {
+	sessions := testSessions()
+	sessions[0].State = session.StateWorking
+	sessions[1].State = session.StateIdle
+	m, fw := newTestModel(t, sessions)
+	defer fw.Close()
+	m.tmuxClient.(*tmuxtest.MockClient).CaptureOutput = "● Bash(go test ./...)\n  ⎿  12/40 tests\n"
+
+	updated, _ := m.Update(m.scanProgress()())
+	m = updated.(Model)
+	if len(m.toolProgress) != 1 {
+		t.Fatalf("progress for %d sessions, want only the working one", len(m.toolProgress))
+	}
+	item := ansi.Strip(m.renderSessionItem(0, m.sessions[0], "", false, false))
+	if !strings.Contains(item, "⟳12/40") {
+		t.Errorf("working session should show its progress:\n%s", item)
+	}
+
+	// The selected session's progress follows its captured output.
+	updated, _ = m.Update(captureMsg{paneID: "%1", content: "[#######---] installing\n"})
+	m = updated.(Model)
+	if got := m.renderProgress(m.sessions[0]); !strings.Contains(got, "70%") {
+		t.Errorf("after a capture renderProgress = %q, want 70%%", got)
+	}
+
+	m.sessions[0].State = session.StateWaiting
+	if got := m.renderProgress(m.sessions[0]); got != "" {
+		t.Errorf("a waiting session shows progress %q", got)
+	}
+}
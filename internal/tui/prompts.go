@@ -0,0 +1,93 @@
+package tui
+
+import (
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/shnupta/herd/internal/history"
+	"github.com/shnupta/herd/internal/prompts"
+)
+
+// sendPrompt types text into pane and records it in the history of the
+// session running there, tagged with source (see prompts.Entry). Nothing is
+// sent to a pane that no longer runs Claude.
+func (m Model) sendPrompt(pane, text, source string) error {
+	return m.deliverPrompt(pane, text, source, m.tmuxClient.SendKeys)
+}
+
+// pastePrompt sends text as sendPrompt does, but pasted in one go and then
+// submitted. tmux refuses a send-keys command longer than about 16KB, which
+// a prompt with files attached easily is.
+func (m Model) pastePrompt(pane, text, source string) error {
+	return m.deliverPrompt(pane, text, source, func(pane, text string) error {
+		if err := m.tmuxClient.PasteText(pane, text); err != nil {
+			return err
+		}
+		return m.tmuxClient.SendKeyName(pane, "Enter")
+	})
+}
+
+func (m Model) deliverPrompt(pane, text, source string, send func(pane, text string) error) error {
+	if err := m.guardPane(pane); err != nil {
+		return err
+	}
+	if err := send(pane, text); err != nil {
+		return err
+	}
+	m.audit(pane, history.ActionPrompt, source, history.Text(text))
+	for _, s := range m.sessions {
+		if s.TmuxPane == pane {
+			_ = m.prompts.Add(s.Key(), prompts.Entry{Text: text, Source: source, SentAt: time.Now()})
+			break
+		}
+	}
+	return nil
+}
+
+// recallPrompt steps through the reply bar's history: delta -1 goes to an
+// older prompt, +1 to a newer one and, past the newest, back to the draft.
+func (m Model) recallPrompt(delta int) Model {
+	i := m.queueRecall + delta
+	if i < 0 || i > len(m.queueHistory) {
+		return m
+	}
+	if m.queueRecall == len(m.queueHistory) {
+		m.queueDraft = m.queueInput.Value()
+	}
+	m.queueRecall = i
+	if i == len(m.queueHistory) {
+		m.queueInput.SetValue(m.queueDraft)
+	} else {
+		m.queueInput.SetValue(m.queueHistory[i].Text)
+	}
+	m.queueInput.CursorEnd()
+	return m
+}
+
+// recallRows is how many earlier prompts the reply bar lists while
+// recalling.
+const recallRows = 5
+
+// renderRecall lists the prompts around the one being recalled, oldest at
+// the top, or nothing when no prompt is being recalled.
+func (m Model) renderRecall() string {
+	if m.queueRecall >= len(m.queueHistory) {
+		return ""
+	}
+	last := min(len(m.queueHistory), max(m.queueRecall+1, recallRows))
+	first := max(0, last-recallRows)
+	subtle := lipgloss.NewStyle().Foreground(colSubtle)
+	var sb strings.Builder
+	for i := first; i < last; i++ {
+		e := m.queueHistory[i]
+		line := truncateLines(strings.ReplaceAll(e.Text, "\n", " "), m.width-30) + "  " + subtle.Render(e.Source+" "+e.SentAt.Format("2 Jan 15:04"))
+		if i == m.queueRecall {
+			sb.WriteString(pickerSelectedStyle.Width(m.width-4).Render("▸ "+line) + "\n")
+		} else {
+			sb.WriteString(pickerItemStyle.Render("  "+line) + "\n")
+		}
+	}
+	return sb.String()
+}
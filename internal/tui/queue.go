@@ -0,0 +1,278 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/shnupta/herd/internal/history"
+	"github.com/shnupta/herd/internal/i18n"
+	"github.com/shnupta/herd/internal/session"
+)
+
+func isCaptureMsg(msg tea.Msg) bool { _, ok := msg.(captureMsg); return ok }
+
+// isSessionChangeMsg matches the messages that can change which sessions need
+// attention, so the queue re-checks its head after normal handling.
+func isSessionChangeMsg(msg tea.Msg) bool {
+	switch msg.(type) {
+	case stateUpdateMsg, sessionsDiscoveredMsg:
+		return true
+	}
+	return false
+}
+
+// needsAttention reports whether s is blocked on the user.
+func needsAttention(s session.Session) bool {
+	switch s.State {
+	case session.StateWaiting, session.StatePlanReady, session.StateNotifying:
+		return true
+	}
+	return false
+}
+
+// attentionQueue returns the indices of sessions waiting on the user, in
+// sidebar order. A session approved or replied to in the queue drops out
+// until its state next changes; a skipped one until the queue wraps around.
+func (m *Model) attentionQueue() []int {
+	eligible := func(s session.Session) bool {
+		if !needsAttention(s) || m.queueSkipped[s.TmuxPane] {
+			return false
+		}
+		at, handled := m.queueHandled[s.TmuxPane]
+		return !handled || s.UpdatedAt.After(at)
+	}
+	var out []int
+	taken := make(map[int]bool)
+	for _, item := range m.viewItems() {
+		if !item.isHeader && eligible(m.sessions[item.sessionIdx]) {
+			out = append(out, item.sessionIdx)
+			taken[item.sessionIdx] = true
+		}
+	}
+	// Members of collapsed groups have no sidebar row; they come last.
+	for i, s := range m.sessions {
+		if !taken[i] && eligible(s) {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+// openQueue enters the attention queue at its first session.
+func (m Model) openQueue() (Model, tea.Cmd) {
+	m.mode = ModeQueue
+	m.queueHandled = make(map[string]time.Time)
+	m.queueSkipped = make(map[string]bool)
+	m.queuePane = ""
+	m.queueReplying = false
+	m.queueView = viewport.New(m.width, m.queueViewHeight())
+	return m.syncQueue()
+}
+
+// syncQueue keeps the queue's current session at the head of the queue,
+// selecting it so the capture loop follows it.
+func (m Model) syncQueue() (Model, tea.Cmd) {
+	queue := m.attentionQueue()
+	for _, idx := range queue {
+		if m.sessions[idx].TmuxPane == m.queuePane {
+			return m, nil // still needs attention; stay on it
+		}
+	}
+	m.queueReplying = false
+	if len(queue) == 0 && len(m.queueSkipped) > 0 {
+		m.queueSkipped = make(map[string]bool)
+		queue = m.attentionQueue()
+	}
+	if len(queue) == 0 {
+		m.queuePane = ""
+		m.queueView.SetContent("")
+		return m, nil
+	}
+	m.selected = queue[0]
+	m.cursorOnGroup = ""
+	m.queuePane = m.sessions[m.selected].TmuxPane
+	m.queueView.SetContent("")
+	return m, m.fetchCapture(m.queuePane)
+}
+
+// advanceQueue marks the current session handled, or skipped, and moves to
+// the next.
+func (m Model) advanceQueue(skip bool) (Model, tea.Cmd) {
+	if skip {
+		m.queueSkipped[m.queuePane] = true
+	} else if sel := m.selectedSession(); sel != nil && sel.TmuxPane == m.queuePane {
+		m.queueHandled[m.queuePane] = sel.UpdatedAt
+	}
+	return m.syncQueue()
+}
+
+func (m Model) queueViewHeight() int {
+	h := m.height - 5 // title, blank, session line, help, plus one spare
+	if m.queueReplying {
+		h -= 2
+		if m.queueRecall < len(m.queueHistory) {
+			h -= min(len(m.queueHistory), recallRows)
+		}
+	}
+	return maxInt(1, h)
+}
+
+func (m Model) updateQueueMode(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m = m.recalcLayout()
+		m.queueView.Width = m.width
+		m.queueView.Height = m.queueViewHeight()
+		return m, nil
+
+	case captureMsg:
+		m.confirmEcho(msg.paneID, msg.content)
+		if msg.paneID == m.queuePane {
+			m.queueView.SetContent(truncateLines(m.displayCapture(msg.paneID, msg.content), m.queueView.Width))
+			m.queueView.GotoBottom()
+		}
+		return m, nil
+
+	case stateUpdateMsg, sessionsDiscoveredMsg:
+		updated, cmd := m.updateNormal(msg)
+		m = updated.(Model)
+		if m.mode != ModeQueue {
+			return m, cmd
+		}
+		var syncCmd tea.Cmd
+		m, syncCmd = m.syncQueue()
+		return m, tea.Batch(cmd, syncCmd)
+
+	case tea.KeyMsg:
+		if m.queueReplying {
+			return m.updateQueueReply(msg)
+		}
+		if m.queuePane == "" {
+			if s := msg.String(); s == "esc" || s == "q" {
+				return m.closeQueue(), nil
+			}
+			return m, nil
+		}
+		switch msg.String() {
+		case "esc", "q":
+			return m.closeQueue(), nil
+		case "y", "enter":
+			// Enter accepts the highlighted option of a permission or plan
+			// prompt, which is Claude's default "yes".
+			// Nothing was accepted if it didn't go through, so the
+			// session stays in the queue.
+			if m.guardPane(m.queuePane) != nil {
+				return m, nil
+			}
+			if err := m.tmuxClient.SendKeyName(m.queuePane, "Enter"); err != nil {
+				m.err = err
+				return m, nil
+			}
+			m.audit(m.queuePane, history.ActionKeys, "queue accept", "<enter>")
+			return m.advanceQueue(false)
+		case "r":
+			m.queueReplying = true
+			m.queueInput = textinput.New()
+			m.queueInput.Placeholder = i18n.T("reply to Claude... (↑ for earlier prompts)")
+			m.queueInput.Focus()
+			if sel := m.selectedSession(); sel != nil {
+				m.queueHistory = m.prompts.Get(sel.Key())
+			}
+			m.queueRecall = len(m.queueHistory)
+			m.queueDraft = ""
+			m.queueView.Height = m.queueViewHeight()
+			return m, nil
+		case "s", "n", "tab":
+			return m.advanceQueue(true)
+		case "t":
+			_ = m.tmuxClient.SwitchToPane(m.queuePane)
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.queueView, cmd = m.queueView.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+func (m Model) updateQueueReply(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.queueReplying = false
+		m.queueView.Height = m.queueViewHeight()
+		return m, nil
+	case "enter":
+		text := strings.TrimSpace(m.queueInput.Value())
+		m.queueReplying = false
+		m.queueView.Height = m.queueViewHeight()
+		if text == "" {
+			return m, nil
+		}
+		if m.sendPrompt(m.queuePane, text, "reply") == nil {
+			m.echoSent(m.queuePane, text)
+		}
+		return m.advanceQueue(false)
+	case "up":
+		m = m.recallPrompt(-1)
+		m.queueView.Height = m.queueViewHeight()
+		return m, nil
+	case "down":
+		m = m.recallPrompt(1)
+		m.queueView.Height = m.queueViewHeight()
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.queueInput, cmd = m.queueInput.Update(msg)
+	return m, cmd
+}
+
+// closeQueue returns to normal mode with the last shown session selected.
+func (m Model) closeQueue() Model {
+	m.mode = ModeNormal
+	m.queuePane = ""
+	m.queueHandled = nil
+	m.queueSkipped = nil
+	m.queueReplying = false
+	m.queueView = viewport.Model{}
+	m.lastCapture = ""
+	m.forceViewportRefresh = true
+	m.itemsDirty = true
+	return m
+}
+
+func (m Model) renderQueueView() string {
+	var sb strings.Builder
+	queue := m.attentionQueue()
+	sb.WriteString(styleOverlayTitle.Width(m.width).Render(i18n.Tf("Attention Queue (%d)", len(queue))) + "\n\n")
+
+	sel := m.selectedSession()
+	if m.queuePane == "" || sel == nil {
+		sb.WriteString(pickerItemStyle.Render(i18n.T("All caught up — no session is waiting on you.")) + "\n")
+		sb.WriteString(pickerItemStyle.Render(lipgloss.NewStyle().Foreground(colSubtle).Render(i18n.T("Sessions appear here as they need attention."))) + "\n")
+		sb.WriteString("\n" + styleOverlayHelp.Render(i18n.T("[esc] close")))
+		return sb.String()
+	}
+
+	state := stateIcon(sel.State.String()) + " " + sel.State.String()
+	sb.WriteString(fmt.Sprintf(" %s  %s  %s\n",
+		lipgloss.NewStyle().Foreground(colGoldText).Bold(true).Render(m.displayName(*sel)),
+		state,
+		lipgloss.NewStyle().Foreground(colSubtext).Render(filepath.Base(sel.ProjectPath))))
+	sb.WriteString(m.withEcho(m.queuePane, m.queueView.View(), m.queueView.Width) + "\n")
+	if m.queueReplying {
+		sb.WriteString("\n" + m.renderRecall() + m.queueInput.View() + "\n")
+		sb.WriteString(styleOverlayHelp.Render(i18n.T("[enter] send  [↑/↓] earlier prompts  [esc] cancel")))
+	} else {
+		sb.WriteString(styleOverlayHelp.Render(i18n.T("[y/enter] approve  [r] reply  [s] skip  [t] jump  [esc] close")))
+	}
+	return sb.String()
+}
@@ -0,0 +1,186 @@
+package tui
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/shnupta/herd/internal/prompts"
+	"github.com/shnupta/herd/internal/session"
+	"github.com/shnupta/herd/internal/state"
+	"github.com/shnupta/herd/internal/tmux/tmuxtest"
+)
+
+func queueSessions() []session.Session {
+	sessions := testSessions()
+	sessions[0].State = session.StatePlanReady // %1
+	sessions[2].State = session.StateWaiting   // %3; %2 is already waiting
+	return sessions
+}
+
+func pressKey(t *testing.T, m Model, k string) Model {
+	t.Helper()
+	var msg tea.KeyMsg
+	switch k {
+	case "enter":
+		msg = tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		msg = tea.KeyMsg{Type: tea.KeyEsc}
+	case "up":
+		msg = tea.KeyMsg{Type: tea.KeyUp}
+	case "down":
+		msg = tea.KeyMsg{Type: tea.KeyDown}
+	default:
+		msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+	}
+	updated, _ := m.Update(msg)
+	return updated.(Model)
+}
+
+func TestQueueApproveAndSkipAdvance(t *testing.T) {
+	m, fw := newTestModel(t, queueSessions())
+	defer fw.Close()
+	mock := m.tmuxClient.(*tmuxtest.MockClient)
+
+	m = pressKey(t, m, "a")
+	if m.mode != ModeQueue || m.queuePane != "%1" {
+		t.Fatalf("mode %d pane %q, want queue at %%1", m.mode, m.queuePane)
+	}
+
+	m = pressKey(t, m, "y")
+	if !reflect.DeepEqual(mock.SendKeyCalls, []string{"%1:Enter"}) {
+		t.Errorf("approve sent %q, want Enter to %%1", mock.SendKeyCalls)
+	}
+	if m.queuePane != "%2" {
+		t.Fatalf("after approve at %q, want %%2", m.queuePane)
+	}
+
+	m = pressKey(t, m, "s")
+	if m.queuePane != "%3" {
+		t.Fatalf("after skip at %q, want %%3", m.queuePane)
+	}
+
+	// Skipping the last session wraps round to the skipped one; the approved
+	// session stays out until its state changes.
+	m = pressKey(t, m, "s")
+	if m.queuePane != "%2" {
+		t.Fatalf("after wrap at %q, want %%2", m.queuePane)
+	}
+	if m.sessions[m.selected].TmuxPane != "%2" {
+		t.Error("the queue's session should be selected")
+	}
+}
+
+func TestQueueAcceptKeepsSessionWhenNotSent(t *testing.T) {
+	m, fw := newTestModel(t, queueSessions())
+	defer fw.Close()
+	mock := m.tmuxClient.(*tmuxtest.MockClient)
+
+	m = pressKey(t, m, "a")
+	mock.Panes = mock.Panes[1:] // %1 has closed
+	m = pressKey(t, m, "y")
+	if len(mock.SendKeyCalls) != 0 || m.queuePane != "%1" {
+		t.Errorf("sent %q and moved to %q; want nothing sent and %%1 kept", mock.SendKeyCalls, m.queuePane)
+	}
+
+	mock.Panes = makePanes(m.sessions)
+	mock.SendKeyNameErr = errors.New("tmux send-keys: server exited")
+	m = pressKey(t, m, "y")
+	if m.queuePane != "%1" || len(m.queueHandled) != 0 {
+		t.Errorf("after a failed send at %q, handled %v; want %%1 kept", m.queuePane, m.queueHandled)
+	}
+}
+
+func TestQueueReply(t *testing.T) {
+	m, fw := newTestModel(t, queueSessions())
+	defer fw.Close()
+	mock := m.tmuxClient.(*tmuxtest.MockClient)
+
+	m = pressKey(t, m, "a")
+	m = pressKey(t, m, "r")
+	if !m.queueReplying {
+		t.Fatal("r should open the reply input")
+	}
+	m = pressKey(t, m, "use option 2")
+	m = pressKey(t, m, "enter")
+	if !reflect.DeepEqual(mock.SendKeysCalls, []string{"%1:use option 2"}) {
+		t.Errorf("reply sent %q", mock.SendKeysCalls)
+	}
+	if m.queueReplying || m.queuePane != "%2" {
+		t.Errorf("after reply: replying=%v pane=%q, want closed input at %%2", m.queueReplying, m.queuePane)
+	}
+}
+
+func TestQueueReplyRecallsEarlierPrompts(t *testing.T) {
+	m, fw := newTestModel(t, queueSessions())
+	defer fw.Close()
+	mock := m.tmuxClient.(*tmuxtest.MockClient)
+	key := m.sessions[0].Key()
+	_ = m.prompts.Add(key, prompts.Entry{Text: "run the tests", Source: "action"})
+	_ = m.prompts.Add(key, prompts.Entry{Text: "use option 2", Source: "reply"})
+
+	m = pressKey(t, m, "a")
+	m = pressKey(t, m, "r")
+	m = pressKey(t, m, "dra")
+	steps := []struct{ key, want string }{
+		{"up", "use option 2"},
+		{"up", "run the tests"},
+		{"up", "run the tests"}, // oldest: stays put
+		{"down", "use option 2"},
+		{"down", "dra"}, // past the newest: back to the draft
+		{"up", "use option 2"},
+	}
+	for _, s := range steps {
+		m = pressKey(t, m, s.key)
+		if got := m.queueInput.Value(); got != s.want {
+			t.Fatalf("after %s input = %q, want %q", s.key, got, s.want)
+		}
+	}
+	if view := m.View(); !strings.Contains(view, "run the tests") {
+		t.Errorf("recall list missing older prompt:\n%s", view)
+	}
+
+	m = pressKey(t, m, " again")
+	m = pressKey(t, m, "enter")
+	if !reflect.DeepEqual(mock.SendKeysCalls, []string{"%1:use option 2 again"}) {
+		t.Errorf("reply sent %q", mock.SendKeysCalls)
+	}
+	if h := m.prompts.Get(key); len(h) != 3 || h[2].Text != "use option 2 again" || h[2].Source != "reply" {
+		t.Errorf("history = %+v, want the edited reply recorded last", h)
+	}
+}
+
+func TestQueueFollowsStateChanges(t *testing.T) {
+	m, fw := newTestModel(t, queueSessions())
+	defer fw.Close()
+	m = pressKey(t, m, "a")
+
+	// %1 starts working elsewhere: the queue moves on by itself.
+	updated, _ := m.Update(stateUpdateMsg(state.SessionState{
+		SessionID: "sess-aaa", TmuxPane: "%1", State: "working", UpdatedAt: time.Now(),
+	}))
+	m = updated.(Model)
+	if m.queuePane != "%2" {
+		t.Fatalf("queue at %q, want %%2 once %%1 is working", m.queuePane)
+	}
+}
+
+func TestQueueEmpty(t *testing.T) {
+	m, fw := newTestModel(t, testSessions()[:1]) // working only
+	defer fw.Close()
+	m = pressKey(t, m, "a")
+	if m.mode != ModeQueue || m.queuePane != "" {
+		t.Fatalf("mode %d pane %q, want an empty queue", m.mode, m.queuePane)
+	}
+	if view := m.View(); !strings.Contains(view, "All caught up") {
+		t.Errorf("empty queue view:\n%s", view)
+	}
+	m = pressKey(t, m, "esc")
+	if m.mode != ModeNormal {
+		t.Errorf("esc left mode %d", m.mode)
+	}
+}
@@ -0,0 +1,55 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/shnupta/herd/internal/i18n"
+	"github.com/shnupta/herd/internal/session"
+)
+
+// tmuxLostMsg reports that discovery couldn't reach the tmux server,
+// which has usually exited or is restarting.
+type tmuxLostMsg struct{ err error }
+
+// discovered is the message for the outcome of a session discovery.
+func discovered(sessions []session.Session, err error) tea.Msg {
+	if err != nil {
+		return tmuxLostMsg{err}
+	}
+	return sessionsDiscoveredMsg(sessions)
+}
+
+// loseTmux keeps the last sessions seen, greyed out under a banner, while
+// the session refresh keeps polling for the server to come back. Insert
+// mode ends as there is nowhere to send keys.
+func (m Model) loseTmux(err error) Model {
+	m.tmuxLost = err
+	m.insertMode = false
+	return m
+}
+
+// reconnected re-attaches to the selected session once discovery reaches
+// the tmux server again, making the viewport take the next capture as new.
+func (m Model) reconnected() Model {
+	if m.tmuxLost == nil {
+		return m
+	}
+	m.tmuxLost = nil
+	m.lastCapture = ""
+	m.forceViewportRefresh = true
+	m.pendingGotoBottom = true
+	return m
+}
+
+// greyOut draws rendered in the subtle colour alone, for the session list
+// while tmux is unreachable.
+func greyOut(rendered string) string {
+	return lipgloss.NewStyle().Foreground(colSubtle).Render(ansi.Strip(rendered))
+}
+
+// renderTmuxLost is the header banner shown while tmux is unreachable.
+func (m Model) renderTmuxLost() string {
+	return lipgloss.NewStyle().Background(colAccent).Foreground(colRed).Bold(true).Render(i18n.T("⚠ tmux server unreachable — reconnecting…"))
+}
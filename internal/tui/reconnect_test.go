@@ -0,0 +1,42 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/shnupta/herd/internal/tmux/tmuxtest"
+)
+
+func TestTmuxRestartKeepsSessions(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	mock := m.tmuxClient.(*tmuxtest.MockClient)
+	m.insertMode = true
+
+	mock.ListPanesErr = errors.New("no server running on /tmp/tmux-0/default")
+	updated, _ := m.Update(m.discoverSessions()())
+	m = updated.(Model)
+	if m.err != nil {
+		t.Fatalf("a lost server should not be fatal: %v", m.err)
+	}
+	if m.tmuxLost == nil || m.insertMode {
+		t.Errorf("tmuxLost = %v, insertMode = %v", m.tmuxLost, m.insertMode)
+	}
+	if len(m.sessions) != 3 {
+		t.Errorf("kept %d sessions, want the last 3 seen", len(m.sessions))
+	}
+	if view := m.View(); !strings.Contains(view, "reconnecting") || !strings.Contains(view, "project-alpha") {
+		t.Error("expected the banner over the last sessions seen")
+	}
+
+	mock.ListPanesErr = nil
+	updated, _ = m.Update(m.discoverSessions()())
+	m = updated.(Model)
+	if m.tmuxLost != nil || !m.forceViewportRefresh {
+		t.Errorf("after reconnecting: tmuxLost = %v, forceViewportRefresh = %v", m.tmuxLost, m.forceViewportRefresh)
+	}
+	if strings.Contains(m.View(), "reconnecting") {
+		t.Error("banner still shown after reconnecting")
+	}
+}
@@ -0,0 +1,111 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/shnupta/herd/internal/groups"
+	"github.com/shnupta/herd/internal/names"
+	"github.com/shnupta/herd/internal/session"
+)
+
+// Fixture describes a screen for RenderFixture: the sessions in the
+// sidebar, the one selected and what its pane shows.
+type Fixture struct {
+	Sessions []FixtureSession `json:"sessions"`
+	Selected string           `json:"selected,omitempty"` // pane ID; the first session when empty
+	Output   string           `json:"output,omitempty"`   // the selected pane's contents
+}
+
+// FixtureSession is one session of a Fixture.
+type FixtureSession struct {
+	Pane          string   `json:"pane"`
+	ID            string   `json:"id,omitempty"`
+	Project       string   `json:"project"`
+	Branch        string   `json:"branch,omitempty"`
+	State         string   `json:"state,omitempty"`
+	Tool          string   `json:"tool,omitempty"`
+	Age           string   `json:"age,omitempty"` // since the last hook event, e.g. "5m"
+	Model         string   `json:"model,omitempty"`
+	ContextTokens int      `json:"context_tokens,omitempty"`
+	Name          string   `json:"name,omitempty"`
+	Group         string   `json:"group,omitempty"`
+	Timeline      []string `json:"timeline,omitempty"` // states, oldest slot first; needs ID
+}
+
+// ReadFixture reads a Fixture from the JSON file at path.
+func ReadFixture(path string) (Fixture, error) {
+	var f Fixture
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return f, err
+	}
+	if err := json.Unmarshal(data, &f); err != nil {
+		return f, fmt.Errorf("%s: %w", path, err)
+	}
+	return f, nil
+}
+
+// RenderFixture draws the screen f describes at width×height, as herd would
+// with the current config. Names and groups from f are saved to herd's
+// stores, so it should run against a scratch home.
+func RenderFixture(f Fixture, width, height int) (string, error) {
+	now := time.Now()
+	sessions := make([]session.Session, len(f.Sessions))
+	for i, fs := range f.Sessions {
+		s := session.Session{
+			ID:            fs.ID,
+			TmuxPane:      fs.Pane,
+			ProjectPath:   fs.Project,
+			GitBranch:     fs.Branch,
+			State:         session.ParseState(fs.State),
+			CurrentTool:   fs.Tool,
+			Model:         fs.Model,
+			ContextTokens: fs.ContextTokens,
+			UpdatedAt:     now,
+		}
+		if fs.Age != "" {
+			age, err := time.ParseDuration(fs.Age)
+			if err != nil {
+				return "", fmt.Errorf("session %s: age: %w", fs.Pane, err)
+			}
+			s.UpdatedAt = now.Add(-age)
+		}
+		if fs.Name != "" {
+			if err := names.Set(s.Key(), fs.Name); err != nil {
+				return "", err
+			}
+		}
+		if fs.Group != "" {
+			if err := groups.Set(s.Key(), fs.Group); err != nil {
+				return "", err
+			}
+		}
+		sessions[i] = s
+	}
+
+	m := New(nil, nil)
+	m.pendingSelectPane = f.Selected
+	for _, msg := range []tea.Msg{
+		tea.WindowSizeMsg{Width: width, Height: height},
+		sessionsDiscoveredMsg(sessions),
+	} {
+		updated, _ := m.Update(msg)
+		m = updated.(Model)
+	}
+	m.timelines = make(map[string][]string)
+	for _, fs := range f.Sessions {
+		if fs.ID != "" && len(fs.Timeline) > 0 {
+			m.timelines[fs.ID] = fs.Timeline
+		}
+	}
+	if sel := m.selectedSession(); sel != nil {
+		updated, _ := m.Update(captureMsg{paneID: sel.TmuxPane, content: f.Output})
+		m = updated.(Model)
+	}
+	return m.View(), nil
+}
@@ -0,0 +1,44 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestRenderFixture(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fixture.json")
+	data := `{
+		"selected": "%2",
+		"sessions": [
+			{"pane": "%1", "id": "a", "project": "/code/api", "state": "working", "tool": "Bash", "timeline": ["idle", "working"]},
+			{"pane": "%2", "project": "/code/web", "state": "waiting", "age": "2m"}
+		],
+		"output": "● All tests pass."
+	}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := ReadFixture(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	screen, err := RenderFixture(f, 100, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	screen = ansi.Strip(screen)
+	for _, want := range []string{"api", "▁█", "Bash", "waiting  %2", "● All tests pass."} {
+		if !strings.Contains(screen, want) {
+			t.Errorf("screen missing %q:\n%s", want, screen)
+		}
+	}
+
+	f.Sessions[0].Age = "soon"
+	if _, err := RenderFixture(f, 100, 20); err == nil {
+		t.Error("a bad age should fail")
+	}
+}
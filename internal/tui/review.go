@@ -2,16 +2,24 @@ package tui
 
 import (
 	"fmt"
+	"os/exec"
+	"path/filepath"
+	"runtime"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
 	"github.com/shnupta/herd/internal/diff"
+	"github.com/shnupta/herd/internal/git"
+	"github.com/shnupta/herd/internal/i18n"
 	"github.com/shnupta/herd/internal/review"
+	"github.com/shnupta/herd/internal/transcript"
 )
 
 // ReviewModel is the bubbletea model for diff review.
@@ -36,14 +44,71 @@ type ReviewModel struct {
 
 	// State
 	ready        bool
+	viewOnly     bool // Opened by herd diff: no comments, nothing to submit
 	commenting   bool // True when entering a comment
+	replying     bool // True when the comment being entered replies to the line's thread
 	submitted    bool // True when review was submitted
+	approved     bool // True when submitted as an approval, without comments
 	cancelled    bool // True when review was cancelled
 	feedbackText string // The formatted feedback to send
 
 	// Flattened view of all lines for easier navigation
 	flatLines []flatLine
 	flatIndex int
+
+	// Files left out by review ignore patterns
+	ignored int
+
+	// HEAD and working tree sizes of binary files, by file index
+	binarySizes map[int][2]int64
+
+	// Sessions feedback can be sent to, the reviewed one first
+	targets        []reviewTarget
+	choosingTarget bool
+	targetIndex    int
+	target         string // Key of the chosen session, "" for the reviewed one
+	toTodo         bool   // Submitted to the todo file rather than a session
+
+	// Feedback wording, and any error rendering it
+	feedbackTmpl review.Template
+	feedbackErr  error
+
+	// URL template `e` opens instead of $EDITOR (see config.EditorURL)
+	editorURL string
+
+	// Ignore patterns the diff is filtered by, reapplied on refresh
+	ignore []string
+
+	// How the diff is read, and the big files the reviewer loaded, kept
+	// loaded on refresh
+	diffOpts diff.Options
+	loaded   map[string]bool
+
+	// Error shown before the help until the next key
+	notice string
+
+	// HEAD blame of each file by its old path, loaded when first shown
+	showBlame bool
+	blame     map[string][]git.Blame
+
+	// Diff source chooser, on the row under the header; a commit or range
+	// is typed into revInput
+	choosingSource bool
+	sourceIndex    int
+	enteringRev    bool
+	revInput       textinput.Model
+
+	// File tree panel
+	treeVisible   bool
+	treeFocus     bool // Keys go to the tree rather than the diff
+	treeCursor    int
+	treeCollapsed map[string]bool // Directories folded in the tree
+}
+
+// reviewTarget is a session the feedback can be sent to.
+type reviewTarget struct {
+	key  string
+	name string
 }
 
 type flatLine struct {
@@ -54,6 +119,8 @@ type flatLine struct {
 	hunk      *diff.Hunk
 	line      *diff.Line
 	isHeader  bool // True for hunk headers
+	binary    bool // True for the single entry standing in for a binary file
+	unloaded  bool // True for the single entry standing in for a file too big to load up front
 }
 
 // ReviewKeyMap defines the key bindings for the review UI.
@@ -67,7 +134,18 @@ type ReviewKeyMap struct {
 	Comment   key.Binding
 	Delete    key.Binding
 	Submit    key.Binding
+	SubmitTo  key.Binding
+	WriteTodo key.Binding
+	Approve   key.Binding
 	Pause     key.Binding
+	Open      key.Binding
+	Edit      key.Binding
+	Reply     key.Binding
+	Refresh   key.Binding
+	Blame     key.Binding
+	Load      key.Binding
+	Tree      key.Binding
+	Source    key.Binding
 	Quit      key.Binding
 }
 
@@ -81,7 +159,18 @@ var reviewKeys = ReviewKeyMap{
 	Comment:   key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "comment/edit")),
 	Delete:    key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "delete comment")),
 	Submit:    key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "submit")),
+	SubmitTo:  key.NewBinding(key.WithKeys("S"), key.WithHelp("S", "submit to…")),
+	WriteTodo: key.NewBinding(key.WithKeys("w"), key.WithHelp("w", "write todo file")),
+	Approve:   key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "approve")),
 	Pause:     key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "pause")),
+	Open:      key.NewBinding(key.WithKeys("o"), key.WithHelp("o", "open binary")),
+	Edit:      key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "edit at line")),
+	Reply:     key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "reply to thread")),
+	Refresh:   key.NewBinding(key.WithKeys("R"), key.WithHelp("R", "refresh diff")),
+	Blame:     key.NewBinding(key.WithKeys("b"), key.WithHelp("b", "toggle blame")),
+	Load:      key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "load large file")),
+	Tree:      key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "file tree")),
+	Source:    key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "diff source")),
 	Quit:      key.NewBinding(key.WithKeys("q", "esc"), key.WithHelp("q/esc", "cancel")),
 }
 
@@ -124,6 +213,13 @@ var (
 	reviewLineNumStyle = lipgloss.NewStyle().
 				Foreground(colSubtle)
 
+	reviewUnviewedStyle = lipgloss.NewStyle().
+				Foreground(colBlue)
+
+	reviewBlameStyle = lipgloss.NewStyle().
+				Foreground(colSubtle).
+				Italic(true)
+
 	reviewHelpStyle = lipgloss.NewStyle().
 			Background(colSurface).
 			Foreground(colSubtext).
@@ -138,7 +234,7 @@ var (
 // NewReviewModel creates a new review model.
 func NewReviewModel(d *diff.Diff, sessionID, projectPath string) ReviewModel {
 	ta := textarea.New()
-	ta.Placeholder = "Enter your comment..."
+	ta.Placeholder = i18n.T("Enter your comment...")
 	ta.CharLimit = 500
 	ta.SetWidth(60)
 	ta.SetHeight(3)
@@ -148,6 +244,9 @@ func NewReviewModel(d *diff.Diff, sessionID, projectPath string) ReviewModel {
 	r, err := review.Load(sessionID)
 	if err != nil {
 		r = review.NewReview(sessionID, projectPath)
+	} else {
+		// The tree may have changed since the review was paused.
+		r.Reanchor(d)
 	}
 
 	m := ReviewModel{
@@ -159,12 +258,55 @@ func NewReviewModel(d *diff.Diff, sessionID, projectPath string) ReviewModel {
 	}
 
 	m.buildFlatLines()
+	m.resumeAtFirstUnviewed()
 	return m
 }
 
+// binarySizes, openExternally and saveReview are variables so tests can
+// stub them.
+var (
+	binarySizes = diff.BinarySizes
+
+	saveReview = (*review.Review).Save
+
+	openExternally = func(path string) error {
+		opener := "xdg-open"
+		if runtime.GOOS == "darwin" {
+			opener = "open"
+		}
+		return exec.Command(opener, path).Start()
+	}
+)
+
 func (m *ReviewModel) buildFlatLines() {
 	m.flatLines = nil
+	m.binarySizes = make(map[int][2]int64)
 	for fi, file := range m.diff.Files {
+		if file.Binary {
+			// Binary files have no hunks; give them one entry to land on.
+			oldSize, newSize := binarySizes(m.projectPath, &m.diff.Files[fi])
+			m.binarySizes[fi] = [2]int64{oldSize, newSize}
+			m.flatLines = append(m.flatLines, flatLine{
+				fileIndex: fi,
+				hunkIndex: -1,
+				lineIndex: -1,
+				file:      &m.diff.Files[fi],
+				isHeader:  true,
+				binary:    true,
+			})
+			continue
+		}
+		if file.Unloaded > 0 {
+			m.flatLines = append(m.flatLines, flatLine{
+				fileIndex: fi,
+				hunkIndex: -1,
+				lineIndex: -1,
+				file:      &m.diff.Files[fi],
+				isHeader:  true,
+				unloaded:  true,
+			})
+			continue
+		}
 		for hi, hunk := range file.Hunks {
 			// Add hunk header as a line
 			m.flatLines = append(m.flatLines, flatLine{
@@ -190,6 +332,108 @@ func (m *ReviewModel) buildFlatLines() {
 	}
 }
 
+// refresh re-reads the diff, moving comments with their lines and putting
+// the cursor back on the line it was on, or as near to it as the new diff
+// allows.
+func (m ReviewModel) refresh() ReviewModel {
+	parsed, err := diff.GitDiff(m.projectPath, m.diffOpts)
+	if err != nil {
+		m.notice = i18n.Tf("refresh: %v", err)
+		return m
+	}
+	m.ignored = parsed.Ignore(m.ignore)
+	return m.replaceDiff(parsed)
+}
+
+// replaceDiff shows parsed in place of the diff, as refresh does.
+func (m ReviewModel) replaceDiff(parsed *diff.Diff) ReviewModel {
+	var at *flatLine
+	if m.flatIndex < len(m.flatLines) {
+		fl := m.flatLines[m.flatIndex]
+		at = &fl
+	}
+	m.review.Anchor(m.diff)
+	m.diff = parsed
+	m.loadWanted()
+	m.blame = nil
+	if m.showBlame {
+		m.loadBlame()
+	}
+	m.buildFlatLines()
+	m.flatIndex = 0
+	if at != nil {
+		m.flatIndex = m.nearestLine(*at)
+	}
+	m.updateViewportContent()
+	m.ensureVisible()
+	return m
+}
+
+// loadWanted loads the big files left unloaded that have comments, so the
+// comments can be placed, or that the reviewer loaded before a refresh,
+// then re-anchors the comments.
+func (m *ReviewModel) loadWanted() {
+	for i := range m.diff.Files {
+		f := &m.diff.Files[i]
+		if f.Unloaded == 0 || (!m.loaded[f.GetFilePath()] && len(m.review.GetCommentsForFile(f.GetFilePath())) == 0) {
+			continue
+		}
+		if err := f.Load(m.projectPath, m.diffOpts); err != nil {
+			m.notice = i18n.Tf("loading %s: %v", f.GetFilePath(), err)
+		}
+	}
+	m.review.Reanchor(m.diff)
+	m.buildFlatLines()
+}
+
+// loadFile reads the hunks of the big file at the cursor.
+func (m *ReviewModel) loadFile(fl flatLine) {
+	if err := fl.file.Load(m.projectPath, m.diffOpts); err != nil {
+		m.notice = i18n.Tf("loading %s: %v", fl.file.GetFilePath(), err)
+		return
+	}
+	if m.loaded == nil {
+		m.loaded = make(map[string]bool)
+	}
+	m.loaded[fl.file.GetFilePath()] = true
+	m.review.Reanchor(m.diff)
+	m.buildFlatLines()
+	m.updateViewportContent()
+}
+
+// nearestLine finds where was, a line of the previous diff, sits in the
+// current one: the closest line of its file with the same text if there is
+// one, else the closest line of its file, else the first line of the file
+// now at its position.
+func (m ReviewModel) nearestLine(was flatLine) int {
+	path, target := was.file.GetFilePath(), was.newLine()
+	same := func(fl flatLine) bool {
+		return was.line != nil && fl.line != nil && fl.line.Type == was.line.Type && fl.line.Content == was.line.Content
+	}
+	best, bestSame, bestDist := -1, false, 0
+	for i, fl := range m.flatLines {
+		if fl.file.GetFilePath() != path {
+			continue
+		}
+		dist := fl.newLine() - target
+		if dist < 0 {
+			dist = -dist
+		}
+		if s := same(fl); best < 0 || (s && !bestSame) || (s == bestSame && dist < bestDist) {
+			best, bestSame, bestDist = i, s, dist
+		}
+	}
+	if best >= 0 {
+		return best
+	}
+	for i, fl := range m.flatLines {
+		if fl.fileIndex >= was.fileIndex {
+			return i
+		}
+	}
+	return max(0, len(m.flatLines)-1)
+}
+
 func (m ReviewModel) Init() tea.Cmd {
 	return nil
 }
@@ -202,27 +446,39 @@ func (m ReviewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 
-		vpHeight := m.height - 4 // header + help
+		vpHeight := m.height - 5 // header, sources + help
 		if !m.ready {
-			m.viewport = viewport.New(m.width, vpHeight)
+			m.viewport = viewport.New(m.diffWidth(), vpHeight)
 			m.ready = true
 		} else {
-			m.viewport.Width = m.width
+			m.viewport.Width = m.diffWidth()
 			m.viewport.Height = vpHeight
 		}
 		m.updateViewportContent()
 
+	case fileEditedMsg:
+		if msg.err != nil {
+			m.notice = i18n.Tf("editor: %v", msg.err)
+			return m, nil
+		}
+		return m.refresh(), nil
+
 	case tea.KeyMsg:
+		m.notice = ""
 		if m.commenting {
 			switch msg.String() {
 			case "esc":
 				m.commenting = false
+				m.replying = false
 				m.textarea.Reset()
 			case "enter":
-				if strings.TrimSpace(m.textarea.Value()) != "" {
+				if m.replying {
+					m.addReplyAtCursor()
+				} else if strings.TrimSpace(m.textarea.Value()) != "" {
 					m.addCommentAtCursor()
 				}
 				m.commenting = false
+				m.replying = false
 				m.textarea.Reset()
 				m.updateViewportContent()
 			default:
@@ -232,8 +488,21 @@ func (m ReviewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, tea.Batch(cmds...)
 		}
+		if m.treeFocus {
+			return m.updateTree(msg)
+		}
+		if m.choosingTarget {
+			return m.updateTargetChoice(msg), nil
+		}
+		if m.choosingSource {
+			return m.updateSourceChoice(msg)
+		}
 
+		prev := m.flatIndex
 		switch {
+		case key.Matches(msg, reviewKeys.Tree):
+			return m.toggleTree(), nil
+
 		case key.Matches(msg, reviewKeys.Quit):
 			m.cancelled = true
 			return m, nil
@@ -282,6 +551,12 @@ func (m ReviewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 
+		case key.Matches(msg, reviewKeys.Reply):
+			if m.commentIndexAtCursor() >= 0 {
+				m.commenting = true
+				m.replying = true
+			}
+
 		case key.Matches(msg, reviewKeys.Delete):
 			// Delete comment at current line
 			if len(m.flatLines) > 0 && !m.flatLines[m.flatIndex].isHeader {
@@ -289,7 +564,7 @@ func (m ReviewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				filePath := fl.file.GetFilePath()
 				// Find and remove the comment
 				for i, c := range m.review.Comments {
-					if c.FilePath == filePath && c.HunkIndex == fl.hunkIndex && c.LineIndex == fl.lineIndex {
+					if !c.Outdated && c.FilePath == filePath && c.HunkIndex == fl.hunkIndex && c.LineIndex == fl.lineIndex {
 						m.review.RemoveComment(i)
 						m.updateViewportContent()
 						break
@@ -298,18 +573,74 @@ func (m ReviewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 		case key.Matches(msg, reviewKeys.Submit):
-			if m.review.HasComments() {
-				m.feedbackText = m.review.FormatFeedback(m.diff)
+			if m.review.HasPending() {
+				m.formatFeedback()
 				m.submitted = true
-				_ = review.Delete(m.sessionID) // Clean up saved review
+				m.markSent()
+			}
+			return m, nil
+
+		case key.Matches(msg, reviewKeys.SubmitTo):
+			if m.review.HasPending() && len(m.targets) > 1 {
+				m.choosingTarget = true
+				m.targetIndex = 0
 			}
 			return m, nil
 
+		case key.Matches(msg, reviewKeys.WriteTodo):
+			if m.review.HasPending() {
+				m.formatFeedback()
+				m.toTodo = true
+				m.submitted = true
+				m.markSent()
+			}
+			return m, nil
+
+		case key.Matches(msg, reviewKeys.Approve):
+			if !m.review.HasPending() {
+				m.approved = true
+				m.submitted = true
+				_ = review.Delete(m.sessionID)
+			}
+			return m, nil
+
+		case key.Matches(msg, reviewKeys.Open):
+			if len(m.flatLines) > 0 && m.flatLines[m.flatIndex].binary {
+				return m, m.openBinary(m.flatLines[m.flatIndex])
+			}
+
+		case key.Matches(msg, reviewKeys.Edit):
+			if len(m.flatLines) > 0 {
+				return m, m.editAt(m.flatLines[m.flatIndex])
+			}
+
+		case key.Matches(msg, reviewKeys.Load):
+			if len(m.flatLines) > 0 && m.flatLines[m.flatIndex].unloaded {
+				m.loadFile(m.flatLines[m.flatIndex])
+			}
+			return m, nil
+
+		case key.Matches(msg, reviewKeys.Refresh):
+			return m.refresh(), nil
+
+		case key.Matches(msg, reviewKeys.Source):
+			return m.openSources(), nil
+
+		case key.Matches(msg, reviewKeys.Blame):
+			m.showBlame = !m.showBlame
+			if m.showBlame && m.blame == nil {
+				m.loadBlame()
+			}
+			m.updateViewportContent()
+			return m, nil
+
 		case key.Matches(msg, reviewKeys.Pause):
-			_ = m.review.Save()
+			m.review.Anchor(m.diff)
+			_ = saveReview(m.review)
 			m.cancelled = true
 			return m, nil
 		}
+		m.trackProgress(prev)
 	}
 
 	// Update viewport
@@ -335,20 +666,322 @@ func (m *ReviewModel) addCommentAtCursor() {
 		lineNum = fl.line.OldNum
 	}
 
-	// Remove existing comment at this location first
-	for i, c := range m.review.Comments {
-		if c.FilePath == filePath && c.HunkIndex == fl.hunkIndex && c.LineIndex == fl.lineIndex {
+	text := strings.TrimSpace(m.textarea.Value())
+
+	// Edit an existing comment in place, keeping its thread; a changed
+	// comment is sent again.
+	if i := m.commentIndexAtCursor(); i >= 0 {
+		c := &m.review.Comments[i]
+		switch {
+		case text == "":
 			m.review.RemoveComment(i)
-			break
+		case text != c.Text:
+			c.Text = text
+			c.SentAt = time.Time{}
 		}
+		return
 	}
 
-	text := strings.TrimSpace(m.textarea.Value())
 	if text != "" {
 		m.review.AddComment(filePath, lineNum, fl.hunkIndex, fl.lineIndex, text)
 	}
 }
 
+// commentIndexAtCursor returns the index of the comment on the cursor's
+// line, or -1.
+func (m ReviewModel) commentIndexAtCursor() int {
+	if m.flatIndex >= len(m.flatLines) {
+		return -1
+	}
+	fl := m.flatLines[m.flatIndex]
+	if fl.isHeader || fl.line == nil {
+		return -1
+	}
+	for i, c := range m.review.Comments {
+		if !c.Outdated && c.FilePath == fl.file.GetFilePath() && c.HunkIndex == fl.hunkIndex && c.LineIndex == fl.lineIndex {
+			return i
+		}
+	}
+	return -1
+}
+
+// addReplyAtCursor continues the thread on the cursor's line.
+func (m *ReviewModel) addReplyAtCursor() {
+	text := strings.TrimSpace(m.textarea.Value())
+	if i := m.commentIndexAtCursor(); i >= 0 && text != "" {
+		m.review.AddReply(i, review.AuthorYou, text)
+	}
+}
+
+// markSent records that the pending threads went to the agent and keeps
+// the review, anchored to the diff, so its answers can join the threads.
+func (m *ReviewModel) markSent() {
+	m.review.MarkSent(time.Now())
+	m.review.Anchor(m.diff)
+	_ = saveReview(m.review)
+}
+
+// recordAgentReplies adds what the agent said in its transcript since the
+// feedback was last sent to the threads it answers.
+func (m *ReviewModel) recordAgentReplies(transcriptPath string) {
+	since := m.review.LastSent()
+	if since.IsZero() {
+		return
+	}
+	if m.review.RecordAgentReply(transcript.ReplySince(transcriptPath, since), time.Now()) > 0 {
+		_ = saveReview(m.review)
+		m.updateViewportContent()
+	}
+}
+
+func (fl flatLine) hunkKey() string {
+	return review.HunkKey(fl.file.GetFilePath(), fl.hunk)
+}
+
+// trackProgress marks hunks read as the cursor moves forward from prev:
+// every hunk it passed, and the current one once its last line (or a
+// binary file's entry) is reached.
+func (m *ReviewModel) trackProgress(prev int) {
+	if m.flatIndex <= prev || m.flatIndex >= len(m.flatLines) {
+		return
+	}
+	cur := m.flatLines[m.flatIndex]
+	marked := false
+	mark := func(k string) {
+		if !m.review.IsViewed(k) {
+			m.review.MarkViewed(k)
+			marked = true
+		}
+	}
+	for i := prev; i < m.flatIndex; i++ {
+		if k := m.flatLines[i].hunkKey(); k != cur.hunkKey() {
+			mark(k)
+		}
+	}
+	if cur.binary || cur.unloaded || (cur.hunk != nil && cur.lineIndex == len(cur.hunk.Lines)-1) {
+		mark(cur.hunkKey())
+	}
+	if marked {
+		m.updateViewportContent()
+	}
+}
+
+// resumeAtFirstUnviewed puts the cursor on the first hunk not yet read when
+// a review with some reading done is reopened.
+func (m *ReviewModel) resumeAtFirstUnviewed() {
+	if len(m.review.Viewed) == 0 {
+		return
+	}
+	for i, fl := range m.flatLines {
+		if (fl.isHeader || fl.binary) && !m.review.IsViewed(fl.hunkKey()) {
+			m.flatIndex = i
+			return
+		}
+	}
+}
+
+// fileProgress counts the read and total hunks of file fi.
+func (m ReviewModel) fileProgress(fi int) (viewed, total int) {
+	f := &m.diff.Files[fi]
+	return m.review.Progress(&diff.Diff{Files: []diff.FileDiff{*f}})
+}
+
+// formatFeedback renders the feedback with the configured template, falling
+// back to the default wording if the template is broken.
+func (m *ReviewModel) formatFeedback() {
+	text, err := m.review.FormatFeedbackWith(m.diff, m.feedbackTmpl)
+	if err != nil {
+		m.feedbackErr = err
+		text = m.review.FormatFeedback(m.diff)
+	}
+	m.feedbackText = text
+}
+
+// updateTargetChoice picks the session to send feedback to; enter submits.
+func (m ReviewModel) updateTargetChoice(msg tea.KeyMsg) ReviewModel {
+	switch msg.String() {
+	case "esc":
+		m.choosingTarget = false
+	case "k", "up":
+		if m.targetIndex > 0 {
+			m.targetIndex--
+		}
+	case "j", "down":
+		if m.targetIndex < len(m.targets)-1 {
+			m.targetIndex++
+		}
+	case "enter":
+		m.choosingTarget = false
+		if m.targetIndex > 0 {
+			m.target = m.targets[m.targetIndex].key
+		}
+		m.formatFeedback()
+		m.submitted = true
+		m.markSent()
+	}
+	return m
+}
+
+func (m ReviewModel) renderTargetChoice() string {
+	var sb strings.Builder
+	sb.WriteString(i18n.T("Send feedback to:") + "\n")
+	for i, t := range m.targets {
+		label := t.name
+		if i == 0 {
+			label += " " + i18n.T("(reviewed)")
+		}
+		if i == m.targetIndex {
+			sb.WriteString(reviewSelectedStyle.Render("▸ "+label) + "\n")
+		} else {
+			sb.WriteString("  " + label + "\n")
+		}
+	}
+	return reviewCommentInputStyle.Render(strings.TrimSuffix(sb.String(), "\n"))
+}
+
+// openBinary opens the working copy of a binary file and, when it existed
+// at HEAD, that version too, in the system's default viewer — side by side
+// for a before/after look at an image.
+func (m ReviewModel) openBinary(fl flatLine) tea.Cmd {
+	sizes := m.binarySizes[fl.fileIndex]
+	dir, f := m.projectPath, *fl.file
+	return func() tea.Msg {
+		if sizes[0] >= 0 {
+			if old, err := diff.ExtractOld(dir, f.OldPath); err == nil {
+				_ = openExternally(old)
+			}
+		}
+		if sizes[1] >= 0 {
+			_ = openExternally(filepath.Join(dir, f.NewPath))
+		}
+		return nil
+	}
+}
+
+// renderThread renders a comment, after label, and its replies a line
+// each.
+func (m ReviewModel) renderThread(c review.Comment, label string) string {
+	sent := ""
+	if !c.Pending() {
+		sent = "  ✓ " + i18n.T("sent")
+	}
+	out := reviewCommentStyle.Render("     💬 "+label+c.Text) + reviewLineNumStyle.Render(sent) + "\n"
+	for _, rp := range c.Replies {
+		text := truncateLines(strings.Join(strings.Fields(rp.Text), " "), maxInt(10, m.viewport.Width-18))
+		out += reviewLineNumStyle.Render("        ↳ "+rp.Author+": ") + reviewCommentStyle.Render(text) + "\n"
+	}
+	return out
+}
+
+// editAt opens the working copy of fl's file at fl's line: in $EDITOR,
+// suspending herd, or through the editor URL when one is configured.
+func (m ReviewModel) editAt(fl flatLine) tea.Cmd {
+	path := filepath.Join(m.projectPath, fl.file.GetFilePath())
+	line := fl.newLine()
+	if m.editorURL != "" {
+		url := strings.NewReplacer("{path}", path, "{line}", fmt.Sprint(line)).Replace(m.editorURL)
+		return func() tea.Msg { return fileEditedMsg{err: openExternally(url)} }
+	}
+	return tea.ExecProcess(editorCommandAt(path, line), func(err error) tea.Msg {
+		return fileEditedMsg{err: err}
+	})
+}
+
+// newLine is the line of the working copy fl stands for: a removed line
+// maps to where it was, a hunk header to the hunk's first line.
+func (fl flatLine) newLine() int {
+	switch {
+	case fl.binary || fl.hunk == nil:
+		return 1
+	case fl.isHeader || fl.line == nil:
+		return max(1, fl.hunk.NewStart)
+	case fl.line.NewNum > 0:
+		return fl.line.NewNum
+	}
+	line := fl.hunk.NewStart
+	for _, l := range fl.hunk.Lines[:fl.lineIndex] {
+		if l.Type != diff.LineRemoved {
+			line++
+		}
+	}
+	return max(1, line)
+}
+
+// renderBinary describes a binary file's change in place of its hunks.
+func (m ReviewModel) renderBinary(fl flatLine) string {
+	sizes := m.binarySizes[fl.fileIndex]
+	kind := i18n.T("binary file")
+	if fl.file.IsImage() {
+		kind = i18n.T("image")
+	}
+	var desc string
+	switch {
+	case sizes[0] < 0 && sizes[1] < 0:
+		desc = i18n.Tf("%s changed", kind)
+	case sizes[0] < 0:
+		desc = i18n.Tf("%s added, %s", kind, formatSize(sizes[1]))
+	case sizes[1] < 0:
+		desc = i18n.Tf("%s deleted, was %s", kind, formatSize(sizes[0]))
+	default:
+		delta := sizes[1] - sizes[0]
+		sign := "+"
+		if delta < 0 {
+			sign, delta = "-", -delta
+		}
+		desc = fmt.Sprintf("%s  %s → %s (%s%s)", kind, formatSize(sizes[0]), formatSize(sizes[1]), sign, formatSize(delta))
+	}
+	if sizes[0] >= 0 && sizes[1] >= 0 {
+		desc += "  " + i18n.T("[o] open before/after")
+	} else {
+		desc += "  " + i18n.T("[o] open")
+	}
+	return reviewHunkStyle.Render(desc)
+}
+
+// loadBlame blames every text file of the diff that HEAD has. A file git
+// can't blame is left unannotated.
+func (m *ReviewModel) loadBlame() {
+	m.blame = make(map[string][]git.Blame)
+	for _, f := range m.diff.Files {
+		if f.Binary || f.OldPath == "" || f.OldPath == "/dev/null" {
+			continue
+		}
+		if lines, err := git.BlameLines(m.projectPath, f.OldPath); err == nil {
+			m.blame[f.OldPath] = lines
+		}
+	}
+}
+
+// blameColumn renders who last changed a context line and how long ago, or
+// blanks of the same width for the lines the diff adds or removes.
+func (m ReviewModel) blameColumn(fl flatLine) string {
+	const width = 21
+	lines := m.blame[fl.file.OldPath]
+	if fl.line.Type != diff.LineContext || fl.line.OldNum < 1 || fl.line.OldNum > len(lines) {
+		return strings.Repeat(" ", width)
+	}
+	b := lines[fl.line.OldNum-1]
+	author := []rune(b.Author)
+	if len(author) > 10 {
+		author = append(author[:9], '…')
+	}
+	return fmt.Sprintf("%-10s %9s ", string(author), ageString(time.Since(b.Time)))
+}
+
+// formatSize renders a byte count with a binary unit.
+func formatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
 func (m *ReviewModel) jumpToNextHunk() {
 	if len(m.flatLines) == 0 {
 		return
@@ -442,7 +1075,7 @@ func (m *ReviewModel) ensureVisible() {
 		fl := m.flatLines[i]
 		if !fl.isHeader {
 			if c := m.review.GetCommentForLine(fl.file.GetFilePath(), fl.hunkIndex, fl.lineIndex); c != nil {
-				lineInView++ // Comment takes an extra line
+				lineInView += 1 + len(c.Replies) // Comment and thread take extra lines
 			}
 		}
 	}
@@ -457,7 +1090,7 @@ func (m *ReviewModel) ensureVisible() {
 
 func (m *ReviewModel) updateViewportContent() {
 	if len(m.flatLines) == 0 {
-		m.viewport.SetContent("No changes to review")
+		m.viewport.SetContent(i18n.T("No changes to review"))
 		return
 	}
 
@@ -471,12 +1104,42 @@ func (m *ReviewModel) updateViewportContent() {
 			if sb.Len() > 0 {
 				sb.WriteString("\n")
 			}
-			sb.WriteString(reviewFileStyle.Render("─── "+fl.file.GetFilePath()+" ───") + "\n")
+			header := reviewFileStyle.Render("─── " + fl.file.GetFilePath() + " ───")
+			switch viewed, total := m.fileProgress(fl.fileIndex); {
+			case viewed == total:
+				header += reviewLineNumStyle.Render("  ✓ viewed")
+			case viewed == 0:
+				header += reviewUnviewedStyle.Render("  ● unviewed")
+			default:
+				header += reviewLineNumStyle.Render(fmt.Sprintf("  %d/%d hunks viewed", viewed, total))
+			}
+			sb.WriteString(header + "\n")
+			// Threads whose line has since changed stay visible at the top.
+			for _, c := range m.review.Comments {
+				if c.Outdated && c.FilePath == fl.file.GetFilePath() {
+					sb.WriteString(m.renderThread(c, i18n.Tf("line %d changed: ", c.LineNum)))
+				}
+			}
 		}
 
 		isSelected := i == m.flatIndex
 
-		if fl.isHeader {
+		if fl.unloaded {
+			line := reviewHunkStyle.Render(i18n.Tf("large diff, %d lines not loaded  [enter] load", fl.file.Unloaded))
+			if n := len(m.review.GetCommentsForFile(fl.file.GetFilePath())); n > 0 {
+				line += reviewLineNumStyle.Render(fmt.Sprintf("  %d comments", n))
+			}
+			if isSelected {
+				line = reviewSelectedStyle.Render(line)
+			}
+			sb.WriteString(line + "\n")
+		} else if fl.binary {
+			line := m.renderBinary(fl)
+			if isSelected {
+				line = reviewSelectedStyle.Render(line)
+			}
+			sb.WriteString(line + "\n")
+		} else if fl.isHeader {
 			line := reviewHunkStyle.Render(fl.hunk.Header)
 			if isSelected {
 				line = reviewSelectedStyle.Render(line)
@@ -506,16 +1169,18 @@ func (m *ReviewModel) updateViewportContent() {
 
 			content := style.Render(prefix + fl.line.Content)
 			line := reviewLineNumStyle.Render(lineNum) + content
+			if m.showBlame {
+				line = reviewLineNumStyle.Render(lineNum) + reviewBlameStyle.Render(m.blameColumn(fl)) + content
+			}
 
 			if isSelected {
-				line = reviewSelectedStyle.Width(m.width).Render(line)
+				line = reviewSelectedStyle.Width(m.viewport.Width).Render(line)
 			}
 			sb.WriteString(line + "\n")
 
-			// Show comment if any
+			// Show comment and its thread if any
 			if c := m.review.GetCommentForLine(fl.file.GetFilePath(), fl.hunkIndex, fl.lineIndex); c != nil {
-				commentLine := "     💬 " + c.Text
-				sb.WriteString(reviewCommentStyle.Render(commentLine) + "\n")
+				sb.WriteString(m.renderThread(*c, ""))
 			}
 		}
 	}
@@ -525,11 +1190,11 @@ func (m *ReviewModel) updateViewportContent() {
 
 func (m ReviewModel) View() string {
 	if !m.ready {
-		return "Loading..."
+		return i18n.T("Loading...")
 	}
 
 	if m.diff.IsEmpty() {
-		return "No changes to review"
+		return i18n.T("No changes to review")
 	}
 
 	// Header
@@ -537,23 +1202,38 @@ func (m ReviewModel) View() string {
 	if len(m.flatLines) > 0 && m.flatIndex < len(m.flatLines) {
 		currentFile = m.flatLines[m.flatIndex].file.GetFilePath()
 	}
+	counts := i18n.Tf("%d/%d files, %d comments", m.currentFileIndex()+1, m.diff.TotalFiles(), len(m.review.Comments))
+	if m.viewOnly {
+		counts = i18n.Tf("%d/%d files", m.currentFileIndex()+1, m.diff.TotalFiles())
+	}
+	if n := m.outdatedComments(); n > 0 {
+		counts += ", " + i18n.Tf("%d outdated", n)
+	}
+	if m.ignored > 0 {
+		counts += ", " + i18n.Tf("%d ignored", m.ignored)
+	}
+	if viewed, total := m.review.Progress(m.diff); total > 0 {
+		counts += ", " + i18n.Tf("%d%% read", viewed*100/total)
+	}
 	header := reviewHeaderStyle.Width(m.width).Render(
-		fmt.Sprintf("Review: %s  (%d/%d files, %d comments)",
-			currentFile,
-			m.currentFileIndex()+1,
-			m.diff.TotalFiles(),
-			len(m.review.Comments),
-		),
+		i18n.Tf("Review: %s  (%s)", currentFile, counts),
 	)
 
 	// Main content
 	content := m.viewport.View()
 
-	// Comment input overlay
-	if m.commenting {
+	// Comment input or target chooser overlay
+	if m.commenting || m.choosingTarget {
+		label := i18n.T("Comment:")
+		if m.replying {
+			label = i18n.T("Reply:")
+		}
 		inputBox := reviewCommentInputStyle.Render(
-			"Comment:\n" + m.textarea.View(),
+			label + "\n" + m.textarea.View(),
 		)
+		if m.choosingTarget {
+			inputBox = m.renderTargetChoice()
+		}
 		// Center the input box
 		lines := strings.Split(content, "\n")
 		midLine := len(lines) / 2
@@ -562,15 +1242,59 @@ func (m ReviewModel) View() string {
 		}
 		content = strings.Join(lines, "\n")
 	}
+	if m.treeVisible {
+		content = lipgloss.JoinHorizontal(lipgloss.Top, m.renderTree(m.viewport.Height), content)
+	}
 
 	// Help
-	helpText := "[j/k] navigate  [n/N] hunk  [f/F] file  [c] comment  [x] delete  [s] submit  [p] pause  [q] cancel"
+	helpText := "[j/k] navigate  [n/N] hunk  [f/F] file  [c] comment  [r] reply  [x] delete  [e] edit  [R] refresh  [d] source  [b] blame  [s/S] submit (to…)  [w] to todo file  [a] approve  [p] pause  [t] tree  [q] cancel"
+	if len(m.flatLines) > 0 && m.flatIndex < len(m.flatLines) && m.flatLines[m.flatIndex].binary {
+		helpText = "[j/k] navigate  [f/F] file  [o] open externally  [s] submit  [a] approve  [p] pause  [t] tree  [q] cancel"
+	}
+	if len(m.flatLines) > 0 && m.flatIndex < len(m.flatLines) && m.flatLines[m.flatIndex].unloaded {
+		helpText = "[j/k] navigate  [f/F] file  [enter] load  [R] refresh  [s] submit  [a] approve  [p] pause  [t] tree  [q] cancel"
+	}
+	if m.viewOnly {
+		helpText = "[j/k] navigate  [n/N] hunk  [f/F] file  [e] edit  [R] refresh  [d] source  [b] blame  [t] tree  [q] quit"
+		if len(m.flatLines) > 0 && m.flatIndex < len(m.flatLines) {
+			switch fl := m.flatLines[m.flatIndex]; {
+			case fl.binary:
+				helpText = "[j/k] navigate  [f/F] file  [o] open externally  [t] tree  [q] quit"
+			case fl.unloaded:
+				helpText = "[j/k] navigate  [f/F] file  [enter] load  [R] refresh  [t] tree  [q] quit"
+			}
+		}
+	}
 	if m.commenting {
 		helpText = "[Enter] save comment  [Esc] cancel"
+	} else if m.choosingTarget {
+		helpText = "[j/k] select  [enter] send  [esc] back to review"
+	} else if m.treeFocus {
+		helpText = "[j/k] select  [enter] open file / fold dir  [tab/esc] back to diff  [t] hide tree"
+	} else if m.enteringRev {
+		helpText = "[enter] show  [esc] back to sources"
+	} else if m.choosingSource {
+		helpText = "[h/l] select  [enter] show  [esc] back to review"
+	}
+	helpText = i18n.T(helpText)
+	if m.notice != "" {
+		helpText = m.notice + "  " + helpText
 	}
 	help := reviewHelpStyle.Width(m.width).Render(helpText)
 
-	return lipgloss.JoinVertical(lipgloss.Left, header, content, help)
+	return lipgloss.JoinVertical(lipgloss.Left, header, m.renderSources(), content, help)
+}
+
+// outdatedComments counts comments whose line left the diff since the review
+// was paused. They are still sent with the feedback.
+func (m ReviewModel) outdatedComments() int {
+	n := 0
+	for _, c := range m.review.Comments {
+		if c.Outdated {
+			n++
+		}
+	}
+	return n
 }
 
 func (m ReviewModel) currentFileIndex() int {
@@ -585,6 +1309,11 @@ func (m ReviewModel) Submitted() bool {
 	return m.submitted
 }
 
+// Approved returns true if the review was submitted as an approval.
+func (m ReviewModel) Approved() bool {
+	return m.approved
+}
+
 // Cancelled returns true if the review was cancelled.
 func (m ReviewModel) Cancelled() bool {
 	return m.cancelled
@@ -594,3 +1323,26 @@ func (m ReviewModel) Cancelled() bool {
 func (m ReviewModel) FeedbackText() string {
 	return m.feedbackText
 }
+
+// ToTodo returns true if the feedback should be written to the repo's
+// review todo file instead of being sent to a session.
+func (m ReviewModel) ToTodo() bool {
+	return m.toTodo
+}
+
+// FeedbackErr returns the error from the configured feedback template, if
+// the default wording had to be used instead.
+func (m ReviewModel) FeedbackErr() error {
+	return m.feedbackErr
+}
+
+// Target returns the key of the session chosen to receive the feedback, or
+// "" for the reviewed session.
+func (m ReviewModel) Target() string {
+	return m.target
+}
+
+// Review returns the review being edited, with its comments.
+func (m ReviewModel) Review() *review.Review {
+	return m.review
+}
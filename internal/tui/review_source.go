@@ -0,0 +1,159 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/shnupta/herd/internal/diff"
+	"github.com/shnupta/herd/internal/i18n"
+)
+
+// reviewSources are the diffs `d` chooses between. The commit and range
+// ones are starting points for the revision typed in.
+var reviewSources = []diff.Source{
+	{Kind: diff.SourceWorking},
+	{Kind: diff.SourceStaged},
+	{Kind: diff.SourceCommit, Rev: "HEAD"},
+	{Kind: diff.SourceRange, Rev: "origin/main...HEAD"},
+}
+
+// revKinds names the sources that take a revision, in English; they are
+// translated where shown.
+var revKinds = map[diff.SourceKind]string{
+	diff.SourceCommit: "commit",
+	diff.SourceRange:  "range",
+}
+
+var reviewSourceStyle = lipgloss.NewStyle().
+	Foreground(colAccent).
+	Bold(true)
+
+// openSources puts the cursor on the source chooser, at the diff shown.
+func (m ReviewModel) openSources() ReviewModel {
+	m.choosingSource = true
+	m.sourceIndex = 0
+	for i, src := range reviewSources {
+		if src.Kind == m.diffOpts.Source.Kind {
+			m.sourceIndex = i
+		}
+	}
+	return m
+}
+
+func (m ReviewModel) updateSourceChoice(msg tea.KeyMsg) (ReviewModel, tea.Cmd) {
+	if m.enteringRev {
+		switch msg.String() {
+		case "esc":
+			m.enteringRev = false
+		case "enter":
+			rev := strings.TrimSpace(m.revInput.Value())
+			if rev == "" {
+				return m, nil
+			}
+			m.enteringRev = false
+			m.choosingSource = false
+			src := reviewSources[m.sourceIndex]
+			src.Rev = rev
+			return m.switchSource(src), nil
+		default:
+			var cmd tea.Cmd
+			m.revInput, cmd = m.revInput.Update(msg)
+			return m, cmd
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "esc", "d":
+		m.choosingSource = false
+	case "h", "left":
+		if m.sourceIndex > 0 {
+			m.sourceIndex--
+		}
+	case "l", "right", "tab":
+		if m.sourceIndex < len(reviewSources)-1 {
+			m.sourceIndex++
+		}
+	case "enter":
+		src := reviewSources[m.sourceIndex]
+		if src.Kind == diff.SourceWorking || src.Kind == diff.SourceStaged {
+			m.choosingSource = false
+			return m.switchSource(src), nil
+		}
+		// Start from the revision shown, if it is of this kind.
+		rev := src.Rev
+		if m.diffOpts.Source.Kind == src.Kind {
+			rev = m.diffOpts.Source.Rev
+		}
+		m.revInput = textinput.New()
+		m.revInput.Prompt = ""
+		m.revInput.SetValue(rev)
+		m.enteringRev = true
+		return m, m.revInput.Focus()
+	}
+	return m, nil
+}
+
+// switchSource shows src's diff, keeping the one shown if it can't be read
+// or has no changes.
+func (m ReviewModel) switchSource(src diff.Source) ReviewModel {
+	opts := m.diffOpts
+	opts.Source = src
+	parsed, err := diff.GitDiff(m.projectPath, opts)
+	if err != nil {
+		m.notice = sourceLabel(src) + ": " + err.Error()
+		return m
+	}
+	ignored := parsed.Ignore(m.ignore)
+	if parsed.IsEmpty() {
+		m.notice = i18n.Tf("no changes in %s", sourceLabel(src))
+		return m
+	}
+	m.diffOpts = opts
+	m.ignored = ignored
+	return m.replaceDiff(parsed)
+}
+
+// renderSources renders the row of diff sources under the header, the one
+// shown highlighted.
+func (m ReviewModel) renderSources() string {
+	shown := m.diffOpts.Source
+	var parts []string
+	for i, src := range reviewSources {
+		label := sourceLabel(src)
+		switch {
+		case m.enteringRev && i == m.sourceIndex:
+			label = i18n.T(revKinds[src.Kind]) + ": " + m.revInput.View()
+		case src.Kind == shown.Kind:
+			label = sourceLabel(shown)
+		case revKinds[src.Kind] != "":
+			label = i18n.T(revKinds[src.Kind]) + "…"
+		}
+		switch {
+		case m.choosingSource && i == m.sourceIndex:
+			label = reviewSelectedStyle.Render("▸ " + label)
+		case src.Kind == shown.Kind:
+			label = reviewSourceStyle.Render(label)
+		default:
+			label = reviewLineNumStyle.Render(label)
+		}
+		parts = append(parts, label)
+	}
+	return " " + reviewLineNumStyle.Render(i18n.T("Diff:")) + " " + strings.Join(parts, "  ")
+}
+
+// sourceLabel names src for display, as src.String does in English.
+func sourceLabel(src diff.Source) string {
+	switch src.Kind {
+	case diff.SourceStaged:
+		return i18n.T("staged")
+	case diff.SourceCommit:
+		return i18n.Tf("commit %s", src.Rev)
+	case diff.SourceRange:
+		return src.Rev
+	}
+	return i18n.T("working tree")
+}
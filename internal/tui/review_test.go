@@ -0,0 +1,595 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/shnupta/herd/internal/diff"
+	"github.com/shnupta/herd/internal/review"
+	"github.com/shnupta/herd/internal/tmux/tmuxtest"
+)
+
+func TestReviewBinaryFileEntry(t *testing.T) {
+	origSizes, origOpen := binarySizes, openExternally
+	defer func() { binarySizes, openExternally = origSizes, origOpen }()
+	binarySizes = func(dir string, f *diff.FileDiff) (int64, int64) { return 2048, 3584 }
+	var opened []string
+	openExternally = func(path string) error {
+		opened = append(opened, path)
+		return nil
+	}
+
+	d, err := diff.Parse("diff --git a/logo.png b/logo.png\n" +
+		"Binary files a/logo.png and b/logo.png differ\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rm := NewReviewModel(d, "test-binary-entry", t.TempDir())
+	updated, _ := rm.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	rm = updated.(ReviewModel)
+
+	view := rm.View()
+	for _, want := range []string{"logo.png", "image", "2.0 KiB → 3.5 KiB (+1.5 KiB)", "before/after"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+
+	updated, cmd := rm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("o")})
+	if cmd == nil {
+		t.Fatal("o on a binary file should open it")
+	}
+	cmd()
+	// HEAD can't be extracted from the empty temp dir, so only the working
+	// copy opens.
+	if len(opened) != 1 || !strings.HasSuffix(opened[0], "logo.png") {
+		t.Errorf("opened %q, want the working copy", opened)
+	}
+
+	// Binary entries don't take comments.
+	updated, _ = updated.(ReviewModel).Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	if updated.(ReviewModel).commenting {
+		t.Error("c on a binary file started a comment")
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	for n, want := range map[int64]string{0: "0 B", 1023: "1023 B", 1536: "1.5 KiB", 5 << 20: "5.0 MiB"} {
+		if got := formatSize(n); got != want {
+			t.Errorf("formatSize(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestReviewFileTreeJumpsToFile(t *testing.T) {
+	d, err := diff.Parse("diff --git a/cmd/main.go b/cmd/main.go\n" +
+		"--- a/cmd/main.go\n+++ b/cmd/main.go\n" +
+		"@@ -1,2 +1,2 @@\n ctx\n-old\n+new\n" +
+		"diff --git a/internal/a.go b/internal/a.go\n" +
+		"--- a/internal/a.go\n+++ b/internal/a.go\n" +
+		"@@ -1 +1,3 @@\n ctx\n+one\n+two\n" +
+		"diff --git a/internal/b.go b/internal/b.go\n" +
+		"--- a/internal/b.go\n+++ b/internal/b.go\n" +
+		"@@ -1,2 +1 @@\n ctx\n-gone\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rm := NewReviewModel(d, "test-file-tree", t.TempDir())
+	rm.review.AddComment("internal/b.go", 1, 0, 0, "why?")
+	updated, _ := rm.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	key := func(k string) {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		if k == "enter" {
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		}
+		updated, _ = updated.(ReviewModel).Update(msg)
+	}
+
+	key("t")
+	rm = updated.(ReviewModel)
+	if !rm.treeVisible || !rm.treeFocus {
+		t.Fatal("t should show and focus the tree")
+	}
+	view := rm.View()
+	for _, want := range []string{"cmd/", "internal/", "a.go", "+2 -0", "b.go", "+0 -1", "💬1"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("tree missing %q:\n%s", want, view)
+		}
+	}
+
+	// Rows: cmd/, main.go, internal/, a.go, b.go.
+	key("j")
+	key("j")
+	key("j")
+	key("j")
+	key("enter")
+	rm = updated.(ReviewModel)
+	if rm.treeFocus || rm.currentFileIndex() != 2 {
+		t.Fatalf("enter on b.go: focus %v, file %d; want diff focus on file 2", rm.treeFocus, rm.currentFileIndex())
+	}
+
+	// Folding a directory hides its files.
+	key("t")
+	key("k")
+	key("k")
+	key("enter")
+	if rows := updated.(ReviewModel).treeRows(); len(rows) != 3 {
+		t.Errorf("after folding internal/ the tree has %d rows, want 3", len(rows))
+	}
+	key("t")
+	if updated.(ReviewModel).treeVisible {
+		t.Error("t with the tree focused should hide it")
+	}
+}
+
+func TestReviewReadProgress(t *testing.T) {
+	d, err := diff.Parse("diff --git a/a.go b/a.go\n" +
+		"--- a/a.go\n+++ b/a.go\n" +
+		"@@ -1,2 +1,2 @@\n ctx\n-old\n+new\n" +
+		"@@ -20,1 +20,2 @@\n ctx\n+more\n" +
+		"diff --git a/b.go b/b.go\n" +
+		"--- a/b.go\n+++ b/b.go\n" +
+		"@@ -1 +1 @@\n-x\n+y\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rm := NewReviewModel(d, "test-read-progress", t.TempDir())
+	updated, _ := rm.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	if view := updated.(ReviewModel).View(); !strings.Contains(view, "0% read") || !strings.Contains(view, "unviewed") {
+		t.Fatalf("fresh review should be unread:\n%s", view)
+	}
+
+	// Down to the last line of a.go's first hunk, then jump a hunk.
+	for range 4 {
+		updated, _ = updated.(ReviewModel).Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	}
+	rm = updated.(ReviewModel)
+	if viewed, _ := rm.review.Progress(d); viewed != 1 {
+		t.Fatalf("reaching a hunk's last line should mark it read, %d read", viewed)
+	}
+	updated, _ = rm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	updated, _ = updated.(ReviewModel).Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	rm = updated.(ReviewModel)
+	if viewed, total := rm.review.Progress(d); viewed != 2 || total != 3 {
+		t.Fatalf("after skipping past the second hunk: %d/%d read, want 2/3", viewed, total)
+	}
+	if view := rm.View(); !strings.Contains(view, "66% read") || !strings.Contains(view, "✓ viewed") {
+		t.Errorf("header or file marker missing:\n%s", view)
+	}
+
+	// Reopening starts at the first unread hunk: b.go's.
+	resumed := NewReviewModel(d, "test-read-progress", t.TempDir())
+	resumed.review = rm.review
+	resumed.resumeAtFirstUnviewed()
+	if fl := resumed.flatLines[resumed.flatIndex]; fl.fileIndex != 1 || !fl.isHeader {
+		t.Errorf("resumed at file %d line %d, want b.go's hunk header", fl.fileIndex, fl.lineIndex)
+	}
+}
+
+func TestReviewFeedbackToAnotherSession(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+
+	d, err := diff.Parse("diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -1 +1 @@\n-x\n+y\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rm := NewReviewModel(d, "test-feedback-target", t.TempDir())
+	rm.review.AddComment("a.go", 1, 0, 1, "rename y")
+	rm.targets = m.reviewTargets(m.sessions[0])
+	updated, _ := rm.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	rm = updated.(ReviewModel)
+	m.reviewModel = &rm
+	m.mode = ModeReview
+
+	m = pressKey(t, m, "S")
+	if !m.reviewModel.choosingTarget {
+		t.Fatal("S should offer a choice of sessions")
+	}
+	if view := m.View(); !strings.Contains(view, "project-alpha (reviewed)") || !strings.Contains(view, "project-gamma") {
+		t.Errorf("target chooser missing sessions:\n%s", view)
+	}
+	m = pressKey(t, m, "j")
+	m = pressKey(t, m, "enter")
+
+	if m.mode != ModeNormal {
+		t.Fatalf("mode = %d, want normal after submitting", m.mode)
+	}
+	mock := m.tmuxClient.(*tmuxtest.MockClient)
+	if len(mock.SendKeysCalls) != 1 || !strings.HasPrefix(mock.SendKeysCalls[0], "%2:Review of your recent changes") {
+		t.Errorf("SendKeysCalls = %q, want the feedback sent to %%2", mock.SendKeysCalls)
+	}
+}
+
+func TestReviewFeedbackToTodoFile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+
+	repo := t.TempDir()
+	d, err := diff.Parse("diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -1 +1 @@\n-x\n+y\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rm := NewReviewModel(d, "test-feedback-todo", repo)
+	rm.review.AddComment("a.go", 1, 0, 1, "rename y")
+	updated, _ := rm.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	rm = updated.(ReviewModel)
+	m.reviewModel = &rm
+	m.mode = ModeReview
+
+	m = pressKey(t, m, "w")
+	mock := m.tmuxClient.(*tmuxtest.MockClient)
+	if len(mock.SendKeysCalls) != 0 {
+		t.Errorf("feedback was typed into a pane: %q", mock.SendKeysCalls)
+	}
+	got, err := os.ReadFile(filepath.Join(repo, "TODO.review.md"))
+	if err != nil || !strings.Contains(string(got), "rename y") {
+		t.Errorf("todo file = %q, %v", got, err)
+	}
+}
+
+func TestReviewEditAtLine(t *testing.T) {
+	orig := openExternally
+	defer func() { openExternally = orig }()
+	var opened []string
+	openExternally = func(path string) error {
+		opened = append(opened, path)
+		return nil
+	}
+
+	d, err := diff.Parse("diff --git a/main.go b/main.go\n" +
+		"--- a/main.go\n" +
+		"+++ b/main.go\n" +
+		"@@ -10,3 +10,3 @@\n" +
+		" keep\n" +
+		"-old\n" +
+		"+new\n" +
+		" tail\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rm := NewReviewModel(d, "test-edit-at-line", "/repo")
+	rm.editorURL = "vscode://file{path}:{line}"
+	var lines []int
+	for _, fl := range rm.flatLines {
+		lines = append(lines, fl.newLine())
+	}
+	// Header, keep, old (removed where new now is), new, tail.
+	if want := []int{10, 10, 11, 11, 12}; !slices.Equal(lines, want) {
+		t.Errorf("lines = %v, want %v", lines, want)
+	}
+
+	rm.flatIndex = 3
+	updated, cmd := rm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	rm = updated.(ReviewModel)
+	if cmd == nil {
+		t.Fatal("e should open the editor")
+	}
+	updated, _ = rm.Update(cmd())
+	rm = updated.(ReviewModel)
+	if len(opened) != 1 || opened[0] != "vscode://file/repo/main.go:11" {
+		t.Errorf("opened %q, want the URL for main.go line 11", opened)
+	}
+	// /repo isn't a repository, so only the refresh that follows may complain.
+	if strings.HasPrefix(rm.notice, "editor:") {
+		t.Errorf("notice = %q", rm.notice)
+	}
+}
+
+func TestReviewThreadCarriesTheAgentsAnswer(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	var saved *review.Review
+	saveReview = func(r *review.Review) error { saved = r; return nil }
+
+	d, err := diff.Parse("diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -1 +1 @@\n-x\n+y\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rm := NewReviewModel(d, "test-review-thread", t.TempDir())
+	rm.review.AddComment("a.go", 1, 0, 1, "rename y")
+	updated, _ := rm.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	rm = updated.(ReviewModel)
+	m.reviewModel = &rm
+	m.mode = ModeReview
+
+	m = pressKey(t, m, "s")
+	if saved == nil || saved.HasPending() || saved.LastSent().IsZero() {
+		t.Fatalf("submitted review saved = %+v, want it kept with the thread marked sent", saved)
+	}
+
+	// The agent answers; reopening the review pulls its reply in.
+	path := filepath.Join(t.TempDir(), "transcript.jsonl")
+	line := fmt.Sprintf(`{"type":"assistant","timestamp":%q,"message":{"content":[{"type":"text","text":"Renamed y to total in a.go."}]}}`,
+		saved.LastSent().Add(time.Second).Format(time.RFC3339Nano))
+	if err := os.WriteFile(path, []byte(line+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rm = NewReviewModel(d, "test-review-thread", t.TempDir())
+	rm.review = saved
+	updated, _ = rm.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	rm = updated.(ReviewModel)
+	rm.recordAgentReplies(path)
+	if view := rm.View(); !strings.Contains(view, "agent: Renamed y to total in a.go.") || !strings.Contains(view, "✓ sent") {
+		t.Errorf("view lacks the agent's answer:\n%s", view)
+	}
+
+	// A reply reopens the thread for the next submit.
+	rm.flatIndex = 2
+	updated, _ = rm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	rm = updated.(ReviewModel)
+	rm.textarea.SetValue("call it sum instead")
+	updated, _ = rm.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	rm = updated.(ReviewModel)
+	c := rm.review.Comments[0]
+	if len(c.Replies) != 2 || c.Replies[1].Author != review.AuthorYou || !rm.review.HasPending() {
+		t.Fatalf("replies = %+v, want the reviewer's reply pending", c.Replies)
+	}
+	if fb, _ := rm.review.FormatFeedbackWith(d, review.DefaultTemplate); !strings.Contains(fb, "agent: Renamed y to total in a.go.\nyou: call it sum instead") {
+		t.Errorf("feedback lacks the thread:\n%s", fb)
+	}
+}
+
+func TestReviewRefreshKeepsPosition(t *testing.T) {
+	repo := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		if out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(text string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repo, "a.go"), []byte(text), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run("init")
+	run("config", "user.email", "test@test.com")
+	run("config", "user.name", "Test")
+	write("one\ntwo\nthree\n")
+	run("add", ".")
+	run("commit", "-m", "first")
+	write("one\nTWO\nthree\n")
+
+	text, err := diff.GetGitDiff(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := diff.Parse(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rm := NewReviewModel(d, "test-review-refresh", repo)
+	updated, _ := rm.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	rm = updated.(ReviewModel)
+	for i, fl := range rm.flatLines {
+		if fl.line != nil && fl.line.Content == "TWO" {
+			rm.flatIndex = i
+		}
+	}
+	rm.textarea.SetValue("shouting")
+	rm.addCommentAtCursor()
+
+	// The agent adds lines above; the cursor and comment follow "+TWO".
+	write("zero\nhalf\none\nTWO\nthree\n")
+	updated, _ = rm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("R")})
+	rm = updated.(ReviewModel)
+	if rm.notice != "" {
+		t.Fatalf("refresh failed: %s", rm.notice)
+	}
+	fl := rm.flatLines[rm.flatIndex]
+	if fl.line == nil || fl.line.Content != "TWO" || fl.line.NewNum != 4 {
+		t.Errorf("cursor on %+v, want +TWO at line 4", fl.line)
+	}
+	if c := rm.review.GetCommentForLine("a.go", fl.hunkIndex, fl.lineIndex); c == nil || c.Text != "shouting" {
+		t.Errorf("comment did not follow its line: %+v", rm.review.Comments)
+	}
+	if !strings.Contains(rm.View(), "+zero") {
+		t.Error("view should show the new changes")
+	}
+}
+
+func TestReviewBlameAnnotatesContextLines(t *testing.T) {
+	repo := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		if out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	run("init")
+	run("config", "user.email", "ada@test.com")
+	run("config", "user.name", "Ada")
+	if err := os.WriteFile(filepath.Join(repo, "a.go"), []byte("one\ntwo\nthree\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	run("add", ".")
+	run("commit", "-m", "first")
+	if err := os.WriteFile(filepath.Join(repo, "a.go"), []byte("one\nTWO\nthree\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	text, err := diff.GetGitDiff(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := diff.Parse(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rm := NewReviewModel(d, "test-review-blame", repo)
+	updated, _ := rm.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	rm = updated.(ReviewModel)
+	if strings.Contains(rm.View(), "Ada") {
+		t.Fatal("blame should be off until toggled")
+	}
+
+	updated, _ = rm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b")})
+	rm = updated.(ReviewModel)
+	var annotated []string
+	for _, line := range strings.Split(rm.View(), "\n") {
+		if strings.Contains(line, "Ada") {
+			annotated = append(annotated, line)
+		}
+	}
+	// The context lines one and three, not the changed ones.
+	if len(annotated) != 2 || !strings.Contains(annotated[0], "one") || !strings.Contains(annotated[1], "three") {
+		t.Fatalf("blamed lines = %q", annotated)
+	}
+	if !strings.Contains(annotated[0], "0m ago") {
+		t.Errorf("blame should show the commit's age: %q", annotated[0])
+	}
+
+	updated, _ = rm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b")})
+	rm = updated.(ReviewModel)
+	if strings.Contains(rm.View(), "Ada") {
+		t.Error("b again should hide the blame")
+	}
+}
+
+func TestReviewLoadsBigFilesOnDemand(t *testing.T) {
+	repo := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		if out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, text string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repo, name), []byte(text), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run("init")
+	run("config", "user.email", "test@test.com")
+	run("config", "user.name", "Test")
+	write("big.lock", "")
+	write("small.go", "a\n")
+	run("add", ".")
+	run("commit", "-m", "first")
+	write("big.lock", strings.Repeat("dep\n", 40))
+	write("small.go", "b\n")
+
+	opts := diff.Options{LazyFileLines: 20}
+	d, err := diff.GitDiff(repo, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rm := NewReviewModel(d, "test-review-lazy", repo)
+	rm.diffOpts = opts
+	rm.loadWanted()
+	updated, _ := rm.Update(tea.WindowSizeMsg{Width: 120, Height: 60})
+	rm = updated.(ReviewModel)
+	if !strings.Contains(rm.View(), "large diff, 40 lines not loaded") {
+		t.Fatal("the big file should be left unloaded")
+	}
+	if n := len(rm.flatLines); n != 4 { // big.lock's entry, then small.go's header and two lines
+		t.Fatalf("got %d entries, want 4", n)
+	}
+
+	rm.flatIndex = 0
+	updated, _ = rm.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	rm = updated.(ReviewModel)
+	if rm.diff.Files[0].Unloaded != 0 || len(rm.flatLines) != 1+40+3 {
+		t.Fatalf("enter should load the big file: %d entries", len(rm.flatLines))
+	}
+
+	// A comment keeps the file loaded across a refresh.
+	rm.flatIndex = 5
+	rm.textarea.SetValue("why this dep?")
+	rm.addCommentAtCursor()
+	rm = rm.refresh()
+	if rm.diff.Files[0].Unloaded != 0 || rm.outdatedComments() != 0 {
+		t.Errorf("refresh unloaded the commented file or lost the comment: %+v", rm.review.Comments)
+	}
+}
+
+func TestReviewSwitchesDiffSource(t *testing.T) {
+	repo := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		if out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, text string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repo, name), []byte(text), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run("init")
+	run("config", "user.email", "test@test.com")
+	run("config", "user.name", "Test")
+	write("committed.go", "a\n")
+	run("add", ".")
+	run("commit", "-m", "first")
+	write("staged.go", "b\n")
+	run("add", "staged.go")
+	write("committed.go", "changed\n")
+
+	d, err := diff.GitDiff(repo, diff.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rm := NewReviewModel(d, "test-review-source", repo)
+	updated, _ := rm.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	rm = updated.(ReviewModel)
+	press := func(keys ...string) {
+		t.Helper()
+		for _, k := range keys {
+			msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+			switch k {
+			case "enter":
+				msg = tea.KeyMsg{Type: tea.KeyEnter}
+			case "ctrl+u":
+				msg = tea.KeyMsg{Type: tea.KeyCtrlU}
+			}
+			updated, _ := rm.Update(msg)
+			rm = updated.(ReviewModel)
+		}
+	}
+	files := func() []string {
+		var out []string
+		for _, f := range rm.diff.Files {
+			out = append(out, f.GetFilePath())
+		}
+		return out
+	}
+
+	press("d", "l", "enter")
+	if got := files(); len(got) != 1 || got[0] != "staged.go" || rm.choosingSource {
+		t.Fatalf("staged diff shows %v", got)
+	}
+	if !strings.Contains(rm.View(), "staged") {
+		t.Error("the source row should name the staged diff")
+	}
+
+	// The commit entry asks for a revision, starting at HEAD.
+	press("d", "l", "enter")
+	if !rm.enteringRev || rm.revInput.Value() != "HEAD" {
+		t.Fatalf("entering %v, revision %q", rm.enteringRev, rm.revInput.Value())
+	}
+	press("enter")
+	if got := files(); len(got) != 1 || got[0] != "committed.go" || rm.diffOpts.Source.Rev != "HEAD" {
+		t.Fatalf("commit diff shows %v from %+v", got, rm.diffOpts.Source)
+	}
+
+	// A revision git doesn't know keeps the diff shown.
+	press("d", "enter", "ctrl+u", "n", "o", "p", "e", "enter")
+	if rm.notice == "" || rm.diffOpts.Source.Rev != "HEAD" {
+		t.Errorf("unknown commit: notice %q, source %+v", rm.notice, rm.diffOpts.Source)
+	}
+}
@@ -0,0 +1,206 @@
+package tui
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/shnupta/herd/internal/diff"
+	"github.com/shnupta/herd/internal/i18n"
+)
+
+// reviewTreeWidth is the width of the file tree panel, border included.
+const reviewTreeWidth = 34
+
+// treeRow is one row of the file tree: a directory, or a file when file is
+// a diff file index.
+type treeRow struct {
+	dir  string
+	file int
+}
+
+// treeRows lists the changed files grouped under their directories, sorted
+// by path, leaving out files in collapsed directories.
+func (m ReviewModel) treeRows() []treeRow {
+	order := make([]int, len(m.diff.Files))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return m.diff.Files[order[a]].GetFilePath() < m.diff.Files[order[b]].GetFilePath()
+	})
+
+	var rows []treeRow
+	lastDir := "\x00"
+	for _, fi := range order {
+		dir := path.Dir(m.diff.Files[fi].GetFilePath())
+		if dir != lastDir {
+			rows = append(rows, treeRow{dir: dir, file: -1})
+			lastDir = dir
+		}
+		if !m.treeCollapsed[dir] {
+			rows = append(rows, treeRow{dir: dir, file: fi})
+		}
+	}
+	return rows
+}
+
+// diffWidth is the width left for the diff beside the tree.
+func (m ReviewModel) diffWidth() int {
+	if m.treeVisible {
+		return maxInt(20, m.width-reviewTreeWidth)
+	}
+	return m.width
+}
+
+// toggleTree shows and focuses the tree, or hides it if it already has focus.
+func (m ReviewModel) toggleTree() ReviewModel {
+	if m.treeVisible && m.treeFocus {
+		m.treeVisible = false
+		m.treeFocus = false
+	} else {
+		m.treeVisible = true
+		m.treeFocus = true
+		m.treeCursor = m.treeRowForFile(m.currentFileIndex())
+	}
+	m.viewport.Width = m.diffWidth()
+	m.updateViewportContent()
+	return m
+}
+
+func (m ReviewModel) treeRowForFile(fi int) int {
+	for i, r := range m.treeRows() {
+		if r.file == fi {
+			return i
+		}
+	}
+	return 0
+}
+
+// updateTree handles keys while the tree has focus.
+func (m ReviewModel) updateTree(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	rows := m.treeRows()
+	switch msg.String() {
+	case "t":
+		return m.toggleTree(), nil
+	case "esc", "tab":
+		m.treeFocus = false
+	case "k", "up":
+		if m.treeCursor > 0 {
+			m.treeCursor--
+		}
+	case "j", "down":
+		if m.treeCursor < len(rows)-1 {
+			m.treeCursor++
+		}
+	case "enter", " ", "l", "h":
+		if m.treeCursor >= len(rows) {
+			return m, nil
+		}
+		row := rows[m.treeCursor]
+		if row.file < 0 {
+			if m.treeCollapsed == nil {
+				m.treeCollapsed = make(map[string]bool)
+			}
+			m.treeCollapsed[row.dir] = !m.treeCollapsed[row.dir]
+			return m, nil
+		}
+		m.jumpToFile(row.file)
+		m.treeFocus = false
+		m.updateViewportContent()
+		m.ensureVisible()
+	}
+	return m, nil
+}
+
+// jumpToFile moves the cursor to the first line of file fi.
+func (m *ReviewModel) jumpToFile(fi int) {
+	for i, fl := range m.flatLines {
+		if fl.fileIndex == fi {
+			m.flatIndex = i
+			return
+		}
+	}
+}
+
+// diffStat counts a file's added and removed lines.
+func diffStat(f *diff.FileDiff) (added, removed int) {
+	for _, h := range f.Hunks {
+		for _, l := range h.Lines {
+			switch l.Type {
+			case diff.LineAdded:
+				added++
+			case diff.LineRemoved:
+				removed++
+			}
+		}
+	}
+	return added, removed
+}
+
+func (m ReviewModel) renderTree(height int) string {
+	rows := m.treeRows()
+	current := m.currentFileIndex()
+	inner := reviewTreeWidth - 1
+	subtle := lipgloss.NewStyle().Foreground(colSubtle)
+	added := lipgloss.NewStyle().Foreground(colGreen)
+	removed := lipgloss.NewStyle().Foreground(colRed)
+	comments := lipgloss.NewStyle().Foreground(colAmber)
+
+	// Keep the cursor in view.
+	first := 0
+	if m.treeCursor >= height {
+		first = m.treeCursor - height + 1
+	}
+	var lines []string
+	for i := first; i < len(rows) && len(lines) < height; i++ {
+		row := rows[i]
+		var line string
+		if row.file < 0 {
+			arrow := "▾ "
+			if m.treeCollapsed[row.dir] {
+				arrow = "▸ "
+			}
+			line = subtle.Render(ansi.Truncate(arrow+row.dir+"/", inner, "…"))
+		} else {
+			f := &m.diff.Files[row.file]
+			var stat string
+			if f.Binary {
+				stat = subtle.Render(i18n.T("bin"))
+			} else if f.Unloaded > 0 {
+				stat = subtle.Render(i18n.Tf("%d lines", f.Unloaded))
+			} else {
+				a, r := diffStat(f)
+				stat = added.Render(fmt.Sprintf("+%d", a)) + " " + removed.Render(fmt.Sprintf("-%d", r))
+			}
+			if n := len(m.review.GetCommentsForFile(f.GetFilePath())); n > 0 {
+				stat += " " + comments.Render(fmt.Sprintf("💬%d", n))
+			}
+			marker := "  "
+			if row.file == current {
+				marker = "● "
+			}
+			name := ansi.Truncate(marker+f.GetFileName(), maxInt(4, inner-lipgloss.Width(stat)-1), "…")
+			if viewed, _ := m.fileProgress(row.file); viewed == 0 {
+				name = reviewUnviewedStyle.Render(name)
+			}
+			line = name + strings.Repeat(" ", maxInt(1, inner-lipgloss.Width(name)-lipgloss.Width(stat))) + stat
+		}
+		if m.treeFocus && i == m.treeCursor {
+			line = reviewSelectedStyle.Width(inner).Render(line)
+		}
+		lines = append(lines, line)
+	}
+	return lipgloss.NewStyle().
+		Width(inner).
+		Height(height).
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderRight(true).
+		BorderForeground(colBorder).
+		Render(strings.Join(lines, "\n"))
+}
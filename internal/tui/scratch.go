@@ -0,0 +1,43 @@
+package tui
+
+import (
+	"github.com/shnupta/herd/internal/session"
+)
+
+// scratchPane returns the live scratch shell pane for s, creating one split
+// below the session's pane (in its project directory) when none exists.
+// The pane ID is remembered per session key so later calls reuse it.
+func (m *Model) scratchPane(s session.Session) (string, error) {
+	if id := m.scratch.Get(s.Key()); id != "" {
+		if panes, err := m.tmuxClient.ListPanes(); err == nil {
+			for _, p := range panes {
+				if p.ID == id {
+					return id, nil
+				}
+			}
+		}
+	}
+	id, err := m.tmuxClient.SplitWindow(s.TmuxPane, s.ProjectPath, "")
+	if err != nil {
+		return "", err
+	}
+	_ = m.scratch.Set(s.Key(), id)
+	return id, nil
+}
+
+// toggleScratch jumps to the selected session's scratch pane, opening it
+// first if needed.
+func (m *Model) toggleScratch() {
+	sel := m.selectedSession()
+	if sel == nil {
+		return
+	}
+	id, err := m.scratchPane(*sel)
+	if err != nil {
+		m.err = err
+		return
+	}
+	if err := m.tmuxClient.SwitchToPane(id); err != nil {
+		m.err = err
+	}
+}
@@ -0,0 +1,53 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/shnupta/herd/internal/tmux"
+	"github.com/shnupta/herd/internal/tmux/tmuxtest"
+)
+
+func TestScratchOpensOnceThenReuses(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	mock := m.tmuxClient.(*tmuxtest.MockClient)
+	mock.SplitWindowPane = "%50"
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	m = updated.(Model)
+	if len(mock.SplitCalls) != 1 {
+		t.Fatalf("SplitCalls = %v, want one split", mock.SplitCalls)
+	}
+	if len(mock.SwitchedPanes) != 1 || mock.SwitchedPanes[0] != "%50" {
+		t.Errorf("SwitchedPanes = %v, want [%%50]", mock.SwitchedPanes)
+	}
+
+	// The scratch pane is now live, so the next toggle just jumps to it.
+	mock.Panes = append(mock.Panes, tmux.Pane{ID: "%50", CurrentCmd: "zsh"})
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	m = updated.(Model)
+	if len(mock.SplitCalls) != 1 {
+		t.Errorf("SplitCalls = %v, want the existing scratch pane reused", mock.SplitCalls)
+	}
+	if len(mock.SwitchedPanes) != 2 || mock.SwitchedPanes[1] != "%50" {
+		t.Errorf("SwitchedPanes = %v, want a second jump to %%50", mock.SwitchedPanes)
+	}
+}
+
+func TestScratchReopensWhenPaneIsGone(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	mock := m.tmuxClient.(*tmuxtest.MockClient)
+	_ = m.scratch.Set("session:sess-aaa", "%77") // stale: not in mock.Panes
+	mock.SplitWindowPane = "%51"
+
+	id, err := m.scratchPane(m.sessions[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "%51" || m.scratch.Get("session:sess-aaa") != "%51" {
+		t.Errorf("scratchPane() = %q (stored %q), want a fresh %%51", id, m.scratch.Get("session:sess-aaa"))
+	}
+}
@@ -0,0 +1,96 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/shnupta/herd/internal/groups"
+	"github.com/shnupta/herd/internal/history"
+	"github.com/shnupta/herd/internal/names"
+	"github.com/shnupta/herd/internal/script"
+	"github.com/shnupta/herd/internal/session"
+	"github.com/shnupta/herd/internal/tmux"
+)
+
+// scriptsDoneMsg reports that event handlers finished, so names and groups
+// they set can be picked up.
+type scriptsDoneMsg struct{ errs []error }
+
+// scriptAPI carries out script requests against tmux and herd's stores,
+// recording what it sends in the history log at historyPath.
+type scriptAPI struct {
+	client      tmux.ClientIface
+	historyPath string
+}
+
+func (a scriptAPI) SendKeys(pane, text string) error {
+	if err := checkPane(a.client, pane); err != nil {
+		return err
+	}
+	if err := a.client.SendKeys(pane, text); err != nil {
+		return err
+	}
+	_ = history.AppendTo(a.historyPath, history.Event{Kind: history.KindAction, TmuxPane: pane, Action: history.ActionPrompt, Trigger: "script", Detail: history.Text(text)})
+	return nil
+}
+
+func (a scriptAPI) Notify(message string) error    { return a.client.DisplayMessage(message) }
+func (scriptAPI) SetGroup(key, group string) error { return groups.Set(key, group) }
+
+func (scriptAPI) SetName(key, name string) error {
+	if name == "" {
+		return names.Delete(key)
+	}
+	return names.Set(key, name)
+}
+
+// fireScripts runs the handlers for events off the UI goroutine. It is a
+// no-op when no script listens for any of them.
+func (m Model) fireScripts(events ...script.Event) tea.Cmd {
+	var wanted []script.Event
+	for _, ev := range events {
+		if m.scripts.Handlers(ev.Name) > 0 {
+			wanted = append(wanted, ev)
+		}
+	}
+	if len(wanted) == 0 {
+		return nil
+	}
+	engine := m.scripts
+	return func() tea.Msg {
+		var errs []error
+		for _, ev := range wanted {
+			errs = append(errs, engine.Fire(ev)...)
+		}
+		return scriptsDoneMsg{errs: errs}
+	}
+}
+
+// stateChangeEvents compares sessions' states before and after a hook update
+// and returns a state_change event for each session whose state moved.
+func stateChangeEvents(before map[string]session.State, sessions []session.Session) []script.Event {
+	var events []script.Event
+	for _, s := range sessions {
+		prev, ok := before[s.TmuxPane]
+		if !ok || prev == s.State {
+			continue
+		}
+		events = append(events, script.Event{Name: script.StateChange, Fields: map[string]string{
+			"key":            s.Key(),
+			"pane":           s.TmuxPane,
+			"session_id":     s.ID,
+			"project":        s.ProjectPath,
+			"state":          s.State.String(),
+			"previous_state": prev.String(),
+		}})
+	}
+	return events
+}
+
+// sessionStates snapshots each session's state by pane.
+func sessionStates(sessions []session.Session) map[string]session.State {
+	out := make(map[string]session.State, len(sessions))
+	for _, s := range sessions {
+		out[s.TmuxPane] = s.State
+	}
+	return out
+}
@@ -0,0 +1,70 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shnupta/herd/internal/script"
+	"github.com/shnupta/herd/internal/state"
+	"github.com/shnupta/herd/internal/tmux"
+	"github.com/shnupta/herd/internal/tmux/tmuxtest"
+)
+
+func TestStateChangeFiresScripts(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	// Closed up front so the re-armed watcher wait returns instead of blocking
+	// flattenBatch.
+	fw.Close()
+	mock := m.tmuxClient.(*tmuxtest.MockClient)
+
+	dir := t.TempDir()
+	src := `herd.on("state_change", function(ev) herd.notify(ev.pane .. " " .. ev.previous_state .. "→" .. ev.state) end)`
+	if err := os.WriteFile(filepath.Join(dir, "notify.lua"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	engine, errs := script.Load(dir, scriptAPI{client: mock, historyPath: filepath.Join(dir, "history.jsonl")})
+	if len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	defer engine.Close()
+	m.scripts = engine
+
+	// Same state: no event.
+	_, cmd := m.Update(stateUpdateMsg(state.SessionState{SessionID: "sess-aaa", TmuxPane: "%1", State: "working"}))
+	for _, msg := range flattenBatch(cmd) {
+		if _, ok := msg.(scriptsDoneMsg); ok {
+			t.Fatal("unchanged state should not fire scripts")
+		}
+	}
+
+	_, cmd = m.Update(stateUpdateMsg(state.SessionState{SessionID: "sess-aaa", TmuxPane: "%1", State: "waiting"}))
+	var fired bool
+	for _, msg := range flattenBatch(cmd) {
+		if done, ok := msg.(scriptsDoneMsg); ok {
+			fired = true
+			if len(done.errs) != 0 {
+				t.Errorf("script errors = %v", done.errs)
+			}
+		}
+	}
+	if !fired || len(mock.Messages) != 1 || mock.Messages[0] != "%1 working→waiting" {
+		t.Errorf("Messages = %v, want one working→waiting notification", mock.Messages)
+	}
+}
+
+func TestScriptSendKeysChecksPane(t *testing.T) {
+	mock := &tmuxtest.MockClient{Panes: []tmux.Pane{{ID: "%1", CurrentCmd: "claude"}, {ID: "%2", CurrentCmd: "zsh"}}}
+	api := scriptAPI{client: mock, historyPath: filepath.Join(t.TempDir(), "history.jsonl")}
+	if err := api.SendKeys("%1", "status?"); err != nil {
+		t.Fatalf("SendKeys to Claude: %v", err)
+	}
+	for _, pane := range []string{"%2", "%3"} {
+		if err := api.SendKeys(pane, "status?"); err == nil {
+			t.Errorf("SendKeys to %s went through", pane)
+		}
+	}
+	if len(mock.SendKeysCalls) != 1 {
+		t.Errorf("SendKeysCalls = %q, want only the one to %%1", mock.SendKeysCalls)
+	}
+}
@@ -0,0 +1,209 @@
+package tui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/shnupta/herd/internal/i18n"
+)
+
+// searchScrollback is how many lines of history a search pulls in, more
+// than the viewport keeps, so output that has scrolled off can be found.
+const searchScrollback = 10000
+
+// outputSearch is a search through the selected session's output. Its
+// matches are highlighted in the viewport, which shows the session's
+// plain text while searching.
+type outputSearch struct {
+	input   textinput.Model
+	pane    string
+	lines   []string // the pane's output, without colours
+	matches []searchMatch
+	current int  // index into matches
+	loading bool // still pulling in the deeper scrollback
+}
+
+// searchMatch is where a match is: bytes start to end of line.
+type searchMatch struct {
+	line, start, end int
+}
+
+// searchCaptureMsg carries the deeper scrollback captured for a search.
+type searchCaptureMsg struct {
+	pane, content string
+}
+
+func isSearchCaptureMsg(msg tea.Msg) bool { _, ok := msg.(searchCaptureMsg); return ok }
+
+var (
+	searchMatchStyle   = lipgloss.NewStyle().Reverse(true)
+	searchCurrentStyle = lipgloss.NewStyle().Background(colGold).Foreground(lipgloss.Color("#000000"))
+)
+
+// openSearch starts searching the selected session's output, first what
+// the viewport already has and then, once captured, its deeper scrollback.
+func (m Model) openSearch() (Model, tea.Cmd) {
+	sel := m.selectedSession()
+	if sel == nil {
+		return m, nil
+	}
+	ti := textinput.New()
+	ti.Prompt = "?"
+	ti.Placeholder = i18n.T("search output")
+	ti.CharLimit = 200
+
+	pane := sel.TmuxPane
+	m.search = &outputSearch{
+		input:   ti,
+		pane:    pane,
+		lines:   plainLines(m.displayCapture(pane, m.lastCapture)),
+		loading: true,
+	}
+	m.mode = ModeSearch
+	client := m.tmuxClient
+	return m, tea.Batch(m.search.input.Focus(), func() tea.Msg {
+		content, _ := client.CapturePane(pane, searchScrollback)
+		return searchCaptureMsg{pane: pane, content: content}
+	})
+}
+
+func plainLines(content string) []string {
+	return strings.Split(ansi.Strip(content), "\n")
+}
+
+func (m Model) updateSearchMode(msg tea.Msg) (tea.Model, tea.Cmd) {
+	s := m.search
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m = m.recalcLayout()
+		m.showMatches()
+		return m, nil
+
+	case searchCaptureMsg:
+		if msg.pane == s.pane {
+			s.loading = false
+			if msg.content != "" {
+				s.lines = plainLines(m.displayCapture(msg.pane, msg.content))
+				s.find()
+				m.showMatches()
+			}
+		}
+		return m, nil
+
+	case captureMsg:
+		// The viewport shows the search until it closes.
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			m.closeSearch()
+			return m, nil
+		case "enter", "up", "ctrl+p":
+			s.step(-1)
+			m.showMatches()
+			return m, nil
+		case "down", "ctrl+n":
+			s.step(1)
+			m.showMatches()
+			return m, nil
+		}
+	}
+	var cmd tea.Cmd
+	before := s.input.Value()
+	s.input, cmd = s.input.Update(msg)
+	if s.input.Value() != before {
+		s.find()
+		m.showMatches()
+	}
+	return m, cmd
+}
+
+// find lists the matches of the query, ignoring case unless it has
+// capitals, and makes the last — the newest output — current.
+func (s *outputSearch) find() {
+	s.matches = s.matches[:0]
+	q := s.input.Value()
+	if q == "" {
+		return
+	}
+	pattern := regexp.QuoteMeta(q)
+	if strings.IndexFunc(q, unicode.IsUpper) < 0 {
+		pattern = "(?i)" + pattern
+	}
+	re := regexp.MustCompile(pattern)
+	for i, line := range s.lines {
+		for _, loc := range re.FindAllStringIndex(line, -1) {
+			s.matches = append(s.matches, searchMatch{line: i, start: loc[0], end: loc[1]})
+		}
+	}
+	s.current = len(s.matches) - 1
+}
+
+// step moves to the match delta away, -1 being the one above, wrapping
+// around the ends.
+func (s *outputSearch) step(delta int) {
+	if n := len(s.matches); n > 0 {
+		s.current = ((s.current+delta)%n + n) % n
+	}
+}
+
+// showMatches puts the searched output in the viewport with the matches
+// highlighted, scrolled to put the current one mid-screen.
+func (m *Model) showMatches() {
+	s := m.search
+	lines := make([]string, len(s.lines))
+	copy(lines, s.lines)
+	// Highlight from the end of each line so earlier offsets stay valid.
+	for i := len(s.matches) - 1; i >= 0; i-- {
+		mt := s.matches[i]
+		style := searchMatchStyle
+		if i == s.current {
+			style = searchCurrentStyle
+		}
+		l := lines[mt.line]
+		lines[mt.line] = l[:mt.start] + style.Render(s.lines[mt.line][mt.start:mt.end]) + l[mt.end:]
+	}
+	m.viewport.SetContent(truncateLines(strings.Join(lines, "\n"), m.viewport.Width))
+	if len(s.matches) == 0 {
+		m.viewport.GotoBottom()
+		return
+	}
+	m.viewport.SetYOffset(s.matches[s.current].line - m.viewport.Height/2)
+}
+
+// closeSearch goes back to the session's live output.
+func (m *Model) closeSearch() {
+	m.search = nil
+	m.mode = ModeNormal
+	m.lastCapture = ""
+	m.forceViewportRefresh = true
+	m.pendingGotoBottom = true
+}
+
+// renderSearchBar is the help line while searching: the query, how many
+// matches it has and the keys.
+func (m Model) renderSearchBar() string {
+	s := m.search
+	var count string
+	switch {
+	case s.input.Value() == "":
+	case len(s.matches) == 0:
+		count = i18n.T("no matches")
+	default:
+		count = fmt.Sprintf("%d/%d", s.current+1, len(s.matches))
+	}
+	if s.loading {
+		count = strings.TrimSpace(count + " " + i18n.T("(reading scrollback…)"))
+	}
+	return styleHelpFilter.Width(m.width).Render("  " + s.input.View() + "  " + count + "  " + i18n.T("[enter/↑] older  [↓] newer  [esc] close"))
+}
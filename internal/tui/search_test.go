@@ -0,0 +1,56 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSearchOutput(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	m.lastCapture = "recent line\n"
+
+	m = pressKey(t, m, "?")
+	if m.mode != ModeSearch || m.search == nil {
+		t.Fatalf("mode = %v, want ModeSearch", m.mode)
+	}
+	updated, _ := m.Update(searchCaptureMsg{pane: "%1", content: "foo bar\nbaz\nFoo again, foo\n"})
+	m = updated.(Model)
+	if m.search.loading {
+		t.Error("still loading after the scrollback arrived")
+	}
+
+	m = pressKey(t, m, "foo")
+	if got := len(m.search.matches); got != 3 {
+		t.Fatalf("%d matches for foo, want 3 (case ignored)", got)
+	}
+	if cur := m.search.matches[m.search.current]; cur.line != 2 || cur.start != 11 {
+		t.Errorf("current match = %+v, want the newest", cur)
+	}
+	m = pressKey(t, m, "enter")
+	m = pressKey(t, m, "enter")
+	if cur := m.search.matches[m.search.current]; cur.line != 0 {
+		t.Errorf("two older matches up is %+v, want line 0", cur)
+	}
+	m = pressKey(t, m, "up")
+	if m.search.current != 2 {
+		t.Errorf("current = %d, want wrapped around to the newest", m.search.current)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	m = updated.(Model)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	m = updated.(Model)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	m = updated.(Model)
+	m = pressKey(t, m, "Foo")
+	if got := len(m.search.matches); got != 1 {
+		t.Errorf("%d matches for Foo, want 1 (capitals match case)", got)
+	}
+
+	m = pressKey(t, m, "esc")
+	if m.mode != ModeNormal || m.search != nil || !m.forceViewportRefresh {
+		t.Errorf("after esc: mode = %v, search = %v", m.mode, m.search)
+	}
+}
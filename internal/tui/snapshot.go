@@ -0,0 +1,56 @@
+package tui
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/shnupta/herd/internal/config"
+	"github.com/shnupta/herd/internal/names"
+	"github.com/shnupta/herd/internal/snapshot"
+)
+
+// snapshotDoneMsg reports a saved, and possibly uploaded, snapshot.
+type snapshotDoneMsg struct {
+	path string
+	link string
+	err  error
+}
+
+// takeSnapshot saves the selected session's captured output as an HTML page
+// and uploads it when snapshot_upload is configured, off the UI goroutine.
+func (m Model) takeSnapshot() tea.Cmd {
+	sel := m.selectedSession()
+	if sel == nil || m.lastCapture == "" {
+		return nil
+	}
+	meta := snapshot.Meta{
+		Name:    sel.DisplayName(),
+		Project: sel.ProjectPath,
+		Branch:  sel.GitBranch,
+		State:   sel.State.String(),
+		Taken:   time.Now(),
+	}
+	if name := names.Get(sel.Key()); name != "" {
+		meta.Name = name
+	}
+	capture, dir := m.displayCapture(sel.TmuxPane, m.lastCapture), m.snapshotDir
+	upload := config.Load().SnapshotUpload
+	return func() tea.Msg {
+		page, err := snapshot.HTML(meta, capture)
+		if err != nil {
+			return snapshotDoneMsg{err: err}
+		}
+		path, err := snapshot.Save(dir, meta, page)
+		if err != nil {
+			return snapshotDoneMsg{err: err}
+		}
+		if upload.URL == "" {
+			return snapshotDoneMsg{path: path}
+		}
+		link, err := snapshot.Upload(context.Background(), upload, filepath.Base(path), page)
+		return snapshotDoneMsg{path: path, link: link, err: err}
+	}
+}
@@ -0,0 +1,43 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/shnupta/herd/internal/tmux/tmuxtest"
+)
+
+func TestSnapshotSavesHTML(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	m.lastCapture = "\x1b[31mtests failed\x1b[0m\n\n"
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("H")})
+	if cmd == nil {
+		t.Fatal("H returned no command")
+	}
+	done, ok := m.takeSnapshot()().(snapshotDoneMsg)
+	if !ok {
+		t.Fatal("takeSnapshot did not report back")
+	}
+	if done.err != nil || filepath.Dir(done.path) != m.snapshotDir {
+		t.Fatalf("snapshot = %+v, want a page in %s", done, m.snapshotDir)
+	}
+	page, err := os.ReadFile(done.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(page), `<span style="color:#cd3131">tests failed</span>`) {
+		t.Errorf("page missing the coloured output:\n%s", page)
+	}
+
+	updated, _ = updated.(Model).Update(done)
+	mock := updated.(Model).tmuxClient.(*tmuxtest.MockClient)
+	if len(mock.Messages) == 0 || !strings.Contains(mock.Messages[len(mock.Messages)-1], done.path) {
+		t.Errorf("messages = %v, want the saved path", mock.Messages)
+	}
+}
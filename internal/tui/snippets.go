@@ -0,0 +1,165 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/shnupta/herd/internal/i18n"
+)
+
+// visibleOutput returns the lines of the selected session's output currently
+// on screen in the viewport, without colour codes.
+func (m Model) visibleOutput() string {
+	pane := ""
+	if sel := m.selectedSession(); sel != nil {
+		pane = sel.TmuxPane
+	}
+	lines := strings.Split(m.displayCapture(pane, m.lastCapture), "\n")
+	start := minInt(m.viewport.YOffset, len(lines))
+	end := minInt(start+m.viewport.Height, len(lines))
+	visible := lines[start:end]
+	for i, l := range visible {
+		visible[i] = strings.TrimRight(ansi.Strip(l), " ")
+	}
+	return strings.Trim(strings.Join(visible, "\n"), "\n")
+}
+
+// openSnippetCapture grabs the visible output and asks for a snippet name.
+func (m Model) openSnippetCapture() (Model, tea.Cmd) {
+	sel := m.selectedSession()
+	if sel == nil {
+		return m, nil
+	}
+	text := m.visibleOutput()
+	if text == "" {
+		return m, nil
+	}
+	key := sel.Key()
+	in := textinput.New()
+	in.Placeholder = i18n.T("snippet name...")
+	in.CharLimit = 100
+	in.SetValue(fmt.Sprintf("snippet %d", len(m.snippets.Get(key))+1))
+	return m.openPrompt(ModeSnippetName, promptOverlay{
+		title:   i18n.T("Save Snippet"),
+		input:   in,
+		preview: text,
+		help:    i18n.Tf("[enter] save %d lines  [esc] cancel", strings.Count(text, "\n")+1),
+		parse: func(name string) (tea.Msg, error) {
+			if name == "" {
+				name = "snippet"
+			}
+			return snippetSaveMsg{key: key, name: name, text: text}, nil
+		},
+	})
+}
+
+// openSnippets shows the selected session's saved snippets.
+func (m Model) openSnippets() Model {
+	sel := m.selectedSession()
+	if sel == nil {
+		return m
+	}
+	m.snippetKey = sel.Key()
+	m.snippetList = m.snippets.Get(sel.Key())
+	m.snippetSelected = maxInt(0, len(m.snippetList)-1) // newest
+	m.snippetView = viewport.New(m.width, m.snippetPreviewHeight())
+	m.mode = ModeSnippets
+	return m.showSnippet()
+}
+
+// snippetPreviewHeight leaves room for the title, up to eight list rows and
+// the help line.
+func (m Model) snippetPreviewHeight() int {
+	return maxInt(1, m.height-6-minInt(len(m.snippetList), 8))
+}
+
+// showSnippet loads the selected snippet into the preview.
+func (m Model) showSnippet() Model {
+	m.snippetView.Height = m.snippetPreviewHeight()
+	if m.snippetSelected < len(m.snippetList) {
+		m.snippetView.SetContent(truncateLines(m.snippetList[m.snippetSelected].Text, m.snippetView.Width))
+	} else {
+		m.snippetView.SetContent("")
+	}
+	m.snippetView.GotoTop()
+	return m
+}
+
+func (m Model) updateSnippetsMode(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m = m.recalcLayout()
+		m.snippetView.Width = m.width
+		return m.showSnippet(), nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "q":
+			m.mode = ModeNormal
+			m.snippetList = nil
+			return m, nil
+		case "k", "up":
+			if m.snippetSelected > 0 {
+				m.snippetSelected--
+				return m.showSnippet(), nil
+			}
+			return m, nil
+		case "j", "down":
+			if m.snippetSelected < len(m.snippetList)-1 {
+				m.snippetSelected++
+				return m.showSnippet(), nil
+			}
+			return m, nil
+		case "x":
+			if m.snippetSelected < len(m.snippetList) {
+				if err := m.snippets.Delete(m.snippetKey, m.snippetSelected); err != nil {
+					m.err = err
+					return m, nil
+				}
+				m.snippetList = m.snippets.Get(m.snippetKey)
+				m.snippetSelected = minInt(m.snippetSelected, maxInt(0, len(m.snippetList)-1))
+			}
+			return m.showSnippet(), nil
+		}
+	}
+	// Anything else (pgup/pgdn, mouse wheel) scrolls the preview.
+	var cmd tea.Cmd
+	m.snippetView, cmd = m.snippetView.Update(msg)
+	return m, cmd
+}
+
+func (m Model) renderSnippetsOverlay() string {
+	var sb strings.Builder
+	sb.WriteString(styleOverlayTitle.Width(m.width).Render(i18n.T("Snippets")) + "\n\n")
+	if len(m.snippetList) == 0 {
+		sb.WriteString(pickerItemStyle.Render("No snippets for this session — press c in the session list to save what's on screen.") + "\n")
+		sb.WriteString("\n" + styleOverlayHelp.Render(i18n.T("[esc] close")))
+		return sb.String()
+	}
+
+	// Keep the selection inside an eight-row window of the list.
+	const rows = 8
+	first := minInt(maxInt(0, m.snippetSelected-rows+1), maxInt(0, len(m.snippetList)-rows))
+	subtle := lipgloss.NewStyle().Foreground(colSubtle)
+	for i := first; i < minInt(first+rows, len(m.snippetList)); i++ {
+		sn := m.snippetList[i]
+		line := sn.Name + "  " + subtle.Render(sn.CreatedAt.Format("2 Jan 15:04"))
+		if i == m.snippetSelected {
+			sb.WriteString(pickerSelectedStyle.Width(m.width-4).Render("▸ "+line) + "\n")
+		} else {
+			sb.WriteString(pickerItemStyle.Render("  "+line) + "\n")
+		}
+	}
+	sb.WriteString(lipgloss.NewStyle().Foreground(colBorder).Render(strings.Repeat("─", m.width)) + "\n")
+	sb.WriteString(m.snippetView.View() + "\n")
+	sb.WriteString(styleOverlayHelp.Render(i18n.T("[j/k] select  [pgup/pgdn] scroll  [x] delete  [esc] close")))
+	return sb.String()
+}
@@ -0,0 +1,71 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSaveSnippetFromVisibleOutput(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+
+	var lines []string
+	for i := 1; i <= 100; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	m.lastCapture = strings.Join(lines, "\n") + "\n\n"
+	m.viewport.SetContent(cleanCapture(m.lastCapture))
+	m.viewport.GotoBottom()
+
+	m = pressKey(t, m, "c")
+	if m.mode != ModeSnippetName {
+		t.Fatalf("mode = %d, want snippet name prompt", m.mode)
+	}
+	captured := strings.Split(promptOf(t, m).preview, "\n")
+	if len(captured) != m.viewport.Height || captured[len(captured)-1] != lines[99] {
+		t.Fatalf("captured %d lines ending %q, want the %d visible ending %q",
+			len(captured), captured[len(captured)-1], m.viewport.Height, lines[99])
+	}
+
+	// Replace the suggested name.
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlU})
+	m = updated.(Model)
+	m = pressKey(t, m, "stack trace")
+	m = pressKey(t, m, "enter")
+	saved := m.snippets.Get("session:sess-aaa")
+	if len(saved) != 1 || saved[0].Name != "stack trace" || !strings.HasSuffix(saved[0].Text, lines[99]) {
+		t.Fatalf("saved = %+v", saved)
+	}
+
+	m = pressKey(t, m, "P")
+	if m.mode != ModeSnippets || len(m.snippetList) != 1 {
+		t.Fatalf("snippets panel: mode %d, %d snippets", m.mode, len(m.snippetList))
+	}
+	if view := m.View(); !strings.Contains(view, "stack trace") || !strings.Contains(view, captured[0]) {
+		t.Errorf("panel view missing the snippet:\n%s", view)
+	}
+
+	m = pressKey(t, m, "x")
+	if got := m.snippets.Get("session:sess-aaa"); len(got) != 0 {
+		t.Errorf("snippet kept after delete: %+v", got)
+	}
+}
+
+func TestSaveSnippetCancel(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	m.lastCapture = "some output"
+	m.viewport.SetContent(m.lastCapture)
+
+	m = pressKey(t, m, "c")
+	m = pressKey(t, m, "esc")
+	if m.mode != ModeNormal {
+		t.Fatalf("mode = %d after esc", m.mode)
+	}
+	if got := m.snippets.Get("session:sess-aaa"); len(got) != 0 {
+		t.Errorf("cancelled snippet saved: %+v", got)
+	}
+}
@@ -0,0 +1,148 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/shnupta/herd/internal/config"
+	"github.com/shnupta/herd/internal/groups"
+	"github.com/shnupta/herd/internal/i18n"
+	"github.com/shnupta/herd/internal/names"
+)
+
+// openSquads switches to the squad picker.
+func (m Model) openSquads() Model {
+	m.mode = ModeSquads
+	m.squads = config.Load().Squads
+	m.squadsSelected = 0
+	m.squadsErr = ""
+	return m
+}
+
+func (m Model) updateSquadsMode(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m = m.recalcLayout()
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "q":
+			m.mode = ModeNormal
+			m.squads = nil
+			return m, nil
+		case "k", "up":
+			if m.squadsSelected > 0 {
+				m.squadsSelected--
+			}
+		case "j", "down":
+			if m.squadsSelected < len(m.squads)-1 {
+				m.squadsSelected++
+			}
+		case "enter":
+			return m.launchSquad()
+		}
+	}
+	return m, nil
+}
+
+// launchSquad starts a session for every member of the chosen squad, puts
+// them in a group named after it and pins the group if the squad asks. A
+// member that fails to launch stops the rest; those already up are kept.
+func (m Model) launchSquad() (tea.Model, tea.Cmd) {
+	if m.squadsSelected >= len(m.squads) {
+		return m, nil
+	}
+	sq := m.squads[m.squadsSelected]
+	if len(sq.Members) == 0 {
+		m.squadsErr = fmt.Sprintf("squad %q has no members", sq.Name)
+		return m, nil
+	}
+
+	var first string
+	for _, mem := range sq.Members {
+		project := expandPath(mem.Project)
+		if project == "" {
+			m.squadsErr = "a member of " + sq.Name + " has no project"
+			break
+		}
+		paneID, err := LaunchSessionWithPrompt(project, mem.Prompt, m.tmuxClient)
+		if err != nil {
+			m.squadsErr = err.Error()
+			break
+		}
+		if first == "" {
+			first = paneID
+		}
+		// As for tickets, the pane key is carried over to the session key
+		// once hooks report an ID.
+		key := "pane:" + paneID
+		if sq.Name != "" {
+			_ = groups.Set(key, sq.Name)
+		}
+		if mem.Name != "" {
+			_ = names.Set(key, mem.Name)
+		}
+		if sq.Pin {
+			m.pinCounter++
+			m.pinned[key] = m.pinCounter
+		}
+	}
+	if first == "" {
+		return m, nil
+	}
+	if sq.Pin {
+		m.saveSidebarState()
+	}
+
+	if m.squadsErr == "" {
+		m.mode = ModeNormal
+		m.squads = nil
+	}
+	m.pendingSelectPane = first
+	m.pendingQuickRetried = false
+	m.lastCapture = ""
+	m.forceViewportRefresh = true
+	return m, tea.Batch(m.discoverSessions(), tickCapture(), tickSessionRefresh())
+}
+
+func (m Model) renderSquadsOverlay() string {
+	var sb strings.Builder
+	sb.WriteString(styleOverlayTitle.Width(m.width).Render(i18n.T("Launch Squad")) + "\n\n")
+
+	subtle := lipgloss.NewStyle().Foreground(colSubtle)
+	if len(m.squads) == 0 {
+		sb.WriteString(pickerItemStyle.Render("No squads configured") + "\n")
+		sb.WriteString(pickerItemStyle.Render(subtle.Render(`Add "squads" to ~/.herd/config.json — see the README.`)) + "\n")
+	}
+	for i, sq := range m.squads {
+		var members []string
+		for _, mem := range sq.Members {
+			label := mem.Name
+			if label == "" {
+				label = shortenPath(expandPath(mem.Project))
+			}
+			members = append(members, label)
+		}
+		line := sq.Name + "  " + subtle.Render(strings.Join(members, ", "))
+		if sq.Pin {
+			line += subtle.Render("  (pinned)")
+		}
+		if i == m.squadsSelected {
+			sb.WriteString(pickerSelectedStyle.Width(m.width-4).Render("▸ "+line) + "\n")
+		} else {
+			sb.WriteString(pickerItemStyle.Render("  "+line) + "\n")
+		}
+	}
+	if m.squadsErr != "" {
+		sb.WriteString("\n" + pickerItemStyle.Render(lipgloss.NewStyle().Foreground(colRed).Render(m.squadsErr)) + "\n")
+	}
+
+	sb.WriteString("\n" + styleOverlayHelp.Render(i18n.T("[j/k] navigate  [enter] launch all  [esc] cancel")))
+	return sb.String()
+}
@@ -0,0 +1,60 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/shnupta/herd/internal/config"
+	"github.com/shnupta/herd/internal/tmux/tmuxtest"
+)
+
+func TestSquadsOverlayListsMembers(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	m.mode = ModeSquads
+	m.squads = []config.Squad{
+		{Name: "usual", Pin: true, Members: []config.SquadMember{{Project: "/code/api", Name: "api"}, {Project: "/code/web"}}},
+		{Name: "empty"},
+	}
+	view := m.View()
+	for _, want := range []string{"usual", "api, /code/web", "(pinned)"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+
+	m = pressKey(t, m, "j")
+	m = pressKey(t, m, "enter")
+	if m.mode != ModeSquads || !strings.Contains(m.squadsErr, "no members") {
+		t.Errorf("mode = %v, err %q; want to stay open with an error", m.mode, m.squadsErr)
+	}
+}
+
+func TestLaunchSquadStartsEveryMember(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	m, fw := newTestModel(t, nil)
+	defer fw.Close()
+	mock := m.tmuxClient.(*tmuxtest.MockClient)
+	mock.CurrentSessionVal = "0"
+	mock.NewWindowPane = "%9"
+	m.mode = ModeSquads
+	m.squads = []config.Squad{{Members: []config.SquadMember{
+		{Project: "/code/api", Prompt: "Fix the build"},
+		{Project: "/code/web"},
+	}}}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.mode != ModeNormal || cmd == nil {
+		t.Fatalf("mode = %v, err %q; want back to normal with discovery scheduled", m.mode, m.squadsErr)
+	}
+	want := []string{"0:/code/api:claude 'Fix the build'", "0:/code/web:claude"}
+	if len(mock.NewWindowCalls) != 2 || mock.NewWindowCalls[0] != want[0] || mock.NewWindowCalls[1] != want[1] {
+		t.Errorf("NewWindowCalls = %q, want %q", mock.NewWindowCalls, want)
+	}
+	if m.pendingSelectPane != "%9" {
+		t.Errorf("pendingSelectPane = %q, want the first member", m.pendingSelectPane)
+	}
+}
@@ -0,0 +1,155 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/shnupta/herd/internal/history"
+	"github.com/shnupta/herd/internal/i18n"
+	"github.com/shnupta/herd/internal/report"
+)
+
+// statsRow is one project and branch's working time over each window the
+// stats overlay shows.
+type statsRow struct {
+	project, branch  string
+	today, week, all time.Duration
+}
+
+// statsLoadedMsg carries the time table read from the history log.
+type statsLoadedMsg struct {
+	rows []statsRow
+	err  error
+}
+
+func isStatsLoadedMsg(msg tea.Msg) bool { _, ok := msg.(statsLoadedMsg); return ok }
+
+// statsMonth is the longest window shown.
+const statsMonth = 30 * 24 * time.Hour
+
+// openStats switches to the stats overlay and loads the history log.
+func (m Model) openStats() (Model, tea.Cmd) {
+	m.mode = ModeStats
+	m.statsRows = nil
+	m.statsErr = ""
+	m.statsLoading = true
+	return m, func() tea.Msg {
+		now := time.Now()
+		events, err := history.ReadSince(history.Path(), time.Time{})
+		if err != nil {
+			return statsLoadedMsg{err: err}
+		}
+		y, mo, d := now.Date()
+		return statsLoadedMsg{rows: buildStatsRows(events, now, time.Date(y, mo, d, 0, 0, 0, 0, now.Location()))}
+	}
+}
+
+// buildStatsRows totals working time per project and branch for today (from
+// midnight), the last 7 days and the last 30 days, busiest first.
+func buildStatsRows(events []history.Event, now, midnight time.Time) []statsRow {
+	type key struct{ project, branch string }
+	rows := make(map[key]*statsRow)
+	var order []key
+	add := func(since time.Time, set func(*statsRow, time.Duration)) {
+		for _, bt := range report.TimeByBranch(events, since, now) {
+			k := key{bt.Project, bt.Branch}
+			r, ok := rows[k]
+			if !ok {
+				r = &statsRow{project: bt.Project, branch: bt.Branch}
+				rows[k] = r
+				order = append(order, k)
+			}
+			set(r, bt.Working)
+		}
+	}
+	add(now.Add(-statsMonth), func(r *statsRow, d time.Duration) { r.all = d })
+	add(now.Add(-7*24*time.Hour), func(r *statsRow, d time.Duration) { r.week = d })
+	add(midnight, func(r *statsRow, d time.Duration) { r.today = d })
+
+	out := make([]statsRow, 0, len(order))
+	for _, k := range order {
+		out = append(out, *rows[k])
+	}
+	// Busiest first over the longest window; ties keep project order.
+	sort.SliceStable(out, func(i, j int) bool { return out[i].all > out[j].all })
+	return out
+}
+
+func (m Model) updateStatsMode(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m = m.recalcLayout()
+		return m, nil
+
+	case statsLoadedMsg:
+		m.statsLoading = false
+		m.statsRows = msg.rows
+		if msg.err != nil {
+			m.statsErr = msg.err.Error()
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "q", "S":
+			m.mode = ModeNormal
+			m.statsRows = nil
+			return m, nil
+		}
+	}
+	return m, nil
+}
+
+func (m Model) renderStatsOverlay() string {
+	var sb strings.Builder
+	sb.WriteString(styleOverlayTitle.Width(m.width).Render(i18n.T("Working Time")) + "\n\n")
+
+	subtle := lipgloss.NewStyle().Foreground(colSubtle)
+	switch {
+	case m.statsLoading:
+		sb.WriteString(pickerItemStyle.Render("Reading history…") + "\n")
+	case m.statsErr != "":
+		sb.WriteString(pickerItemStyle.Render(lipgloss.NewStyle().Foreground(colRed).Render(m.statsErr)) + "\n")
+	case len(m.statsRows) == 0:
+		sb.WriteString(pickerItemStyle.Render("No working time recorded in the last 30 days.") + "\n")
+		sb.WriteString(pickerItemStyle.Render(subtle.Render("Time is taken from the history the hooks write ('herd install').")) + "\n")
+	default:
+		projW, branchW := len("PROJECT"), len("BRANCH")
+		for _, r := range m.statsRows {
+			projW = maxInt(projW, lipgloss.Width(shortenPath(r.project)))
+			branchW = maxInt(branchW, lipgloss.Width(r.branch))
+		}
+		row := func(project, branch, today, week, all string) string {
+			return fmt.Sprintf("%-*s  %-*s  %8s  %8s  %8s", projW, project, branchW, branch, today, week, all)
+		}
+		sb.WriteString(pickerItemStyle.Render(subtle.Render(row("PROJECT", "BRANCH", "TODAY", "7 DAYS", "30 DAYS"))) + "\n")
+		var today, week, all time.Duration
+		for _, r := range m.statsRows {
+			sb.WriteString(pickerItemStyle.Render(row(shortenPath(r.project), r.branch,
+				statsDuration(r.today), statsDuration(r.week), statsDuration(r.all))) + "\n")
+			today += r.today
+			week += r.week
+			all += r.all
+		}
+		total := row("total", "", statsDuration(today), statsDuration(week), statsDuration(all))
+		sb.WriteString(pickerItemStyle.Render(lipgloss.NewStyle().Bold(true).Render(total)) + "\n")
+	}
+
+	sb.WriteString("\n" + styleOverlayHelp.Render(i18n.T("[esc] close  ·  herd report --csv --since 30d exports these totals")))
+	return sb.String()
+}
+
+// statsDuration formats d for the stats table, with "–" for none.
+func statsDuration(d time.Duration) string {
+	if d <= 0 {
+		return "–"
+	}
+	return report.FormatDuration(d)
+}
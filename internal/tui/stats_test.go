@@ -0,0 +1,66 @@
+package tui
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shnupta/herd/internal/history"
+)
+
+func TestBuildStatsRows(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	midnight := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	run := func(start time.Time, d time.Duration, project, branch string) []history.Event {
+		return []history.Event{
+			{Time: start, Kind: history.KindState, SessionID: project + branch, Project: project, State: "working", Branch: branch},
+			{Time: start.Add(d), Kind: history.KindState, SessionID: project + branch, Project: project, State: "waiting"},
+		}
+	}
+	var events []history.Event
+	events = append(events, run(now.Add(-2*time.Hour), time.Hour, "/api", "main")...)         // today
+	events = append(events, run(now.Add(-3*24*time.Hour), 2*time.Hour, "/api", "main")...)    // this week
+	events = append(events, run(now.Add(-20*24*time.Hour), 5*time.Hour, "/web", "feat/x")...) // this month
+
+	rows := buildStatsRows(events, now, midnight)
+	if len(rows) != 2 {
+		t.Fatalf("rows = %+v", rows)
+	}
+	web, api := rows[0], rows[1] // busiest over 30 days first
+	if web.project != "/web" || web.all != 5*time.Hour || web.week != 0 || web.today != 0 {
+		t.Errorf("web row = %+v", web)
+	}
+	if api.branch != "main" || api.today != time.Hour || api.week != 3*time.Hour || api.all != 3*time.Hour {
+		t.Errorf("api row = %+v", api)
+	}
+}
+
+func TestStatsOverlay(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	now := time.Now()
+	path := filepath.Join(home, ".herd", "history.jsonl")
+	for _, ev := range []history.Event{
+		{Time: now.Add(-time.Hour), Kind: history.KindState, SessionID: "a", Project: "/code/api", State: "working", Branch: "main"},
+		{Time: now.Add(-30 * time.Minute), Kind: history.KindState, SessionID: "a", Project: "/code/api", State: "waiting"},
+	} {
+		if err := history.AppendTo(path, ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	m, cmd := m.openStats()
+	if m.mode != ModeStats {
+		t.Fatalf("mode = %d, want stats", m.mode)
+	}
+	updated, _ := m.Update(cmd())
+	view := updated.(Model).View()
+	for _, want := range []string{"/code/api", "main", "30m", "total"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("stats view missing %q:\n%s", want, view)
+		}
+	}
+}
@@ -1,6 +1,10 @@
 package tui
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
 
 const sessionPaneWidth = 28
 
@@ -85,6 +89,13 @@ var (
 			Bold(true).
 			PaddingLeft(1)
 
+	// Red, as keys typed in group insert mode reach every session at once
+	styleHelpGroupInsert = lipgloss.NewStyle().
+				Background(colRedDim).
+				Foreground(colRed).
+				Bold(true).
+				PaddingLeft(1)
+
 	styleHelpFilter = lipgloss.NewStyle().
 			Background(colAmberDim).
 			Foreground(colAmber).
@@ -118,6 +129,10 @@ var (
 				Foreground(colSubtext).
 				PaddingLeft(1)
 
+	styleOverlayError = lipgloss.NewStyle().
+				Foreground(colRed).
+				PaddingLeft(1)
+
 	// ── Filter input ─────────────────────────────────────────────────────────
 	styleFilter = lipgloss.NewStyle().
 			Foreground(colAmber).
@@ -164,6 +179,50 @@ func stateLabel(stateStr, tool string) string {
 	}
 }
 
+// permissionBadge returns a short coloured tag for permission modes that
+// change how closely a session needs watching, or "" for the default mode.
+func permissionBadge(mode string) string {
+	switch mode {
+	case "plan":
+		return lipgloss.NewStyle().Foreground(colBlue).Render("plan")
+	case "acceptEdits":
+		return lipgloss.NewStyle().Foreground(colAmber).Render("edits")
+	case "bypassPermissions":
+		return lipgloss.NewStyle().Foreground(colRed).Bold(true).Render("bypass")
+	default:
+		return ""
+	}
+}
+
+// contextWarnPercent is the utilisation at which the context gauge turns red:
+// close enough to auto-compaction that the user may want to step in.
+const contextWarnPercent = 80
+
+// contextGauge renders a five-cell bar for a context utilisation percentage,
+// or "" when no usage is known.
+func contextGauge(pct int) string {
+	if pct <= 0 {
+		return ""
+	}
+	const cells = 5
+	filled := (pct*cells + 50) / 100
+	if filled < 1 {
+		filled = 1
+	}
+	if filled > cells {
+		filled = cells
+	}
+	col := colGreen
+	switch {
+	case pct >= contextWarnPercent:
+		col = colRed
+	case pct >= 50:
+		col = colAmber
+	}
+	bar := strings.Repeat("▰", filled) + strings.Repeat("▱", cells-filled)
+	return lipgloss.NewStyle().Foreground(col).Render(bar)
+}
+
 // stateBg returns a subtle background tint for a session row based on state.
 func stateBg(stateStr string) lipgloss.Color {
 	switch stateStr {
@@ -0,0 +1,317 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/shnupta/herd/internal/git"
+	"github.com/shnupta/herd/internal/history"
+	"github.com/shnupta/herd/internal/i18n"
+	"github.com/shnupta/herd/internal/names"
+	"github.com/shnupta/herd/internal/session"
+)
+
+// teardownPlan is the teardown of a session in a linked worktree: kill its
+// pane, remove the worktree and, if asked and merged, delete its branch.
+type teardownPlan struct {
+	pane     string
+	label    string // session name for the overlay
+	path     string // the linked worktree
+	mainPath string // the main worktree, where git runs
+	branch   string // "" if detached
+	into     string // the main worktree's branch
+	merged   bool   // branch is contained in into
+
+	// dirty lists the worktree's uncommitted changes, in git status
+	// --porcelain form; nothing runs while there are any unless force is
+	// set, which removes the worktree with --force and loses them.
+	dirty []string
+	force bool
+
+	deleteBranch bool
+	steps        []teardownStep
+	finished     bool
+}
+
+// teardownStep is one stage of a teardown; done and err are set as it
+// finishes.
+type teardownStep struct {
+	label string
+	run   func() error
+	done  bool
+	err   error
+}
+
+// teardownStepMsg reports that step i of the teardown finished.
+type teardownStepMsg struct {
+	i   int
+	err error
+}
+
+func isTeardownStepMsg(msg tea.Msg) bool { _, ok := msg.(teardownStepMsg); return ok }
+
+// inLinkedWorktree reports whether s runs in a linked worktree, whose .git
+// is a file pointing at the main repository rather than a directory.
+func inLinkedWorktree(s session.Session) bool {
+	if s.GitRoot == "" {
+		return false
+	}
+	info, err := os.Stat(filepath.Join(s.GitRoot, ".git"))
+	return err == nil && info.Mode().IsRegular()
+}
+
+// openTeardown plans the teardown of the selected session's worktree and
+// asks for confirmation.
+func (m Model) openTeardown() Model {
+	sel := m.selectedSession()
+	if sel == nil {
+		return m
+	}
+	if !inLinkedWorktree(*sel) {
+		m.err = fmt.Errorf("%s is not in a linked worktree", sel.DisplayName())
+		return m
+	}
+	worktrees, err := git.ListWorktrees(sel.GitRoot)
+	if err != nil {
+		m.err = err
+		return m
+	}
+	plan := &teardownPlan{pane: sel.TmuxPane, label: sel.DisplayName(), path: sel.GitRoot}
+	if name := names.Get(sel.Key()); name != "" {
+		plan.label = name
+	}
+	for _, wt := range worktrees {
+		switch {
+		case wt.IsMain:
+			plan.mainPath, plan.into = wt.Path, wt.Branch
+		case wt.Path == sel.GitRoot:
+			plan.branch = wt.Branch
+		}
+	}
+	if plan.mainPath == "" {
+		m.err = fmt.Errorf("no main worktree found for %s", sel.GitRoot)
+		return m
+	}
+	plan.merged = plan.branch != "" && plan.into != "" && git.IsMerged(plan.mainPath, plan.branch, plan.into)
+	plan.deleteBranch = plan.merged
+	if plan.dirty, err = git.Uncommitted(plan.path); err != nil {
+		m.err = err
+		return m
+	}
+	m.teardown = plan
+	m.mode = ModeTeardown
+	return m
+}
+
+func (m Model) updateTeardownMode(msg tea.Msg) (tea.Model, tea.Cmd) {
+	plan := m.teardown
+	if plan == nil {
+		m.mode = ModeNormal
+		return m, nil
+	}
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m = m.recalcLayout()
+		return m, nil
+
+	case teardownStepMsg:
+		step := &plan.steps[msg.i]
+		step.done, step.err = true, msg.err
+		if msg.i == 0 && msg.err == nil {
+			m = m.dropSession(plan.pane)
+		}
+		if msg.err != nil || msg.i == len(plan.steps)-1 {
+			plan.finished = true
+			return m, m.discoverSessions()
+		}
+		return m, plan.runStep(msg.i + 1)
+
+	case tea.KeyMsg:
+		if plan.finished {
+			m.mode = ModeNormal
+			m.teardown = nil
+			var cmd tea.Cmd
+			m, cmd = m.selectSession()
+			return m, cmd
+		}
+		if plan.steps != nil {
+			return m, nil // running
+		}
+		switch msg.String() {
+		case "esc", "q":
+			m.mode = ModeNormal
+			m.teardown = nil
+		case "d":
+			if plan.merged {
+				plan.deleteBranch = !plan.deleteBranch
+			}
+		case "f":
+			if len(plan.dirty) > 0 {
+				plan.force = !plan.force
+			}
+		case "enter":
+			// Check again: changes made since the overlay opened would
+			// otherwise stop the removal after the pane is gone.
+			dirty, err := git.Uncommitted(plan.path)
+			if err != nil {
+				m.err = err
+				return m, nil
+			}
+			plan.dirty = dirty
+			if len(dirty) > 0 && !plan.force {
+				return m, nil
+			}
+			plan.steps = m.teardownSteps(plan)
+			return m, plan.runStep(0)
+		}
+	}
+	return m, nil
+}
+
+// teardownSteps lists the stages of plan, in order.
+func (m Model) teardownSteps(plan *teardownPlan) []teardownStep {
+	client := m.tmuxClient
+	logKill := m.auditCmd(plan.pane, history.ActionKill, "teardown", plan.path)
+	kill := func() error {
+		if err := client.KillPane(plan.pane); err != nil {
+			return err
+		}
+		logKill()
+		return nil
+	}
+	remove := teardownStep{label: i18n.Tf("Remove worktree %s", shortenPath(plan.path)), run: func() error { return git.RemoveWorktree(plan.mainPath, plan.path) }}
+	if plan.force && len(plan.dirty) > 0 {
+		remove = teardownStep{
+			label: i18n.Tf("Remove worktree %s, discarding %d uncommitted changes", shortenPath(plan.path), len(plan.dirty)),
+			run:   func() error { return git.ForceRemoveWorktree(plan.mainPath, plan.path) },
+		}
+	}
+	steps := []teardownStep{
+		{label: i18n.Tf("Kill pane %s", plan.pane), run: kill},
+		remove,
+	}
+	if plan.deleteBranch {
+		steps = append(steps, teardownStep{
+			label: i18n.Tf("Delete branch %s", plan.branch),
+			run:   func() error { return git.DeleteBranch(plan.mainPath, plan.branch) },
+		})
+	}
+	return steps
+}
+
+// runStep runs step i off the UI goroutine.
+func (p *teardownPlan) runStep(i int) tea.Cmd {
+	run := p.steps[i].run
+	return func() tea.Msg {
+		return teardownStepMsg{i: i, err: run()}
+	}
+}
+
+// dropSession removes the session in pane from the list after its pane was
+// killed.
+func (m Model) dropSession(pane string) Model {
+	for i, s := range m.sessions {
+		if s.TmuxPane == pane {
+			delete(m.pinned, s.Key())
+			m.sessions = append(m.sessions[:i], m.sessions[i+1:]...)
+			if m.selected >= len(m.sessions) {
+				m.selected = maxInt(0, len(m.sessions)-1)
+			}
+			m.lastCapture = ""
+			m.itemsDirty = true
+			m.cleanupSidebarState()
+			m.saveSidebarState()
+			break
+		}
+	}
+	return m
+}
+
+func (m Model) renderTeardownOverlay() string {
+	plan := m.teardown
+	var sb strings.Builder
+	sb.WriteString(styleOverlayTitle.Width(m.width).Render(i18n.T("Tear Down Worktree Session")) + "\n\n")
+
+	subtle := lipgloss.NewStyle().Foreground(colSubtle)
+	row := func(label, value string) {
+		sb.WriteString(pickerItemStyle.Render(fmt.Sprintf("%-11s", i18n.T(label))+value) + "\n")
+	}
+	row("Session", plan.label+"  "+subtle.Render(plan.pane))
+	row("Worktree", shortenPath(plan.path))
+	switch {
+	case plan.branch == "":
+		row("Branch", subtle.Render(i18n.T("detached HEAD")))
+	case plan.merged:
+		row("Branch", plan.branch+"  "+subtle.Render(i18n.Tf("merged into %s", plan.into)))
+	default:
+		row("Branch", plan.branch+"  "+subtle.Render(i18n.Tf("not merged into %s — kept", plan.into)))
+	}
+	if len(plan.dirty) > 0 {
+		red := lipgloss.NewStyle().Foreground(colRed)
+		row("Changes", red.Render(i18n.Tf("%d uncommitted — lost if removed", len(plan.dirty))))
+		for i, f := range plan.dirty {
+			if i == 5 {
+				row("", subtle.Render(i18n.Tf("… and %d more", len(plan.dirty)-i)))
+				break
+			}
+			row("", subtle.Render(f))
+		}
+	}
+	sb.WriteString("\n")
+
+	if plan.steps == nil {
+		for i, s := range m.teardownSteps(plan) {
+			sb.WriteString(pickerItemStyle.Render(fmt.Sprintf("%d. %s", i+1, s.label)) + "\n")
+		}
+		help := i18n.T("[enter] tear down  [esc] cancel")
+		onOff := i18n.T("off")
+		if plan.deleteBranch {
+			onOff = i18n.T("on")
+		}
+		if plan.merged {
+			help = i18n.Tf("[enter] tear down  [d] delete branch: %s  [esc] cancel", onOff)
+		}
+		if len(plan.dirty) > 0 {
+			help = i18n.T("[f] discard the changes")
+			if plan.force {
+				help = i18n.T("[enter] tear down, discarding the changes  [f] keep them")
+			}
+			if plan.merged {
+				help += "  " + i18n.Tf("[d] delete branch: %s", onOff)
+			}
+			help += "  " + i18n.T("[esc] cancel")
+		}
+		sb.WriteString("\n" + styleOverlayHelp.Render(help))
+		return sb.String()
+	}
+
+	running := true
+	for _, s := range plan.steps {
+		var icon string
+		switch {
+		case s.err != nil:
+			icon = lipgloss.NewStyle().Foreground(colRed).Render("✗")
+		case s.done:
+			icon = lipgloss.NewStyle().Foreground(colGreen).Render("✓")
+		case running && !plan.finished:
+			icon, running = lipgloss.NewStyle().Foreground(colAmber).Render("●"), false
+		default:
+			icon = subtle.Render("○")
+		}
+		sb.WriteString(pickerItemStyle.Render(icon+" "+s.label) + "\n")
+		if s.err != nil {
+			sb.WriteString(pickerItemStyle.Render("  "+lipgloss.NewStyle().Foreground(colRed).Render(s.err.Error())) + "\n")
+		}
+	}
+	if plan.finished {
+		sb.WriteString("\n" + styleOverlayHelp.Render(i18n.T("[any key] close")))
+	}
+	return sb.String()
+}
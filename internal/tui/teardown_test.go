@@ -0,0 +1,162 @@
+package tui
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/shnupta/herd/internal/tmux/tmuxtest"
+)
+
+func TestTeardownKillsRemovesAndDeletesMergedBranch(t *testing.T) {
+	repo := filepath.Join(t.TempDir(), "repo")
+	wt := filepath.Join(t.TempDir(), "repo-done")
+	run := func(args ...string) {
+		t.Helper()
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	run("init", "-b", "main", repo)
+	run("-C", repo, "config", "user.email", "test@test.com")
+	run("-C", repo, "config", "user.name", "Test")
+	run("-C", repo, "commit", "--allow-empty", "-m", "first")
+	run("-C", repo, "worktree", "add", "-b", "done", wt)
+
+	sessions := testSessions()[:1]
+	sessions[0].ProjectPath, sessions[0].GitRoot = wt, wt
+	m, _ := newTestModel(t, sessions)
+	mock := m.tmuxClient.(*tmuxtest.MockClient)
+
+	m = pressKey(t, m, "X")
+	if m.mode != ModeTeardown || m.teardown == nil {
+		t.Fatalf("mode = %v, want the teardown overlay", m.mode)
+	}
+	if !m.teardown.merged || !m.teardown.deleteBranch {
+		t.Fatalf("plan = %+v, want a merged branch marked for deletion", *m.teardown)
+	}
+	if view := m.View(); !strings.Contains(view, "Delete branch done") {
+		t.Errorf("overlay should list the branch deletion:\n%s", view)
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	for cmd != nil {
+		msg := cmd()
+		if _, ok := msg.(teardownStepMsg); !ok {
+			break
+		}
+		updated, cmd = m.Update(msg)
+		m = updated.(Model)
+	}
+
+	if !m.teardown.finished {
+		t.Fatalf("teardown did not finish: %+v", m.teardown.steps)
+	}
+	for _, s := range m.teardown.steps {
+		if s.err != nil {
+			t.Errorf("step %q failed: %v", s.label, s.err)
+		}
+	}
+	if len(mock.KilledPanes) != 1 || mock.KilledPanes[0] != "%1" {
+		t.Errorf("KilledPanes = %v, want [%%1]", mock.KilledPanes)
+	}
+	if len(m.sessions) != 0 {
+		t.Errorf("sessions = %d, want the killed session dropped", len(m.sessions))
+	}
+	if _, err := os.Stat(wt); !os.IsNotExist(err) {
+		t.Errorf("worktree still exists: %v", err)
+	}
+	if out, _ := exec.Command("git", "-C", repo, "branch", "--list", "done").Output(); len(out) != 0 {
+		t.Errorf("branch done should be deleted, got %q", out)
+	}
+
+	m = pressKey(t, m, "q")
+	if m.mode != ModeNormal || m.teardown != nil {
+		t.Errorf("any key should close the finished teardown, mode = %v", m.mode)
+	}
+}
+
+func TestTeardownRefusesMainWorktree(t *testing.T) {
+	repo := t.TempDir()
+	if out, err := exec.Command("git", "init", repo).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+	sessions := testSessions()[:1]
+	sessions[0].GitRoot = repo
+	m, _ := newTestModel(t, sessions)
+
+	m = pressKey(t, m, "X")
+	if m.mode != ModeNormal || m.err == nil {
+		t.Errorf("mode = %v, err = %v; want an error outside a linked worktree", m.mode, m.err)
+	}
+}
+
+func TestTeardownHoldsForUncommittedChanges(t *testing.T) {
+	repo := filepath.Join(t.TempDir(), "repo")
+	wt := filepath.Join(t.TempDir(), "repo-wip")
+	run := func(args ...string) {
+		t.Helper()
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	run("init", "-b", "main", repo)
+	run("-C", repo, "config", "user.email", "test@test.com")
+	run("-C", repo, "config", "user.name", "Test")
+	run("-C", repo, "commit", "--allow-empty", "-m", "first")
+	run("-C", repo, "worktree", "add", "-b", "wip", wt)
+	if err := os.WriteFile(filepath.Join(wt, "notes.txt"), []byte("draft"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	sessions := testSessions()[:1]
+	sessions[0].ProjectPath, sessions[0].GitRoot = wt, wt
+	m, _ := newTestModel(t, sessions)
+	mock := m.tmuxClient.(*tmuxtest.MockClient)
+
+	m = pressKey(t, m, "X")
+	if m.teardown == nil || len(m.teardown.dirty) != 1 {
+		t.Fatalf("plan = %+v, want the untracked file listed", m.teardown)
+	}
+	if view := m.View(); !strings.Contains(view, "1 uncommitted") || !strings.Contains(view, "notes.txt") {
+		t.Errorf("overlay should warn about the changes:\n%s", view)
+	}
+
+	// Enter does nothing until the changes are explicitly discarded.
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if cmd != nil || m.teardown.steps != nil || len(mock.KilledPanes) != 0 {
+		t.Fatalf("teardown started with uncommitted changes, killed %v", mock.KilledPanes)
+	}
+
+	m = pressKey(t, m, "f")
+	if view := m.View(); !strings.Contains(view, "discarding 1 uncommitted changes") {
+		t.Errorf("forced plan should say the changes go:\n%s", view)
+	}
+	updated, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	for cmd != nil {
+		msg := cmd()
+		if _, ok := msg.(teardownStepMsg); !ok {
+			break
+		}
+		updated, cmd = m.Update(msg)
+		m = updated.(Model)
+	}
+	for _, s := range m.teardown.steps {
+		if s.err != nil {
+			t.Errorf("step %q failed: %v", s.label, s.err)
+		}
+	}
+	if len(mock.KilledPanes) != 1 {
+		t.Errorf("KilledPanes = %v, want the pane killed once forced", mock.KilledPanes)
+	}
+	if _, err := os.Stat(wt); !os.IsNotExist(err) {
+		t.Errorf("worktree still exists: %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/shnupta/herd/internal/config"
+	"github.com/shnupta/herd/internal/testrun"
+)
+
+// testPanelHeight is the number of rows the test output panel takes from the
+// viewport when open, including its title row.
+const testPanelHeight = 10
+
+// testPollInterval is how often the test panel and badges refresh while a
+// run is in progress.
+const testPollInterval = 500 * time.Millisecond
+
+// testTickMsg refreshes test output while runs are in progress.
+type testTickMsg time.Time
+
+func tickTests() tea.Cmd {
+	return tea.Tick(testPollInterval, func(t time.Time) tea.Msg {
+		return testTickMsg(t)
+	})
+}
+
+// runTests starts the configured test command for the selected session's
+// repo and opens the test panel. A run already in progress is left alone.
+func (m Model) runTests() (Model, tea.Cmd) {
+	sel := m.selectedSession()
+	if sel == nil {
+		return m, nil
+	}
+	if !m.testPanelOpen {
+		m.testPanelOpen = true
+		m = m.recalcLayout()
+	}
+	if r := m.testRuns[sel.Key()]; r != nil && r.Status() == testrun.Running {
+		return m, nil
+	}
+	command := config.Load().TestCommandFor(sel.ProjectPath)
+	if command == "" {
+		return m, nil
+	}
+	dir := sel.GitRoot
+	if dir == "" {
+		dir = sel.ProjectPath
+	}
+	r, err := testrun.Start(dir, command)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	if m.testRuns == nil {
+		m.testRuns = make(map[string]*testrun.Run)
+	}
+	m.testRuns[sel.Key()] = r
+	m.itemsDirty = true
+	return m, tickTests()
+}
+
+// testsRunning reports whether any session has a test run in progress.
+func (m Model) testsRunning() bool {
+	for _, r := range m.testRuns {
+		if r.Status() == testrun.Running {
+			return true
+		}
+	}
+	return false
+}
+
+// testBadge returns the pass/fail/running marker for a session's latest run.
+func (m Model) testBadge(key string) string {
+	r := m.testRuns[key]
+	if r == nil {
+		return ""
+	}
+	switch r.Status() {
+	case testrun.Running:
+		return lipgloss.NewStyle().Foreground(colBlue).Render("⟳")
+	case testrun.Passed:
+		return lipgloss.NewStyle().Foreground(colGreen).Render("✓")
+	default:
+		return lipgloss.NewStyle().Foreground(colRed).Bold(true).Render("✗")
+	}
+}
+
+func (m Model) renderTestPanel() string {
+	width := m.width - sessionPaneWidth - 1
+	titleStyle := lipgloss.NewStyle().Foreground(colSubtext).Bold(true)
+	dimStyle := lipgloss.NewStyle().Foreground(colSubtle)
+
+	var title string
+	var body []string
+	sel := m.selectedSession()
+	var r *testrun.Run
+	if sel != nil {
+		r = m.testRuns[sel.Key()]
+	}
+	switch {
+	case r != nil:
+		status := r.Status().String()
+		if r.Status() == testrun.Running {
+			status += " " + fmtDuration(r.Elapsed())
+		} else {
+			status += fmt.Sprintf(" in %.1fs", r.Elapsed().Seconds())
+		}
+		title = m.testBadge(sel.Key()) + " " + titleStyle.Render("tests") + dimStyle.Render("  "+r.Command+"  ·  "+status)
+		body = strings.Split(strings.TrimRight(r.Output(), "\n"), "\n")
+	default:
+		title = titleStyle.Render("tests")
+		body = []string{dimStyle.Render(`No test run yet — press T to run the project's "test_commands" entry from ~/.herd/config.json.`)}
+	}
+
+	// One row each for the separator and the title.
+	rows := testPanelHeight - 2
+	if len(body) > rows {
+		body = body[len(body)-rows:]
+	}
+	for i, line := range body {
+		body[i] = ansi.Truncate(line, width, "")
+	}
+	for len(body) < rows {
+		body = append(body, "")
+	}
+
+	top := lipgloss.NewStyle().Foreground(colBorder).Render(strings.Repeat("─", width))
+	return top + "\n" + ansi.Truncate(" "+title, width, "") + "\n" + strings.Join(body, "\n")
+}
@@ -0,0 +1,67 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/shnupta/herd/internal/testrun"
+)
+
+func TestRunTestsBadgeAndPanel(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	cfg := `{"test_commands": [{"command": "echo all good"}]}`
+	if err := os.MkdirAll(filepath.Join(home, ".herd"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, ".herd", "config.json"), []byte(cfg), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	sessions := testSessions()
+	sessions[0].ProjectPath = t.TempDir()
+	m, fw := newTestModel(t, sessions)
+	defer fw.Close()
+	vpHeight := m.viewport.Height
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'T'}})
+	m = updated.(Model)
+	if !m.testPanelOpen || m.viewport.Height != vpHeight-testPanelHeight {
+		t.Fatalf("expected test panel open and viewport shrunk by %d rows", testPanelHeight)
+	}
+	r := m.testRuns["session:sess-aaa"]
+	if r == nil {
+		t.Fatal("expected a test run for the selected session")
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for r.Status() == testrun.Running && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if r.Status() != testrun.Passed {
+		t.Fatalf("run status = %v, want passed", r.Status())
+	}
+
+	panel := ansi.Strip(m.renderTestPanel())
+	if !strings.Contains(panel, "all good") || !strings.Contains(panel, "passed") {
+		t.Errorf("test panel missing output or status:\n%s", panel)
+	}
+	if lines := strings.Count(panel, "\n") + 1; lines != testPanelHeight {
+		t.Errorf("test panel is %d rows, want %d", lines, testPanelHeight)
+	}
+	item := ansi.Strip(m.renderSessionItem(0, m.sessions[0], "", false, false))
+	if !strings.Contains(item, "✓") {
+		t.Errorf("session item missing pass badge:\n%s", item)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'o'}})
+	m = updated.(Model)
+	if m.testPanelOpen || m.viewport.Height != vpHeight {
+		t.Error("expected o to collapse the test panel and restore the viewport")
+	}
+}
@@ -0,0 +1,162 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/shnupta/herd/internal/config"
+	"github.com/shnupta/herd/internal/i18n"
+	"github.com/shnupta/herd/internal/names"
+	"github.com/shnupta/herd/internal/tasks"
+	"github.com/shnupta/herd/internal/tickets"
+)
+
+// ticketsLoadedMsg carries the open tickets from every task source.
+type ticketsLoadedMsg struct {
+	tickets []tasks.Ticket
+	errs    []error
+}
+
+func isTicketsLoadedMsg(msg tea.Msg) bool { _, ok := msg.(ticketsLoadedMsg); return ok }
+
+// openTickets switches to the ticket picker and starts loading tickets.
+func (m Model) openTickets() (Model, tea.Cmd) {
+	m.mode = ModeTickets
+	m.tickets = nil
+	m.ticketsSelected = 0
+	m.ticketsErr = ""
+	m.ticketsDupPane = ""
+	m.ticketsLoading = true
+	sources := config.Load().TaskSources
+	return m, func() tea.Msg {
+		ts, errs := tasks.ListAll(sources)
+		return ticketsLoadedMsg{tickets: ts, errs: errs}
+	}
+}
+
+func (m Model) updateTicketsMode(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m = m.recalcLayout()
+		return m, nil
+
+	case ticketsLoadedMsg:
+		m.ticketsLoading = false
+		m.tickets = msg.tickets
+		if len(msg.errs) > 0 {
+			m.ticketsErr = msg.errs[0].Error()
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "q":
+			m.mode = ModeNormal
+			m.tickets = nil
+			return m, nil
+		case "k", "up":
+			if m.ticketsSelected > 0 {
+				m.ticketsSelected--
+			}
+			m.ticketsDupPane = ""
+		case "j", "down":
+			if m.ticketsSelected < len(m.tickets)-1 {
+				m.ticketsSelected++
+			}
+			m.ticketsDupPane = ""
+		case "s":
+			if pane := m.ticketsDupPane; pane != "" {
+				m.tickets, m.ticketsDupPane = nil, ""
+				return m.switchToSession(pane)
+			}
+		case "enter":
+			return m.startTicket()
+		}
+	}
+	return m, nil
+}
+
+// startTicket launches Claude on the chosen ticket in its source's project
+// (or the selected session's, when the source sets none), names the session
+// after the ticket and records the ticket ID against it.
+func (m Model) startTicket() (tea.Model, tea.Cmd) {
+	if m.ticketsSelected >= len(m.tickets) {
+		return m, nil
+	}
+	t := m.tickets[m.ticketsSelected]
+	project := expandPath(t.Project)
+	if project == "" {
+		if sel := m.selectedSession(); sel != nil {
+			project = sel.ProjectPath
+		}
+	}
+	if project == "" {
+		m.ticketsErr = "no project set for this task source"
+		return m, nil
+	}
+
+	// Ask before putting a second agent on a project; enter again confirms.
+	if dup := m.duplicateSession(project); dup != nil && m.ticketsDupPane != dup.TmuxPane {
+		m.ticketsDupPane = dup.TmuxPane
+		return m, nil
+	}
+	m.ticketsDupPane = ""
+
+	paneID, err := LaunchSessionWithPrompt(project, t.Prompt(), m.tmuxClient)
+	if err != nil {
+		m.ticketsErr = err.Error()
+		return m, nil
+	}
+	// The new session's key is its pane until hooks report an ID; the alias
+	// index carries both records over when that happens.
+	key := "pane:" + paneID
+	_ = tickets.Set(key, t.ID)
+	_ = names.Set(key, t.ID)
+
+	m.mode = ModeNormal
+	m.tickets = nil
+	m.pendingSelectPane = paneID
+	m.pendingQuickRetried = false
+	m.lastCapture = ""
+	m.forceViewportRefresh = true
+	return m, tea.Batch(m.discoverSessions(), tickCapture(), tickSessionRefresh())
+}
+
+func (m Model) renderTicketsOverlay() string {
+	var sb strings.Builder
+	sb.WriteString(styleOverlayTitle.Width(m.width).Render(i18n.T("Start Session from Ticket")) + "\n\n")
+
+	subtle := lipgloss.NewStyle().Foreground(colSubtle)
+	switch {
+	case m.ticketsLoading:
+		sb.WriteString(pickerItemStyle.Render("Loading tickets…") + "\n")
+	case len(m.tickets) == 0 && m.ticketsErr == "":
+		sb.WriteString(pickerItemStyle.Render("No open tickets") + "\n")
+		sb.WriteString(pickerItemStyle.Render(subtle.Render(`Add "task_sources" to ~/.herd/config.json — see the README.`)) + "\n")
+	}
+	for i, t := range m.tickets {
+		line := fmt.Sprintf("%-10s %s", t.ID, t.Title)
+		if i == m.ticketsSelected {
+			sb.WriteString(pickerSelectedStyle.Width(m.width-4).Render("▸ "+line) + "\n")
+		} else {
+			sb.WriteString(pickerItemStyle.Render("  "+line) + "\n")
+		}
+	}
+	if m.ticketsErr != "" {
+		sb.WriteString("\n" + pickerItemStyle.Render(lipgloss.NewStyle().Foreground(colRed).Render(m.ticketsErr)) + "\n")
+	}
+	if m.ticketsDupPane != "" {
+		warn := fmt.Sprintf("⚠ A session is already running in this project (%s).", m.ticketsDupPane)
+		sb.WriteString("\n" + pickerItemStyle.Render(lipgloss.NewStyle().Foreground(colAmber).Render(warn)) + "\n")
+		sb.WriteString("\n" + styleOverlayHelp.Render(i18n.T("[s] switch to it  [enter] start another anyway  [esc] cancel")))
+		return sb.String()
+	}
+
+	sb.WriteString("\n" + styleOverlayHelp.Render(i18n.T("[j/k] navigate  [enter] start session  [esc] cancel")))
+	return sb.String()
+}
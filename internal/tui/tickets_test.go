@@ -0,0 +1,87 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/shnupta/herd/internal/tasks"
+	"github.com/shnupta/herd/internal/tmux/tmuxtest"
+)
+
+func TestTicketsOverlayShowsLoadedTickets(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	m.mode = ModeTickets
+	m.ticketsLoading = true
+
+	updated, _ := m.Update(ticketsLoadedMsg{
+		tickets: []tasks.Ticket{{ID: "API-7", Title: "Fix login"}, {ID: "WEB-3", Title: "Dark mode"}},
+		errs:    []error{errors.New("linear: 401 Unauthorized")},
+	})
+	m = updated.(Model)
+	view := m.View()
+	for _, want := range []string{"API-7", "Dark mode", "401 Unauthorized"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q", want)
+		}
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	if updated.(Model).ticketsSelected != 1 {
+		t.Error("j should move to the second ticket")
+	}
+}
+
+func TestStartTicketNeedsAProject(t *testing.T) {
+	m, fw := newTestModel(t, nil)
+	defer fw.Close()
+	mock := m.tmuxClient.(*tmuxtest.MockClient)
+	m.mode = ModeTickets
+	m.tickets = []tasks.Ticket{{ID: "API-7", Title: "Fix login"}}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.mode != ModeTickets || m.ticketsErr == "" {
+		t.Errorf("mode = %v, err %q; want to stay open with an error", m.mode, m.ticketsErr)
+	}
+	if len(mock.NewWindowCalls) != 0 {
+		t.Errorf("NewWindowCalls = %v, want no launch", mock.NewWindowCalls)
+	}
+}
+
+func TestStartTicketGuardsAgainstDuplicates(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	mock := m.tmuxClient.(*tmuxtest.MockClient)
+	m.mode = ModeTickets
+	m.tickets = []tasks.Ticket{{ID: "API-7", Title: "Fix login", Project: "/home/user/project-beta"}}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.mode != ModeTickets || len(mock.NewWindowCalls) != 0 {
+		t.Fatalf("mode = %v, launched %v; want a prompt before a second session", m.mode, mock.NewWindowCalls)
+	}
+	if view := m.View(); !strings.Contains(view, "already running in this project (%2)") {
+		t.Errorf("view missing the duplicate warning:\n%s", view)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	m = updated.(Model)
+	if m.mode != ModeNormal || m.selectedSession().TmuxPane != "%2" {
+		t.Errorf("s: mode %v, selected %s; want a switch to %%2", m.mode, m.selectedSession().TmuxPane)
+	}
+}
+
+func TestLaunchSessionWithPromptQuotes(t *testing.T) {
+	mock := &tmuxtest.MockClient{CurrentSessionVal: "0", NewWindowPane: "%9"}
+	pane, err := LaunchSessionWithPrompt("/code/api", "Fix Bob's login", mock)
+	if err != nil || pane != "%9" {
+		t.Fatalf("LaunchSessionWithPrompt() = %q, %v", pane, err)
+	}
+	if len(mock.NewWindowCalls) != 1 || !strings.HasSuffix(mock.NewWindowCalls[0], `claude 'Fix Bob'\''s login'`) {
+		t.Errorf("NewWindowCalls = %v, want the prompt single-quoted", mock.NewWindowCalls)
+	}
+}
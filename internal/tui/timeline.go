@@ -0,0 +1,87 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/shnupta/herd/internal/history"
+)
+
+// Sidebar timelines cover the last timelineWindow in timelineSlots blocks,
+// reread from the history log at most every timelineRefresh.
+const (
+	timelineWindow  = time.Hour
+	timelineSlots   = 10
+	timelineRefresh = time.Minute
+)
+
+// timelinesMsg carries each session's recent states by session ID, oldest
+// slot first, and how many times herd acted on it in the same window.
+type timelinesMsg struct {
+	at        time.Time
+	timelines map[string][]string
+	acts      map[string]int
+}
+
+// loadTimelines rereads the history log if the timelines are stale.
+func (m Model) loadTimelines(now time.Time) tea.Cmd {
+	if now.Sub(m.timelinesAt) < timelineRefresh {
+		return nil
+	}
+	path := m.historyPath
+	return func() tea.Msg {
+		since := now.Add(-timelineWindow)
+		events, err := history.ReadSince(path, since)
+		if err != nil {
+			return nil
+		}
+		return timelinesMsg{
+			at:        now,
+			timelines: history.Timelines(events, since, now, timelineSlots),
+			acts:      history.ActionCounts(events, since, now),
+		}
+	}
+}
+
+// renderTimeline draws a session's recent states as a strip of blocks, tall
+// for working, half for waiting on the user and low for idle; "" when none
+// are known.
+func renderTimeline(slots []string) string {
+	if len(slots) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	for _, state := range slots {
+		var glyph string
+		var col lipgloss.Color
+		switch state {
+		case "working":
+			glyph, col = "█", colGreen
+		case "waiting":
+			glyph, col = "▄", colBlue
+		case "plan_ready":
+			glyph, col = "▄", colAmber
+		case "notifying":
+			glyph, col = "▄", colPurple
+		case "idle":
+			glyph, col = "▁", colCyan
+		default:
+			glyph, col = " ", colSubtle
+		}
+		sb.WriteString(lipgloss.NewStyle().Foreground(col).Render(glyph))
+	}
+	return sb.String()
+}
+
+// renderActs marks a timeline with how many times herd acted on the session
+// in its window, or "" when it didn't.
+func renderActs(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return lipgloss.NewStyle().Foreground(colGold).Render(fmt.Sprintf("⚙%d", n))
+}
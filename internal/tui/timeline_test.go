@@ -0,0 +1,43 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/shnupta/herd/internal/history"
+)
+
+func TestSidebarTimelineFromHistory(t *testing.T) {
+	m, _ := newTestModel(t, testSessions())
+	now := time.Now()
+	for _, ev := range []history.Event{
+		{Time: now.Add(-50 * time.Minute), Kind: history.KindState, SessionID: "sess-aaa", State: "waiting"},
+		{Time: now.Add(-30 * time.Minute), Kind: history.KindState, SessionID: "sess-aaa", State: "working"},
+	} {
+		if err := history.AppendTo(m.historyPath, ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	msg := m.loadTimelines(now)()
+	updated, _ := m.Update(msg)
+	m = updated.(Model)
+	if m.loadTimelines(now.Add(time.Second)) != nil {
+		t.Error("timelines should not be reread within timelineRefresh")
+	}
+
+	got := m.timelines["sess-aaa"]
+	if len(got) != timelineSlots || got[0] != "" || got[2] != "waiting" || got[len(got)-1] != "working" {
+		t.Fatalf("timeline = %q", got)
+	}
+	view := ansi.Strip(m.View())
+	if !strings.Contains(view, "project-alpha  ▄▄▄▄█████") {
+		t.Errorf("sidebar should show alpha's timeline:\n%s", view)
+	}
+	if _, ok := m.timelines["sess-bbb"]; ok {
+		t.Error("beta has no history and should have no timeline")
+	}
+}
@@ -0,0 +1,72 @@
+package tui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// settleTimeout is how long keys wait for a resized pane to redraw before
+// they are sent anyway.
+const settleTimeout = 500 * time.Millisecond
+
+// settle tracks a pane whose window herd has resized until Claude redraws
+// at the new size. Keys sent while it redraws can be lost, so insert mode
+// holds them until then.
+type settle struct {
+	since   time.Time
+	resized bool         // the resize is done; the next capture shows the redraw
+	keys    []tea.KeyMsg // typed meanwhile, to send once settled
+}
+
+// startSettle marks pane as redrawing after a resize, keeping keys already
+// held for it.
+func (m Model) startSettle(pane string) {
+	if m.settling == nil {
+		return
+	}
+	if s := m.settling[pane]; s != nil {
+		s.since, s.resized = time.Now(), false
+		return
+	}
+	m.settling[pane] = &settle{since: time.Now()}
+}
+
+// holdKey keeps a key typed into pane while it settles, reporting whether
+// it did.
+func (m Model) holdKey(pane string, msg tea.KeyMsg) bool {
+	s := m.settling[pane]
+	if s == nil {
+		return false
+	}
+	s.keys = append(s.keys, msg)
+	return true
+}
+
+// settled sends the keys held for pane, in the order typed, and stops
+// holding keys for it.
+func (m Model) settled(pane string) Model {
+	s := m.settling[pane]
+	if s == nil {
+		return m
+	}
+	delete(m.settling, pane)
+	for _, k := range s.keys {
+		if err := m.forwardKey(pane, k); err != nil {
+			m.err = err
+			break
+		}
+	}
+	return m
+}
+
+// settleExpired sends the keys of panes that have taken longer than
+// settleTimeout to settle, or of every pane if all is set.
+func (m Model) settleExpired(now time.Time, all bool) Model {
+	for pane, s := range m.settling {
+		if all || now.Sub(s.since) >= settleTimeout {
+			m = m.settled(pane)
+		}
+	}
+	return m
+}
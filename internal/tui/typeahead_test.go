@@ -0,0 +1,57 @@
+package tui
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/shnupta/herd/internal/tmux/tmuxtest"
+)
+
+func TestTypeAheadWaitsForResizedPane(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	mock := m.tmuxClient.(*tmuxtest.MockClient)
+	m.viewport.Width, m.viewport.Height = 80, 20
+
+	m = pressKey(t, m, "j")
+	pane := m.selectedSession().TmuxPane
+	if m.settling[pane] == nil {
+		t.Fatalf("%s not settling after its window was resized", pane)
+	}
+	for _, k := range []string{"i", "h", "i"} {
+		m = pressKey(t, m, k)
+	}
+	if len(mock.SendLiteralCalls) != 0 {
+		t.Fatalf("sent %q before the pane redrew", mock.SendLiteralCalls)
+	}
+
+	// The resize is done, then a capture shows the pane redrawn.
+	updated, _ := m.Update(paneSharedMsg{paneID: pane})
+	m = updated.(Model)
+	if len(mock.SendLiteralCalls) != 0 {
+		t.Fatalf("sent %q before the redraw was captured", mock.SendLiteralCalls)
+	}
+	updated, _ = m.Update(captureMsg{paneID: pane, content: "> "})
+	m = updated.(Model)
+	m = pressKey(t, m, "!")
+	if want := []string{pane + ":h", pane + ":i", pane + ":!"}; !reflect.DeepEqual(mock.SendLiteralCalls, want) {
+		t.Errorf("sent %q, want %q", mock.SendLiteralCalls, want)
+	}
+}
+
+func TestTypeAheadGivesUpWaiting(t *testing.T) {
+	m, fw := newTestModel(t, testSessions())
+	defer fw.Close()
+	mock := m.tmuxClient.(*tmuxtest.MockClient)
+	pane := m.selectedSession().TmuxPane
+
+	m.startSettle(pane)
+	m = pressKey(t, m, "i")
+	m = pressKey(t, m, "x")
+	updated, _ := m.Update(tickMsg(time.Now().Add(settleTimeout)))
+	m = updated.(Model)
+	if want := []string{pane + ":x"}; !reflect.DeepEqual(mock.SendLiteralCalls, want) || m.settling[pane] != nil {
+		t.Errorf("after the timeout sent %q, want %q", mock.SendLiteralCalls, want)
+	}
+}
@@ -1,53 +1,140 @@
 package tui
 
 import (
+	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/x/ansi"
+	"github.com/shnupta/herd/internal/config"
 	"github.com/shnupta/herd/internal/diff"
 	"github.com/shnupta/herd/internal/git"
 	"github.com/shnupta/herd/internal/groups"
+	"github.com/shnupta/herd/internal/history"
 	"github.com/shnupta/herd/internal/hook"
+	"github.com/shnupta/herd/internal/i18n"
 	"github.com/shnupta/herd/internal/names"
+	"github.com/shnupta/herd/internal/plugin"
+	"github.com/shnupta/herd/internal/review"
+	"github.com/shnupta/herd/internal/script"
 	"github.com/shnupta/herd/internal/session"
+	"github.com/shnupta/herd/internal/slash"
 	"github.com/shnupta/herd/internal/state"
 	"github.com/shnupta/herd/internal/tmux"
 )
 
-func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	switch m.mode {
-	case ModeReview:
-		// Review mode only intercepts key/window/mouse messages;
-		// other messages (ticks, refresh, etc.) fall through to the main handler.
-		switch msg.(type) {
-		case tea.KeyMsg, tea.WindowSizeMsg, tea.MouseMsg:
-			return m.updateReviewMode(msg)
-		}
-	case ModePicker:
-		// Picker mode only intercepts key/window messages;
-		// other messages fall through to the main handler.
-		switch msg.(type) {
-		case tea.KeyMsg, tea.WindowSizeMsg:
-			return m.updatePickerMode(msg)
-		}
-	case ModeFilter:
-		return m.updateFilterMode(msg)
-	case ModeRename:
-		return m.updateRenameMode(msg)
-	case ModeGroupSet:
-		return m.updateGroupSetMode(msg)
-	case ModeWorktree:
+// modeRoute describes how a non-normal mode receives messages.
+type modeRoute struct {
+	// intercepts reports whether the mode handles msg itself. Anything it
+	// declines (ticks, discovery, state updates) falls through to
+	// updateNormal so background work keeps running under overlays.
+	intercepts func(tea.Msg) bool
+	update     func(Model, tea.Msg) (tea.Model, tea.Cmd)
+}
+
+// interceptInput routes key and window-size messages, plus any message
+// whose type matches one of extra.
+func interceptInput(extra ...func(tea.Msg) bool) func(tea.Msg) bool {
+	return func(msg tea.Msg) bool {
 		switch msg.(type) {
 		case tea.KeyMsg, tea.WindowSizeMsg:
-			return m.updateWorktreeMode(msg)
+			return true
+		}
+		for _, f := range extra {
+			if f(msg) {
+				return true
+			}
 		}
+		return false
 	}
+}
+
+// interceptAll routes every message to the mode. The text-input overlays use
+// it so cursor blink messages reach their inputs.
+func interceptAll(tea.Msg) bool { return true }
+
+func isMouseMsg(msg tea.Msg) bool          { _, ok := msg.(tea.MouseMsg); return ok }
+func isBulkEditorDoneMsg(msg tea.Msg) bool { _, ok := msg.(bulkEditorDoneMsg); return ok }
+
+var modeRoutes = map[Mode]modeRoute{
+	ModeReview: {
+		intercepts: interceptInput(isMouseMsg, isFileEditedMsg),
+		update:     Model.updateReviewMode,
+	},
+	ModePicker:   {intercepts: interceptInput(isPickerPreviewMsg), update: Model.updatePickerMode},
+	ModeFilter:   {intercepts: interceptAll, update: Model.updateFilterMode},
+	ModeRename:   {intercepts: interceptAll, update: Model.updateOverlay},
+	ModeGroupSet: {intercepts: interceptAll, update: Model.updateOverlay},
+	ModeWorktree: {intercepts: interceptInput(isWorktreeUsageMsg), update: Model.updateWorktreeMode},
+	ModePalette:  {intercepts: interceptInput(), update: Model.updatePaletteMode},
+	ModeActions:  {intercepts: interceptInput(), update: Model.updateActionsMode},
+	ModeTickets: {
+		intercepts: interceptInput(isTicketsLoadedMsg),
+		update:     Model.updateTicketsMode,
+	},
+	ModeMerge: {
+		intercepts: interceptInput(isMergeMsg, isMouseMsg),
+		update:     Model.updateMergeMode,
+	},
+	ModeQueue: {
+		intercepts: interceptInput(isCaptureMsg, isSessionChangeMsg, isMouseMsg),
+		update:     Model.updateQueueMode,
+	},
+	ModeBlockedOn: {intercepts: interceptInput(), update: Model.updateBlockedOnMode},
+	ModeStats: {
+		intercepts: interceptInput(isStatsLoadedMsg),
+		update:     Model.updateStatsMode,
+	},
+	ModeSnippetName: {intercepts: interceptAll, update: Model.updateOverlay},
+	ModeSnippets: {
+		intercepts: interceptInput(isMouseMsg),
+		update:     Model.updateSnippetsMode,
+	},
+	ModeSquads: {intercepts: interceptInput(), update: Model.updateSquadsMode},
+	ModeMenu:   {intercepts: interceptInput(isMouseMsg), update: Model.updateMenuMode},
+	ModeTeardown: {
+		intercepts: interceptInput(isTeardownStepMsg),
+		update:     Model.updateTeardownMode,
+	},
+	ModeFocusTimer: {intercepts: interceptAll, update: Model.updateOverlay},
+	ModeCompose:    {intercepts: interceptAll, update: Model.updateComposeMode},
+	ModeBroadcast:  {intercepts: interceptAll, update: Model.updateBroadcastMode},
+	ModeActivity: {
+		intercepts: interceptInput(isActivityLoadedMsg),
+		update:     Model.updateActivityMode,
+	},
+	ModeSearch: {
+		intercepts: interceptInput(isCaptureMsg, isSearchCaptureMsg),
+		update:     Model.updateSearchMode,
+	},
+	ModeFiles: {
+		intercepts: interceptInput(isFileEditedMsg),
+		update:     Model.updateFilesMode,
+	},
+	ModePluginPanel: {
+		intercepts: interceptInput(isMouseMsg),
+		update:     Model.updatePluginPanelMode,
+	},
+	ModeBulkEdit: {
+		intercepts: interceptInput(isBulkEditorDoneMsg),
+		update:     Model.updateBulkEditMode,
+	},
+	ModeOnboarding: {intercepts: interceptInput(), update: Model.updateOnboardingMode},
+	ModePlanReject: {intercepts: interceptAll, update: Model.updateOverlay},
+}
 
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	m.invalidateFrame(msg)
+	if route, ok := modeRoutes[m.mode]; ok && route.intercepts(msg) {
+		return route.update(m, msg)
+	}
 	return m.updateNormal(msg)
 }
 
@@ -63,15 +150,42 @@ func (m Model) updateReviewMode(msg tea.Msg) (tea.Model, tea.Cmd) {
 	m.reviewModel = &reviewModel
 
 	if reviewModel.Submitted() {
-		if sel := m.selectedSession(); sel != nil && reviewModel.FeedbackText() != "" {
-			_ = m.tmuxClient.SendKeys(sel.TmuxPane, reviewModel.FeedbackText())
+		if err := reviewModel.FeedbackErr(); err != nil {
+			m.err = fmt.Errorf("feedback template: %w", err)
+		}
+		var scripts tea.Cmd
+		if sel := m.feedbackTarget(reviewModel.Target()); sel != nil && reviewModel.FeedbackText() != "" {
+			if reviewModel.ToTodo() {
+				path := config.Load().ReviewTodoPathFor(reviewModel.projectPath)
+				if err := review.WriteTodo(path, reviewModel.FeedbackText(), time.Now()); err != nil {
+					m.err = err
+				} else {
+					_ = m.tmuxClient.DisplayMessage("herd: review feedback written to " + path)
+				}
+			} else {
+				_ = m.sendPrompt(sel.TmuxPane, reviewModel.FeedbackText(), "review")
+			}
+			_ = history.Append(history.Event{Kind: history.KindReview, SessionID: sel.ID, TmuxPane: sel.TmuxPane, Project: sel.ProjectPath})
+			scripts = m.fireScripts(script.Event{Name: script.ReviewSubmitted, Fields: map[string]string{
+				"key":      sel.Key(),
+				"pane":     sel.TmuxPane,
+				"project":  sel.ProjectPath,
+				"feedback": reviewModel.FeedbackText(),
+			}})
+		}
+		var hooks tea.Cmd
+		if sel := m.selectedSession(); sel != nil {
+			if reviewModel.Approved() {
+				m.releaseBlocked(*sel)
+			}
+			hooks = reviewWebhooks(*sel, reviewModel)
 		}
 		m.mode = ModeNormal
 		m.reviewModel = nil
 		m.lastCapture = ""
 		m.forceViewportRefresh = true
 		if sel := m.selectedSession(); sel != nil {
-			return m, tea.Batch(tickCapture(), tickSessionRefresh(), m.fetchCapture(sel.TmuxPane))
+			return m, tea.Batch(tickCapture(), tickSessionRefresh(), m.fetchCapture(sel.TmuxPane), scripts, hooks)
 		}
 		return m, tea.Batch(tickCapture(), tickSessionRefresh())
 	} else if reviewModel.Cancelled() {
@@ -88,6 +202,61 @@ func (m Model) updateReviewMode(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// reviewTargets lists the sessions review feedback can go to: the reviewed
+// session first.
+func (m Model) reviewTargets(reviewed session.Session) []reviewTarget {
+	targets := []reviewTarget{{key: reviewed.Key(), name: m.displayName(reviewed)}}
+	for _, s := range m.sessions {
+		if s.Key() != reviewed.Key() {
+			targets = append(targets, reviewTarget{key: s.Key(), name: m.displayName(s)})
+		}
+	}
+	return targets
+}
+
+// feedbackTarget returns the session keyed key, or the selected (reviewed)
+// session when key is empty.
+func (m Model) feedbackTarget(key string) *session.Session {
+	if key == "" {
+		return m.selectedSession()
+	}
+	for i := range m.sessions {
+		if m.sessions[i].Key() == key {
+			return &m.sessions[i]
+		}
+	}
+	return nil
+}
+
+func (m Model) updatePaletteMode(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.paletteModel == nil {
+		return m.updateNormal(msg)
+	}
+
+	updated, cmd := m.paletteModel.Update(msg)
+	paletteModel := updated.(PaletteModel)
+	m.paletteModel = &paletteModel
+
+	if paletteModel.Chosen() == "" && !paletteModel.Cancelled() {
+		return m, cmd
+	}
+	if chosen := paletteModel.Chosen(); chosen != "" {
+		if sel := m.selectedSession(); sel != nil {
+			if err := m.sendPrompt(sel.TmuxPane, chosen, "command"); err != nil {
+				m.err = err
+			}
+		}
+	}
+	m.mode = ModeNormal
+	m.paletteModel = nil
+	m.lastCapture = ""
+	m.forceViewportRefresh = true
+	if sel := m.selectedSession(); sel != nil {
+		return m, tea.Batch(tickCapture(), tickSessionRefresh(), m.fetchCapture(sel.TmuxPane))
+	}
+	return m, tea.Batch(tickCapture(), tickSessionRefresh())
+}
+
 func (m Model) updatePickerMode(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if m.pickerModel == nil {
 		return m.updateNormal(msg)
@@ -97,8 +266,15 @@ func (m Model) updatePickerMode(msg tea.Msg) (tea.Model, tea.Cmd) {
 	pickerModel := updated.(PickerModel)
 	m.pickerModel = &pickerModel
 
-	if pickerModel.ChosenPath() != "" {
-		if paneID, err := LaunchSession(pickerModel.ChosenPath(), m.tmuxClient); err != nil {
+	if pane := pickerModel.SwitchPane(); pane != "" {
+		m.pickerModel = nil
+		return m.switchToSession(pane)
+	} else if pickerModel.ChosenPath() != "" {
+		var t config.Template
+		if chosen := pickerModel.Template(); chosen != nil {
+			t = *chosen
+		}
+		if paneID, err := LaunchSessionFromTemplate(pickerModel.ChosenPath(), t, m.tmuxClient); err != nil {
 			m.err = err
 		} else {
 			m.pendingSelectPane = paneID
@@ -144,7 +320,7 @@ func (m Model) updateWorktreeMode(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		m.mode = ModeNormal
 		m.worktreeModel = nil
-		return m, createAndLaunchWorktree(m.tmuxClient, repoRoot, createPath, branch)
+		return m, createAndLaunchWorktree(m.tmuxClient, repoRoot, createPath, branch, wm.CreateBase(), wm.mainPath(), wm.setup)
 	}
 	if wtPath, sessionPane, ok := wm.ShouldRemove(); ok {
 		repoRoot := ""
@@ -153,7 +329,7 @@ func (m Model) updateWorktreeMode(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		m.mode = ModeNormal
 		m.worktreeModel = nil
-		return m, removeWorktree(m.tmuxClient, repoRoot, wtPath, sessionPane)
+		return m, removeWorktree(m.tmuxClient, repoRoot, wtPath, sessionPane, m.auditCmd(sessionPane, history.ActionKill, "worktree remove", wtPath))
 	}
 	if wm.Cancelled() {
 		m.mode = ModeNormal
@@ -170,87 +346,56 @@ func (m Model) updateWorktreeMode(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m Model) updateFilterMode(msg tea.Msg) (tea.Model, tea.Cmd) {
-	switch msg := msg.(type) {
-	case tea.KeyMsg:
-		switch msg.String() {
-		case "esc":
-			m.mode = ModeNormal
-			m.filterQuery = ""
-			m.filterInput.Reset()
-			m.filtered = nil
-			return m, nil
-		case "enter":
-			m.mode = ModeNormal
-			m.filterInput.Blur()
-			return m, nil
-		case "backspace":
-			if m.filterInput.Value() == "" {
-				m.mode = ModeNormal
-				m.filterQuery = ""
-				m.filtered = nil
-				return m, nil
-			}
-		}
-	}
-
 	var cmd tea.Cmd
-	m.filterInput, cmd = m.filterInput.Update(msg)
-	m.filterQuery = m.filterInput.Value()
+	m.filter, cmd = m.filter.Update(msg)
+	m.filterQuery = m.filter.Query()
 	m.updateFilter()
+	if m.filter.Closed() {
+		m.mode = ModeNormal
+	}
 	return m, cmd
 }
 
-func (m Model) updateRenameMode(msg tea.Msg) (tea.Model, tea.Cmd) {
-	switch msg := msg.(type) {
-	case tea.KeyMsg:
-		switch msg.String() {
-		case "esc":
-			m.mode = ModeNormal
-			m.renameInput.Reset()
-			m.renameKey = ""
-			return m, nil
-		case "enter":
-			label := strings.TrimSpace(m.renameInput.Value())
-			if label == "" {
-				_ = names.Delete(m.renameKey)
-			} else {
-				_ = names.Set(m.renameKey, label)
-			}
-			m.mode = ModeNormal
-			m.renameInput.Reset()
-			m.renameKey = ""
-			m.itemsDirty = true
-			return m, nil
-		}
+// openRename asks for the selected session's name.
+func (m Model) openRename() (Model, tea.Cmd) {
+	sel := m.selectedSession()
+	if sel == nil {
+		return m, nil
 	}
-
-	var cmd tea.Cmd
-	m.renameInput, cmd = m.renameInput.Update(msg)
-	return m, cmd
+	key := sel.Key()
+	in := textinput.New()
+	in.Placeholder = i18n.T("session name...")
+	in.CharLimit = 100
+	in.SetValue(names.Get(key))
+	return m.openPrompt(ModeRename, promptOverlay{
+		title: i18n.T("Rename Session"),
+		input: in,
+		help:  i18n.T("[enter] save  [esc] cancel  (empty to clear name)"),
+		parse: func(text string) (tea.Msg, error) {
+			return renameMsg{key: key, name: text}, nil
+		},
+	})
 }
 
-func (m Model) updateGroupSetMode(msg tea.Msg) (tea.Model, tea.Cmd) {
-	switch msg := msg.(type) {
-	case tea.KeyMsg:
-		switch msg.String() {
-		case "esc":
-			m.mode = ModeNormal
-			m.groupSetInput.Reset()
-			m.groupSetKey = ""
-			return m, nil
-		case "enter":
-			groupName := strings.TrimSpace(m.groupSetInput.Value())
-			_ = groups.Set(m.groupSetKey, groupName)
-			m.mode = ModeNormal
-			m.groupSetInput.Reset()
-			m.groupSetKey = ""
-			m.itemsDirty = true
-			return m, nil
-		}
+// openGroupSet asks which group the selected session belongs in.
+func (m Model) openGroupSet() (Model, tea.Cmd) {
+	sel := m.selectedSession()
+	if sel == nil {
+		return m, nil
 	}
-	var cmd tea.Cmd
-	m.groupSetInput, cmd = m.groupSetInput.Update(msg)
-	return m, cmd
+	key := sel.Key()
+	in := textinput.New()
+	in.Placeholder = i18n.T("group name (empty to auto-detect)...")
+	in.CharLimit = 100
+	in.SetValue(groups.Get(key))
+	return m.openPrompt(ModeGroupSet, promptOverlay{
+		title: i18n.T("Set Group"),
+		input: in,
+		help:  i18n.T("[enter] save  [esc] cancel  (empty to use auto-detected group)"),
+		parse: func(text string) (tea.Msg, error) {
+			return groupSetMsg{key: key, group: text}, nil
+		},
+	})
 }
 
 // ── Normal mode ────────────────────────────────────────────────────────────
@@ -274,10 +419,8 @@ func (m Model) updateNormal(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	// ── Initial session discovery ──────────────────────────────────────────
 	case sessionsDiscoveredMsg:
-		var selectedPane string
-		if m.selected < len(m.sessions) {
-			selectedPane = m.sessions[m.selected].TmuxPane
-		}
+		m = m.reconnected()
+		anchor := m.anchorSelection()
 
 		existing := make(map[string]session.Session)
 		for _, s := range m.sessions {
@@ -301,23 +444,13 @@ func (m Model) updateNormal(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if states, err := state.ReadAll(); err == nil {
 			m = m.applyStates(states)
 		}
+		migrations := m.migrateKeys()
 		m.cleanupSidebarState()
 		if m.sidebarDirty {
 			m.saveSidebarState()
 		}
 		m.sortSessions()
-
-		if selectedPane != "" {
-			for i, s := range m.sessions {
-				if s.TmuxPane == selectedPane {
-					m.selected = i
-					break
-				}
-			}
-		}
-		if m.selected >= len(m.sessions) {
-			m.selected = maxInt(0, len(m.sessions)-1)
-		}
+		m.followSelection(anchor, migrations)
 		if m.pendingSelectPane != "" {
 			for i, s := range m.sessions {
 				if s.TmuxPane == m.pendingSelectPane {
@@ -335,6 +468,15 @@ func (m Model) updateNormal(msg tea.Msg) (tea.Model, tea.Cmd) {
 				cmds = append(cmds, m.pendingDiscoveryTick())
 			}
 		}
+		if m.preset != nil {
+			var cmd tea.Cmd
+			m, cmd = m.applyPreset()
+			cmds = append(cmds, cmd)
+		}
+		if m.setupPending {
+			m.setupPending = false
+			cmds = append(cmds, m.checkOnboarding(true))
+		}
 		if m.ready {
 			if sel := m.selectedSession(); sel != nil {
 				cmds = append(cmds, m.resizePaneToViewport(sel.TmuxPane, m.viewport.Width, m.viewport.Height))
@@ -342,18 +484,108 @@ func (m Model) updateNormal(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	// ── Session list auto-refresh ──────────────────────────────────────────
+	case hubMsg:
+		m = m.applyHub(msg)
+		return m, nil
+
 	case sessionRefreshMsg:
 		_ = m.teamsStore.Load() // pick up new/updated team configs
-		cmds = append(cmds, m.discoverSessions(), tickSessionRefresh())
+		cfg := config.Load()
+		m.capture = cfg.Capture
+		m.urgentAlert = cfg.UrgentAlert
+		m.alertStates = cfg.AlertStates
+		m.staleAfter = time.Duration(cfg.StaleAfterMinutes) * time.Minute
+		m.itemsDirty = true // sessions go stale as time passes
+		m.reloadSidebarState()
+		cmds = append(cmds, m.discoverSessions(), tickSessionRefresh(), m.scanBadges(), m.scanProgress(), m.scanPluginBadges(), m.loadTimelines(time.Time(msg)))
+		if cfg.HubURL == "" {
+			m.hubURL, m.hubSessions, m.hubErr = "", nil, nil
+		}
+		cmds = append(cmds, fetchHub(cfg.HubURL, cfg.HubToken))
+		if sel := m.selectedSession(); sel != nil {
+			cmds = append(cmds, m.checkPaneShared(sel.TmuxPane))
+		}
+
+	case paneSharedMsg:
+		if s := m.settling[msg.paneID]; s != nil {
+			s.resized = true
+			cmds = append(cmds, m.fetchCapture(msg.paneID))
+		}
+		switch wasShared := m.sharedPane == msg.paneID; {
+		case msg.shared && !wasShared:
+			// Hand the window back to tmux's own sizing in case herd sized
+			// it before the other viewer turned up.
+			m.sharedPane = msg.paneID
+			client, pane := m.tmuxClient, msg.paneID
+			logged := m.auditCmd(pane, history.ActionResize, "pane shared", "tmux sizing")
+			cmds = append(cmds, func() tea.Msg {
+				if client.ResizePaneAuto(pane) == nil {
+					logged()
+				}
+				return nil
+			})
+		case !msg.shared && wasShared:
+			m.sharedPane = ""
+			if sel := m.selectedSession(); sel != nil && sel.TmuxPane == msg.paneID {
+				cmds = append(cmds, m.resizePaneToViewport(sel.TmuxPane, m.viewport.Width, m.viewport.Height))
+			}
+		}
+
+	case onboardingMsg:
+		m = m.onboardingChecked(msg)
+
+	case badgesMsg:
+		m.badges = msg
+
+	case progressMsg:
+		m.toolProgress = msg
+
+	case timelinesMsg:
+		m.timelines, m.timelineActs, m.timelinesAt = msg.timelines, msg.acts, msg.at
+
+	// ── Plugins ────────────────────────────────────────────────────────────
+	case pluginsLoadedMsg:
+		m.plugins = msg
+		cmds = append(cmds, m.scanPluginBadges())
+
+	case pluginBadgesMsg:
+		m.pluginBadges = msg
+
+	case pluginResultMsg:
+		var cmd tea.Cmd
+		m, cmd = m.applyPluginResult(msg)
+		cmds = append(cmds, cmd)
 
 	// ── Capture-pane poll ──────────────────────────────────────────────────
 	case tickMsg:
 		cmds = append(cmds, tickCapture())
+		var expired tea.Cmd
+		m, expired = m.expireFocusTimers(time.Time(msg))
+		cmds = append(cmds, expired)
+		m = m.settleExpired(time.Time(msg), false)
+		m.expireEchoes(time.Time(msg))
 		if sel := m.selectedSession(); sel != nil {
-			cmds = append(cmds, m.fetchCapture(sel.TmuxPane))
+			// Sessions can ask to be captured less often than every tick.
+			interval := m.captureFor(*sel).IntervalMS
+			if sel.State == session.StateIdle {
+				interval = m.captureFor(*sel).IdleIntervalMS
+			}
+			if now := time.Time(msg); now.Sub(m.lastCaptureAt) >= time.Duration(interval)*time.Millisecond-pollInterval/2 {
+				m.lastCaptureAt = now
+				cmds = append(cmds, m.fetchCapture(sel.TmuxPane))
+			}
+		}
+
+	case testTickMsg:
+		if m.testsRunning() {
+			cmds = append(cmds, tickTests())
 		}
 
 	case captureMsg:
+		m.confirmEcho(msg.paneID, msg.content)
+		if s := m.settling[msg.paneID]; s != nil && s.resized {
+			m = m.settled(msg.paneID)
+		}
 		if sel := m.selectedSession(); sel != nil && sel.TmuxPane == msg.paneID {
 			contentChanged := msg.content != m.lastCapture
 			if contentChanged || m.forceViewportRefresh {
@@ -368,7 +600,8 @@ func (m Model) updateNormal(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.atBottom = m.viewport.AtBottom()
 				}
 
-				m.viewport.SetContent(truncateLines(cleanCapture(msg.content), m.viewport.Width))
+				m.viewport.SetContent(truncateLines(m.displayCapture(msg.paneID, msg.content), m.viewport.Width))
+				m = m.noteProgress(*sel, msg.content)
 				if m.atBottom {
 					m.viewport.GotoBottom()
 				}
@@ -377,7 +610,15 @@ func (m Model) updateNormal(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	// ── Hook state update ──────────────────────────────────────────────────
 	case stateUpdateMsg:
+		before := sessionStates(m.sessions)
 		m = m.applyStates([]state.SessionState{state.SessionState(msg)})
+		cmds = append(cmds, m.fireScripts(stateChangeEvents(before, m.sessions)...))
+		cmds = append(cmds, m.urgentAlerts(before))
+		m.migrateKeys()
+		m.releaseNowWaiting(before)
+		if m.sidebarDirty {
+			m.saveSidebarState()
+		}
 		cmds = append(cmds, waitForStateEvent(m.stateWatcher))
 
 	// ── Spinner ────────────────────────────────────────────────────────────
@@ -391,6 +632,43 @@ func (m Model) updateNormal(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.pendingSelectPane = string(msg)
 		return m, tea.Batch(m.discoverSessions(), tickCapture(), tickSessionRefresh())
 
+	case worktreeCreatedMsg:
+		m.pendingSelectPane = msg.pane
+		if msg.setupErr != nil {
+			_ = m.tmuxClient.DisplayMessage("herd: worktree setup: " + msg.setupErr.Error())
+		} else if msg.copied > 0 {
+			_ = m.tmuxClient.DisplayMessage(fmt.Sprintf("herd: copied %d untracked files into the worktree", msg.copied))
+		}
+		return m, tea.Batch(m.discoverSessions(), tickCapture(), tickSessionRefresh(),
+			m.fireScripts(script.Event{Name: script.WorktreeCreated, Fields: map[string]string{
+				"pane":   msg.pane,
+				"path":   msg.path,
+				"branch": msg.branch,
+			}}))
+
+	// ── Script handlers finished ───────────────────────────────────────────
+	case scriptsDoneMsg:
+		// Handlers may have renamed or regrouped sessions.
+		m.itemsDirty = true
+		if len(msg.errs) > 0 {
+			_ = m.tmuxClient.DisplayMessage("herd: " + msg.errs[0].Error())
+		}
+
+	case snapshotDoneMsg:
+		if msg.err != nil {
+			m.err = msg.err
+		}
+		if msg.link != "" {
+			_ = m.tmuxClient.DisplayMessage("herd: snapshot shared at " + msg.link)
+		} else if msg.path != "" {
+			_ = m.tmuxClient.DisplayMessage("herd: snapshot saved to " + msg.path)
+		}
+
+	case webhooksDoneMsg:
+		if len(msg.errs) > 0 {
+			_ = m.tmuxClient.DisplayMessage("herd: " + msg.errs[0].Error())
+		}
+
 	// ── Worktree removed ───────────────────────────────────────────────────
 	case worktreeRemovedMsg:
 		if msg.sessionPane != "" {
@@ -416,16 +694,42 @@ func (m Model) updateNormal(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case errMsg:
 		m.err = msg.err
 
+	case tmuxLostMsg:
+		m = m.loseTmux(msg.err)
+
 	// ── Keyboard ──────────────────────────────────────────────────────────
 	case tea.KeyMsg:
 		if m.insertMode {
 			if msg.String() == "ctrl+h" {
+				m = m.settleExpired(time.Now(), true)
 				m.insertMode = false
-			} else if sel := m.selectedSession(); sel != nil {
-				if err := m.forwardKey(sel.TmuxPane, msg); err != nil {
-					m.err = err
-					m.insertMode = false
-				} else {
+				m = m.flushTyped()
+			} else if panes := m.insertPanes(); len(panes) > 0 {
+				if time.Since(m.insertChecked) > paneRecheck {
+					for _, pane := range panes {
+						if m.guardPane(pane) != nil {
+							m.insertMode = false
+							m = m.flushTyped()
+							return m, nil
+						}
+					}
+					m.insertChecked = time.Now()
+				}
+				for _, pane := range panes {
+					if m.holdKey(pane, msg) {
+						continue
+					}
+					if err := m.forwardKey(pane, msg); err != nil {
+						m.err = err
+						m.insertMode = false
+						break
+					}
+				}
+				m = m.typeKey(panes, msg)
+				m.echoKey(panes, msg)
+				if !m.insertMode {
+					m = m.flushTyped()
+				} else if sel := m.selectedSession(); sel != nil {
 					cmds = append(cmds, m.fetchCapture(sel.TmuxPane))
 				}
 			}
@@ -434,8 +738,11 @@ func (m Model) updateNormal(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		switch {
 		case key.Matches(msg, keys.Quit):
+			m = m.flushTyped()
 			for _, s := range m.sessions {
-				_ = m.tmuxClient.ResizePaneAuto(s.TmuxPane)
+				if m.tmuxClient.ResizePaneAuto(s.TmuxPane) == nil {
+					m.audit(s.TmuxPane, history.ActionResize, "quit", "tmux sizing")
+				}
 			}
 			return m, tea.Quit
 
@@ -472,8 +779,31 @@ func (m Model) updateNormal(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 
+		case key.Matches(msg, keys.RunTests):
+			var cmd tea.Cmd
+			m, cmd = m.runTests()
+			cmds = append(cmds, cmd)
+
+		case key.Matches(msg, keys.TestPanel):
+			m.testPanelOpen = !m.testPanelOpen
+			m = m.recalcLayout()
+			if sel := m.selectedSession(); sel != nil {
+				cmds = append(cmds, m.resizePaneToViewport(sel.TmuxPane, m.viewport.Width, m.viewport.Height))
+			}
+
+		case key.Matches(msg, keys.Scratch):
+			m.toggleScratch()
+
 		case key.Matches(msg, keys.Insert):
+			if m.insertComposer {
+				var cmd tea.Cmd
+				m, cmd = m.openCompose()
+				cmds = append(cmds, cmd)
+				break
+			}
 			m.insertMode = true
+			m.insertGroup, m.insertGroupName = "", ""
+			m.insertChecked = time.Time{}
 
 		case key.Matches(msg, keys.Refresh):
 			cmds = append(cmds, m.discoverSessions())
@@ -489,6 +819,7 @@ func (m Model) updateNormal(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if err := m.tmuxClient.KillPane(sel.TmuxPane); err != nil {
 					m.err = err
 				} else {
+					m.audit(sel.TmuxPane, history.ActionKill, "kill key", "")
 					delete(m.pinned, sel.Key())
 					m.sessions = append(m.sessions[:m.selected], m.sessions[m.selected+1:]...)
 					if m.selected >= len(m.sessions) {
@@ -505,14 +836,11 @@ func (m Model) updateNormal(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 
+		case key.Matches(msg, keys.Teardown):
+			m = m.openTeardown()
+
 		case key.Matches(msg, keys.New):
-			var existingPaths []string
-			for _, s := range m.sessions {
-				if s.ProjectPath != "" {
-					existingPaths = append(existingPaths, s.ProjectPath)
-				}
-			}
-			pickerModel := NewPickerModel(existingPaths)
+			pickerModel := NewPickerModel(m.sessions)
 			updatedModel, _ := pickerModel.Update(tea.WindowSizeMsg{
 				Width:  m.width,
 				Height: m.height,
@@ -520,13 +848,18 @@ func (m Model) updateNormal(msg tea.Msg) (tea.Model, tea.Cmd) {
 			pickerModel = updatedModel.(PickerModel)
 			m.pickerModel = &pickerModel
 			m.mode = ModePicker
+			cmds = append(cmds, fetchPickerPreviews(m.sessions, m.tmuxClient))
 
 		case key.Matches(msg, keys.Worktree):
 			if sel := m.selectedSession(); sel != nil && sel.GitRoot != "" {
 				if worktrees, err := git.ListWorktrees(sel.GitRoot); err == nil {
 					wm := NewWorktreeModel(worktrees, sel.GitRoot, m.sessions, m.width, m.height)
+					cfg := config.Load()
+					wm.setup = cfg.WorktreeSetupFor(wm.mainPath())
+					wm.warnGB = cfg.WorktreeWarnGB
 					m.worktreeModel = &wm
 					m.mode = ModeWorktree
+					return m, measureWorktrees(worktrees)
 				}
 			}
 
@@ -534,37 +867,172 @@ func (m Model) updateNormal(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if sel := m.selectedSession(); sel != nil {
 				gitRoot, err := diff.GetGitRoot(sel.ProjectPath)
 				if err == nil {
-					diffText, err := diff.GetGitDiff(gitRoot)
-					if err == nil && diffText != "" {
-						parsed, err := diff.Parse(diffText)
-						if err == nil && !parsed.IsEmpty() {
-							sessionID := sel.ID
-							if sessionID == "" {
-								sessionID = sel.TmuxPane
-							}
-							reviewModel := NewReviewModel(parsed, sessionID, gitRoot)
-							updatedModel, _ := reviewModel.Update(tea.WindowSizeMsg{
-								Width:  m.width,
-								Height: m.height,
-							})
-							reviewModel = updatedModel.(ReviewModel)
-							m.reviewModel = &reviewModel
-							m.mode = ModeReview
+					cfg := config.Load()
+					opts := diff.Options{MaxLineBytes: cfg.ReviewMaxLineBytes, LazyFileLines: max(0, cfg.ReviewLazyFileLines)}
+					parsed, err := diff.GitDiff(gitRoot, opts)
+					ignored := 0
+					if err == nil {
+						ignored = parsed.Ignore(cfg.ReviewIgnoreFor(gitRoot))
+					}
+					if err == nil && !parsed.IsEmpty() {
+						sessionID := sel.ID
+						if sessionID == "" {
+							sessionID = sel.TmuxPane
 						}
+						reviewModel := NewReviewModel(parsed, sessionID, gitRoot)
+						reviewModel.ignored = ignored
+						reviewModel.targets = m.reviewTargets(*sel)
+						ft := cfg.FeedbackTemplate
+						reviewModel.feedbackTmpl = review.Template{Preamble: ft.Preamble, Comment: ft.Comment, Closing: ft.Closing}
+						reviewModel.editorURL = cfg.EditorURL
+						reviewModel.ignore = cfg.ReviewIgnoreFor(gitRoot)
+						reviewModel.diffOpts = opts
+						reviewModel.loadWanted()
+						if sel.State != session.StateWorking {
+							reviewModel.recordAgentReplies(sel.TranscriptPath)
+						}
+						updatedModel, _ := reviewModel.Update(tea.WindowSizeMsg{
+							Width:  m.width,
+							Height: m.height,
+						})
+						reviewModel = updatedModel.(ReviewModel)
+						m.reviewModel = &reviewModel
+						m.mode = ModeReview
 					}
 				}
 			}
 
 		case key.Matches(msg, keys.Filter):
 			m.mode = ModeFilter
-			m.filterInput.Focus()
+			m.filter = m.filter.Open()
 
 		case key.Matches(msg, keys.Rename):
+			var cmd tea.Cmd
+			m, cmd = m.openRename()
+			cmds = append(cmds, cmd)
+
+		case key.Matches(msg, keys.Model):
+			// Open Claude's model picker in the selected session and hand the
+			// keyboard over so the user can choose with the arrow keys.
+			if sel := m.selectedSession(); sel != nil && m.guardPane(sel.TmuxPane) == nil {
+				if err := m.tmuxClient.SendKeys(sel.TmuxPane, "/model"); err != nil {
+					m.err = err
+				} else {
+					m.audit(sel.TmuxPane, history.ActionKeys, "model picker", "/model")
+					m.insertMode = true
+					m.insertGroup, m.insertGroupName = "", ""
+					m.insertChecked = time.Now()
+				}
+			}
+
+		case key.Matches(msg, keys.Palette):
 			if sel := m.selectedSession(); sel != nil {
-				m.renameKey = sel.Key()
-				m.renameInput.SetValue(names.Get(m.renameKey))
-				m.renameInput.Focus()
-				m.mode = ModeRename
+				home, _ := os.UserHomeDir()
+				cmds := slash.Discover(sel.ProjectPath, filepath.Join(home, ".claude"))
+				pm := NewPaletteModel(cmds, m.width, m.height)
+				m.paletteModel = &pm
+				m.mode = ModePalette
+			}
+
+		case key.Matches(msg, keys.Actions):
+			m = m.openActions()
+
+		case key.Matches(msg, keys.SaveSnippet):
+			var cmd tea.Cmd
+			m, cmd = m.openSnippetCapture()
+			cmds = append(cmds, cmd)
+
+		case key.Matches(msg, keys.Snippets):
+			m = m.openSnippets()
+
+		case key.Matches(msg, keys.Snapshot):
+			cmds = append(cmds, m.takeSnapshot())
+
+		case key.Matches(msg, keys.Focus):
+			var cmd tea.Cmd
+			m, cmd = m.openFocusTimer()
+			cmds = append(cmds, cmd)
+
+		case key.Matches(msg, keys.Compose):
+			var cmd tea.Cmd
+			m, cmd = m.openCompose()
+			cmds = append(cmds, cmd)
+
+		case key.Matches(msg, keys.Files):
+			m = m.openFiles()
+
+		case key.Matches(msg, keys.Mark):
+			m.toggleMark()
+
+		case key.Matches(msg, keys.Broadcast):
+			var cmd tea.Cmd
+			m, cmd = m.openBroadcast()
+			cmds = append(cmds, cmd)
+
+		case key.Matches(msg, keys.Search):
+			var cmd tea.Cmd
+			m, cmd = m.openSearch()
+			cmds = append(cmds, cmd)
+
+		case key.Matches(msg, keys.ApprovePlan):
+			m.approvePlan()
+
+		case key.Matches(msg, keys.RejectPlan):
+			var cmd tea.Cmd
+			m, cmd = m.openPlanReject()
+			cmds = append(cmds, cmd)
+
+		case key.Matches(msg, keys.Activity):
+			var cmd tea.Cmd
+			m, cmd = m.openActivity()
+			cmds = append(cmds, cmd)
+
+		case key.Matches(msg, keys.Onboarding):
+			var cmd tea.Cmd
+			m, cmd = m.openOnboarding()
+			cmds = append(cmds, cmd)
+
+		case key.Matches(msg, keys.Stats):
+			var cmd tea.Cmd
+			m, cmd = m.openStats()
+			cmds = append(cmds, cmd)
+
+		case key.Matches(msg, keys.BlockedOn):
+			m = m.openBlockedOn()
+
+		case key.Matches(msg, keys.Queue):
+			var cmd tea.Cmd
+			m, cmd = m.openQueue()
+			cmds = append(cmds, cmd)
+
+		case key.Matches(msg, keys.Merge):
+			var cmd tea.Cmd
+			m, cmd = m.openMerge()
+			cmds = append(cmds, cmd)
+
+		case key.Matches(msg, keys.GroupInsert):
+			m = m.openGroupInsert()
+
+		case key.Matches(msg, keys.Tickets):
+			var cmd tea.Cmd
+			m, cmd = m.openTickets()
+			cmds = append(cmds, cmd)
+
+		case key.Matches(msg, keys.Squads):
+			m = m.openSquads()
+
+		case key.Matches(msg, keys.Menu):
+			if m.selectedSession() != nil {
+				var cmd tea.Cmd
+				m, cmd = m.openMenu(m.selected)
+				cmds = append(cmds, cmd)
+			}
+
+		case key.Matches(msg, keys.BulkEdit):
+			if len(m.sessions) > 0 {
+				var cmd tea.Cmd
+				m, cmd = m.openBulkEdit()
+				cmds = append(cmds, cmd)
 			}
 
 		case key.Matches(msg, keys.ToggleGroup):
@@ -573,13 +1041,9 @@ func (m Model) updateNormal(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case key.Matches(msg, keys.SetGroup):
 			if m.cursorOnGroup == "" {
-				if sel := m.selectedSession(); sel != nil {
-					m.groupSetKey = sel.Key()
-					current := groups.Get(m.groupSetKey)
-					m.groupSetInput.SetValue(current)
-					m.groupSetInput.Focus()
-					m.mode = ModeGroupSet
-				}
+				var cmd tea.Cmd
+				m, cmd = m.openGroupSet()
+				cmds = append(cmds, cmd)
 			}
 
 		case key.Matches(msg, keys.Pin):
@@ -640,6 +1104,12 @@ func (m Model) updateNormal(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.saveSidebarState()
 				m.itemsDirty = true
 			}
+
+		default:
+			// Keys no built-in binding claims may belong to a plugin action.
+			if p, a, ok := plugin.ActionForKey(m.plugins, msg.String()); ok {
+				cmds = append(cmds, m.runPluginAction(p, a))
+			}
 		}
 
 	// ── Mouse ──────────────────────────────────────────────────────────────
@@ -649,8 +1119,31 @@ func (m Model) updateNormal(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.viewport.ScrollUp(3)
 		case tea.MouseButtonWheelDown:
 			m.viewport.ScrollDown(3)
+		case tea.MouseButtonRight:
+			if msg.Action == tea.MouseActionPress && msg.X < sessionPaneWidth {
+				if idx, _ := m.sessionIndexAtY(msg.Y); idx >= 0 && idx < len(m.sessions) {
+					var cmd tea.Cmd
+					m, cmd = m.openMenu(idx)
+					cmds = append(cmds, cmd)
+				}
+			}
 		case tea.MouseButtonLeft:
-			if msg.X < sessionPaneWidth {
+			if msg.Action != tea.MouseActionPress {
+				break
+			}
+			if msg.Y == 0 {
+				// A state pill in the header filters to that state.
+				if st, ok := m.statePillAt(msg.X); ok {
+					m.toggleStateFilter(st)
+				}
+			} else if msg.Y == 1 && msg.X > sessionPaneWidth {
+				// The output header jumps to the pane, as t does.
+				if sel := m.selectedSession(); sel != nil {
+					if err := m.tmuxClient.SwitchToPane(sel.TmuxPane); err != nil {
+						m.err = err
+					}
+				}
+			} else if msg.X < sessionPaneWidth {
 				idx, groupKey := m.sessionIndexAtY(msg.Y)
 				if groupKey != "" {
 					// Clicked a group header — toggle collapse
@@ -710,10 +1203,11 @@ func (m *Model) openOrSwitchWorktree(wtPath string) tea.Cmd {
 }
 
 // removeWorktree is a Cmd that kills the associated session (if any) then removes the worktree.
-func removeWorktree(client tmux.ClientIface, repoRoot, wtPath, sessionPane string) tea.Cmd {
+// logKill records the kill in the history log.
+func removeWorktree(client tmux.ClientIface, repoRoot, wtPath, sessionPane string, logKill func()) tea.Cmd {
 	return func() tea.Msg {
-		if sessionPane != "" {
-			_ = client.KillPane(sessionPane)
+		if sessionPane != "" && client.KillPane(sessionPane) == nil {
+			logKill()
 		}
 		if err := git.RemoveWorktree(repoRoot, wtPath); err != nil {
 			return errMsg{err}
@@ -722,17 +1216,26 @@ func removeWorktree(client tmux.ClientIface, repoRoot, wtPath, sessionPane strin
 	}
 }
 
-// createAndLaunchWorktree is a Cmd that creates the worktree then launches Claude.
-func createAndLaunchWorktree(client tmux.ClientIface, repoRoot, path, branch string) tea.Cmd {
+// createAndLaunchWorktree is a Cmd that creates the worktree (a new branch
+// starts from base, or HEAD when empty), copies in the
+// configured untracked files from mainPath, launches Claude and starts any
+// post-create command in a pane below it.
+func createAndLaunchWorktree(client tmux.ClientIface, repoRoot, path, branch, base, mainPath string, setup config.WorktreeSetup) tea.Cmd {
 	return func() tea.Msg {
-		if err := git.AddWorktree(repoRoot, path, branch); err != nil {
+		if err := git.AddWorktree(repoRoot, path, branch, base); err != nil {
 			return errMsg{err}
 		}
+		copied, setupErr := git.CopyUntracked(mainPath, path, setup.Copy)
 		paneID, err := LaunchSession(path, client)
 		if err != nil {
 			return errMsg{err}
 		}
-		return worktreeLaunchedMsg(paneID)
+		if setup.PostCreate != "" {
+			if _, err := client.SplitWindow(paneID, path, setup.PostCreate); err != nil && setupErr == nil {
+				setupErr = err
+			}
+		}
+		return worktreeCreatedMsg{pane: paneID, path: path, branch: branch, copied: len(copied), setupErr: setupErr}
 	}
 }
 
@@ -784,7 +1287,8 @@ var tmuxKeyNames = map[string]string{
 }
 
 // forwardKey sends a single key event to the given tmux pane.
-// ctrl+h is the exit-insert-mode key and is never forwarded.
+// ctrl+h is the exit-insert-mode key and is never forwarded. A paste goes
+// in one go rather than a send-keys per character.
 func (m Model) forwardKey(paneID string, msg tea.KeyMsg) error {
 	if msg.String() == "ctrl+h" {
 		return nil // exit key — handled by caller
@@ -796,6 +1300,9 @@ func (m Model) forwardKey(paneID string, msg tea.KeyMsg) error {
 		if len(msg.Runes) == 0 {
 			return nil
 		}
+		if msg.Paste {
+			return m.tmuxClient.PasteText(paneID, string(msg.Runes))
+		}
 		return m.tmuxClient.SendLiteral(paneID, string(msg.Runes))
 	}
 	return nil
@@ -816,59 +1323,84 @@ func (m Model) selectSession() (Model, tea.Cmd) {
 
 // ── Helpers ────────────────────────────────────────────────────────────────
 
+// paneSharedMsg reports whether a pane is zoomed or on another client's
+// screen, where herd leaves its size alone.
+type paneSharedMsg struct {
+	paneID string
+	shared bool
+}
+
 // resizePaneToViewport resizes the tmux window containing paneID to width×height
 // so that the observed session formats its output to fit the herd viewport.
-// This is a fire-and-forget async command; errors are silently ignored.
+// A shared pane (see tmux.PaneShared) is left as it is so the resize doesn't
+// fight its other viewer. This is an async command; resize errors are
+// silently ignored.
 func (m Model) resizePaneToViewport(paneID string, width, height int) tea.Cmd {
 	if paneID == "" || width <= 0 || height <= 0 {
 		return nil
 	}
+	// Only a change of size is recorded, not every refit to the same one.
+	logged := func() {}
+	if size := fmt.Sprintf("%dx%d", width, height); m.sizedTo != nil && m.sizedTo[paneID] != size {
+		m.sizedTo[paneID] = size
+		m.startSettle(paneID)
+		logged = m.auditCmd(paneID, history.ActionResize, "viewport", size)
+	}
 	client := m.tmuxClient
 	return func() tea.Msg {
-		_ = client.ResizeWindow(paneID, width, height)
-		return nil
+		if shared, err := client.PaneShared(paneID); err == nil && shared {
+			return paneSharedMsg{paneID: paneID, shared: true}
+		}
+		if client.ResizeWindow(paneID, width, height) == nil {
+			logged()
+		}
+		return paneSharedMsg{paneID: paneID}
 	}
 }
 
-func (m Model) fetchCapture(paneID string) tea.Cmd {
+// checkPaneShared re-checks whether paneID is shared, as other clients come
+// and go.
+func (m Model) checkPaneShared(paneID string) tea.Cmd {
 	client := m.tmuxClient
 	return func() tea.Msg {
-		content, err := client.CapturePane(paneID, 2000)
+		shared, err := client.PaneShared(paneID)
 		if err != nil {
 			return nil
 		}
-		return captureMsg{paneID: paneID, content: content}
+		return paneSharedMsg{paneID: paneID, shared: shared}
 	}
 }
 
-func (m Model) applyStates(states []state.SessionState) Model {
-	byPane := make(map[string]state.SessionState)
-	byID := make(map[string]state.SessionState)
-	for _, s := range states {
-		if s.TmuxPane != "" {
-			byPane[s.TmuxPane] = s
-		}
-		if s.SessionID != "" {
-			byID[s.SessionID] = s
+// captureFor returns the capture settings for s.
+func (m Model) captureFor(s session.Session) config.Capture {
+	return config.Config{Capture: m.capture}.CaptureFor(s.ProjectPath, names.Get(s.Key()))
+}
+
+func (m Model) fetchCapture(paneID string) tea.Cmd {
+	client := m.tmuxClient
+	scrollback := config.Config{}.CaptureFor("", "").Scrollback
+	for _, s := range m.sessions {
+		if s.TmuxPane == paneID {
+			scrollback = m.captureFor(s).Scrollback
+			break
 		}
 	}
-	for i, sess := range m.sessions {
-		var st state.SessionState
-		var found bool
-		if sess.ID != "" {
-			st, found = byID[sess.ID]
-		}
-		if !found {
-			st, found = byPane[sess.TmuxPane]
+	return func() tea.Msg {
+		// A full-screen program's screen comes out garbled, so show where
+		// to find it instead.
+		if alt, err := client.AlternateOn(paneID); err == nil && alt {
+			return captureMsg{paneID: paneID, content: altScreenPlaceholder(paneID)}
 		}
-		if !found {
-			continue
+		content, err := client.CapturePane(paneID, scrollback)
+		if err != nil {
+			return nil
 		}
-		m.sessions[i].ID = st.SessionID
-		m.sessions[i].State = session.ParseState(st.State)
-		m.sessions[i].CurrentTool = st.CurrentTool
-		m.sessions[i].UpdatedAt = st.UpdatedAt
+		return captureMsg{paneID: paneID, content: content}
 	}
+}
+
+func (m Model) applyStates(states []state.SessionState) Model {
+	session.ApplyStates(m.sessions, states)
 	return m
 }
 
@@ -879,6 +1411,9 @@ func (m Model) recalcLayout() Model {
 
 	vpWidth := m.width - sessionPaneWidth - 1
 	vpHeight := m.height - headerH - outputHeaderH - helpH
+	if m.testPanelOpen {
+		vpHeight -= testPanelHeight
+	}
 
 	if vpWidth < 10 {
 		vpWidth = 10
@@ -977,9 +1512,23 @@ func (m *Model) updateFilter() {
 
 	query := strings.ToLower(m.filterQuery)
 	m.filtered = nil
+	m.filterHits = make(map[string]string)
 
 	for i, s := range m.sessions {
-		// Match against project path, git branch, pane ID, and session ID
+		// "state:<state>" keeps sessions in that state; anything else is
+		// matched against the labels given to the session, then its project
+		// path, git branch, pane ID, and session ID.
+		if st, ok := strings.CutPrefix(query, "state:"); ok {
+			if s.State.String() == st {
+				m.filtered = append(m.filtered, i)
+			}
+			continue
+		}
+		if hit := m.labelMatch(s, query); hit != "" {
+			m.filtered = append(m.filtered, i)
+			m.filterHits[s.TmuxPane] = hit
+			continue
+		}
 		searchable := strings.ToLower(s.ProjectPath + " " + s.GitBranch + " " + s.TmuxPane + " " + s.ID)
 		if strings.Contains(searchable, query) {
 			m.filtered = append(m.filtered, i)
@@ -1001,6 +1550,36 @@ func (m *Model) updateFilter() {
 	}
 }
 
+// labelMatch returns which of the labels given to s — its custom name,
+// team member name, custom group or team — contains query, or "".
+func (m *Model) labelMatch(s session.Session, query string) string {
+	labels := []struct{ source, text string }{
+		{i18n.T("name"), names.Get(s.Key())},
+		{i18n.T("member"), m.teamsStore.MemberNameForSession(s.TmuxPane, s.ID)},
+		{i18n.T("group"), groups.Get(s.Key())},
+		{i18n.T("team"), m.teamsStore.TeamForSession(s.TmuxPane, s.ID)},
+	}
+	for _, l := range labels {
+		if l.text != "" && strings.Contains(strings.ToLower(l.text), query) {
+			return l.source
+		}
+	}
+	return ""
+}
+
+// toggleStateFilter filters the list to sessions in st, or clears the
+// filter if it already does.
+func (m *Model) toggleStateFilter(st session.State) {
+	query := "state:" + st.String()
+	if m.filterQuery == query {
+		query = ""
+	}
+	m.filterQuery = query
+	m.filter = m.filter.SetQuery(query)
+	m.updateFilter()
+	m.itemsDirty = true
+}
+
 // filteredSessions returns the sessions that match the current filter.
 // If no filter is active, returns all sessions.
 func (m *Model) filteredSessions() []session.Session {
@@ -1029,3 +1608,10 @@ func maxInt(a, b int) int {
 	}
 	return b
 }
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -3,22 +3,25 @@ package tui
 import (
 	"fmt"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/x/ansi"
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/shnupta/herd/internal/i18n"
 	"github.com/shnupta/herd/internal/names"
 	"github.com/shnupta/herd/internal/session"
 )
 
-func (m Model) View() string {
+// render draws the screen for the current mode.
+func (m Model) render() string {
 	if !m.ready {
 		return "initialising..."
 	}
 	if m.err != nil {
-		return fmt.Sprintf("error: %v\n\nPress q to quit.", m.err)
+		return i18n.Tf("error: %v\n\nPress q to quit.", m.err)
 	}
 
 	// If in review mode, show the review UI
@@ -36,14 +39,100 @@ func (m Model) View() string {
 		return m.pickerModel.View()
 	}
 
-	// If in rename mode, show the rename overlay
-	if m.mode == ModeRename {
-		return m.renderRenameOverlay()
+	// If in palette mode, show the slash-command palette
+	if m.mode == ModePalette && m.paletteModel != nil {
+		return m.paletteModel.View()
 	}
 
-	// If in group-set mode, show the group overlay
-	if m.mode == ModeGroupSet {
-		return m.renderGroupSetOverlay()
+	// If in actions mode, show the quick actions overlay
+	if m.mode == ModeActions {
+		return m.renderActionsOverlay()
+	}
+
+	// If in tickets mode, show the ticket picker
+	if m.mode == ModeTickets {
+		return m.renderTicketsOverlay()
+	}
+
+	// If in menu mode, show the session context menu
+	if m.mode == ModeMenu {
+		return m.renderMenuOverlay()
+	}
+
+	// If in teardown mode, show the worktree teardown
+	if m.mode == ModeTeardown && m.teardown != nil {
+		return m.renderTeardownOverlay()
+	}
+
+	// If in squads mode, show the squad picker
+	if m.mode == ModeSquads {
+		return m.renderSquadsOverlay()
+	}
+
+	// If a prompt is open full-screen, show it; the plan feedback prompt
+	// is a bar drawn by renderHelp
+	if m.overlay != nil && m.mode != ModePlanReject {
+		return m.overlay.View(m.width, m.height)
+	}
+
+	// If in snippets mode, show the snippets panel
+	if m.mode == ModeSnippets {
+		return m.renderSnippetsOverlay()
+	}
+
+	// If in stats mode, show working time per project
+	if m.mode == ModeStats {
+		return m.renderStatsOverlay()
+	}
+
+	// If in blocked-on mode, show the blocker picker
+	if m.mode == ModeBlockedOn {
+		return m.renderBlockedOnOverlay()
+	}
+
+	// If in queue mode, show the session at the head of the attention queue
+	if m.mode == ModeQueue {
+		return m.renderQueueView()
+	}
+
+	// If in merge mode, show the group's interleaved output
+	if m.mode == ModeMerge {
+		return m.renderMergeView()
+	}
+
+	// If in plugin-panel mode, show the plugin's output
+	if m.mode == ModePluginPanel {
+		return m.renderPluginPanel()
+	}
+
+	// If in compose mode, show the prompt and its attachments
+	if m.mode == ModeCompose {
+		return m.renderComposeOverlay()
+	}
+
+	// If in activity mode, show the session's timeline
+	if m.mode == ModeActivity {
+		return m.renderActivityPanel()
+	}
+
+	// If onboarding, show the setup milestones
+	if m.mode == ModeOnboarding {
+		return m.renderOnboarding()
+	}
+
+	// If in broadcast mode, show the prompt and the sessions it goes to
+	if m.mode == ModeBroadcast {
+		return m.renderBroadcastOverlay()
+	}
+
+	// If in files mode, show the project's file tree
+	if m.mode == ModeFiles && m.files != nil {
+		return m.renderFilesOverlay()
+	}
+
+	// If in bulk-edit mode, show the editable session buffer
+	if m.mode == ModeBulkEdit {
+		return m.renderBulkEditOverlay()
 	}
 
 	// No sessions — show landing page with the normal header/help chrome.
@@ -58,13 +147,19 @@ func (m Model) View() string {
 	header := m.renderHeader()
 	outputHeader := m.renderOutputHeader()
 
-	sessionList := m.renderSessionList()
+	sessionList := m.withHub(m.renderSessionList(), m.height-2)
+	if m.tmuxLost != nil {
+		sessionList = greyOut(sessionList)
+	}
 	sessionPane := styleSessionPane.
 		Width(sessionPaneWidth).
 		Height(m.height - 2). // total - header(1) - help(1)
 		Render(sessionList)
 
 	viewportContent := m.viewport.View()
+	if sel := m.selectedSession(); sel != nil {
+		viewportContent = m.withEcho(sel.TmuxPane, viewportContent, m.viewport.Width)
+	}
 	outputPane := lipgloss.NewStyle().
 		Width(m.width - sessionPaneWidth - 1).
 		Height(m.viewport.Height).
@@ -75,6 +170,9 @@ func (m Model) View() string {
 		Render(outputHeader)
 
 	rightCol := lipgloss.JoinVertical(lipgloss.Left, outputHeader, outputPane)
+	if m.testPanelOpen {
+		rightCol = lipgloss.JoinVertical(lipgloss.Left, rightCol, m.renderTestPanel())
+	}
 	middle := lipgloss.JoinHorizontal(lipgloss.Top, sessionPane, rightCol)
 
 	help := m.renderHelp()
@@ -117,6 +215,9 @@ func (m Model) renderHeader() string {
 	}
 
 	right := m.aggregateStats() + fill(1) // trailing padding
+	if m.tmuxLost != nil {
+		right = m.renderTmuxLost() + fill(1)
+	}
 
 	gap := m.width - lipgloss.Width(left) - lipgloss.Width(right)
 	return left + fill(gap) + right
@@ -142,26 +243,75 @@ func (m Model) aggregateStats() string {
 	}
 
 	var parts []string
-	if n := counts[session.StateWorking]; n > 0 {
-		parts = append(parts, pill(colGreen, fmt.Sprintf("● %d working", n)))
+	for _, p := range statePills(counts) {
+		parts = append(parts, pill(p.colour, p.text))
 	}
-	if n := counts[session.StateWaiting]; n > 0 {
-		parts = append(parts, pill(colBlue, fmt.Sprintf("◉ %d waiting", n)))
+	if len(parts) == 0 {
+		return lipgloss.NewStyle().Background(colAccent).Foreground(colSubtext).Render(fmt.Sprintf("%d sessions", len(m.sessions)))
 	}
-	if n := counts[session.StatePlanReady]; n > 0 {
-		parts = append(parts, pill(colAmber, fmt.Sprintf("◆ %d plan", n)))
+	sep := lipgloss.NewStyle().Background(colAccent).Foreground(lipgloss.Color("#C4B5FD")).Render(pillSep)
+	return strings.Join(parts, sep)
+}
+
+// pillSep separates the state pills in the header.
+const pillSep = "  ·  "
+
+// statePill is one state's count in the header.
+type statePill struct {
+	state  session.State
+	colour lipgloss.Color
+	text   string
+}
+
+// statePills returns the header pills for the states with sessions, in
+// display order.
+func statePills(counts map[session.State]int) []statePill {
+	var pills []statePill
+	add := func(st session.State, colour lipgloss.Color, format string) {
+		if n := counts[st]; n > 0 {
+			pills = append(pills, statePill{st, colour, fmt.Sprintf(format, n)})
+		}
 	}
-	if n := counts[session.StateNotifying]; n > 0 {
-		parts = append(parts, pill(colPurple, fmt.Sprintf("◈ %d notify", n)))
+	add(session.StateWorking, colGreen, "● %d working")
+	add(session.StateWaiting, colBlue, "◉ %d waiting")
+	add(session.StatePlanReady, colAmber, "◆ %d plan")
+	add(session.StateNotifying, colPurple, "◈ %d notify")
+	add(session.StateIdle, colCyan, "○ %d idle")
+	return pills
+}
+
+// statePillAt returns the state whose header pill covers column x.
+func (m Model) statePillAt(x int) (session.State, bool) {
+	counts := make(map[session.State]int)
+	for _, s := range m.sessions {
+		counts[s.State]++
 	}
-	if n := counts[session.StateIdle]; n > 0 {
-		parts = append(parts, pill(colCyan, fmt.Sprintf("○ %d idle", n)))
+	pills := statePills(counts)
+	// The pills are right-aligned, before one column of padding.
+	width := 0
+	for i, p := range pills {
+		if i > 0 {
+			width += lipgloss.Width(pillSep)
+		}
+		width += lipgloss.Width(p.text)
 	}
-	if len(parts) == 0 {
-		return lipgloss.NewStyle().Background(colAccent).Foreground(colSubtext).Render(fmt.Sprintf("%d sessions", len(m.sessions)))
+	col := m.width - 1 - width
+	for _, p := range pills {
+		w := lipgloss.Width(p.text)
+		if x >= col && x < col+w {
+			return p.state, true
+		}
+		col += w + lipgloss.Width(pillSep)
 	}
-	sep := lipgloss.NewStyle().Background(colAccent).Foreground(lipgloss.Color("#C4B5FD")).Render("  ·  ")
-	return strings.Join(parts, sep)
+	return 0, false
+}
+
+// altScreenPlaceholder stands in for the output of a pane running a
+// full-screen program.
+func altScreenPlaceholder(paneID string) string {
+	title := lipgloss.NewStyle().Foreground(colAmber).Bold(true).Render("▣ " + paneID + " is running a full-screen program")
+	hint := lipgloss.NewStyle().Foreground(colSubtext).Render("vim, less and the like can't be mirrored here — press t to jump to the pane.")
+	return "\n  " + title + "\n\n  " + hint
 }
 
 func (m Model) renderOutputHeader() string {
@@ -175,11 +325,30 @@ func (m Model) renderOutputHeader() string {
 
 	paneStyle := lipgloss.NewStyle().Foreground(colSubtle)
 	left := " " + icon + " " + label + "  " + paneStyle.Render(sel.TmuxPane)
+	if badge := permissionBadge(sel.PermissionMode); badge != "" {
+		left += "  " + badge
+	}
+	if model := shortModelName(sel.Model); model != "" {
+		left += "  " + paneStyle.Render(model)
+	}
+	if sel.TmuxPane == m.sharedPane {
+		left += "  " + lipgloss.NewStyle().Foreground(colAmber).Render("view only (shared)")
+	}
+	if pct := sel.ContextPercent(); pct > 0 {
+		ctx := fmt.Sprintf("ctx %d%%", pct)
+		if pct >= contextWarnPercent {
+			ctx += " — nearing auto-compact"
+		}
+		left += "  " + contextGauge(pct) + " " + paneStyle.Render(ctx)
+	}
 
-	right := ""
+	right := m.focusRemaining(sel.Key(), time.Now())
 	if !m.viewport.AtBottom() {
 		pct := int(m.viewport.ScrollPercent() * 100)
-		right = lipgloss.NewStyle().Foreground(colSubtle).Render(fmt.Sprintf("%d%%", pct))
+		if right != "" {
+			right += "  "
+		}
+		right += lipgloss.NewStyle().Foreground(colSubtle).Render(fmt.Sprintf("%d%%", pct))
 	}
 
 	available := m.width - sessionPaneWidth - 1
@@ -197,7 +366,7 @@ func (m Model) renderSessionList() string {
 	// Filter mode: flat list with no tree decoration.
 	if m.mode == ModeFilter || m.isFiltered() {
 		if m.mode == ModeFilter {
-			sb.WriteString(styleFilter.Render("/" + m.filterInput.Value() + "▎") + "\n")
+			sb.WriteString(m.filter.View() + "\n")
 		} else {
 			sb.WriteString(styleFilter.Render("/" + m.filterQuery) + "\n")
 		}
@@ -224,7 +393,7 @@ func (m Model) renderSessionList() string {
 	// Tree view.
 	items := m.viewItems()
 	if len(items) == 0 {
-		sb.WriteString(styleSessionMeta.Render("no claude sessions\nfound in tmux"))
+		sb.WriteString(styleSessionMeta.Render(i18n.T("no claude sessions\nfound in tmux")))
 		return sb.String()
 	}
 
@@ -252,20 +421,25 @@ func (m Model) renderSessionList() string {
 	return strings.TrimSuffix(sb.String(), "\n")
 }
 
-func (m Model) renderSessionItem(i int, s session.Session, groupKey string, inGroup, isLastChild bool) string {
-	icon := stateIcon(s.State.String())
-	name := names.Get(s.Key())
-	if name == "" {
-		if agentName := m.teamsStore.MemberNameForSession(s.TmuxPane, s.ID); agentName != "" {
-			name = "@" + agentName
-		}
+// displayName is the session's label in the sidebar: its custom name, then
+// its agent-team member name, then its project directory.
+func (m Model) displayName(s session.Session) string {
+	if name := names.Get(s.Key()); name != "" {
+		return name
 	}
-	if name == "" {
-		name = filepath.Base(s.ProjectPath)
-		if name == "." || name == "" {
-			name = s.TmuxPane
-		}
+	if agentName := m.teamsStore.MemberNameForSession(s.TmuxPane, s.ID); agentName != "" {
+		return "@" + agentName
 	}
+	name := filepath.Base(s.ProjectPath)
+	if name == "." || name == "" {
+		name = s.TmuxPane
+	}
+	return name
+}
+
+func (m Model) renderSessionItem(i int, s session.Session, groupKey string, inGroup, isLastChild bool) string {
+	icon := stateIcon(s.State.String())
+	name := m.displayName(s)
 
 	selected := i == m.selected
 
@@ -309,8 +483,49 @@ func (m Model) renderSessionItem(i int, s session.Session, groupKey string, inGr
 		metaStyle = styleSessionMeta.Background(bg).Width(innerW)
 	}
 
-	nameLine := connector + nameStyle.Render(pinIndicator+icon+" "+name)
-	metaLine := metaPrefix + metaStyle.Render(sessionMeta(s))
+	label := pinIndicator + icon + " " + name
+	if m.marked[s.Key()] {
+		label = lipgloss.NewStyle().Foreground(colBlue).Render("◆") + " " + label
+	}
+	// The timeline sits at the right of the name line, the name giving way.
+	timeline := renderTimeline(m.timelines[s.ID])
+	if acts := renderActs(m.timelineActs[s.ID]); acts != "" {
+		timeline = strings.TrimPrefix(timeline+" "+acts, " ")
+	}
+	if timeline != "" {
+		labelW := innerW - nameStyle.GetHorizontalPadding() - lipgloss.Width(timeline) - 1
+		label = ansi.Truncate(label, maxInt(1, labelW), "…")
+		label += strings.Repeat(" ", maxInt(1, labelW-lipgloss.Width(label)+1)) + timeline
+	}
+	nameLine := connector + nameStyle.Render(label)
+	// Badges are appended after the state text, which is truncated to make
+	// room so the meta line never wraps onto a third row.
+	var extras string
+	if blocker := m.blockedOnName(s); blocker != "" {
+		extras += " " + lipgloss.NewStyle().Foreground(colAmber).Render("⧗"+blocker)
+	}
+	if hit := m.filterHits[s.TmuxPane]; hit != "" && m.isFiltered() {
+		extras += " " + lipgloss.NewStyle().Foreground(colBlue).Render("⌕"+hit)
+	}
+	if badge := m.testBadge(s.Key()); badge != "" {
+		extras += " " + badge
+	}
+	if p := m.renderProgress(s); p != "" {
+		extras += " " + p
+	}
+	if badges := m.renderCustomBadges(s.Key()); badges != "" {
+		extras += " " + badges
+	}
+	if badge := permissionBadge(s.PermissionMode); badge != "" {
+		extras += " " + badge
+	}
+	if gauge := contextGauge(s.ContextPercent()); gauge != "" {
+		extras += " " + gauge
+	}
+	extras = ansi.Truncate(extras, innerW-styleSessionMeta.GetPaddingLeft()-2, "")
+	metaW := innerW - styleSessionMeta.GetPaddingLeft() - lipgloss.Width(extras)
+	meta := ansi.Truncate(sessionMeta(s), maxInt(1, metaW), "…") + extras
+	metaLine := metaPrefix + metaStyle.Render(meta)
 
 	return nameLine + "\n" + metaLine
 }
@@ -350,6 +565,18 @@ func (m Model) renderGroupHeader(item viewItem, selected bool) string {
 	return arrow + style.Render(label)
 }
 
+// shortModelName trims a model ID such as "claude-sonnet-4-5-20250929" to
+// "sonnet-4-5" for the output header.
+func shortModelName(model string) string {
+	model = strings.TrimPrefix(model, "claude-")
+	if i := strings.LastIndex(model, "-"); i >= 0 && len(model)-i-1 == 8 {
+		if _, err := strconv.Atoi(model[i+1:]); err == nil {
+			model = model[:i]
+		}
+	}
+	return model
+}
+
 func sessionMeta(s session.Session) string {
 	switch s.State {
 	case session.StateWorking:
@@ -358,10 +585,19 @@ func sessionMeta(s session.Session) string {
 		}
 		return "working  ⟳"
 	case session.StateWaiting:
+		if s.Prompt != "" {
+			return "waiting: " + s.Prompt
+		}
 		return "waiting for input"
 	case session.StatePlanReady:
+		if s.Prompt != "" {
+			return "plan ready: " + s.Prompt
+		}
 		return "plan ready"
 	case session.StateNotifying:
+		if s.Message != "" {
+			return s.Message
+		}
 		return "notification"
 	case session.StateIdle:
 		if !s.UpdatedAt.IsZero() {
@@ -391,10 +627,13 @@ func (m Model) renderLandingPage() string {
 	body := lipgloss.JoinVertical(lipgloss.Center,
 		titleStyle.Render("herd"),
 		"",
-		subtextStyle.Render("no Claude sessions found in tmux"),
+		subtextStyle.Render(i18n.T("no Claude sessions found in tmux")),
 		"",
-		hintStyle.Render("open Claude Code in a tmux pane to get started"),
+		hintStyle.Render(i18n.T("open Claude Code in a tmux pane to get started")),
 	)
+	if hub := m.renderHub(m.height - 2 - lipgloss.Height(body) - 1); hub != "" {
+		body = lipgloss.JoinVertical(lipgloss.Center, body, "", hub)
+	}
 
 	page := lipgloss.NewStyle().
 		Width(m.width).
@@ -405,44 +644,77 @@ func (m Model) renderLandingPage() string {
 	return page
 }
 
-func (m Model) renderRenameOverlay() string {
-	var sb strings.Builder
-	sb.WriteString(styleOverlayTitle.Width(m.width).Render("Rename Session") + "\n\n")
-	sb.WriteString(styleOverlayInput.Render(m.renameInput.View()) + "\n\n")
-	sb.WriteString(styleOverlayHelp.Render("[enter] save  [esc] cancel  (empty to clear name)"))
-	return sb.String()
-}
-
-func (m Model) renderGroupSetOverlay() string {
-	var sb strings.Builder
-	sb.WriteString(styleOverlayTitle.Width(m.width).Render("Set Group") + "\n\n")
-	sb.WriteString(styleOverlayInput.Render(m.groupSetInput.View()) + "\n\n")
-	sb.WriteString(styleOverlayHelp.Render("[enter] save  [esc] cancel  (empty to use auto-detected group)"))
-	return sb.String()
-}
-
 func (m Model) renderHelp() string {
+	if m.insertMode && m.insertGroup != "" {
+		return m.renderGroupInsertBar()
+	}
 	if m.insertMode {
-		return styleHelpInsert.Width(m.width).Render("  INSERT  [ctrl+h] exit")
+		return styleHelpInsert.Width(m.width).Render("  " + i18n.T("INSERT  [ctrl+h] exit"))
 	}
 	if m.mode == ModeFilter {
-		return styleHelpFilter.Width(m.width).Render("  FILTER  [enter] apply  [esc] clear")
+		return styleHelpFilter.Width(m.width).Render("  " + i18n.T("FILTER  [enter] apply  [esc] clear"))
+	}
+	if m.mode == ModeSearch && m.search != nil {
+		return m.renderSearchBar()
+	}
+	if m.mode == ModePlanReject && m.overlay != nil {
+		return m.overlay.View(m.width, 1)
 	}
 	parts := []string{
 		"[j/k] nav",
+		"[enter] menu",
 		"[J/K] move",
 		"[p] pin",
 		"[e] rename",
+		"[E] bulk edit",
 		"[space] collapse",
 		"[g] group",
+		"[B] blocked on",
+		"[m] merge",
+		"[G] group insert",
 		"[/] filter",
+		"[?] search",
 		"[i] insert",
+		"[y/Y] plan",
+		"[a] attention",
+		"[:] commands",
+		"[.] actions",
+		"[b] tickets",
+		"[Q] squads",
 		"[t] jump",
 		"[d] diff",
+		"[c/P] snippets",
+		"[H] snapshot",
+		"[L] activity",
+		"[O] getting started",
+		"[f] focus",
+		"[A] attach",
+		"[F] files",
+		"[v/V] broadcast",
 		"[n] new",
 		"[x] kill",
+		"[X] kill + clean up",
+	}
+	for i, p := range parts {
+		if p == "[i] insert" && m.insertComposer {
+			p = "[i] compose"
+		}
+		parts[i] = i18n.T(p)
+	}
+	if m.showFrameStats {
+		parts = append([]string{m.frameStats()}, parts...)
+	}
+	hint := m.stateHint()
+	if hint != "" {
+		hint = m.renderStateHint(ansi.Truncate(hint, max(0, m.width-2), "…"))
+	}
+	// The layout leaves the help one line; keys that don't fit are cut.
+	w := m.width - lipgloss.Width(hint)
+	if w <= styleHelp.GetHorizontalPadding() {
+		return hint
 	}
-	return styleHelp.Width(m.width).Render(strings.Join(parts, "  "))
+	help := ansi.Truncate(strings.Join(parts, "  "), max(0, w-styleHelp.GetHorizontalPadding()), "…")
+	return hint + styleHelp.Width(w).Render(help)
 }
 
 // fmtDuration formats a duration as a short human string, e.g. "2m" or "45s".
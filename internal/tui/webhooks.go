@@ -0,0 +1,51 @@
+package tui
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/shnupta/herd/internal/config"
+	"github.com/shnupta/herd/internal/names"
+	"github.com/shnupta/herd/internal/session"
+	"github.com/shnupta/herd/internal/webhook"
+)
+
+// webhooksDoneMsg reports failed webhook deliveries.
+type webhooksDoneMsg struct{ errs []error }
+
+// reviewWebhooks posts a submitted review of s to the webhooks that want it,
+// off the UI goroutine. It is a no-op when none do.
+func reviewWebhooks(s session.Session, rm ReviewModel) tea.Cmd {
+	name := webhook.ReviewSubmitted
+	if rm.Approved() {
+		name = webhook.ReviewApproved
+	}
+	hooks := config.Load().WebhooksFor(s.ProjectPath, name)
+	if len(hooks) == 0 {
+		return nil
+	}
+	r := rm.Review()
+	ev := webhook.Event{
+		Event: name,
+		Time:  time.Now(),
+		Session: webhook.Session{
+			Key:     s.Key(),
+			Pane:    s.TmuxPane,
+			Name:    names.Get(s.Key()),
+			Project: s.ProjectPath,
+			Branch:  s.GitBranch,
+		},
+		Review: &webhook.Review{
+			Approved: rm.Approved(),
+			Comments: len(r.Comments),
+			Outdated: rm.outdatedComments(),
+			Severity: r.SeveritySummary(),
+			Feedback: rm.FeedbackText(),
+		},
+	}
+	return func() tea.Msg {
+		return webhooksDoneMsg{errs: webhook.Send(context.Background(), hooks, ev)}
+	}
+}
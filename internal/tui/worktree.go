@@ -4,16 +4,42 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/shnupta/herd/internal/config"
 	"github.com/shnupta/herd/internal/git"
+	"github.com/shnupta/herd/internal/i18n"
 	"github.com/shnupta/herd/internal/session"
 )
 
+// worktreeUsageMsg carries one worktree's measured size and last commit.
+type worktreeUsageMsg struct {
+	path       string
+	size       int64
+	lastCommit time.Time
+}
+
+func isWorktreeUsageMsg(msg tea.Msg) bool { _, ok := msg.(worktreeUsageMsg); return ok }
+
+// measureWorktrees sizes each worktree in the background, one message per
+// worktree so large ones don't hold up the rest.
+func measureWorktrees(worktrees []git.Worktree) tea.Cmd {
+	var cmds []tea.Cmd
+	for _, wt := range worktrees {
+		path := wt.Path
+		cmds = append(cmds, func() tea.Msg {
+			last, _ := git.LastCommitTime(path)
+			return worktreeUsageMsg{path: path, size: git.DiskUsage(path), lastCommit: last}
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
 type worktreeViewState int
 
 const (
@@ -35,8 +61,14 @@ type WorktreeModel struct {
 	// Create form
 	branchInput  textinput.Model
 	pathInput    textinput.Model
-	focusedField int  // 0 = branch, 1 = path
+	baseInput    textinput.Model
+	focusedField int  // 0 = branch, 1 = path, 2 = base
 	pathManual   bool // true once user has manually edited path
+	setup        config.WorktreeSetup
+
+	// Disk usage, filled in as measurements arrive
+	usage  map[string]worktreeUsageMsg
+	warnGB float64 // Warn when linked worktrees total more; <= 0 never
 
 	// Confirm-remove state
 	confirmWorktreeIdx int    // index into m.worktrees
@@ -46,6 +78,7 @@ type WorktreeModel struct {
 	chosenPath        string
 	createPath        string
 	createBranch      string
+	createBase        string
 	removeWorktreePath string
 	removeSessionPane  string
 	cancelled         bool
@@ -105,6 +138,10 @@ var (
 				Foreground(lipgloss.Color("#6B7280")).
 				PaddingLeft(1)
 
+	worktreeWarnStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#F59E0B")).
+				PaddingLeft(1)
+
 	worktreeLabelStyle = lipgloss.NewStyle().
 				Foreground(lipgloss.Color("#9CA3AF")).
 				Width(8)
@@ -113,17 +150,23 @@ var (
 // NewWorktreeModel creates a WorktreeModel ready for display.
 func NewWorktreeModel(worktrees []git.Worktree, repoRoot string, sessions []session.Session, w, h int) WorktreeModel {
 	bi := textinput.New()
-	bi.Placeholder = "branch name (e.g. feat/payments)"
+	bi.Placeholder = i18n.T("branch name (e.g. feat/payments)")
 	bi.Focus()
 	bi.CharLimit = 200
 	bi.Width = min(50, w-10)
 
 	pi := textinput.New()
-	pi.Placeholder = "path"
+	pi.Placeholder = i18n.T("path")
 	pi.CharLimit = 500
 	pi.Width = min(50, w-10)
 
+	ba := textinput.New()
+	ba.Placeholder = i18n.T("HEAD (or origin/main, v1.2.0…)")
+	ba.CharLimit = 200
+	ba.Width = min(50, w-10)
+
 	return WorktreeModel{
+		usage:       make(map[string]worktreeUsageMsg),
 		repoRoot:    repoRoot,
 		worktrees:   worktrees,
 		sessions:    sessions,
@@ -131,6 +174,7 @@ func NewWorktreeModel(worktrees []git.Worktree, repoRoot string, sessions []sess
 		height:      h,
 		branchInput: bi,
 		pathInput:   pi,
+		baseInput:   ba,
 	}
 }
 
@@ -140,6 +184,10 @@ func (m WorktreeModel) Init() tea.Cmd {
 
 func (m WorktreeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case worktreeUsageMsg:
+		m.usage[msg.path] = msg
+		return m, nil
+
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
@@ -237,16 +285,18 @@ func (m WorktreeModel) updateCreating(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.state = worktreeStateListing
 		m.branchInput.Blur()
 		m.pathInput.Blur()
+		m.baseInput.Blur()
 		return m, nil
 
 	case key.Matches(msg, worktreeKeys.Tab):
-		m.focusedField = 1 - m.focusedField
-		if m.focusedField == 0 {
-			m.branchInput.Focus()
-			m.pathInput.Blur()
-		} else {
-			m.pathInput.Focus()
-			m.branchInput.Blur()
+		m.focusedField = (m.focusedField + 1) % 3
+		inputs := []*textinput.Model{&m.branchInput, &m.pathInput, &m.baseInput}
+		for i, in := range inputs {
+			if i == m.focusedField {
+				in.Focus()
+			} else {
+				in.Blur()
+			}
 		}
 		return m, textinput.Blink
 
@@ -256,12 +306,15 @@ func (m WorktreeModel) updateCreating(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if branch != "" && path != "" {
 			m.createBranch = branch
 			m.createPath = path
+			m.createBase = strings.TrimSpace(m.baseInput.Value())
 		}
 		return m, nil
 	}
 
 	// Update the focused input.
-	if m.focusedField == 0 {
+	if m.focusedField == 2 {
+		m.baseInput, cmd = m.baseInput.Update(msg)
+	} else if m.focusedField == 0 {
 		m.branchInput, cmd = m.branchInput.Update(msg)
 		if !m.pathManual {
 			branch := m.branchInput.Value()
@@ -296,13 +349,13 @@ func (m WorktreeModel) View() string {
 
 func (m WorktreeModel) viewListing(repoName string) string {
 	var sb strings.Builder
-	sb.WriteString(worktreeTitleStyle.Width(m.width).Render("Worktrees — "+repoName) + "\n\n")
+	sb.WriteString(worktreeTitleStyle.Width(m.width).Render(i18n.Tf("Worktrees — %s", repoName)) + "\n\n")
 
 	// "New worktree..." row
 	if m.selected == 0 {
-		sb.WriteString(worktreeNewSelectedStyle.Width(m.width-4).Render("▸ + New worktree...") + "\n")
+		sb.WriteString(worktreeNewSelectedStyle.Width(m.width-4).Render("▸ + " + i18n.T("New worktree...")) + "\n")
 	} else {
-		sb.WriteString(worktreeNewStyle.Render("  + New worktree...") + "\n")
+		sb.WriteString(worktreeNewStyle.Render("  + " + i18n.T("New worktree...")) + "\n")
 	}
 
 	// Existing worktrees
@@ -310,12 +363,20 @@ func (m WorktreeModel) viewListing(repoName string) string {
 		listIdx := i + 1
 		branch := wt.Branch
 		if branch == "" {
-			branch = "(detached)"
+			branch = i18n.T("(detached)")
 		}
 		label := fmt.Sprintf("%-14s %s", branch, shortenPath(wt.Path))
 		if wt.IsMain {
 			label += "  [main]"
 		}
+		if u, ok := m.usage[wt.Path]; ok {
+			label += "  " + formatSize(u.size)
+			if !u.lastCommit.IsZero() {
+				label += ", " + i18n.Tf("last commit %s", ageString(time.Since(u.lastCommit)))
+			}
+		} else {
+			label += "  " + i18n.T("measuring…")
+		}
 		if listIdx == m.selected {
 			sb.WriteString(worktreeSelectedStyle.Width(m.width-4).Render("▸ "+label) + "\n")
 		} else {
@@ -324,7 +385,10 @@ func (m WorktreeModel) viewListing(repoName string) string {
 	}
 
 	sb.WriteString("\n")
-	sb.WriteString(worktreeHelpStyle.Render("[j/k] nav  [enter] open  [x] remove  [esc] cancel"))
+	if total, over := m.linkedUsage(); over {
+		sb.WriteString(worktreeWarnStyle.Render(i18n.Tf("⚠ worktrees use %s, over the %g GiB limit — remove finished ones with x", formatSize(total), m.warnGB)) + "\n\n")
+	}
+	sb.WriteString(worktreeHelpStyle.Render(i18n.T("[j/k] nav  [enter] open  [x] remove  [esc] cancel")))
 	return sb.String()
 }
 
@@ -332,38 +396,85 @@ func (m WorktreeModel) viewConfirming(repoName string) string {
 	wt := m.worktrees[m.confirmWorktreeIdx]
 	branch := wt.Branch
 	if branch == "" {
-		branch = "(detached)"
+		branch = i18n.T("(detached)")
 	}
 	sessionLine := m.confirmSessionPane
 	if sessionLine == "" {
-		sessionLine = "none"
+		sessionLine = i18n.T("none")
 	}
 
 	var sb strings.Builder
-	sb.WriteString(worktreeTitleStyle.Width(m.width).Render("Remove Worktree — "+repoName) + "\n\n")
-	sb.WriteString(worktreeLabelStyle.Render("Branch") + "   " + branch + "\n")
-	sb.WriteString(worktreeLabelStyle.Render("Path") + "     " + shortenPath(wt.Path) + "\n")
-	sb.WriteString(worktreeLabelStyle.Render("Session") + "  " + sessionLine)
+	sb.WriteString(worktreeTitleStyle.Width(m.width).Render(i18n.Tf("Remove Worktree — %s", repoName)) + "\n\n")
+	sb.WriteString(worktreeLabelStyle.Render(i18n.T("Branch")) + "   " + branch + "\n")
+	sb.WriteString(worktreeLabelStyle.Render(i18n.T("Path")) + "     " + shortenPath(wt.Path) + "\n")
+	sb.WriteString(worktreeLabelStyle.Render(i18n.T("Session")) + "  " + sessionLine)
 	if m.confirmSessionPane != "" {
-		sb.WriteString("  (will be killed)")
+		sb.WriteString("  " + i18n.T("(will be killed)"))
 	}
 	sb.WriteString("\n\n")
-	sb.WriteString(worktreeHelpStyle.Render("[enter] confirm  [esc] cancel"))
+	sb.WriteString(worktreeHelpStyle.Render(i18n.T("[enter] confirm  [esc] cancel")))
 	return sb.String()
 }
 
 func (m WorktreeModel) viewCreating(repoName string) string {
 	var sb strings.Builder
-	sb.WriteString(worktreeTitleStyle.Width(m.width).Render("New Worktree — "+repoName) + "\n\n")
+	sb.WriteString(worktreeTitleStyle.Width(m.width).Render(i18n.Tf("New Worktree — %s", repoName)) + "\n\n")
 
-	branchLine := worktreeLabelStyle.Render("Branch") + "  " + worktreeInputStyle.Render(m.branchInput.View())
-	pathLine := worktreeLabelStyle.Render("Path") + "    " + worktreeInputStyle.Render(m.pathInput.View())
+	branchLine := worktreeLabelStyle.Render(i18n.T("Branch")) + "  " + worktreeInputStyle.Render(m.branchInput.View())
+	pathLine := worktreeLabelStyle.Render(i18n.T("Path")) + "    " + worktreeInputStyle.Render(m.pathInput.View())
+	baseLine := worktreeLabelStyle.Render(i18n.T("Base")) + "    " + worktreeInputStyle.Render(m.baseInput.View())
 	sb.WriteString(branchLine + "\n")
-	sb.WriteString(pathLine + "\n\n")
-	sb.WriteString(worktreeHelpStyle.Render("[tab] switch field  [enter] create  [esc] back"))
+	sb.WriteString(pathLine + "\n")
+	sb.WriteString(baseLine + "\n")
+	var setup []string
+	if len(m.setup.Copy) > 0 {
+		setup = append(setup, i18n.Tf("copy %s", strings.Join(m.setup.Copy, ", ")))
+	}
+	if m.setup.PostCreate != "" {
+		setup = append(setup, i18n.Tf("run %s", m.setup.PostCreate))
+	}
+	if len(setup) > 0 {
+		sb.WriteString(worktreeLabelStyle.Render(i18n.T("Setup")) + "   " + worktreeHelpStyle.Render(strings.Join(setup, " · ")) + "\n")
+	}
+	sb.WriteString("\n")
+	sb.WriteString(worktreeHelpStyle.Render(i18n.T("[tab] switch field  [enter] create  [esc] back")))
 	return sb.String()
 }
 
+// linkedUsage totals the measured size of the linked (non-main) worktrees
+// and reports whether it is over the warning threshold.
+func (m WorktreeModel) linkedUsage() (total int64, over bool) {
+	for _, wt := range m.worktrees {
+		if !wt.IsMain {
+			total += m.usage[wt.Path].size
+		}
+	}
+	return total, m.warnGB > 0 && float64(total) > m.warnGB*(1<<30)
+}
+
+// ageString renders a duration coarsely, as "5m ago", "3h ago" or "12d ago".
+func ageString(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return i18n.Tf("%dm ago", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return i18n.Tf("%dh ago", int(d.Hours()))
+	default:
+		return i18n.Tf("%dd ago", int(d.Hours()/24))
+	}
+}
+
+// mainPath returns the main worktree, where untracked setup files are
+// copied from.
+func (m WorktreeModel) mainPath() string {
+	for _, wt := range m.worktrees {
+		if wt.IsMain {
+			return wt.Path
+		}
+	}
+	return m.repoRoot
+}
+
 // ChosenPath returns the path of an existing worktree that was selected, or "".
 func (m WorktreeModel) ChosenPath() string {
 	return m.chosenPath
@@ -377,6 +488,12 @@ func (m WorktreeModel) ShouldCreate() (path, branch string, ok bool) {
 	return "", "", false
 }
 
+// CreateBase returns the ref a new branch should start from, or "" for
+// HEAD.
+func (m WorktreeModel) CreateBase() string {
+	return m.createBase
+}
+
 // ShouldRemove returns the worktree path and associated session pane to kill,
 // with ok=true when the user has confirmed a removal.
 func (m WorktreeModel) ShouldRemove() (wtPath, sessionPane string, ok bool) {
@@ -6,6 +6,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/x/exp/teatest"
+	"github.com/shnupta/herd/internal/config"
 	"github.com/shnupta/herd/internal/git"
 	"github.com/shnupta/herd/internal/session"
 )
@@ -196,8 +197,32 @@ func TestWorktreeModel_CreateFormTabSwitchesField(t *testing.T) {
 		t.Errorf("expected focusedField=1 after tab, got %d", m.focusedField)
 	}
 	m = sendSpecialKey(m, tea.KeyTab)
+	if m.focusedField != 2 {
+		t.Errorf("expected focusedField=2 (base) after second tab, got %d", m.focusedField)
+	}
+	m = sendSpecialKey(m, tea.KeyTab)
 	if m.focusedField != 0 {
-		t.Errorf("expected focusedField=0 after second tab, got %d", m.focusedField)
+		t.Errorf("expected focusedField=0 after third tab, got %d", m.focusedField)
+	}
+}
+
+func TestWorktreeModel_CreateFormBase(t *testing.T) {
+	m := newTestWorktreeModel(testWorktrees())
+	m = sendSpecialKey(m, tea.KeyEnter)
+	for _, r := range "feat/x" {
+		m = sendKey(m, r)
+	}
+	m = sendSpecialKey(m, tea.KeyTab)
+	m = sendSpecialKey(m, tea.KeyTab)
+	for _, r := range "origin/main" {
+		m = sendKey(m, r)
+	}
+	m = sendSpecialKey(m, tea.KeyEnter)
+	if _, branch, ok := m.ShouldCreate(); !ok || branch != "feat/x" {
+		t.Fatalf("ShouldCreate = %q, %v", branch, ok)
+	}
+	if m.CreateBase() != "origin/main" {
+		t.Errorf("CreateBase = %q, want origin/main", m.CreateBase())
 	}
 }
 
@@ -238,6 +263,45 @@ func TestWorktreeModel_ViewCreatingContainsFormHints(t *testing.T) {
 	}
 }
 
+func TestWorktreeModel_ViewCreatingShowsSetup(t *testing.T) {
+	m := newTestWorktreeModel(testWorktrees())
+	m.setup = config.WorktreeSetup{Copy: []string{".env*"}, PostCreate: "npm install"}
+	m = sendSpecialKey(m, tea.KeyEnter)
+	v := m.View()
+	if !containsStr(v, "copy .env*") || !containsStr(v, "run npm install") {
+		t.Errorf("create view should describe the setup:\n%s", v)
+	}
+	if m.mainPath() != "/home/user/repo" {
+		t.Errorf("mainPath = %q, want the main worktree", m.mainPath())
+	}
+}
+
+func TestWorktreeModel_UsageAndWarning(t *testing.T) {
+	m := newTestWorktreeModel(testWorktrees())
+	m.warnGB = 1
+	if v := m.View(); !containsStr(v, "measuring") {
+		t.Errorf("listing should show sizes as pending:\n%s", v)
+	}
+
+	wts := testWorktrees()
+	for i, size := range []int64{50 << 30, 700 << 20, 600 << 20} {
+		updated, _ := m.Update(worktreeUsageMsg{path: wts[i].Path, size: size, lastCommit: time.Now().Add(-72 * time.Hour)})
+		m = updated.(WorktreeModel)
+	}
+	v := m.View()
+	for _, want := range []string{"700.0 MiB", "3d ago", "1.3 GiB, over the 1 GiB limit"} {
+		if !containsStr(v, want) {
+			t.Errorf("listing missing %q:\n%s", want, v)
+		}
+	}
+
+	// The main worktree doesn't count towards the limit.
+	m.warnGB = 2
+	if containsStr(m.View(), "over the") {
+		t.Error("warned although linked worktrees are under the limit")
+	}
+}
+
 func TestWorktreeModel_ViewConfirmingContainsWorktreeInfo(t *testing.T) {
 	m := newTestWorktreeModel(testWorktrees())
 	m = sendKey(m, 'j')
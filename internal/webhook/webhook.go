@@ -0,0 +1,92 @@
+// Package webhook POSTs herd events as JSON to the webhooks configured in
+// webhooks, so a sign-off on agent changes can start CI or ping a channel.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/shnupta/herd/internal/config"
+)
+
+// requestTimeout bounds one delivery.
+const requestTimeout = 10 * time.Second
+
+// Event names.
+const (
+	ReviewSubmitted = "review_submitted" // feedback was sent with comments
+	ReviewApproved  = "review_approved"  // the changes were approved as-is
+	NeedsAttention  = "needs_attention"  // a session entered one of alert_states (sent by the daemon)
+)
+
+// Event is the JSON body of a delivery.
+type Event struct {
+	Event   string    `json:"event"`
+	Time    time.Time `json:"time"`
+	Session Session   `json:"session"`
+	State   string    `json:"state,omitempty"` // the state a needs_attention session entered
+	Review  *Review   `json:"review,omitempty"`
+}
+
+// Session identifies the session an event is about.
+type Session struct {
+	Key     string `json:"key"`
+	Pane    string `json:"pane"`
+	Name    string `json:"name,omitempty"`
+	Project string `json:"project"`
+	Branch  string `json:"branch,omitempty"`
+}
+
+// Review summarises a submitted review. Severity counts comments by their
+// Conventional Comments label ("nit", "issue", ...; "comment" for none).
+type Review struct {
+	Approved bool           `json:"approved"`
+	Comments int            `json:"comments"`
+	Outdated int            `json:"outdated,omitempty"`
+	Severity map[string]int `json:"severity,omitempty"`
+	Feedback string         `json:"feedback,omitempty"`
+}
+
+// Send delivers ev to every hook, returning one error per failed delivery.
+func Send(ctx context.Context, hooks []config.Webhook, ev Event) []error {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return []error{err}
+	}
+	var errs []error
+	for _, h := range hooks {
+		if err := post(ctx, h, body); err != nil {
+			errs = append(errs, fmt.Errorf("webhook %s: %w", h.URL, err))
+		}
+	}
+	return errs
+}
+
+func post(ctx context.Context, h config.Webhook, body []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "herd")
+	for k, v := range h.Headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("%s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shnupta/herd/internal/config"
+)
+
+func TestSend(t *testing.T) {
+	var got Event
+	var auth string
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode: %v", err)
+		}
+	}))
+	defer ok.Close()
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusInternalServerError)
+	}))
+	defer broken.Close()
+
+	ev := Event{
+		Event:   ReviewSubmitted,
+		Session: Session{Key: "abc", Pane: "%1", Project: "/code/app"},
+		Review:  &Review{Comments: 2, Severity: map[string]int{"nit": 1, "issue": 1}},
+	}
+	errs := Send(context.Background(), []config.Webhook{
+		{URL: ok.URL, Headers: map[string]string{"Authorization": "Bearer t"}},
+		{URL: broken.URL},
+	}, ev)
+
+	if len(errs) != 1 {
+		t.Fatalf("errs = %v, want one for the broken hook", errs)
+	}
+	if auth != "Bearer t" {
+		t.Errorf("Authorization = %q, want the configured header", auth)
+	}
+	if got.Event != ReviewSubmitted || got.Session.Key != "abc" || got.Review.Severity["issue"] != 1 {
+		t.Errorf("delivered %+v, want the event", got)
+	}
+}
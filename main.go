@@ -1,13 +1,22 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 
-	"github.com/shnupta/herd/internal/hook"
+	"github.com/shnupta/herd/internal/cli"
+	"github.com/shnupta/herd/internal/config"
+	"github.com/shnupta/herd/internal/daemon"
+	"github.com/shnupta/herd/internal/history"
+	"github.com/shnupta/herd/internal/i18n"
+	"github.com/shnupta/herd/internal/seal"
+	"github.com/shnupta/herd/internal/sidebar"
 	"github.com/shnupta/herd/internal/state"
 	"github.com/shnupta/herd/internal/tmux"
 	"github.com/shnupta/herd/internal/tui"
@@ -16,13 +25,18 @@ import (
 // version is set by goreleaser via ldflags at release time
 var version = "dev"
 
-const usage = `herd — tmux-based Claude Code session manager
+// usageHeader and usageFooter frame the command list in herd --help.
+const usageHeader = `herd — tmux-based Claude Code session manager
 
 Usage:
   herd                  Launch the TUI (must be run inside tmux)
-  herd install          Install Claude Code hooks into ~/.claude/settings.json
-  herd hook <event>     Handle a hook event (called by Claude Code, not directly)
-  herd --help           Show this help
+`
+
+const usageFooter = `
+  kill, gc, worktree prune and uninstall accept --dry-run to list the
+  changes without making them, --yes to skip the confirmation prompt and
+  --verbose to report each change as it is made. 'herd help <command>'
+  describes a command's flags.
 
 TUI key bindings:
   j / k / ↑ / ↓        Navigate sessions
@@ -33,6 +47,15 @@ TUI key bindings:
   I                     Install hooks (same as 'herd install')
   q / ctrl+c            Quit
 
+Environment:
+  HERD_TMUX_LOG=<file>  Append a JSON record of every tmux command herd runs,
+                        with its duration and any failure, to file
+  HERD_STORAGE_KEY=<k>  Base64 32-byte key for the stores listed in encrypt,
+                        instead of one kept in the OS keychain
+  HERD_STATE_POLL=1     Rescan the session state directory every second
+                        instead of waiting for change notifications, for
+                        network homes where those never arrive
+
 Status indicators:
   ●  working            Claude is using a tool
   ⏸  waiting            Claude is waiting for your input
@@ -45,53 +68,63 @@ Claude session started after installation.
 `
 
 func main() {
-	// Subcommand: herd hook <EventType>
-	// Called by Claude Code hooks — must be fast and produce no terminal output.
-	if len(os.Args) >= 3 && os.Args[1] == "hook" {
-		if err := hook.Run(os.Args[2]); err != nil {
-			// Hooks must not fail loudly (Claude would surface the error).
-			os.Exit(1)
+	// herd hook runs on every Claude Code event, so it skips the tmux log.
+	if len(os.Args) < 2 || os.Args[1] != "hook" {
+		cfg := config.Load()
+		seal.Enable(cfg.Encrypt)
+		history.SetRecordText(cfg.RecordPrompts)
+		if err := i18n.SetLocale(i18n.Resolve(cfg.Locale)); err != nil {
+			fmt.Fprintln(os.Stderr, "herd:", err)
+		}
+		if path := os.Getenv("HERD_TMUX_LOG"); path != "" {
+			if f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644); err == nil {
+				defer f.Close()
+				tmux.SetLogger(slog.New(slog.NewJSONHandler(f, &slog.HandlerOptions{Level: slog.LevelDebug})))
+			} else {
+				fmt.Fprintln(os.Stderr, "error opening HERD_TMUX_LOG:", err)
+			}
+		}
+		if addr := os.Getenv("HERD_PPROF"); addr != "" {
+			go func() {
+				if err := daemon.ListenProfiles(addr); err != nil {
+					fmt.Fprintln(os.Stderr, "error serving HERD_PPROF:", err)
+				}
+			}()
 		}
-		return
-	}
-
-	if len(os.Args) == 2 && (os.Args[1] == "--help" || os.Args[1] == "-h" || os.Args[1] == "help") {
-		fmt.Print(usage)
-		return
 	}
 
-	if len(os.Args) == 2 && (os.Args[1] == "--version" || os.Args[1] == "-v" || os.Args[1] == "version") {
-		fmt.Println(version)
-		return
+	if len(os.Args) > 1 {
+		code, ok := cli.NewRouter(commands...).Run(os.Args[1:])
+		if !ok {
+			fmt.Fprintf(os.Stderr, "herd: unknown command %q (see herd --help)\n", os.Args[1])
+			code = 2
+		}
+		os.Exit(code)
 	}
 
-	// Subcommand: herd install
-	// Writes herd hooks into ~/.claude/settings.json.
-	if len(os.Args) == 2 && os.Args[1] == "install" {
-		self, err := os.Executable()
-		if err != nil {
-			fmt.Fprintln(os.Stderr, "error finding executable path:", err)
-			os.Exit(1)
-		}
-		// Resolve any symlinks to get the real path
-		self, err = filepath.EvalSymlinks(self)
-		if err != nil {
-			fmt.Fprintln(os.Stderr, "error resolving executable path:", err)
-			os.Exit(1)
-		}
-		if err := hook.Install(self); err != nil {
-			fmt.Fprintln(os.Stderr, "error installing hooks:", err)
-			os.Exit(1)
-		}
-		fmt.Printf("hooks installed → ~/.claude/settings.json\n")
-		fmt.Printf("using herd at: %s\n", self)
-		return
+	if err := runTUI(nil); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
 	}
+}
 
+// runTUI runs the TUI until it quits, opening as preset lays out when it
+// is set.
+func runTUI(preset *config.Preset) error {
 	// Ensure we are running inside tmux.
 	if os.Getenv("TMUX") == "" {
-		fmt.Fprintln(os.Stderr, "herd must be run inside a tmux session")
-		os.Exit(1)
+		return errors.New("herd must be run inside a tmux session")
+	}
+
+	// Name what an old tmux can't do now, rather than failing cryptically
+	// later; herd falls back to the pane's own size without resizing.
+	if missing := tmux.MissingFeatures(); len(missing) > 0 {
+		v, _ := tmux.CurrentVersion()
+		var lacks []string
+		for _, f := range missing {
+			lacks = append(lacks, fmt.Sprintf("%s (needs %d.%d)", f.Name, f.Major, f.Minor))
+		}
+		_ = tmux.DisplayMessage(fmt.Sprintf("herd: tmux %s lacks %s", v, strings.Join(lacks, ", ")))
 	}
 
 	// Start the state file watcher (best-effort; herd works without hooks).
@@ -101,9 +134,15 @@ func main() {
 	}
 	if watcher != nil {
 		defer watcher.Close()
+		if watcher.Polling() {
+			_ = tmux.DisplayMessage("herd: can't watch " + state.Dir() + " for changes; polling it instead")
+		}
 	}
 
 	model := tui.New(watcher, &tmux.Client{})
+	if preset != nil {
+		model = model.WithPreset(*preset)
+	}
 
 	p := tea.NewProgram(
 		model,
@@ -111,8 +150,13 @@ func main() {
 		tea.WithMouseCellMotion(),
 	)
 
-	if _, err := p.Run(); err != nil {
-		fmt.Fprintln(os.Stderr, "error:", err)
-		os.Exit(1)
-	}
+	_, err = p.Run()
+	_ = sidebar.Flush()
+	return err
+}
+
+// herdDir returns herd's data directory, ~/.herd.
+func herdDir() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".herd")
 }